@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -96,6 +97,28 @@ var absOrganizeCmd = &cobra.Command{
 	RunE: runABSOrganize,
 }
 
+// absIDMapCmd maps reorganized paths to ABS item IDs
+var absIDMapCmd = &cobra.Command{
+	Use:   "id-map",
+	Short: "Map reorganized books to their ABS item IDs",
+	Long: `Read the organizer log and look up the ABS library item of every moved book in
+abs.sqlite, writing a JSON file of old path → new path → item ID. ABS path-update
+tooling or the API can use it to repoint items so listening progress survives a
+big reorganization.
+
+Use a copy of abs.sqlite taken before organizing: once ABS rescans, the old paths
+are gone from its database. Paths are translated with --abs-path-map when given;
+otherwise ABS is assumed to see the same paths as this machine.`,
+	Example: `  docker cp abs_container:/config/abs.sqlite /tmp/abs-before.sqlite
+  audiobook-organizer --dir=/mnt/media/incoming --out=/mnt/media/audiobooks
+  audiobook-organizer abs id-map \
+    --abs-sqlite=/tmp/abs-before.sqlite \
+    --abs-path-map="/audiobooks:/mnt/media/audiobooks" \
+    --out=/mnt/media/audiobooks \
+    --id-map-file=abs-id-map.json`,
+	RunE: runABSIDMap,
+}
+
 // absTestPathsCmd tests path discovery
 var absTestPathsCmd = &cobra.Command{
 	Use:   "test-paths",
@@ -123,6 +146,7 @@ func init() {
 	absCmd.AddCommand(absScanCmd)
 	absCmd.AddCommand(absOrganizeCmd)
 	absCmd.AddCommand(absTestPathsCmd)
+	absCmd.AddCommand(absIDMapCmd)
 	absCmd.AddCommand(absScanTriggerCmd)
 	absCmd.AddCommand(absWebSocketCmd)
 
@@ -147,6 +171,11 @@ func init() {
 
 	addABSOrganizeFlags()
 
+	absIDMapCmd.Flags().
+		String("log", "", "Organizer log to read (default: --log-path, or the undo log of --out or --dir)")
+	absIDMapCmd.Flags().
+		String("id-map-file", "abs-id-map.json", "Where to write the item ID mapping")
+
 	// Header flags (for Cloudflare/proxy auth)
 	absCmd.PersistentFlags().
 		StringVar(&absHeaderFile, "header-file", "", "File with custom headers (KEY=VALUE format, one per line)")
@@ -164,21 +193,33 @@ func addABSOrganizeFlags() {
 	absOrganizeCmd.Flags().
 		BoolVar(&absAllLibraries, "abs-all-libraries", false, "Organize all libraries instead of just one (requires path mappings)")
 	absOrganizeCmd.Flags().
-		StringVar(&replaceSpace, "replace_space", "", "Character to replace spaces")
+		String("replace_space", "", "Character to replace spaces")
 	absOrganizeCmd.Flags().
-		BoolVar(&undo, "undo", false, "Restore files to their original locations")
+		Bool("undo", false, "Restore files to their original locations")
 	absOrganizeCmd.Flags().
-		BoolVar(&prompt, "prompt", false, "Prompt for confirmation before moving each book")
+		Bool("prompt", false, "Prompt for confirmation before moving each book")
 	absOrganizeCmd.Flags().
-		BoolVar(&removeEmpty, removeEmptyKey, false, "Remove empty directories after moving files")
+		Bool(removeEmptyKey, false, "Remove empty directories after moving files")
 	absOrganizeCmd.Flags().
-		StringVarP(&layout, "layout", "l", "author-series-title", "Directory structure layout:\n  - author-series-title:        Author/Series/Title/ (default)\n  - author-series-title-number: Author/Series/#1 - Title/ (include series number in title)\n  - author-title:               Author/Title/ (ignore series)\n  - author-only:                Author/ (flatten all books)")
+		StringP("layout", "l", "author-series-title", "Directory structure layout:\n  - author-series-title:        Author/Series/Title/ (default)\n  - author-series-title-number: Author/Series/#1 - Title/ (include series number in title)\n  - author-title:               Author/Title/ (ignore series)\n  - author-only:                Author/ (flatten all books)")
 	absOrganizeCmd.Flags().
-		StringVar(&layoutTemplate, "layout-template", "", "Custom directory layout template overriding --layout; see \"audiobook-organizer layout-template\"")
+		String("layout-template", "", "Custom directory layout template overriding --layout; see \"audiobook-organizer layout-template\"")
+	absOrganizeCmd.Flags().
+		String(casingKey, organizer.CasingPreserve, "Casing of folder names: preserve, title (The Way of Kings), or sentence (The way of kings)")
+	absOrganizeCmd.Flags().
+		Bool(stripTitleKey, false, "Drop a leading author or series name and number from title folders when they repeat the other tags")
 }
 
 func runABSScan(cmd *cobra.Command, args []string) error {
 	verbose := viper.GetBool("verbose")
+	inputDir, err := inputDirFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	outputDir, err := outputDirFromCommand(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Validate inputs
 	if absURL == "" {
@@ -224,7 +265,6 @@ func runABSScan(cmd *cobra.Command, args []string) error {
 
 	// Determine mode and create provider
 	var provider *abs.MetadataProvider
-	var err error
 
 	if absAllLibraries {
 		// Scan ALL libraries mode
@@ -373,7 +413,7 @@ func runABSScan(cmd *cobra.Command, args []string) error {
 	)
 
 	// If output dir specified and not dry run, trigger library scan at end
-	if outputDir != "" && !dryRun {
+	if outputDir != "" && !viper.GetBool(dryRunKey) {
 		fmt.Println("\nTo trigger ABS library scan after organizing:")
 		fmt.Printf(
 			"  audiobook-organizer abs scan-trigger --abs-url=%s --abs-token=*** --abs-library=%s\n",
@@ -408,6 +448,7 @@ func runABSOrganize(cmd *cobra.Command, args []string) error {
 	if err := org.ResolvePaths(); err != nil {
 		return err
 	}
+	defer org.Close()
 
 	provider, selectedLibraryID, err := newABSMetadataProvider(org.BaseDir())
 	if err != nil {
@@ -532,6 +573,14 @@ func organizerConfigFromABSCommand(cmd *cobra.Command) (organizer.OrganizerConfi
 	if err != nil {
 		return organizer.OrganizerConfig{}, err
 	}
+	casingValue, err := stringFlagOrViper(cmd, casingKey)
+	if err != nil {
+		return organizer.OrganizerConfig{}, err
+	}
+	stripTitleValue, err := boolFlagOrViper(cmd, stripTitleKey)
+	if err != nil {
+		return organizer.OrganizerConfig{}, err
+	}
 	titleFieldValue, err := stringFlagOrViper(cmd, titleFieldKey)
 	if err != nil {
 		return organizer.OrganizerConfig{}, err
@@ -559,10 +608,25 @@ func organizerConfigFromABSCommand(cmd *cobra.Command) (organizer.OrganizerConfi
 		Prompt:              promptValue,
 		RemoveEmpty:         removeEmptyValue,
 		UseEmbeddedMetadata: useEmbeddedValue,
+		StrictJSON:          viper.GetBool(strictJSONKey),
 		Flat:                flatValue,
 		SkipErrors:          skipErrorsValue,
 		Layout:              layoutValue,
 		LayoutTemplate:      layoutTemplateValue,
+		Casing:              casingValue,
+		StripTitlePrefix:    stripTitleValue,
+		SFTPIdentityFile:    viper.GetString(sftpIdentityKey),
+		SFTPKnownHostsFile:  viper.GetString(sftpKnownHostsKey),
+		LogPath:             viper.GetString(logPathKey),
+		LogChecksums:        viper.GetBool(logChecksumsKey),
+		MinFileAge:          viper.GetDuration(minFileAgeKey),
+		SizeSettle:          viper.GetDuration(sizeSettleKey),
+		MinConfidence:       viper.GetFloat64(minConfidenceKey),
+		SeedSafe:            viper.GetBool(seedSafeKey),
+		TorrentDirs:         stringListValue(torrentDirKey),
+		Extensions:          extensionPolicy(),
+		Locale:              viper.GetString(localeKey),
+		Language:            language(),
 		FieldMapping: organizer.FieldMapping{
 			TitleField:   titleFieldValue,
 			SeriesField:  seriesFieldValue,
@@ -739,10 +803,65 @@ func isPathWithin(basePath string, candidatePath string) bool {
 	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
 }
 
+func runABSIDMap(cmd *cobra.Command, args []string) error {
+	if absSQLite == "" {
+		return fmt.Errorf("--abs-sqlite is required (a copy of abs.sqlite from before organizing)")
+	}
+
+	logFlag, _ := cmd.Flags().GetString("log")
+	logPath, err := undoLogPath(cmd, logFlag)
+	if err != nil {
+		return err
+	}
+	if logPath == "" {
+		return fmt.Errorf("--log, --out, or --dir is required to find the organizer log")
+	}
+	entries, err := organizer.ReadLogEntries(logPath)
+	if err != nil {
+		return err
+	}
+
+	var mapper *abs.PathMapper
+	if len(absPathMaps) > 0 {
+		mappings, err := parseABSPathMappings()
+		if err != nil {
+			return err
+		}
+		mapper = abs.NewPathMapper(mappings)
+	}
+
+	items, err := abs.LoadItemIDs(absSQLite)
+	if err != nil {
+		return err
+	}
+	moves, unmatched := abs.MapItemMoves(items, mapper, entries)
+
+	outPath, _ := cmd.Flags().GetString("id-map-file")
+	if err := abs.WriteItemMoves(outPath, moves); err != nil {
+		return err
+	}
+	writeABSIDMapResult(cmd.OutOrStdout(), outPath, moves, unmatched)
+	return nil
+}
+
+func writeABSIDMapResult(out io.Writer, outPath string, moves []abs.ItemMove, unmatched []string) {
+	fmt.Fprintf(out, "Mapped %d ABS item(s) to their new paths in %s\n", len(moves), outPath)
+	if len(unmatched) > 0 {
+		fmt.Fprintf(out, "%d moved path(s) matched no ABS item:\n", len(unmatched))
+		for _, path := range unmatched {
+			fmt.Fprintf(out, "  - %s\n", path)
+		}
+	}
+}
+
 func runABSTestPaths(cmd *cobra.Command, args []string) error {
 	if absSQLite == "" {
 		return fmt.Errorf("--abs-sqlite is required for path testing")
 	}
+	inputDir, err := inputDirFromCommand(cmd)
+	if err != nil {
+		return err
+	}
 	if inputDir == "" {
 		return fmt.Errorf("--dir is required (path to test against)")
 	}
@@ -762,10 +881,11 @@ func runABSTestPaths(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("listing libraries: %w", err)
 		}
 		for _, f := range folders {
-			fmt.Printf("  - %s (ABS path: %s)\n", f.FullPath, f.Path)
+			fmt.Printf("  - %s (library: %s)\n", f.Path, f.LibraryID)
 		}
 
-		fmt.Printf("\nYour path '%s' must be under one of the above paths.\n", inputDir)
+		fmt.Printf("\nNo local directory overlapping '%s' holds the items of these folders.\n", inputDir)
+		fmt.Println("Point --dir at the local mount of a library folder, or use --abs-path-map.")
 		return fmt.Errorf("path discovery failed")
 	}
 
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/viper"
+)
+
+func TestBookFilterFromFlags(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set(onlyPathKey, nil)
+		viper.Set(onlyAuthorKey, nil)
+		viper.Set(onlyTitleKey, "")
+	})
+
+	viper.Set(onlyPathKey, []string{"/books/a", "/books/b"})
+	viper.Set(onlyAuthorKey, "Frank Herbert, Jane Austen")
+	viper.Set(onlyTitleKey, "^Dune")
+
+	filter, err := bookFilterFromFlags()
+	if err != nil {
+		t.Fatalf("bookFilterFromFlags() error = %v", err)
+	}
+	if !reflect.DeepEqual(filter.Paths, []string{"/books/a", "/books/b"}) {
+		t.Errorf("Paths = %v", filter.Paths)
+	}
+	if !reflect.DeepEqual(filter.Authors, []string{"Frank Herbert", "Jane Austen"}) {
+		t.Errorf("Authors = %v, want names split on commas only", filter.Authors)
+	}
+	if filter.TitlePattern == nil || !filter.TitlePattern.MatchString("Dune Messiah") {
+		t.Errorf("TitlePattern = %v, want ^Dune", filter.TitlePattern)
+	}
+
+	viper.Set(onlyTitleKey, "(")
+	if _, err := bookFilterFromFlags(); err == nil {
+		t.Error("invalid title pattern accepted")
+	}
+}
+
+func TestRootCommandIncludesOnlyFlags(t *testing.T) {
+	for _, key := range []string{onlyPathKey, onlyAuthorKey, onlyTitleKey} {
+		if rootCmd.Flags().Lookup(key) == nil {
+			t.Errorf("root command missing %s flag", key)
+		}
+		if len(envAliases[key]) != 2 {
+			t.Errorf("%s aliases = %v, want 2 aliases", key, envAliases[key])
+		}
+	}
+}
+
+func TestBookOrderFromFlags(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set(orderKey, "")
+		viper.Set(newestFirstKey, false)
+		viper.Set(limitKey, 0)
+	})
+
+	viper.Set(newestFirstKey, true)
+	if order, err := bookOrderFromFlags(); err != nil || order != organizer.OrderNewestFirst {
+		t.Errorf("bookOrderFromFlags() = %q, %v; want newest-first", order, err)
+	}
+
+	viper.Set(orderKey, "largest-first")
+	if _, err := bookOrderFromFlags(); err == nil {
+		t.Error("--newest-first accepted with --order=largest-first")
+	}
+
+	viper.Set(orderKey, "")
+	viper.Set(limitKey, -1)
+	if _, err := bookOrderFromFlags(); err == nil {
+		t.Error("negative --limit accepted")
+	}
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/fatih/color"
+	"github.com/jeeftor/audiobook-organizer/internal/abs"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// doctorCmd diagnoses common setup problems
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common setup problems",
+	Long: `Check the setup without moving anything and print pass, warn, or fail for each
+check:
+
+  - the version, and whether a newer release is out (skipped with --no-network)
+  - AO_* and AUDIOBOOK_ORGANIZER_* environment variables the organizer doesn't know
+  - that the input is readable and the output writable, and how they overlap
+  - free space and file system quirks of the output, such as FAT32 limits
+  - whether the input holds metadata the organizer can read
+  - whether the terminal can run the TUI
+  - that Audiobookshelf answers, when --abs-url or abs.url is configured
+
+Give it the same --dir, --out, and environment as a real run, for example inside
+the Docker container. It exits with 1 when a check fails.
+
+Examples:
+  audiobook-organizer doctor --dir=/books --out=/library
+  docker run --rm -e AO_DIR=/books -e AO_OUT=/library -v ... audiobook-organizer doctor`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().String("abs-url", "", "Audiobookshelf URL to check (default: abs.url from the config file)")
+	doctorCmd.Flags().String("abs-token", "", "Audiobookshelf API token (default: abs.token from the config file)")
+	doctorCmd.Flags().Bool(noNetworkKey, false, "Don't check GitHub for a newer release")
+	doctorCmd.Flags().Bool("json", false, "Print the checks as JSON")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	handleInputAliases(cmd)
+
+	checks := []organizer.Check{
+		doctorVersionCheck(previewFlag(cmd, noNetworkKey) == "true"),
+		doctorEnvCheck(os.Environ()),
+	}
+	checks = append(checks, doctorLibraryChecks()...)
+	checks = append(checks, doctorTerminalCheck())
+	if check, ok := doctorABSCheck(cmd); ok {
+		checks = append(checks, check)
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(checks); err != nil {
+			return err
+		}
+	} else {
+		writeDoctorChecks(cmd.OutOrStdout(), checks)
+	}
+
+	for _, check := range checks {
+		if check.Status == organizer.CheckFail {
+			return exitWith(cmd, ExitFatal)
+		}
+	}
+	return nil
+}
+
+// doctorLibraryChecks checks the input and output directories through the organizer
+func doctorLibraryChecks() []organizer.Check {
+	inputDir := firstNonEmpty(viper.GetString("dir"), viper.GetString("input"))
+	if inputDir == "" {
+		return []organizer.Check{{
+			Name:   "Input directory",
+			Status: organizer.CheckFail,
+			Detail: "no input directory; set --dir or AO_DIR",
+		}}
+	}
+
+	org, err := organizer.NewOrganizer(&organizer.OrganizerConfig{
+		BaseDir:             inputDir,
+		OutputDir:           firstNonEmpty(viper.GetString("out"), viper.GetString("output")),
+		DryRun:              true,
+		SeedSafe:            viper.GetBool(seedSafeKey),
+		UseEmbeddedMetadata: viper.GetBool(useEmbeddedMetaKey) || viper.GetBool("flat"),
+		StrictJSON:          viper.GetBool(strictJSONKey),
+		Flat:                viper.GetBool("flat"),
+		AllowProtectedDirs:  viper.GetBool(allowProtectedKey),
+		Extensions:          extensionPolicy(),
+		MinDepth:            viper.GetInt(minDepthKey),
+		MaxDepth:            viper.GetInt(maxDepthKey),
+		FieldMapping: organizer.FieldMapping{
+			TitleField:      fieldChainValue(titleFieldKey),
+			SeriesField:     fieldChainValue(seriesFieldKey),
+			AuthorFields:    stringListValue(authorFieldsKey),
+			TrackField:      fieldChainValue(trackFieldKey),
+			DiscField:       viper.GetString(discFieldKey),
+			TrackTitleField: fieldChainValue(trackTitleFieldKey),
+		},
+	})
+	if err != nil {
+		detail, _, _ := strings.Cut(err.Error(), "\n")
+		return []organizer.Check{{Name: "Configuration", Status: organizer.CheckFail, Detail: detail}}
+	}
+	return org.Diagnose()
+}
+
+// doctorVersionCheck compares the running version with the latest release
+func doctorVersionCheck(noNetwork bool) organizer.Check {
+	check := organizer.Check{Name: "Version", Status: organizer.CheckPass}
+	current := strings.TrimPrefix(buildVersion, "v")
+	currentVersion, err := semver.Parse(current)
+	switch {
+	case err != nil:
+		check.Detail = fmt.Sprintf("%s, a development build", GetDisplayVersion())
+		return check
+	case noNetwork:
+		check.Detail = fmt.Sprintf("v%s (latest release not checked with --no-network)", currentVersion)
+		return check
+	}
+
+	release, err := fetchLatestRelease()
+	if err != nil {
+		check.Status = organizer.CheckWarn
+		check.Detail = fmt.Sprintf("v%s; couldn't check for a newer release: %v", currentVersion, err)
+		return check
+	}
+	latest, err := semver.Parse(strings.TrimPrefix(release.TagName, "v"))
+	if err == nil && latest.GT(currentVersion) {
+		check.Status = organizer.CheckWarn
+		check.Detail = fmt.Sprintf("v%s is behind the latest release v%s; see %s", currentVersion, latest, release.HTMLURL)
+		return check
+	}
+	check.Detail = fmt.Sprintf("v%s is the latest release", currentVersion)
+	return check
+}
+
+// doctorEnvCheck lists the organizer's environment variables that are set, warning
+// about names it doesn't read, which are usually typos in a Docker setup
+func doctorEnvCheck(environ []string) organizer.Check {
+	check := organizer.Check{Name: "Environment", Status: organizer.CheckPass}
+	var known, unknown []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, "AO_") && !strings.HasPrefix(name, "AUDIOBOOK_ORGANIZER_") {
+			continue
+		}
+		if isKnownEnvVar(name) {
+			known = append(known, name)
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(known)
+	sort.Strings(unknown)
+
+	switch {
+	case len(unknown) > 0:
+		check.Status = organizer.CheckWarn
+		check.Detail = fmt.Sprintf("not recognized: %s (see docs/CLI.md for the supported names)", strings.Join(unknown, ", "))
+	case len(known) > 0:
+		check.Detail = "recognized: " + strings.Join(known, ", ")
+	default:
+		check.Detail = "no AO_* or AUDIOBOOK_ORGANIZER_* variables set"
+	}
+	return check
+}
+
+// isKnownEnvVar reports whether the organizer reads the environment variable name,
+// either as a listed alias or as AUDIOBOOK_ORGANIZER_ followed by a flag name
+func isKnownEnvVar(name string) bool {
+	if name == "AO_DISABLE_SELF_UPDATE" {
+		return true
+	}
+	for _, aliases := range envAliases {
+		for _, alias := range aliases {
+			if alias == name {
+				return true
+			}
+		}
+	}
+	if key, ok := strings.CutPrefix(name, "AUDIOBOOK_ORGANIZER_"); ok {
+		return rootCmd.Flags().Lookup(strings.ToLower(key)) != nil
+	}
+	return false
+}
+
+// doctorTerminalCheck reports whether the TUI can run in the current terminal
+func doctorTerminalCheck() organizer.Check {
+	check := organizer.Check{Name: "Terminal", Status: organizer.CheckPass}
+	switch {
+	case !isCharDevice(os.Stdin) || !isCharDevice(os.Stdout):
+		check.Status = organizer.CheckWarn
+		check.Detail = "not interactive, so the TUI can't run; with Docker, pass -it"
+	case os.Getenv("TERM") == "" || os.Getenv("TERM") == "dumb":
+		check.Status = organizer.CheckWarn
+		check.Detail = fmt.Sprintf("TERM is %q, so the TUI may not draw correctly", os.Getenv("TERM"))
+	default:
+		check.Detail = fmt.Sprintf("interactive, %s", colorProfileName(termenv.ColorProfile()))
+	}
+	return check
+}
+
+// isCharDevice reports whether f is a terminal rather than a file or pipe
+func isCharDevice(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorProfileName describes how many colors a terminal profile supports
+func colorProfileName(profile termenv.Profile) string {
+	switch profile {
+	case termenv.TrueColor:
+		return "true color"
+	case termenv.ANSI256:
+		return "256 colors"
+	case termenv.ANSI:
+		return "16 colors"
+	default:
+		return "no colors"
+	}
+}
+
+// doctorABSCheck checks that Audiobookshelf answers. It reports false when no
+// Audiobookshelf URL is configured.
+func doctorABSCheck(cmd *cobra.Command) (organizer.Check, bool) {
+	url, _ := cmd.Flags().GetString("abs-url")
+	token, _ := cmd.Flags().GetString("abs-token")
+	url = firstNonEmpty(url, viper.GetString("abs.url"))
+	token = firstNonEmpty(token, viper.GetString("abs.token"))
+	if url == "" {
+		return organizer.Check{}, false
+	}
+
+	check := organizer.Check{Name: "Audiobookshelf", Status: organizer.CheckPass}
+	libraries, err := abs.NewClient(url, token).GetLibraries()
+	if err != nil {
+		check.Status = organizer.CheckFail
+		check.Detail = fmt.Sprintf("%s: %v", url, err)
+		return check, true
+	}
+	check.Detail = fmt.Sprintf("%s answered with %d libraries", url, len(libraries))
+	return check, true
+}
+
+// writeDoctorChecks prints one line per check and a count of each outcome
+func writeDoctorChecks(out io.Writer, checks []organizer.Check) {
+	icons := map[organizer.CheckStatus]string{
+		organizer.CheckPass: color.GreenString("✔ pass"),
+		organizer.CheckWarn: color.YellowString("! warn"),
+		organizer.CheckFail: color.RedString("✗ fail"),
+	}
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+
+	counts := map[organizer.CheckStatus]int{}
+	for _, check := range checks {
+		counts[check.Status]++
+		fmt.Fprintf(out, "%s  %-*s  %s\n", icons[check.Status], width, check.Name, check.Detail)
+	}
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n",
+		counts[organizer.CheckPass], counts[organizer.CheckWarn], counts[organizer.CheckFail])
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+func TestDoctorEnvCheck(t *testing.T) {
+	check := doctorEnvCheck([]string{"AO_DIR=/books", "AUDIOBOOK_ORGANIZER_LAYOUT=author-title", "HOME=/root"})
+	if check.Status != organizer.CheckPass {
+		t.Fatalf("Status = %s, want pass: %s", check.Status, check.Detail)
+	}
+	if check.Detail != "recognized: AO_DIR, AUDIOBOOK_ORGANIZER_LAYOUT" {
+		t.Errorf("Detail = %q", check.Detail)
+	}
+
+	check = doctorEnvCheck([]string{"AO_DIR=/books", "AO_OUTPUT_DIR=/library", "AO_DISABLE_SELF_UPDATE=1"})
+	if check.Status != organizer.CheckWarn {
+		t.Fatalf("Status = %s, want warn", check.Status)
+	}
+	if !strings.Contains(check.Detail, "not recognized: AO_OUTPUT_DIR") {
+		t.Errorf("Detail = %q, want AO_OUTPUT_DIR flagged", check.Detail)
+	}
+}
+
+func TestWriteDoctorChecks(t *testing.T) {
+	var out bytes.Buffer
+	writeDoctorChecks(&out, []organizer.Check{
+		{Name: "Version", Status: organizer.CheckPass, Detail: "v1.0.0"},
+		{Name: "Input directory", Status: organizer.CheckFail, Detail: "/books: no such file or directory"},
+	})
+
+	got := out.String()
+	for _, want := range []string{
+		"Version          v1.0.0",
+		"Input directory  /books: no such file or directory",
+		"1 passed, 0 warnings, 1 failed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
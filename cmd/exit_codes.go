@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by the organize command. Scripts and systemd units can rely on
+// these values; see docs/CLI.md.
+const (
+	ExitOK                  = 0 // Run completed and moved (or planned) at least one book
+	ExitFatal               = 1 // Configuration or runtime error stopped the run
+	ExitCompletedWithErrors = 2 // Run finished but one or more books failed
+	ExitNothingToDo         = 3 // Run finished without anything to move
+)
+
+// exitCodeError ends a command that already reported its outcome with a non-zero
+// exit code. Execute turns it back into a code instead of printing it as an error.
+type exitCodeError struct {
+	code int
+}
+
+func (e exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// exitWith returns the error that makes Execute report code, or nil for ExitOK
+func exitWith(cmd *cobra.Command, code int) error {
+	if code == ExitOK {
+		return nil
+	}
+	// The command printed its own outcome, so cobra must not add an error or usage
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return exitCodeError{code: code}
+}
+
+// exitCodeFor returns the exit code for the error a command finished with
+func exitCodeFor(err error) int {
+	var exit exitCodeError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	if err != nil {
+		return ExitFatal
+	}
+	return ExitOK
+}
+
+// exitCodeForStatus maps an organizer run status to a process exit code.
+func exitCodeForStatus(status organizer.RunStatus) int {
+	switch status {
+	case organizer.RunStatusOK:
+		return ExitOK
+	case organizer.RunStatusCompletedWithErrors:
+		return ExitCompletedWithErrors
+	case organizer.RunStatusNothingToDo:
+		return ExitNothingToDo
+	default:
+		return ExitFatal
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func TestExitCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status organizer.RunStatus
+		want   int
+	}{
+		{organizer.RunStatusOK, ExitOK},
+		{organizer.RunStatusFatal, ExitFatal},
+		{organizer.RunStatusCompletedWithErrors, ExitCompletedWithErrors},
+		{organizer.RunStatusNothingToDo, ExitNothingToDo},
+		{organizer.RunStatus("unknown"), ExitFatal},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := exitCodeForStatus(tt.status); got != tt.want {
+				t.Errorf("exitCodeForStatus(%q) = %d, want %d", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitWithCarriesCodeThroughExecute(t *testing.T) {
+	command := &cobra.Command{
+		Use: "probe",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exitWith(cmd, ExitNothingToDo)
+		},
+	}
+	command.SetArgs([]string{})
+
+	err := command.Execute()
+
+	if got := exitCodeFor(err); got != ExitNothingToDo {
+		t.Errorf("exitCodeFor() = %d, want %d", got, ExitNothingToDo)
+	}
+	if !command.SilenceErrors {
+		t.Error("exitWith left cobra printing the exit code as an error")
+	}
+	if exitWith(command, ExitOK) != nil {
+		t.Error("exitWith(ExitOK) returned an error")
+	}
+	if got := exitCodeFor(errors.New("boom")); got != ExitFatal {
+		t.Errorf("exitCodeFor(other error) = %d, want %d", got, ExitFatal)
+	}
+}
+
+func TestShouldPrintStartupBannerQuiet(t *testing.T) {
+	for _, args := range [][]string{{"--quiet"}, {"-q", "--dir=books"}, {"--dir=books", "--quiet=true"}} {
+		if shouldPrintStartupBanner(args) {
+			t.Errorf("shouldPrintStartupBanner(%v) = true, want false", args)
+		}
+	}
+	if !shouldPrintStartupBanner([]string{"--dir=books"}) {
+		t.Error("shouldPrintStartupBanner without --quiet = false, want true")
+	}
+}
+
+func TestShouldPrintStartupBannerPlan(t *testing.T) {
+	for _, args := range [][]string{{"--dry-run", "--format=plan"}, {"--format", "plan", "--dry-run"}} {
+		if shouldPrintStartupBanner(args) {
+			t.Errorf("shouldPrintStartupBanner(%v) = true, want false", args)
+		}
+	}
+	if !shouldPrintStartupBanner([]string{"--format=text"}) {
+		t.Error("shouldPrintStartupBanner with --format=text = false, want true")
+	}
+}
+
+func TestPlanOutput(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		format  string
+		dryRun  bool
+		want    bool
+		wantErr bool
+	}{
+		{"", false, false, false},
+		{"text", true, false, false},
+		{"plan", true, true, false},
+		{"plan", false, false, true},
+		{"yaml", true, false, true},
+	}
+
+	for _, tt := range tests {
+		viper.Set(formatKey, tt.format)
+		got, err := planOutput(tt.dryRun)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("planOutput(%v) with --format=%q = %v, %v", tt.dryRun, tt.format, got, err)
+		}
+	}
+}
+
+func TestUndoCommands(t *testing.T) {
+	defer viper.Reset()
+
+	got := undoCommands("/in", "/out", "/out/.abook-org.log")
+	want := []string{
+		"audiobook-organizer --input=/in --undo",
+		"audiobook-organizer --input=/in --output=/out --undo",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("undoCommands() = %q, want %q", got, want)
+	}
+
+	viper.Set(logPathKey, "/var/log/books.log")
+	got = undoCommands("/in", "/out", "/var/log/books.log")
+	if want := "audiobook-organizer --input=/in --log-path=/var/log/books.log --undo"; len(got) != 1 || got[0] != want {
+		t.Errorf("undoCommands() with --log-path = %q, want %q", got, want)
+	}
+}
+
+func TestWriteRunReportWritesHTML(t *testing.T) {
+	defer viper.Reset()
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	viper.Set(htmlReportKey, path)
+	writeRunReport(organizer.NewRunReport(organizer.Summary{}, true, nil), organizer.HTMLReportContext{InputDir: "/in"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("HTML report not written: %v", err)
+	}
+	if !strings.Contains(string(data), "Status: nothing_to_do") {
+		t.Errorf("HTML report missing status:\n%s", data)
+	}
+}
+
+func TestEmailConfigFromFlags(t *testing.T) {
+	defer viper.Reset()
+
+	if config, err := emailConfigFromFlags(); config != nil || err != nil {
+		t.Fatalf("emailConfigFromFlags() without --email-summary = %v, %v; want nil, nil", config, err)
+	}
+
+	viper.Set(emailSummaryKey, "failure")
+	if _, err := emailConfigFromFlags(); err == nil {
+		t.Error("emailConfigFromFlags() accepted a missing email section")
+	}
+
+	viper.Set("email.host", "smtp.example.com")
+	viper.Set("email.port", 465)
+	viper.Set("email.from", "nas@example.com")
+	viper.Set("email.to", "me@example.com, you@example.com")
+	config, err := emailConfigFromFlags()
+	if err != nil {
+		t.Fatalf("emailConfigFromFlags() error = %v", err)
+	}
+	if config.Port != 465 || len(config.To) != 2 || config.To[1] != "you@example.com" {
+		t.Errorf("emailConfigFromFlags() = %+v", *config)
+	}
+}
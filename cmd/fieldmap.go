@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultFieldmapSampleSize = 200
+
+// fieldmapCmd is the parent command for field mapping helpers
+var fieldmapCmd = &cobra.Command{
+	Use:   "fieldmap",
+	Short: "Field mapping helpers",
+	Long: `Helpers for choosing --title-field, --series-field, --author-fields,
+--track-field, and --disc-field values.`,
+}
+
+// fieldmapDetectCmd reports populated raw fields and suggests a field mapping
+var fieldmapDetectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "Report populated metadata fields and suggest a field mapping",
+	Long: `Scan a sample of files and report which raw metadata fields are populated,
+how often, and with which values. A field mapping is suggested from the fields
+with the best coverage, for example "series found in 'album' for 85% of files".
+
+This gives CLI users the same insight as the TUI metadata widget in a
+scriptable form.
+
+Examples:
+  # Report fields for the first 200 files
+  audiobook-organizer fieldmap detect --dir=/path/to/books
+
+  # Scan every file and print JSON for scripts
+  audiobook-organizer fieldmap detect --dir=/path/to/books --sample=0 --json
+
+  # Ignore metadata.json and inspect embedded tags only
+  audiobook-organizer fieldmap detect --dir=/path/to/books --use-embedded-metadata`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if metadataInputDir(cmd) == "" {
+			return errMetadataDirRequired()
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputDir := metadataInputDir(cmd)
+		sample, _ := cmd.Flags().GetInt("sample")
+		top, _ := cmd.Flags().GetInt("top")
+
+		output, err := organizer.InspectMetadataDirectory(inputDir, organizer.MetadataInspectionConfig{
+			UseEmbeddedMetadata: metadataUseEmbedded(cmd),
+			StrictJSON:          viper.GetBool(strictJSONKey),
+			MaxFiles:            sample,
+		})
+		if err != nil {
+			return err
+		}
+		report := organizer.DetectFieldMapping(output, top)
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		}
+
+		writeFieldDetectionReport(cmd.OutOrStdout(), inputDir, report)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fieldmapCmd)
+	fieldmapCmd.AddCommand(fieldmapDetectCmd)
+
+	fieldmapDetectCmd.Flags().StringP("dir", "d", "", "Directory to scan for audiobooks (required)")
+	fieldmapDetectCmd.Flags().String("input", "", "Alias for --dir")
+	fieldmapDetectCmd.Flags().
+		Bool("use-embedded-metadata", false, "Force use of embedded metadata (ignore metadata.json)")
+	fieldmapDetectCmd.Flags().Bool("flat", false, "Flat mode (implies --use-embedded-metadata)")
+	fieldmapDetectCmd.Flags().
+		Int("sample", defaultFieldmapSampleSize, "Maximum number of files to scan (0 scans every file)")
+	fieldmapDetectCmd.Flags().
+		Int("top", organizer.DefaultFieldDetectionTopValues, "Number of most common values to show per field")
+	fieldmapDetectCmd.Flags().Bool("json", false, "Write the detection report as JSON")
+}
+
+func writeFieldDetectionReport(
+	out io.Writer,
+	inputDir string,
+	report organizer.FieldDetectionReport,
+) {
+	fmt.Fprintf(out, "Field detection: %s\n", inputDir)
+	fmt.Fprintf(out, "Files sampled: %d\n", report.FilesSampled)
+	fmt.Fprintf(out, "Errors: %d\n\n", report.Errors)
+
+	fmt.Fprintln(out, "Populated fields:")
+	if len(report.Fields) == 0 {
+		fmt.Fprintln(out, "  -")
+	}
+	for _, field := range report.Fields {
+		values := make([]string, 0, len(field.TopValues))
+		for _, value := range field.TopValues {
+			values = append(values, fmt.Sprintf("%q (%d)", truncateMetadataValue(value.Value), value.Count))
+		}
+		fmt.Fprintf(out, "  %-20s %4d/%-4d %5.1f%%  %s\n",
+			field.Field, field.Populated, report.FilesSampled, field.Percent, strings.Join(values, ", "))
+	}
+
+	fmt.Fprintln(out, "\nSuggestions:")
+	if len(report.Suggestions) == 0 {
+		fmt.Fprintln(out, "  -")
+		return
+	}
+	for _, suggestion := range report.Suggestions {
+		fmt.Fprintf(out, "  %s\n", suggestion.Message)
+	}
+
+	mapping := report.SuggestedMapping
+	fmt.Fprintln(out, "\nSuggested flags:")
+	var flags []string
+	if mapping.TitleField != "" {
+		flags = append(flags, "--title-field="+mapping.TitleField)
+	}
+	if mapping.SeriesField != "" {
+		flags = append(flags, "--series-field="+mapping.SeriesField)
+	}
+	if len(mapping.AuthorFields) > 0 {
+		flags = append(flags, "--author-fields="+strings.Join(mapping.AuthorFields, ","))
+	}
+	if mapping.TrackField != "" {
+		flags = append(flags, "--track-field="+mapping.TrackField)
+	}
+	if mapping.DiscField != "" {
+		flags = append(flags, "--disc-field="+mapping.DiscField)
+	}
+	fmt.Fprintf(out, "  %s\n", strings.Join(flags, " "))
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+func TestFieldmapDetectCmd_Registered(t *testing.T) {
+	found := false
+	for _, command := range fieldmapCmd.Commands() {
+		if command.Use == "detect" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("fieldmap detect command is not registered")
+	}
+	if flag := fieldmapDetectCmd.Flags().Lookup("sample"); flag == nil {
+		t.Fatal("fieldmap detect missing sample flag")
+	}
+}
+
+func TestFieldmapDetect_WithMP3FlatFixture(t *testing.T) {
+	fixtureDir := filepath.Join("..", "testdata", "mp3flat")
+
+	var out bytes.Buffer
+	fieldmapDetectCmd.SetOut(&out)
+	t.Cleanup(func() { fieldmapDetectCmd.SetOut(nil) })
+	if err := fieldmapDetectCmd.Flags().Set("dir", fixtureDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := fieldmapDetectCmd.Flags().Set("json", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fieldmapDetectCmd.Flags().Set("sample", "2"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		fieldmapDetectCmd.Flags().Set("dir", "")
+		fieldmapDetectCmd.Flags().Set("json", "false")
+		fieldmapDetectCmd.Flags().Set("sample", "200")
+	})
+
+	if err := fieldmapDetectCmd.RunE(fieldmapDetectCmd, nil); err != nil {
+		t.Fatalf("fieldmap detect error = %v", err)
+	}
+
+	var report organizer.FieldDetectionReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("fieldmap detect output is not JSON: %v\n%s", err, out.String())
+	}
+	if report.FilesSampled != 2 {
+		t.Fatalf("FilesSampled = %d, want 2", report.FilesSampled)
+	}
+	if len(report.Fields) == 0 {
+		t.Fatal("expected populated fields in report")
+	}
+}
+
+func TestWriteFieldDetectionReport(t *testing.T) {
+	report := organizer.DetectFieldMapping(organizer.MetadataInspectionOutput{
+		Files: []organizer.MetadataInspectionFile{
+			{RawData: map[string]interface{}{"title": "Dune", "album": "Dune Saga", "artist": "Frank Herbert"}},
+		},
+	}, 3)
+
+	var out bytes.Buffer
+	writeFieldDetectionReport(&out, "/books", report)
+
+	text := out.String()
+	for _, want := range []string{
+		"Files sampled: 1",
+		"series found in 'album' for 100% of files",
+		"--series-field=album",
+		"--author-fields=artist",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
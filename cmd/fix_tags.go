@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+)
+
+// fixTagsCmd makes the album-wide tags of each album's tracks agree
+var fixTagsCmd = &cobra.Command{
+	Use:   "fix-tags",
+	Short: "Rewrite album and artist tags that differ from the rest of their album",
+	Long: `Find albums whose tracks disagree on the album, artist, or album artist tag
+and rewrite the outliers to the value most of the album's tracks share. One track
+tagged "Dune (Unabridged)" among twenty tagged "Dune" otherwise splits the book in
+flat mode and in media servers that group by album.
+
+Albums are detected as in --flat mode: the tracks of one directory that share
+album metadata. A field is only fixed when a strict majority of the tracks agree
+on it. Only MP3 files (ID3v2) are rewritten; outliers in other formats are listed
+so you can fix them with a tag editor.
+
+Each file is copied to a timestamped folder below --backup-dir
+(default: <dir>/` + organizer.TagBackupDirName + `) before its tags change. Use
+--dry-run to list the changes without writing anything.
+
+Examples:
+  audiobook-organizer fix-tags --dir=/media/incoming --dry-run
+  audiobook-organizer fix-tags --dir=/media/incoming --backup-dir=/media/tag-backups`,
+	Args: cobra.NoArgs,
+	RunE: runFixTags,
+}
+
+func init() {
+	rootCmd.AddCommand(fixTagsCmd)
+
+	fixTagsCmd.Flags().String("backup-dir", "", "Directory to copy files into before changing their tags (default: <dir>/"+organizer.TagBackupDirName+")")
+	fixTagsCmd.Flags().Bool("no-backup", false, "Change tags without copying the files first")
+	fixTagsCmd.Flags().Bool("json", false, "Print the result as JSON")
+}
+
+func runFixTags(cmd *cobra.Command, args []string) error {
+	dir, err := inputDirFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		return fmt.Errorf("--dir is required")
+	}
+	dryRun, err := boolFlagOrViper(cmd, dryRunKey)
+	if err != nil {
+		return err
+	}
+
+	options := organizer.TagFixOptions{DryRun: dryRun}
+	options.BackupDir, _ = cmd.Flags().GetString("backup-dir")
+	options.NoBackup, _ = cmd.Flags().GetBool("no-backup")
+	result, err := organizer.FixTags(dir, options)
+	if err != nil {
+		return err
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		writeTagFixes(cmd.OutOrStdout(), dir, result, dryRun)
+	}
+
+	if len(result.Errors) > 0 {
+		return exitWith(cmd, ExitCompletedWithErrors)
+	}
+	return nil
+}
+
+func writeTagFixes(out io.Writer, dir string, result organizer.TagFixResult, dryRun bool) {
+	if len(result.Groups) == 0 {
+		fmt.Fprintln(out, "✅ Every album's tracks have consistent tags")
+		return
+	}
+	for _, group := range result.Groups {
+		fmt.Fprintf(out, "\n%s (%s, %d tracks)\n", group.Album, relativePreviewPath(dir, group.Dir), group.Tracks)
+		for _, fix := range group.Fixes {
+			fmt.Fprintf(out, "  %s: %s %q → %q\n", filepath.Base(fix.Path), fix.Field, fix.From, fix.To)
+		}
+		for _, path := range group.Unsupported {
+			fmt.Fprintf(out, "  %s: differs, but only MP3 tags can be rewritten\n", filepath.Base(path))
+		}
+	}
+
+	fmt.Fprintln(out)
+	if dryRun {
+		fmt.Fprintf(out, "Would fix %d file(s) (dry run)\n", result.Files)
+	} else {
+		fmt.Fprintf(out, "Fixed %d file(s)\n", result.Files)
+	}
+	if result.BackupDir != "" {
+		fmt.Fprintf(out, "Originals backed up to %s\n", result.BackupDir)
+	}
+	for _, err := range result.Errors {
+		fmt.Fprintf(out, "❌ %s\n", err)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// id3Track returns an MP3 file with an ID3v2.3 tag holding the given album
+func id3Track(album string) []byte {
+	var frames bytes.Buffer
+	for _, frame := range [][2]string{{"TALB", album}, {"TPE1", "Frank Herbert"}} {
+		frames.WriteString(frame[0])
+		frames.Write(binary.BigEndian.AppendUint32(nil, uint32(len(frame[1])+1)))
+		frames.Write([]byte{0, 0, 0})
+		frames.WriteString(frame[1])
+	}
+	size := frames.Len()
+	tag := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7f), byte(size >> 14 & 0x7f), byte(size >> 7 & 0x7f), byte(size & 0x7f)}
+	return append(append(tag, frames.Bytes()...), "fake mpeg audio"...)
+}
+
+func TestFixTagsCommand(t *testing.T) {
+	root := t.TempDir()
+	viper.Set("dir", root)
+	viper.Set(dryRunKey, true)
+	t.Cleanup(func() {
+		viper.Set("dir", "")
+		viper.Set(dryRunKey, false)
+	})
+
+	book := filepath.Join(root, "Dune")
+	if err := os.MkdirAll(book, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i, album := range []string{"Dune", "Dune (Unabridged)", "Dune"} {
+		name := filepath.Join(book, "Part "+string(rune('1'+i))+".mp3")
+		if err := os.WriteFile(name, id3Track(album), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	fixTagsCmd.SetOut(&buf)
+	if err := fixTagsCmd.RunE(fixTagsCmd, nil); err != nil {
+		t.Fatalf("RunE() = %v", err)
+	}
+	for _, want := range []string{"Dune (Dune, 3 tracks)", `Part 2.mp3: album "Dune (Unabridged)" → "Dune"`, "Would fix 1 file(s)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(book, "Part 2.mp3")); !bytes.Equal(data, id3Track("Dune (Unabridged)")) {
+		t.Error("Expected a dry run to leave the file alone")
+	}
+}
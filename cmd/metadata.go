@@ -6,6 +6,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 	"github.com/spf13/cobra"
@@ -31,6 +32,11 @@ used by verbose organize output.
 The output includes each file path, metadata source type, title, authors,
 series, track number, album, and extraction errors when present.
 
+Use --explain to show, for each file, which raw field supplied the title,
+authors, series, and track under the field mapping flags, and which fallbacks
+were tried and skipped on the way. With --json, the same explanation is added
+to each file.
+
 Examples:
   # Inspect metadata in the terminal
   audiobook-organizer metadata --dir=/path/to/books
@@ -47,6 +53,10 @@ Examples:
   # Flat mode (implies embedded metadata)
   audiobook-organizer metadata --dir=/path --flat
 
+  # Show which tag supplied each field, with the fallbacks tried
+  audiobook-organizer metadata --dir=/path --flat --explain \
+    --title-field=album,title --series-field=series,mvnm,=Standalone
+
   # Launch the interactive metadata TUI
   audiobook-organizer metadata-tui --dir=/path/to/books
 
@@ -69,6 +79,9 @@ Examples:
 			return runMetadataJSON(cmd, inputDir)
 		}
 
+		if explain, _ := cmd.Flags().GetBool("explain"); explain {
+			return runMetadataExplain(cmd, inputDir)
+		}
 		return runMetadataText(cmd, inputDir)
 	},
 }
@@ -85,6 +98,8 @@ func init() {
 	metadataCmd.Flags().Bool("json", false, "Write metadata scan results as JSON")
 	metadataCmd.Flags().Bool("pretty", false, "Write formatter-backed pretty metadata output")
 	metadataCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+	metadataCmd.Flags().
+		Bool("explain", false, "Show which raw field supplied each file's title, authors, series, and track, and the fallbacks skipped")
 
 	// Field mapping flags (for metadata.json customization)
 	metadataCmd.Flags().String("title-field", "", "Field to use for title (e.g., 'title', 'album')")
@@ -103,6 +118,7 @@ func init() {
 	viper.BindPFlag("json", metadataCmd.Flags().Lookup("json"))
 	viper.BindPFlag("pretty", metadataCmd.Flags().Lookup("pretty"))
 	viper.BindPFlag("verbose", metadataCmd.Flags().Lookup("verbose"))
+	viper.BindPFlag("explain", metadataCmd.Flags().Lookup("explain"))
 	viper.BindPFlag("title-field", metadataCmd.Flags().Lookup("title-field"))
 	viper.BindPFlag("series-field", metadataCmd.Flags().Lookup("series-field"))
 	viper.BindPFlag("author-fields", metadataCmd.Flags().Lookup("author-fields"))
@@ -110,7 +126,13 @@ func init() {
 }
 
 func runMetadataJSON(cmd *cobra.Command, inputDir string) error {
-	output, err := scanMetadataJSON(inputDir, metadataUseEmbedded(cmd), metadataFieldMapping(cmd))
+	explain, _ := cmd.Flags().GetBool("explain")
+	output, err := organizer.InspectMetadataDirectory(inputDir, organizer.MetadataInspectionConfig{
+		UseEmbeddedMetadata: metadataUseEmbedded(cmd),
+		StrictJSON:          viper.GetBool(strictJSONKey),
+		FieldMapping:        metadataFieldMapping(cmd),
+		Explain:             explain,
+	})
 	if err != nil {
 		return err
 	}
@@ -156,6 +178,9 @@ func runMetadataText(cmd *cobra.Command, inputDir string) error {
 			fmt.Fprintf(out, "  Track Title: %s\n", file.TrackTitle)
 		}
 		fmt.Fprintf(out, "  Album: %s\n", valueOrDash(file.Album))
+		if file.Audio != nil {
+			fmt.Fprintf(out, "  Audio: %s\n", file.Audio)
+		}
 		if file.Error != "" {
 			fmt.Fprintf(out, "  Error: %s\n", file.Error)
 		}
@@ -165,6 +190,76 @@ func runMetadataText(cmd *cobra.Command, inputDir string) error {
 	return nil
 }
 
+// runMetadataExplain prints, per file, where each mapped field came from
+func runMetadataExplain(cmd *cobra.Command, inputDir string) error {
+	fieldMapping := metadataFieldMapping(cmd)
+	output, err := organizer.InspectMetadataDirectory(inputDir, organizer.MetadataInspectionConfig{
+		UseEmbeddedMetadata: metadataUseEmbedded(cmd),
+		StrictJSON:          viper.GetBool(strictJSONKey),
+		FieldMapping:        fieldMapping,
+		Explain:             true,
+	})
+	if err != nil {
+		return err
+	}
+	writeMetadataExplain(cmd.OutOrStdout(), inputDir, output, fieldMapping)
+	return nil
+}
+
+// writeMetadataExplain writes one table per file: each mapped field with its value,
+// the candidate that supplied it, and the candidates tried before it without a value
+func writeMetadataExplain(
+	out io.Writer,
+	inputDir string,
+	output metadataJSONOutput,
+	fieldMapping organizer.FieldMapping,
+) {
+	fmt.Fprintln(out, "🔎 Field mapping")
+	fmt.Fprintf(out, "  📁 Directory: %s\n", inputDir)
+	fmt.Fprintf(out, "  Title: %s  Authors: %s  Series: %s  Track: %s\n\n",
+		valueOrDash(fieldMapping.TitleField), joinedOrDash(fieldMapping.AuthorFields),
+		valueOrDash(fieldMapping.SeriesField), valueOrDash(fieldMapping.TrackField))
+
+	for i, file := range output.Files {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "📄 %s (%s)\n", file.Path, valueOrDash(file.SourceType))
+		if file.Error != "" {
+			fmt.Fprintf(out, "  ⚠️ Error: %s\n", file.Error)
+			continue
+		}
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, field := range file.Explanation {
+			var skipped []string
+			for _, candidate := range field.Candidates {
+				if candidate.Status == organizer.CandidateEmpty {
+					skipped = append(skipped, candidate.Candidate)
+				}
+			}
+			fmt.Fprintf(table, "  %s\t%s\t← %s", field.Field, valueOrDash(field.Value), explainedSource(field, file.SourceType))
+			if len(skipped) > 0 {
+				fmt.Fprintf(table, " (skipped: %s)", strings.Join(skipped, ", "))
+			}
+			fmt.Fprintln(table)
+		}
+		table.Flush()
+	}
+}
+
+// explainedSource names where a field's value came from: the candidate that
+// supplied it, the metadata source as read when no candidate did, or nothing
+func explainedSource(field organizer.FieldExplanation, sourceType string) string {
+	switch {
+	case field.Source != "":
+		return field.Source
+	case field.Value != "":
+		return "as read from " + valueOrDash(sourceType)
+	default:
+		return "none"
+	}
+}
+
 func writeMetadataPretty(
 	out io.Writer,
 	inputDir string,
@@ -199,6 +294,7 @@ func scanMetadataJSON(
 ) (metadataJSONOutput, error) {
 	return organizer.InspectMetadataDirectory(inputDir, organizer.MetadataInspectionConfig{
 		UseEmbeddedMetadata: useEmbedded,
+		StrictJSON:          viper.GetBool(strictJSONKey),
 		FieldMapping:        fieldMapping,
 	})
 }
@@ -374,6 +374,47 @@ func TestRunMetadataTextPretty_UsesFormatterBackedOutput(t *testing.T) {
 	}
 }
 
+func TestRunMetadataExplain_ShowsSuppliedAndSkippedFields(t *testing.T) {
+	fixtureDir := filepath.Join("..", "testdata", "mp3flat")
+	cmd := newMetadataJSONTestCommand(t)
+	cmd.Flags().Set("flat", "true")
+	cmd.Flags().Set("title-field", "album,title")
+	cmd.Flags().Set("author-fields", "album_artist,artist")
+	cmd.Flags().Set("track-field", "trck,track")
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMetadataExplain(cmd, fixtureDir); err != nil {
+		t.Fatalf("runMetadataExplain() error = %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"title    The Case of Charles Dexter Ward  ← album",
+		"← artist (skipped: album_artist)",
+		"← track (skipped: trck)",
+		"← as read from audio",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("explain output missing %q:\n%s", want, output)
+		}
+	}
+
+	cmd.Flags().Set("explain", "true")
+	buf.Reset()
+	if err := runMetadataJSON(cmd, fixtureDir); err != nil {
+		t.Fatalf("runMetadataJSON() error = %v", err)
+	}
+	var result metadataJSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("metadata JSON did not parse: %v", err)
+	}
+	title := result.Files[0].Explanation[0]
+	if title.Field != "title" || title.Source != "album" || len(title.Candidates) != 2 ||
+		title.Candidates[1].Status != organizer.CandidateNotTried {
+		t.Errorf("title explanation = %+v, want album used and title not tried", title)
+	}
+}
+
 func TestScanMetadataJSON_ReportsExtractionErrors(t *testing.T) {
 	tmpDir := t.TempDir()
 	audioPath := filepath.Join(tmpDir, "broken.mp3")
@@ -439,6 +480,7 @@ func newMetadataJSONTestCommand(t *testing.T) *cobra.Command {
 	cmd.Flags().Bool("json", false, "Write metadata scan results as JSON")
 	cmd.Flags().Bool("pretty", false, "Write formatter-backed pretty metadata output")
 	cmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+	cmd.Flags().Bool("explain", false, "Show which raw field supplied each mapped field")
 	cmd.Flags().String("title-field", "", "Field to use for title")
 	cmd.Flags().String("series-field", "", "Field to use for series")
 	cmd.Flags().String("author-fields", "", "Comma-separated fields for authors")
@@ -26,6 +26,9 @@ This interactive mode allows you to:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		inputDir := metadataInputDir(cmd)
 		syncMetadataFlagsToViper(cmd, inputDir)
+		if err := applyTUIAppearance(cmd); err != nil {
+			return err
+		}
 		return tui.RunRenameMode(inputDir)
 	},
 }
@@ -47,4 +50,5 @@ func init() {
 		String("author-fields", "", "Comma-separated fields for authors (e.g., 'artist,album_artist')")
 	metadataTuiCmd.Flags().
 		String("track-field", "", "Field to use for track number (e.g., 'track', 'track_number')")
+	addTUIAppearanceFlags(metadataTuiCmd)
 }
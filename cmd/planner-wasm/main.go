@@ -0,0 +1,57 @@
+//go:build js && wasm
+
+// Command planner-wasm exposes the planning core to the web UI as WebAssembly, so
+// layout and filename templates can be previewed in the browser while typing.
+//
+// Build it with "make wasm-build". The module registers a global
+// audiobookPlanner object with two functions that take and return JSON strings:
+//
+//	audiobookPlanner.preview(request)          // planning.PreviewRequest -> planning.PreviewResponse
+//	audiobookPlanner.validateTemplate(template) // -> {"error": "..."} or {}
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/jeeftor/audiobook-organizer/internal/planning"
+)
+
+func main() {
+	js.Global().Set("audiobookPlanner", js.ValueOf(map[string]interface{}{
+		"preview":          js.FuncOf(preview),
+		"validateTemplate": js.FuncOf(validateTemplate),
+	}))
+
+	// Keep the Go runtime alive so the registered functions stay callable
+	select {}
+}
+
+func preview(_ js.Value, args []js.Value) interface{} {
+	var request planning.PreviewRequest
+	if len(args) == 0 {
+		return marshal(planning.PreviewResponse{Error: "missing preview request"})
+	}
+	if err := json.Unmarshal([]byte(args[0].String()), &request); err != nil {
+		return marshal(planning.PreviewResponse{Error: "invalid preview request: " + err.Error()})
+	}
+	return marshal(planning.Preview(request))
+}
+
+func validateTemplate(_ js.Value, args []js.Value) interface{} {
+	result := map[string]string{}
+	if len(args) == 0 {
+		result["error"] = "missing template"
+	} else if err := planning.ValidateTemplate(args[0].String()); err != nil {
+		result["error"] = err.Error()
+	}
+	return marshal(result)
+}
+
+func marshal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return `{"error":"failed to encode response"}`
+	}
+	return string(data)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultPreviewLimit = 20
+
+// previewCmd prints where a sample of books would be moved
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Preview target paths for a sample of books",
+	Long: `Print where the first books of a library would be moved with the given layout,
+without moving anything and without walking the whole library.
+
+This is the path preview of the TUI in a scriptable form, a quick way to check a
+--layout, --layout-template, or field mapping before running the organizer. Only
+book directories are shown; use --dry-run on the main command to see every file.
+
+Examples:
+  # Preview the first 20 books with the default layout
+  audiobook-organizer preview --dir=/path/to/books
+
+  # Try a custom template on 5 books
+  audiobook-organizer preview --dir=/path/to/books --limit=5 \
+    --layout-template="{author}/{series|Standalone}/{title}"
+
+  # Check a field mapping with embedded tags
+  audiobook-organizer preview --dir=/path/to/books --use-embedded-metadata \
+    --series-field=album --layout=author-series-title-number`,
+	Args: cobra.NoArgs,
+	RunE: runPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().Int("limit", defaultPreviewLimit, "Number of books to preview (0 previews every book)")
+	previewCmd.Flags().
+		StringP("layout", "l", "author-series-title", "Directory structure layout (see the main command)")
+	previewCmd.Flags().
+		String("layout-template", "", "Custom directory layout template overriding --layout")
+	previewCmd.Flags().String(casingKey, organizer.CasingPreserve, "Casing of folder names: preserve, title, or sentence")
+	previewCmd.Flags().String(subtitleKey, organizer.SubtitleKeep, "How titles carry their subtitle: keep (as tagged), colon (Title: Subtitle), dash (Title - Subtitle), or drop")
+	previewCmd.Flags().Bool(stripTitleKey, false, "Drop a leading author or series name and number from title folders")
+	previewCmd.Flags().String("replace_space", "", "Character to replace spaces")
+	previewCmd.Flags().Bool("json", false, "Print the preview as JSON")
+}
+
+// previewFlag returns a flag given on the preview command, falling back to the
+// value of the main command's flag of the same name from the config file or environment
+func previewFlag(cmd *cobra.Command, key string) string {
+	if cmd.Flags().Changed(key) {
+		return cmd.Flags().Lookup(key).Value.String()
+	}
+	if viper.IsSet(key) {
+		return viper.GetString(key)
+	}
+	return cmd.Flags().Lookup(key).DefValue
+}
+
+func runPreview(cmd *cobra.Command, args []string) error {
+	handleInputAliases(cmd)
+	inputDir := firstNonEmpty(viper.GetString("dir"), viper.GetString("input"))
+	if inputDir == "" {
+		return errMetadataDirRequired()
+	}
+	outputDir := firstNonEmpty(viper.GetString("out"), viper.GetString("output"))
+	authorAliases, err := organizer.ParseAuthorAliases(stringListValue(authorAliasKey))
+	if err != nil {
+		return err
+	}
+
+	org, err := organizer.NewOrganizer(&organizer.OrganizerConfig{
+		BaseDir:             inputDir,
+		OutputDir:           outputDir,
+		ReplaceSpace:        previewFlag(cmd, "replace_space"),
+		DryRun:              true,
+		UseEmbeddedMetadata: viper.GetBool(useEmbeddedMetaKey) || viper.GetBool("flat"),
+		StrictJSON:          viper.GetBool(strictJSONKey),
+		Flat:                viper.GetBool("flat"),
+		Layout:              previewFlag(cmd, "layout"),
+		LayoutTemplate:      previewFlag(cmd, "layout-template"),
+		Casing:              previewFlag(cmd, casingKey),
+		Subtitle:            previewFlag(cmd, subtitleKey),
+		StripTitlePrefix:    previewFlag(cmd, stripTitleKey) == "true",
+		AuthorAliases:       authorAliases,
+		Extensions:          extensionPolicy(),
+		MinDepth:            viper.GetInt(minDepthKey),
+		MaxDepth:            viper.GetInt(maxDepthKey),
+		Locale:              viper.GetString(localeKey),
+		FieldMapping: organizer.FieldMapping{
+			TitleField:      fieldChainValue(titleFieldKey),
+			SeriesField:     fieldChainValue(seriesFieldKey),
+			AuthorFields:    stringListValue(authorFieldsKey),
+			TrackField:      fieldChainValue(trackFieldKey),
+			DiscField:       viper.GetString(discFieldKey),
+			TrackTitleField: fieldChainValue(trackTitleFieldKey),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	previews, err := org.PreviewPaths(limit)
+	if err != nil {
+		return fmt.Errorf("error scanning %s: %w", inputDir, err)
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		if previews == nil {
+			previews = []organizer.PathPreview{}
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(previews)
+	}
+	writePathPreview(cmd.OutOrStdout(), org.BaseDir(), firstNonEmpty(outputDir, org.BaseDir()), previews)
+	return nil
+}
+
+// writePathPreview prints each book's source and target below their base directories.
+// The folders leading to a book are cyan and the book folder itself is green.
+func writePathPreview(out io.Writer, inputDir, outputDir string, previews []organizer.PathPreview) {
+	dim := color.New(color.Faint)
+	arrow := color.New(color.FgYellow)
+	parent := color.New(color.FgCyan)
+	book := color.New(color.FgGreen)
+	failed := color.New(color.FgRed)
+
+	fmt.Fprintf(out, "Previewing %d book(s) from %s\n", len(previews), inputDir)
+	for _, preview := range previews {
+		fmt.Fprintf(out, "\n  %s\n", dim.Sprint(relativePreviewPath(inputDir, preview.SourcePath)))
+		if preview.Error != "" {
+			fmt.Fprintf(out, "  %s %s\n", failed.Sprint("✗"), failed.Sprint(preview.Error))
+			continue
+		}
+
+		parts := strings.Split(relativePreviewPath(outputDir, preview.TargetPath), string(filepath.Separator))
+		for i := range parts {
+			if i == len(parts)-1 {
+				parts[i] = book.Sprint(parts[i])
+			} else {
+				parts[i] = parent.Sprint(parts[i])
+			}
+		}
+		fmt.Fprintf(out, "  %s %s\n", arrow.Sprint("→"), strings.Join(parts, dim.Sprint(string(filepath.Separator))))
+	}
+}
+
+// relativePreviewPath shortens a path to be relative to base when it lies below it
+func relativePreviewPath(base, path string) string {
+	relative, err := filepath.Rel(base, path)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return relative
+}
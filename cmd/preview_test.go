@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/viper"
+)
+
+func TestPreviewCommand(t *testing.T) {
+	root := t.TempDir()
+	viper.Set("dir", root)
+	t.Cleanup(func() { viper.Set("dir", "") })
+
+	for i, title := range []string{"Dune", "Dune Messiah", "Children of Dune"} {
+		dir := filepath.Join(root, fmt.Sprintf("book%d", i))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(map[string]any{"title": title, "authors": []string{"Frank Herbert"}})
+		if err := os.WriteFile(filepath.Join(dir, organizer.MetadataFileName), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "01.mp3"), []byte("audio"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	previewCmd.SetOut(&out)
+	previewCmd.Flags().Set("limit", "2")
+	previewCmd.Flags().Set("layout", "author-title")
+	t.Cleanup(func() {
+		previewCmd.Flags().Set("limit", fmt.Sprint(defaultPreviewLimit))
+		previewCmd.Flags().Set("layout", "author-series-title")
+	})
+	if err := previewCmd.RunE(previewCmd, nil); err != nil {
+		t.Fatalf("preview error = %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"Previewing 2 book(s)", "book0", "Frank Herbert" + string(filepath.Separator) + "Dune"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "book2") {
+		t.Errorf("preview output exceeds --limit:\n%s", got)
+	}
+
+	out.Reset()
+	previewCmd.Flags().Set("json", "true")
+	t.Cleanup(func() { previewCmd.Flags().Set("json", "false") })
+	if err := previewCmd.RunE(previewCmd, nil); err != nil {
+		t.Fatalf("preview --json error = %v", err)
+	}
+	var previews []organizer.PathPreview
+	if err := json.Unmarshal(out.Bytes(), &previews); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if len(previews) != 2 || previews[0].TargetPath != filepath.Join(root, "Frank Herbert", "Dune") {
+		t.Errorf("previews = %+v", previews)
+	}
+	if _, err := os.Stat(filepath.Join(root, "book0")); err != nil {
+		t.Errorf("preview moved a book: %v", err)
+	}
+}
@@ -18,6 +18,7 @@ var (
 	renameStrictMode   bool
 	renamePreservePath bool
 	renamePrompt       bool
+	renameSubtitle     string
 )
 
 var renameCmd = &cobra.Command{
@@ -127,17 +128,21 @@ func runRename() {
 		AuthorFormat: authorFormat,
 		Recursive:    renameRecursive,
 		FieldMapping: organizer.FieldMapping{
-			TitleField:   viper.GetString("title-field"),
-			SeriesField:  viper.GetString("series-field"),
+			TitleField:   fieldChainValue("title-field"),
+			SeriesField:  fieldChainValue("series-field"),
 			AuthorFields: authorFieldsList,
-			TrackField:   viper.GetString("track-field"),
+			TrackField:   fieldChainValue("track-field"),
 			DiscField:    viper.GetString("disc-field"),
 		},
 		ReplaceSpace:        viper.GetString("replace_space"),
+		Subtitle:            renameSubtitle,
 		StrictMode:          renameStrictMode,
 		PreservePath:        renamePreservePath,
 		PromptEnabled:       renamePrompt,
 		UseEmbeddedMetadata: useEmbedded,
+		StrictJSON:          viper.GetBool(strictJSONKey),
+		Extensions:          extensionPolicy(),
+		Locale:              viper.GetString(localeKey),
 	}
 
 	renamer, err := organizer.NewRenamer(config)
@@ -183,6 +188,9 @@ func runRename() {
 			} else {
 				organizer.PrintGreen("  %s → %s", currentName, newName)
 			}
+			for _, companion := range candidate.Companions {
+				organizer.PrintBase("    + %s → %s", filepath.Base(companion.From), filepath.Base(companion.To))
+			}
 			changesShown++
 		}
 
@@ -342,6 +350,8 @@ func init() {
 		BoolVar(&renameStrictMode, "strict", false, "Error on missing template fields")
 	renameCmd.Flags().
 		BoolVar(&renamePreservePath, "preserve-path", true, "Only rename filename, preserve directory structure")
+	renameCmd.Flags().
+		StringVar(&renameSubtitle, subtitleKey, organizer.SubtitleKeep, "How {title} carries the subtitle: keep (as tagged), colon (Title: Subtitle), dash (Title - Subtitle), or drop")
 	renameCmd.Flags().BoolVar(&renamePrompt, "prompt", false, "Prompt before renaming each file")
 	renameCmd.Flags().Bool("undo", false, "Undo previous rename operations")
 
@@ -352,5 +362,6 @@ func init() {
 	viper.BindPFlag("rename-strict", renameCmd.Flags().Lookup("strict"))
 	viper.BindPFlag("rename-preserve-path", renameCmd.Flags().Lookup("preserve-path"))
 	viper.BindPFlag("rename-prompt", renameCmd.Flags().Lookup("prompt"))
+	viper.BindPFlag("rename-subtitle", renameCmd.Flags().Lookup(subtitleKey))
 	viper.BindPFlag("rename-undo", renameCmd.Flags().Lookup("undo"))
 }
@@ -35,6 +35,11 @@ metadata with visual indicators (📁 for JSON fields, 🎵 for embedded fields)
 			inputDir = cmd.Flags().Lookup("dir").Value.String()
 		}
 
+		if err := applyTUIAppearance(cmd); err != nil {
+			fmt.Printf("Error running rename TUI: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Initialize and run the rename TUI
 		if err := tui.RunRenameMode(inputDir); err != nil {
 			fmt.Printf("Error running rename TUI: %v\n", err)
@@ -49,6 +54,7 @@ func init() {
 	// Define flags with aliases matching the root command
 	renameTuiCmd.Flags().String("dir", "", "Base directory to scan (alias for --input)")
 	renameTuiCmd.Flags().StringP("input", "i", "", "Base directory to scan (alias for --dir)")
+	addTUIAppearanceFlags(renameTuiCmd)
 
 	// Note: rename-tui doesn't need output directory since files are renamed in-place
 }
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
 	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -18,35 +23,86 @@ const ( // This makes sonar pass
 	authorFieldsKey    = "author-fields"
 	trackFieldKey      = "track-field"
 	discFieldKey       = "disc-field"
+	trackTitleFieldKey = "track-title-field"
 	useEmbeddedMetaKey = "use-embedded-metadata"
 	removeEmptyKey     = "remove-empty"
 	dryRunKey          = "dry-run"
+	quietKey           = "quiet"
+	strictJSONKey      = "strict-json"
+	jsonReportKey      = "json-report"
+	htmlReportKey      = "report-html"
+	emailSummaryKey    = "email-summary"
+	diffLogKey         = "diff-log"
+	trashDirKey        = "trash-dir"
+	sftpIdentityKey    = "sftp-identity"
+	sftpKnownHostsKey  = "sftp-known-hosts"
+	fullScanKey        = "full-scan"
+	checkAuthorsKey    = "check-authors"
+	selectionKey       = "selection"
+	onlyPathKey        = "only-path"
+	onlyAuthorKey      = "only-author"
+	onlyTitleKey       = "only-title-matches"
+	logPathKey         = "log-path"
+	logChecksumsKey    = "log-checksums"
+	minFileAgeKey      = "min-file-age"
+	sizeSettleKey      = "size-settle"
+	retriesKey         = "retries"
+	retryDelayKey      = "retry-delay"
+	minConfidenceKey   = "min-confidence"
+	seedSafeKey        = "seed-safe"
+	torrentDirKey      = "torrent-dir"
+	authorLookupKey    = "author-lookup"
+	applyLookupKey     = "apply-author-lookup"
+	authorAuthorityKey = "author-authority"
+	authorAliasKey     = "author-alias"
+	writeIdentsKey     = "write-identifiers"
+	provenanceKey      = "keep-provenance"
+	seriesReadmeKey    = "series-readme"
+	ebooksKey          = "ebooks"
+	trackMapKey        = "track-map"
+	minDepthKey        = "min-depth"
+	maxDepthKey        = "max-depth"
+	fileLinesKey       = "file-lines"
+	progressEveryKey   = "progress-interval"
+	detailLogKey       = "detail-log"
+	noNetworkKey       = "no-network"
+	strictKey          = "strict"
+	hiddenFilesKey     = "hidden-files"
+	trackTitlesKey     = "track-titles"
+	mergeDiscsKey      = "merge-discs"
+	continuousKey      = "continuous-tracks"
+	continuousTagsKey  = "continuous-track-tags"
+	allowProtectedKey  = "allow-protected"
+	summaryKey         = "summary"
+	orderKey           = "order"
+	newestFirstKey     = "newest-first"
+	oldestFirstKey     = "oldest-first"
+	limitKey           = "limit"
+	yesIKnowKey        = "yes-i-know"
+	waitKey            = "wait"
+	forceUnlockKey     = "force-unlock"
+	profileReportKey   = "profile-report"
+	formatKey          = "format"
+	casingKey          = "casing"
+	stripTitleKey      = "strip-title-prefix"
+	subtitleKey        = "subtitle"
+	singleFileKey      = "single-file-layout"
+	bookFolderKey      = "book-folder"
+	fetchCoversKey     = "fetch-covers"
+	musicBrainzKey     = "musicbrainz"
+	musicBrainzURLKey  = "musicbrainz-url"
+	carryKey           = "carry-unsupported"
+	mergeKey           = "merge"
+	tuiThemeKey        = "tui-theme"
+	plainGlyphsKey     = "plain-glyphs"
+	noColorKey         = "no-color"
+	forceColorKey      = "force-color"
+	localeKey          = "locale"
+	langKey            = "lang"
+	extensionKey       = "extension"
 )
 
-var (
-	inputDir            string // Combined input from --dir and --input
-	outputDir           string // Combined output from --out and --output
-	replaceSpace        string
-	verbose             bool
-	dryRun              bool
-	undo                bool
-	prompt              bool
-	removeEmpty         bool
-	useEmbeddedMetadata bool
-	flat                bool
-	skipErrors          bool
-	layout              string // Directory structure layout
-	layoutTemplate      string // Custom directory structure template
-
-	// Field mapping flags
-	titleField   string
-	seriesField  string
-	authorFields string // Comma-separated list
-	trackField   string
-	discField    string
-
-	cfgFile string
-)
+var cfgFile string
 
 // envAliases maps config keys to their possible environment variable names
 var envAliases = map[string][]string{
@@ -84,13 +140,86 @@ var envAliases = map[string][]string{
 	"flat":             {"AO_FLAT", "AUDIOBOOK_ORGANIZER_FLAT"},
 	"layout":           {"AO_LAYOUT", "AUDIOBOOK_ORGANIZER_LAYOUT"},
 	"layout-template":  {"AO_LAYOUT_TEMPLATE", "AUDIOBOOK_ORGANIZER_LAYOUT_TEMPLATE"},
+	casingKey:          {"AO_CASING", "AUDIOBOOK_ORGANIZER_CASING"},
+	stripTitleKey:      {"AO_STRIP_TITLE_PREFIX", "AUDIOBOOK_ORGANIZER_STRIP_TITLE_PREFIX"},
+	subtitleKey:        {"AO_SUBTITLE", "AUDIOBOOK_ORGANIZER_SUBTITLE"},
+	singleFileKey:      {"AO_SINGLE_FILE_LAYOUT", "AUDIOBOOK_ORGANIZER_SINGLE_FILE_LAYOUT"},
+	bookFolderKey:      {"AO_BOOK_FOLDER", "AUDIOBOOK_ORGANIZER_BOOK_FOLDER"},
+	fetchCoversKey:     {"AO_FETCH_COVERS", "AUDIOBOOK_ORGANIZER_FETCH_COVERS"},
+	musicBrainzKey:     {"AO_MUSICBRAINZ", "AUDIOBOOK_ORGANIZER_MUSICBRAINZ"},
+	musicBrainzURLKey:  {"AO_MUSICBRAINZ_URL", "AUDIOBOOK_ORGANIZER_MUSICBRAINZ_URL"},
+	carryKey:           {"AO_CARRY_UNSUPPORTED", "AUDIOBOOK_ORGANIZER_CARRY_UNSUPPORTED"},
+	mergeKey:           {"AO_MERGE", "AUDIOBOOK_ORGANIZER_MERGE"},
+	tuiThemeKey:        {"AO_TUI_THEME", "AUDIOBOOK_ORGANIZER_TUI_THEME"},
+	plainGlyphsKey:     {"AO_PLAIN_GLYPHS", "AUDIOBOOK_ORGANIZER_PLAIN_GLYPHS"},
+	quietKey:           {"AO_QUIET", "AUDIOBOOK_ORGANIZER_QUIET"},
+	strictJSONKey:      {"AO_STRICT_JSON", "AUDIOBOOK_ORGANIZER_STRICT_JSON"},
+	noColorKey:         {"AO_NO_COLOR", "AUDIOBOOK_ORGANIZER_NO_COLOR"},
+	forceColorKey:      {"AO_FORCE_COLOR", "AUDIOBOOK_ORGANIZER_FORCE_COLOR"},
+	localeKey:          {"AO_LOCALE", "AUDIOBOOK_ORGANIZER_LOCALE"},
+	langKey:            {"AO_LANG", "AUDIOBOOK_ORGANIZER_LANG"},
+	extensionKey:       {"AO_EXTENSION", "AUDIOBOOK_ORGANIZER_EXTENSION"},
+	jsonReportKey:      {"AO_JSON_REPORT", "AUDIOBOOK_ORGANIZER_JSON_REPORT"},
+	htmlReportKey:      {"AO_REPORT_HTML", "AUDIOBOOK_ORGANIZER_REPORT_HTML"},
+	emailSummaryKey:    {"AO_EMAIL_SUMMARY", "AUDIOBOOK_ORGANIZER_EMAIL_SUMMARY"},
+	trashDirKey:        {"AO_TRASH_DIR", "AUDIOBOOK_ORGANIZER_TRASH_DIR"},
+	sftpIdentityKey:    {"AO_SFTP_IDENTITY", "AUDIOBOOK_ORGANIZER_SFTP_IDENTITY"},
+	sftpKnownHostsKey:  {"AO_SFTP_KNOWN_HOSTS", "AUDIOBOOK_ORGANIZER_SFTP_KNOWN_HOSTS"},
+	fullScanKey:        {"AO_FULL_SCAN", "AUDIOBOOK_ORGANIZER_FULL_SCAN"},
+	checkAuthorsKey:    {"AO_CHECK_AUTHORS", "AUDIOBOOK_ORGANIZER_CHECK_AUTHORS"},
+	selectionKey:       {"AO_SELECTION", "AUDIOBOOK_ORGANIZER_SELECTION"},
+	onlyPathKey:        {"AO_ONLY_PATH", "AUDIOBOOK_ORGANIZER_ONLY_PATH"},
+	onlyAuthorKey:      {"AO_ONLY_AUTHOR", "AUDIOBOOK_ORGANIZER_ONLY_AUTHOR"},
+	onlyTitleKey:       {"AO_ONLY_TITLE_MATCHES", "AUDIOBOOK_ORGANIZER_ONLY_TITLE_MATCHES"},
+	logPathKey:         {"AO_LOG_PATH", "AUDIOBOOK_ORGANIZER_LOG_PATH"},
+	logChecksumsKey:    {"AO_LOG_CHECKSUMS", "AUDIOBOOK_ORGANIZER_LOG_CHECKSUMS"},
+	minFileAgeKey:      {"AO_MIN_FILE_AGE", "AUDIOBOOK_ORGANIZER_MIN_FILE_AGE"},
+	sizeSettleKey:      {"AO_SIZE_SETTLE", "AUDIOBOOK_ORGANIZER_SIZE_SETTLE"},
+	retriesKey:         {"AO_RETRIES", "AUDIOBOOK_ORGANIZER_RETRIES"},
+	retryDelayKey:      {"AO_RETRY_DELAY", "AUDIOBOOK_ORGANIZER_RETRY_DELAY"},
+	minConfidenceKey:   {"AO_MIN_CONFIDENCE", "AUDIOBOOK_ORGANIZER_MIN_CONFIDENCE"},
+	seedSafeKey:        {"AO_SEED_SAFE", "AUDIOBOOK_ORGANIZER_SEED_SAFE"},
+	torrentDirKey:      {"AO_TORRENT_DIR", "AUDIOBOOK_ORGANIZER_TORRENT_DIR"},
+	authorLookupKey:    {"AO_AUTHOR_LOOKUP", "AUDIOBOOK_ORGANIZER_AUTHOR_LOOKUP"},
+	applyLookupKey:     {"AO_APPLY_AUTHOR_LOOKUP", "AUDIOBOOK_ORGANIZER_APPLY_AUTHOR_LOOKUP"},
+	authorAuthorityKey: {"AO_AUTHOR_AUTHORITY", "AUDIOBOOK_ORGANIZER_AUTHOR_AUTHORITY"},
+	authorAliasKey:     {"AO_AUTHOR_ALIAS", "AUDIOBOOK_ORGANIZER_AUTHOR_ALIAS"},
+	writeIdentsKey:     {"AO_WRITE_IDENTIFIERS", "AUDIOBOOK_ORGANIZER_WRITE_IDENTIFIERS"},
+	provenanceKey:      {"AO_KEEP_PROVENANCE", "AUDIOBOOK_ORGANIZER_KEEP_PROVENANCE"},
+	seriesReadmeKey:    {"AO_SERIES_README", "AUDIOBOOK_ORGANIZER_SERIES_README"},
+	ebooksKey:          {"AO_EBOOKS", "AUDIOBOOK_ORGANIZER_EBOOKS"},
+	trackMapKey:        {"AO_TRACK_MAP", "AUDIOBOOK_ORGANIZER_TRACK_MAP"},
+	minDepthKey:        {"AO_MIN_DEPTH", "AUDIOBOOK_ORGANIZER_MIN_DEPTH"},
+	maxDepthKey:        {"AO_MAX_DEPTH", "AUDIOBOOK_ORGANIZER_MAX_DEPTH"},
+	fileLinesKey:       {"AO_FILE_LINES", "AUDIOBOOK_ORGANIZER_FILE_LINES"},
+	progressEveryKey:   {"AO_PROGRESS_INTERVAL", "AUDIOBOOK_ORGANIZER_PROGRESS_INTERVAL"},
+	detailLogKey:       {"AO_DETAIL_LOG", "AUDIOBOOK_ORGANIZER_DETAIL_LOG"},
+	noNetworkKey:       {"AO_NO_NETWORK", "AUDIOBOOK_ORGANIZER_NO_NETWORK"},
+	strictKey:          {"AO_STRICT", "AUDIOBOOK_ORGANIZER_STRICT"},
+	hiddenFilesKey:     {"AO_HIDDEN_FILES", "AUDIOBOOK_ORGANIZER_HIDDEN_FILES"},
+	trackTitlesKey:     {"AO_TRACK_TITLES", "AUDIOBOOK_ORGANIZER_TRACK_TITLES"},
+	mergeDiscsKey:      {"AO_MERGE_DISCS", "AUDIOBOOK_ORGANIZER_MERGE_DISCS"},
+	continuousKey:      {"AO_CONTINUOUS_TRACKS", "AUDIOBOOK_ORGANIZER_CONTINUOUS_TRACKS"},
+	continuousTagsKey:  {"AO_CONTINUOUS_TRACK_TAGS", "AUDIOBOOK_ORGANIZER_CONTINUOUS_TRACK_TAGS"},
+	allowProtectedKey:  {"AO_ALLOW_PROTECTED", "AUDIOBOOK_ORGANIZER_ALLOW_PROTECTED"},
+	summaryKey:         {"AO_SUMMARY", "AUDIOBOOK_ORGANIZER_SUMMARY"},
+	orderKey:           {"AO_ORDER", "AUDIOBOOK_ORGANIZER_ORDER"},
+	newestFirstKey:     {"AO_NEWEST_FIRST", "AUDIOBOOK_ORGANIZER_NEWEST_FIRST"},
+	oldestFirstKey:     {"AO_OLDEST_FIRST", "AUDIOBOOK_ORGANIZER_OLDEST_FIRST"},
+	limitKey:           {"AO_LIMIT", "AUDIOBOOK_ORGANIZER_LIMIT"},
+	yesIKnowKey:        {"AO_YES_I_KNOW", "AUDIOBOOK_ORGANIZER_YES_I_KNOW"},
+	waitKey:            {"AO_WAIT", "AUDIOBOOK_ORGANIZER_WAIT"},
+	forceUnlockKey:     {"AO_FORCE_UNLOCK", "AUDIOBOOK_ORGANIZER_FORCE_UNLOCK"},
+	profileReportKey:   {"AO_PROFILE_REPORT", "AUDIOBOOK_ORGANIZER_PROFILE_REPORT"},
+	formatKey:          {"AO_FORMAT", "AUDIOBOOK_ORGANIZER_FORMAT"},
 
 	// Field mapping environment variables
-	titleFieldKey:   {"AO_TITLE_FIELD", "AUDIOBOOK_ORGANIZER_TITLE_FIELD"},
-	seriesFieldKey:  {"AO_SERIES_FIELD", "AUDIOBOOK_ORGANIZER_SERIES_FIELD"},
-	authorFieldsKey: {"AO_AUTHOR_FIELDS", "AUDIOBOOK_ORGANIZER_AUTHOR_FIELDS"},
-	trackFieldKey:   {"AO_TRACK_FIELD", "AUDIOBOOK_ORGANIZER_TRACK_FIELD"},
-	discFieldKey:    {"AO_DISC_FIELD", "AUDIOBOOK_ORGANIZER_DISC_FIELD"},
+	titleFieldKey:      {"AO_TITLE_FIELD", "AUDIOBOOK_ORGANIZER_TITLE_FIELD"},
+	seriesFieldKey:     {"AO_SERIES_FIELD", "AUDIOBOOK_ORGANIZER_SERIES_FIELD"},
+	authorFieldsKey:    {"AO_AUTHOR_FIELDS", "AUDIOBOOK_ORGANIZER_AUTHOR_FIELDS"},
+	trackFieldKey:      {"AO_TRACK_FIELD", "AUDIOBOOK_ORGANIZER_TRACK_FIELD"},
+	discFieldKey:       {"AO_DISC_FIELD", "AUDIOBOOK_ORGANIZER_DISC_FIELD"},
+	trackTitleFieldKey: {"AO_TRACK_TITLE_FIELD", "AUDIOBOOK_ORGANIZER_TRACK_TITLE_FIELD"},
 
 	// Rename command environment variables
 	"rename-template":      {"AO_RENAME_TEMPLATE", "AUDIOBOOK_ORGANIZER_RENAME_TEMPLATE"},
@@ -121,6 +250,11 @@ var rootCmd = &cobra.Command{
 			viper.Set("output", viper.GetString("out"))
 		}
 
+		// Ebook libraries are organized file by file
+		if viper.GetBool(ebooksKey) {
+			viper.Set("flat", true)
+		}
+
 		// If flat mode is enabled, automatically enable embedded metadata
 		if viper.GetBool("flat") {
 			viper.Set(useEmbeddedMetaKey, true)
@@ -129,7 +263,7 @@ var rootCmd = &cobra.Command{
 			}
 		}
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get the final input directory (either from --dir or --input)
 		inputDir := viper.GetString("dir")
 		if inputDir == "" {
@@ -148,65 +282,428 @@ var rootCmd = &cobra.Command{
 			authorFieldsList = strings.Split(af, ",")
 		}
 
-		org, err := organizer.NewOrganizer(
-			&organizer.OrganizerConfig{
-				BaseDir:             inputDir,
-				OutputDir:           outputDir,
-				ReplaceSpace:        viper.GetString("replace_space"),
-				Verbose:             viper.GetBool("verbose"),
-				DryRun:              viper.GetBool(dryRunKey),
-				Undo:                viper.GetBool("undo"),
-				Prompt:              viper.GetBool("prompt"),
-				RemoveEmpty:         viper.GetBool(removeEmptyKey),
-				UseEmbeddedMetadata: viper.GetBool(useEmbeddedMetaKey),
-				Flat:                viper.GetBool("flat"),
-				SkipErrors:          viper.GetBool("skip-errors"),
-				Layout:              viper.GetString("layout"),
-				LayoutTemplate:      viper.GetString("layout-template"),
-				FieldMapping: organizer.FieldMapping{
-					TitleField:   viper.GetString(titleFieldKey),
-					SeriesField:  viper.GetString(seriesFieldKey),
-					AuthorFields: authorFieldsList,
-					TrackField:   viper.GetString(trackFieldKey),
-					DiscField:    viper.GetString(discFieldKey),
-				},
+		dryRun := viper.GetBool(dryRunKey)
+		reportContext := organizer.HTMLReportContext{
+			InputDir:  inputDir,
+			OutputDir: outputDir,
+			Locale:    viper.GetString(localeKey),
+		}
+		fullScan := viper.GetBool(fullScanKey)
+
+		// Comparing against a previous run only computes the plan
+		var previousEntries []organizer.LogEntry
+		if diffLogPath := viper.GetString(diffLogKey); diffLogPath != "" {
+			entries, err := organizer.ReadLogEntries(diffLogPath)
+			if err != nil {
+				organizer.PrintRed("Configuration error: %v", err)
+				writeRunReport(organizer.NewRunReport(organizer.Summary{}, true, err), reportContext)
+				os.Exit(ExitFatal)
+			}
+			previousEntries = entries
+			dryRun = true
+			// The comparison needs every book, not just the ones that changed
+			fullScan = true
+		}
+
+		// A selection file limits the run to the books chosen in the TUI
+		var allowedPaths []string
+		if selectionPath := viper.GetString(selectionKey); selectionPath != "" {
+			paths, err := organizer.ReadSelectionFile(selectionPath)
+			if err != nil {
+				organizer.PrintRed("Configuration error: %v", err)
+				writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+				os.Exit(ExitFatal)
+			}
+			allowedPaths = paths
+		}
+
+		filter, err := bookFilterFromFlags()
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		if _, err := emailConfigFromFlags(); err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		hiddenFiles, err := organizer.ParseHiddenFilePolicy(viper.GetString(hiddenFilesKey))
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		merge, err := organizer.ParseMergePolicy(viper.GetString(mergeKey))
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		summaryMode, err := organizer.ParseSummaryMode(viper.GetString(summaryKey))
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		order, err := bookOrderFromFlags()
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		singleFileLayout, err := organizer.ParseSingleFileLayout(viper.GetString(singleFileKey))
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		authorAliases, err := organizer.ParseAuthorAliases(stringListValue(authorAliasKey))
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		var detailLog io.Writer
+		if path := viper.GetString(detailLogKey); path != "" {
+			file, err := organizer.OpenDetailLog(path)
+			if err != nil {
+				organizer.PrintRed("Configuration error: %v", err)
+				writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+				os.Exit(ExitFatal)
+			}
+			defer file.Close()
+			detailLog = file
+		}
+		printPlan, err := planOutput(dryRun)
+		if err != nil {
+			organizer.PrintRed("Configuration error: %v", err)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		if printPlan {
+			// The plan is the only output, so decorations would end up in diffs
+			organizer.SetQuietMode(true)
+		}
+
+		config := &organizer.OrganizerConfig{
+			BaseDir:             inputDir,
+			OutputDir:           outputDir,
+			ReplaceSpace:        viper.GetString("replace_space"),
+			Verbose:             viper.GetBool("verbose") && !organizer.QuietMode,
+			DryRun:              dryRun,
+			Undo:                viper.GetBool("undo"),
+			Prompt:              viper.GetBool("prompt"),
+			RemoveEmpty:         viper.GetBool(removeEmptyKey),
+			UseEmbeddedMetadata: viper.GetBool(useEmbeddedMetaKey),
+			StrictJSON:          viper.GetBool(strictJSONKey),
+			Flat:                viper.GetBool("flat"),
+			SkipErrors:          viper.GetBool("skip-errors"),
+			Layout:              viper.GetString("layout"),
+			LayoutTemplate:      viper.GetString("layout-template"),
+			Casing:              viper.GetString(casingKey),
+			Subtitle:            viper.GetString(subtitleKey),
+			SingleFileLayout:    singleFileLayout,
+			BookFolder:          viper.GetBool(bookFolderKey),
+			FetchCovers:         viper.GetBool(fetchCoversKey),
+			MusicBrainz:         viper.GetBool(musicBrainzKey),
+			MusicBrainzURL:      viper.GetString(musicBrainzURLKey),
+			CarryUnsupported:    viper.GetInt64(carryKey) * 1024,
+			Merge:               merge,
+			StripTitlePrefix:    viper.GetBool(stripTitleKey),
+			TrashDir:            viper.GetString(trashDirKey),
+			LogPath:             viper.GetString(logPathKey),
+			LogChecksums:        viper.GetBool(logChecksumsKey),
+			MinFileAge:          viper.GetDuration(minFileAgeKey),
+			SizeSettle:          viper.GetDuration(sizeSettleKey),
+			Retries:             viper.GetInt(retriesKey),
+			RetryDelay:          viper.GetDuration(retryDelayKey),
+			LockWait:            viper.GetDuration(waitKey),
+			ForceUnlock:         viper.GetBool(forceUnlockKey),
+			ProfileReport:       viper.GetBool(profileReportKey),
+			MinConfidence:       viper.GetFloat64(minConfidenceKey),
+			SeedSafe:            viper.GetBool(seedSafeKey),
+			TorrentDirs:         stringListValue(torrentDirKey),
+			SFTPIdentityFile:    viper.GetString(sftpIdentityKey),
+			SFTPKnownHostsFile:  viper.GetString(sftpKnownHostsKey),
+			FullScan:            fullScan,
+			CheckAuthors:        viper.GetBool(checkAuthorsKey),
+			AuthorLookup:        viper.GetBool(authorLookupKey) || viper.GetBool(applyLookupKey),
+			ApplyAuthorLookup:   viper.GetBool(applyLookupKey),
+			AuthorAliases:       authorAliases,
+			WriteIdentifiers:    viper.GetBool(writeIdentsKey),
+			KeepProvenance:      viper.GetBool(provenanceKey),
+			SeriesReadme:        viper.GetBool(seriesReadmeKey),
+			Ebooks:              viper.GetBool(ebooksKey),
+			MinDepth:            viper.GetInt(minDepthKey),
+			MaxDepth:            viper.GetInt(maxDepthKey),
+			FileLines:           viper.GetInt(fileLinesKey),
+			ProgressInterval:    viper.GetDuration(progressEveryKey),
+			DetailLog:           detailLog,
+			AuthorAuthorityURL:  viper.GetString(authorAuthorityKey),
+			NoNetwork:           viper.GetBool(noNetworkKey),
+			Strict:              viper.GetBool(strictKey),
+			HiddenFiles:         hiddenFiles,
+			TrackTitles:         viper.GetBool(trackTitlesKey),
+			MergeDiscs:          viper.GetBool(mergeDiscsKey),
+			ContinuousTracks:    viper.GetBool(continuousKey),
+			ContinuousTrackTags: viper.GetBool(continuousTagsKey),
+			AllowProtectedDirs:  viper.GetBool(allowProtectedKey),
+			Extensions:          extensionPolicy(),
+			Locale:              viper.GetString(localeKey),
+			Language:            language(),
+			Order:               order,
+			Limit:               viper.GetInt(limitKey),
+			Summary:             summaryMode,
+			AllowedSourcePaths:  allowedPaths,
+			Filter:              filter,
+			FieldMapping: organizer.FieldMapping{
+				TitleField:      fieldChainValue(titleFieldKey),
+				SeriesField:     fieldChainValue(seriesFieldKey),
+				AuthorFields:    authorFieldsList,
+				TrackField:      fieldChainValue(trackFieldKey),
+				DiscField:       viper.GetString(discFieldKey),
+				TrackTitleField: fieldChainValue(trackTitleFieldKey),
 			},
-		)
+		}
+		if !viper.GetBool(yesIKnowKey) {
+			config.ConfirmPlan = confirmPlan
+		}
+		org, err := organizer.NewOrganizer(config)
 		if err != nil {
 			organizer.PrintRed("Configuration error: %v", err)
-			os.Exit(1)
+			writeRunReport(organizer.NewRunReport(organizer.Summary{}, dryRun, err), reportContext)
+			os.Exit(ExitFatal)
 		}
 
 		if err := org.Execute(); err != nil {
-			color.Red("❌ Error: %v", err)
-			os.Exit(1)
+			organizer.PrintRed("❌ Error: %v", err)
+			writeRunReport(organizer.NewRunReport(org.GetSummary(), dryRun, err), reportContext)
+			os.Exit(ExitFatal)
+		}
+		if printPlan {
+			if err := org.WriteMovePlan(os.Stdout); err != nil {
+				organizer.PrintRed("❌ Error writing plan: %v", err)
+				os.Exit(ExitFatal)
+			}
 		}
 
 		// Print log file location if not in dry-run mode
-		if !viper.GetBool(dryRunKey) {
-			logPath := org.GetLogPath()
-			color.Cyan("\n📝 Log file location: %s", logPath)
-			color.Cyan("To undo these changes, run:")
-			color.White("  audiobook-organizer --input=%s --undo", inputDir)
-			if outputDir != "" {
-				color.White("  audiobook-organizer --input=%s --output=%s --undo",
-					inputDir, outputDir)
+		if !dryRun {
+			reportContext.UndoCommands = undoCommands(inputDir, outputDir, org.GetLogPath())
+			if !organizer.QuietMode {
+				color.Cyan("\n📝 Log file location: %s", org.GetLogPath())
+				color.Cyan("To undo these changes, run:")
+				for _, command := range reportContext.UndoCommands {
+					color.White("  %s", command)
+				}
 			}
 		}
+
+		report := organizer.NewRunReport(org.GetSummary(), dryRun, nil)
+		if previousEntries != nil {
+			diff := organizer.DiffMovePlan(previousEntries, org.GetSummary().Moves)
+			organizer.PrintMovePlanDiff(diff, viper.GetBool("verbose"))
+			report.PlanDiff = &diff
+		}
+		if viper.GetBool("undo") && report.Status == organizer.RunStatusNothingToDo {
+			// Undo restores from the log and does not record moves in the summary.
+			report.Status = organizer.RunStatusOK
+		}
+		writeRunReport(report, reportContext)
+		return exitWith(cmd, exitCodeForStatus(report.Status))
 	},
 }
 
-func Execute() error {
+// planOutput reports whether --format asks for the move plan instead of the usual
+// output. The plan lists what a dry run would do, so it requires --dry-run.
+func planOutput(dryRun bool) (bool, error) {
+	switch format := viper.GetString(formatKey); format {
+	case "", "text":
+		return false, nil
+	case "plan":
+		if !dryRun {
+			return false, fmt.Errorf("--format=plan requires --dry-run")
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid --format %q (use text or plan)", format)
+	}
+}
+
+// confirmPlan prints the warnings of a plan that looks destructive and asks before
+// the run goes on. Without a terminal to ask on, the run stops unless --yes-i-know
+// is given.
+func confirmPlan(warnings []organizer.PlanWarning) error {
+	organizer.PrintPlanWarnings(warnings)
+	if !isCharDevice(os.Stdin) {
+		return fmt.Errorf("refusing to run a plan that looks destructive; review it with --dry-run and pass --%s to run it anyway", yesIKnowKey)
+	}
+	fmt.Print(organizer.RenderPromptIcon("\n❓ Run this plan anyway? [y/N] "))
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer := strings.ToLower(strings.TrimSpace(response)); answer != "y" && answer != "yes" {
+		return errors.New("run cancelled; nothing was moved")
+	}
+	return nil
+}
+
+// bookOrderFromFlags reads --order and its --newest-first and --oldest-first
+// shorthands, and checks --limit
+func bookOrderFromFlags() (organizer.BookOrder, error) {
+	if viper.GetInt(limitKey) < 0 {
+		return "", fmt.Errorf("--%s must not be negative", limitKey)
+	}
+	order, err := organizer.ParseBookOrder(viper.GetString(orderKey))
+	if err != nil {
+		return "", err
+	}
+	for key, shorthand := range map[string]organizer.BookOrder{
+		newestFirstKey: organizer.OrderNewestFirst,
+		oldestFirstKey: organizer.OrderOldestFirst,
+	} {
+		if !viper.GetBool(key) || order == shorthand {
+			continue
+		}
+		if order != organizer.OrderScan {
+			return "", fmt.Errorf("--%s conflicts with --order=%s", key, order)
+		}
+		order = shorthand
+	}
+	return order, nil
+}
+
+// bookFilterFromFlags builds the --only-* filters of an organize run
+func bookFilterFromFlags() (organizer.BookFilter, error) {
+	filter := organizer.BookFilter{
+		Paths:   stringListValue(onlyPathKey),
+		Authors: stringListValue(onlyAuthorKey),
+	}
+	if pattern := viper.GetString(onlyTitleKey); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --%s pattern: %v", onlyTitleKey, err)
+		}
+		filter.TitlePattern = re
+	}
+	return filter, nil
+}
+
+// stringListValue reads a repeatable flag. Environment variables and config files may
+// give the list as one comma-separated string, so names with spaces stay whole.
+func stringListValue(key string) []string {
+	value, ok := viper.Get(key).(string)
+	if !ok {
+		return viper.GetStringSlice(key)
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// extensionPolicy returns the --extension table, which initConfig has already checked
+func extensionPolicy() organizer.ExtensionPolicy {
+	policy, _ := organizer.ParseExtensionActions(stringListValue(extensionKey))
+	return policy
+}
+
+// fieldChainValue returns a title, series, or track mapping with its fallbacks, given
+// as a comma-separated flag or a list in the config file
+func fieldChainValue(key string) string {
+	return strings.Join(stringListValue(key), ",")
+}
+
+// undoCommands returns the commands that restore a run from its undo log
+func undoCommands(inputDir, outputDir, logPath string) []string {
+	if viper.GetString(logPathKey) != "" {
+		return []string{fmt.Sprintf("audiobook-organizer --input=%s --log-path=%s --undo", inputDir, logPath)}
+	}
+	commands := []string{fmt.Sprintf("audiobook-organizer --input=%s --undo", inputDir)}
+	if outputDir != "" {
+		commands = append(commands, fmt.Sprintf("audiobook-organizer --input=%s --output=%s --undo", inputDir, outputDir))
+	}
+	return commands
+}
+
+// emailConfigFromFlags reads the email section of the config file when --email-summary
+// is set, and returns nil when it is not
+func emailConfigFromFlags() (*organizer.EmailConfig, error) {
+	on := viper.GetString(emailSummaryKey)
+	if on == "" {
+		return nil, nil
+	}
+	config := &organizer.EmailConfig{
+		Host:     viper.GetString("email.host"),
+		Port:     viper.GetInt("email.port"),
+		Username: viper.GetString("email.username"),
+		Password: viper.GetString("email.password"),
+		From:     viper.GetString("email.from"),
+		To:       stringListValue("email.to"),
+		On:       on,
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// writeRunReport writes the JSON run report when --json-report is set, the track map
+// when --track-map is set, and the HTML report when --report-html is set, and emails
+// the run when --email-summary asks for it.
+func writeRunReport(report organizer.RunReport, context organizer.HTMLReportContext) {
+	if path := viper.GetString(jsonReportKey); path != "" {
+		if err := organizer.WriteRunReport(path, report); err != nil {
+			organizer.PrintRed("❌ Error writing JSON report: %v", err)
+		}
+	}
+	// A run that failed before moving anything keeps the previous track map
+	if path := viper.GetString(trackMapKey); path != "" && report.Status != organizer.RunStatusFatal {
+		if err := organizer.WriteTrackMaps(path, report.TrackMaps); err != nil {
+			organizer.PrintRed("❌ Error writing track map: %v", err)
+		}
+	}
+	if path := viper.GetString(htmlReportKey); path != "" {
+		if err := organizer.WriteHTMLReport(path, report, context); err != nil {
+			organizer.PrintRed("❌ Error writing HTML report: %v", err)
+		}
+	}
+	// An invalid email configuration was already reported as a configuration error
+	if config, err := emailConfigFromFlags(); err == nil && config != nil && config.ShouldSend(report) {
+		if err := organizer.SendReportEmail(*config, report, context); err != nil {
+			organizer.PrintRed("❌ Error emailing run report: %v", err)
+		}
+	}
+}
+
+// Execute runs the command line and returns the exit code the process should finish
+// with. The error is nil when a command only reported a non-zero exit code, such as
+// a run that completed with errors.
+func Execute() (int, error) {
 	if shouldPrintStartupBanner(os.Args[1:]) {
 		color.Cyan("🎧 Audiobook Organizer")
 		color.Cyan("=====================")
 	}
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	code := exitCodeFor(err)
+	if errors.As(err, new(exitCodeError)) {
+		err = nil
+	}
+	return code, err
 }
 
 func shouldPrintStartupBanner(args []string) bool {
-	for _, arg := range args {
-		if arg == "metadata" || arg == "layout-template" {
+	for i, arg := range args {
+		if arg == "metadata" || arg == "layout-template" || arg == "fieldmap" || arg == "preview" || arg == "doctor" {
+			return false
+		}
+		if arg == "-q" || arg == "--quiet" || arg == "--quiet=true" {
+			return false
+		}
+		// A move plan is meant to be diffed, so nothing else may be printed
+		if arg == "--format=plan" || (arg == "--format" && i+1 < len(args) && args[i+1] == "plan") {
 			return false
 		}
 	}
@@ -251,46 +748,190 @@ func init() {
 
 	// Persistent flags (available to all subcommands)
 	rootCmd.PersistentFlags().
-		StringVar(&inputDir, "dir", "", "Base directory to scan (alias for --input)")
+		String("dir", "", "Base directory to scan (alias for --input)")
+	rootCmd.PersistentFlags().
+		String("input", "", "Base directory to scan (alias for --dir)")
+	rootCmd.PersistentFlags().
+		String("out", "", "Output directory, sftp://user@host/path URL, or rclone:remote:path (alias for --output)")
+	rootCmd.PersistentFlags().
+		String("output", "", "Output directory, sftp://user@host/path URL, or rclone:remote:path (alias for --out)")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	rootCmd.PersistentFlags().
+		Bool(dryRunKey, false, "Show what would happen without making changes")
+	rootCmd.PersistentFlags().
+		Bool(useEmbeddedMetaKey, false, "Use metadata embedded in EPUB files if metadata.json is not found")
+	rootCmd.PersistentFlags().
+		Bool("flat", false, "Process files in a flat directory structure (automatically enables --use-embedded-metadata)")
+	rootCmd.Flags().
+		Bool(ebooksKey, false, "Organize an ebook library: every EPUB, MOBI, AZW3, FB2, or PDF file is a book and audio files are left alone (implies --flat)")
+	rootCmd.PersistentFlags().
+		Bool("skip-errors", false, "Skip files with missing/invalid metadata instead of stopping")
+	rootCmd.PersistentFlags().
+		String(trashDirKey, "", "Move files that would be overwritten or deleted into timestamped folders here")
+	rootCmd.PersistentFlags().
+		String(sftpIdentityKey, "", "Private key for sftp:// output (default: ssh-agent and ~/.ssh keys)")
+	rootCmd.PersistentFlags().
+		String(sftpKnownHostsKey, "", "known_hosts file used to verify sftp:// hosts (default: ~/.ssh/known_hosts)")
+	rootCmd.PersistentFlags().
+		String(logPathKey, "", "Undo log file (default: "+organizer.LogFileName+" in the output directory, or the XDG state directory when it is read-only)")
 	rootCmd.PersistentFlags().
-		StringVar(&inputDir, "input", "", "Base directory to scan (alias for --dir)")
+		Bool(logChecksumsKey, false, "Record a SHA-256 of every moved file in the undo log so verify-library --checksums can compare contents")
 	rootCmd.PersistentFlags().
-		StringVar(&outputDir, "out", "", "Output directory (alias for --output)")
+		Duration(minFileAgeKey, 0, "Defer books with a file modified more recently than this (e.g. 2m) to a later run")
 	rootCmd.PersistentFlags().
-		StringVar(&outputDir, "output", "", "Output directory (alias for --out)")
-	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output")
+		Duration(sizeSettleKey, 0, "Wait this long (e.g. 5s) and defer recently modified books whose size changed")
 	rootCmd.PersistentFlags().
-		BoolVar(&dryRun, dryRunKey, false, "Show what would happen without making changes")
+		Int(retriesKey, organizer.DefaultRetries, "Retry a file operation this many times when it fails with a transient error like EIO or ESTALE (0 = never)")
 	rootCmd.PersistentFlags().
-		BoolVar(&useEmbeddedMetadata, useEmbeddedMetaKey, false, "Use metadata embedded in EPUB files if metadata.json is not found")
+		Duration(retryDelayKey, organizer.DefaultRetryDelay, "Wait before the first retry, doubled for each later one")
 	rootCmd.PersistentFlags().
-		BoolVar(&flat, "flat", false, "Process files in a flat directory structure (automatically enables --use-embedded-metadata)")
+		Float64(minConfidenceKey, 0, "Hold back books whose embedded or file metadata scores below this (0-1, e.g. 0.5) instead of organizing them")
 	rootCmd.PersistentFlags().
-		BoolVar(&skipErrors, "skip-errors", false, "Skip files with missing/invalid metadata instead of stopping")
+		Bool(seedSafeKey, false, "Hardlink (or copy) books into the output instead of moving them, so torrents keep seeding")
+	rootCmd.PersistentFlags().
+		StringSlice(torrentDirKey, nil, "Torrent client directory with .torrent/.fastresume files; only books they reference are linked instead of moved (repeatable)")
+	rootCmd.PersistentFlags().
+		BoolP(quietKey, "q", false, "Machine mode: suppress decorative output and emoji, printing only errors")
+	rootCmd.PersistentFlags().
+		Bool(strictJSONKey, false, "Parse metadata.json as standard JSON only, rejecting byte order marks, comments, trailing commas, and single quotes")
+	rootCmd.PersistentFlags().
+		Bool(noColorKey, false, "Print without ANSI colors (also set by NO_COLOR, and automatic when stdout is not a terminal)")
+	rootCmd.PersistentFlags().
+		Bool(forceColorKey, false, "Print ANSI colors even when stdout is not a terminal or NO_COLOR is set")
+	rootCmd.PersistentFlags().
+		String(localeKey, "", "Locale for sorting names and {author_initial} folders, e.g. sv or de-AT (default: root collation order)")
+	rootCmd.PersistentFlags().
+		String(langKey, "", "Language of the summary, TUI, and web UI text, e.g. de (default: from LC_ALL, LC_MESSAGES, or LANG)")
+	rootCmd.PersistentFlags().
+		StringSlice(extensionKey, nil, "Handle an extension as organize, companion, ignore, or delete, as \".mp4=organize\" (repeatable)")
+	rootCmd.PersistentFlags().
+		Int(minDepthKey, 0, "Only take books at least this many levels below the input; the input's own entries are level 1")
+	rootCmd.PersistentFlags().
+		Int(maxDepthKey, 0, "Don't scan more than this many levels below the input, e.g. 2 for Author/Book (0 = no limit)")
 
 	// Local flags (only for root command)
-	rootCmd.Flags().StringVar(&replaceSpace, "replace_space", "", "Character to replace spaces")
-	rootCmd.Flags().BoolVar(&undo, "undo", false, "Restore files to their original locations")
+	rootCmd.Flags().String("replace_space", "", "Character to replace spaces")
+	rootCmd.Flags().Bool("undo", false, "Restore files to their original locations")
+	rootCmd.Flags().
+		Bool("prompt", false, "Prompt for confirmation before moving each book")
+	rootCmd.Flags().
+		Bool(yesIKnowKey, false, "Run even when the plan looks destructive, without asking (see \"Plan Warnings\" in the docs)")
+	rootCmd.Flags().
+		Duration(waitKey, 0, "Wait up to this long (e.g. 10m) for another run to release the library lock instead of stopping. Locks of finished runs on this host are removed; locks from another host are never stale")
+	rootCmd.Flags().
+		Bool(forceUnlockKey, false, "Remove the library lock left by another run before starting. Locks from another host are never treated as stale, so they need this")
+	rootCmd.Flags().
+		Bool(removeEmptyKey, false, "Remove empty directories after moving files")
+	rootCmd.Flags().
+		StringP("layout", "l", "author-series-title", "Directory structure layout:\n  - author-series-title:        Author/Series/Title/ (default)\n  - author-series-title-number: Author/Series/#1 - Title/ (include series number in title)\n  - author-title:               Author/Title/ (ignore series)\n  - author-only:                Author/ (flatten all books)")
+	rootCmd.Flags().
+		String(jsonReportKey, "", "Write a JSON run report to this path (\"-\" for stdout)")
+	rootCmd.Flags().
+		String(trackMapKey, "", "Write a JSON map of each book's renamed files (old name → new name) to this path, for carrying playback progress over")
+	rootCmd.Flags().
+		String(htmlReportKey, "", "Write a self-contained HTML run report to this path")
+	rootCmd.Flags().
+		Bool(profileReportKey, false, "Time metadata reading, planning, and moving per book and add the breakdown to the --json-report")
+	rootCmd.Flags().
+		String(emailSummaryKey, "", "Email the run summary using the config file's email section: always, or failure for failed runs only")
+	rootCmd.Flags().
+		String(diffLogKey, "", "Compare the computed plan with a previous .abook-org.log (implies --dry-run)")
+	rootCmd.Flags().
+		String("layout-template", "", "Custom directory layout template overriding --layout; see \"audiobook-organizer layout-template\"")
 	rootCmd.Flags().
-		BoolVar(&prompt, "prompt", false, "Prompt for confirmation before moving each book")
+		String(casingKey, organizer.CasingPreserve, "Casing of folder names: preserve, title (The Way of Kings), or sentence (The way of kings)")
 	rootCmd.Flags().
-		BoolVar(&removeEmpty, removeEmptyKey, false, "Remove empty directories after moving files")
+		String(subtitleKey, organizer.SubtitleKeep, "How titles carry their subtitle: keep (as tagged), colon (Title: Subtitle), dash (Title - Subtitle), or drop")
 	rootCmd.Flags().
-		StringVarP(&layout, "layout", "l", "author-series-title", "Directory structure layout:\n  - author-series-title:        Author/Series/Title/ (default)\n  - author-series-title-number: Author/Series/#1 - Title/ (include series number in title)\n  - author-title:               Author/Title/ (ignore series)\n  - author-only:                Author/ (flatten all books)")
+		String(singleFileKey, string(organizer.SingleFileFolder), "Where books of one audio file go: folder (Author/Title/Title.m4b) or file (Author/Title.m4b)")
 	rootCmd.Flags().
-		StringVar(&layoutTemplate, "layout-template", "", "Custom directory layout template overriding --layout; see \"audiobook-organizer layout-template\"")
+		Bool(bookFolderKey, false, "Give every book a folder of its own, also in --flat mode and with layouts like author-only that have none")
+	rootCmd.Flags().
+		Int(carryKey, 0, "In --flat mode, move files of unsupported types up to this many KiB, like a .txt description or .jpg cover, into the folder of the book beside them (0 = leave them in place)")
+	rootCmd.Flags().
+		Bool(fetchCoversKey, false, "Download cover art from Open Library or Audible (by ISBN, ASIN, or title and author) for books with no embedded or folder cover")
+	rootCmd.Flags().
+		Bool(musicBrainzKey, false, "Fill in missing series, year, publisher, and ISBN from the book's MusicBrainz audiobook release, matched by title, author, and playing time")
+	rootCmd.Flags().
+		String(musicBrainzURLKey, organizer.DefaultMusicBrainzURL, "Base URL of the MusicBrainz web service used by --musicbrainz")
+	rootCmd.Flags().
+		Bool(stripTitleKey, false, "Drop a leading author or series name and number from title folders when they repeat the other tags (\"Mistborn 01 - The Final Empire\" -> \"The Final Empire\")")
+	rootCmd.Flags().
+		Bool(fullScanKey, false, "Read every directory instead of skipping those unchanged since the last run")
+	rootCmd.Flags().
+		Bool(checkAuthorsKey, false, "Warn about titles found under several similar author spellings and suggest merges")
+	rootCmd.Flags().
+		Bool(authorLookupKey, false, "Look authors up on OpenLibrary and report canonical names, pseudonyms, and co-authors")
+	rootCmd.Flags().
+		Bool(applyLookupKey, false, "Use the canonical author names found by --author-lookup when building paths (pseudonyms are only reported)")
+	rootCmd.Flags().
+		String(authorAuthorityKey, organizer.DefaultAuthorAuthorityURL, "Base URL of the OpenLibrary-compatible author search used by --author-lookup")
+	rootCmd.Flags().
+		StringSlice(authorAliasKey, nil, "Shelve a pen name under another author folder, as \"Pen Name=Author\" (repeatable)")
+	rootCmd.Flags().
+		Bool(writeIdentsKey, false, "Write identifiers.json with the book's ISBN and ASIN next to each organized book")
+	rootCmd.Flags().
+		Bool(provenanceKey, false, "Write the original folder name and leftover .txt/.nfo files to "+organizer.ProvenanceFileName+" in each organized book")
+	rootCmd.Flags().
+		Bool(seriesReadmeKey, false, "Write "+organizer.SeriesReadmeFileName+" listing the books, narrators, and durations into each series folder a run adds books to")
+	rootCmd.Flags().
+		Int(fileLinesKey, 0, "Print at most this many per-file lines for each book and count the rest (0 prints every line)")
+	rootCmd.Flags().
+		Duration(progressEveryKey, organizer.DefaultProgressInterval, "How often a book with more files than --file-lines prints how many it has moved")
+	rootCmd.Flags().
+		String(detailLogKey, "", "Append every per-file line, without colors and including those coalesced on screen, to this file")
+	rootCmd.Flags().
+		Bool(noNetworkKey, false, "Never use the network; --author-lookup, --fetch-covers, and --musicbrainz answer from their local caches only")
+	rootCmd.Flags().
+		Bool(strictKey, false, "Refuse books with a file too large for a FAT32 output instead of warning")
+	rootCmd.Flags().
+		Bool(trackTitlesKey, false, "Name the files of multi-file books \"NN - <track title>\" after each file's own title tag")
+	rootCmd.Flags().
+		Bool(mergeDiscsKey, false, "Merge sibling \"Book CD1\", \"Book CD2\" folders whose tags match into one book with disc-numbered tracks")
+	rootCmd.Flags().
+		Bool(continuousKey, false, "With --merge-discs, number tracks on across discs (disc 2 track 1 becomes \"13 - \") instead of \"2-01 - \"")
+	rootCmd.Flags().
+		Bool(continuousTagsKey, false, "With --continuous-tracks, also write the new track numbers into the MP3 tags")
+	rootCmd.Flags().
+		Bool(allowProtectedKey, false, "Also organize inside folders managed by Audiobookshelf, Plex, or Calibre, which are skipped by default")
+	rootCmd.Flags().
+		String(summaryKey, string(organizer.SummaryFull), "End-of-run summary: full (every book and move), compact (counts and problems), or errors-only")
+	rootCmd.Flags().
+		String(orderKey, string(organizer.OrderScan), "Order books are moved in: scan (as found), smallest-first, largest-first, alphabetical, newest-first, or oldest-first")
+	rootCmd.Flags().
+		Bool(newestFirstKey, false, "Move the books whose folders changed most recently first (same as --order=newest-first)")
+	rootCmd.Flags().
+		Bool(oldestFirstKey, false, "Move the books whose folders changed longest ago first (same as --order=oldest-first)")
+	rootCmd.Flags().
+		Int(limitKey, 0, "Organize at most this many books per run, in --order; the rest are left for later runs (0 = no limit)")
+	rootCmd.Flags().
+		String(hiddenFilesKey, string(organizer.HiddenFilesSkip), "What to do with .DS_Store, Thumbs.db, and other hidden files in book folders: skip, delete, or move")
+	rootCmd.Flags().
+		String(mergeKey, string(organizer.MergeOverwrite), "How a book moves into a folder that already holds files: overwrite files of the same name, fill in only missing files, replace-smaller existing files, or ask for each differing file")
+	rootCmd.Flags().
+		String(formatKey, "text", "Output format: text, or plan for one sorted \"SRC -> DST\" line per file (requires --dry-run)")
+	rootCmd.Flags().
+		String(selectionKey, "", "Only organize the book paths listed in this file, one per line (as written by the TUI)")
+	rootCmd.Flags().
+		StringSlice(onlyPathKey, nil, "Only organize books at or below this path (repeatable)")
+	rootCmd.Flags().
+		StringSlice(onlyAuthorKey, nil, "Only organize books by this author, ignoring case (repeatable)")
+	rootCmd.Flags().
+		String(onlyTitleKey, "", "Only organize books whose title matches this regular expression")
 
 	// Field mapping flags (persistent for all commands)
 	rootCmd.PersistentFlags().
-		StringVar(&titleField, titleFieldKey, "", "Field to use as title (e.g., 'album', 'title', 'track_title')")
+		String(titleFieldKey, "", "Field to use as title, or comma-separated fallbacks; '=text' is a literal (e.g., 'album', 'album,title')")
+	rootCmd.PersistentFlags().
+		String(seriesFieldKey, "", "Field to use as series, or comma-separated fallbacks; '=text' is a literal (e.g., 'series,mvnm,album,=Standalone')")
 	rootCmd.PersistentFlags().
-		StringVar(&seriesField, seriesFieldKey, "", "Field to use as series (e.g., 'series', 'album')")
+		String(authorFieldsKey, "", "Comma-separated list of fields to try for author (e.g., 'authors,narrators,album_artist,artist')")
 	rootCmd.PersistentFlags().
-		StringVar(&authorFields, authorFieldsKey, "", "Comma-separated list of fields to try for author (e.g., 'authors,narrators,album_artist,artist')")
+		String(trackFieldKey, "", "Field to use for track number, or comma-separated fallbacks (e.g., 'track', 'track_number,trck')")
 	rootCmd.PersistentFlags().
-		StringVar(&trackField, trackFieldKey, "", "Field to use for track number (e.g., 'track', 'track_number', 'trck', 'trk')")
+		String(discFieldKey, "", "Field to use for disc number (e.g., 'disc', 'discnumber', 'disk', 'tpos')")
 	rootCmd.PersistentFlags().
-		StringVar(&discField, discFieldKey, "", "Field to use for disc number (e.g., 'disc', 'discnumber', 'disk', 'tpos')")
+		String(trackTitleFieldKey, "", "Per-file field naming each track for --track-titles, or comma-separated fallbacks (default: the file's title tag)")
 
 	// Bind persistent flags to viper
 	viper.BindPFlag("dir", rootCmd.PersistentFlags().Lookup("dir"))
@@ -302,11 +943,33 @@ func init() {
 	viper.BindPFlag(useEmbeddedMetaKey, rootCmd.PersistentFlags().Lookup(useEmbeddedMetaKey))
 	viper.BindPFlag("flat", rootCmd.PersistentFlags().Lookup("flat"))
 	viper.BindPFlag("skip-errors", rootCmd.PersistentFlags().Lookup("skip-errors"))
+	viper.BindPFlag(quietKey, rootCmd.PersistentFlags().Lookup(quietKey))
+	viper.BindPFlag(strictJSONKey, rootCmd.PersistentFlags().Lookup(strictJSONKey))
+	viper.BindPFlag(noColorKey, rootCmd.PersistentFlags().Lookup(noColorKey))
+	viper.BindPFlag(forceColorKey, rootCmd.PersistentFlags().Lookup(forceColorKey))
+	viper.BindPFlag(localeKey, rootCmd.PersistentFlags().Lookup(localeKey))
+	viper.BindPFlag(langKey, rootCmd.PersistentFlags().Lookup(langKey))
+	viper.BindPFlag(extensionKey, rootCmd.PersistentFlags().Lookup(extensionKey))
+	viper.BindPFlag(minDepthKey, rootCmd.PersistentFlags().Lookup(minDepthKey))
+	viper.BindPFlag(maxDepthKey, rootCmd.PersistentFlags().Lookup(maxDepthKey))
+	viper.BindPFlag(trashDirKey, rootCmd.PersistentFlags().Lookup(trashDirKey))
+	viper.BindPFlag(logPathKey, rootCmd.PersistentFlags().Lookup(logPathKey))
+	viper.BindPFlag(logChecksumsKey, rootCmd.PersistentFlags().Lookup(logChecksumsKey))
+	viper.BindPFlag(minFileAgeKey, rootCmd.PersistentFlags().Lookup(minFileAgeKey))
+	viper.BindPFlag(sizeSettleKey, rootCmd.PersistentFlags().Lookup(sizeSettleKey))
+	viper.BindPFlag(retriesKey, rootCmd.PersistentFlags().Lookup(retriesKey))
+	viper.BindPFlag(retryDelayKey, rootCmd.PersistentFlags().Lookup(retryDelayKey))
+	viper.BindPFlag(minConfidenceKey, rootCmd.PersistentFlags().Lookup(minConfidenceKey))
+	viper.BindPFlag(seedSafeKey, rootCmd.PersistentFlags().Lookup(seedSafeKey))
+	viper.BindPFlag(torrentDirKey, rootCmd.PersistentFlags().Lookup(torrentDirKey))
+	viper.BindPFlag(sftpIdentityKey, rootCmd.PersistentFlags().Lookup(sftpIdentityKey))
+	viper.BindPFlag(sftpKnownHostsKey, rootCmd.PersistentFlags().Lookup(sftpKnownHostsKey))
 	viper.BindPFlag(titleFieldKey, rootCmd.PersistentFlags().Lookup(titleFieldKey))
 	viper.BindPFlag(seriesFieldKey, rootCmd.PersistentFlags().Lookup(seriesFieldKey))
 	viper.BindPFlag(authorFieldsKey, rootCmd.PersistentFlags().Lookup(authorFieldsKey))
 	viper.BindPFlag(trackFieldKey, rootCmd.PersistentFlags().Lookup(trackFieldKey))
 	viper.BindPFlag(discFieldKey, rootCmd.PersistentFlags().Lookup(discFieldKey))
+	viper.BindPFlag(trackTitleFieldKey, rootCmd.PersistentFlags().Lookup(trackTitleFieldKey))
 
 	// Bind local flags to viper
 	viper.BindPFlag("replace_space", rootCmd.Flags().Lookup("replace_space"))
@@ -315,6 +978,56 @@ func init() {
 	viper.BindPFlag(removeEmptyKey, rootCmd.Flags().Lookup(removeEmptyKey))
 	viper.BindPFlag("layout", rootCmd.Flags().Lookup("layout"))
 	viper.BindPFlag("layout-template", rootCmd.Flags().Lookup("layout-template"))
+	viper.BindPFlag(casingKey, rootCmd.Flags().Lookup(casingKey))
+	viper.BindPFlag(stripTitleKey, rootCmd.Flags().Lookup(stripTitleKey))
+	viper.BindPFlag(subtitleKey, rootCmd.Flags().Lookup(subtitleKey))
+	viper.BindPFlag(singleFileKey, rootCmd.Flags().Lookup(singleFileKey))
+	viper.BindPFlag(bookFolderKey, rootCmd.Flags().Lookup(bookFolderKey))
+	viper.BindPFlag(fetchCoversKey, rootCmd.Flags().Lookup(fetchCoversKey))
+	viper.BindPFlag(musicBrainzKey, rootCmd.Flags().Lookup(musicBrainzKey))
+	viper.BindPFlag(musicBrainzURLKey, rootCmd.Flags().Lookup(musicBrainzURLKey))
+	viper.BindPFlag(carryKey, rootCmd.Flags().Lookup(carryKey))
+	viper.BindPFlag(mergeKey, rootCmd.Flags().Lookup(mergeKey))
+	viper.BindPFlag(jsonReportKey, rootCmd.Flags().Lookup(jsonReportKey))
+	viper.BindPFlag(trackMapKey, rootCmd.Flags().Lookup(trackMapKey))
+	viper.BindPFlag(htmlReportKey, rootCmd.Flags().Lookup(htmlReportKey))
+	viper.BindPFlag(emailSummaryKey, rootCmd.Flags().Lookup(emailSummaryKey))
+	viper.BindPFlag(diffLogKey, rootCmd.Flags().Lookup(diffLogKey))
+	viper.BindPFlag(fullScanKey, rootCmd.Flags().Lookup(fullScanKey))
+	viper.BindPFlag(checkAuthorsKey, rootCmd.Flags().Lookup(checkAuthorsKey))
+	viper.BindPFlag(authorLookupKey, rootCmd.Flags().Lookup(authorLookupKey))
+	viper.BindPFlag(applyLookupKey, rootCmd.Flags().Lookup(applyLookupKey))
+	viper.BindPFlag(authorAuthorityKey, rootCmd.Flags().Lookup(authorAuthorityKey))
+	viper.BindPFlag(authorAliasKey, rootCmd.Flags().Lookup(authorAliasKey))
+	viper.BindPFlag(writeIdentsKey, rootCmd.Flags().Lookup(writeIdentsKey))
+	viper.BindPFlag(provenanceKey, rootCmd.Flags().Lookup(provenanceKey))
+	viper.BindPFlag(seriesReadmeKey, rootCmd.Flags().Lookup(seriesReadmeKey))
+	viper.BindPFlag(ebooksKey, rootCmd.Flags().Lookup(ebooksKey))
+	viper.BindPFlag(fileLinesKey, rootCmd.Flags().Lookup(fileLinesKey))
+	viper.BindPFlag(progressEveryKey, rootCmd.Flags().Lookup(progressEveryKey))
+	viper.BindPFlag(detailLogKey, rootCmd.Flags().Lookup(detailLogKey))
+	viper.BindPFlag(noNetworkKey, rootCmd.Flags().Lookup(noNetworkKey))
+	viper.BindPFlag(strictKey, rootCmd.Flags().Lookup(strictKey))
+	viper.BindPFlag(trackTitlesKey, rootCmd.Flags().Lookup(trackTitlesKey))
+	viper.BindPFlag(mergeDiscsKey, rootCmd.Flags().Lookup(mergeDiscsKey))
+	viper.BindPFlag(continuousKey, rootCmd.Flags().Lookup(continuousKey))
+	viper.BindPFlag(continuousTagsKey, rootCmd.Flags().Lookup(continuousTagsKey))
+	viper.BindPFlag(allowProtectedKey, rootCmd.Flags().Lookup(allowProtectedKey))
+	viper.BindPFlag(hiddenFilesKey, rootCmd.Flags().Lookup(hiddenFilesKey))
+	viper.BindPFlag(summaryKey, rootCmd.Flags().Lookup(summaryKey))
+	viper.BindPFlag(orderKey, rootCmd.Flags().Lookup(orderKey))
+	viper.BindPFlag(newestFirstKey, rootCmd.Flags().Lookup(newestFirstKey))
+	viper.BindPFlag(oldestFirstKey, rootCmd.Flags().Lookup(oldestFirstKey))
+	viper.BindPFlag(limitKey, rootCmd.Flags().Lookup(limitKey))
+	viper.BindPFlag(yesIKnowKey, rootCmd.Flags().Lookup(yesIKnowKey))
+	viper.BindPFlag(waitKey, rootCmd.Flags().Lookup(waitKey))
+	viper.BindPFlag(forceUnlockKey, rootCmd.Flags().Lookup(forceUnlockKey))
+	viper.BindPFlag(profileReportKey, rootCmd.Flags().Lookup(profileReportKey))
+	viper.BindPFlag(formatKey, rootCmd.Flags().Lookup(formatKey))
+	viper.BindPFlag(selectionKey, rootCmd.Flags().Lookup(selectionKey))
+	viper.BindPFlag(onlyPathKey, rootCmd.Flags().Lookup(onlyPathKey))
+	viper.BindPFlag(onlyAuthorKey, rootCmd.Flags().Lookup(onlyAuthorKey))
+	viper.BindPFlag(onlyTitleKey, rootCmd.Flags().Lookup(onlyTitleKey))
 
 	// Set up environment variable handling
 	viper.SetEnvPrefix("AUDIOBOOK_ORGANIZER") // This will still be used for unmapped variables
@@ -393,4 +1106,37 @@ func initConfig() {
 			}
 		}
 	}
+
+	organizer.SetQuietMode(viper.GetBool(quietKey))
+	organizer.SetColorMode(colorMode())
+	if _, err := organizer.NewCollation(viper.GetString(localeKey)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitFatal)
+	}
+	if _, err := organizer.ParseExtensionActions(stringListValue(extensionKey)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitFatal)
+	}
+	if _, err := i18n.New(language()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitFatal)
+	}
+}
+
+// language returns the message language from --lang, falling back to the locale
+// environment variables
+func language() string {
+	return i18n.Detect(viper.GetString(langKey))
+}
+
+// colorMode returns the color mode from --no-color and --force-color. No color wins
+// when both are set, since it is the safe choice for captured logs.
+func colorMode() organizer.ColorMode {
+	switch {
+	case viper.GetBool(noColorKey):
+		return organizer.ColorNever
+	case viper.GetBool(forceColorKey):
+		return organizer.ColorAlways
+	}
+	return organizer.ColorAuto
 }
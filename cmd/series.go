@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// seriesCmd is the parent command for series maintenance
+var seriesCmd = &cobra.Command{
+	Use:   "series",
+	Short: "Inspect the series in an organized library",
+}
+
+// seriesReportCmd reports missing and unnumbered books per series
+var seriesReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report missing and unnumbered books in each series",
+	Long: `Report how complete each series of an organized library is.
+
+Books are grouped by author and series from their metadata. For every series the
+report lists books without a series number and gaps in the numbering, such as a
+library holding #1 and #3 but not #2. Nothing is sent anywhere unless --online is
+given; then each series is also looked up in the Audible catalog and books it
+lists that the library lacks are reported as missing.
+
+Examples:
+  # Offline: unnumbered books and numbering gaps
+  audiobook-organizer series report --dir=/media/audiobooks
+
+  # Compare against the Audible catalog, only showing incomplete series
+  audiobook-organizer series report --dir=/media/audiobooks --online --incomplete
+
+  # Machine-readable output
+  audiobook-organizer series report --dir=/media/audiobooks --json`,
+	Args: cobra.NoArgs,
+	RunE: runSeriesReport,
+}
+
+func init() {
+	rootCmd.AddCommand(seriesCmd)
+	seriesCmd.AddCommand(seriesReportCmd)
+
+	seriesReportCmd.Flags().Bool("online", false, "Look each series up in the Audible catalog and report missing books")
+	seriesReportCmd.Flags().String("catalog-url", organizer.DefaultSeriesCatalogURL, "Audible catalog API to query with --online (e.g. https://api.audible.co.uk)")
+	seriesReportCmd.Flags().Bool("incomplete", false, "Only show series with missing or unnumbered books")
+	seriesReportCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
+
+func runSeriesReport(cmd *cobra.Command, args []string) error {
+	handleInputAliases(cmd)
+	libraryDir := firstNonEmpty(viper.GetString("dir"), viper.GetString("input"))
+	if libraryDir == "" {
+		return fmt.Errorf("library directory is required\n\nPlease specify it with:\n  --dir=/path/to/organized/library")
+	}
+	root, err := filepath.Abs(libraryDir)
+	if err != nil {
+		return err
+	}
+
+	scanner := organizer.NewScanner(organizer.ScanOptions{
+		UseEmbeddedMetadata: viper.GetBool(useEmbeddedMetaKey),
+		StrictJSON:          viper.GetBool(strictJSONKey),
+		FieldMapping:        metadataFieldMapping(cmd),
+		SkipUnreadable:      true,
+		Extensions:          extensionPolicy(),
+		MinDepth:            viper.GetInt(minDepthKey),
+		MaxDepth:            viper.GetInt(maxDepthKey),
+	})
+	result, err := scanner.Scan(root)
+	if err != nil {
+		return fmt.Errorf("error scanning %s: %w", root, err)
+	}
+	collation, err := organizer.NewCollation(viper.GetString(localeKey))
+	if err != nil {
+		return err
+	}
+	report := organizer.BuildSeriesReport(result.Books, collation)
+
+	if online, _ := cmd.Flags().GetBool("online"); online {
+		catalogURL, _ := cmd.Flags().GetString("catalog-url")
+		organizer.CheckSeriesOnline(report, organizer.NewAudibleCatalog(catalogURL))
+	}
+	if incomplete, _ := cmd.Flags().GetBool("incomplete"); incomplete {
+		var filtered []organizer.SeriesStatus
+		for _, status := range report {
+			if !status.Complete() || status.Error != "" {
+				filtered = append(filtered, status)
+			}
+		}
+		report = filtered
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		if report == nil {
+			report = []organizer.SeriesStatus{}
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	writeSeriesReport(cmd.OutOrStdout(), report)
+	return nil
+}
+
+func writeSeriesReport(out io.Writer, report []organizer.SeriesStatus) {
+	incomplete := 0
+	for _, status := range report {
+		if !status.Complete() {
+			incomplete++
+		}
+	}
+	fmt.Fprintf(out, "%d series, %d incomplete\n", len(report), incomplete)
+
+	for _, status := range report {
+		mark := "✅"
+		if !status.Complete() {
+			mark = "⚠️ "
+		}
+		fmt.Fprintf(out, "\n%s %s — %s (%d owned)\n", mark, status.Series, status.Author, len(status.Books))
+		fmt.Fprintf(out, "   %s\n", status.Dir)
+		if len(status.Gaps) > 0 {
+			fmt.Fprintf(out, "   Gaps in numbering: #%s\n", strings.Join(status.Gaps, ", #"))
+		}
+		for _, title := range status.Unnumbered {
+			fmt.Fprintf(out, "   Unnumbered: %s\n", title)
+		}
+		for _, book := range status.Missing {
+			if book.Number != "" {
+				fmt.Fprintf(out, "   Missing #%s: %s\n", book.Number, book.Title)
+			} else {
+				fmt.Fprintf(out, "   Missing: %s\n", book.Title)
+			}
+		}
+		if status.Error != "" {
+			fmt.Fprintf(out, "   Online check failed: %s\n", status.Error)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/viper"
+)
+
+func TestSeriesReportCommand(t *testing.T) {
+	root := t.TempDir()
+	viper.Set("dir", root)
+	t.Cleanup(func() { viper.Set("dir", "") })
+
+	for _, book := range []struct{ dir, title, series string }{
+		{"One", "The Eye of the World", "The Wheel of Time #1"},
+		{"Three", "The Dragon Reborn", "The Wheel of Time #3"},
+		{"Prequel", "New Spring", "The Wheel of Time"},
+	} {
+		dir := filepath.Join(root, "Robert Jordan", book.dir)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(map[string]any{"title": book.title, "authors": []string{"Robert Jordan"}, "series": []string{book.series}})
+		if err := os.WriteFile(filepath.Join(dir, organizer.MetadataFileName), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "01.mp3"), []byte("audio"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	seriesReportCmd.SetOut(&out)
+	if err := seriesReportCmd.RunE(seriesReportCmd, nil); err != nil {
+		t.Fatalf("series report error = %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"1 series, 1 incomplete", "The Wheel of Time", "Gaps in numbering: #2", "Unnumbered: New Spring"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("series report output missing %q:\n%s", want, got)
+		}
+	}
+
+	out.Reset()
+	seriesReportCmd.Flags().Set("json", "true")
+	t.Cleanup(func() { seriesReportCmd.Flags().Set("json", "false") })
+	if err := seriesReportCmd.RunE(seriesReportCmd, nil); err != nil {
+		t.Fatalf("series report --json error = %v", err)
+	}
+	var report []organizer.SeriesStatus
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if len(report) != 1 || len(report[0].Books) != 3 {
+		t.Errorf("report = %+v", report)
+	}
+}
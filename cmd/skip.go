@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// skipCmd is the parent command for the input directory's skip list
+var skipCmd = &cobra.Command{
+	Use:   "skip",
+	Short: "Manage the list of books that are never organized",
+	Long: `Manage the skip list kept in the input directory (` + organizer.SkipListFileName + `).
+
+Listed directories and files, and everything below them, are left out of every
+scan: organize runs, rename runs, the TUI, and the web UI. Use it for rips in
+progress, DRM backups, or anything else that must stay where it is.
+
+Paths are relative to --dir unless they are absolute.
+
+Examples:
+  # Never organize a rip that is still in progress
+  audiobook-organizer skip add --dir=/media/audiobooks "Incoming/Half Ripped"
+
+  # Show and edit the list
+  audiobook-organizer skip list --dir=/media/audiobooks
+  audiobook-organizer skip remove --dir=/media/audiobooks "Incoming/Half Ripped"`,
+}
+
+var skipAddCmd = &cobra.Command{
+	Use:   "add <path>...",
+	Short: "Add paths to the skip list",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := loadSkipList(cmd)
+		if err != nil {
+			return err
+		}
+		for _, path := range args {
+			added, err := list.Add(path)
+			if err != nil {
+				return err
+			}
+			if added {
+				fmt.Fprintf(cmd.OutOrStdout(), "Skipping %s\n", path)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is already skipped\n", path)
+			}
+		}
+		return list.Save()
+	},
+}
+
+var skipRemoveCmd = &cobra.Command{
+	Use:   "remove <path>...",
+	Short: "Remove paths from the skip list",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := loadSkipList(cmd)
+		if err != nil {
+			return err
+		}
+		for _, path := range args {
+			if list.Remove(path) {
+				fmt.Fprintf(cmd.OutOrStdout(), "No longer skipping %s\n", path)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is not on the skip list\n", path)
+			}
+		}
+		return list.Save()
+	},
+}
+
+var skipListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the skip list",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := loadSkipList(cmd)
+		if err != nil {
+			return err
+		}
+		writeSkipList(cmd.OutOrStdout(), list)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(skipCmd)
+	skipCmd.AddCommand(skipAddCmd, skipRemoveCmd, skipListCmd)
+}
+
+// loadSkipList opens the skip list of the --dir/--input directory
+func loadSkipList(cmd *cobra.Command) (*organizer.SkipList, error) {
+	handleInputAliases(cmd)
+	inputDir := viper.GetString("dir")
+	if inputDir == "" {
+		inputDir = viper.GetString("input")
+	}
+	if inputDir == "" {
+		return nil, fmt.Errorf("input directory is required\n\nPlease specify it with:\n  --dir=/path/to/audiobooks")
+	}
+	root, err := filepath.Abs(inputDir)
+	if err != nil {
+		return nil, err
+	}
+	return organizer.LoadSkipList(root)
+}
+
+func writeSkipList(out io.Writer, list *organizer.SkipList) {
+	fmt.Fprintf(out, "%d path(s) skipped\n", list.Len())
+	for _, entry := range list.Entries() {
+		fmt.Fprintf(out, "  - %s\n", entry)
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/viper"
+)
+
+func TestSkipCommands(t *testing.T) {
+	root := t.TempDir()
+	viper.Set("dir", root)
+	t.Cleanup(func() { viper.Set("dir", "") })
+
+	var out bytes.Buffer
+	skipAddCmd.SetOut(&out)
+	skipRemoveCmd.SetOut(&out)
+	skipListCmd.SetOut(&out)
+
+	if err := skipAddCmd.RunE(skipAddCmd, []string{"Incoming/Rip", filepath.Join(root, "DRM")}); err != nil {
+		t.Fatalf("skip add error = %v", err)
+	}
+	if err := skipAddCmd.RunE(skipAddCmd, []string{"../outside"}); err == nil {
+		t.Error("skip add accepted a path outside the input directory")
+	}
+
+	list, err := organizer.LoadSkipList(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"DRM", "Incoming/Rip"}; !reflect.DeepEqual(list.Entries(), want) {
+		t.Errorf("entries = %v, want %v", list.Entries(), want)
+	}
+
+	if err := skipRemoveCmd.RunE(skipRemoveCmd, []string{"DRM"}); err != nil {
+		t.Fatalf("skip remove error = %v", err)
+	}
+	out.Reset()
+	if err := skipListCmd.RunE(skipListCmd, nil); err != nil {
+		t.Fatalf("skip list error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "1 path(s) skipped") || !strings.Contains(got, "Incoming/Rip") {
+		t.Errorf("skip list output = %q", got)
+	}
+}
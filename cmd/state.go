@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// exportStateCmd bundles the curation state of a library into one file
+var exportStateCmd = &cobra.Command{
+	Use:   "export-state <bundle.tar.gz>",
+	Short: "Bundle the config, author cache, skip list, and undo log into one file",
+	Long: `Write the curation state of a library to a gzipped tar bundle, so it can be moved
+to another machine with import-state: try a layout on a laptop, then apply it on
+the NAS.
+
+The bundle holds:
+  - the config file in use, with author aliases and other settings
+  - the author lookup cache
+  - the skip list of --dir (` + organizer.SkipListFileName + `)
+  - the undo log of --out, or of --dir without an output (` + organizer.LogFileName + `)
+
+Files that don't exist are left out. Use "-" to write the bundle to stdout.
+
+Examples:
+  audiobook-organizer export-state --dir=/downloads --out=/media/audiobooks state.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handleInputAliases(cmd)
+		locations := stateLocations()
+		locations.ConfigFile = viper.ConfigFileUsed()
+
+		var out io.Writer = cmd.OutOrStdout()
+		if args[0] != "-" {
+			file, err := os.Create(args[0])
+			if err != nil {
+				return fmt.Errorf("error creating bundle: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		manifest, err := organizer.ExportState(out, locations)
+		if err != nil {
+			return err
+		}
+		if args[0] != "-" {
+			writeStateExport(cmd.OutOrStdout(), args[0], manifest)
+		}
+		return nil
+	},
+}
+
+// importStateCmd restores a bundle written by export-state
+var importStateCmd = &cobra.Command{
+	Use:   "import-state <bundle.tar.gz>",
+	Short: "Restore a bundle written by export-state",
+	Long: `Restore the curation state bundled by export-state on this machine.
+
+The author lookup cache and skip list are merged with any already here. The
+config file and undo log are only written where none exists yet, unless --force
+is given. Paths in the undo log are moved from the exported --dir and --out to
+the ones given here, so undo works where the library is mounted now.
+
+The config file goes to --config, the config file in use, or
+$HOME/.audiobook-organizer.yaml.
+
+Examples:
+  audiobook-organizer import-state --dir=/volume1/downloads --out=/volume1/audiobooks state.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handleInputAliases(cmd)
+		locations := stateLocations()
+		locations.ConfigFile = viper.ConfigFileUsed()
+		if locations.ConfigFile == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			locations.ConfigFile = filepath.Join(home, ".audiobook-organizer.yaml")
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("error opening bundle: %w", err)
+		}
+		defer file.Close()
+
+		force, _ := cmd.Flags().GetBool("force")
+		results, err := organizer.ImportState(file, locations, force)
+		writeStateImport(cmd.OutOrStdout(), results)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportStateCmd, importStateCmd)
+	importStateCmd.Flags().Bool("force", false, "Replace an existing config file and undo log")
+}
+
+// stateLocations returns the input and output directories and undo log of the flags
+func stateLocations() organizer.StateLocations {
+	return organizer.StateLocations{
+		InputDir:  viper.GetString("dir"),
+		OutputDir: viper.GetString("out"),
+		LogPath:   viper.GetString(logPathKey),
+	}
+}
+
+func writeStateExport(out io.Writer, path string, manifest organizer.StateManifest) {
+	fmt.Fprintf(out, "Exported %d file(s) to %s\n", len(manifest.Files), path)
+	for _, file := range manifest.Files {
+		fmt.Fprintf(out, "  - %s: %s\n", file.Kind, file.Path)
+	}
+}
+
+func writeStateImport(out io.Writer, results []organizer.StateImport) {
+	fmt.Fprintf(out, "Read %d file(s) from the bundle\n", len(results))
+	for _, result := range results {
+		if result.Path == "" {
+			fmt.Fprintf(out, "  - %s: %s\n", result.Kind, result.Action)
+			continue
+		}
+		fmt.Fprintf(out, "  - %s: %s (%s)\n", result.Kind, result.Path, result.Action)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/viper"
+)
+
+func TestExportImportStateCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	input := t.TempDir()
+	viper.Set("dir", input)
+	t.Cleanup(func() { viper.Set("dir", "") })
+
+	if err := os.WriteFile(filepath.Join(input, organizer.SkipListFileName), []byte("Incoming/Rip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(t.TempDir(), "state.tar.gz")
+
+	var out bytes.Buffer
+	exportStateCmd.SetOut(&out)
+	if err := exportStateCmd.RunE(exportStateCmd, []string{bundle}); err != nil {
+		t.Fatalf("export-state error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "Exported 1 file(s)") || !strings.Contains(got, "skip-list") {
+		t.Errorf("export-state output = %q", got)
+	}
+
+	target := t.TempDir()
+	viper.Set("dir", target)
+	out.Reset()
+	importStateCmd.SetOut(&out)
+	if err := importStateCmd.RunE(importStateCmd, []string{bundle}); err != nil {
+		t.Fatalf("import-state error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "skip-list: "+filepath.Join(target, organizer.SkipListFileName)+" (merged)") {
+		t.Errorf("import-state output = %q", got)
+	}
+	list, err := organizer.LoadSkipList(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := list.Entries(); len(entries) != 1 || entries[0] != "Incoming/Rip" {
+		t.Errorf("imported skip list = %v", entries)
+	}
+}
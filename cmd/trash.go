@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultTrashPurgeAge = "30d"
+
+// trashCmd is the parent command for trash directory maintenance
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Maintain the --trash-dir safety folder",
+	Long: `Maintain the folder used by --trash-dir.
+
+When --trash-dir is set, files the organizer would overwrite or delete are moved
+into a timestamped folder (YYYYMMDD-HHMMSS) below the trash directory instead.`,
+}
+
+// trashPurgeCmd deletes old trash sessions
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete trash folders older than a given age",
+	Long: `Delete timestamped trash folders older than --older-than.
+
+Ages accept days (30d), weeks (2w), or Go durations (12h). Only folders created
+by --trash-dir are removed; anything else in the trash directory is left alone.
+
+Examples:
+  # Delete trash older than 30 days
+  audiobook-organizer trash purge --trash-dir=/media/.abook-trash --older-than 30d
+
+  # Preview what would be deleted
+  audiobook-organizer trash purge --trash-dir=/media/.abook-trash --older-than 2w --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := viper.GetString(trashDirKey)
+		if dir == "" {
+			return fmt.Errorf("trash directory is required\n\nPlease specify it with:\n  --trash-dir=/path/to/trash")
+		}
+
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		age, err := organizer.ParseTrashAge(olderThan)
+		if err != nil {
+			return err
+		}
+
+		purged, err := organizer.PurgeTrash(dir, age, time.Now(), viper.GetBool(dryRunKey))
+		if err != nil {
+			return err
+		}
+		writeTrashPurgeResult(cmd.OutOrStdout(), purged, viper.GetBool(dryRunKey))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+
+	trashPurgeCmd.Flags().
+		String("older-than", defaultTrashPurgeAge, "Delete trash folders older than this age (e.g. 30d, 2w, 12h)")
+}
+
+func writeTrashPurgeResult(out io.Writer, purged []string, dryRun bool) {
+	verb := "Purged"
+	if dryRun {
+		verb = "Would purge"
+	}
+	fmt.Fprintf(out, "%s %d trash folder(s)\n", verb, len(purged))
+	for _, dir := range purged {
+		fmt.Fprintf(out, "  - %s\n", dir)
+	}
+}
@@ -3,9 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 	"github.com/jeeftor/audiobook-organizer/internal/tui"
+	"github.com/jeeftor/audiobook-organizer/internal/tui/models"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // tuiCmd represents the tui command for the TUI interface
@@ -32,8 +37,43 @@ This mode provides an interactive terminal-based way to scan, select, and organi
 			outputDir = cmd.Flags().Lookup("out").Value.String()
 		}
 
+		// Without a config file this is a first run, so the setup wizard recommends
+		// settings and saves them as the default profile
+		configPath := viper.ConfigFileUsed()
+		hasConfig := configPath != ""
+		if hasConfig {
+			if _, err := os.Stat(configPath); err != nil {
+				hasConfig = false
+			}
+		}
+		runSetup, _ := cmd.Flags().GetBool("setup")
+		messages, _ := i18n.New(language())
+		if err := organizer.ValidateScanDepth(viper.GetInt(minDepthKey), viper.GetInt(maxDepthKey)); err != nil {
+			fmt.Printf("Error running TUI: %v\n", err)
+			os.Exit(1)
+		}
+		setup := models.SetupOptions{
+			FirstRun:    runSetup || !hasConfig,
+			SaveProfile: saveProfile,
+			Messages:    messages,
+			MinDepth:    viper.GetInt(minDepthKey),
+			MaxDepth:    viper.GetInt(maxDepthKey),
+		}
+		if hasConfig {
+			setup.Profile = &models.Profile{
+				Layout:              viper.GetString("layout"),
+				UseEmbeddedMetadata: viper.GetBool(useEmbeddedMetaKey),
+				Flat:                viper.GetBool("flat"),
+			}
+		}
+
+		if err := applyTUIAppearance(cmd); err != nil {
+			fmt.Printf("Error running TUI: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Initialize and run the TUI
-		if err := tui.Run(inputDir, outputDir); err != nil {
+		if err := tui.RunWithSetup(inputDir, outputDir, setup); err != nil {
 			fmt.Printf("Error running TUI: %v\n", err)
 			os.Exit(1)
 		}
@@ -48,4 +88,74 @@ func init() {
 	tuiCmd.Flags().StringP("input", "i", "", "Base directory to scan (alias for --dir)")
 	tuiCmd.Flags().String("out", "", "Output directory (alias for --output)")
 	tuiCmd.Flags().StringP("output", "o", "", "Output directory (alias for --out)")
+	tuiCmd.Flags().Bool("setup", false, "Run the first-run setup wizard even when a config file exists")
+	addTUIAppearanceFlags(tuiCmd)
+}
+
+// addTUIAppearanceFlags adds the theme and glyph flags shared by the TUI commands
+func addTUIAppearanceFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		String("theme", "", "Color theme: dark, light, high-contrast, or no-color (default dark, or no-color when NO_COLOR is set)")
+	cmd.Flags().
+		Bool("plain-glyphs", false, "Replace emoji and box drawing with ASCII for terminals that cannot render them")
+}
+
+// applyTUIAppearance sets the TUI theme and glyphs from the flags, falling back to
+// the tui-theme and plain-glyphs config keys and their environment variables
+func applyTUIAppearance(cmd *cobra.Command) error {
+	name := viper.GetString(tuiThemeKey)
+	if cmd.Flags().Changed("theme") {
+		name, _ = cmd.Flags().GetString("theme")
+	} else if name == "" && os.Getenv("NO_COLOR") != "" {
+		// https://no-color.org
+		name = string(models.ThemeNoColor)
+	}
+	theme, err := models.ParseTheme(name)
+	if err != nil {
+		return err
+	}
+
+	plain := viper.GetBool(plainGlyphsKey)
+	if cmd.Flags().Changed("plain-glyphs") {
+		plain, _ = cmd.Flags().GetBool("plain-glyphs")
+	}
+
+	models.SetTheme(theme)
+	models.SetPlainGlyphs(plain)
+	return nil
+}
+
+// saveProfile writes the setup wizard's choices to the config file in use, or to
+// ~/.audiobook-organizer.yaml when there is none
+func saveProfile(profile models.Profile) error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, ".audiobook-organizer.yaml")
+	}
+	return writeProfile(path, profile)
+}
+
+// writeProfile stores profile in the YAML config file at path, keeping any other
+// settings already in it
+func writeProfile(path string, profile models.Profile) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if _, err := os.Stat(path); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	v.Set("layout", profile.Layout)
+	v.Set(useEmbeddedMetaKey, profile.UseEmbeddedMetadata)
+	v.Set("flat", profile.Flat)
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/jeeftor/audiobook-organizer/internal/tui/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func TestWriteProfileKeepsExistingSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".audiobook-organizer.yaml")
+	if err := os.WriteFile(path, []byte("verbose: true\nlayout: author-only\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	profile := models.Profile{Layout: "author-title", UseEmbeddedMetadata: true, Flat: true}
+	if err := writeProfile(path, profile); err != nil {
+		t.Fatalf("writeProfile() error = %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("reading written profile: %v", err)
+	}
+	if got := v.GetString("layout"); got != "author-title" {
+		t.Errorf("layout = %q, want author-title", got)
+	}
+	if !v.GetBool(useEmbeddedMetaKey) || !v.GetBool("flat") {
+		t.Errorf("scan mode = embedded %v, flat %v, want both true", v.GetBool(useEmbeddedMetaKey), v.GetBool("flat"))
+	}
+	if !v.GetBool("verbose") {
+		t.Error("existing verbose setting was lost")
+	}
+}
+
+func TestTUICommandIncludesSetupFlag(t *testing.T) {
+	if tuiCmd.Flags().Lookup("setup") == nil {
+		t.Fatal("tui command missing setup flag")
+	}
+}
+
+func TestRootCommandIncludesSelectionFlag(t *testing.T) {
+	if rootCmd.Flags().Lookup(selectionKey) == nil {
+		t.Fatal("root command missing selection flag")
+	}
+	if aliases := envAliases[selectionKey]; len(aliases) != 2 || aliases[0] != "AO_SELECTION" {
+		t.Errorf("selection aliases = %v, want AO_SELECTION and AUDIOBOOK_ORGANIZER_SELECTION", aliases)
+	}
+}
+
+func TestTUICommandsIncludeAppearanceFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{tuiCmd, renameTuiCmd, metadataTuiCmd} {
+		for _, flag := range []string{"theme", "plain-glyphs"} {
+			if cmd.Flags().Lookup(flag) == nil {
+				t.Errorf("%s command missing %s flag", cmd.Name(), flag)
+			}
+		}
+	}
+}
+
+func TestApplyTUIAppearance(t *testing.T) {
+	defer models.SetTheme(models.ThemeDark)
+	defer models.SetPlainGlyphs(false)
+	defer viper.Reset()
+
+	t.Setenv("NO_COLOR", "1")
+	cmd := &cobra.Command{}
+	addTUIAppearanceFlags(cmd)
+	if err := applyTUIAppearance(cmd); err != nil {
+		t.Fatalf("applyTUIAppearance() error = %v", err)
+	}
+	if got := models.CurrentTheme(); got != models.ThemeNoColor {
+		t.Errorf("theme with NO_COLOR = %q, want no-color", got)
+	}
+
+	viper.Set(plainGlyphsKey, true)
+	cmd.Flags().Set("theme", "high-contrast")
+	if err := applyTUIAppearance(cmd); err != nil {
+		t.Fatalf("applyTUIAppearance() error = %v", err)
+	}
+	if got := models.CurrentTheme(); got != models.ThemeHighContrast {
+		t.Errorf("theme = %q, want high-contrast", got)
+	}
+	if !models.PlainGlyphs() {
+		t.Error("plain-glyphs config setting was ignored")
+	}
+
+	cmd.Flags().Set("theme", "sepia")
+	if err := applyTUIAppearance(cmd); err == nil {
+		t.Error("applyTUIAppearance() accepted an unknown theme")
+	}
+}
+
+func TestColorMode(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		noColor, forceColor bool
+		want                organizer.ColorMode
+	}{
+		{false, false, organizer.ColorAuto},
+		{true, false, organizer.ColorNever},
+		{false, true, organizer.ColorAlways},
+		{true, true, organizer.ColorNever},
+	}
+
+	for _, tt := range tests {
+		viper.Set(noColorKey, tt.noColor)
+		viper.Set(forceColorKey, tt.forceColor)
+		if got := colorMode(); got != tt.want {
+			t.Errorf("colorMode() with no-color %v, force-color %v = %v, want %v", tt.noColor, tt.forceColor, got, tt.want)
+		}
+	}
+}
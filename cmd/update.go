@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,10 +15,18 @@ import (
 	"strings"
 
 	"github.com/blang/semver"
+	"github.com/inconshreveable/go-update"
 	"github.com/rhysd/go-github-selfupdate/selfupdate"
 	"github.com/spf13/cobra"
 )
 
+// checksumsAssetName is the goreleaser checksum file published with every release
+const checksumsAssetName = "checksums.txt"
+
+// selfUpdateDisabled can be set to "true" via ldflags by packagers that manage the
+// binary themselves. AO_DISABLE_SELF_UPDATE has the same effect at runtime.
+var selfUpdateDisabled = "false"
+
 // GitHubRelease represents a GitHub release
 type GitHubRelease struct {
 	TagName string `json:"tag_name"`
@@ -36,27 +48,34 @@ const (
 	InstallMethodApk
 	InstallMethodBinary
 	InstallMethodGoInstall
+	InstallMethodDocker
 )
 
 var checkOnly bool
 
-// updateCmd represents the update command
+// updateCmd represents the self-update command
 var updateCmd = &cobra.Command{
-	Use:   "update",
-	Short: "Update audiobook-organizer to the latest version",
+	Use:     "self-update",
+	Aliases: []string{"update"},
+	Short:   "Update audiobook-organizer to the latest version",
 	Long: `Check for and install the latest version of audiobook-organizer from GitHub releases.
 
 This command will:
   - Check for the latest release on GitHub
   - Compare with your current version
-  - Download and install the update (if not using --check)
+  - Download the release archive and verify it against the release checksums.txt
+  - Replace the running binary (if not using --check)
+
+Package-managed installs (Homebrew, APT, YUM/DNF, APK) and Docker images are never
+replaced in place; the command prints the upgrade steps for that install method
+instead. Set AO_DISABLE_SELF_UPDATE=true to turn off binary replacement entirely.
 
 Examples:
   # Check for updates without installing
-  audiobook-organizer update --check
+  audiobook-organizer self-update --check
 
   # Update to the latest version
-  audiobook-organizer update
+  audiobook-organizer self-update
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpdate()
@@ -120,7 +139,7 @@ func runUpdate() error {
 
 	// If check-only mode, stop here
 	if checkOnly {
-		fmt.Println("\n💡 Run 'audiobook-organizer update' to install the update")
+		fmt.Println("\n💡 Run 'audiobook-organizer self-update' to install the update")
 		return nil
 	}
 
@@ -138,7 +157,15 @@ func runUpdate() error {
 	case InstallMethodApk:
 		return updateViaApk()
 
+	case InstallMethodDocker:
+		return updateViaDocker(latestVersion.String())
+
 	case InstallMethodBinary, InstallMethodGoInstall, InstallMethodUnknown:
+		if isSelfUpdateDisabled() {
+			fmt.Println("\n⚠️  Self-update is disabled for this installation")
+			fmt.Printf("Download the new release from: %s\n", release.HTMLURL)
+			return nil
+		}
 		// Use self-update for binary installs
 		return updateViaSelfUpdate(release, latestVersion)
 
@@ -170,6 +197,29 @@ func fetchLatestRelease() (*GitHubRelease, error) {
 	return &release, nil
 }
 
+// isSelfUpdateDisabled reports whether binary replacement was turned off by the
+// build or by the AO_DISABLE_SELF_UPDATE environment variable
+func isSelfUpdateDisabled() bool {
+	if selfUpdateDisabled == "true" {
+		return true
+	}
+	switch strings.ToLower(os.Getenv("AO_DISABLE_SELF_UPDATE")) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// findReleaseAsset returns the download URL of the named release asset
+func findReleaseAsset(release *GitHubRelease, name string) (string, bool) {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return asset.BrowserDownloadURL, true
+		}
+	}
+	return "", false
+}
+
 // findAssetForPlatform finds the correct download asset for the current platform
 func findAssetForPlatform(release *GitHubRelease) (string, error) {
 	// Determine the expected asset name based on OS and architecture
@@ -242,6 +292,11 @@ func detectInstallMethod() InstallMethod {
 		return InstallMethodUnknown
 	}
 
+	// Binaries inside the published image are replaced by pulling a new image
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return InstallMethodDocker
+	}
+
 	// Check for Homebrew (macOS/Linux)
 	if strings.Contains(exe, "/Cellar/") || strings.Contains(exe, "/homebrew/") {
 		return InstallMethodHomebrew
@@ -292,6 +347,8 @@ func (m InstallMethod) String() string {
 		return "Binary Install"
 	case InstallMethodGoInstall:
 		return "Go Install"
+	case InstallMethodDocker:
+		return "Docker"
 	default:
 		return "Unknown"
 	}
@@ -359,23 +416,58 @@ func updateViaApk() error {
 	return nil
 }
 
-// updateViaSelfUpdate performs a self-update for binary installations
+// updateViaDocker prints the image upgrade steps for container installs
+func updateViaDocker(version string) error {
+	fmt.Println("\n🐳 Docker container detected")
+	fmt.Println("⚠️  The binary inside a container is replaced by pulling a new image")
+	fmt.Println("\nTo update, please run:")
+	fmt.Printf("  docker pull jeffsui/audiobook-organizer:%s\n", version)
+
+	return nil
+}
+
+// updateViaSelfUpdate performs a self-update for binary installations. The archive is
+// verified against the release checksums.txt before the running binary is replaced.
 func updateViaSelfUpdate(release *GitHubRelease, latestVersion semver.Version) error {
 	// Find the correct asset for this platform
 	assetURL, err := findAssetForPlatform(release)
 	if err != nil {
 		return err
 	}
+	checksumsURL, ok := findReleaseAsset(release, checksumsAssetName)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary",
+			release.TagName, checksumsAssetName)
+	}
 
 	// Perform the update
 	fmt.Printf("\n⬇️  Downloading update from: %s\n", assetURL)
 
+	archive, err := downloadReleaseAsset(assetURL)
+	if err != nil {
+		return err
+	}
+	checksums, err := downloadReleaseAsset(checksumsURL)
+	if err != nil {
+		return err
+	}
+
+	assetName := assetURL[strings.LastIndex(assetURL, "/")+1:]
+	if err := verifyAssetChecksum(assetName, archive, checksums); err != nil {
+		return err
+	}
+	fmt.Println("🔒 Checksum verified")
+
 	exe, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
-	if err := selfupdate.UpdateTo(assetURL, exe); err != nil {
+	binary, err := selfupdate.UncompressCommand(bytes.NewReader(archive), assetURL, exe)
+	if err != nil {
+		return fmt.Errorf("failed to extract binary: %w", err)
+	}
+	if err := update.Apply(binary, update.Options{TargetPath: exe}); err != nil {
 		return fmt.Errorf("failed to update binary: %w", err)
 	}
 
@@ -384,3 +476,42 @@ func updateViaSelfUpdate(release *GitHubRelease, latestVersion semver.Version) e
 
 	return nil
 }
+
+// downloadReleaseAsset fetches a release asset into memory
+func downloadReleaseAsset(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download of %s returned status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// verifyAssetChecksum checks data against the SHA-256 recorded for assetName in a
+// goreleaser checksums file ("<hex>  <name>" per line)
+func verifyAssetChecksum(assetName string, data, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != assetName {
+			continue
+		}
+
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s",
+				assetName, fields[0], hex.EncodeToString(sum[:]))
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum found for %s in %s", assetName, checksumsAssetName)
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"runtime"
 	"testing"
 
@@ -18,6 +20,7 @@ func TestInstallMethodString(t *testing.T) {
 		{InstallMethodApk, "APK (Alpine)"},
 		{InstallMethodBinary, "Binary Install"},
 		{InstallMethodGoInstall, "Go Install"},
+		{InstallMethodDocker, "Docker"},
 		{InstallMethodUnknown, "Unknown"},
 	}
 
@@ -334,6 +337,7 @@ func TestDetectInstallMethod(t *testing.T) {
 		InstallMethodApk,
 		InstallMethodBinary,
 		InstallMethodGoInstall,
+		InstallMethodDocker,
 	}
 
 	found := false
@@ -354,3 +358,32 @@ func TestDetectInstallMethod(t *testing.T) {
 		t.Error("InstallMethod.String() returned empty string")
 	}
 }
+
+func TestVerifyAssetChecksum(t *testing.T) {
+	data := []byte("release archive")
+	sum := sha256.Sum256(data)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  audiobook-organizer_Linux_x86_64.tar.gz\n" +
+		"0000  audiobook-organizer_Darwin_arm64.tar.gz\n")
+
+	if err := verifyAssetChecksum("audiobook-organizer_Linux_x86_64.tar.gz", data, checksums); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := verifyAssetChecksum("audiobook-organizer_Darwin_arm64.tar.gz", data, checksums); err == nil {
+		t.Error("Expected checksum mismatch error but got none")
+	}
+	if err := verifyAssetChecksum("audiobook-organizer_Windows_x86_64.zip", data, checksums); err == nil {
+		t.Error("Expected missing checksum error but got none")
+	}
+}
+
+func TestIsSelfUpdateDisabled(t *testing.T) {
+	t.Setenv("AO_DISABLE_SELF_UPDATE", "")
+	if isSelfUpdateDisabled() {
+		t.Error("Expected self-update to be enabled by default")
+	}
+
+	t.Setenv("AO_DISABLE_SELF_UPDATE", "true")
+	if !isSelfUpdateDisabled() {
+		t.Error("Expected AO_DISABLE_SELF_UPDATE=true to disable self-update")
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// verifyLibraryCmd checks the undo log against the files on disk
+var verifyLibraryCmd = &cobra.Command{
+	Use:   "verify-library",
+	Short: "Check that every file in the undo log is still where undo expects it",
+	Long: `Replay the undo log and check that every file it recorded is still at its
+target with the same size. Books moved, renamed, deleted, or re-encoded outside the
+organizer are listed, so you know whether --undo will work before relying on it.
+
+Files a later run moved again are checked where that run put them. Runs made with
+--log-checksums also record a SHA-256 of every file; --checksums reads the files
+back and compares them. Logs written before sizes were recorded are only checked
+for existence. Nothing is changed. The command exits with 2 when a file doesn't
+match.
+
+Examples:
+  audiobook-organizer verify-library --out=/media/audiobooks
+  audiobook-organizer verify-library --log-path=/var/lib/audiobook-organizer/library.log --checksums`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyLibrary,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyLibraryCmd)
+
+	verifyLibraryCmd.Flags().Bool("checksums", false, "Also compare file contents with the checksums recorded by --log-checksums")
+	verifyLibraryCmd.Flags().Bool("json", false, "Print the result as JSON")
+}
+
+func runVerifyLibrary(cmd *cobra.Command, args []string) error {
+	logPath, err := undoLogPath(cmd, "")
+	if err != nil {
+		return err
+	}
+	if logPath == "" {
+		return fmt.Errorf("--log-path, --out, or --dir is required to find the undo log")
+	}
+	entries, err := organizer.ReadLogEntries(logPath)
+	if err != nil {
+		return err
+	}
+
+	checksums, _ := cmd.Flags().GetBool("checksums")
+	result := organizer.VerifyLogEntries(entries, checksums)
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		writeLibraryVerification(cmd.OutOrStdout(), logPath, result)
+	}
+
+	if !result.OK() {
+		return exitWith(cmd, ExitCompletedWithErrors)
+	}
+	return nil
+}
+
+// undoLogPath finds the undo log a command reads: explicit when given, otherwise
+// --log-path, or the default log of the output or input directory. It returns ""
+// when none of them is set.
+func undoLogPath(cmd *cobra.Command, explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if logPath := viper.GetString(logPathKey); logPath != "" {
+		return logPath, nil
+	}
+	logDir, err := outputDirFromCommand(cmd)
+	if err != nil {
+		return "", err
+	}
+	if logDir == "" {
+		if logDir, err = inputDirFromCommand(cmd); err != nil {
+			return "", err
+		}
+	}
+	if logDir == "" {
+		return "", nil
+	}
+	return organizer.DefaultLogPath(logDir), nil
+}
+
+func writeLibraryVerification(out io.Writer, logPath string, result organizer.LibraryVerification) {
+	fmt.Fprintf(out, "Checked %d file(s) from %d log entries in %s\n", result.Files, result.Entries, logPath)
+	if result.Superseded > 0 {
+		fmt.Fprintf(out, "%d earlier move(s) were moved again by a later run and checked at their newest target\n", result.Superseded)
+	}
+	if result.NoSize > 0 {
+		fmt.Fprintf(out, "%d file(s) were logged without a size and only checked for existence\n", result.NoSize)
+	}
+	if result.Checksums > 0 {
+		fmt.Fprintf(out, "%d checksum(s) compared\n", result.Checksums)
+	}
+
+	if result.OK() {
+		fmt.Fprintln(out, "✅ Every logged file is in place; undo can restore the library")
+		return
+	}
+	fmt.Fprintf(out, "❌ %d book(s) changed outside the organizer; undo can't fully restore them:\n", len(result.Books))
+	for _, book := range result.Books {
+		fmt.Fprintf(out, "\n%s (from %s, %s)\n", book.TargetPath, book.SourcePath, book.Timestamp.Format("2006-01-02 15:04"))
+		for _, problem := range book.Problems {
+			line := fmt.Sprintf("  - %s: %s", problem.Problem, problem.Path)
+			if problem.Detail != "" {
+				line += " (" + problem.Detail + ")"
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+}
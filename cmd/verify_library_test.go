@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+	"github.com/spf13/viper"
+)
+
+func TestVerifyLibraryCommand(t *testing.T) {
+	out := t.TempDir()
+	viper.Set("out", out)
+	t.Cleanup(func() { viper.Set("out", "") })
+
+	book := filepath.Join(out, "Frank Herbert", "Dune")
+	if err := os.MkdirAll(book, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(book, "dune.m4b"), []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries := []organizer.LogEntry{{
+		SourcePath: "/downloads/Dune",
+		TargetPath: book,
+		Files:      []organizer.FilePair{{From: "dune.m4b", To: "dune.m4b", Size: 5}, {From: "cover.jpg", To: "cover.jpg", Size: 3}},
+	}}
+	data, _ := json.Marshal(entries)
+	if err := os.WriteFile(filepath.Join(out, organizer.LogFileName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	verifyLibraryCmd.SetOut(&buf)
+	err := verifyLibraryCmd.RunE(verifyLibraryCmd, nil)
+	var exitErr exitCodeError
+	if !errors.As(err, &exitErr) || exitErr.code != ExitCompletedWithErrors {
+		t.Fatalf("RunE() = %v, want exit code %d", err, ExitCompletedWithErrors)
+	}
+	for _, want := range []string{"Checked 2 file(s) from 1 log entries", "1 book(s) changed", "missing: " + filepath.Join(book, "cover.jpg")} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(book, "cover.jpg"), []byte("jpg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := verifyLibraryCmd.RunE(verifyLibraryCmd, nil); err != nil {
+		t.Fatalf("RunE() = %v after restoring the file", err)
+	}
+	if !strings.Contains(buf.String(), "Every logged file is in place") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"runtime/debug"
 	"strings"
 	"time"
 
@@ -22,6 +23,39 @@ var (
 
 var shortOutput bool
 
+func init() {
+	applyModuleBuildInfo()
+}
+
+// applyModuleBuildInfo fills version details that were not set through ldflags from
+// the module and VCS information embedded by the Go toolchain, so `go install` and
+// plain `go build` binaries still report a meaningful version.
+func applyModuleBuildInfo() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	if buildVersion == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		buildVersion = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if buildCommit == "none" && setting.Value != "" {
+				buildCommit = setting.Value
+				if len(buildCommit) > 7 {
+					buildCommit = buildCommit[:7]
+				}
+			}
+		case "vcs.time":
+			if buildTime == "unknown" && setting.Value != "" {
+				buildTime = setting.Value
+			}
+		}
+	}
+}
+
 // GetFormattedBuildTime returns the build time in a readable format
 func GetFormattedBuildTime() string {
 	if buildTime == "unknown" {
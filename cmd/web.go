@@ -8,9 +8,12 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/jeeftor/audiobook-organizer/internal/app"
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
 	"github.com/jeeftor/audiobook-organizer/internal/server"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,7 +26,15 @@ var webCmd = &cobra.Command{
 
 The web UI serves from the same binary and binds to localhost by default.
 It exposes local API endpoints for scan, preview, rename, and Audiobookshelf
-configuration while reusing the existing organizer and ABS packages.`,
+configuration while reusing the existing organizer and ABS packages.
+
+With --read-only, endpoints that move or rename files or change Audiobookshelf
+(organize run, rename run, ABS scan trigger, ABS cleanup) are disabled, so the
+wizard can be shared or pointed at a production library for inspection only.
+
+With --root (repeatable), the directory browser, path validation, and every
+organize or rename request are limited to the given directories and what lies
+below them; without it the web UI may use any path the process can read.`,
 	RunE: runWeb,
 }
 
@@ -37,6 +48,8 @@ func addWebFlags(cmd *cobra.Command) {
 	cmd.Flags().Int("port", 0, "Port for the local web UI (0 chooses an available port)")
 	cmd.Flags().Bool("open", true, "Open the web UI in the default browser")
 	cmd.Flags().Bool("no-open", false, "Do not open the web UI in the default browser")
+	cmd.Flags().Bool("read-only", false, "Disable every endpoint that changes files or Audiobookshelf")
+	cmd.Flags().StringArray("root", nil, "Limit the paths the web UI may browse and use to this directory (repeatable)")
 }
 
 func runWeb(cmd *cobra.Command, args []string) error {
@@ -44,6 +57,8 @@ func runWeb(cmd *cobra.Command, args []string) error {
 	port, _ := cmd.Flags().GetInt("port")
 	openBrowser, _ := cmd.Flags().GetBool("open")
 	noOpen, _ := cmd.Flags().GetBool("no-open")
+	readOnly, _ := cmd.Flags().GetBool("read-only")
+	roots, _ := cmd.Flags().GetStringArray("root")
 	if noOpen {
 		openBrowser = false
 	}
@@ -57,6 +72,17 @@ func runWeb(cmd *cobra.Command, args []string) error {
 	}
 
 	webConfig := app.DefaultWebConfig(host, port, openBrowser, inputDir, outputDir)
+	webConfig.ReadOnly = readOnly
+	messages, _ := i18n.New(language())
+	webConfig.Language = messages.Language()
+	webConfig.Messages = messages.Messages("web.")
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("invalid --root %s: %w", root, err)
+		}
+		webConfig.Roots = append(webConfig.Roots, abs)
+	}
 	service := app.NewService(webConfig)
 	webServer, err := server.New(server.Config{Token: token}, service)
 	if err != nil {
@@ -70,6 +96,12 @@ func runWeb(cmd *cobra.Command, args []string) error {
 	url := server.URL(host, listener, token)
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Audiobook Organizer web UI running at:\n%s\n", url)
+	if readOnly {
+		fmt.Fprintln(cmd.OutOrStdout(), "Read-only mode: organize, rename, and ABS changes are disabled.")
+	}
+	if len(webConfig.Roots) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Paths are limited to: %s\n", strings.Join(webConfig.Roots, ", "))
+	}
 	if openBrowser {
 		if err := openURL(url); err != nil {
 			fmt.Fprintf(cmd.ErrOrStderr(), "Could not open browser automatically: %v\n", err)
@@ -0,0 +1,128 @@
+// internal/abs/item_ids.go
+// Mapping of reorganized paths to ABS library item IDs
+
+package abs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// ItemRef identifies an ABS library item
+type ItemRef struct {
+	ID        string
+	LibraryID string
+}
+
+// ItemMove links an ABS library item to the place the organizer moved it, so ABS
+// path-update tooling or the API can repoint the item and keep listening progress.
+type ItemMove struct {
+	ItemID       string `json:"item_id"`
+	LibraryID    string `json:"library_id"`
+	OldPath      string `json:"old_path"` // As ABS sees it
+	NewPath      string `json:"new_path"` // As ABS will see it
+	OldLocalPath string `json:"old_local_path"`
+	NewLocalPath string `json:"new_local_path"`
+	// Tracks lists the files of a book folder that were renamed, so progress and
+	// bookmarks kept per file can follow them
+	Tracks []organizer.TrackRename `json:"tracks,omitempty"`
+}
+
+// LoadItemIDs reads the library item IDs from an ABS database, keyed by the item
+// path as ABS stores it. The database must predate the reorganization.
+func LoadItemIDs(dbPath string) (map[string]ItemRef, error) {
+	db, err := openABSDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT id, libraryId, path FROM libraryItems`)
+	if err != nil {
+		return nil, fmt.Errorf("querying library items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string]ItemRef)
+	for rows.Next() {
+		var ref ItemRef
+		var itemPath string
+		if err := rows.Scan(&ref.ID, &ref.LibraryID, &itemPath); err != nil {
+			continue
+		}
+		items[normalizeABSPath(itemPath)] = ref
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading library items: %w", err)
+	}
+	return items, nil
+}
+
+// MapItemMoves matches the moves in an organizer log to ABS items. A moved book
+// directory matches an item by its path; single-file items, as moved in flat mode,
+// match by file. mapper translates local paths to ABS paths and may be nil when ABS
+// sees the same paths. Sources that match no item are returned as unmatched.
+func MapItemMoves(
+	items map[string]ItemRef,
+	mapper *PathMapper,
+	entries []organizer.LogEntry,
+) (moves []ItemMove, unmatched []string) {
+	toABS := func(local string) string {
+		if mapper == nil {
+			return local
+		}
+		return mapper.ToABS(local)
+	}
+	match := func(oldLocal, newLocal string, tracks []organizer.TrackRename) bool {
+		oldPath := toABS(oldLocal)
+		ref, ok := items[normalizeABSPath(oldPath)]
+		if !ok {
+			return false
+		}
+		moves = append(moves, ItemMove{
+			ItemID:       ref.ID,
+			LibraryID:    ref.LibraryID,
+			OldPath:      oldPath,
+			NewPath:      toABS(newLocal),
+			OldLocalPath: oldLocal,
+			NewLocalPath: newLocal,
+			Tracks:       tracks,
+		})
+		return true
+	}
+
+	for _, entry := range entries {
+		if match(entry.SourcePath, entry.TargetPath, organizer.RenamedFiles(entry.Files)) {
+			continue
+		}
+		found := false
+		for _, file := range entry.Files {
+			if match(filepath.Join(entry.SourcePath, file.From), filepath.Join(entry.TargetPath, file.To), nil) {
+				found = true
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, entry.SourcePath)
+		}
+	}
+	return moves, unmatched
+}
+
+// WriteItemMoves writes the mapping as indented JSON
+func WriteItemMoves(path string, moves []ItemMove) error {
+	if moves == nil {
+		moves = []ItemMove{}
+	}
+	data, err := json.MarshalIndent(moves, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing item ID map: %w", err)
+	}
+	return nil
+}
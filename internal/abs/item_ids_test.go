@@ -0,0 +1,154 @@
+// internal/abs/item_ids_test.go
+// Tests for ABS item ID mapping
+
+package abs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// writeTestABSDatabase creates an abs.sqlite with the given item paths keyed by ID
+func writeTestABSDatabase(t *testing.T, items map[string]string) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "abs.sqlite")
+	db, err := sql.Open(sqliteDriver, dbPath)
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE libraryItems (id TEXT PRIMARY KEY, libraryId TEXT, path TEXT)`); err != nil {
+		t.Fatalf("creating libraryItems: %v", err)
+	}
+	for id, itemPath := range items {
+		if _, err := db.Exec(`INSERT INTO libraryItems VALUES (?, ?, ?)`, id, "lib_main", itemPath); err != nil {
+			t.Fatalf("inserting %s: %v", id, err)
+		}
+	}
+	return dbPath
+}
+
+func TestLoadItemIDs(t *testing.T) {
+	dbPath := writeTestABSDatabase(t, map[string]string{
+		"li_dune":   "/audiobooks/Incoming/Dune/",
+		"li_single": "/audiobooks/Loose/hobbit.m4b",
+	})
+
+	items, err := LoadItemIDs(dbPath)
+	if err != nil {
+		t.Fatalf("LoadItemIDs() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("LoadItemIDs() returned %d items, want 2", len(items))
+	}
+	if got := items["/audiobooks/Incoming/Dune"]; got.ID != "li_dune" || got.LibraryID != "lib_main" {
+		t.Errorf("items[Dune] = %+v, want li_dune in lib_main", got)
+	}
+
+	if _, err := LoadItemIDs(filepath.Join(t.TempDir(), "missing.sqlite")); err == nil {
+		t.Error("LoadItemIDs() on a missing database should fail")
+	}
+}
+
+func TestMapItemMoves(t *testing.T) {
+	items := map[string]ItemRef{
+		"/audiobooks/Incoming/Dune":    {ID: "li_dune", LibraryID: "lib_main"},
+		"/audiobooks/Loose/hobbit.m4b": {ID: "li_hobbit", LibraryID: "lib_main"},
+	}
+	entries := []organizer.LogEntry{
+		{
+			SourcePath: "/mnt/media/audiobooks/Incoming/Dune",
+			TargetPath: "/mnt/media/audiobooks/Frank Herbert/Dune",
+			Files:      []organizer.FilePair{{From: "01.mp3", To: "01.mp3"}},
+		},
+		{
+			SourcePath: "/mnt/media/audiobooks/Loose",
+			TargetPath: "/mnt/media/audiobooks/J.R.R. Tolkien/The Hobbit",
+			Files:      []organizer.FilePair{{From: "hobbit.m4b", To: "hobbit.m4b"}},
+		},
+		{
+			SourcePath: "/mnt/media/audiobooks/Incoming/Unknown",
+			TargetPath: "/mnt/media/audiobooks/Someone/Unknown",
+		},
+	}
+
+	t.Run("mapped paths", func(t *testing.T) {
+		mapper := NewPathMapper([]PathMapping{{ABSPrefix: "/audiobooks", LocalPrefix: "/mnt/media/audiobooks"}})
+		moves, unmatched := MapItemMoves(items, mapper, entries)
+
+		if len(moves) != 2 {
+			t.Fatalf("MapItemMoves() returned %d moves, want 2: %+v", len(moves), moves)
+		}
+		if moves[0].ItemID != "li_dune" || moves[0].NewPath != "/audiobooks/Frank Herbert/Dune" {
+			t.Errorf("directory move = %+v", moves[0])
+		}
+		if moves[0].NewLocalPath != "/mnt/media/audiobooks/Frank Herbert/Dune" {
+			t.Errorf("directory move local path = %q", moves[0].NewLocalPath)
+		}
+		if moves[1].ItemID != "li_hobbit" || moves[1].NewPath != "/audiobooks/J.R.R. Tolkien/The Hobbit/hobbit.m4b" {
+			t.Errorf("single-file move = %+v", moves[1])
+		}
+		if len(unmatched) != 1 || unmatched[0] != "/mnt/media/audiobooks/Incoming/Unknown" {
+			t.Errorf("unmatched = %v", unmatched)
+		}
+	})
+
+	t.Run("renamed tracks", func(t *testing.T) {
+		moves, _ := MapItemMoves(items, nil, []organizer.LogEntry{{
+			SourcePath: "/audiobooks/Incoming/Dune",
+			TargetPath: "/audiobooks/Frank Herbert/Dune",
+			Files: []organizer.FilePair{
+				{From: "dune_01.mp3", To: "01 - Dune.mp3"},
+				{From: "cover.jpg", To: "cover.jpg"},
+			},
+		}})
+		want := []organizer.TrackRename{{From: "dune_01.mp3", To: "01 - Dune.mp3"}}
+		if len(moves) != 1 || !reflect.DeepEqual(moves[0].Tracks, want) {
+			t.Errorf("MapItemMoves() tracks = %+v, want %+v", moves, want)
+		}
+	})
+
+	t.Run("identity paths", func(t *testing.T) {
+		moves, unmatched := MapItemMoves(items, nil, []organizer.LogEntry{{
+			SourcePath: "/audiobooks/Incoming/Dune",
+			TargetPath: "/audiobooks/Frank Herbert/Dune",
+		}})
+		if len(moves) != 1 || moves[0].OldPath != moves[0].OldLocalPath || len(unmatched) != 0 {
+			t.Errorf("MapItemMoves() = %+v, %v", moves, unmatched)
+		}
+	})
+}
+
+func TestWriteItemMoves(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "abs-id-map.json")
+	want := []ItemMove{{ItemID: "li_dune", LibraryID: "lib_main", OldPath: "/a", NewPath: "/b"}}
+	if err := WriteItemMoves(outPath, want); err != nil {
+		t.Fatalf("WriteItemMoves() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []ItemMove
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want[0]) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+
+	if err := WriteItemMoves(outPath, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(outPath); string(data) != "[]\n" {
+		t.Errorf("empty map = %q, want []", data)
+	}
+}
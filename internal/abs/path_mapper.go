@@ -6,12 +6,25 @@ package abs
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
 	_ "modernc.org/sqlite" // Pure Go SQLite driver (no CGO, supports cross-compilation)
 )
 
+// sqliteDriver is the database/sql driver name registered by modernc.org/sqlite
+const sqliteDriver = "sqlite"
+
+// openABSDatabase opens an ABS database read-only
+func openABSDatabase(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open(sqliteDriver, fmt.Sprintf("file:%s?mode=ro", dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("opening ABS database: %w", err)
+	}
+	return db, nil
+}
+
 // PathMapping represents a mapping between ABS and local paths
 type PathMapping struct {
 	ABSPrefix   string // What ABS sees (e.g., "/audiobooks")
@@ -28,45 +41,44 @@ func NewPathMapper(mappings []PathMapping) *PathMapper {
 	return &PathMapper{Mappings: mappings}
 }
 
-// NewPathMapperFromSQLite discovers mappings from ABS SQLite database
+// discoverySampleSize bounds how many items per library folder are checked on disk
+// when looking for the folder's local mount
+const discoverySampleSize = 25
+
+// NewPathMapperFromSQLite discovers mappings from ABS SQLite database. ABS stores
+// each library folder as the path it sees (often a container path such as
+// "/audiobooks") and each item relative to it, so the local mount of a folder is
+// found by checking which candidate directory actually holds the folder's items:
+// the ABS path itself when the organizer shares the host or container volume,
+// userInputPath or one of its parents, or a child of userInputPath named like the
+// folder. Only folders that overlap userInputPath are mapped.
 func NewPathMapperFromSQLite(dbPath string, userInputPath string) (*PathMapper, error) {
-	// Open in read-only mode
-	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	db, err := openABSDatabase(dbPath)
 	if err != nil {
-		return nil, fmt.Errorf("opening ABS database: %w", err)
+		return nil, err
 	}
 	defer db.Close()
 
-	// Query library folders - tables: libraries, libraryFolders, folders
-	rows, err := db.Query(`
-		SELECT
-			l.id as library_id,
-			l.name as library_name,
-			f.path as folder_path,
-			f.fullPath as folder_full_path
-		FROM libraries l
-		JOIN libraryFolders lf ON l.id = lf.libraryId
-		JOIN folders f ON lf.folderId = f.id
-	`)
+	folders, err := queryLibraryFolders(db)
 	if err != nil {
-		return nil, fmt.Errorf("querying library folders: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
+	inputPath := filepath.Clean(userInputPath)
 	var mappings []PathMapping
-	for rows.Next() {
-		var libID, libName, folderPath, folderFullPath string
-		if err := rows.Scan(&libID, &libName, &folderPath, &folderFullPath); err != nil {
-			continue
+	for _, folder := range folders {
+		relPaths, err := queryFolderItemPaths(db, folder.ID)
+		if err != nil {
+			return nil, err
 		}
-
-		// Check if user input path matches this folder
-		if strings.HasPrefix(userInputPath, folderFullPath) {
-			mappings = append(mappings, PathMapping{
-				ABSPrefix:   folderPath,
-				LocalPrefix: folderFullPath,
-			})
+		localPrefix, ok := discoverLocalPrefix(folder.Path, relPaths, inputPath)
+		if !ok || !(pathContains(localPrefix, inputPath) || pathContains(inputPath, localPrefix)) {
+			continue
 		}
+		mappings = append(mappings, PathMapping{
+			ABSPrefix:   folder.Path,
+			LocalPrefix: localPrefix,
+		})
 	}
 
 	if len(mappings) == 0 {
@@ -76,6 +88,90 @@ func NewPathMapperFromSQLite(dbPath string, userInputPath string) (*PathMapper,
 	return &PathMapper{Mappings: mappings}, nil
 }
 
+// discoverLocalPrefix finds the local directory that holds the items of the ABS
+// library folder absPath. The candidate holding the most sampled items wins; an
+// empty folder can only be matched by its own path.
+func discoverLocalPrefix(absPath string, relPaths []string, inputPath string) (string, bool) {
+	candidates := []string{filepath.Clean(absPath)}
+	for dir := inputPath; ; dir = filepath.Dir(dir) {
+		candidates = append(candidates, dir)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	candidates = append(candidates, filepath.Join(inputPath, filepath.Base(absPath)))
+
+	best, bestHits := "", 0
+	for _, candidate := range candidates {
+		hits := 0
+		for _, relPath := range relPaths {
+			if _, err := os.Stat(filepath.Join(candidate, relPath)); err == nil {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = candidate, hits
+		}
+	}
+	if best != "" {
+		return best, true
+	}
+
+	if len(relPaths) == 0 {
+		if info, err := os.Stat(absPath); err == nil && info.IsDir() {
+			return filepath.Clean(absPath), true
+		}
+	}
+	return "", false
+}
+
+// queryLibraryFolders reads every library folder as ABS sees it
+func queryLibraryFolders(db *sql.DB) ([]Folder, error) {
+	rows, err := db.Query(`SELECT id, path, libraryId FROM libraryFolders ORDER BY path`)
+	if err != nil {
+		return nil, fmt.Errorf("querying library folders: %w", err)
+	}
+	defer rows.Close()
+
+	var folders []Folder
+	for rows.Next() {
+		var f Folder
+		if err := rows.Scan(&f.ID, &f.Path, &f.LibraryID); err != nil {
+			continue
+		}
+		folders = append(folders, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading library folders: %w", err)
+	}
+	return folders, nil
+}
+
+// queryFolderItemPaths samples the item paths of a library folder, relative to it
+func queryFolderItemPaths(db *sql.DB, folderID string) ([]string, error) {
+	rows, err := db.Query(
+		`SELECT relPath FROM libraryItems WHERE libraryFolderId = ? AND relPath != '' LIMIT ?`,
+		folderID, discoverySampleSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying library items: %w", err)
+	}
+	defer rows.Close()
+
+	var relPaths []string
+	for rows.Next() {
+		var relPath string
+		if err := rows.Scan(&relPath); err != nil {
+			continue
+		}
+		relPaths = append(relPaths, filepath.FromSlash(relPath))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading library items: %w", err)
+	}
+	return relPaths, nil
+}
+
 // ToLocal converts an ABS path to a local path
 func (pm *PathMapper) ToLocal(absPath string) string {
 	for _, m := range pm.Mappings {
@@ -123,32 +219,13 @@ func ParsePathMapping(s string) (PathMapping, error) {
 	}, nil
 }
 
-// ListLibraries returns all library paths from SQLite (for debugging)
+// ListLibraries returns all library folders from SQLite as ABS sees them (for debugging)
 func ListLibraries(dbPath string) ([]Folder, error) {
-	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	db, err := openABSDatabase(dbPath)
 	if err != nil {
-		return nil, fmt.Errorf("opening ABS database: %w", err)
+		return nil, err
 	}
 	defer db.Close()
 
-	rows, err := db.Query(`
-		SELECT f.id, f.path, f.fullPath
-		FROM folders f
-		JOIN libraryFolders lf ON f.id = lf.folderId
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("querying folders: %w", err)
-	}
-	defer rows.Close()
-
-	var folders []Folder
-	for rows.Next() {
-		var f Folder
-		if err := rows.Scan(&f.ID, &f.Path, &f.FullPath); err != nil {
-			continue
-		}
-		folders = append(folders, f)
-	}
-
-	return folders, nil
+	return queryLibraryFolders(db)
 }
@@ -4,7 +4,10 @@
 package abs
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -116,21 +119,116 @@ func TestPathMapper_ToABS(t *testing.T) {
 	}
 }
 
+// writeTestLibraryDatabase creates an abs.sqlite with one library folder at absPath
+// holding items at the given paths relative to it
+func writeTestLibraryDatabase(t *testing.T, absPath string, relPaths ...string) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "abs.sqlite")
+	db, err := sql.Open(sqliteDriver, dbPath)
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE libraryFolders (id TEXT PRIMARY KEY, path TEXT, libraryId TEXT)`,
+		`CREATE TABLE libraryItems (id TEXT PRIMARY KEY, path TEXT, relPath TEXT, libraryId TEXT, libraryFolderId TEXT)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("creating schema: %v", err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO libraryFolders VALUES ('fol_1', ?, 'lib_main')`, absPath); err != nil {
+		t.Fatal(err)
+	}
+	for i, relPath := range relPaths {
+		if _, err := db.Exec(
+			`INSERT INTO libraryItems VALUES (?, ?, ?, 'lib_main', 'fol_1')`,
+			fmt.Sprintf("li_%d", i), absPath+"/"+relPath, relPath,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dbPath
+}
+
 func TestNewPathMapperFromSQLite(t *testing.T) {
-	// Create a test SQLite database
-	tmpDir := t.TempDir()
-	_ = tmpDir // Will be used when we add SQLite test
+	// Local mount of the library, as seen outside the ABS container
+	mount := filepath.Join(t.TempDir(), "media", "audiobooks")
+	for _, dir := range []string{"Frank Herbert/Dune", "Andy Weir/The Martian", "Incoming"} {
+		if err := os.MkdirAll(filepath.Join(mount, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	containerDB := writeTestLibraryDatabase(t, "/audiobooks", "Frank Herbert/Dune", "Andy Weir/The Martian")
 
-	// Create minimal schema and data
-	// Note: This requires the mattn/go-sqlite3 package
-	// Skip if not available
-	if os.Getenv("SKIP_SQLITE_TESTS") != "" {
-		t.Skip("Skipping SQLite tests")
+	tests := []struct {
+		name      string
+		dbPath    string
+		inputPath string
+		want      PathMapping
+		wantErr   bool
+	}{
+		{
+			name:      "input is the mount",
+			dbPath:    containerDB,
+			inputPath: mount,
+			want:      PathMapping{ABSPrefix: "/audiobooks", LocalPrefix: mount},
+		},
+		{
+			name:      "input inside the mount",
+			dbPath:    containerDB,
+			inputPath: filepath.Join(mount, "Incoming"),
+			want:      PathMapping{ABSPrefix: "/audiobooks", LocalPrefix: mount},
+		},
+		{
+			name:      "input above the mount",
+			dbPath:    containerDB,
+			inputPath: filepath.Dir(mount),
+			want:      PathMapping{ABSPrefix: "/audiobooks", LocalPrefix: mount},
+		},
+		{
+			name:      "same volume as ABS",
+			dbPath:    writeTestLibraryDatabase(t, mount, "Frank Herbert/Dune"),
+			inputPath: filepath.Join(mount, "Incoming"),
+			want:      PathMapping{ABSPrefix: mount, LocalPrefix: mount},
+		},
+		{
+			name:      "unrelated input",
+			dbPath:    containerDB,
+			inputPath: t.TempDir(),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := NewPathMapperFromSQLite(tt.dbPath, tt.inputPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewPathMapperFromSQLite() = %+v, want error", mapper.Mappings)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPathMapperFromSQLite() error = %v", err)
+			}
+			if len(mapper.Mappings) != 1 || mapper.Mappings[0] != tt.want {
+				t.Errorf("Mappings = %+v, want [%+v]", mapper.Mappings, tt.want)
+			}
+		})
 	}
+}
 
-	// This test would require creating a real SQLite DB
-	// For unit tests, we mock the behavior
-	t.Skip("SQLite integration test requires real database - tested via integration tests")
+func TestListLibraries(t *testing.T) {
+	folders, err := ListLibraries(writeTestLibraryDatabase(t, "/audiobooks"))
+	if err != nil {
+		t.Fatalf("ListLibraries() error = %v", err)
+	}
+	if len(folders) != 1 || folders[0].Path != "/audiobooks" || folders[0].LibraryID != "lib_main" {
+		t.Errorf("ListLibraries() = %+v", folders)
+	}
 }
 
 func TestPathMapper_Empty(t *testing.T) {
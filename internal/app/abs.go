@@ -82,6 +82,9 @@ func (s *Service) NewABSClient(cfg ABSConfigDTO) (*abs.Client, error) {
 	if strings.TrimSpace(cfg.Token) == "" {
 		return nil, fmt.Errorf("abs token is required")
 	}
+	if err := s.checkABSRoots(cfg); err != nil {
+		return nil, err
+	}
 
 	client := abs.NewClient(cfg.URL, cfg.Token)
 	if cfg.HeaderFile != "" {
@@ -122,6 +125,10 @@ func (s *Service) TestABSPathMappings(
 	default:
 	}
 
+	if err := s.checkABSRoots(req.Config, req.InputDir); err != nil {
+		return nil, err
+	}
+
 	var mappings []abs.PathMapping
 	if req.Config.SQLitePath != "" {
 		mapper, err := abs.NewPathMapperFromSQLite(req.Config.SQLitePath, req.InputDir)
@@ -260,6 +267,10 @@ func (s *Service) newABSProviderForInput(
 	cfg ABSConfigDTO,
 	inputPath string,
 ) (*abs.MetadataProvider, error) {
+	if err := s.checkABSRoots(cfg, inputPath); err != nil {
+		return nil, err
+	}
+
 	libraryID := cfg.LibraryID
 	if libraryID == "" {
 		libraryID = "main"
@@ -304,6 +315,16 @@ func (s *Service) newABSProviderForInput(
 	return provider, nil
 }
 
+// checkABSRoots checks the local files and folders an ABS configuration reads, and
+// any extra paths, against the allowed roots
+func (s *Service) checkABSRoots(cfg ABSConfigDTO, extra ...string) error {
+	paths := append([]string{cfg.SQLitePath, cfg.HeaderFile}, extra...)
+	for _, mapping := range cfg.PathMappings {
+		paths = append(paths, mapping.LocalPrefix)
+	}
+	return s.checkRoots(paths...)
+}
+
 func pathMappingsToDTO(mappings []abs.PathMapping) []PathMappingDTO {
 	result := make([]PathMappingDTO, 0, len(mappings))
 	for _, mapping := range mappings {
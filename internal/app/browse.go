@@ -0,0 +1,250 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// ErrOutsideRoots reports a path outside the directories the web UI may access.
+var ErrOutsideRoots = errors.New("path is outside the allowed roots")
+
+// DirectoryListRequest requests the subdirectories of one local directory.
+type DirectoryListRequest struct {
+	Path       string `json:"path"`
+	ShowHidden bool   `json:"show_hidden,omitempty"`
+}
+
+// DirectoryListResponse lists the subdirectories of a directory. An empty Path lists
+// the allowed roots themselves.
+type DirectoryListResponse struct {
+	Path      string           `json:"path"`
+	Parent    string           `json:"parent,omitempty"`
+	Entries   []DirectoryEntry `json:"entries"`
+	FreeBytes uint64           `json:"free_bytes,omitempty"`
+}
+
+// DirectoryEntry is one directory shown in the browser.
+type DirectoryEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// CreateDirectoryRequest requests a new directory named Name inside Parent.
+type CreateDirectoryRequest struct {
+	Parent string `json:"parent"`
+	Name   string `json:"name"`
+}
+
+// FreeSpaceRequest requests the free space of the file system holding Path.
+type FreeSpaceRequest struct {
+	Path string `json:"path"`
+}
+
+// FreeSpaceResponse reports free space; Supported is false on platforms that don't
+// report it.
+type FreeSpaceResponse struct {
+	Path      string `json:"path"`
+	FreeBytes uint64 `json:"free_bytes"`
+	Supported bool   `json:"supported"`
+}
+
+// ListDirectories returns the subdirectories of a directory within the allowed roots.
+func (s *Service) ListDirectories(
+	ctx context.Context,
+	req DirectoryListRequest,
+) (*DirectoryListResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	path := strings.TrimSpace(req.Path)
+	if path == "" {
+		roots := s.browseRoots()
+		if len(roots) != 1 {
+			entries := make([]DirectoryEntry, 0, len(roots))
+			for _, root := range roots {
+				entries = append(entries, DirectoryEntry{Name: root, Path: root})
+			}
+			return &DirectoryListResponse{Entries: entries}, nil
+		}
+		path = roots[0]
+	}
+
+	path, err := s.allowedPath(path)
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %s: %w", path, err)
+	}
+
+	entries := []DirectoryEntry{}
+	for _, entry := range dirEntries {
+		if !req.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		entryPath := filepath.Join(path, entry.Name())
+		// Follow symlinks so linked library folders can be browsed too
+		if info, err := os.Stat(entryPath); err != nil || !info.IsDir() {
+			continue
+		}
+		entries = append(entries, DirectoryEntry{Name: entry.Name(), Path: entryPath})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+
+	free, _ := organizer.LocalFreeSpace(path)
+	return &DirectoryListResponse{
+		Path:      path,
+		Parent:    s.browseParent(path),
+		Entries:   entries,
+		FreeBytes: free,
+	}, nil
+}
+
+// CreateDirectory creates a directory within the allowed roots, such as a new output
+// folder picked in the browser.
+func (s *Service) CreateDirectory(
+	ctx context.Context,
+	req CreateDirectoryRequest,
+) (*DirectoryEntry, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid directory name %q", req.Name)
+	}
+	parent, err := s.allowedPath(req.Parent)
+	if err != nil {
+		return nil, err
+	}
+	if msg := validateExistingDirectory(parent); msg != "" {
+		return nil, errors.New(msg)
+	}
+	path := filepath.Join(parent, name)
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory %s: %w", path, err)
+	}
+	return &DirectoryEntry{Name: name, Path: path}, nil
+}
+
+// FreeSpace reports the free space of the file system that holds, or will hold, a path
+// within the allowed roots.
+func (s *Service) FreeSpace(ctx context.Context, req FreeSpaceRequest) (*FreeSpaceResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	path, err := s.allowedPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	free, err := organizer.LocalFreeSpace(path)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return &FreeSpaceResponse{Path: path}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading free space of %s: %w", path, err)
+	}
+	return &FreeSpaceResponse{Path: path, FreeBytes: free, Supported: true}, nil
+}
+
+// browseRoots returns the configured roots, or the home directory when the web UI
+// may access any path.
+func (s *Service) browseRoots() []string {
+	if len(s.config.Roots) > 0 {
+		return s.config.Roots
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return []string{home}
+	}
+	return []string{string(filepath.Separator)}
+}
+
+// browseParent returns the directory above path, or "" at an allowed root or at the
+// top of the file system.
+func (s *Service) browseParent(path string) string {
+	parent := filepath.Dir(path)
+	if parent == path {
+		return ""
+	}
+	for _, root := range s.config.Roots {
+		if filepath.Clean(root) == path {
+			return ""
+		}
+	}
+	if _, err := s.allowedPath(parent); err != nil {
+		return ""
+	}
+	return parent
+}
+
+// allowedPath cleans path and checks that it lies within the configured roots,
+// following symlinks so a link inside a root can't reach outside it. Any path is
+// allowed when no roots are configured.
+func (s *Service) allowedPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("path is required")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if len(s.config.Roots) == 0 {
+		return path, nil
+	}
+
+	resolved := resolveExisting(path)
+	for _, root := range s.config.Roots {
+		if isPathWithin(resolveExisting(filepath.Clean(root)), resolved) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrOutsideRoots, path)
+}
+
+// checkRoots checks that every non-empty path lies within the configured roots
+func (s *Service) checkRoots(paths ...string) error {
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		if _, err := s.allowedPath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveExisting resolves the symlinks of the deepest existing part of path and
+// appends the rest, so paths that don't exist yet can still be checked.
+func resolveExisting(path string) string {
+	var rest []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		if filepath.Dir(dir) == dir {
+			return path
+		}
+		rest = append([]string{filepath.Base(dir)}, rest...)
+	}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListDirectoriesReturnsSortedSubdirectories(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"beta", "Alpha", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "book.mp3"), []byte("audio"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	service := newRootedService(root)
+
+	resp, err := service.ListDirectories(context.Background(), DirectoryListRequest{Path: root})
+	if err != nil {
+		t.Fatalf("ListDirectories() error = %v", err)
+	}
+	if len(resp.Entries) != 2 || resp.Entries[0].Name != "Alpha" || resp.Entries[1].Name != "beta" {
+		t.Fatalf("entries = %+v, want Alpha and beta", resp.Entries)
+	}
+	if resp.Parent != "" {
+		t.Fatalf("parent of a root = %q, want none", resp.Parent)
+	}
+
+	resp, err = service.ListDirectories(context.Background(), DirectoryListRequest{Path: "", ShowHidden: true})
+	if err != nil {
+		t.Fatalf("ListDirectories() error = %v", err)
+	}
+	if resp.Path != root || len(resp.Entries) != 3 {
+		t.Fatalf("listing of the only root = %+v, want %s with 3 entries", resp, root)
+	}
+
+	resp, err = service.ListDirectories(context.Background(), DirectoryListRequest{Path: filepath.Join(root, "beta")})
+	if err != nil {
+		t.Fatalf("ListDirectories() error = %v", err)
+	}
+	if resp.Parent != root {
+		t.Fatalf("parent = %q, want %q", resp.Parent, root)
+	}
+}
+
+func TestAllowedRootsRejectOutsidePathsAndSymlinkEscapes(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	link := filepath.Join(root, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	service := newRootedService(root)
+
+	for _, path := range []string{outside, filepath.Join(root, ".."), link, filepath.Join(link, "new")} {
+		_, err := service.ListDirectories(context.Background(), DirectoryListRequest{Path: path})
+		if !errors.Is(err, ErrOutsideRoots) {
+			t.Errorf("ListDirectories(%s) error = %v, want ErrOutsideRoots", path, err)
+		}
+	}
+
+	resp, err := service.ValidatePaths(context.Background(), PathValidationRequest{
+		Paths: []PathValidationItem{{ID: "outside", Path: outside, Kind: "existing-directory"}},
+	})
+	if err != nil {
+		t.Fatalf("ValidatePaths() error = %v", err)
+	}
+	assertPathValidation(t, resp, "outside", false, "Path is outside the allowed roots:")
+
+	_, err = service.PreviewOrganize(context.Background(), OrganizeRequest{
+		Config: OrganizerConfigDTO{BaseDir: root, OutputDir: outside},
+	})
+	if !errors.Is(err, ErrOutsideRoots) {
+		t.Fatalf("PreviewOrganize() error = %v, want ErrOutsideRoots", err)
+	}
+}
+
+func TestAllowedRootsCoverABSLocalPaths(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	service := newRootedService(root)
+	base := ABSConfigDTO{URL: "http://abs.invalid", Token: "token"}
+
+	sqlite := base
+	sqlite.SQLitePath = filepath.Join(outside, "absdatabase.sqlite")
+	_, err := service.TestABSPathMappings(context.Background(), ABSPathMappingRequest{Config: sqlite, InputDir: root})
+	if !errors.Is(err, ErrOutsideRoots) {
+		t.Fatalf("TestABSPathMappings() with an outside database error = %v, want ErrOutsideRoots", err)
+	}
+
+	headers := base
+	headers.HeaderFile = filepath.Join(outside, "headers.txt")
+	if _, err := service.NewABSClient(headers); !errors.Is(err, ErrOutsideRoots) {
+		t.Fatalf("NewABSClient() with an outside header file error = %v, want ErrOutsideRoots", err)
+	}
+
+	mapped := base
+	mapped.PathMappings = []PathMappingDTO{{ABSPrefix: "/audiobooks", LocalPrefix: outside}}
+	_, err = service.LoadABSItems(context.Background(), ABSItemsRequest{Config: mapped})
+	if !errors.Is(err, ErrOutsideRoots) {
+		t.Fatalf("LoadABSItems() with an outside mapping error = %v, want ErrOutsideRoots", err)
+	}
+}
+
+func TestCreateDirectoryMakesOneDirectoryInsideRoots(t *testing.T) {
+	root := t.TempDir()
+	service := newRootedService(root)
+
+	entry, err := service.CreateDirectory(context.Background(), CreateDirectoryRequest{Parent: root, Name: "Library"})
+	if err != nil {
+		t.Fatalf("CreateDirectory() error = %v", err)
+	}
+	if info, err := os.Stat(entry.Path); err != nil || !info.IsDir() {
+		t.Fatalf("created directory %s missing: %v", entry.Path, err)
+	}
+
+	for _, name := range []string{"", "..", "a/b"} {
+		if _, err := service.CreateDirectory(context.Background(), CreateDirectoryRequest{Parent: root, Name: name}); err == nil {
+			t.Errorf("CreateDirectory(%q) succeeded, want an error", name)
+		}
+	}
+	_, err = service.CreateDirectory(context.Background(), CreateDirectoryRequest{Parent: t.TempDir(), Name: "x"})
+	if !errors.Is(err, ErrOutsideRoots) {
+		t.Fatalf("CreateDirectory() outside roots error = %v, want ErrOutsideRoots", err)
+	}
+}
+
+func TestFreeSpaceReportsMissingDirectoriesByTheirParent(t *testing.T) {
+	root := t.TempDir()
+	service := newRootedService(root)
+
+	resp, err := service.FreeSpace(context.Background(), FreeSpaceRequest{Path: filepath.Join(root, "not", "yet")})
+	if err != nil {
+		t.Fatalf("FreeSpace() error = %v", err)
+	}
+	if resp.Supported && resp.FreeBytes == 0 {
+		t.Fatalf("FreeSpace() = %+v, want free bytes", resp)
+	}
+}
+
+func newRootedService(roots ...string) *Service {
+	cfg := DefaultWebConfig("127.0.0.1", 0, false, "", "")
+	cfg.Roots = roots
+	return NewService(cfg)
+}
@@ -12,10 +12,14 @@ type WebConfig struct {
 	Host      string             `json:"host"`
 	Port      int                `json:"port"`
 	Open      bool               `json:"open"`
+	ReadOnly  bool               `json:"read_only"`
+	Roots     []string           `json:"roots,omitempty"`
 	Initial   InitialConfigDTO   `json:"initial"`
 	Organizer OrganizerConfigDTO `json:"organizer"`
 	Rename    RenameConfigDTO    `json:"rename"`
 	ABS       ABSConfigDTO       `json:"abs"`
+	Language  string             `json:"language,omitempty"` // Language of Messages; "" leaves the UI in English
+	Messages  map[string]string  `json:"messages,omitempty"` // Translated UI text by message key
 }
 
 // InitialConfigDTO contains initial path values passed from the CLI.
@@ -37,6 +41,8 @@ type OrganizerConfigDTO struct {
 	Layout              string          `json:"layout"`
 	LayoutTemplate      string          `json:"layout_template"`
 	AuthorFormat        string          `json:"author_format"`
+	Casing              string          `json:"casing,omitempty"`
+	StripTitlePrefix    bool            `json:"strip_title_prefix,omitempty"`
 	FieldMapping        FieldMappingDTO `json:"field_mapping"`
 	AllowedSourcePaths  []string        `json:"allowed_source_paths,omitempty"`
 	MetadataSource      string          `json:"metadata_source,omitempty"`
@@ -120,6 +126,8 @@ func (d OrganizerConfigDTO) ToOrganizerConfig() organizer.OrganizerConfig {
 		Layout:              d.Layout,
 		LayoutTemplate:      d.LayoutTemplate,
 		AuthorFormat:        d.AuthorFormat,
+		Casing:              d.Casing,
+		StripTitlePrefix:    d.StripTitlePrefix,
 		FieldMapping:        d.FieldMapping.ToFieldMapping(),
 		AllowedSourcePaths:  d.AllowedSourcePaths,
 	}
@@ -15,6 +15,9 @@ import (
 // OrganizeRequest requests an organization preview or execution.
 type OrganizeRequest struct {
 	Config OrganizerConfigDTO `json:"config"`
+	// ApprovedMoves limits a run to these book moves from a preview. The run is
+	// refused when the library no longer plans exactly these moves.
+	ApprovedMoves []organizer.MoveSummary `json:"approved_moves,omitempty"`
 }
 
 const metadataSourceABS = "abs"
@@ -22,6 +25,7 @@ const metadataSourceABS = "abs"
 // OrganizePreviewResponse contains a dry-run organization summary.
 type OrganizePreviewResponse struct {
 	Summary organizer.Summary `json:"summary"`
+	Plan    []PlanBook        `json:"plan"`
 	LogPath string            `json:"log_path,omitempty"`
 }
 
@@ -46,10 +50,13 @@ func (s *Service) PreviewOrganize(
 	if err != nil {
 		return nil, err
 	}
-	return &OrganizePreviewResponse{Summary: org.GetSummary()}, nil
+	summary := org.GetSummary()
+	return &OrganizePreviewResponse{Summary: summary, Plan: buildPlan(summary)}, nil
 }
 
-// RunOrganize runs the organizer with filesystem mutations enabled.
+// RunOrganize runs the organizer with filesystem mutations enabled. The web server
+// has no job queue, so the run completes within the request like every other
+// endpoint; approved moves are checked against a fresh dry run first.
 func (s *Service) RunOrganize(
 	ctx context.Context,
 	req OrganizeRequest,
@@ -60,6 +67,20 @@ func (s *Service) RunOrganize(
 	default:
 	}
 
+	if len(req.ApprovedMoves) > 0 {
+		req.Config.AllowedSourcePaths = make([]string, 0, len(req.ApprovedMoves))
+		for _, move := range req.ApprovedMoves {
+			req.Config.AllowedSourcePaths = append(req.Config.AllowedSourcePaths, move.From)
+		}
+		check, err := s.executeOrganize(req, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkApprovedMoves(req.ApprovedMoves, check.GetSummary().Moves); err != nil {
+			return nil, err
+		}
+	}
+
 	org, err := s.executeOrganize(req, false)
 	if err != nil {
 		return nil, err
@@ -68,8 +89,17 @@ func (s *Service) RunOrganize(
 }
 
 func (s *Service) executeOrganize(req OrganizeRequest, dryRun bool) (*organizer.Organizer, error) {
+	paths := []string{req.Config.BaseDir}
+	if !organizer.IsRemoteOutput(req.Config.OutputDir) {
+		paths = append(paths, req.Config.OutputDir)
+	}
+	if err := s.checkRoots(paths...); err != nil {
+		return nil, err
+	}
 	config := req.Config.ToOrganizerConfig()
 	config.DryRun = dryRun
+	// Previews always check author spellings so the review step can suggest merges
+	config.CheckAuthors = config.CheckAuthors || dryRun
 	org, err := organizer.NewOrganizer(&config)
 	if err != nil {
 		return nil, err
@@ -84,6 +114,7 @@ func (s *Service) executeOrganize(req OrganizeRequest, dryRun bool) (*organizer.
 	if err := org.ResolvePaths(); err != nil {
 		return nil, err
 	}
+	defer org.Close()
 	provider, err := s.newABSProviderForInput(req.Config.ABS, org.BaseDir())
 	if err != nil {
 		return nil, err
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 )
 
 // PathValidationRequest requests non-mutating local path validation.
@@ -27,13 +29,16 @@ type PathValidationResponse struct {
 
 // PathValidationResult reports whether a local path is usable.
 type PathValidationResult struct {
-	ID    string `json:"id"`
-	Path  string `json:"path"`
-	Valid bool   `json:"valid"`
-	Error string `json:"error,omitempty"`
+	ID        string `json:"id"`
+	Path      string `json:"path"`
+	Valid     bool   `json:"valid"`
+	Error     string `json:"error,omitempty"`
+	FreeBytes uint64 `json:"free_bytes,omitempty"`
 }
 
-// ValidatePaths checks local workflow paths without creating directories.
+// ValidatePaths checks local workflow paths without creating directories. Paths
+// outside the allowed roots are invalid, and usable output directories report the
+// free space of their file system.
 func (s *Service) ValidatePaths(
 	ctx context.Context,
 	req PathValidationRequest,
@@ -46,12 +51,12 @@ func (s *Service) ValidatePaths(
 
 	results := make([]PathValidationResult, 0, len(req.Paths))
 	for _, path := range req.Paths {
-		results = append(results, validatePath(path))
+		results = append(results, s.validatePath(path))
 	}
 	return &PathValidationResponse{Results: results}, nil
 }
 
-func validatePath(item PathValidationItem) PathValidationResult {
+func (s *Service) validatePath(item PathValidationItem) PathValidationResult {
 	result := PathValidationResult{
 		ID:   item.ID,
 		Path: strings.TrimSpace(item.Path),
@@ -60,6 +65,10 @@ func validatePath(item PathValidationItem) PathValidationResult {
 		result.Error = "Path is required."
 		return result
 	}
+	if err := s.checkRoots(result.Path); err != nil {
+		result.Error = fmt.Sprintf("Path is outside the allowed roots: %s", result.Path)
+		return result
+	}
 
 	switch item.Kind {
 	case "output-directory":
@@ -68,6 +77,9 @@ func validatePath(item PathValidationItem) PathValidationResult {
 		result.Error = validateExistingDirectory(result.Path)
 	}
 	result.Valid = result.Error == ""
+	if result.Valid && item.Kind == "output-directory" {
+		result.FreeBytes, _ = organizer.LocalFreeSpace(result.Path)
+	}
 	return result
 }
 
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// ErrPlanChanged reports that a run would not make the moves approved in the preview.
+var ErrPlanChanged = errors.New("the plan changed since the preview; preview again before running")
+
+// PlanBook is one planned move of an organize preview with the files it carries.
+type PlanBook struct {
+	Source    string                  `json:"source"`
+	Target    string                  `json:"target"`
+	Files     []organizer.MoveSummary `json:"files"`
+	Conflicts []string                `json:"conflicts,omitempty"`
+	Warnings  []string                `json:"warnings,omitempty"`
+}
+
+// buildPlan groups the file moves of a dry run under the book moves they belong to
+// and flags targets that clash or already exist, so the web UI can show the plan as
+// a tree and let the user exclude single books before running it.
+func buildPlan(summary organizer.Summary) []PlanBook {
+	books := make([]PlanBook, 0, len(summary.Moves))
+	for _, move := range summary.Moves {
+		books = append(books, PlanBook{Source: move.From, Target: move.To, Files: []organizer.MoveSummary{}})
+	}
+
+	targets := make(map[string]int)
+	for _, move := range summary.FileMoves {
+		targets[move.To]++
+		if i := planBookIndex(books, move.From); i >= 0 {
+			books[i].Files = append(books[i].Files, move)
+		}
+	}
+
+	for i := range books {
+		book := &books[i]
+		for _, file := range book.Files {
+			if targets[file.To] > 1 {
+				book.Conflicts = append(book.Conflicts, fmt.Sprintf("%s is also the target of another book", filepath.Base(file.To)))
+			} else if file.To != file.From {
+				if _, err := os.Lstat(file.To); err == nil {
+					book.Conflicts = append(book.Conflicts, fmt.Sprintf("%s already exists in the output", filepath.Base(file.To)))
+				}
+			}
+		}
+		for _, path := range summary.MetadataMissing {
+			if isPathWithin(book.Source, path) {
+				book.Warnings = append(book.Warnings, "no metadata found for "+filepath.Base(path))
+			}
+		}
+		for _, low := range summary.LowConfidence {
+			if isPathWithin(book.Source, low.Path) {
+				book.Warnings = append(book.Warnings, "low metadata confidence: "+strings.Join(low.Reasons, ", "))
+			}
+		}
+		for _, message := range summary.Errors {
+			if strings.Contains(message, book.Source) {
+				book.Warnings = append(book.Warnings, message)
+			}
+		}
+	}
+
+	sort.SliceStable(books, func(i, j int) bool {
+		return books[i].Target < books[j].Target
+	})
+	return books
+}
+
+// planBookIndex returns the book whose source holds path, preferring the deepest
+// source, or -1 when no book does
+func planBookIndex(books []PlanBook, path string) int {
+	found := -1
+	for i, book := range books {
+		if isPathWithin(book.Source, path) && (found < 0 || len(book.Source) > len(books[found].Source)) {
+			found = i
+		}
+	}
+	return found
+}
+
+// checkApprovedMoves compares the moves a run would make with the moves approved in
+// the preview, so a library that changed in between is never organized blindly
+func checkApprovedMoves(approved, planned []organizer.MoveSummary) error {
+	want := make(map[organizer.MoveSummary]bool, len(approved))
+	for _, move := range approved {
+		want[move] = true
+	}
+	if len(planned) != len(want) {
+		return fmt.Errorf("%w (%d approved moves, %d planned now)", ErrPlanChanged, len(want), len(planned))
+	}
+	for _, move := range planned {
+		if !want[move] {
+			return fmt.Errorf("%w (%s would now go to %s)", ErrPlanChanged, move.From, move.To)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+func TestPreviewOrganizeGroupsFilesIntoPlanBooks(t *testing.T) {
+	service := NewService(DefaultWebConfig("127.0.0.1", 0, false, "", ""))
+	inputDir, outputDir := createOrganizeFixture(t)
+	writeFile(t, filepath.Join(inputDir, "test_book", "cover.jpg"), "fake cover")
+
+	preview, err := service.PreviewOrganize(context.Background(), OrganizeRequest{
+		Config: organizeTestConfig(inputDir, outputDir, true),
+	})
+	if err != nil {
+		t.Fatalf("PreviewOrganize() error = %v", err)
+	}
+	if len(preview.Plan) != 1 {
+		t.Fatalf("plan books = %d, want 1", len(preview.Plan))
+	}
+	book := preview.Plan[0]
+	if book.Source != mustResolvePath(t, filepath.Join(inputDir, "test_book")) {
+		t.Fatalf("book source = %s, want the test book", book.Source)
+	}
+	if got := len(book.Files); got < 2 {
+		t.Fatalf("book files = %d, want audio and cover", got)
+	}
+	if len(book.Conflicts) != 0 || len(book.Warnings) != 0 {
+		t.Fatalf("clean book flagged: conflicts %v, warnings %v", book.Conflicts, book.Warnings)
+	}
+}
+
+func TestBuildPlanFlagsSharedAndExistingTargets(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "out", "Author", "Book", "existing.mp3")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("create output fixture: %v", err)
+	}
+	writeFile(t, existing, "already there")
+
+	shared := filepath.Join(root, "out", "Author", "Book", "01.mp3")
+	summary := organizer.Summary{
+		Moves: []organizer.MoveSummary{
+			{From: filepath.Join(root, "in", "a"), To: filepath.Join(root, "out", "Author", "Book")},
+			{From: filepath.Join(root, "in", "b"), To: filepath.Join(root, "out", "Author", "Book")},
+		},
+		FileMoves: []organizer.MoveSummary{
+			{From: filepath.Join(root, "in", "a", "01.mp3"), To: shared},
+			{From: filepath.Join(root, "in", "b", "01.mp3"), To: shared},
+			{From: filepath.Join(root, "in", "b", "existing.mp3"), To: existing},
+		},
+		MetadataMissing: []string{filepath.Join(root, "in", "b")},
+	}
+
+	plan := buildPlan(summary)
+
+	if len(plan) != 2 {
+		t.Fatalf("plan books = %d, want 2", len(plan))
+	}
+	if got := len(plan[0].Conflicts); got != 1 {
+		t.Fatalf("book a conflicts = %v, want the shared target", plan[0].Conflicts)
+	}
+	if got := len(plan[1].Conflicts); got != 2 {
+		t.Fatalf("book b conflicts = %v, want the shared and existing targets", plan[1].Conflicts)
+	}
+	if len(plan[0].Warnings) != 0 || len(plan[1].Warnings) != 1 {
+		t.Fatalf("warnings = %v / %v, want only book b warned", plan[0].Warnings, plan[1].Warnings)
+	}
+}
+
+func TestRunOrganizeRunsOnlyApprovedMoves(t *testing.T) {
+	service := NewService(DefaultWebConfig("127.0.0.1", 0, false, "", ""))
+	inputDir, outputDir := createOrganizeFixture(t)
+	otherDir := filepath.Join(inputDir, "other_book")
+	if err := os.MkdirAll(otherDir, 0o755); err != nil {
+		t.Fatalf("create other book: %v", err)
+	}
+	writeFile(t, filepath.Join(otherDir, "metadata.json"), `{"title":"Other Book","authors":["Other Author"]}`)
+	writeFile(t, filepath.Join(otherDir, "other.mp3"), "fake audio")
+
+	preview, err := service.PreviewOrganize(context.Background(), OrganizeRequest{
+		Config: organizeTestConfig(inputDir, outputDir, true),
+	})
+	if err != nil {
+		t.Fatalf("PreviewOrganize() error = %v", err)
+	}
+	var approved []organizer.MoveSummary
+	for _, book := range preview.Plan {
+		if filepath.Base(book.Source) == "test_book" {
+			approved = append(approved, organizer.MoveSummary{From: book.Source, To: book.Target})
+		}
+	}
+	if len(approved) != 1 {
+		t.Fatalf("approved moves = %v, want the test book", approved)
+	}
+
+	stale := []organizer.MoveSummary{{From: approved[0].From, To: filepath.Join(outputDir, "Elsewhere")}}
+	_, err = service.RunOrganize(context.Background(), OrganizeRequest{
+		Config:        organizeTestConfig(inputDir, outputDir, false),
+		ApprovedMoves: stale,
+	})
+	if !errors.Is(err, ErrPlanChanged) {
+		t.Fatalf("RunOrganize() with a stale plan error = %v, want ErrPlanChanged", err)
+	}
+	assertFileExists(t, filepath.Join(inputDir, "test_book", "audio.mp3"))
+
+	if _, err := service.RunOrganize(context.Background(), OrganizeRequest{
+		Config:        organizeTestConfig(inputDir, outputDir, false),
+		ApprovedMoves: approved,
+	}); err != nil {
+		t.Fatalf("RunOrganize() error = %v", err)
+	}
+	assertFileExists(t, filepath.Join(outputDir, "App Author", "App Test Book", "audio.mp3"))
+	assertFileExists(t, filepath.Join(otherDir, "other.mp3"))
+}
@@ -86,6 +86,9 @@ func (s *Service) RunRename(
 }
 
 func (s *Service) newRenamer(req RenameRequest, dryRun bool) (*organizer.Renamer, error) {
+	if err := s.checkRoots(req.Config.BaseDir); err != nil {
+		return nil, err
+	}
 	config := req.Config.ToRenamerConfig()
 	config.DryRun = dryRun
 	config.PromptEnabled = false
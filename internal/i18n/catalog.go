@@ -0,0 +1,141 @@
+// Package i18n holds the translations of the text the CLI, TUI, and web UI show.
+// Each language is a flat JSON file in messages/ mapping a message key to a
+// fmt format string, so translations can be contributed without touching code.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language every key is defined in and the fallback for
+// keys a translation is missing
+const DefaultLanguage = "en"
+
+//go:embed messages/*.json
+var messageFiles embed.FS
+
+// catalogs holds the parsed message files by language code
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := messageFiles.ReadDir("messages")
+	if err != nil {
+		panic(err)
+	}
+	result := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := messageFiles.ReadFile(path.Join("messages", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", entry.Name(), err))
+		}
+		result[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return result
+}
+
+// Languages returns the codes of the available languages in sorted order
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Catalog translates message keys into one language. A nil *Catalog is English.
+type Catalog struct {
+	language string
+	messages map[string]string
+}
+
+// New returns the catalog for a language code such as "de", "de-DE", or
+// "de_DE.UTF-8". An empty code is English.
+func New(language string) (*Catalog, error) {
+	code := baseLanguage(language)
+	if code == "" {
+		code = DefaultLanguage
+	}
+	messages, ok := catalogs[code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q (available: %s)", language, strings.Join(Languages(), ", "))
+	}
+	return &Catalog{language: code, messages: messages}, nil
+}
+
+// Detect picks the language to use: the explicit choice when set, otherwise the
+// first of LC_ALL, LC_MESSAGES, and LANG that names an available language, and
+// English when none does
+func Detect(explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		code := baseLanguage(os.Getenv(name))
+		if _, ok := catalogs[code]; ok {
+			return code
+		}
+	}
+	return DefaultLanguage
+}
+
+// baseLanguage reduces a locale such as "pt_BR.UTF-8" to its language code
+func baseLanguage(locale string) string {
+	code := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+	if code == "c" || code == "posix" {
+		return DefaultLanguage
+	}
+	return code
+}
+
+// Language returns the catalog's language code
+func (c *Catalog) Language() string {
+	if c == nil {
+		return DefaultLanguage
+	}
+	return c.language
+}
+
+// Sprintf formats the message for key with args. Keys the language lacks fall back
+// to English, and unknown keys are returned as they are.
+func (c *Catalog) Sprintf(key string, args ...any) string {
+	format, ok := "", false
+	if c != nil {
+		format, ok = c.messages[key]
+	}
+	if !ok {
+		if format, ok = catalogs[DefaultLanguage][key]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Messages returns the unformatted messages whose keys start with prefix, with
+// English filling the keys the language lacks, for clients that format their own
+// text such as the web UI
+func (c *Catalog) Messages(prefix string) map[string]string {
+	result := make(map[string]string)
+	for key := range catalogs[DefaultLanguage] {
+		if strings.HasPrefix(key, prefix) {
+			result[key] = c.Sprintf(key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestTranslationsMatchEnglish(t *testing.T) {
+	english := catalogs[DefaultLanguage]
+	require.NotEmpty(t, english)
+	for _, language := range Languages() {
+		if language == DefaultLanguage {
+			continue
+		}
+		t.Run(language, func(t *testing.T) {
+			messages := catalogs[language]
+			for key, format := range english {
+				translated, ok := messages[key]
+				if !assert.True(t, ok, "missing key %s", key) {
+					continue
+				}
+				assert.Equal(t, formatVerb.FindAllString(format, -1), formatVerb.FindAllString(translated, -1),
+					"format verbs of %s differ", key)
+			}
+			for key := range messages {
+				_, ok := english[key]
+				assert.True(t, ok, "unknown key %s", key)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	for input, want := range map[string]string{
+		"":            "en",
+		"de":          "de",
+		"de-DE":       "de",
+		"de_DE.UTF-8": "de",
+		"EN_us":       "en",
+		"C":           "en",
+		"POSIX":       "en",
+	} {
+		catalog, err := New(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, catalog.Language(), input)
+	}
+
+	_, err := New("xx")
+	assert.ErrorContains(t, err, "unsupported language")
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "fr", Detect("fr"), "an explicit choice wins, even if unsupported")
+	assert.Equal(t, "de", Detect(""))
+
+	t.Setenv("LC_ALL", "xx_XX.UTF-8")
+	assert.Equal(t, "de", Detect(""), "unavailable languages are skipped")
+
+	t.Setenv("LANG", "")
+	assert.Equal(t, DefaultLanguage, Detect(""))
+}
+
+func TestSprintf(t *testing.T) {
+	german, err := New("de")
+	require.NoError(t, err)
+	assert.Equal(t, "Fehler: 2", german.Sprintf("summary.errors", 2))
+	assert.Equal(t, "Errors: 2", (*Catalog)(nil).Sprintf("summary.errors", 2))
+	assert.Equal(t, "no.such.key", german.Sprintf("no.such.key"))
+
+	// Missing translations fall back to English
+	partial := &Catalog{language: "de", messages: map[string]string{}}
+	assert.Equal(t, "Errors: 2", partial.Sprintf("summary.errors", 2))
+
+	web := german.Messages("web.")
+	assert.Contains(t, web, "web.subtitle")
+	for key := range web {
+		assert.Regexp(t, `^web\.`, key)
+	}
+}
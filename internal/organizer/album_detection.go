@@ -4,6 +4,7 @@ package organizer
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"unicode"
 )
@@ -41,7 +42,7 @@ func (o *Organizer) shouldProcessAsAlbum(dirPath string) bool {
 		ext := strings.ToLower(filepath.Ext(filePath))
 
 		// Check if this is an audio file
-		if !IsSupportedAudioFile(ext) {
+		if !o.config.Extensions.IsAudio(ext) {
 			continue
 		}
 
@@ -110,20 +111,16 @@ func (o *Organizer) shouldProcessAsAlbum(dirPath string) bool {
 	return audioFiles > 1 && firstMetadata != nil
 }
 
+// titleSeparators separate an album title from the part of a track title that varies
+var titleSeparators = []string{" - ", ": ", ", "}
+
 // hasCommonPrefix checks if two strings have a common prefix that suggests they belong to the same album
 // For example: "Album Name - Track 01" and "Album Name - Track 02"
 func hasCommonPrefix(str1, str2 string) bool {
-	// Find common prefix
-	minLen := len(str1)
-	if len(str2) < minLen {
-		minLen = len(str2)
-	}
-
 	// For very short strings, check if they're identical up to a separator
-	if minLen <= 5 {
+	if min(len(str1), len(str2)) <= 5 {
 		// Check if the strings start the same and contain a common separator
-		commonSeparators := []string{" - ", ": ", ", "}
-		for _, sep := range commonSeparators {
+		for _, sep := range titleSeparators {
 			if strings.Contains(str1, sep) && strings.Contains(str2, sep) {
 				// Get the part before the separator
 				parts1 := strings.Split(str1, sep)
@@ -135,20 +132,42 @@ func hasCommonPrefix(str1, str2 string) bool {
 		}
 	}
 
-	// Look for common prefix ending with a separator like " - " or ": "
-	for i := minLen; i > 3; i-- {
-		prefix := str1[:i]
-		if strings.HasPrefix(str2, prefix) {
-			// Check if the prefix ends with a common separator
-			if strings.HasSuffix(prefix, " - ") ||
-				strings.HasSuffix(prefix, ": ") ||
-				strings.HasSuffix(prefix, ", ") {
-				return true
-			}
+	return commonTitlePrefix(str1, str2) != ""
+}
+
+// commonTitlePrefix returns the longest common prefix of two titles that ends with a
+// separator like " - " or ": ", or "" when there is none. It runs in linear time, so
+// it can be called for every file of a large directory.
+func commonTitlePrefix(str1, str2 string) string {
+	n := 0
+	for n < len(str1) && n < len(str2) && str1[n] == str2[n] {
+		n++
+	}
+
+	end := 0
+	for _, sep := range titleSeparators {
+		if i := strings.LastIndex(str1[:n], sep); i >= 0 && i+len(sep) > end {
+			end = i + len(sep)
 		}
 	}
+	if end <= 3 {
+		return ""
+	}
+	return str1[:end]
+}
 
-	return false
+// trackSuffixPattern matches a trailing track designation such as " - Part 2" or ": Chapter 12"
+var trackSuffixPattern = regexp.MustCompile(
+	`(?i)^(.*?)[\s,:-]*\b(?:track|tr|part|pt|chapter|ch|disc|cd|episode|ep|section)\.?\s*\d+$`,
+)
+
+// albumStem returns a title without its trailing track designation, so "Book - Part 1"
+// and "Book - Part 2" share the stem "Book". Titles without one are returned as is.
+func albumStem(title string) string {
+	if m := trackSuffixPattern.FindStringSubmatch(title); m != nil && strings.TrimSpace(m[1]) != "" {
+		return strings.TrimSpace(m[1])
+	}
+	return title
 }
 
 // hasTrackNumberPattern checks if two strings follow a track numbering pattern
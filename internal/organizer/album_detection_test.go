@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestHasCommonPrefix(t *testing.T) {
@@ -805,6 +807,116 @@ func TestGroupFilesByAlbum(t *testing.T) {
 	}
 }
 
+func TestGroupFilesByAlbumMergesTrackTitles(t *testing.T) {
+	org := &Organizer{}
+	originalNewAudioMetadataProvider := newAudioMetadataProviderFunc
+	defer func() {
+		newAudioMetadataProviderFunc = originalNewAudioMetadataProvider
+	}()
+
+	titles := map[string]string{
+		"1.mp3": "The Book - Part 1",
+		"2.mp3": "The Book - Part 2",
+		"3.mp3": "The Expanse - Leviathan Wakes",
+		"4.mp3": "The Expanse - Caliban's War",
+		"5.mp3": "Unrelated",
+	}
+	newAudioMetadataProviderFunc = func(filePath string) MetadataProvider {
+		return &mockAudioProvider{metadata: Metadata{
+			Title:   titles[filepath.Base(filePath)],
+			Authors: []string{"Author"},
+		}}
+	}
+	var entries []os.DirEntry
+	for _, name := range []string{"1.mp3", "2.mp3", "3.mp3", "4.mp3", "5.mp3"} {
+		entries = append(entries, mockDirEntry{name: name})
+	}
+
+	albumGroups, err := org.groupFilesByAlbum("test/dir", entries)
+	if err != nil {
+		t.Fatalf("groupFilesByAlbum() error = %v", err)
+	}
+
+	got := make(map[string]int)
+	for _, group := range albumGroups {
+		got[group.Metadata.Title] = len(group.Files)
+	}
+	// Distinct books sharing a prefix are never merged into one album
+	want := map[string]int{
+		"The Book":                      2,
+		"The Expanse - Leviathan Wakes": 1,
+		"The Expanse - Caliban's War":   1,
+		"Unrelated":                     1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("groupFilesByAlbum() grouped titles %v, want %v", got, want)
+	}
+	for title, count := range want {
+		if got[title] != count {
+			t.Errorf("album %q has %d files, want %d", title, got[title], count)
+		}
+	}
+}
+
+// BenchmarkGroupFilesByAlbum groups directories of 1,000 and 10,000 files with one
+// author and many distinct albums, the worst case for comparing titles. It fails when
+// the time per file at 10,000 files is more than three times that at 1,000.
+func BenchmarkGroupFilesByAlbum(b *testing.B) {
+	org := &Organizer{}
+	originalNewAudioMetadataProvider := newAudioMetadataProviderFunc
+	defer func() {
+		newAudioMetadataProviderFunc = originalNewAudioMetadataProvider
+	}()
+	newAudioMetadataProviderFunc = func(filePath string) MetadataProvider {
+		var n int
+		fmt.Sscanf(filepath.Base(filePath), "%d.mp3", &n)
+		return &mockAudioProvider{metadata: Metadata{
+			Title:       fmt.Sprintf("Book %d - Part %d", n/10, n%10+1),
+			Authors:     []string{"Prolific Author"},
+			TrackNumber: n%10 + 1,
+		}}
+	}
+
+	entries := func(count int) []os.DirEntry {
+		result := make([]os.DirEntry, count)
+		for i := range result {
+			result[i] = mockDirEntry{name: fmt.Sprintf("%d.mp3", i)}
+		}
+		return result
+	}
+	small, large := entries(1000), entries(10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var smallTime, largeTime time.Duration
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		if _, err := org.groupFilesByAlbum("dir", small); err != nil {
+			b.Fatal(err)
+		}
+		smallTime += time.Since(start)
+
+		start = time.Now()
+		groups, err := org.groupFilesByAlbum("dir", large)
+		if err != nil {
+			b.Fatal(err)
+		}
+		largeTime += time.Since(start)
+		if len(groups) != 1000 {
+			b.Fatalf("got %d albums, want 1000", len(groups))
+		}
+	}
+
+	perFileSmall := float64(smallTime) / float64(b.N*len(small))
+	perFileLarge := float64(largeTime) / float64(b.N*len(large))
+	b.ReportMetric(perFileSmall, "ns/file-1k")
+	b.ReportMetric(perFileLarge, "ns/file-10k")
+	if perFileLarge > 3*perFileSmall {
+		b.Fatalf("%.0f ns per file at 10k files vs %.0f at 1k, want near-linear grouping",
+			perFileLarge, perFileSmall)
+	}
+}
+
 func TestAlbumGroupSorting(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -924,6 +1036,162 @@ func TestCustomLayoutTemplateAlbumGroupDryRun(t *testing.T) {
 	}
 }
 
+func TestUndoAlbumGroupRestoresOriginalFilenames(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceDir := filepath.Join(baseDir, "disc")
+	if err := os.MkdirAll(sourceDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"part1.mp3", "part2.mp3"} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		Layout:       "author-title",
+		FieldMapping: DefaultFieldMapping(),
+	}
+	org, err := NewOrganizer(&config)
+	if err != nil {
+		t.Fatalf("NewOrganizer() error = %v", err)
+	}
+	group := NewAlbumGroup(Metadata{Title: "Album Book", Authors: []string{"Album Author"}})
+	group.AddFile(filepath.Join(sourceDir, "part1.mp3"), 1)
+	group.AddFile(filepath.Join(sourceDir, "part2.mp3"), 2)
+	if err := org.organizeAlbumGroup(group); err != nil {
+		t.Fatalf("organizeAlbumGroup() error = %v", err)
+	}
+	prefixed := filepath.Join(outputDir, "Album Author", "Album Book", "02 - part2.mp3")
+	if _, err := os.Stat(prefixed); err != nil {
+		t.Fatalf("expected track-prefixed file: %v", err)
+	}
+
+	config.Undo = true
+	undoOrg, err := NewOrganizer(&config)
+	if err != nil {
+		t.Fatalf("NewOrganizer() undo error = %v", err)
+	}
+	if err := undoOrg.Execute(); err != nil {
+		t.Fatalf("Execute() undo error = %v", err)
+	}
+
+	for _, name := range []string{"part1.mp3", "part2.mp3"} {
+		data, err := os.ReadFile(filepath.Join(sourceDir, name))
+		if err != nil || string(data) != name {
+			t.Errorf("%s not restored under its original name: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(prefixed); !os.IsNotExist(err) {
+		t.Errorf("track-prefixed file still exists after undo")
+	}
+}
+
+func TestProcessMultiFileAlbumPlansEveryAlbumFirst(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	for _, name := range []string{"x.mp3", "01 - x.mp3"} {
+		if err := os.WriteFile(filepath.Join(baseDir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	originalNewAudioMetadataProvider := newAudioMetadataProviderFunc
+	defer func() {
+		newAudioMetadataProviderFunc = originalNewAudioMetadataProvider
+	}()
+	titles := map[string]string{"x.mp3": "Book A", "01 - x.mp3": "Totally Different"}
+	newAudioMetadataProviderFunc = func(filePath string) MetadataProvider {
+		return &mockAudioProvider{metadata: Metadata{
+			Title:       titles[filepath.Base(filePath)],
+			Authors:     []string{"Author"},
+			TrackNumber: 1,
+		}}
+	}
+
+	// Both albums land in Author/ as "01 - x.mp3"
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		Layout:       "author-only",
+		FieldMapping: DefaultFieldMapping(),
+	})
+	if err != nil {
+		t.Fatalf("NewOrganizer() error = %v", err)
+	}
+	err = org.ProcessMultiFileAlbum(baseDir)
+	if err == nil || !strings.Contains(err.Error(), "nothing moved") {
+		t.Fatalf("ProcessMultiFileAlbum() error = %v, want a planning error", err)
+	}
+	for _, name := range []string{"x.mp3", "01 - x.mp3"} {
+		if _, err := os.Stat(filepath.Join(baseDir, name)); err != nil {
+			t.Errorf("%s moved although planning failed: %v", name, err)
+		}
+	}
+}
+
+func TestUndoLeavesIncompleteAlbumInPlace(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	for _, name := range []string{"part1.mp3", "part2.mp3"} {
+		if err := os.WriteFile(filepath.Join(baseDir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		Layout:       "author-title",
+		FieldMapping: DefaultFieldMapping(),
+	}
+	org, err := NewOrganizer(&config)
+	if err != nil {
+		t.Fatalf("NewOrganizer() error = %v", err)
+	}
+	group := NewAlbumGroup(Metadata{Title: "Album Book", Authors: []string{"Album Author"}})
+	group.Key = "album author|album book"
+	group.AddFile(filepath.Join(baseDir, "part1.mp3"), 1)
+	group.AddFile(filepath.Join(baseDir, "part2.mp3"), 2)
+	if err := org.organizeAlbumGroup(group); err != nil {
+		t.Fatalf("organizeAlbumGroup() error = %v", err)
+	}
+
+	entries, err := ReadLogEntries(org.GetLogPath())
+	if err != nil || len(entries) != 1 || entries[0].Album != group.Key {
+		t.Fatalf("log entries = %+v (%v), want one entry for album %q", entries, err, group.Key)
+	}
+
+	targetDir := filepath.Join(outputDir, "Album Author", "Album Book")
+	if err := os.Remove(filepath.Join(targetDir, "02 - part2.mp3")); err != nil {
+		t.Fatal(err)
+	}
+
+	config.Undo = true
+	undoOrg, err := NewOrganizer(&config)
+	if err != nil {
+		t.Fatalf("NewOrganizer() undo error = %v", err)
+	}
+	CaptureOutput(func() {
+		if err := undoOrg.Execute(); err != nil {
+			t.Fatalf("Execute() undo error = %v", err)
+		}
+	})
+
+	if _, err := os.Stat(filepath.Join(targetDir, "01 - part1.mp3")); err != nil {
+		t.Errorf("part of an incomplete album was restored: %v", err)
+	}
+	if len(undoOrg.summary.Errors) != 1 || !strings.Contains(undoOrg.summary.Errors[0], "02 - part2.mp3") {
+		t.Errorf("undo errors = %v, want the missing file reported", undoOrg.summary.Errors)
+	}
+	if entries, err := ReadLogEntries(undoOrg.GetLogPath()); err != nil || len(entries) != 1 {
+		t.Errorf("log entries after undo = %+v (%v), want the album kept for a later undo", entries, err)
+	}
+}
+
 func TestCustomLayoutTemplateAlbumGroupRejectsTraversalSegment(t *testing.T) {
 	tempDir := t.TempDir()
 	config := OrganizerConfig{
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -16,6 +17,7 @@ type AlbumGroup struct {
 	Files       []string
 	TrackOrder  map[string]int // Maps filenames to track numbers
 	AlbumFolder string         // The folder name to use for this album
+	Key         string         // Grouping key, recorded in the undo log so the album is undone as a unit
 }
 
 // NewAlbumGroup creates a new album group with the given metadata
@@ -58,7 +60,10 @@ func (ag *AlbumGroup) SortFilesByTrackNumber() {
 	})
 }
 
-// ProcessMultiFileAlbum processes a directory containing multiple files that belong to the same album
+// ProcessMultiFileAlbum processes a directory containing multiple files that belong to
+// the same album. Every album of the directory is planned and verified before any
+// file moves, and each album then moves as one transaction, so an interrupted or
+// failed run never leaves an album split between two directories.
 func (o *Organizer) ProcessMultiFileAlbum(dirPath string) error {
 	if o.config.Verbose {
 		PrintBlue("🎵 Processing multi-file album in: %s", dirPath)
@@ -76,22 +81,54 @@ func (o *Organizer) ProcessMultiFileAlbum(dirPath string) error {
 		return err
 	}
 
-	// Process each album group
-	for _, albumGroup := range albumGroups {
-		if err := o.organizeAlbumGroup(albumGroup); err != nil {
-			PrintRed("❌ Error organizing album group: %v", err)
+	keys := make([]string, 0, len(albumGroups))
+	for key := range albumGroups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Plan and verify every album before committing any of them
+	plans := make([]*albumPlan, 0, len(keys))
+	for _, key := range keys {
+		plan, err := o.planAlbumGroup(albumGroups[key])
+		if err != nil {
+			return fmt.Errorf("error planning albums in %s, nothing moved: %w", dirPath, err)
+		}
+		if plan != nil {
+			plans = append(plans, plan)
 		}
 	}
+	if err := o.verifyAlbumPlans(plans); err != nil {
+		return fmt.Errorf("error planning albums in %s, nothing moved: %w", dirPath, err)
+	}
 
+	for _, plan := range plans {
+		if err := o.commitAlbumPlan(plan); err != nil {
+			o.recordRetriesExhausted(dirPath, err)
+			o.recordError("❌ Error organizing album group: %v", err)
+		}
+	}
 	return nil
 }
 
-// groupFilesByAlbum groups files in a directory by their album metadata
+// albumBucket holds the album groups sharing normalized authors and series, indexed
+// by album stem so titles differing only by a track designation find one group.
+type albumBucket struct {
+	byStem map[string]*AlbumGroup
+}
+
+// groupFilesByAlbum groups files in a directory by their album metadata. Files with
+// the same normalized key share a group, as do files whose titles differ only by a
+// track designation ("Book - Part 1", "Book - Part 2"). Titles that merely share a
+// prefix, such as two books of a series, stay apart. Each file costs a constant
+// number of map lookups, so directories with thousands of files group in linear time.
 func (o *Organizer) groupFilesByAlbum(
 	dirPath string,
 	entries []os.DirEntry,
 ) (map[string]*AlbumGroup, error) {
 	albumGroups := make(map[string]*AlbumGroup)
+	groupsByKey := make(map[string]*AlbumGroup) // Every key seen, including merged ones
+	buckets := make(map[string]*albumBucket)
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -102,7 +139,7 @@ func (o *Organizer) groupFilesByAlbum(
 		ext := strings.ToLower(filepath.Ext(filePath))
 
 		// Skip non-audio files
-		if !IsSupportedAudioFile(ext) {
+		if !o.config.Extensions.IsAudio(ext) {
 			continue
 		}
 
@@ -118,15 +155,28 @@ func (o *Organizer) groupFilesByAlbum(
 
 		// Apply field mapping
 		metadata.ApplyFieldMapping(o.config.FieldMapping)
+		metadata = o.applyAuthorAliases(metadata)
 
 		// Create a key for grouping files by album
 		albumKey := o.createAlbumKey(metadata)
 
 		// Add to existing group or create a new one
-		group, exists := albumGroups[albumKey]
+		group, exists := groupsByKey[albumKey]
 		if !exists {
-			group = NewAlbumGroup(metadata)
-			albumGroups[albumKey] = group
+			bucketKey := albumBucketKey(metadata)
+			bucket := buckets[bucketKey]
+			if bucket == nil {
+				bucket = &albumBucket{byStem: make(map[string]*AlbumGroup)}
+				buckets[bucketKey] = bucket
+			}
+			group = bucket.find(metadata.Title)
+			if group == nil {
+				group = NewAlbumGroup(metadata)
+				group.Key = albumKey
+				bucket.add(group)
+				albumGroups[albumKey] = group
+			}
+			groupsByKey[albumKey] = group
 		}
 
 		// Add file to the group
@@ -136,6 +186,32 @@ func (o *Organizer) groupFilesByAlbum(
 	return albumGroups, nil
 }
 
+// find returns the group whose album stem matches title, or nil. A group found
+// through a track designation is retitled to the stem, "Book - Part 2" to "Book".
+func (b *albumBucket) find(title string) *AlbumGroup {
+	stem := albumStem(title)
+	group := b.byStem[normalizeString(stem)]
+	if group != nil && stem != title {
+		group.Metadata.Title = stem
+	}
+	return group
+}
+
+// add registers a new group with the bucket
+func (b *albumBucket) add(group *AlbumGroup) {
+	stem := normalizeString(albumStem(group.Metadata.Title))
+	if _, exists := b.byStem[stem]; !exists {
+		b.byStem[stem] = group
+	}
+}
+
+// albumBucketKey returns the normalized authors and series shared by every group of
+// a bucket; only titles within the same bucket are compared
+func albumBucketKey(metadata Metadata) string {
+	return strings.Join(normalizeStrings(metadata.Authors), ",") + "|" +
+		normalizeString(metadata.GetValidSeries())
+}
+
 // createAlbumKey creates a unique key for grouping files by album
 func (o *Organizer) createAlbumKey(metadata Metadata) string {
 	// Normalize author names and title to handle special characters
@@ -153,6 +229,22 @@ func (o *Organizer) createAlbumKey(metadata Metadata) string {
 	return key
 }
 
+// specialCharReplacer spells out or drops the special characters normalizeString handles
+var specialCharReplacer = strings.NewReplacer(
+	"&", "and",
+	"+", "plus",
+	"@", "at",
+	"#", "number",
+	"%", "percent",
+	"$", "dollar",
+	"*", "",
+	"\\", "",
+	"/", "",
+	":", "",
+	"_", " ",
+	".", " ",
+)
+
 // normalizeString prepares a string for consistent comparison by removing/replacing special characters
 func normalizeString(s string) string {
 	// Convert to lowercase
@@ -162,7 +254,7 @@ func normalizeString(s string) string {
 	s = strings.ReplaceAll(s, " $$ ", " doubledollar ")
 
 	// First, consolidate repeated special characters
-	var prev rune
+	prev := rune(-1)
 	var result strings.Builder
 	for _, r := range s {
 		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) {
@@ -181,24 +273,7 @@ func normalizeString(s string) string {
 	s = strings.ReplaceAll(s, " doubledollar ", " dollar dollar ")
 
 	// Replace common special characters and accents
-	replacements := map[string]string{
-		"&":  "and",
-		"+":  "plus",
-		"@":  "at",
-		"#":  "number",
-		"%":  "percent",
-		"$":  "dollar",
-		"*":  "",
-		"\\": "",
-		"/":  "",
-		":":  "",
-		"_":  " ",
-		".":  " ",
-	}
-
-	for old, new := range replacements {
-		s = strings.ReplaceAll(s, old, new)
-	}
+	s = specialCharReplacer.Replace(s)
 
 	// Remove extra whitespace
 	s = strings.Join(strings.Fields(s), " ")
@@ -215,10 +290,30 @@ func normalizeStrings(slice []string) []string {
 	return result
 }
 
+// albumPlan is an album whose target directory and file names have been worked out
+type albumPlan struct {
+	group     *AlbumGroup
+	targetDir string
+	moves     []FilePair
+}
+
 // organizeAlbumGroup organizes a group of files that belong to the same album
 func (o *Organizer) organizeAlbumGroup(albumGroup *AlbumGroup) error {
+	plan, err := o.planAlbumGroup(albumGroup)
+	if err != nil || plan == nil {
+		return err
+	}
+	if err := o.verifyAlbumPlans([]*albumPlan{plan}); err != nil {
+		return err
+	}
+	return o.commitAlbumPlan(plan)
+}
+
+// planAlbumGroup works out where the files of an album go without moving anything.
+// It returns nil for an empty group.
+func (o *Organizer) planAlbumGroup(albumGroup *AlbumGroup) (*albumPlan, error) {
 	if len(albumGroup.Files) == 0 {
-		return nil // Nothing to do
+		return nil, nil // Nothing to do
 	}
 
 	// Sort files by track number
@@ -227,7 +322,7 @@ func (o *Organizer) organizeAlbumGroup(albumGroup *AlbumGroup) error {
 	// Calculate target directory based on the album metadata
 	targetDir, err := o.calculateAlbumTargetDirE(albumGroup.Metadata)
 	if err != nil {
-		return fmt.Errorf("error calculating album target directory: %w", err)
+		return nil, fmt.Errorf("error calculating album target directory: %w", err)
 	}
 
 	if o.config.Verbose {
@@ -237,14 +332,8 @@ func (o *Organizer) organizeAlbumGroup(albumGroup *AlbumGroup) error {
 			targetDir)
 	}
 
-	// Create target directory if it doesn't exist
-	if !o.config.DryRun {
-		if err := o.fileOps.CreateDirIfNotExists(targetDir); err != nil {
-			return fmt.Errorf("error creating target directory: %w", err)
-		}
-	}
-
-	// Move each file to the target directory with appropriate track numbering
+	// Name each file with appropriate track numbering
+	var moves []FilePair
 	for i, filePath := range albumGroup.Files {
 		// Get original track number or use index+1 if not available
 		trackNum := albumGroup.TrackOrder[filePath]
@@ -254,31 +343,96 @@ func (o *Organizer) organizeAlbumGroup(albumGroup *AlbumGroup) error {
 
 		// Calculate target filename with track prefix
 		fileName := filepath.Base(filePath)
-		targetName := AddTrackPrefix(fileName, trackNum)
+		targetName := o.fileNamer().WithTrackPrefix(trackNum).Normalize(fileName)
 		targetPath := filepath.Join(targetDir, targetName)
 
 		if o.config.Verbose || o.config.DryRun {
-			message := o.formatFileMove(filePath, targetPath, o.config.DryRun)
-			fmt.Println(message)
+			o.printFileLine(albumGroup.Key, o.formatFileMove(filePath, targetPath, o.config.DryRun))
 		}
 
-		// Move the file
-		if !o.config.DryRun {
-			if err := o.moveFile(filePath, targetPath); err != nil {
-				PrintRed("❌ Error moving %s: %v", filePath, err)
+		moves = append(moves, FilePair{From: filePath, To: targetName})
+	}
+	o.endFileLines()
+
+	if err := o.checkFileSizes(moves); err != nil {
+		return nil, err
+	}
+	return &albumPlan{group: albumGroup, targetDir: targetDir, moves: moves}, nil
+}
+
+// verifyAlbumPlans checks that no two files, of one album or of different albums, are
+// planned onto the same target and, unless this is a dry run, that every planned
+// source is still there
+func (o *Organizer) verifyAlbumPlans(plans []*albumPlan) error {
+	targets := make(map[string]string)
+	for _, plan := range plans {
+		for _, move := range plan.moves {
+			if _, err := os.Stat(move.From); err != nil && !o.config.DryRun {
+				return fmt.Errorf("album file is no longer available: %w", err)
+			}
+			target := filepath.Join(plan.targetDir, move.To)
+			if other, ok := targets[target]; ok {
+				return fmt.Errorf("%s and %s would both be moved to %s", other, move.From, target)
 			}
+			targets[target] = move.From
 		}
+	}
+	return nil
+}
+
+// commitAlbumPlan moves a planned album and records it
+func (o *Organizer) commitAlbumPlan(plan *albumPlan) error {
+	targetDir, moves := plan.targetDir, plan.moves
 
-		// Add to summary
+	// Move the whole album at once so a failure never splits it
+	if !o.config.DryRun {
+		if err := o.moveBookFiles(targetDir, moves); err != nil {
+			return fmt.Errorf("error moving album, source left untouched: %w", err)
+		}
+	}
+
+	for _, move := range moves {
 		o.summary.Moves = append(o.summary.Moves, MoveSummary{
-			From: filePath,
-			To:   targetPath,
+			From: move.From,
+			To:   filepath.Join(targetDir, move.To),
 		})
+		o.recordFileMove(move.From, filepath.Join(targetDir, move.To))
+	}
+
+	if !o.config.DryRun {
+		o.logAlbumMoves(targetDir, plan.group.Key, moves)
 	}
 
 	return nil
 }
 
+// logAlbumMoves records an album's moves with one log entry per source directory,
+// keeping each file's original name so undo drops the track prefixes again. Every
+// entry carries the album's grouping key so undo restores the album as a whole.
+func (o *Organizer) logAlbumMoves(targetDir, album string, moves []FilePair) {
+	var sourceDirs []string
+	bySource := make(map[string][]FilePair)
+	for _, move := range moves {
+		sourceDir := filepath.Dir(move.From)
+		if _, ok := bySource[sourceDir]; !ok {
+			sourceDirs = append(sourceDirs, sourceDir)
+		}
+		bySource[sourceDir] = append(bySource[sourceDir], FilePair{
+			From: filepath.Base(move.From),
+			To:   move.To,
+		})
+	}
+	for _, sourceDir := range sourceDirs {
+		o.appendLogEntry(LogEntry{
+			Timestamp:  time.Now(),
+			SourcePath: sourceDir,
+			TargetPath: targetDir,
+			Files:      bySource[sourceDir],
+			Album:      album,
+		})
+	}
+}
+
 // calculateAlbumTargetDir calculates the target directory for an album based on metadata
 func (o *Organizer) calculateAlbumTargetDir(metadata Metadata) string {
 	targetDir, _ := o.calculateAlbumTargetDirE(metadata)
@@ -296,17 +450,25 @@ func (o *Organizer) calculateAlbumTargetDirE(metadata Metadata) (string, error)
 		return o.layoutCalculator.CalculateTargetPathInBaseE(metadata, baseDir)
 	}
 
+	return safeTargetPath(metadata, baseDir, func() (string, error) {
+		return o.albumTargetDir(metadata, baseDir), nil
+	})
+}
+
+// albumTargetDir builds the directory of an album below baseDir per a built-in layout
+func (o *Organizer) albumTargetDir(metadata Metadata, baseDir string) string {
 	// Use PathBuilder for cleaner path construction
+	metadata = PathMetadata(metadata, o.config.Casing, o.config.Subtitle, o.config.StripTitlePrefix)
 	pathBuilder := NewPathBuilder().WithSanitizer(o.SanitizePath)
 
 	switch o.config.Layout {
 	case "author-only":
-		return pathBuilder.AddAuthor(strings.Join(metadata.Authors, ",")).Build(baseDir), nil
+		return pathBuilder.AddAuthor(strings.Join(metadata.Authors, ",")).Build(baseDir)
 	case "author-title":
 		return pathBuilder.
 			AddAuthor(strings.Join(metadata.Authors, ",")).
 			AddTitle(metadata.Title).
-			Build(baseDir), nil
+			Build(baseDir)
 	case "author-series-title", "":
 		pathBuilder.AddAuthor(strings.Join(metadata.Authors, ","))
 		if validSeries := metadata.GetValidSeries(); validSeries != "" {
@@ -319,11 +481,11 @@ func (o *Organizer) calculateAlbumTargetDirE(metadata Metadata) (string, error)
 			// No series, just add the title
 			pathBuilder.AddTitle(metadata.Title)
 		}
-		return pathBuilder.Build(baseDir), nil
+		return pathBuilder.Build(baseDir)
 	default:
 		return pathBuilder.
 			AddAuthor(strings.Join(metadata.Authors, ",")).
 			AddTitle(metadata.Title).
-			Build(baseDir), nil
+			Build(baseDir)
 	}
 }
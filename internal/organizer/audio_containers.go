@@ -0,0 +1,415 @@
+// internal/organizer/audio_containers.go
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/dhowden/tag"
+)
+
+// Tag formats and file types for the containers the tag library can't read. WMA files
+// keep their tags in ASF header objects and WAV files in a RIFF LIST/INFO chunk.
+const (
+	asfFormat      tag.Format   = "ASF"
+	riffInfoFormat tag.Format   = "RIFF INFO"
+	wmaFileType    tag.FileType = "WMA"
+	wavFileType    tag.FileType = "WAV"
+)
+
+// containerHeaderLimit bounds how much of an ASF header or RIFF chunk is read into
+// memory. Tags are small, so anything larger is audio data or a broken file.
+const containerHeaderLimit = 16 << 20
+
+var (
+	asfHeaderGUID              = asfGUID("75B22630-668E-11CF-A6D9-00AA0062CE6C")
+	asfContentDescriptionGUID  = asfGUID("75B22633-668E-11CF-A6D9-00AA0062CE6C")
+	asfExtendedDescriptionGUID = asfGUID("D2D0A440-E307-11D2-97F0-00A0C95EA850")
+	asfFilePropertiesGUID      = asfGUID("8CABDCA1-A947-11CF-8EE4-00C00C205365")
+)
+
+// readAudioTags reads the tags of an audio file, handling WMA and WAV files itself
+// and passing every other format to the tag library
+func readAudioTags(r io.ReadSeeker) (tag.Metadata, error) {
+	header := make([]byte, 16)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	n, _ := io.ReadFull(r, header)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case n == 16 && bytes.Equal(header, asfHeaderGUID[:]):
+		return readASFTags(r)
+	case n >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return readRIFFTags(r)
+	}
+	return tag.ReadFrom(r)
+}
+
+// containerTags holds tags read from an ASF or RIFF container, with keys naming the
+// raw fields that back each tag.Metadata method
+type containerTags struct {
+	format   tag.Format
+	fileType tag.FileType
+	raw      map[string]interface{}
+	keys     containerTagKeys
+}
+
+type containerTagKeys struct {
+	title, album, artist, albumArtist, composer, genre, year, track, disc, comment string
+}
+
+var asfTagKeys = containerTagKeys{
+	title:       "Title",
+	album:       "WM/AlbumTitle",
+	artist:      "Author",
+	albumArtist: "WM/AlbumArtist",
+	composer:    "WM/Composer",
+	genre:       "WM/Genre",
+	year:        "WM/Year",
+	track:       "WM/TrackNumber",
+	disc:        "WM/PartOfSet",
+	comment:     "Description",
+}
+
+var riffInfoTagKeys = containerTagKeys{
+	title:   "INAM",
+	album:   "IPRD",
+	artist:  "IART",
+	genre:   "IGNR",
+	year:    "ICRD",
+	track:   "ITRK",
+	comment: "ICMT",
+}
+
+func (c *containerTags) str(key string) string {
+	if key == "" {
+		return ""
+	}
+	value, _ := c.raw[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// numberPair parses "3" or "3/12" into a number and total
+func (c *containerTags) numberPair(key string) (int, int) {
+	parts := strings.SplitN(c.str(key), "/", 2)
+	num, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+	total := 0
+	if len(parts) == 2 {
+		total, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return num, total
+}
+
+func (c *containerTags) Format() tag.Format     { return c.format }
+func (c *containerTags) FileType() tag.FileType { return c.fileType }
+func (c *containerTags) Title() string          { return c.str(c.keys.title) }
+func (c *containerTags) Album() string          { return c.str(c.keys.album) }
+func (c *containerTags) Artist() string         { return c.str(c.keys.artist) }
+func (c *containerTags) AlbumArtist() string    { return c.str(c.keys.albumArtist) }
+func (c *containerTags) Composer() string       { return c.str(c.keys.composer) }
+func (c *containerTags) Genre() string          { return c.str(c.keys.genre) }
+func (c *containerTags) Track() (int, int)      { return c.numberPair(c.keys.track) }
+func (c *containerTags) Disc() (int, int)       { return c.numberPair(c.keys.disc) }
+func (c *containerTags) Picture() *tag.Picture  { return nil }
+func (c *containerTags) Lyrics() string         { return "" }
+func (c *containerTags) Comment() string        { return c.str(c.keys.comment) }
+func (c *containerTags) Raw() map[string]interface{} {
+	return c.raw
+}
+
+func (c *containerTags) Year() int {
+	year := c.str(c.keys.year)
+	if len(year) > 4 {
+		year = year[:4]
+	}
+	n, _ := strconv.Atoi(year)
+	return n
+}
+
+// readASFTags reads the content description and extended content description
+// objects of a WMA file's ASF header
+func readASFTags(r io.ReadSeeker) (tag.Metadata, error) {
+	tags := &containerTags{
+		format:   asfFormat,
+		fileType: wmaFileType,
+		raw:      make(map[string]interface{}),
+		keys:     asfTagKeys,
+	}
+	err := walkASFHeader(r, func(guid [16]byte, data []byte) {
+		switch guid {
+		case asfContentDescriptionGUID:
+			readASFContentDescription(data, tags.raw)
+		case asfExtendedDescriptionGUID:
+			readASFExtendedDescription(data, tags.raw)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tags.raw) == 0 {
+		return nil, tag.ErrNoTagsFound
+	}
+	return tags, nil
+}
+
+// walkASFHeader calls visit with the GUID and body of each top-level object in the
+// ASF header
+func walkASFHeader(r io.ReadSeeker, visit func(guid [16]byte, data []byte)) error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	header := make([]byte, 30)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	size := binary.LittleEndian.Uint64(header[16:24])
+	if size < 30 || size > containerHeaderLimit {
+		return tag.ErrNoTagsFound
+	}
+	body := make([]byte, size-30)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return err
+	}
+
+	for len(body) >= 24 {
+		var guid [16]byte
+		copy(guid[:], body[:16])
+		objectSize := binary.LittleEndian.Uint64(body[16:24])
+		if objectSize < 24 || objectSize > uint64(len(body)) {
+			break
+		}
+		visit(guid, body[24:objectSize])
+		body = body[objectSize:]
+	}
+	return nil
+}
+
+// readASFContentDescription reads the title, author, copyright, description, and
+// rating strings
+func readASFContentDescription(data []byte, raw map[string]interface{}) {
+	if len(data) < 10 {
+		return
+	}
+	names := []string{"Title", "Author", "Copyright", "Description", "Rating"}
+	offset := 10
+	for i, name := range names {
+		length := int(binary.LittleEndian.Uint16(data[i*2:]))
+		if offset+length > len(data) {
+			return
+		}
+		if value := decodeUTF16LE(data[offset : offset+length]); value != "" {
+			raw[name] = value
+		}
+		offset += length
+	}
+}
+
+// readASFExtendedDescription reads the named attributes, such as WM/AlbumTitle or a
+// custom SERIES attribute. Numbers are stored as strings like the other fields.
+func readASFExtendedDescription(data []byte, raw map[string]interface{}) {
+	if len(data) < 2 {
+		return
+	}
+	count := int(binary.LittleEndian.Uint16(data))
+	data = data[2:]
+	for i := 0; i < count && len(data) >= 2; i++ {
+		nameLen := int(binary.LittleEndian.Uint16(data))
+		if len(data) < 2+nameLen+4 {
+			return
+		}
+		name := decodeUTF16LE(data[2 : 2+nameLen])
+		data = data[2+nameLen:]
+		valueType := binary.LittleEndian.Uint16(data)
+		valueLen := int(binary.LittleEndian.Uint16(data[2:]))
+		if len(data) < 4+valueLen {
+			return
+		}
+		value := data[4 : 4+valueLen]
+		data = data[4+valueLen:]
+
+		var text string
+		switch {
+		case valueType == 0:
+			text = decodeUTF16LE(value)
+		case (valueType == 2 || valueType == 3) && valueLen >= 4:
+			text = strconv.FormatUint(uint64(binary.LittleEndian.Uint32(value)), 10)
+		case valueType == 4 && valueLen >= 8:
+			text = strconv.FormatUint(binary.LittleEndian.Uint64(value), 10)
+		case valueType == 5 && valueLen >= 2:
+			text = strconv.FormatUint(uint64(binary.LittleEndian.Uint16(value)), 10)
+		}
+		if name != "" && text != "" {
+			raw[name] = text
+		}
+	}
+}
+
+// readASFDuration reads the play duration from the ASF file properties object,
+// less the preroll
+func readASFDuration(r io.ReadSeeker) time.Duration {
+	var duration time.Duration
+	walkASFHeader(r, func(guid [16]byte, data []byte) {
+		if guid != asfFilePropertiesGUID || len(data) < 64 {
+			return
+		}
+		play := time.Duration(binary.LittleEndian.Uint64(data[40:])) * 100 // 100ns units
+		preroll := time.Duration(binary.LittleEndian.Uint64(data[56:])) * time.Millisecond
+		if play > preroll {
+			duration = play - preroll
+		}
+	})
+	return duration
+}
+
+// readRIFFTags reads the LIST/INFO chunk of a WAV file. A WAV file tagged with an
+// ID3v2 chunk instead is read with the tag library.
+func readRIFFTags(r io.ReadSeeker) (tag.Metadata, error) {
+	tags := &containerTags{
+		format:   riffInfoFormat,
+		fileType: wavFileType,
+		raw:      make(map[string]interface{}),
+		keys:     riffInfoTagKeys,
+	}
+	var id3 []byte
+	err := walkRIFFChunks(r, func(id string, size uint32) bool {
+		switch {
+		case id == "LIST" || id == "id3 " || id == "ID3 ":
+			if size > containerHeaderLimit {
+				return true
+			}
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return false
+			}
+			if id == "LIST" {
+				readRIFFInfo(data, tags.raw)
+			} else {
+				id3 = data
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags.raw) == 0 && id3 != nil {
+		return tag.ReadID3v2Tags(bytes.NewReader(id3))
+	}
+	if len(tags.raw) == 0 {
+		return nil, tag.ErrNoTagsFound
+	}
+	return tags, nil
+}
+
+// walkRIFFChunks calls visit with each top-level chunk of a RIFF file, with the reader
+// positioned at the chunk's data. Returning false stops the walk.
+func walkRIFFChunks(r io.ReadSeeker, visit func(id string, size uint32) bool) error {
+	if _, err := r.Seek(12, io.SeekStart); err != nil {
+		return err
+	}
+	offset := int64(12)
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil // End of file
+		}
+		size := binary.LittleEndian.Uint32(header[4:])
+		if !visit(string(header[:4]), size) {
+			return nil
+		}
+		offset += 8 + int64(size) + int64(size&1) // Chunks are padded to an even size
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+}
+
+// readRIFFInfo reads the NUL-terminated strings of a LIST chunk of type INFO
+func readRIFFInfo(data []byte, raw map[string]interface{}) {
+	if len(data) < 4 || string(data[:4]) != "INFO" {
+		return
+	}
+	data = data[4:]
+	for len(data) >= 8 {
+		id := string(data[:4])
+		size := int(binary.LittleEndian.Uint32(data[4:]))
+		if 8+size > len(data) {
+			return
+		}
+		if value := strings.TrimSpace(strings.TrimRight(string(data[8:8+size]), "\x00")); value != "" {
+			raw[id] = value
+		}
+		next := 8 + size + size&1
+		if next > len(data) {
+			return
+		}
+		data = data[next:]
+	}
+}
+
+// readWAVDuration reads the playing time of a WAV file from its fmt and data chunks,
+// reporting false when r is not a WAV file
+func readWAVDuration(r io.ReadSeeker) (time.Duration, bool) {
+	header := make([]byte, 12)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, false
+	}
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0, false
+	}
+
+	var byteRate, dataSize uint32
+	walkRIFFChunks(r, func(id string, size uint32) bool {
+		switch id {
+		case "fmt ":
+			format := make([]byte, 12)
+			if size >= 12 {
+				if _, err := io.ReadFull(r, format); err == nil {
+					byteRate = binary.LittleEndian.Uint32(format[8:])
+				}
+			}
+		case "data":
+			dataSize = size
+		}
+		return byteRate == 0 || dataSize == 0
+	})
+	if byteRate == 0 {
+		return 0, true
+	}
+	return time.Duration(float64(dataSize) / float64(byteRate) * float64(time.Second)), true
+}
+
+// asfGUID converts a GUID in its canonical text form to the mixed-endian bytes ASF
+// files store
+func asfGUID(s string) [16]byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		panic("invalid GUID " + s)
+	}
+	var guid [16]byte
+	guid[0], guid[1], guid[2], guid[3] = b[3], b[2], b[1], b[0]
+	guid[4], guid[5] = b[5], b[4]
+	guid[6], guid[7] = b[7], b[6]
+	copy(guid[8:], b[8:])
+	return guid
+}
+
+// decodeUTF16LE decodes a NUL-terminated UTF-16LE string
+func decodeUTF16LE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, binary.LittleEndian.Uint16(b[i:]))
+	}
+	return strings.TrimSpace(strings.TrimRight(string(utf16.Decode(units)), "\x00"))
+}
@@ -0,0 +1,159 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func utf16LE(s string) []byte {
+	units := utf16.Encode([]rune(s + "\x00"))
+	buf := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(buf[2*i:], u)
+	}
+	return buf
+}
+
+func asfObject(guid [16]byte, body []byte) []byte {
+	buf := make([]byte, 24, 24+len(body))
+	copy(buf, guid[:])
+	binary.LittleEndian.PutUint64(buf[16:], uint64(24+len(body)))
+	return append(buf, body...)
+}
+
+func buildWMA() []byte {
+	var content bytes.Buffer
+	fields := [][]byte{utf16LE("The Final Empire"), utf16LE("Brandon Sanderson"), nil, nil, nil}
+	for _, field := range fields {
+		binary.Write(&content, binary.LittleEndian, uint16(len(field)))
+	}
+	for _, field := range fields {
+		content.Write(field)
+	}
+
+	var extended bytes.Buffer
+	attribute := func(name string, valueType uint16, value []byte) {
+		binary.Write(&extended, binary.LittleEndian, uint16(len(utf16LE(name))))
+		extended.Write(utf16LE(name))
+		binary.Write(&extended, binary.LittleEndian, valueType)
+		binary.Write(&extended, binary.LittleEndian, uint16(len(value)))
+		extended.Write(value)
+	}
+	track := make([]byte, 4)
+	binary.LittleEndian.PutUint32(track, 3)
+	binary.Write(&extended, binary.LittleEndian, uint16(3))
+	attribute("WM/AlbumTitle", 0, utf16LE("Mistborn"))
+	attribute("WM/TrackNumber", 3, track)
+	attribute("SERIES", 0, utf16LE("Mistborn Era One"))
+	extendedBody := append([]byte(nil), extended.Bytes()...)
+
+	properties := make([]byte, 80)
+	binary.LittleEndian.PutUint64(properties[40:], uint64(90*time.Minute/100)) // play duration, 100ns units
+	binary.LittleEndian.PutUint64(properties[56:], 3000)                       // preroll, ms
+
+	objects := bytes.Join([][]byte{
+		asfObject(asfFilePropertiesGUID, properties),
+		asfObject(asfContentDescriptionGUID, content.Bytes()),
+		asfObject(asfExtendedDescriptionGUID, extendedBody),
+	}, nil)
+	header := make([]byte, 30, 30+len(objects))
+	copy(header, asfHeaderGUID[:])
+	binary.LittleEndian.PutUint64(header[16:], uint64(30+len(objects)))
+	binary.LittleEndian.PutUint32(header[24:], 3)
+	return append(append(header, objects...), make([]byte, 64)...) // data object stand-in
+}
+
+func riffChunk(id string, body []byte) []byte {
+	buf := make([]byte, 8, 8+len(body)+1)
+	copy(buf, id)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(body)))
+	buf = append(buf, body...)
+	if len(body)%2 == 1 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+func buildWAV() []byte {
+	format := make([]byte, 16)
+	binary.LittleEndian.PutUint16(format, 1)        // PCM
+	binary.LittleEndian.PutUint16(format[2:], 1)    // mono
+	binary.LittleEndian.PutUint32(format[4:], 8000) // sample rate
+	binary.LittleEndian.PutUint32(format[8:], 16000)
+	info := bytes.Join([][]byte{
+		[]byte("INFO"),
+		riffChunk("INAM", []byte("Chapter One: A Beginning\x00")),
+		riffChunk("IART", []byte("Jane Doe\x00")),
+		riffChunk("IPRD", []byte("Old Radio Tales\x00")),
+		riffChunk("ITRK", []byte("2\x00")),
+	}, nil)
+	body := bytes.Join([][]byte{
+		[]byte("WAVE"),
+		riffChunk("fmt ", format),
+		riffChunk("LIST", info),
+		riffChunk("data", make([]byte, 32000)), // two seconds
+	}, nil)
+	return append(append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...), body...)
+}
+
+func TestReadWMATags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.wma")
+	require.NoError(t, os.WriteFile(path, buildWMA(), 0o644))
+
+	metadata, err := NewAudioMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, "The Final Empire", metadata.Title)
+	assert.Equal(t, []string{"Brandon Sanderson"}, metadata.Authors)
+	assert.Equal(t, "Mistborn", metadata.Album)
+	assert.Equal(t, []string{"Mistborn Era One"}, metadata.Series)
+	assert.Equal(t, 3, metadata.TrackNumber)
+	assert.InDelta(t, (90*time.Minute - 3*time.Second).Seconds(), metadata.RawData[DurationField], 0.001)
+}
+
+func TestReadWAVInfoTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.wav")
+	require.NoError(t, os.WriteFile(path, buildWAV(), 0o644))
+
+	metadata, err := NewAudioMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, "Chapter One: A Beginning", metadata.Title)
+	assert.Equal(t, []string{"Jane Doe"}, metadata.Authors)
+	assert.Equal(t, "Old Radio Tales", metadata.Album)
+	assert.Equal(t, 2, metadata.TrackNumber)
+	assert.InDelta(t, 2.0, metadata.RawData[DurationField], 0.001)
+}
+
+func TestReadUntaggedContainers(t *testing.T) {
+	dir := t.TempDir()
+	untaggedWAV := append([]byte("RIFF\x04\x00\x00\x00"), []byte("WAVE")...)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.wav"), untaggedWAV, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.aac"), []byte{0xFF, 0xF1, 0x50, 0x80, 0x02, 0x1F, 0xFC}, 0o644))
+
+	for _, name := range []string{"a.wav", "b.aac"} {
+		_, err := NewAudioMetadataProvider(filepath.Join(dir, name)).GetMetadata()
+		assert.Error(t, err, name)
+	}
+}
+
+func TestNewFormatsAreSupported(t *testing.T) {
+	for _, ext := range []string{".opus", ".aac", ".wma", ".WAV"} {
+		assert.True(t, IsSupportedAudioFile(ext), ext)
+		assert.Equal(t, "audio", detectSourceType("book"+ext, false, nil), ext)
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "book.opus"), nil, 0o644))
+	found, err := FindAudioFileInDirectory(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "book.opus"), found)
+}
@@ -0,0 +1,280 @@
+// internal/organizer/audio_properties.go
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+var (
+	asfStreamPropertiesGUID = asfGUID("B7DC0791-A9B7-11CF-8EE6-00C00C205365")
+	asfAudioMediaGUID       = asfGUID("F8699E40-5B4D-11CF-A8FD-00805F5C442B")
+)
+
+// mp4AudioCodecs names the sample entries of MP4 audio tracks
+var mp4AudioCodecs = map[string]string{
+	"mp4a": "AAC",
+	"alac": "ALAC",
+	"ac-3": "AC-3",
+	"ec-3": "E-AC-3",
+	"Opus": "Opus",
+	"fLaC": "FLAC",
+	".mp3": "MP3",
+}
+
+// waveFormatCodecs names the WAVEFORMATEX format tags used by WAV and WMA files
+var waveFormatCodecs = map[uint16]string{
+	0x0001: "PCM",
+	0x0003: "PCM",
+	0x0055: "MP3",
+	0x0160: "WMA",
+	0x0161: "WMA",
+	0x0162: "WMA Pro",
+	0x0163: "WMA Lossless",
+	0xFFFE: "PCM",
+}
+
+// adtsSampleRates are indexed by the ADTS sampling frequency index
+var adtsSampleRates = [...]int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// readAudioInfo reads the codec, channel count, and sample rate from the headers of
+// an audio file. The bitrate is the container's nominal rate, or the file size over
+// duration when it has none. It returns nil when the headers are not recognized.
+func readAudioInfo(r io.ReadSeeker, duration time.Duration) *AudioInfo {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil
+	}
+	header := make([]byte, 16)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil
+	}
+	n, _ := io.ReadFull(r, header)
+	header = header[:n]
+
+	var info AudioInfo
+	switch {
+	case len(header) < 12:
+		return nil
+	case string(header[:4]) == "fLaC":
+		info = readFLACStreamInfo(r)
+	case string(header[:4]) == "OggS":
+		info = readOggStreamInfo(r)
+	case string(header[4:8]) == "ftyp":
+		info = readMP4StreamInfo(r, size)
+	case len(header) == 16 && bytes.Equal(header, asfHeaderGUID[:]):
+		info = readASFStreamInfo(r)
+	case string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		info = readWAVStreamInfo(r)
+	default:
+		info = readMPEGStreamInfo(r, id3v2Size(header))
+	}
+
+	info.Duration = duration.Seconds()
+	if info.Bitrate == 0 && duration > 0 && info.Codec != "" {
+		info.Bitrate = int(math.Round(float64(size) * 8 / duration.Seconds() / 1000))
+	}
+	if info.IsZero() {
+		return nil
+	}
+	return &info
+}
+
+// readFLACStreamInfo reads the sample rate and channel count from FLAC stream info
+func readFLACStreamInfo(r io.ReadSeeker) AudioInfo {
+	buf := make([]byte, 4+4+18)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return AudioInfo{}
+	}
+	if _, err := io.ReadFull(r, buf); err != nil || buf[4]&0x7f != 0 {
+		return AudioInfo{Codec: "FLAC"}
+	}
+	info := buf[8:]
+	return AudioInfo{
+		Codec:      "FLAC",
+		SampleRate: int(info[10])<<12 | int(info[11])<<4 | int(info[12])>>4,
+		Channels:   int(info[12]>>1&0x07) + 1,
+	}
+}
+
+// readOggStreamInfo reads the Vorbis or Opus identification header in the first Ogg
+// page. Vorbis headers carry a nominal bitrate; Opus always decodes at 48 kHz, so the
+// input rate it records is reported instead.
+func readOggStreamInfo(r io.ReadSeeker) AudioInfo {
+	page := make([]byte, 27)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return AudioInfo{}
+	}
+	if _, err := io.ReadFull(r, page); err != nil {
+		return AudioInfo{}
+	}
+	segments := make([]byte, page[26])
+	if _, err := io.ReadFull(r, segments); err != nil {
+		return AudioInfo{}
+	}
+	packet := make([]byte, 32)
+	n, _ := io.ReadFull(r, packet)
+	packet = packet[:n]
+
+	switch {
+	case len(packet) >= 28 && string(packet[:7]) == "\x01vorbis":
+		return AudioInfo{
+			Codec:      "Vorbis",
+			Channels:   int(packet[11]),
+			SampleRate: int(binary.LittleEndian.Uint32(packet[12:])),
+			Bitrate:    int(int32(binary.LittleEndian.Uint32(packet[20:]))) / 1000,
+		}
+	case len(packet) >= 16 && string(packet[:8]) == "OpusHead":
+		return AudioInfo{
+			Codec:      "Opus",
+			Channels:   int(packet[9]),
+			SampleRate: int(binary.LittleEndian.Uint32(packet[12:])),
+		}
+	}
+	return AudioInfo{}
+}
+
+// readMP4StreamInfo reads the first audio sample entry of an MP4 file's sample
+// descriptions, skipping the text tracks that hold chapter titles
+func readMP4StreamInfo(r io.ReadSeeker, size int64) AudioInfo {
+	var info AudioInfo
+	walkMP4Boxes(r, 0, size, func(name string, boxSize int64) bool {
+		switch name {
+		case "moov", "trak", "mdia", "minf", "stbl":
+			return info.Codec == ""
+		case "stsd":
+			// version/flags and entry count, then the first entry: its box header,
+			// 6 reserved bytes, data reference index, 8 version/vendor bytes, channel
+			// count, sample size, 4 reserved bytes, and a 16.16 sample rate
+			buf := make([]byte, 8+36)
+			if boxSize < int64(len(buf)) {
+				return false
+			}
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return false
+			}
+			entry := buf[8:]
+			codec, ok := mp4AudioCodecs[string(entry[4:8])]
+			if !ok {
+				return false
+			}
+			info = AudioInfo{
+				Codec:      codec,
+				Channels:   int(binary.BigEndian.Uint16(entry[24:])),
+				SampleRate: int(binary.BigEndian.Uint32(entry[32:]) >> 16),
+			}
+		}
+		return false
+	})
+	return info
+}
+
+// readASFStreamInfo reads the WAVEFORMATEX of the first audio stream in a WMA file
+func readASFStreamInfo(r io.ReadSeeker) AudioInfo {
+	var info AudioInfo
+	walkASFHeader(r, func(guid [16]byte, data []byte) {
+		// stream type, error correction type, time offset, data lengths, flags,
+		// reserved, then the type-specific data
+		const formatOffset = 16 + 16 + 8 + 4 + 4 + 2 + 4
+		if guid != asfStreamPropertiesGUID || info.Codec != "" || len(data) < formatOffset+12 {
+			return
+		}
+		if !bytes.Equal(data[:16], asfAudioMediaGUID[:]) {
+			return
+		}
+		info = waveFormatInfo(data[formatOffset:])
+		if info.Codec == "" {
+			info.Codec = "WMA"
+		}
+	})
+	return info
+}
+
+// readWAVStreamInfo reads the fmt chunk of a WAV file
+func readWAVStreamInfo(r io.ReadSeeker) AudioInfo {
+	var info AudioInfo
+	walkRIFFChunks(r, func(id string, size uint32) bool {
+		if id != "fmt " {
+			return true
+		}
+		format := make([]byte, 12)
+		if size >= 12 {
+			if _, err := io.ReadFull(r, format); err == nil {
+				info = waveFormatInfo(format)
+			}
+		}
+		return false
+	})
+	return info
+}
+
+// waveFormatInfo reads the start of a WAVEFORMATEX: format tag, channels, sample
+// rate, and average bytes per second
+func waveFormatInfo(format []byte) AudioInfo {
+	return AudioInfo{
+		Codec:      waveFormatCodecs[binary.LittleEndian.Uint16(format)],
+		Channels:   int(binary.LittleEndian.Uint16(format[2:])),
+		SampleRate: int(binary.LittleEndian.Uint32(format[4:])),
+		Bitrate:    int(binary.LittleEndian.Uint32(format[8:])) * 8 / 1000,
+	}
+}
+
+// readMPEGStreamInfo reads the first MP3 or ADTS AAC frame header after the ID3v2
+// tag that ends at start
+func readMPEGStreamInfo(r io.ReadSeeker, start int64) AudioInfo {
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return AudioInfo{}
+	}
+	buf := make([]byte, mp3SyncSearchLimit)
+	n, _ := io.ReadFull(r, buf)
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xFF || buf[i+1]&0xE0 != 0xE0 {
+			continue
+		}
+		version := (buf[i+1] >> 3) & 0x03
+		layer := (buf[i+1] >> 1) & 0x03
+
+		if buf[i+1]&0xF6 == 0xF0 { // ADTS: 12 sync bits and layer 0
+			rateIndex := int(buf[i+2]>>2) & 0x0F
+			if rateIndex >= len(adtsSampleRates) {
+				continue
+			}
+			return AudioInfo{
+				Codec:      "AAC",
+				SampleRate: adtsSampleRates[rateIndex],
+				Channels:   int(buf[i+2]&0x01)<<2 | int(buf[i+3]>>6),
+			}
+		}
+
+		rates, ok := mp3SampleRates[version]
+		rateIndex := (buf[i+2] >> 2) & 0x03
+		bitrateIndex := buf[i+2] >> 4
+		if !ok || layer != 1 || rateIndex == 3 || bitrateIndex == 0 || bitrateIndex == 15 {
+			continue
+		}
+		channels := 2
+		if buf[i+3]>>6 == 3 {
+			channels = 1
+		}
+		return AudioInfo{Codec: "MP3", SampleRate: rates[rateIndex], Channels: channels}
+	}
+	return AudioInfo{}
+}
+
+// id3v2Size returns the length of the ID3v2 tag that header starts with, or 0
+func id3v2Size(header []byte) int64 {
+	if len(header) < 10 || string(header[:3]) != "ID3" {
+		return 0
+	}
+	size := 10 + (int64(header[6]&0x7f)<<21 | int64(header[7]&0x7f)<<14 |
+		int64(header[8]&0x7f)<<7 | int64(header[9]&0x7f))
+	if header[5]&0x10 != 0 {
+		size += 10 // footer
+	}
+	return size
+}
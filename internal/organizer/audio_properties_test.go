@@ -0,0 +1,59 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAudioInfo(t *testing.T) {
+	wav := readAudioInfo(bytes.NewReader(buildWAV()), 2*time.Second)
+	require.NotNil(t, wav)
+	assert.Equal(t, AudioInfo{Codec: "PCM", Bitrate: 128, SampleRate: 8000, Channels: 1, Duration: 2}, *wav)
+
+	// A lone ADTS frame header: AAC LC, 44.1 kHz, stereo
+	adts := []byte{0xFF, 0xF1, 0x50, 0x80, 0x02, 0x1F, 0xFC, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	aac := readAudioInfo(bytes.NewReader(adts), 0)
+	require.NotNil(t, aac)
+	assert.Equal(t, "AAC", aac.Codec)
+	assert.Equal(t, 44100, aac.SampleRate)
+	assert.Equal(t, 2, aac.Channels)
+
+	assert.Nil(t, readAudioInfo(bytes.NewReader(make([]byte, 64)), 0))
+}
+
+func TestMetadataIncludesAudioInfo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.wav")
+	require.NoError(t, os.WriteFile(path, buildWAV(), 0o644))
+
+	metadata, err := NewAudioMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	require.NotNil(t, metadata.Audio)
+	assert.Equal(t, "PCM", metadata.Audio.Codec)
+	assert.InDelta(t, 2.0, metadata.Audio.Duration, 0.001)
+	assert.Contains(t, NewMetadataFormatter(metadata, FieldMapping{}).FormatMetadata(), "PCM, 128 kbps, mono, 8 kHz")
+}
+
+func TestAuthorVariantBestCopy(t *testing.T) {
+	detector := NewAuthorVariantDetector()
+	detector.Add("/lib/1", Metadata{Title: "Mistborn", Authors: []string{"Brandon Sanderson"}, Audio: &AudioInfo{Codec: "MP3", Bitrate: 64}})
+	detector.Add("/lib/2", Metadata{Title: "Mistborn", Authors: []string{"Brandon Sandersen"}, Audio: &AudioInfo{Codec: "AAC", Bitrate: 128}})
+
+	suggestions := detector.Suggestions()
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "/lib/2", suggestions[0].BestCopy)
+	assert.Equal(t, 128, suggestions[0].Variants[1].Audio["/lib/2"].Bitrate)
+
+	// Copies that can't be told apart have no best copy
+	tied := NewAuthorVariantDetector()
+	tied.Add("/lib/1", Metadata{Title: "Mistborn", Authors: []string{"Brandon Sanderson"}, Audio: &AudioInfo{Bitrate: 64}})
+	tied.Add("/lib/2", Metadata{Title: "Mistborn", Authors: []string{"Brandon Sandersen"}, Audio: &AudioInfo{Bitrate: 64}})
+	assert.Empty(t, tied.Suggestions()[0].BestCopy)
+}
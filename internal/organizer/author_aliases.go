@@ -0,0 +1,77 @@
+package organizer
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// AuthorAlias shelves the books of a pen name under another author folder, such as
+// "Robert Galbraith" under "J.K. Rowling" or "J.K. Rowling (as Robert Galbraith)"
+type AuthorAlias struct {
+	Name   string // Author name as found in metadata, matched ignoring case
+	Author string // Name used for the author folder instead
+}
+
+// authorAliasField records in RawData the names aliases replaced, so the verbose
+// metadata output can show where an author folder came from
+const authorAliasField = "_author_alias"
+
+// ParseAuthorAliases parses --author-alias entries of the form "Pen Name=Author"
+func ParseAuthorAliases(entries []string) ([]AuthorAlias, error) {
+	var aliases []AuthorAlias
+	for _, entry := range entries {
+		name, author, ok := strings.Cut(entry, "=")
+		name, author = strings.TrimSpace(name), strings.TrimSpace(author)
+		if !ok || name == "" || author == "" {
+			return nil, fmt.Errorf("invalid author alias %q (use \"Pen Name=Author\")", entry)
+		}
+		aliases = append(aliases, AuthorAlias{Name: name, Author: author})
+	}
+	return aliases, nil
+}
+
+// applyAuthorAliases replaces the authors of metadata that have an alias. Authors
+// that end up with the same name are kept once, so a book credited to both an author
+// and their pen name lands in a single folder.
+func (o *Organizer) applyAuthorAliases(metadata Metadata) Metadata {
+	if len(o.config.AuthorAliases) == 0 || len(metadata.Authors) == 0 {
+		return metadata
+	}
+
+	var authors, replaced []string
+	seen := make(map[string]bool)
+	for _, author := range metadata.Authors {
+		if alias, ok := o.authorAlias(author); ok {
+			replaced = append(replaced, author)
+			author = alias
+		}
+		if key := strings.ToLower(author); !seen[key] {
+			seen[key] = true
+			authors = append(authors, author)
+		}
+	}
+	if len(replaced) == 0 {
+		return metadata
+	}
+
+	// RawData may be shared with the metadata cache, so it is copied before marking
+	metadata.RawData = maps.Clone(metadata.RawData)
+	if metadata.RawData == nil {
+		metadata.RawData = make(map[string]interface{})
+	}
+	metadata.RawData[authorAliasField] = strings.Join(replaced, ", ")
+	metadata.Authors = authors
+	return metadata
+}
+
+// authorAlias returns the folder name configured for author
+func (o *Organizer) authorAlias(author string) (string, bool) {
+	name := strings.TrimSpace(author)
+	for _, alias := range o.config.AuthorAliases {
+		if strings.EqualFold(alias.Name, name) {
+			return alias.Author, true
+		}
+	}
+	return "", false
+}
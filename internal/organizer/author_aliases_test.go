@@ -0,0 +1,69 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuthorAliases(t *testing.T) {
+	aliases, err := ParseAuthorAliases([]string{
+		"Robert Galbraith=J.K. Rowling (as Robert Galbraith)",
+		" Richard Bachman = Stephen King ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []AuthorAlias{
+		{Name: "Robert Galbraith", Author: "J.K. Rowling (as Robert Galbraith)"},
+		{Name: "Richard Bachman", Author: "Stephen King"},
+	}, aliases)
+
+	for _, invalid := range []string{"Robert Galbraith", "=J.K. Rowling", "Robert Galbraith="} {
+		_, err := ParseAuthorAliases([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestApplyAuthorAliases(t *testing.T) {
+	org := &Organizer{config: OrganizerConfig{AuthorAliases: []AuthorAlias{
+		{Name: "Robert Galbraith", Author: "J.K. Rowling"},
+	}}}
+	raw := map[string]interface{}{"title": "The Cuckoo's Calling"}
+
+	metadata := org.applyAuthorAliases(Metadata{
+		Authors: []string{"robert galbraith", "J.K. Rowling"},
+		RawData: raw,
+	})
+	assert.Equal(t, []string{"J.K. Rowling"}, metadata.Authors)
+	assert.Equal(t, "robert galbraith", metadata.RawData[authorAliasField])
+	assert.NotContains(t, raw, authorAliasField, "the provider's raw data must not change")
+
+	unchanged := org.applyAuthorAliases(Metadata{Authors: []string{"Stephen King"}, RawData: raw})
+	assert.Equal(t, []string{"Stephen King"}, unchanged.Authors)
+	assert.NotContains(t, unchanged.RawData, authorAliasField)
+}
+
+func TestOrganizeWithAuthorAlias(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Cuckoo"), `{"title":"The Cuckoo's Calling","authors":["Robert Galbraith"]}`, "01.mp3")
+	writeDisc(t, filepath.Join(base, "Stone"), `{"title":"Philosopher's Stone","authors":["J.K. Rowling"]}`, "01.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:       base,
+		OutputDir:     out,
+		Verbose:       true,
+		AuthorAliases: []AuthorAlias{{Name: "Robert Galbraith", Author: "J.K. Rowling"}},
+	})
+	require.NoError(t, err)
+	output := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	assert.ElementsMatch(t, []string{"Philosopher's Stone", "The Cuckoo's Calling"}, dirNames(t, filepath.Join(out, "J.K. Rowling")))
+	assert.NoDirExists(t, filepath.Join(out, "Robert Galbraith"))
+	assert.Contains(t, output, "(author alias for Robert Galbraith)")
+}
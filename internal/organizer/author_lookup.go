@@ -0,0 +1,322 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultAuthorAuthorityURL is the OpenLibrary API queried by --author-lookup. Any
+// service answering /search/authors.json in the same format can replace it.
+const DefaultAuthorAuthorityURL = "https://openlibrary.org"
+
+// AuthorLookupCacheName is the cache file kept in the user cache directory
+const AuthorLookupCacheName = "author-lookup.json"
+
+// Kinds of author correction
+const (
+	AuthorCorrectionSpelling  = "spelling"   // Same person, canonical spelling differs
+	AuthorCorrectionPseudonym = "pseudonym"  // A pen name of another author; never applied
+	AuthorCorrectionCoAuthors = "co-authors" // Several authors written as one name
+)
+
+// errOffline is returned for uncached names when the network is off
+var errOffline = errors.New("not cached and network lookups are disabled")
+
+// coAuthorSeparator splits "A & B", "A and B", and "A; B" into separate authors
+var coAuthorSeparator = regexp.MustCompile(`\s*(?:&|;|\band\b)\s*`)
+
+// AuthorRecord is one author known to an authority
+type AuthorRecord struct {
+	Key            string   `json:"key"`
+	Name           string   `json:"name"`
+	AlternateNames []string `json:"alternate_names,omitempty"`
+	WorkCount      int      `json:"work_count"`
+}
+
+// AuthorCorrection suggests a canonical form for an author name found in metadata
+type AuthorCorrection struct {
+	Author    string   `json:"author"`
+	Suggested []string `json:"suggested"`
+	Kind      string   `json:"kind"`
+	Key       string   `json:"key,omitempty"` // Authority ID of the suggested author
+	Books     int      `json:"books"`
+	Applied   bool     `json:"applied"`
+}
+
+// AuthorLookup canonicalizes author names against an external authority. Answers
+// are cached on disk, including names the authority doesn't know, so each name is
+// only fetched once.
+type AuthorLookup struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Offline    bool // Only use the cache
+	cachePath  string
+	cache      map[string][]AuthorRecord
+	dirty      bool
+}
+
+// NewAuthorLookup creates a lookup against baseURL (DefaultAuthorAuthorityURL when
+// empty) with its cache at cachePath (in the user cache directory when empty)
+func NewAuthorLookup(baseURL, cachePath string, offline bool) (*AuthorLookup, error) {
+	if baseURL == "" {
+		baseURL = DefaultAuthorAuthorityURL
+	}
+	if cachePath == "" {
+		var err error
+		if cachePath, err = DefaultAuthorLookupCachePath(); err != nil {
+			return nil, err
+		}
+	}
+
+	lookup := &AuthorLookup{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+		Offline:    offline,
+		cachePath:  cachePath,
+		cache:      make(map[string][]AuthorRecord),
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading author cache: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &lookup.cache); err != nil {
+			return nil, fmt.Errorf("error reading author cache %s: %w", cachePath, err)
+		}
+	}
+	return lookup, nil
+}
+
+// DefaultAuthorLookupCachePath returns where the author lookup cache is kept in the
+// user cache directory
+func DefaultAuthorLookupCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding cache directory: %w", err)
+	}
+	return filepath.Join(dir, StateDirName, AuthorLookupCacheName), nil
+}
+
+// Save writes new answers to the cache file
+func (l *AuthorLookup) Save() error {
+	if !l.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.cachePath), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l.cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(l.cachePath, data, 0o644); err != nil {
+		return err
+	}
+	l.dirty = false
+	return nil
+}
+
+// Correct returns the correction for author, or nil when the name is already
+// canonical or unknown to the authority
+func (l *AuthorLookup) Correct(author string) (*AuthorCorrection, error) {
+	records, err := l.records(author)
+	if err != nil {
+		return nil, err
+	}
+	if record, kind, ok := matchAuthorRecord(author, records); ok {
+		if kind == AuthorCorrectionSpelling && record.Name == author {
+			return nil, nil
+		}
+		return &AuthorCorrection{Author: author, Suggested: []string{record.Name}, Kind: kind, Key: record.Key}, nil
+	}
+
+	// "A & B" unknown as a whole but known as two people is two co-authors
+	parts := coAuthorSeparator.Split(author, -1)
+	if len(parts) < 2 {
+		return nil, nil
+	}
+	correction := &AuthorCorrection{Author: author, Kind: AuthorCorrectionCoAuthors}
+	for _, part := range parts {
+		records, err := l.records(part)
+		if err != nil {
+			return nil, err
+		}
+		record, kind, ok := matchAuthorRecord(part, records)
+		if !ok || kind == AuthorCorrectionPseudonym {
+			return nil, nil
+		}
+		correction.Suggested = append(correction.Suggested, record.Name)
+	}
+	return correction, nil
+}
+
+// matchAuthorRecord finds the record for name: the author with that name or a
+// similar spelling, or one who published under it. Authors with more works win.
+func matchAuthorRecord(name string, records []AuthorRecord) (AuthorRecord, string, bool) {
+	normalized := normalizeForComparison(name, false)
+	if normalized == "" {
+		return AuthorRecord{}, "", false
+	}
+	sorted := append([]AuthorRecord(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].WorkCount > sorted[j].WorkCount })
+
+	for _, record := range sorted {
+		if normalizeForComparison(record.Name, false) == normalized {
+			return record, AuthorCorrectionSpelling, true
+		}
+	}
+	for _, record := range sorted {
+		canonical := normalizeForComparison(record.Name, false)
+		for _, alternate := range record.AlternateNames {
+			if normalizeForComparison(alternate, false) != normalized {
+				continue
+			}
+			if stringSimilarity(canonical, normalized) >= authorSimilarityThreshold {
+				return record, AuthorCorrectionSpelling, true
+			}
+			return record, AuthorCorrectionPseudonym, true
+		}
+	}
+	for _, record := range sorted {
+		if stringSimilarity(normalizeForComparison(record.Name, false), normalized) >= authorSimilarityThreshold {
+			return record, AuthorCorrectionSpelling, true
+		}
+	}
+	return AuthorRecord{}, "", false
+}
+
+// records returns the authority's answer for name, from the cache when possible
+func (l *AuthorLookup) records(name string) ([]AuthorRecord, error) {
+	key := normalizeForComparison(name, true)
+	if records, ok := l.cache[key]; ok {
+		return records, nil
+	}
+	if l.Offline {
+		return nil, errOffline
+	}
+
+	records, err := l.search(name)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []AuthorRecord{} // Cache misses too
+	}
+	l.cache[key] = records
+	l.dirty = true
+	return records, nil
+}
+
+// search queries the authority's author search
+func (l *AuthorLookup) search(name string) ([]AuthorRecord, error) {
+	endpoint := l.BaseURL + "/search/authors.json?limit=5&q=" + url.QueryEscape(name)
+	request, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := l.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("author lookup for %q failed: %w", name, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("author lookup for %q failed: %s", name, response.Status)
+	}
+
+	var result struct {
+		Docs []AuthorRecord `json:"docs"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("author lookup for %q returned invalid JSON: %w", name, err)
+	}
+	return result.Docs, nil
+}
+
+// canonicalizeAuthors looks up every author of metadata when AuthorLookup is enabled,
+// recording the corrections and applying them when ApplyAuthorLookup is set.
+// Pseudonyms are only reported, since pen names are usually shelved on their own.
+func (o *Organizer) canonicalizeAuthors(metadata Metadata) Metadata {
+	if !o.config.AuthorLookup || len(metadata.Authors) == 0 {
+		return metadata
+	}
+	if o.authorLookup == nil {
+		lookup, err := NewAuthorLookup(o.config.AuthorAuthorityURL, "", o.config.NoNetwork)
+		if err != nil {
+			PrintYellow("⚠️  Warning: author lookup disabled: %v", err)
+			o.config.AuthorLookup = false
+			return metadata
+		}
+		o.authorLookup = lookup
+	}
+
+	var authors []string
+	for _, author := range metadata.Authors {
+		correction, err := o.authorCorrection(author)
+		if err != nil {
+			o.debugLog("Author lookup for %s: %v", author, err)
+		}
+		if correction == nil || !correction.Applied {
+			authors = append(authors, author)
+			continue
+		}
+		authors = append(authors, correction.Suggested...)
+	}
+	metadata.Authors = authors
+	return metadata
+}
+
+// authorCorrection returns the run's correction for author, looking it up the first
+// time the name is seen
+func (o *Organizer) authorCorrection(author string) (*AuthorCorrection, error) {
+	for i := range o.summary.AuthorCorrections {
+		if o.summary.AuthorCorrections[i].Author == author {
+			o.summary.AuthorCorrections[i].Books++
+			return &o.summary.AuthorCorrections[i], nil
+		}
+	}
+	if o.authorChecked[author] {
+		return nil, nil
+	}
+	if o.authorChecked == nil {
+		o.authorChecked = make(map[string]bool)
+	}
+	o.authorChecked[author] = true
+
+	correction, err := o.authorLookup.Correct(author)
+	if err != nil && !errors.Is(err, errOffline) {
+		// One failure is enough to assume the authority is unreachable for this run
+		PrintYellow("⚠️  Warning: %v; using cached author lookups only", err)
+		o.authorLookup.Offline = true
+	}
+	if correction == nil || err != nil {
+		return nil, err
+	}
+	correction.Books = 1
+	correction.Applied = o.config.ApplyAuthorLookup && correction.Kind != AuthorCorrectionPseudonym
+	o.summary.AuthorCorrections = append(o.summary.AuthorCorrections, *correction)
+	return correction, nil
+}
+
+// PrintAuthorCorrections prints the author corrections of a run
+func PrintAuthorCorrections(corrections []AuthorCorrection) {
+	PrintYellow("\n📖 Author lookup suggestions: %d", len(corrections))
+	for _, correction := range corrections {
+		state := "suggested"
+		if correction.Applied {
+			state = "applied"
+		}
+		PrintBase("  %q (%d) -> %q [%s, %s]", correction.Author, correction.Books,
+			strings.Join(correction.Suggested, ", "), correction.Kind, state)
+	}
+}
@@ -0,0 +1,152 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuthorAuthority serves /search/authors.json from a fixed table of answers and
+// counts the requests it receives
+func newAuthorAuthority(t *testing.T, answers map[string][]AuthorRecord) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/search/authors.json", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{"docs": answers[r.URL.Query().Get("q")]})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+var testAuthorAnswers = map[string][]AuthorRecord{
+	"JK Rowling": {{Key: "OL23919A", Name: "J. K. Rowling", WorkCount: 300}},
+	"Robert Galbraith": {
+		{Key: "OL1A", Name: "Robert Galbraith Heath", WorkCount: 2},
+		{Key: "OL23919A", Name: "J. K. Rowling", AlternateNames: []string{"Robert Galbraith"}, WorkCount: 300},
+	},
+	"Brandon Sandersen": {{Key: "OL1394865A", Name: "Brandon Sanderson", WorkCount: 200}},
+	"Brandon Sanderson": {{Key: "OL1394865A", Name: "Brandon Sanderson", WorkCount: 200}},
+	"Douglas Preston":   {{Key: "OL2A", Name: "Douglas Preston", WorkCount: 90}},
+	"Lincoln Child":     {{Key: "OL3A", Name: "Lincoln Child", WorkCount: 60}},
+}
+
+func TestAuthorLookupCorrect(t *testing.T) {
+	server, requests := newAuthorAuthority(t, testAuthorAnswers)
+	cachePath := filepath.Join(t.TempDir(), "cache", AuthorLookupCacheName)
+	lookup, err := NewAuthorLookup(server.URL, cachePath, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		author    string
+		kind      string
+		suggested []string
+	}{
+		{"JK Rowling", AuthorCorrectionSpelling, []string{"J. K. Rowling"}},
+		{"Brandon Sandersen", AuthorCorrectionSpelling, []string{"Brandon Sanderson"}},
+		{"Robert Galbraith", AuthorCorrectionPseudonym, []string{"J. K. Rowling"}},
+		{"Douglas Preston & Lincoln Child", AuthorCorrectionCoAuthors, []string{"Douglas Preston", "Lincoln Child"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.author, func(t *testing.T) {
+			correction, err := lookup.Correct(tt.author)
+			require.NoError(t, err)
+			require.NotNil(t, correction)
+			assert.Equal(t, tt.kind, correction.Kind)
+			assert.Equal(t, tt.suggested, correction.Suggested)
+		})
+	}
+
+	correction, err := lookup.Correct("Brandon Sanderson")
+	require.NoError(t, err)
+	assert.Nil(t, correction, "canonical names need no correction")
+	correction, err = lookup.Correct("Nobody Known")
+	require.NoError(t, err)
+	assert.Nil(t, correction)
+
+	require.NoError(t, lookup.Save())
+	fetched := *requests
+
+	offline, err := NewAuthorLookup(server.URL, cachePath, true)
+	require.NoError(t, err)
+	correction, err = offline.Correct("JK Rowling")
+	require.NoError(t, err)
+	require.NotNil(t, correction, "cached answers work offline")
+	correction, err = offline.Correct("Nobody Known")
+	require.NoError(t, err)
+	assert.Nil(t, correction, "cached misses work offline")
+	_, err = offline.Correct("Terry Pratchett")
+	assert.ErrorIs(t, err, errOffline)
+	assert.Equal(t, fetched, *requests, "offline lookups never reach the network")
+}
+
+func TestOrganizerAuthorLookup(t *testing.T) {
+	server, requests := newAuthorAuthority(t, testAuthorAnswers)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	run := func(t *testing.T, config OrganizerConfig) (*Organizer, string) {
+		t.Helper()
+		config.BaseDir = t.TempDir()
+		config.OutputDir = t.TempDir()
+		config.Layout = "author-title"
+		config.AuthorAuthorityURL = server.URL
+		for _, book := range []struct{ dir, author string }{
+			{"Cuckoo", "Robert Galbraith"},
+			{"Elantris", "Brandon Sandersen"},
+			{"Relic", "Douglas Preston & Lincoln Child"},
+		} {
+			dir := filepath.Join(config.BaseDir, book.dir)
+			require.NoError(t, os.MkdirAll(dir, 0o755))
+			data, _ := json.Marshal(map[string]any{"title": book.dir, "authors": []string{book.author}})
+			require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataFileName), data, 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "01.mp3"), []byte("audio"), 0o644))
+		}
+		org, err := NewOrganizer(&config)
+		require.NoError(t, err)
+		require.NoError(t, org.Execute())
+		return org, config.OutputDir
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		org, _ := run(t, OrganizerConfig{DryRun: true})
+		assert.Empty(t, org.GetSummary().AuthorCorrections)
+		assert.Zero(t, *requests)
+	})
+
+	t.Run("suggestions only", func(t *testing.T) {
+		org, outputDir := run(t, OrganizerConfig{AuthorLookup: true})
+		corrections := org.GetSummary().AuthorCorrections
+		require.Len(t, corrections, 3)
+		for _, correction := range corrections {
+			assert.False(t, correction.Applied)
+		}
+		assert.DirExists(t, filepath.Join(outputDir, "Brandon Sandersen", "Elantris"))
+	})
+
+	t.Run("applied", func(t *testing.T) {
+		org, outputDir := run(t, OrganizerConfig{AuthorLookup: true, ApplyAuthorLookup: true})
+		assert.DirExists(t, filepath.Join(outputDir, "Brandon Sanderson", "Elantris"))
+		assert.DirExists(t, filepath.Join(outputDir, "Douglas Preston,Lincoln Child", "Relic"))
+		assert.DirExists(t, filepath.Join(outputDir, "Robert Galbraith", "Cuckoo"), "pseudonyms are never applied")
+		for _, correction := range org.GetSummary().AuthorCorrections {
+			assert.Equal(t, correction.Kind != AuthorCorrectionPseudonym, correction.Applied, correction.Author)
+		}
+	})
+
+	t.Run("no network", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", t.TempDir())
+		before := *requests
+		org, _ := run(t, OrganizerConfig{DryRun: true, AuthorLookup: true, NoNetwork: true})
+		assert.Empty(t, org.GetSummary().AuthorCorrections)
+		assert.Equal(t, before, *requests)
+	})
+}
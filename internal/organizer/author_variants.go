@@ -0,0 +1,231 @@
+package organizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// authorSimilarityThreshold is how alike two normalized author names must be, by
+// stringSimilarity, to count as spellings of the same person
+const authorSimilarityThreshold = 0.8
+
+// AuthorVariant is one spelling of an author found for a title
+type AuthorVariant struct {
+	Author string               `json:"author"`
+	Paths  []string             `json:"paths"`
+	Audio  map[string]AudioInfo `json:"audio,omitempty"` // Audio stream of each path, when known
+}
+
+// AuthorMergeSuggestion flags a title that appears under several similar author
+// spellings, which usually means some of the books are mis-tagged
+type AuthorMergeSuggestion struct {
+	Title     string          `json:"title"`
+	Suggested string          `json:"suggested"`           // The spelling most of the books use
+	Variants  []AuthorVariant `json:"variants"`            // Suggested spelling first
+	BestCopy  string          `json:"best_copy,omitempty"` // Path with the best audio quality, when the copies differ
+}
+
+// AuthorVariantDetector collects the title and authors of each book so that near
+// duplicate author trees can be reported before they are created
+type AuthorVariantDetector struct {
+	titles map[string]*titleAuthors
+	order  []string
+}
+
+// titleAuthors holds the author spellings seen for one normalized title
+type titleAuthors struct {
+	title    string
+	variants map[string]*AuthorVariant
+	order    []string
+}
+
+// NewAuthorVariantDetector creates an empty detector
+func NewAuthorVariantDetector() *AuthorVariantDetector {
+	return &AuthorVariantDetector{titles: make(map[string]*titleAuthors)}
+}
+
+// Add records the book at path. Books without a title or author are ignored.
+func (d *AuthorVariantDetector) Add(path string, metadata Metadata) {
+	key := normalizeForComparison(metadata.Title, true)
+	author := strings.TrimSpace(strings.Join(metadata.Authors, ", "))
+	if key == "" || author == "" {
+		return
+	}
+
+	entry, ok := d.titles[key]
+	if !ok {
+		entry = &titleAuthors{title: metadata.Title, variants: make(map[string]*AuthorVariant)}
+		d.titles[key] = entry
+		d.order = append(d.order, key)
+	}
+	variant, ok := entry.variants[author]
+	if !ok {
+		variant = &AuthorVariant{Author: author}
+		entry.variants[author] = variant
+		entry.order = append(entry.order, author)
+	}
+	variant.Paths = append(variant.Paths, path)
+	if metadata.Audio != nil {
+		if variant.Audio == nil {
+			variant.Audio = make(map[string]AudioInfo)
+		}
+		variant.Audio[path] = *metadata.Audio
+	}
+}
+
+// Suggestions returns one merge suggestion for every set of similar author spellings
+// found under the same title. Different authors who happen to share a title are not
+// similar enough to be reported.
+func (d *AuthorVariantDetector) Suggestions() []AuthorMergeSuggestion {
+	var suggestions []AuthorMergeSuggestion
+	for _, key := range d.order {
+		entry := d.titles[key]
+		if len(entry.order) < 2 {
+			continue
+		}
+		for _, cluster := range clusterAuthors(entry.order) {
+			if len(cluster) < 2 {
+				continue
+			}
+			suggestions = append(suggestions, entry.suggestion(cluster))
+		}
+	}
+	return suggestions
+}
+
+// suggestion orders a cluster of spellings with the most used one first
+func (t *titleAuthors) suggestion(cluster []string) AuthorMergeSuggestion {
+	best := 0
+	for i, author := range cluster {
+		if len(t.variants[author].Paths) > len(t.variants[cluster[best]].Paths) {
+			best = i
+		}
+	}
+
+	suggestion := AuthorMergeSuggestion{Title: t.title, Suggested: cluster[best]}
+	suggestion.Variants = append(suggestion.Variants, *t.variants[cluster[best]])
+	for i, author := range cluster {
+		if i != best {
+			suggestion.Variants = append(suggestion.Variants, *t.variants[author])
+		}
+	}
+	suggestion.BestCopy = bestCopy(suggestion.Variants)
+	return suggestion
+}
+
+// bestCopy returns the path with the best audio quality across the variants, or ""
+// when no copy is better than all the others
+func bestCopy(variants []AuthorVariant) string {
+	var best string
+	var bestAudio AudioInfo
+	tied := false
+	for _, variant := range variants {
+		for _, path := range variant.Paths {
+			audio, ok := variant.Audio[path]
+			if !ok {
+				continue
+			}
+			switch {
+			case best == "" || audio.Better(bestAudio):
+				best, bestAudio, tied = path, audio, false
+			case !bestAudio.Better(audio):
+				tied = true
+			}
+		}
+	}
+	if tied {
+		return ""
+	}
+	return best
+}
+
+// clusterAuthors groups spellings that are transitively similar, keeping the order in
+// which they were first seen
+func clusterAuthors(authors []string) [][]string {
+	normalized := make([]string, len(authors))
+	for i, author := range authors {
+		normalized[i] = normalizeForComparison(author, false)
+	}
+
+	cluster := make([]int, len(authors))
+	for i := range cluster {
+		cluster[i] = i
+	}
+	for i := range authors {
+		for j := i + 1; j < len(authors); j++ {
+			if !authorsSimilar(normalized[i], normalized[j]) {
+				continue
+			}
+			from, to := cluster[j], cluster[i]
+			for k := range cluster {
+				if cluster[k] == from {
+					cluster[k] = to
+				}
+			}
+		}
+	}
+
+	var clusters [][]string
+	index := make(map[int]int)
+	for i, author := range authors {
+		n, ok := index[cluster[i]]
+		if !ok {
+			n = len(clusters)
+			index[cluster[i]] = n
+			clusters = append(clusters, nil)
+		}
+		clusters[n] = append(clusters[n], author)
+	}
+	return clusters
+}
+
+func authorsSimilar(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || stringSimilarity(a, b) >= authorSimilarityThreshold
+}
+
+// normalizeForComparison lowercases s and drops punctuation, so "J.R.R. Tolkien" and
+// "J. R. R. Tolkien" compare equal. Titles keep single spaces between words; author
+// names drop spaces entirely.
+func normalizeForComparison(s string, keepSpaces bool) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && keepSpaces && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// PrintAuthorMergeSuggestions prints the suggested author merges of a run
+func PrintAuthorMergeSuggestions(suggestions []AuthorMergeSuggestion, verbose bool) {
+	PrintYellow("\n👥 Possible author misspellings: %d", len(suggestions))
+	for _, suggestion := range suggestions {
+		PrintBase("  %s", suggestion.Title)
+		for _, variant := range suggestion.Variants[1:] {
+			PrintBase("    %q (%d) -> %q", variant.Author, len(variant.Paths), suggestion.Suggested)
+			if verbose {
+				for _, path := range variant.Paths {
+					if audio, ok := variant.Audio[path]; ok {
+						PrintBase("      %s (%s)", path, audio)
+					} else {
+						PrintBase("      %s", path)
+					}
+				}
+			}
+		}
+		if suggestion.BestCopy != "" {
+			PrintBase("    Best quality copy: %s", suggestion.BestCopy)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorVariantDetector(t *testing.T) {
+	detector := NewAuthorVariantDetector()
+	add := func(path, title, author string) {
+		detector.Add(path, Metadata{Title: title, Authors: []string{author}})
+	}
+	add("/lib/1", "The Way of Kings", "Brandon Sanderson")
+	add("/lib/2", "The Way of Kings", "Brandon Sandersen")
+	add("/lib/3", "the way of kings!", "Brandon Sanderson")
+	add("/lib/4", "The Hobbit", "J.R.R. Tolkien")
+	add("/lib/5", "The Hobbit", "J. R. R. Tolkien")
+	// Different people who share a title are left alone
+	add("/lib/6", "Dune", "Frank Herbert")
+	add("/lib/7", "Dune", "Brian Herbert & Kevin J. Anderson")
+	add("/lib/8", "Untitled", "")
+
+	suggestions := detector.Suggestions()
+	require.Len(t, suggestions, 2)
+
+	kings := suggestions[0]
+	assert.Equal(t, "The Way of Kings", kings.Title)
+	assert.Equal(t, "Brandon Sanderson", kings.Suggested)
+	require.Len(t, kings.Variants, 2)
+	assert.Equal(t, []string{"/lib/1", "/lib/3"}, kings.Variants[0].Paths)
+	assert.Equal(t, "Brandon Sandersen", kings.Variants[1].Author)
+
+	hobbit := suggestions[1]
+	assert.Equal(t, "J.R.R. Tolkien", hobbit.Suggested)
+	assert.Equal(t, "J. R. R. Tolkien", hobbit.Variants[1].Author)
+}
+
+func TestOrganizerCheckAuthors(t *testing.T) {
+	baseDir := t.TempDir()
+	createBookDir(t, baseDir, "A", "Mistborn", "Brandon Sanderson")
+	createBookDir(t, baseDir, "B", "Mistborn", "Brandon Sandersen")
+
+	config := OrganizerConfig{BaseDir: baseDir, OutputDir: t.TempDir(), DryRun: true}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+	assert.Empty(t, org.GetSummary().AuthorVariants, "the check is opt-in")
+
+	config.CheckAuthors = true
+	org, err = NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	summary := org.GetSummary()
+	require.Len(t, summary.AuthorVariants, 1)
+	assert.Equal(t, "Mistborn", summary.AuthorVariants[0].Title)
+	assert.Len(t, NewRunReport(summary, true, nil).AuthorVariants, 1)
+}
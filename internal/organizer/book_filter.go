@@ -0,0 +1,50 @@
+package organizer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BookFilter narrows a run to some of the books found. Empty fields match everything.
+type BookFilter struct {
+	Paths        []string       // Books at or below one of these paths
+	Authors      []string       // Books with one of these authors, ignoring case
+	TitlePattern *regexp.Regexp // Books whose title matches
+}
+
+// IsEmpty reports whether the filter lets every book through
+func (f BookFilter) IsEmpty() bool {
+	return len(f.Paths) == 0 && len(f.Authors) == 0 && f.TitlePattern == nil
+}
+
+// MatchesPath reports whether a book path is one of Paths or lies below one of them
+func (f BookFilter) MatchesPath(path string) bool {
+	if len(f.Paths) == 0 {
+		return true
+	}
+	for _, p := range f.Paths {
+		if path == p || isSubPathOf(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesMetadata reports whether a book's metadata satisfies the author and title
+// filters
+func (f BookFilter) MatchesMetadata(metadata Metadata) bool {
+	if f.TitlePattern != nil && !f.TitlePattern.MatchString(metadata.Title) {
+		return false
+	}
+	if len(f.Authors) == 0 {
+		return true
+	}
+	for _, want := range f.Authors {
+		for _, author := range metadata.Authors {
+			if strings.EqualFold(strings.TrimSpace(author), strings.TrimSpace(want)) {
+				return true
+			}
+		}
+	}
+	return false
+}
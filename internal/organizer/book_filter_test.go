@@ -0,0 +1,98 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookFilter(t *testing.T) {
+	dune := Metadata{Title: "Dune Messiah", Authors: []string{"Frank Herbert"}}
+	emma := Metadata{Title: "Emma", Authors: []string{"Jane Austen"}}
+
+	t.Run("empty filter matches everything", func(t *testing.T) {
+		var filter BookFilter
+		assert.True(t, filter.IsEmpty())
+		assert.True(t, filter.MatchesPath("/books/anything"))
+		assert.True(t, filter.MatchesMetadata(emma))
+	})
+
+	t.Run("paths match themselves and below", func(t *testing.T) {
+		filter := BookFilter{Paths: []string{"/books/Frank Herbert"}}
+		assert.True(t, filter.MatchesPath("/books/Frank Herbert"))
+		assert.True(t, filter.MatchesPath("/books/Frank Herbert/Dune/01.mp3"))
+		assert.False(t, filter.MatchesPath("/books/Frank Herbert Jr"))
+		assert.False(t, filter.MatchesPath("/books"))
+	})
+
+	t.Run("authors ignore case", func(t *testing.T) {
+		filter := BookFilter{Authors: []string{"frank herbert ", "Someone Else"}}
+		assert.True(t, filter.MatchesMetadata(dune))
+		assert.False(t, filter.MatchesMetadata(emma))
+	})
+
+	t.Run("title pattern", func(t *testing.T) {
+		filter := BookFilter{TitlePattern: regexp.MustCompile(`^Dune\b`)}
+		assert.True(t, filter.MatchesMetadata(dune))
+		assert.False(t, filter.MatchesMetadata(emma))
+	})
+
+	t.Run("all criteria must match", func(t *testing.T) {
+		filter := BookFilter{
+			Authors:      []string{"Frank Herbert"},
+			TitlePattern: regexp.MustCompile(`Emma`),
+		}
+		assert.False(t, filter.MatchesMetadata(dune))
+	})
+}
+
+func TestOrganizerFilterLimitsRun(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	books := map[string]string{"Dune": "Frank Herbert", "Emma": "Jane Austen", "Persuasion": "Jane Austen"}
+	for title, author := range books {
+		createBookDir(t, baseDir, title, title, author)
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:   baseDir,
+		OutputDir: outputDir,
+		Layout:    "author-title",
+		Filter: BookFilter{
+			Authors:      []string{"jane austen"},
+			TitlePattern: regexp.MustCompile(`^P`),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	assert.FileExists(t, filepath.Join(outputDir, "Jane Austen", "Persuasion", "audio.mp3"))
+	assert.FileExists(t, filepath.Join(baseDir, "Emma", "audio.mp3"))
+	assert.FileExists(t, filepath.Join(baseDir, "Dune", "audio.mp3"))
+	assert.NoFileExists(t, org.scanIndexPath(baseDir), "filtered runs must not save the scan index")
+}
+
+func TestScannerFilterCountsSkippedBooks(t *testing.T) {
+	baseDir := t.TempDir()
+	for _, title := range []string{"One", "Two"} {
+		createBookDir(t, baseDir, title, title, "A")
+	}
+
+	scanner := NewScanner(ScanOptions{Filter: BookFilter{TitlePattern: regexp.MustCompile("One")}})
+	result, err := scanner.Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, "One", result.Books[0].Metadata.Title)
+	assert.Equal(t, 1, scanner.Progress().BooksFiltered)
+
+	scanner = NewScanner(ScanOptions{Filter: BookFilter{Paths: []string{filepath.Join(baseDir, "Two")}}})
+	result, err = scanner.Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, "Two", result.Books[0].Metadata.Title)
+}
@@ -0,0 +1,62 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookFolderInFlatMode(t *testing.T) {
+	base := t.TempDir()
+	file := filepath.Join(base, "Dune.m4b")
+	dune := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}, Series: []string{"Dune #1"}}
+	messiah := Metadata{Title: "Dune Messiah", Authors: []string{"Frank Herbert"}, Series: []string{"Dune #2"}}
+
+	tests := []struct {
+		name       string
+		layout     string
+		template   string
+		bookFolder bool
+		metadata   Metadata
+		want       string
+	}{
+		{"series named like the title", "author-series-title", "", false, dune, "Frank Herbert/Dune"},
+		{"series named like the title with book folder", "author-series-title", "", true, dune, "Frank Herbert/Dune/Dune"},
+		{"author-only", "author-only", "", true, dune, "Frank Herbert/Dune"},
+		{"author-series", "author-series", "", true, messiah, "Frank Herbert/Dune/Dune Messiah"},
+		{"numbered titles follow the layout", "author-series-title-number", "", true, messiah, "Frank Herbert/Dune/#2 - Dune Messiah"},
+		{"template without title", "", "{author}/{series}", true, messiah, "Frank Herbert/Dune/Dune Messiah"},
+		{"template with title", "", "{author}/{title}{ (year)}", true, messiah, "Frank Herbert/Dune Messiah"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org, err := NewOrganizer(&OrganizerConfig{
+				BaseDir:        base,
+				OutputDir:      "/out",
+				Flat:           true,
+				Layout:         tt.layout,
+				LayoutTemplate: tt.template,
+				BookFolder:     tt.bookFolder,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join("/out", filepath.FromSlash(tt.want)), org.calculateSingleFileTargetDir(file, tt.metadata))
+		})
+	}
+}
+
+func TestBookFolderForBookDirectories(t *testing.T) {
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: t.TempDir(), OutputDir: "/out", Layout: "author-only", BookFolder: true})
+	require.NoError(t, err)
+	target, err := org.layoutCalculator.CalculateTargetPathE(Metadata{Title: "Emma", Authors: []string{"Jane Austen"}})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/out", "Jane Austen", "Emma"), target)
+}
+
+func TestBookFolderConflictsWithSingleFileLayout(t *testing.T) {
+	_, err := NewOrganizer(&OrganizerConfig{BaseDir: t.TempDir(), BookFolder: true, SingleFileLayout: SingleFileFile})
+	assert.ErrorContains(t, err, "can't be combined")
+}
@@ -0,0 +1,183 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BookOrder decides the order books are organized in. Any order but OrderScan
+// holds every book until the scan is done, so moves only start once it is.
+type BookOrder string
+
+const (
+	// OrderScan organizes each book as soon as the scan finds it (the default)
+	OrderScan BookOrder = "scan"
+	// OrderSmallestFirst organizes the books with the fewest bytes first, so an
+	// interrupted migration has moved as many books as possible
+	OrderSmallestFirst BookOrder = "smallest-first"
+	// OrderLargestFirst organizes the books with the most bytes first
+	OrderLargestFirst BookOrder = "largest-first"
+	// OrderAlphabetical organizes books by their first author, then title
+	OrderAlphabetical BookOrder = "alphabetical"
+	// OrderNewestFirst organizes the books whose directory changed last first, so a
+	// backlog fed in with Limit starts with the latest downloads
+	OrderNewestFirst BookOrder = "newest-first"
+	// OrderOldestFirst organizes the books whose directory changed first first
+	OrderOldestFirst BookOrder = "oldest-first"
+)
+
+// ParseBookOrder parses an --order value; "" selects OrderScan
+func ParseBookOrder(value string) (BookOrder, error) {
+	switch order := BookOrder(strings.ToLower(strings.TrimSpace(value))); order {
+	case "":
+		return OrderScan, nil
+	case OrderScan, OrderSmallestFirst, OrderLargestFirst, OrderAlphabetical, OrderNewestFirst, OrderOldestFirst:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid order %q (use scan, smallest-first, largest-first, alphabetical, newest-first, or oldest-first)", value)
+	}
+}
+
+// holds reports whether books wait for the end of the scan
+func (order BookOrder) holds() bool {
+	return order != OrderScan && order != ""
+}
+
+// heldBook is a book waiting for the end of the scan, with the bytes its files hold
+// and the time its directory was last modified
+type heldBook struct {
+	Book
+	size    int64
+	modTime time.Time
+}
+
+// holdOrderedBook keeps a book until the scan is done when an order other than the
+// scan's is configured, or the plan is checked before anything moves. It reports
+// whether the book was held.
+func (o *Organizer) holdOrderedBook(book Book) bool {
+	if !o.config.Order.holds() && !o.confirmsPlan() {
+		return false
+	}
+	held := heldBook{Book: book, size: bookSize(book)}
+	if o.config.Order == OrderNewestFirst || o.config.Order == OrderOldestFirst {
+		held.modTime = o.bookModTime(book)
+	}
+	o.heldBooks = append(o.heldBooks, held)
+	return true
+}
+
+// organizeHeldBooks organizes the books held during the scan in the configured
+// order, or the scan's. Like the scan, it stops at the first error that isn't skipped.
+func (o *Organizer) organizeHeldBooks() error {
+	books := o.heldBooks
+	o.heldBooks = nil
+	if len(books) == 0 {
+		return nil
+	}
+
+	switch o.config.Order {
+	case OrderSmallestFirst:
+		sort.SliceStable(books, func(i, j int) bool { return books[i].size < books[j].size })
+	case OrderLargestFirst:
+		sort.SliceStable(books, func(i, j int) bool { return books[i].size > books[j].size })
+	case OrderAlphabetical:
+		collation := o.layoutCalculator.collation
+		sort.SliceStable(books, func(i, j int) bool {
+			a, b := books[i].Metadata, books[j].Metadata
+			if byAuthor := collation.Compare(firstAuthor(a), firstAuthor(b)); byAuthor != 0 {
+				return byAuthor < 0
+			}
+			return collation.Compare(a.Title, b.Title) < 0
+		})
+	case OrderNewestFirst:
+		sort.SliceStable(books, func(i, j int) bool { return books[i].modTime.After(books[j].modTime) })
+	case OrderOldestFirst:
+		sort.SliceStable(books, func(i, j int) bool { return books[i].modTime.Before(books[j].modTime) })
+	}
+
+	if o.config.Order.holds() {
+		var total int64
+		for _, book := range books {
+			total += book.size
+		}
+		PrintBlue("🔢 Organizing %d books (%s) in %s order", len(books), formatBytes(uint64(total)), o.config.Order)
+	}
+	for _, book := range books {
+		if o.config.Verbose && o.config.Order.holds() {
+			PrintBlue("   %s (%s)", book.Path, formatBytes(uint64(book.size)))
+		}
+		if err := o.organizeBook(book.Book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bookSize returns the bytes held by the files of a book, read from the snapshot
+// the scanner took when it has one
+func bookSize(book Book) int64 {
+	snapshot := book.snapshot
+	if snapshot == nil {
+		var err error
+		if snapshot, err = takeSourceSnapshot(book.Path); err != nil {
+			return 0
+		}
+	}
+	var size int64
+	for _, file := range snapshot {
+		size += file.size
+	}
+	return size
+}
+
+// bookModTime returns when the directory of a book was last modified. In flat mode
+// that is the directory holding the file, so an album's files stay together.
+func (o *Organizer) bookModTime(book Book) time.Time {
+	dir := book.Path
+	if o.config.Flat {
+		dir = filepath.Dir(dir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// leftByLimit reports whether a book is left for a later run because Limit books
+// were already organized. In flat mode the files of one album count as one book, and
+// an album that was started is always finished.
+func (o *Organizer) leftByLimit(book Book) bool {
+	if o.config.Limit <= 0 {
+		return false
+	}
+	key := book.Path
+	if o.config.Flat && book.Metadata.Album != "" {
+		key = filepath.Dir(book.Path) + "\x00" + book.Metadata.Album
+	}
+	if o.limitBooks[key] {
+		return false
+	}
+	if len(o.limitBooks) < o.config.Limit {
+		if o.limitBooks == nil {
+			o.limitBooks = make(map[string]bool)
+		}
+		o.limitBooks[key] = true
+		return false
+	}
+
+	// Read the book again on the next incremental scan
+	o.scanIndex.Invalidate(book.Path)
+	if !o.limitLeft[key] {
+		if o.limitLeft == nil {
+			o.limitLeft = make(map[string]bool)
+		}
+		o.limitLeft[key] = true
+		o.summary.LeftByLimit = append(o.summary.LeftByLimit, book.Path)
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBookOrder(t *testing.T) {
+	for value, want := range map[string]BookOrder{
+		"":                 OrderScan,
+		"scan":             OrderScan,
+		" Smallest-First ": OrderSmallestFirst,
+		"largest-first":    OrderLargestFirst,
+		"alphabetical":     OrderAlphabetical,
+		"newest-first":     OrderNewestFirst,
+		"oldest-first":     OrderOldestFirst,
+	} {
+		order, err := ParseBookOrder(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, order, value)
+	}
+	_, err := ParseBookOrder("random")
+	assert.ErrorContains(t, err, "invalid order")
+}
+
+func TestOrganizeInOrder(t *testing.T) {
+	base := t.TempDir()
+	books := []struct {
+		dir, title, author string
+		size               int
+	}{
+		{"big", "Middlemarch", "George Eliot", 3000},
+		{"small", "Zazie", "Raymond Queneau", 1000},
+		{"medium", "Beloved", "Toni Morrison", 2000},
+	}
+	modified := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, book := range books {
+		dir := createBookDir(t, base, book.dir, book.title, book.author)
+		audio := bytes.Repeat([]byte("x"), book.size)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "audio.mp3"), audio, 0o644))
+		changed := modified.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(dir, changed, changed))
+	}
+
+	movedBooks := func(order BookOrder) []string {
+		org, err := NewOrganizer(&OrganizerConfig{
+			BaseDir:      base,
+			OutputDir:    t.TempDir(),
+			Layout:       "author-title",
+			FieldMapping: DefaultFieldMapping(),
+			DryRun:       true,
+			Order:        order,
+		})
+		require.NoError(t, err)
+		output := CaptureOutput(func() {
+			require.NoError(t, org.Execute())
+		})
+		assert.Contains(t, output, "in "+string(order)+" order")
+
+		var sources []string
+		for _, move := range org.GetSummary().Moves {
+			sources = append(sources, filepath.Base(move.From))
+		}
+
+		var plan bytes.Buffer
+		require.NoError(t, org.WriteMovePlan(&plan))
+		assert.True(t, strings.HasPrefix(plan.String(), sources[0]+"/"), "the plan lists the first book first")
+		return sources
+	}
+
+	assert.Equal(t, []string{"small", "medium", "big"}, movedBooks(OrderSmallestFirst))
+	assert.Equal(t, []string{"big", "medium", "small"}, movedBooks(OrderLargestFirst))
+	assert.Equal(t, []string{"big", "small", "medium"}, movedBooks(OrderAlphabetical))
+	assert.Equal(t, []string{"medium", "small", "big"}, movedBooks(OrderNewestFirst))
+	assert.Equal(t, []string{"big", "small", "medium"}, movedBooks(OrderOldestFirst))
+}
+
+func TestOrganizeLimit(t *testing.T) {
+	base := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		dir := createBookDir(t, base, name, "Book "+name, "Author")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "audio.mp3"), []byte("audio"), 0o644))
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      base,
+		OutputDir:    t.TempDir(),
+		Layout:       "author-title",
+		FieldMapping: DefaultFieldMapping(),
+		DryRun:       true,
+		Order:        OrderAlphabetical,
+		Limit:        2,
+	})
+	require.NoError(t, err)
+	output := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	summary := org.GetSummary()
+	require.Len(t, summary.Moves, 2)
+	assert.Equal(t, "a", filepath.Base(summary.Moves[0].From))
+	assert.Equal(t, "b", filepath.Base(summary.Moves[1].From))
+	assert.Equal(t, []string{filepath.Join(base, "c")}, summary.LeftByLimit)
+	assert.Contains(t, output, "Stopped after --limit=2 books; left for a later run: 1")
+}
+
+func TestOrganizeLimitFinishesFlatAlbums(t *testing.T) {
+	org := &Organizer{config: OrganizerConfig{Flat: true, Limit: 1}}
+	book := func(path, album string) Book {
+		return Book{Path: filepath.Join("/in", path), Metadata: Metadata{Album: album}}
+	}
+	assert.False(t, org.leftByLimit(book("Album/01.mp3", "Dune")))
+	assert.False(t, org.leftByLimit(book("Album/02.mp3", "Dune")), "the started album is finished")
+	assert.True(t, org.leftByLimit(book("Loose/loose.mp3", "")))
+	assert.Equal(t, []string{filepath.Join("/in", "Loose/loose.mp3")}, org.summary.LeftByLimit)
+}
@@ -0,0 +1,372 @@
+package organizer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// StagingDirPrefix names the temporary folder a book is staged into before it is
+// renamed into place
+const StagingDirPrefix = ".abook-staging-"
+
+// replacedDirName is the folder inside the staging directory holding target files a
+// commit replaces, until the whole book is in place
+const replacedDirName = ".replaced"
+
+// bookTransaction moves all files of one book as a unit. Files are first staged into
+// a temporary directory next to the target, verified, and only then renamed into
+// place. If any step fails, staged files go back to the source so the book is never
+// left split across two locations. Target-side operations go through the organizer's
+// TargetFS so the same steps work for remote outputs.
+type bookTransaction struct {
+	org         *Organizer
+	targetDir   string
+	stagingDir  string
+	files       []stagedFile
+	committed   int
+	keepSources bool // Link or copy instead of moving, leaving the sources in place
+}
+
+// stagedFile tracks one file through staging and commit
+type stagedFile struct {
+	source   string
+	staged   string
+	target   string
+	size     int64
+	copied   bool   // Staged by copying across filesystems; the source is still in place
+	kept     bool   // Staged by linking or copying; the source stays after commit
+	replaced string // Where the file previously at target was set aside, if there was one
+}
+
+// beginBookTransaction creates the staging directory for a move into targetDir
+func (o *Organizer) beginBookTransaction(targetDir string) (*bookTransaction, error) {
+	parent := filepath.Dir(targetDir)
+	if err := o.fileOps.CreateDirIfNotExists(parent); err != nil {
+		return nil, fmt.Errorf("error creating target directory: %w", err)
+	}
+
+	stagingDir, err := o.target.MkdirTemp(parent, StagingDirPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating staging directory: %w", err)
+	}
+	o.debugLog("Staging book for %s in %s", targetDir, stagingDir)
+
+	return &bookTransaction{org: o, targetDir: targetDir, stagingDir: stagingDir}, nil
+}
+
+// stage moves source into the staging directory under the final target name. On the
+// same filesystem this is a rename; otherwise, or for a remote target, the file is
+// copied and the source kept until commit. With keepSources the file is hardlinked,
+// or copied across filesystems, and the source is never removed.
+func (tx *bookTransaction) stage(source, targetName string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("error reading source file: %w", err)
+	}
+
+	file := stagedFile{
+		source: source,
+		staged: filepath.Join(tx.stagingDir, targetName),
+		target: filepath.Join(tx.targetDir, targetName),
+		size:   info.Size(),
+	}
+
+	if tx.org.hasRemoteTarget() {
+		upload := func() error { return tx.org.uploadFile(source, file.staged) }
+		if err := tx.org.retry(source, upload); err != nil {
+			tx.org.target.Remove(file.staged)
+			return fmt.Errorf("error staging %s: %w", source, err)
+		}
+		file.copied, file.kept = true, tx.keepSources
+	} else if tx.keepSources {
+		if err := os.Link(source, file.staged); err != nil {
+			tx.org.debugLog("Hardlink into staging failed, copying instead: %v", err)
+			if err := tx.org.retry(source, tx.copyFunc(source, file.staged)); err != nil {
+				os.Remove(file.staged)
+				return fmt.Errorf("error staging %s: %w", source, err)
+			}
+		}
+		file.copied, file.kept = true, true
+	} else if err := tx.org.retry(source, func() error { return os.Rename(source, file.staged) }); err != nil {
+		tx.org.debugLog("Rename into staging failed, copying instead: %v", err)
+		if err := tx.org.retry(source, tx.copyFunc(source, file.staged)); err != nil {
+			os.Remove(file.staged)
+			return fmt.Errorf("error staging %s: %w", source, err)
+		}
+		file.copied = true
+	}
+
+	tx.files = append(tx.files, file)
+	return nil
+}
+
+// copyFunc returns a copy of source to staged for retry
+func (tx *bookTransaction) copyFunc(source, staged string) func() error {
+	return func() error { return copyFileContents(source, staged) }
+}
+
+// verify checks that every staged file is complete before anything is committed
+func (tx *bookTransaction) verify() error {
+	for _, file := range tx.files {
+		info, err := tx.org.target.Stat(file.staged)
+		if err != nil {
+			return fmt.Errorf("staged file missing for %s: %w", file.source, err)
+		}
+		if info.Size() != file.size {
+			return fmt.Errorf(
+				"staged file for %s is incomplete: %d of %d bytes",
+				file.source,
+				info.Size(),
+				file.size,
+			)
+		}
+	}
+	return nil
+}
+
+// commit renames the staged files into the target directory and then releases the
+// sources of copied files
+func (tx *bookTransaction) commit() error {
+	if err := tx.org.fileOps.CreateDirIfNotExists(tx.targetDir); err != nil {
+		return fmt.Errorf("error creating target directory: %w", err)
+	}
+	if err := tx.org.syncTargetDirectory(tx.stagingDir); err != nil {
+		return err
+	}
+
+	for i := range tx.files {
+		file := &tx.files[i]
+		if err := tx.setAsideExisting(file); err != nil {
+			return err
+		}
+		rename := func() error { return tx.org.target.Rename(file.staged, file.target) }
+		if err := tx.org.retry(file.target, rename); err != nil {
+			return fmt.Errorf("error moving %s into place: %w", file.target, err)
+		}
+		tx.committed++
+	}
+
+	if err := tx.org.syncTargetDirectory(tx.targetDir); err != nil {
+		tx.org.debugLog("Could not sync %s: %v", tx.targetDir, err)
+	}
+
+	// Replaced files are only given up once the whole book is in place
+	replacedAny := false
+	for _, file := range tx.files {
+		if file.replaced == "" {
+			continue
+		}
+		replacedAny = true
+		if err := tx.discardReplaced(file); err != nil {
+			tx.org.recordError("❌ Error removing replaced file %s: %v", file.replaced, err)
+		}
+	}
+
+	// The book is complete at the target; copied sources can go now
+	for _, file := range tx.files {
+		if !file.copied || file.kept {
+			continue
+		}
+		if err := tx.org.discard(file.source); err != nil {
+			tx.org.recordError("❌ Error removing source file %s: %v", file.source, err)
+		}
+	}
+
+	if replacedAny {
+		tx.org.target.Remove(filepath.Join(tx.stagingDir, replacedDirName))
+	}
+	if err := tx.org.target.Remove(tx.stagingDir); err != nil {
+		PrintYellow("⚠️  Warning: couldn't remove staging directory %s: %v", tx.stagingDir, err)
+	}
+	return nil
+}
+
+// setAsideExisting moves a file already at the target into the staging directory so
+// a rollback can put it back instead of leaving it overwritten
+func (tx *bookTransaction) setAsideExisting(file *stagedFile) error {
+	info, err := tx.org.target.Stat(file.target)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+	replaced := filepath.Join(tx.stagingDir, replacedDirName, filepath.Base(file.staged))
+	if err := tx.org.target.MkdirAll(filepath.Dir(replaced)); err != nil {
+		return fmt.Errorf("error setting aside existing %s: %w", file.target, err)
+	}
+	if err := tx.org.target.Rename(file.target, replaced); err != nil {
+		return fmt.Errorf("error setting aside existing %s: %w", file.target, err)
+	}
+	file.replaced = replaced
+	return nil
+}
+
+// discardReplaced gives up a file the commit replaced, through the trash when one is
+// configured so it can still be recovered from there
+func (tx *bookTransaction) discardReplaced(file stagedFile) error {
+	if tx.org.hasRemoteTarget() {
+		return tx.org.target.Remove(file.replaced)
+	}
+	return tx.org.discardAs(file.replaced, file.target)
+}
+
+// rollback undoes a partially staged or committed book, restoring the source
+// directory and removing the staging directory
+func (tx *bookTransaction) rollback() {
+	for i := tx.committed - 1; i >= 0; i-- {
+		file := tx.files[i]
+		if err := tx.org.target.Rename(file.target, file.staged); err != nil {
+			tx.org.recordError("❌ Error rolling back %s: %v", file.target, err)
+		}
+	}
+	tx.committed = 0
+
+	restored := true
+	// Put back what the commit replaced, including a file set aside just before its
+	// rename into place failed
+	for i := range tx.files {
+		file := &tx.files[i]
+		if file.replaced == "" {
+			continue
+		}
+		if err := tx.org.target.Rename(file.replaced, file.target); err != nil {
+			tx.org.recordError("❌ Error restoring replaced file %s: %v", file.target, err)
+			restored = false
+			continue
+		}
+		file.replaced = ""
+	}
+
+	for _, file := range tx.files {
+		if file.copied {
+			continue
+		}
+		if err := os.Rename(file.staged, file.source); err != nil {
+			tx.org.recordError("❌ Error restoring %s: %v", file.source, err)
+			restored = false
+		}
+	}
+
+	// Never delete the only remaining copy of a file
+	if !restored {
+		PrintYellow("⚠️  Warning: leaving staging directory in place: %s", tx.stagingDir)
+		return
+	}
+	if err := tx.org.target.RemoveAll(tx.stagingDir); err != nil {
+		PrintYellow("⚠️  Warning: couldn't remove staging directory %s: %v", tx.stagingDir, err)
+	}
+}
+
+// moveBookFiles moves every file in moves (From is the source path, To the target
+// file name) into targetDir as a single transaction
+func (o *Organizer) moveBookFiles(targetDir string, moves []FilePair) error {
+	if err := o.checkFreeSpace(targetDir, moves); err != nil {
+		return err
+	}
+
+	tx, err := o.beginBookTransaction(targetDir)
+	if err != nil {
+		return err
+	}
+	tx.keepSources = o.keepsSources(moves)
+
+	for _, move := range moves {
+		if filepath.Clean(move.From) == filepath.Join(targetDir, move.To) {
+			continue // Already in place
+		}
+		if tx.keepSources && !o.hasRemoteTarget() && isSameLocalFile(move.From, filepath.Join(targetDir, move.To)) {
+			continue // Linked by an earlier run
+		}
+		if err := tx.stage(move.From, move.To); err != nil {
+			tx.rollback()
+			return err
+		}
+	}
+
+	if err := tx.verify(); err != nil {
+		tx.rollback()
+		return err
+	}
+	if err := tx.commit(); err != nil {
+		tx.rollback()
+		return err
+	}
+	if tx.keepSources {
+		o.recordSeeding(targetDir)
+	}
+	return nil
+}
+
+// isSameLocalFile reports whether a and b are links to the same file on disk
+func isSameLocalFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	return err == nil && os.SameFile(aInfo, bInfo)
+}
+
+// onSameFileSystem is sameFileSystem, replaced in tests
+var onSameFileSystem = sameFileSystem
+
+// checkFreeSpace fails before anything is staged when the target doesn't have room for
+// the book. Files renamed or hardlinked within one local file system take no space
+// and aren't counted. Targets that can't report free space are not checked.
+func (o *Organizer) checkFreeSpace(targetDir string, moves []FilePair) error {
+	targetBase := o.layoutCalculator.getTargetBase()
+	localBase := ""
+	if !o.hasRemoteTarget() {
+		localBase = nearestExistingDir(targetBase)
+	}
+	var needed uint64
+	for _, move := range moves {
+		if localBase != "" && onSameFileSystem(move.From, localBase) {
+			continue
+		}
+		if info, err := os.Stat(move.From); err == nil {
+			needed += uint64(info.Size())
+		}
+	}
+	if needed == 0 {
+		return nil
+	}
+
+	available, err := o.target.FreeSpace(targetBase)
+	if err != nil {
+		o.debugLog("Skipping free space check for %s: %v", targetDir, err)
+		return nil
+	}
+	if needed > available {
+		return fmt.Errorf(
+			"not enough free space for %s: need %d bytes, %d available",
+			targetDir,
+			needed,
+			available,
+		)
+	}
+	return nil
+}
+
+// uploadFile copies a local file to the target file system
+func (o *Organizer) uploadFile(source, target string) error {
+	if uploader, ok := o.target.(targetUploader); ok {
+		return uploader.Upload(source, target)
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := o.target.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
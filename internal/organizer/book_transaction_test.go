@@ -0,0 +1,136 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveBookFilesCommitsAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "in", "Book")
+	target := filepath.Join(dir, "out", "Author", "Book")
+	moves := writeBook(t, source, nil, "01.mp3", "02.mp3", "03.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: filepath.Join(dir, "in")})
+	require.NoError(t, err)
+	require.NoError(t, org.moveBookFiles(target, moves))
+
+	for _, move := range moves {
+		assert.NoFileExists(t, move.From)
+		assert.FileExists(t, filepath.Join(target, move.To))
+	}
+
+	// No staging directory is left behind
+	entries, err := os.ReadDir(filepath.Dir(target))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Book", entries[0].Name())
+}
+
+func TestMoveBookFilesRollsBackOnPartialFailure(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "in", "Book")
+	target := filepath.Join(dir, "out", "Book")
+	moves := writeBook(t, source, nil, "01.mp3", "02.mp3", "03.mp3")
+
+	// A non-empty directory in the way makes the second file fail to land
+	blocker := filepath.Join(target, "02.mp3")
+	require.NoError(t, os.MkdirAll(blocker, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(blocker, "keep"), []byte("x"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: filepath.Join(dir, "in")})
+	require.NoError(t, err)
+	require.Error(t, org.moveBookFiles(target, moves))
+
+	// The source is untouched and nothing was left at the target
+	for _, move := range moves {
+		data, err := os.ReadFile(move.From)
+		require.NoError(t, err)
+		assert.Equal(t, move.To, string(data))
+	}
+	assert.NoFileExists(t, filepath.Join(target, "01.mp3"))
+	assert.NoFileExists(t, filepath.Join(target, "03.mp3"))
+
+	matches, err := filepath.Glob(filepath.Join(dir, "out", StagingDirPrefix+"*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestMoveBookFilesRestoresReplacedTargetOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "in", "Book")
+	target := filepath.Join(dir, "out", "Book")
+	moves := writeBook(t, source, nil, "01.mp3", "02.mp3")
+
+	// The target already holds 01.mp3, and a directory blocks 02.mp3
+	require.NoError(t, os.MkdirAll(filepath.Join(target, "02.mp3", "x"), 0o755))
+	existing := filepath.Join(target, "01.mp3")
+	require.NoError(t, os.WriteFile(existing, []byte("PRECIOUS"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: filepath.Join(dir, "in")})
+	require.NoError(t, err)
+	require.Error(t, org.moveBookFiles(target, moves))
+
+	data, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "PRECIOUS", string(data))
+	for _, move := range moves {
+		assert.FileExists(t, move.From)
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "out", StagingDirPrefix+"*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestMoveBookFilesTrashesReplacedTargetAfterCommit(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "in", "Book")
+	target := filepath.Join(dir, "out", "Book")
+	moves := writeBook(t, source, nil, "01.mp3")
+	existing := filepath.Join(target, "01.mp3")
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: filepath.Join(dir, "in"), TrashDir: filepath.Join(dir, "trash")})
+	require.NoError(t, err)
+	require.NoError(t, org.moveBookFiles(target, moves))
+
+	data, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "01.mp3", string(data))
+	require.Len(t, org.summary.Trashed, 1)
+	assert.True(t, strings.HasSuffix(org.summary.Trashed[0], existing), "trashed under its target path: %s", org.summary.Trashed[0])
+	trashed, err := os.ReadFile(org.summary.Trashed[0])
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(trashed))
+}
+
+func TestOrganizeAudiobookLeavesSourceOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in")
+	output := filepath.Join(dir, "out")
+	source := filepath.Join(input, "Book")
+	writeBook(t, source, nil, "01.mp3", "02.mp3")
+
+	// Block the first file's target so the whole book fails to move
+	require.NoError(t, os.MkdirAll(filepath.Join(output, "Author", "Title", "01.mp3", "x"), 0o755))
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: input, OutputDir: output, Layout: "author-title"})
+	require.NoError(t, err)
+
+	metadata := Metadata{Title: "Title", Authors: []string{"Author"}}
+	err = org.OrganizeAudiobook(source, NewStaticMetadataProvider(metadata))
+	require.Error(t, err)
+
+	assert.FileExists(t, filepath.Join(source, "01.mp3"))
+	assert.FileExists(t, filepath.Join(source, "02.mp3"))
+	assert.Empty(t, org.summary.Moves)
+	assert.Empty(t, org.logEntries)
+}
@@ -0,0 +1,125 @@
+package organizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collation sorts and files names by the rules of one locale, so "Ångström" sorts
+// with the A's instead of after "Zelazny" as it does in byte order. A nil Collation
+// uses the root collation order, which suits most languages.
+type Collation struct {
+	mu          sync.Mutex // Collators keep buffers and are not safe for concurrent use
+	collator    *collate.Collator
+	letterMatch *collate.Collator
+}
+
+// rootCollation is the root collation order used by a nil Collation
+var rootCollation = newCollation(language.Und)
+
+// NewCollation returns the collation of locale, a BCP 47 tag ("sv", "de-AT") or
+// POSIX name ("sv_SE.UTF-8"). An empty locale uses the root collation order.
+func NewCollation(locale string) (*Collation, error) {
+	tag := language.Und
+	if locale = strings.TrimSpace(locale); locale != "" {
+		// Drop the encoding and modifier of POSIX names
+		if i := strings.IndexAny(locale, ".@"); i >= 0 {
+			locale = locale[:i]
+		}
+		parsed, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %w", locale, err)
+		}
+		tag = parsed
+	}
+	return newCollation(tag), nil
+}
+
+// collationFor returns the collation of locale, or the root collation order when
+// locale is invalid; configs report an invalid locale when they are validated
+func collationFor(locale string) *Collation {
+	collation, err := NewCollation(locale)
+	if err != nil {
+		return nil
+	}
+	return collation
+}
+
+func newCollation(tag language.Tag) *Collation {
+	return &Collation{
+		collator:    collate.New(tag, collate.IgnoreCase),
+		letterMatch: collate.New(tag, collate.Loose),
+	}
+}
+
+// Compare compares a and b in the collation order, returning -1, 0, or 1. Names
+// equal but for case are ordered by byte order so sorts are stable.
+func (c *Collation) Compare(a, b string) int {
+	if c == nil {
+		c = rootCollation
+	}
+	c.mu.Lock()
+	result := c.collator.CompareString(a, b)
+	c.mu.Unlock()
+	if result != 0 {
+		return result
+	}
+	return strings.Compare(a, b)
+}
+
+// Initial returns the letter name is filed under: the first letter without accents
+// when the locale treats it as a variant of A-Z ("Ångström" under A in English), or
+// the letter itself when it is one of the locale's own letters (under Å in Swedish).
+// Names without a leading letter are filed under "#".
+func (c *Collation) Initial(name string) string {
+	initial := NameInitial(name)
+	if initial == "#" || (len(initial) == 1 && initial[0] >= 'A' && initial[0] <= 'Z') {
+		return initial
+	}
+
+	if c == nil {
+		c = rootCollation
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for letter := 'A'; letter <= 'Z'; letter++ {
+		if c.letterMatch.CompareString(initial, string(letter)) == 0 {
+			return string(letter)
+		}
+	}
+	return initial
+}
+
+// CompareNames compares a and b in the root collation order (see Collation.Compare)
+func CompareNames(a, b string) int {
+	return rootCollation.Compare(a, b)
+}
+
+// SortInitial returns the letter name is filed under in the root collation order
+// (see Collation.Initial)
+func SortInitial(name string) string {
+	return rootCollation.Initial(name)
+}
+
+// sortByAuthorAndTitle orders books by their first author, then title, in the
+// collation order
+func sortByAuthorAndTitle(books []Metadata, collation *Collation) {
+	sort.SliceStable(books, func(i, j int) bool {
+		if byAuthor := collation.Compare(firstAuthor(books[i]), firstAuthor(books[j])); byAuthor != 0 {
+			return byAuthor < 0
+		}
+		return collation.Compare(books[i].Title, books[j].Title) < 0
+	})
+}
+
+func firstAuthor(metadata Metadata) string {
+	if len(metadata.Authors) == 0 {
+		return ""
+	}
+	return metadata.Authors[0]
+}
@@ -0,0 +1,55 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollationSortsAccentsWithTheirLetter(t *testing.T) {
+	names := []string{"Zelazny", "Ångström", "adams", "Brust", "Östlund"}
+	sortNames := func(collation *Collation) []string {
+		sorted := append([]string(nil), names...)
+		sort.Slice(sorted, func(i, j int) bool { return collation.Compare(sorted[i], sorted[j]) < 0 })
+		return sorted
+	}
+
+	assert.Equal(t, []string{"adams", "Ångström", "Brust", "Östlund", "Zelazny"}, sortNames(nil))
+
+	swedish, err := NewCollation("sv_SE.UTF-8")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"adams", "Brust", "Zelazny", "Ångström", "Östlund"}, sortNames(swedish))
+}
+
+func TestCollationInitial(t *testing.T) {
+	tests := []struct {
+		locale string
+		name   string
+		want   string
+	}{
+		{"", "Ångström, Anders", "A"},
+		{"de", "Öberg, Lisa", "O"},
+		{"sv", "Ångström, Anders", "Å"},
+		{"sv", "Émile, Zola", "E"},
+		{"", "2001 Authors", "#"},
+	}
+
+	for _, tt := range tests {
+		collation, err := NewCollation(tt.locale)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, collation.Initial(tt.name), "%s in locale %q", tt.name, tt.locale)
+	}
+	assert.Equal(t, "A", SortInitial("Ångström, Anders"))
+}
+
+func TestNewCollationRejectsInvalidTags(t *testing.T) {
+	_, err := NewCollation("not a locale")
+	assert.Error(t, err)
+
+	config := OrganizerConfig{BaseDir: t.TempDir(), Locale: "not a locale"}
+	assert.Error(t, config.Validate())
+}
@@ -0,0 +1,45 @@
+package organizer
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"github.com/muesli/termenv"
+)
+
+// ColorMode controls whether printed output contains ANSI color codes
+type ColorMode int
+
+const (
+	// ColorAuto colors output only when stdout is a terminal and NO_COLOR is unset
+	ColorAuto ColorMode = iota
+	// ColorNever prints plain text, for logs captured by Docker or systemd
+	ColorNever
+	// ColorAlways colors output even when it is piped, for CI logs that render ANSI
+	ColorAlways
+)
+
+// SetColorMode applies mode to the print helpers, the metadata formatter, and any
+// other lipgloss or fatih/color output
+func SetColorMode(mode ColorMode) {
+	profile := colorProfile(mode, termenv.NewOutput(os.Stdout))
+	lipgloss.SetColorProfile(profile)
+	color.NoColor = profile == termenv.Ascii
+}
+
+// colorProfile returns the color profile for mode on out. Auto follows out's
+// terminal and the NO_COLOR and CLICOLOR variables; forced color on a pipe or dumb
+// terminal uses 256 colors.
+func colorProfile(mode ColorMode, out *termenv.Output) termenv.Profile {
+	switch mode {
+	case ColorNever:
+		return termenv.Ascii
+	case ColorAlways:
+		if profile := out.ColorProfile(); profile != termenv.Ascii {
+			return profile
+		}
+		return termenv.ANSI256
+	}
+	return out.EnvColorProfile()
+}
@@ -0,0 +1,59 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEnviron is a fixed environment for termenv outputs
+type testEnviron map[string]string
+
+func (e testEnviron) Environ() []string {
+	var env []string
+	for k, v := range e {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+func (e testEnviron) Getenv(key string) string {
+	return e[key]
+}
+
+func TestColorProfile(t *testing.T) {
+	terminal := testEnviron{"TERM": "xterm-256color"}
+	noColor := testEnviron{"TERM": "xterm-256color", "NO_COLOR": "1"}
+
+	tests := []struct {
+		name string
+		mode ColorMode
+		out  *termenv.Output
+		want termenv.Profile
+	}{
+		{"auto on a terminal", ColorAuto, termenv.NewOutput(&bytes.Buffer{}, termenv.WithTTY(true), termenv.WithEnvironment(terminal)), termenv.ANSI256},
+		{"auto on a pipe", ColorAuto, termenv.NewOutput(&bytes.Buffer{}, termenv.WithEnvironment(terminal)), termenv.Ascii},
+		{"auto with NO_COLOR", ColorAuto, termenv.NewOutput(&bytes.Buffer{}, termenv.WithTTY(true), termenv.WithEnvironment(noColor)), termenv.Ascii},
+		{"never on a terminal", ColorNever, termenv.NewOutput(&bytes.Buffer{}, termenv.WithTTY(true), termenv.WithEnvironment(terminal)), termenv.Ascii},
+		{"always on a pipe", ColorAlways, termenv.NewOutput(&bytes.Buffer{}, termenv.WithEnvironment(noColor)), termenv.ANSI256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, colorProfile(tt.mode, tt.out))
+		})
+	}
+}
+
+func TestSetColorModeNeverStripsANSI(t *testing.T) {
+	defer SetColorMode(ColorAuto)
+
+	SetColorMode(ColorNever)
+	out := CaptureOutput(func() { PrintGreen("done") })
+	assert.Equal(t, "done\n", out)
+	assert.Equal(t, "Gold", jsonSourceStyle.Render("Gold"))
+}
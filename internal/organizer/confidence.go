@@ -0,0 +1,118 @@
+package organizer
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// genericTitlePattern matches placeholder titles rippers and players write when a
+// file has no real title, such as "Track 1", "Chapter 03", "Untitled", or "01"
+var genericTitlePattern = regexp.MustCompile(
+	`(?i)^(?:track|chapter|part|disc|disk|cd|audio\s*track|untitled|unknown(?:\s+(?:title|album))?|title|new\s+recording|audiobook)?[\s#_.-]*\d{0,3}$`,
+)
+
+// genericAuthors are placeholder author names
+var genericAuthors = map[string]bool{
+	"unknown":         true,
+	"unknown artist":  true,
+	"unknown author":  true,
+	"unknownartist":   true,
+	"various":         true,
+	"various artists": true,
+	"artist":          true,
+	"author":          true,
+	"n/a":             true,
+	"none":            true,
+	"[unknown]":       true,
+}
+
+// Score penalties for the metadata confidence heuristics
+const (
+	missingFieldPenalty  = 0.6
+	genericFieldPenalty  = 0.6
+	filenameTitlePenalty = 0.3
+)
+
+// MetadataConfidence scores how trustworthy a book's metadata looks, from 0 (garbage)
+// to 1 (nothing suspicious), with the reasons for any deductions
+type MetadataConfidence struct {
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// LowConfidence records a book held back because its metadata scored below the
+// configured minimum and no other source was usable
+type LowConfidence struct {
+	Path    string   `json:"path"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// ScoreMetadata rates metadata read from the file at metadataPath. Missing or
+// placeholder titles and authors ("Track 1", "Unknown Artist") and titles that only
+// repeat the file name lower the score.
+func ScoreMetadata(metadata Metadata, metadataPath string) MetadataConfidence {
+	confidence := MetadataConfidence{Score: 1}
+	deduct := func(penalty float64, format string, a ...interface{}) {
+		confidence.Score -= penalty
+		confidence.Reasons = append(confidence.Reasons, fmt.Sprintf(format, a...))
+	}
+
+	title := strings.TrimSpace(metadata.Title)
+	switch {
+	case title == "":
+		deduct(missingFieldPenalty, "missing title")
+	case genericTitlePattern.MatchString(title):
+		deduct(genericFieldPenalty, "generic title %q", title)
+	case metadataPath != "" && isFilenameTitle(title, metadataPath):
+		deduct(filenameTitlePenalty, "title is the file name")
+	}
+
+	author := ""
+	if len(metadata.Authors) > 0 {
+		author = strings.TrimSpace(metadata.Authors[0])
+	}
+	switch {
+	case author == "":
+		deduct(missingFieldPenalty, "missing author")
+	case genericAuthors[strings.ToLower(author)]:
+		deduct(genericFieldPenalty, "generic author %q", author)
+	}
+
+	confidence.Score = math.Round(math.Max(confidence.Score, 0)*100) / 100
+	return confidence
+}
+
+// isFilenameTitle reports whether title is only the name of the file it came from
+func isFilenameTitle(title, path string) bool {
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	return normalizeForComparison(title, false) == normalizeForComparison(name, false)
+}
+
+// lowConfidence returns the record for a book whose metadata scores below the
+// scanner's minimum, or nil when the gate is off or the metadata passes
+func (s *Scanner) lowConfidence(path, metadataPath string, metadata Metadata) *LowConfidence {
+	if s.opts.MinConfidence <= 0 {
+		return nil
+	}
+	confidence := ScoreMetadata(metadata, metadataPath)
+	if confidence.Score >= s.opts.MinConfidence {
+		return nil
+	}
+	return &LowConfidence{Path: path, Score: confidence.Score, Reasons: confidence.Reasons}
+}
+
+// holdBack reports a book left in place because of low metadata confidence. The
+// index forgets it so a later run with better tags reads it again.
+func (s *Scanner) holdBack(handler ScanHandler, held LowConfidence) {
+	s.opts.Index.Invalidate(held.Path)
+	s.progress.LowConfidence++
+	s.reportProgress()
+	if handler.LowConfidence != nil {
+		handler.LowConfidence(held)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		path     string
+		score    float64
+		reasons  int
+	}{
+		{"good tags", Metadata{Title: "The Final Empire", Authors: []string{"Brandon Sanderson"}}, "/books/01.mp3", 1, 0},
+		{"generic title and author", Metadata{Title: "Track 1", Authors: []string{"Unknown Artist"}}, "/books/01.mp3", 0, 2},
+		{"chapter title", Metadata{Title: "Chapter 03", Authors: []string{"Brandon Sanderson"}}, "/books/03.mp3", 0.4, 1},
+		{"digits only title", Metadata{Title: "01", Authors: []string{"Brandon Sanderson"}}, "/books/01.mp3", 0.4, 1},
+		{"missing author", Metadata{Title: "The Final Empire"}, "/books/01.mp3", 0.4, 1},
+		{"missing title", Metadata{Authors: []string{"Brandon Sanderson"}}, "/books/01.mp3", 0.4, 1},
+		{"title is file name", Metadata{Title: "final_empire", Authors: []string{"Brandon Sanderson"}}, "/books/final_empire.mp3", 0.7, 1},
+		{"numbered real title", Metadata{Title: "1984", Authors: []string{"George Orwell"}}, "/books/a.mp3", 1, 0},
+		{"title starting with a generic word", Metadata{Title: "Track of the Cat", Authors: []string{"Walter Van Tilburg Clark"}}, "/books/a.mp3", 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confidence := ScoreMetadata(tt.metadata, tt.path)
+			assert.InDelta(t, tt.score, confidence.Score, 0.001)
+			assert.Len(t, confidence.Reasons, tt.reasons)
+		})
+	}
+}
+
+func TestScannerHoldsBackLowConfidenceBooks(t *testing.T) {
+	baseDir := t.TempDir()
+	file := filepath.Join(baseDir, "mystery.mp3")
+	require.NoError(t, os.WriteFile(file, []byte("not really audio"), 0o644))
+
+	opts := ScanOptions{Flat: true, FallbackToFilename: true}
+	result, err := NewScanner(opts).Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1, "the gate is off by default")
+
+	opts.MinConfidence = 0.5
+	scanner := NewScanner(opts)
+	result, err = scanner.Scan(baseDir)
+	require.NoError(t, err)
+	assert.Empty(t, result.Books)
+	require.Len(t, result.LowConfidence, 1)
+	assert.Equal(t, file, result.LowConfidence[0].Path)
+	assert.Less(t, result.LowConfidence[0].Score, 0.5)
+	assert.Contains(t, result.LowConfidence[0].Reasons, `generic author "Unknown Author"`)
+	assert.Equal(t, 1, scanner.Progress().LowConfidence)
+}
+
+func TestScannerIgnoresConfidenceForMetadataJSON(t *testing.T) {
+	baseDir := t.TempDir()
+	bookDir := createBookDir(t, baseDir, "Track 1", "Track 1", "Unknown")
+
+	result, err := NewScanner(ScanOptions{MinConfidence: 0.9}).Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1, "metadata.json is curated, so it is never gated")
+	assert.Equal(t, bookDir, result.Books[0].Path)
+	assert.Empty(t, result.LowConfidence)
+}
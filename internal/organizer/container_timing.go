@@ -0,0 +1,299 @@
+// internal/organizer/container_timing.go
+package organizer
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/dhowden/tag"
+)
+
+// Raw field names for the chapter count and playing time read from the container.
+// The {chapters} and {duration} template fields render them.
+const (
+	ChaptersField = "chapters"
+	DurationField = "duration" // Seconds, as a float64
+)
+
+// readContainerTiming reads the chapter count and playing time of an audio file.
+// The tag library reads neither, so the container is parsed here: MP4 movie headers
+// with Nero or QuickTime chapters, MP3 frame headers with ID3v2 CHAP frames, FLAC
+// stream info, ASF file properties, and WAV format chunks. Zero values mean the
+// information is not available.
+func readContainerTiming(r io.ReadSeeker, format tag.Format, rawTags map[string]interface{}) (int, time.Duration) {
+	switch format {
+	case tag.MP4:
+		return readMP4Timing(r)
+	case tag.ID3v2_2, tag.ID3v2_3, tag.ID3v2_4, tag.ID3v1:
+		chapters := 0
+		for key := range rawTags {
+			if stripFrameSuffix(key) == "CHAP" {
+				chapters++
+			}
+		}
+		if duration, ok := readWAVDuration(r); ok {
+			return chapters, duration // ID3v2 chunk in a WAV file
+		}
+		return chapters, readMP3Duration(r)
+	case tag.VORBIS:
+		return 0, readFLACDuration(r)
+	case asfFormat:
+		return 0, readASFDuration(r)
+	case riffInfoFormat:
+		duration, _ := readWAVDuration(r)
+		return 0, duration
+	}
+	return 0, 0
+}
+
+// readMP4Timing reads the movie duration from mvhd and counts chapters, preferring a
+// QuickTime chapter track (trak referenced by tref/chap) over a Nero chpl atom.
+func readMP4Timing(r io.ReadSeeker) (int, time.Duration) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, 0
+	}
+
+	var (
+		duration      time.Duration
+		neroChapters  int
+		trackID       uint32
+		chapterTracks = make(map[uint32]bool)
+		trackSamples  = make(map[uint32]int)
+	)
+	walkMP4Boxes(r, 0, end, func(name string, size int64) bool {
+		switch name {
+		case "moov", "trak", "mdia", "minf", "stbl", "udta", "tref":
+			return true
+		case "mvhd":
+			duration = readMP4MovieDuration(r)
+		case "tkhd":
+			trackID = readMP4TrackID(r)
+		case "chap":
+			ids := make([]byte, size)
+			if _, err := io.ReadFull(r, ids); err == nil {
+				for i := 0; i+4 <= len(ids); i += 4 {
+					chapterTracks[binary.BigEndian.Uint32(ids[i:])] = true
+				}
+			}
+		case "stsz":
+			// version/flags, sample size, sample count
+			buf := make([]byte, 12)
+			if _, err := io.ReadFull(r, buf); err == nil {
+				trackSamples[trackID] = int(binary.BigEndian.Uint32(buf[8:]))
+			}
+		case "chpl":
+			neroChapters = readNeroChapterCount(r)
+		}
+		return false
+	})
+
+	chapters := 0
+	for id := range chapterTracks {
+		chapters += trackSamples[id]
+	}
+	if chapters == 0 {
+		chapters = neroChapters
+	}
+	return chapters, duration
+}
+
+// walkMP4Boxes calls visit for every box between start and limit with r positioned
+// at the box body, descending into the box when visit returns true.
+func walkMP4Boxes(r io.ReadSeeker, start, limit int64, visit func(name string, size int64) bool) {
+	header := make([]byte, 8)
+	for pos := start; pos+8 <= limit; {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(r, header); err != nil {
+			return
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		name := string(header[4:8])
+		body := pos + 8
+		switch size {
+		case 0: // Box runs to the end of its parent
+			size = limit - pos
+		case 1: // 64-bit size follows the name, as used by large mdat boxes
+			large := make([]byte, 8)
+			if _, err := io.ReadFull(r, large); err != nil {
+				return
+			}
+			size = int64(binary.BigEndian.Uint64(large))
+			body += 8
+		}
+		if size < body-pos || pos+size > limit {
+			return
+		}
+
+		if visit(name, pos+size-body) {
+			walkMP4Boxes(r, body, pos+size, visit)
+		}
+		pos += size
+	}
+}
+
+func readMP4MovieDuration(r io.Reader) time.Duration {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return 0
+	}
+	var timescale, units uint64
+	if buf[0] == 1 {
+		// creation and modification times are 64-bit in version 1
+		if _, err := io.ReadFull(r, buf[:28]); err != nil {
+			return 0
+		}
+		timescale = uint64(binary.BigEndian.Uint32(buf[16:]))
+		units = binary.BigEndian.Uint64(buf[20:])
+	} else {
+		if _, err := io.ReadFull(r, buf[:16]); err != nil {
+			return 0
+		}
+		timescale = uint64(binary.BigEndian.Uint32(buf[8:]))
+		units = uint64(binary.BigEndian.Uint32(buf[12:]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(units) / float64(timescale) * float64(time.Second))
+}
+
+func readMP4TrackID(r io.Reader) uint32 {
+	buf := make([]byte, 24)
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return 0
+	}
+	skip := 8 // creation and modification times
+	if buf[0] == 1 {
+		skip = 16
+	}
+	if _, err := io.ReadFull(r, buf[:skip+4]); err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(buf[skip:])
+}
+
+// readNeroChapterCount reads the entry count of a Nero chpl atom
+func readNeroChapterCount(r io.Reader) int {
+	buf := make([]byte, 9)
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return 0
+	}
+	countAt := 4
+	if buf[0] > 0 {
+		// Version 1 adds a reserved 32-bit field before the count
+		countAt = 8
+	}
+	if _, err := io.ReadFull(r, buf[4:countAt+1]); err != nil {
+		return 0
+	}
+	return int(buf[countAt])
+}
+
+// MPEG audio layer III tables; bitrates are indexed by [1 for MPEG-1, else 0][index]
+// and sample rates by the version bits
+var (
+	mp3Bitrates = [2][16]int{
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	}
+	mp3SampleRates = map[byte][3]int{
+		0: {11025, 12000, 8000},  // MPEG 2.5
+		2: {22050, 24000, 16000}, // MPEG 2
+		3: {44100, 48000, 32000}, // MPEG 1
+	}
+)
+
+// mp3SyncSearchLimit bounds how far past the ID3v2 tag the first frame is looked for
+const mp3SyncSearchLimit = 64 * 1024
+
+// readMP3Duration reads the playing time of a layer III MP3 from the frame count in
+// its Xing/Info or VBRI header, or estimates it from the bitrate of a CBR file.
+func readMP3Duration(r io.ReadSeeker) time.Duration {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0
+	}
+
+	header := make([]byte, 10)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0
+	}
+	n, _ := io.ReadFull(r, header)
+	start := id3v2Size(header[:n])
+
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0
+	}
+	buf := make([]byte, mp3SyncSearchLimit)
+	n, _ = io.ReadFull(r, buf)
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xFF || buf[i+1]&0xE0 != 0xE0 {
+			continue
+		}
+		version := (buf[i+1] >> 3) & 0x03
+		layer := (buf[i+1] >> 1) & 0x03
+		bitrateIndex := buf[i+2] >> 4
+		rateIndex := (buf[i+2] >> 2) & 0x03
+		rates, ok := mp3SampleRates[version]
+		if !ok || layer != 1 || rateIndex == 3 || bitrateIndex == 0 || bitrateIndex == 15 {
+			continue
+		}
+
+		mpeg1 := 0
+		samplesPerFrame := 576
+		sideInfo := 17
+		if buf[i+3]>>6 == 3 {
+			sideInfo = 9
+		}
+		if version == 3 {
+			mpeg1 = 1
+			samplesPerFrame = 1152
+			sideInfo = 32
+			if buf[i+3]>>6 == 3 {
+				sideInfo = 17
+			}
+		}
+		sampleRate := rates[rateIndex]
+		bitrate := mp3Bitrates[mpeg1][bitrateIndex] * 1000
+
+		frameDuration := func(frames uint32) time.Duration {
+			return time.Duration(float64(frames) * float64(samplesPerFrame) / float64(sampleRate) * float64(time.Second))
+		}
+		if xing := i + 4 + sideInfo; xing+12 <= len(buf) {
+			marker := string(buf[xing : xing+4])
+			if (marker == "Xing" || marker == "Info") && binary.BigEndian.Uint32(buf[xing+4:])&0x01 != 0 {
+				return frameDuration(binary.BigEndian.Uint32(buf[xing+8:]))
+			}
+		}
+		if vbri := i + 36; vbri+18 <= len(buf) && string(buf[vbri:vbri+4]) == "VBRI" {
+			return frameDuration(binary.BigEndian.Uint32(buf[vbri+14:]))
+		}
+		audioBytes := size - start - int64(i)
+		return time.Duration(float64(audioBytes*8) / float64(bitrate) * float64(time.Second))
+	}
+	return 0
+}
+
+// readFLACDuration reads the total samples and sample rate from FLAC stream info
+func readFLACDuration(r io.ReadSeeker) time.Duration {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0
+	}
+	buf := make([]byte, 4+4+18)
+	if _, err := io.ReadFull(r, buf); err != nil || string(buf[:4]) != "fLaC" || buf[4]&0x7f != 0 {
+		return 0
+	}
+	info := buf[8:]
+	sampleRate := uint64(info[10])<<12 | uint64(info[11])<<4 | uint64(info[12])>>4
+	totalSamples := uint64(info[13]&0x0f)<<32 | uint64(binary.BigEndian.Uint32(info[14:]))
+	if sampleRate == 0 {
+		return 0
+	}
+	return time.Duration(float64(totalSamples) / float64(sampleRate) * float64(time.Second))
+}
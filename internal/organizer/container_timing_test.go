@@ -0,0 +1,109 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/dhowden/tag"
+	"github.com/stretchr/testify/assert"
+)
+
+func mp4Atom(name string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	buf := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(buf, uint32(8+len(body)))
+	copy(buf[4:], name)
+	return append(buf, body...)
+}
+
+func TestReadMP4Timing(t *testing.T) {
+	u32 := func(values ...uint32) []byte {
+		buf := make([]byte, 4*len(values))
+		for i, v := range values {
+			binary.BigEndian.PutUint32(buf[4*i:], v)
+		}
+		return buf
+	}
+	// version 0: flags, created, modified, timescale, duration (11h23m at 1000/s)
+	mvhd := mp4Atom("mvhd", u32(0, 0, 0, 1000, 40980000))
+	track := func(id, samples uint32, children ...[]byte) []byte {
+		stbl := mp4Atom("stbl", mp4Atom("stsz", u32(0, 0, samples)))
+		return mp4Atom("trak", append([][]byte{
+			mp4Atom("tkhd", u32(0, 0, 0, id)),
+			mp4Atom("mdia", mp4Atom("minf", stbl)),
+		}, children...)...)
+	}
+	nero := mp4Atom("udta", mp4Atom("chpl", []byte{1, 0, 0, 0, 0, 0, 0, 0, 12}))
+
+	t.Run("QuickTime chapter track", func(t *testing.T) {
+		audio := track(1, 90000, mp4Atom("tref", mp4Atom("chap", u32(2))))
+		text := track(2, 43)
+		// A 64-bit mdat before moov must be stepped over
+		mdat := append(u32(1), []byte("mdat")...)
+		mdat = append(mdat, 0, 0, 0, 0, 0, 0, 0, 20, 0xAA, 0xBB, 0xCC, 0xDD)
+		file := bytes.Join([][]byte{mp4Atom("ftyp", []byte("M4B ")), mdat, mp4Atom("moov", mvhd, audio, text, nero)}, nil)
+
+		chapters, duration := readMP4Timing(bytes.NewReader(file))
+		assert.Equal(t, 43, chapters, "the chapter track wins over chpl")
+		assert.Equal(t, 11*time.Hour+23*time.Minute, duration)
+	})
+
+	t.Run("Nero chapters", func(t *testing.T) {
+		file := append(mp4Atom("ftyp", []byte("M4B ")), mp4Atom("moov", mvhd, track(1, 90000), nero)...)
+		chapters, _ := readMP4Timing(bytes.NewReader(file))
+		assert.Equal(t, 12, chapters)
+	})
+}
+
+func TestReadMP3Duration(t *testing.T) {
+	// MPEG-1 layer III, 128 kbps, 44.1 kHz, stereo
+	frameHeader := []byte{0xFF, 0xFB, 0x90, 0x00}
+	id3 := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 10}
+	id3 = append(id3, make([]byte, 10)...)
+
+	t.Run("Xing frame count", func(t *testing.T) {
+		frame := append([]byte{}, frameHeader...)
+		frame = append(frame, make([]byte, 32)...)
+		frame = append(frame, []byte("Xing")...)
+		frame = binary.BigEndian.AppendUint32(frame, 1)
+		frame = binary.BigEndian.AppendUint32(frame, 38281) // ~1000 s of 1152-sample frames
+		file := append(append([]byte{}, id3...), frame...)
+		file = append(file, make([]byte, 400)...)
+
+		duration := readMP3Duration(bytes.NewReader(file))
+		assert.InDelta(t, 1000, duration.Seconds(), 0.1)
+	})
+
+	t.Run("CBR estimate", func(t *testing.T) {
+		audio := append([]byte{}, frameHeader...)
+		audio = append(audio, make([]byte, 16000*60-4)...) // one minute at 128 kbps
+		file := append(append([]byte{}, id3...), audio...)
+
+		duration := readMP3Duration(bytes.NewReader(file))
+		assert.InDelta(t, 60, duration.Seconds(), 0.01)
+	})
+}
+
+func TestReadContainerTimingCountsID3Chapters(t *testing.T) {
+	raw := map[string]interface{}{"TIT2": "Book", "CHAP": []byte{}, "CHAP_0": []byte{}, "CHAP_1": []byte{}, "CTOC": []byte{}}
+	chapters, _ := readContainerTiming(bytes.NewReader(nil), tag.ID3v2_4, raw)
+	assert.Equal(t, 3, chapters)
+}
+
+func TestReadFLACDuration(t *testing.T) {
+	info := make([]byte, 34)
+	// 44100 Hz, stereo, 16 bit, 44100*90 samples
+	sampleRate, samples := uint64(44100), uint64(44100*90)
+	info[10] = byte(sampleRate >> 12)
+	info[11] = byte(sampleRate >> 4)
+	info[12] = byte(sampleRate<<4) | 0x02
+	info[13] = 0xF0 | byte(samples>>32)
+	binary.BigEndian.PutUint32(info[14:], uint32(samples))
+	file := append([]byte("fLaC\x00\x00\x00\x22"), info...)
+
+	assert.Equal(t, 90*time.Second, readFLACDuration(bytes.NewReader(file)))
+}
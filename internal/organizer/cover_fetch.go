@@ -0,0 +1,240 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCoversURL serves the Open Library cover images FetchCovers downloads by
+// ISBN or cover ID
+const DefaultCoversURL = "https://covers.openlibrary.org"
+
+// Limits of a fetched cover. Smaller images are placeholders, larger ones aren't
+// worth the disk space.
+const (
+	MaxCoverBytes = 10 << 20
+	minCoverBytes = 1 << 10
+)
+
+// FetchedCover records a cover downloaded for a book that had none, with the
+// source to credit for it
+type FetchedCover struct {
+	Path   string `json:"path"`   // The cover file written
+	Source string `json:"source"` // URL of the image
+	Credit string `json:"credit"` // Service the image comes from
+}
+
+// CoverFetcher finds cover art online: by ISBN on Open Library, by ASIN in the
+// Audible catalog, then by title and author in the Open Library search
+type CoverFetcher struct {
+	Cache      *HTTPCache
+	SearchURL  string // Open Library API for the title search; DefaultAuthorAuthorityURL
+	CoversURL  string // Open Library cover images; DefaultCoversURL
+	CatalogURL string // Audible catalog API for ASINs; DefaultSeriesCatalogURL
+}
+
+// NewCoverFetcher creates a fetcher querying the default services through cache
+func NewCoverFetcher(cache *HTTPCache) *CoverFetcher {
+	return &CoverFetcher{
+		Cache:      cache,
+		SearchURL:  DefaultAuthorAuthorityURL,
+		CoversURL:  DefaultCoversURL,
+		CatalogURL: DefaultSeriesCatalogURL,
+	}
+}
+
+// Fetch returns the first cover found for metadata and where it came from. Image
+// types other than JPEG and PNG are skipped. It returns ErrHTTPNotFound when no
+// source has a cover.
+func (f *CoverFetcher) Fetch(metadata Metadata) ([]byte, FetchedCover, error) {
+	type candidate struct {
+		url    func() (string, error)
+		credit string
+	}
+	candidates := []candidate{
+		{func() (string, error) { return f.isbnCover(metadata.ISBN) }, "Open Library"},
+		{func() (string, error) { return f.asinCover(metadata.ASIN) }, "Audible"},
+		{func() (string, error) { return f.searchCover(metadata) }, "Open Library"},
+	}
+
+	var lastErr error
+	for _, c := range candidates {
+		imageURL, err := c.url()
+		if err == nil && imageURL != "" {
+			var data []byte
+			if data, err = f.Cache.Get(imageURL, MaxCoverBytes); err == nil {
+				if coverExtension(data) != "" && len(data) >= minCoverBytes {
+					return data, FetchedCover{Source: imageURL, Credit: c.credit}, nil
+				}
+				continue
+			}
+		}
+		if err != nil && !errors.Is(err, ErrHTTPNotFound) {
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return nil, FetchedCover{}, lastErr
+	}
+	return nil, FetchedCover{}, ErrHTTPNotFound
+}
+
+// isbnCover returns the Open Library cover URL of an ISBN, which answers 404 when
+// it has no cover
+func (f *CoverFetcher) isbnCover(isbn string) (string, error) {
+	if isbn == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("%s/b/isbn/%s-L.jpg?default=false", strings.TrimRight(f.CoversURL, "/"), url.PathEscape(isbn)), nil
+}
+
+// asinCover looks the largest product image of an ASIN up in the Audible catalog
+func (f *CoverFetcher) asinCover(asin string) (string, error) {
+	if asin == "" {
+		return "", nil
+	}
+	data, err := f.Cache.Get(fmt.Sprintf("%s/1.0/catalog/products/%s?response_groups=media&image_sizes=500,1024",
+		strings.TrimRight(f.CatalogURL, "/"), url.PathEscape(asin)), 1<<20)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Product struct {
+			Images map[string]string `json:"product_images"`
+		} `json:"product"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("catalog lookup for %s returned invalid JSON: %w", asin, err)
+	}
+	for _, size := range []string{"1024", "500"} {
+		if image := result.Product.Images[size]; image != "" {
+			return image, nil
+		}
+	}
+	return "", nil
+}
+
+// searchCover finds the cover of the best Open Library match for the title and
+// first author
+func (f *CoverFetcher) searchCover(metadata Metadata) (string, error) {
+	if metadata.Title == "" || len(metadata.Authors) == 0 {
+		return "", nil
+	}
+	query := url.Values{
+		"title":  {metadata.Title},
+		"author": {metadata.Authors[0]},
+		"limit":  {"1"},
+		"fields": {"cover_i"},
+	}
+	data, err := f.Cache.Get(strings.TrimRight(f.SearchURL, "/")+"/search.json?"+query.Encode(), 1<<20)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Docs []struct {
+			CoverID int `json:"cover_i"`
+		} `json:"docs"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("cover search for %q returned invalid JSON: %w", metadata.Title, err)
+	}
+	if len(result.Docs) == 0 || result.Docs[0].CoverID <= 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%s/b/id/%d-L.jpg?default=false", strings.TrimRight(f.CoversURL, "/"), result.Docs[0].CoverID), nil
+}
+
+// coverExtension returns the file extension for image data, or "" when it is not
+// a JPEG or PNG
+func coverExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	}
+	return ""
+}
+
+// hasCover reports whether a book directory already has cover art: an image file
+// of its own, or a picture embedded in its first audio file
+func (o *Organizer) hasCover(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true // Nothing to add a cover to
+	}
+	audio := ""
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch ext := strings.ToLower(filepath.Ext(entry.Name())); {
+		case ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".webp":
+			return true
+		case audio == "" && o.config.Extensions.IsAudio(ext):
+			audio = filepath.Join(dir, entry.Name())
+		}
+	}
+	if audio == "" {
+		return false
+	}
+	file, err := os.Open(audio)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	tags, err := readAudioTags(file)
+	return err == nil && tags.Picture() != nil
+}
+
+// singleFileHasFolder reports whether a file organized in flat mode lands in a
+// folder of its own book, the only place a fetched cover can't belong to another
+func (o *Organizer) singleFileHasFolder(filePath string, metadata Metadata) bool {
+	if !o.config.BookFolder && !o.layoutCalculator.layout().HasBookFolder(metadata) {
+		return false
+	}
+	return !o.layoutCalculator.flattensSingleFiles() || !o.isWholeBookFile(filePath, metadata)
+}
+
+// fetchCover downloads cover art into dir, the folder of one organized book, when
+// FetchCovers is set and the book has none. A failure is only a warning; when a
+// service is unreachable the rest of the run answers from the cache.
+func (o *Organizer) fetchCover(dir string, metadata Metadata) {
+	if !o.config.FetchCovers || o.config.DryRun || o.hasRemoteTarget() || o.hasCover(dir) {
+		return
+	}
+	if o.coverFetcher == nil {
+		cache, err := NewHTTPCache("", o.config.NoNetwork)
+		if err != nil {
+			PrintYellow("⚠️  Warning: cover download disabled: %v", err)
+			o.config.FetchCovers = false
+			return
+		}
+		o.coverFetcher = NewCoverFetcher(cache)
+	}
+
+	data, cover, err := o.coverFetcher.Fetch(metadata)
+	switch {
+	case errors.Is(err, ErrHTTPNotFound) || errors.Is(err, errOffline):
+		o.debugLog("No cover found for %s", metadata.Title)
+		return
+	case err != nil:
+		PrintYellow("⚠️  Warning: %v; using cached covers only", err)
+		o.coverFetcher.Cache.Offline = true
+		return
+	}
+
+	cover.Path = filepath.Join(dir, "cover"+coverExtension(data))
+	if err := os.WriteFile(cover.Path, data, 0o644); err != nil {
+		PrintYellow("⚠️  Warning: couldn't write %s: %v", cover.Path, err)
+		return
+	}
+	PrintGreen("🖼️  Fetched cover for %s from %s", metadata.Title, cover.Credit)
+	o.summary.CoversFetched = append(o.summary.CoversFetched, cover)
+}
@@ -0,0 +1,146 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCover = append([]byte("\xff\xd8\xff\xe0"), bytes.Repeat([]byte{0}, 2048)...)
+
+// newCoverService serves Open Library covers and search and the Audible catalog for
+// one known ISBN, ASIN, and title, and counts the requests it receives
+func newCoverService(t *testing.T) (*CoverFetcher, *int) {
+	t.Helper()
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/b/isbn/9780441013593-L.jpg", "/b/id/42-L.jpg", "/audible/B002V1OF70.jpg":
+			w.Write(testCover)
+		case "/b/isbn/0000000000-L.jpg":
+			w.Write([]byte("GIF89a"))
+		case "/1.0/catalog/products/B002V1OF70":
+			json.NewEncoder(w).Encode(map[string]any{"product": map[string]any{
+				"product_images": map[string]string{"500": server.URL + "/audible/B002V1OF70.jpg"},
+			}})
+		case "/search.json":
+			cover := 0
+			if r.URL.Query().Get("title") == "Dune" {
+				cover = 42
+			}
+			json.NewEncoder(w).Encode(map[string]any{"docs": []map[string]int{{"cover_i": cover}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cache, err := NewHTTPCache(t.TempDir(), false)
+	require.NoError(t, err)
+	fetcher := NewCoverFetcher(cache)
+	fetcher.SearchURL, fetcher.CoversURL, fetcher.CatalogURL = server.URL, server.URL, server.URL
+	return fetcher, &requests
+}
+
+func TestCoverFetcherSources(t *testing.T) {
+	fetcher, _ := newCoverService(t)
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		source   string
+		credit   string
+	}{
+		{"isbn", Metadata{ISBN: "9780441013593", Title: "Dune"}, "/b/isbn/9780441013593-L.jpg", "Open Library"},
+		{"asin", Metadata{ISBN: "1111111111", ASIN: "B002V1OF70"}, "/audible/B002V1OF70.jpg", "Audible"},
+		{"title and author", Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}}, "/b/id/42-L.jpg", "Open Library"},
+		{"not an image type kept", Metadata{ISBN: "0000000000", Title: "Dune", Authors: []string{"Frank Herbert"}}, "/b/id/42-L.jpg", "Open Library"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, cover, err := fetcher.Fetch(tt.metadata)
+			require.NoError(t, err)
+			assert.Equal(t, testCover, data)
+			assert.Contains(t, cover.Source, tt.source)
+			assert.Equal(t, tt.credit, cover.Credit)
+		})
+	}
+
+	_, _, err := fetcher.Fetch(Metadata{Title: "Unknown", Authors: []string{"Nobody"}})
+	assert.ErrorIs(t, err, ErrHTTPNotFound)
+}
+
+func TestHTTPCacheKeepsAnswersAndMisses(t *testing.T) {
+	fetcher, requests := newCoverService(t)
+	url := fetcher.CoversURL + "/b/isbn/9780441013593-L.jpg"
+	missing := fetcher.CoversURL + "/b/isbn/missing-L.jpg"
+
+	for range 2 {
+		data, err := fetcher.Cache.Get(url, MaxCoverBytes)
+		require.NoError(t, err)
+		assert.Equal(t, testCover, data)
+		_, err = fetcher.Cache.Get(missing, MaxCoverBytes)
+		assert.ErrorIs(t, err, ErrHTTPNotFound)
+	}
+	assert.Equal(t, 2, *requests, "the second round answers from the cache")
+
+	_, err := fetcher.Cache.Get(url, 100)
+	assert.ErrorContains(t, err, "larger than")
+
+	fetcher.Cache.Offline = true
+	_, err = fetcher.Cache.Get(fetcher.CoversURL+"/b/id/42-L.jpg", MaxCoverBytes)
+	assert.ErrorIs(t, err, errOffline)
+}
+
+func TestFetchCoversWhenOrganizing(t *testing.T) {
+	dune := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}, ISBN: "9780441013593"}
+
+	for _, existing := range []string{"", "folder.png"} {
+		t.Run("existing "+existing, func(t *testing.T) {
+			base := t.TempDir()
+			output := t.TempDir()
+			book := filepath.Join(base, "download")
+			files := []string{"01.mp3", "02.mp3"}
+			if existing != "" {
+				files = append(files, existing)
+			}
+			writeBook(t, book, nil, files...)
+
+			org, err := NewOrganizer(&OrganizerConfig{
+				BaseDir:      base,
+				OutputDir:    output,
+				FieldMapping: DefaultFieldMapping(),
+				FetchCovers:  true,
+			})
+			require.NoError(t, err)
+			org.coverFetcher, _ = newCoverService(t)
+			CaptureOutput(func() {
+				require.NoError(t, org.OrganizePathWithMetadata(book, dune))
+			})
+
+			cover := filepath.Join(output, "Frank Herbert", "Dune", "cover.jpg")
+			if existing != "" {
+				assert.NoFileExists(t, cover)
+				assert.Empty(t, org.GetSummary().CoversFetched)
+				return
+			}
+			data, err := os.ReadFile(cover)
+			require.NoError(t, err)
+			assert.Equal(t, testCover, data)
+			require.Len(t, org.GetSummary().CoversFetched, 1)
+			assert.Equal(t, cover, org.GetSummary().CoversFetched[0].Path)
+			assert.Equal(t, "Open Library", org.GetSummary().CoversFetched[0].Credit)
+		})
+	}
+}
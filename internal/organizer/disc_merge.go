@@ -0,0 +1,291 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// discFolderPattern matches the name of a folder holding one disc of a split rip,
+// such as "Book Title CD1", "Book Title - Disc 2", or "Book Title (Disk 03)"
+var discFolderPattern = regexp.MustCompile(
+	`(?i)^(.*?)(?:[\s._-]+|[\s._-]*[(\[])(?:cd|disc|disk)[\s._-]*(\d{1,2})[)\]]?$`,
+)
+
+// parseDiscName splits a folder name or title into the book name and disc number
+func parseDiscName(name string) (stem string, disc int, ok bool) {
+	m := discFolderPattern.FindStringSubmatch(strings.TrimSpace(name))
+	if m == nil || strings.TrimSpace(m[1]) == "" {
+		return "", 0, false
+	}
+	disc, err := strconv.Atoi(m[2])
+	if err != nil || disc <= 0 {
+		return "", 0, false
+	}
+	return strings.TrimSpace(m[1]), disc, true
+}
+
+// discBook is one disc folder of a split book
+type discBook struct {
+	Book
+	disc int
+}
+
+// discTracks numbers the audio files of one disc of a merged book: by disc ("2-05 - "),
+// or with a width, continuously after the offset tracks of the discs before it
+type discTracks struct {
+	number int
+	offset int
+	width  int
+}
+
+// discSet collects the sibling disc folders that may belong to one book
+type discSet struct {
+	stem  string
+	discs []discBook
+}
+
+// holdDiscBook keeps a book whose folder is named like one disc of a split rip until
+// the walk has found its siblings. It reports whether the book was held.
+func (o *Organizer) holdDiscBook(book Book) bool {
+	if !o.config.MergeDiscs {
+		return false
+	}
+	stem, disc, ok := parseDiscName(filepath.Base(book.Path))
+	if !ok {
+		return false
+	}
+
+	key := filepath.Dir(book.Path) + string(filepath.Separator) + normalizeString(stem)
+	set := o.discSets[key]
+	if set == nil {
+		if o.discSets == nil {
+			o.discSets = make(map[string]*discSet)
+		}
+		set = &discSet{stem: stem}
+		o.discSets[key] = set
+		o.discSetOrder = append(o.discSetOrder, set)
+	}
+	set.discs = append(set.discs, discBook{Book: book, disc: disc})
+	return true
+}
+
+// organizeDiscSets organizes the disc folders held during the walk, merging each
+// complete set into one book and organizing the others as separate books
+func (o *Organizer) organizeDiscSets() {
+	for _, set := range o.discSetOrder {
+		sort.SliceStable(set.discs, func(i, j int) bool { return set.discs[i].disc < set.discs[j].disc })
+
+		metadata, ok := o.mergedDiscMetadata(set)
+		if !ok {
+			for _, disc := range set.discs {
+				if err := o.OrganizeAudiobook(disc.Path, disc.Provider); err != nil {
+					_ = o.handleBookError(disc.Path, err)
+				}
+			}
+			continue
+		}
+
+		if err := o.mergeDiscs(set, metadata); err != nil {
+			for _, disc := range set.discs {
+				_ = o.handleBookError(disc.Path, err)
+			}
+		}
+	}
+	o.discSets, o.discSetOrder = nil, nil
+}
+
+// mergedDiscMetadata returns the metadata of the book a disc set forms. The set must
+// have at least two discs with distinct numbers whose tags agree on the authors and,
+// once disc markers are removed, the title.
+func (o *Organizer) mergedDiscMetadata(set *discSet) (Metadata, bool) {
+	if len(set.discs) < 2 {
+		return Metadata{}, false
+	}
+
+	var merged Metadata
+	var authors, title string
+	for i, disc := range set.discs {
+		if i > 0 && disc.disc == set.discs[i-1].disc {
+			return Metadata{}, false
+		}
+		metadata, err := o.prepareMetadata(disc.Provider)
+		if err != nil {
+			return Metadata{}, false
+		}
+		if stem, _, ok := parseDiscName(metadata.Title); ok {
+			metadata.Title = stem
+		}
+
+		discAuthors := strings.Join(normalizeStrings(metadata.Authors), ",")
+		discTitle := normalizeString(metadata.Title)
+		if i == 0 {
+			merged, authors, title = metadata, discAuthors, discTitle
+			continue
+		}
+		if discAuthors != authors || discTitle != title {
+			return Metadata{}, false
+		}
+	}
+	return merged, true
+}
+
+// mergeDiscs moves the files of every disc of a set into one book directory as a
+// single transaction. Audio files get disc-aware track prefixes ("2-05 - "), and
+// other files that would collide, like a cover on every disc, are named after their disc.
+func (o *Organizer) mergeDiscs(set *discSet, metadata Metadata) error {
+	o.logMetadataIfVerbose(metadata, set.discs[0].Provider)
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+
+	targetDir, err := o.layoutCalculator.CalculateTargetPathE(metadata)
+	if err != nil {
+		return fmt.Errorf("error calculating target path: %w", err)
+	}
+	if o.shouldSkipMove(metadata, set.discs[0].Path, targetDir) {
+		return nil
+	}
+	// A disc that changed holds back the whole set, so it is never merged partially
+	changed := false
+	for _, disc := range set.discs {
+		changed = o.sourceChanged(disc.Path) || changed
+	}
+	if changed {
+		return nil
+	}
+	PrintCyan("💿 Merging %d discs of %s into %s", len(set.discs), set.stem, o.getRelativeTargetPath(targetDir))
+
+	discEntries := make([][]os.DirEntry, len(set.discs))
+	tracks := 0
+	for i, disc := range set.discs {
+		entries, err := os.ReadDir(disc.Path)
+		if err != nil {
+			return fmt.Errorf("error reading source directory: %w", err)
+		}
+		discEntries[i] = entries
+		tracks += o.countAudioFiles(entries)
+	}
+	width := 0
+	if o.config.ContinuousTracks {
+		width = max(2, len(strconv.Itoa(tracks)))
+	}
+
+	var moves []FilePair
+	hidden := make(map[string][]string)
+	deleted := make(map[string][]string) // File names of each disc, for deleteByExtension
+	used := make(map[string]bool)
+	offset := 0
+	for i, disc := range set.discs {
+		entries := discEntries[i]
+		numbering := discTracks{number: disc.disc, offset: offset, width: width}
+		offset += o.countAudioFiles(entries)
+		fileNames, discHidden := o.planBookFiles(entries, disc.Path, &metadata, numbering)
+		hidden[disc.Path] = discHidden
+		deleted[disc.Path] = fileEntryNames(entries)
+
+		for _, file := range fileNames {
+			if used[file.To] {
+				file.To = o.fileNamer().Normalize(fmt.Sprintf("Disc %d - %s", disc.disc, file.To))
+			}
+			used[file.To] = true
+
+			sourceName := filepath.Join(disc.Path, file.From)
+			if o.config.Verbose || o.config.DryRun {
+				o.printFileLine(disc.Path, o.formatFileMove(sourceName, filepath.Join(targetDir, file.To), o.config.DryRun))
+			}
+			moves = append(moves, FilePair{From: sourceName, To: file.To})
+		}
+	}
+
+	o.endFileLines()
+	if err := o.checkFileSizes(moves); err != nil {
+		return err
+	}
+	if !o.config.DryRun {
+		if err := o.moveBookFiles(targetDir, moves); err != nil {
+			return fmt.Errorf("error merging discs, sources left untouched: %w", err)
+		}
+		if o.hiddenFilePolicy() == HiddenFilesDelete {
+			for _, disc := range set.discs {
+				o.deleteHiddenFiles(disc.Path, hidden[disc.Path])
+			}
+		}
+		if o.config.ContinuousTrackTags {
+			o.writeContinuousTrackTags(targetDir, moves, tracks)
+		}
+	}
+	for _, disc := range set.discs {
+		o.deleteByExtension(disc.Path, deleted[disc.Path])
+	}
+
+	for _, disc := range set.discs {
+		o.summary.Moves = append(o.summary.Moves, MoveSummary{From: disc.Path, To: targetDir})
+		for _, name := range hidden[disc.Path] {
+			o.summary.HiddenFiles = append(o.summary.HiddenFiles, filepath.Join(disc.Path, name))
+		}
+	}
+	for _, move := range moves {
+		o.recordFileMove(move.From, filepath.Join(targetDir, move.To))
+	}
+	if !o.config.DryRun {
+		o.logAlbumMoves(targetDir, o.createAlbumKey(metadata), moves)
+	}
+	return nil
+}
+
+// countAudioFiles counts the audio files of a disc folder that planBookFiles numbers
+func (o *Organizer) countAudioFiles(entries []os.DirEntry) int {
+	count := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || o.config.Extensions.skips(name) ||
+			(IsHiddenFile(name) && o.hiddenFilePolicy() != HiddenFilesMove) {
+			continue
+		}
+		if o.config.Extensions.IsAudio(filepath.Ext(name)) {
+			count++
+		}
+	}
+	return count
+}
+
+// writeContinuousTrackTags writes the continuous track number of each merged audio
+// file into its tags, "13/40", for players that ignore disc numbers. The number is
+// the one its continuous prefix carries. Only MP3 files can be written; the others
+// keep their tags with a warning.
+func (o *Organizer) writeContinuousTrackTags(targetDir string, moves []FilePair, total int) {
+	var unsupported []string
+	written := 0
+	for _, move := range moves {
+		if !o.config.Extensions.IsAudio(filepath.Ext(move.To)) {
+			continue
+		}
+		number, _, found := strings.Cut(move.To, " - ")
+		track, err := strconv.Atoi(number)
+		if !found || err != nil {
+			continue
+		}
+		if !strings.EqualFold(filepath.Ext(move.To), ".mp3") {
+			unsupported = append(unsupported, move.To)
+			continue
+		}
+		path := filepath.Join(targetDir, move.To)
+		if err := setID3TextFrames(path, map[string]string{"TRCK": fmt.Sprintf("%d/%d", track, total)}); err != nil {
+			PrintYellow("⚠️  Couldn't write the track number of %s: %v", path, err)
+			o.summary.Errors = append(o.summary.Errors, fmt.Sprintf("%s: writing track number: %v", path, err))
+			continue
+		}
+		written++
+	}
+	if written > 0 {
+		PrintBlue("🏷️  Numbered the tags of %d tracks continuously", written)
+	}
+	if len(unsupported) > 0 {
+		PrintYellow("⚠️  Kept the track tags of %d non-MP3 files, such as %s: only MP3 tags can be written", len(unsupported), unsupported[0])
+	}
+}
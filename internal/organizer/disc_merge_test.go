@@ -0,0 +1,123 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiscName(t *testing.T) {
+	tests := []struct {
+		name string
+		stem string
+		disc int
+		ok   bool
+	}{
+		{"Book Title CD1", "Book Title", 1, true},
+		{"Book Title - Disc 2", "Book Title", 2, true},
+		{"Book Title (Disk 03)", "Book Title", 3, true},
+		{"Book_Title_cd_4", "Book_Title", 4, true},
+		{"Book Title", "", 0, false},
+		{"CD1", "", 0, false},
+		{"Abracadisc 5", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stem, disc, ok := parseDiscName(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.stem, stem)
+			assert.Equal(t, tt.disc, disc)
+		})
+	}
+}
+
+// writeDisc creates a disc folder with a metadata.json, a cover, and audio files
+func writeDisc(t *testing.T, dir, metadata string, tracks ...string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataFileName), []byte(metadata), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cover.jpg"), []byte("cover"), 0o644))
+	for _, track := range tracks {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, track), []byte(track), 0o644))
+	}
+}
+
+func TestMergeDiscs(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Dune CD1"), `{"title":"Dune (Disc 1)","authors":["Frank Herbert"]}`,
+		"Track 1.mp3", "Track 2.mp3")
+	writeDisc(t, filepath.Join(base, "Dune CD2"), `{"title":"Dune (Disc 2)","authors":["Frank Herbert"]}`,
+		"Track 1.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out, MergeDiscs: true})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	assert.ElementsMatch(t, []string{
+		"1-01 - Track 1.mp3",
+		"1-02 - Track 2.mp3",
+		"2-01 - Track 1.mp3",
+		"cover.jpg",
+		"Disc 2 - cover.jpg",
+		MetadataFileName,
+		"Disc 2 - " + MetadataFileName,
+	}, dirNames(t, filepath.Join(out, "Frank Herbert", "Dune")))
+	assert.Empty(t, org.summary.Errors)
+}
+
+func TestMergeDiscsKeepsInconsistentDiscsApart(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Collection CD1"), `{"title":"First Book","authors":["Jane Doe"]}`, "a.mp3")
+	writeDisc(t, filepath.Join(base, "Collection CD2"), `{"title":"Second Book","authors":["Jane Doe"]}`, "b.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out, MergeDiscs: true})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	assert.Contains(t, dirNames(t, filepath.Join(out, "Jane Doe", "First Book")), "a.mp3")
+	assert.Contains(t, dirNames(t, filepath.Join(out, "Jane Doe", "Second Book")), "b.mp3")
+}
+
+func TestMergeDiscsContinuousTracks(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Dune CD1"), `{"title":"Dune (Disc 1)","authors":["Frank Herbert"]}`,
+		"Track 1.mp3", "Track 2.mp3")
+	writeDisc(t, filepath.Join(base, "Dune CD2"), `{"title":"Dune (Disc 2)","authors":["Frank Herbert"]}`)
+	writeTaggedMP3(t, filepath.Join(base, "Dune CD2", "01 - Track 1.mp3"), map[string]string{"TRCK": "1/1", "TPOS": "2/2"})
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:             base,
+		OutputDir:           out,
+		MergeDiscs:          true,
+		ContinuousTracks:    true,
+		ContinuousTrackTags: true,
+	})
+	require.NoError(t, err)
+	output := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	book := filepath.Join(out, "Frank Herbert", "Dune")
+	assert.Subset(t, dirNames(t, book), []string{"01 - Track 1.mp3", "02 - Track 2.mp3", "03 - Track 1.mp3"})
+	assert.Equal(t, 3, readTags(t, filepath.Join(book, "03 - Track 1.mp3")).TrackNumber, "disc 2 track 1 is track 3")
+	assert.Equal(t, 1, readTags(t, filepath.Join(book, "01 - Track 1.mp3")).TrackNumber, "untagged files get a tag")
+	assert.Contains(t, output, "Numbered the tags of 3 tracks continuously")
+	assert.Empty(t, org.summary.Errors)
+}
+
+func TestContinuousTracksRequireMergeDiscs(t *testing.T) {
+	_, err := NewOrganizer(&OrganizerConfig{BaseDir: t.TempDir(), ContinuousTracks: true})
+	assert.ErrorContains(t, err, "--continuous-tracks requires --merge-discs")
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd)
+
+package organizer
+
+import "errors"
+
+// localFreeSpace is not supported on this platform
+func localFreeSpace(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
+
+// sameFileSystem is not supported on this platform, so every file counts as copied
+func sameFileSystem(string, string) bool {
+	return false
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd
+
+package organizer
+
+import "golang.org/x/sys/unix"
+
+// localFreeSpace returns the bytes available to the current user on the file system
+// holding dir
+func localFreeSpace(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// sameFileSystem reports whether a and b are on the same file system, so moving a
+// file from one to the other is a rename
+func sameFileSystem(a, b string) bool {
+	var aStat, bStat unix.Stat_t
+	if unix.Stat(a, &aStat) != nil || unix.Stat(b, &bStat) != nil {
+		return false
+	}
+	return aStat.Dev == bStat.Dev
+}
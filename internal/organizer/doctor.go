@@ -0,0 +1,231 @@
+package organizer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckStatus is the outcome of one diagnostic check
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// Check is the result of one diagnostic check run by the doctor command
+type Check struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Detail string      `json:"detail"`
+}
+
+// doctorSampleBooks bounds how many books the metadata check reads, so diagnosing a
+// large library stays quick
+const doctorSampleBooks = 25
+
+// errSampleFull stops the metadata check's walk once enough books are read
+var errSampleFull = errors.New("sample full")
+
+// lowFreeSpace is the free space below which the output check warns
+const lowFreeSpace = 1 << 30
+
+// Diagnose checks the organizer's setup without moving anything: that the input and
+// output directories are usable and don't overlap in surprising ways, how much room
+// the output has, quirks of the output file system, and whether the input holds any
+// metadata the organizer can read. Create the organizer with DryRun set so a missing
+// output directory isn't created.
+func (o *Organizer) Diagnose() []Check {
+	checks := []Check{o.checkInputDir()}
+	if checks[0].Status == CheckFail {
+		return checks
+	}
+	checks = append(checks, o.checkOutputDir(), o.checkOverlap())
+	if !o.hasRemoteTarget() {
+		checks = append(checks, o.checkOutputSpace(), o.checkOutputFS())
+	}
+	return append(checks, o.checkMetadataSources())
+}
+
+func (o *Organizer) checkInputDir() Check {
+	check := Check{Name: "Input directory"}
+	dir := o.config.BaseDir
+	info, err := os.Stat(dir)
+	switch {
+	case err != nil:
+		return check.fail("%s: %v", dir, err)
+	case !info.IsDir():
+		return check.fail("%s is not a directory", dir)
+	}
+	if _, err := os.ReadDir(dir); err != nil {
+		return check.fail("cannot read %s%s: %v", dir, ownerNote(dir), err)
+	}
+	// Checked even for a dry run, as the doctor diagnoses the run that moves files
+	if !o.config.SeedSafe {
+		if err := writeAccess(dir); err != nil {
+			return check.fail("cannot move files out of %s%s: %v", dir, ownerNote(dir), err)
+		}
+	}
+	return check.pass("%s is readable", dir)
+}
+
+func (o *Organizer) checkOutputDir() Check {
+	check := Check{Name: "Output directory"}
+	dir := o.config.OutputDir
+	switch {
+	case dir == "":
+		return check.pass("none given, books are organized inside %s", o.config.BaseDir)
+	case o.hasRemoteTarget():
+		return check.warn("%s is remote and is not checked", dir)
+	}
+	existing := nearestExistingDir(dir)
+	if info, err := os.Stat(existing); err != nil || !info.IsDir() {
+		return check.fail("%s is not a directory", existing)
+	}
+	if err := writeAccess(existing); err != nil {
+		return check.fail("cannot write to %s%s: %v", existing, ownerNote(existing), err)
+	}
+	if existing != filepath.Clean(dir) {
+		return check.pass("%s will be created in %s", dir, existing)
+	}
+	return check.pass("%s is writable", dir)
+}
+
+func (o *Organizer) checkOverlap() Check {
+	check := Check{Name: "Input and output overlap"}
+	in, out := filepath.Clean(o.config.BaseDir), o.config.OutputDir
+	switch {
+	case out == "" || o.hasRemoteTarget() || filepath.Clean(out) == in:
+		return check.pass("books are organized in place")
+	case isSubPathOf(in, out):
+		return check.warn("%s lies inside the input; it is skipped while scanning", out)
+	case isSubPathOf(out, in):
+		return check.warn("the input lies inside the output %s; organized books there will be scanned again", out)
+	}
+	return check.pass("separate directories")
+}
+
+func (o *Organizer) checkOutputSpace() Check {
+	check := Check{Name: "Free space"}
+	dir := nearestExistingDir(o.outputOrBaseDir())
+	free, err := localFreeSpace(dir)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return check.pass("not reported on this platform")
+	}
+	if err != nil {
+		return check.warn("couldn't read free space of %s: %v", dir, err)
+	}
+	if free < lowFreeSpace {
+		return check.warn("only %s free on %s", formatBytes(free), dir)
+	}
+	return check.pass("%s free on %s", formatBytes(free), dir)
+}
+
+// LocalFreeSpace returns the bytes available on the file system that holds dir, or
+// that will hold it once created. Platforms that don't report free space return
+// errors.ErrUnsupported.
+func LocalFreeSpace(dir string) (uint64, error) {
+	return localFreeSpace(nearestExistingDir(dir))
+}
+
+func (o *Organizer) checkOutputFS() Check {
+	check := Check{Name: "File system"}
+	dir := o.outputOrBaseDir()
+	if limits := detectRestrictedFS(dir); limits != nil {
+		detail := fmt.Sprintf("%s is on %s; names are cleaned with the Windows rules", dir, limits.Name)
+		if limits.MaxFileSize > 0 {
+			detail += fmt.Sprintf(" and files over %s are refused", formatBytes(uint64(limits.MaxFileSize)))
+		}
+		return check.warn("%s", detail)
+	}
+	if fsType := fileSystemType(nearestExistingDir(dir)); fsType != "" {
+		return check.pass("%s is on %s", dir, fsType)
+	}
+	return check.pass("no known quirks")
+}
+
+func (o *Organizer) checkMetadataSources() Check {
+	check := Check{Name: "Metadata sources"}
+	options := ScanOptionsFromConfig(&o.config)
+	options.SkipUnreadable = true
+
+	var sources SourceCounts
+	unmatched := 0
+	err := NewScanner(options).Walk(o.config.BaseDir, ScanHandler{
+		Book: func(book Book) error {
+			sources.add(book.Metadata)
+			if sources.Total() >= doctorSampleBooks {
+				return errSampleFull
+			}
+			return nil
+		},
+		Unmatched: func(string) { unmatched++ },
+		Error: func(string, error) error {
+			return nil
+		},
+	})
+	if err != nil && !errors.Is(err, errSampleFull) {
+		return check.fail("scanning %s failed: %v", o.config.BaseDir, err)
+	}
+
+	switch {
+	case sources.Total() > 0:
+		return check.pass("sampled %d book(s): %s", sources.Total(), sources)
+	case !o.config.UseEmbeddedMetadata && unmatched > 0:
+		return check.warn("no metadata.json found in %d directories with audio; try --use-embedded-metadata", unmatched)
+	default:
+		return check.warn("no books found in %s", o.config.BaseDir)
+	}
+}
+
+// outputOrBaseDir returns where organized books end up: the output directory, or the
+// input directory when organizing in place
+func (o *Organizer) outputOrBaseDir() string {
+	if o.config.OutputDir != "" {
+		return o.config.OutputDir
+	}
+	return o.config.BaseDir
+}
+
+// ownerNote describes who owns path, in parentheses, when the platform reports it
+func ownerNote(path string) string {
+	if owner := describeOwner(path); owner != "" {
+		return " (" + owner + ")"
+	}
+	return ""
+}
+
+func (c Check) pass(format string, args ...any) Check {
+	return c.with(CheckPass, format, args...)
+}
+
+func (c Check) warn(format string, args ...any) Check {
+	return c.with(CheckWarn, format, args...)
+}
+
+func (c Check) fail(format string, args ...any) Check {
+	return c.with(CheckFail, format, args...)
+}
+
+func (c Check) with(status CheckStatus, format string, args ...any) Check {
+	c.Status = status
+	c.Detail = fmt.Sprintf(format, args...)
+	return c
+}
+
+// formatBytes formats a byte count with a binary unit, such as "3.2 GiB"
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
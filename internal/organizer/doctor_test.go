@@ -0,0 +1,52 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	base := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Book"), `{"title":"Book","authors":["Author"]}`, "01.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: filepath.Join(base, "Library"), DryRun: true})
+	require.NoError(t, err)
+
+	checks := map[string]Check{}
+	for _, check := range org.Diagnose() {
+		checks[check.Name] = check
+	}
+	assert.Equal(t, CheckPass, checks["Input directory"].Status)
+	assert.Equal(t, CheckPass, checks["Output directory"].Status)
+	assert.Contains(t, checks["Output directory"].Detail, "will be created in "+base)
+	assert.Equal(t, CheckWarn, checks["Input and output overlap"].Status)
+	assert.Equal(t, CheckPass, checks["Metadata sources"].Status)
+	assert.Equal(t, "sampled 1 book(s): 1 metadata.json", checks["Metadata sources"].Detail)
+	assert.NoDirExists(t, filepath.Join(base, "Library"))
+}
+
+func TestDiagnoseSuggestsEmbeddedMetadata(t *testing.T) {
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "Book"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "Book", "01.mp3"), []byte("audio"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base})
+	require.NoError(t, err)
+
+	checks := org.Diagnose()
+	last := checks[len(checks)-1]
+	assert.Equal(t, CheckWarn, last.Status)
+	assert.Contains(t, last.Detail, "--use-embedded-metadata")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "4.0 GiB", formatBytes(1<<32))
+}
@@ -0,0 +1,444 @@
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// EbookExtensions are the ebook formats Ebooks mode organizes, each file a book
+var EbookExtensions = map[string]bool{
+	".epub": true,
+	".mobi": true,
+	".azw":  true,
+	".azw3": true,
+	".fb2":  true,
+	".pdf":  true,
+}
+
+// IsEbookFile reports whether ext is one of the EbookExtensions
+func IsEbookFile(ext string) bool {
+	return EbookExtensions[strings.ToLower(ext)]
+}
+
+// EbookExtensionPolicy is the extension policy of Ebooks mode: ebook files are the
+// books and audio files stay where they are. The rules of overrides still win.
+func EbookExtensionPolicy(overrides ExtensionPolicy) ExtensionPolicy {
+	policy := make(ExtensionPolicy, len(EbookExtensions)+len(SupportedAudioExtensions)+len(overrides))
+	for ext := range SupportedAudioExtensions {
+		policy[ext] = ExtensionIgnore
+	}
+	for ext := range EbookExtensions {
+		policy[ext] = ExtensionOrganize
+	}
+	for ext, action := range overrides {
+		policy[ext] = action
+	}
+	return policy
+}
+
+// EbookMetadataProvider reads the metadata of a MOBI, AZW3, FB2, or PDF file. EPUB
+// files have their own EPUBMetadataProvider.
+type EbookMetadataProvider struct {
+	*UnifiedMetadataProvider
+}
+
+// NewEbookMetadataProvider creates a metadata provider for the ebook file at path
+func NewEbookMetadataProvider(path string) *EbookMetadataProvider {
+	return &EbookMetadataProvider{NewMetadataProvider(path, true)}
+}
+
+// newEbookMetadata starts the metadata of an ebook file of the given source type
+func newEbookMetadata(path, sourceType string) Metadata {
+	metadata := NewMetadata()
+	metadata.SourcePath = path
+	metadata.SourceType = sourceType
+	metadata.RawData = make(map[string]interface{})
+	return metadata
+}
+
+// setEbookFields fills the mapped fields of an ebook's metadata and its raw data
+func setEbookFields(metadata *Metadata, title string, authors []string) {
+	metadata.Title = strings.TrimSpace(title)
+	metadata.RawData["title"] = metadata.Title
+	for _, author := range authors {
+		if author = strings.TrimSpace(author); author != "" {
+			metadata.Authors = append(metadata.Authors, author)
+		}
+	}
+	metadata.RawData["authors"] = metadata.Authors
+}
+
+// MOBI EXTH record types read by extractMOBIMetadata
+const (
+	exthAuthor      = 100
+	exthPublisher   = 101
+	exthDescription = 103
+	exthISBN        = 104
+	exthSubject     = 105
+	exthPublished   = 106
+	exthASIN        = 113
+	exthTitle       = 503
+	exthLanguage    = 524
+)
+
+// extractMOBIMetadata reads the title, authors, and identifiers of a MOBI or AZW3
+// file from its MOBI header and EXTH records. AZW3 files share the MOBI container.
+func (p *UnifiedMetadataProvider) extractMOBIMetadata() (Metadata, error) {
+	file, err := os.Open(p.filePath)
+	if err != nil {
+		return NewMetadata(), fmt.Errorf("error opening MOBI file: %v", err)
+	}
+	defer file.Close()
+
+	var header [78]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		return NewMetadata(), fmt.Errorf("error reading MOBI header: %v", err)
+	}
+	if string(header[60:68]) != "BOOKMOBI" {
+		return NewMetadata(), fmt.Errorf("not a MOBI file: %s", p.filePath)
+	}
+	var firstRecord [4]byte
+	if _, err := io.ReadFull(file, firstRecord[:]); err != nil {
+		return NewMetadata(), fmt.Errorf("error reading MOBI record list: %v", err)
+	}
+
+	// Record 0 holds the PalmDOC header, the MOBI header, EXTH, and the full name
+	const maxRecordSize = 1 << 20
+	record := make([]byte, maxRecordSize)
+	n, err := file.ReadAt(record, int64(binary.BigEndian.Uint32(firstRecord[:])))
+	if err != nil && err != io.EOF {
+		return NewMetadata(), fmt.Errorf("error reading MOBI record: %v", err)
+	}
+	record = record[:n]
+	if len(record) < 132 || string(record[16:20]) != "MOBI" {
+		return NewMetadata(), fmt.Errorf("missing MOBI header in %s", p.filePath)
+	}
+
+	decode := func(b []byte) string { return string(b) }
+	if binary.BigEndian.Uint32(record[28:32]) == 1252 {
+		decode = func(b []byte) string {
+			text, _ := charmap.Windows1252.NewDecoder().Bytes(b)
+			return string(text)
+		}
+	}
+
+	metadata := newEbookMetadata(p.filePath, "mobi")
+	title := strings.ReplaceAll(strings.TrimRight(string(header[:32]), "\x00"), "_", " ")
+	nameOffset, nameLength := binary.BigEndian.Uint32(record[84:88]), binary.BigEndian.Uint32(record[88:92])
+	if end := uint64(nameOffset) + uint64(nameLength); nameLength > 0 && end <= uint64(len(record)) {
+		title = decode(record[nameOffset:end])
+	}
+
+	var authors []string
+	exthOffset := 16 + uint64(binary.BigEndian.Uint32(record[20:24]))
+	if binary.BigEndian.Uint32(record[128:132])&0x40 != 0 && exthOffset < uint64(len(record)) {
+		for _, entry := range readEXTHRecords(record[exthOffset:]) {
+			value := strings.TrimSpace(decode(entry.data))
+			switch entry.kind {
+			case exthTitle:
+				title = value
+			case exthAuthor:
+				authors = append(authors, value)
+			case exthPublisher:
+				metadata.RawData["publisher"] = value
+			case exthDescription:
+				metadata.RawData["description"] = value
+			case exthISBN:
+				metadata.RawData["isbn"] = value
+			case exthASIN:
+				metadata.RawData["asin"] = value
+			case exthPublished:
+				metadata.RawData["date"] = value
+			case exthLanguage:
+				metadata.RawData["language"] = value
+			case exthSubject:
+				subjects, _ := metadata.RawData["subjects"].([]string)
+				metadata.RawData["subjects"] = append(subjects, value)
+			}
+		}
+	}
+	setEbookFields(&metadata, title, authors)
+	return metadata, nil
+}
+
+// exthRecord is one typed value of a MOBI EXTH header
+type exthRecord struct {
+	kind uint32
+	data []byte
+}
+
+// readEXTHRecords reads the records of the EXTH header at the start of data, stopping
+// at the first one that runs past the end
+func readEXTHRecords(data []byte) []exthRecord {
+	if len(data) < 12 || string(data[:4]) != "EXTH" {
+		return nil
+	}
+	count := binary.BigEndian.Uint32(data[8:12])
+	var records []exthRecord
+	for offset := 12; count > 0 && offset+8 <= len(data); count-- {
+		kind := binary.BigEndian.Uint32(data[offset : offset+4])
+		length := int(binary.BigEndian.Uint32(data[offset+4 : offset+8]))
+		if length < 8 || offset+length > len(data) {
+			break
+		}
+		records = append(records, exthRecord{kind: kind, data: data[offset+8 : offset+length]})
+		offset += length
+	}
+	return records
+}
+
+// fb2Description is the part of a FictionBook document describing the book
+type fb2Description struct {
+	TitleInfo struct {
+		Authors []struct {
+			First    string `xml:"first-name"`
+			Middle   string `xml:"middle-name"`
+			Last     string `xml:"last-name"`
+			Nickname string `xml:"nickname"`
+		} `xml:"author"`
+		Title    string   `xml:"book-title"`
+		Genres   []string `xml:"genre"`
+		Language string   `xml:"lang"`
+		Sequence []struct {
+			Name   string `xml:"name,attr"`
+			Number string `xml:"number,attr"`
+		} `xml:"sequence"`
+	} `xml:"title-info"`
+	PublishInfo struct {
+		Publisher string `xml:"publisher"`
+		Year      string `xml:"year"`
+		ISBN      string `xml:"isbn"`
+	} `xml:"publish-info"`
+}
+
+// extractFB2Metadata reads the title, authors, and series of a FictionBook file from
+// its description, without reading the body and embedded images that follow it
+func (p *UnifiedMetadataProvider) extractFB2Metadata() (Metadata, error) {
+	file, err := os.Open(p.filePath)
+	if err != nil {
+		return NewMetadata(), fmt.Errorf("error opening FB2 file: %v", err)
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	decoder.Strict = false
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		encoding, err := htmlindex.Get(label)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.NewDecoder().Reader(input), nil
+	}
+
+	var description fb2Description
+	for found := false; !found; {
+		token, err := decoder.Token()
+		if err != nil {
+			return NewMetadata(), fmt.Errorf("no FB2 description in %s: %v", p.filePath, err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "description" {
+			if err := decoder.DecodeElement(&description, &start); err != nil {
+				return NewMetadata(), fmt.Errorf("error reading FB2 description: %v", err)
+			}
+			found = true
+		}
+	}
+
+	metadata := newEbookMetadata(p.filePath, "fb2")
+	info := description.TitleInfo
+	var authors []string
+	for _, author := range info.Authors {
+		name := strings.Join(strings.Fields(author.First+" "+author.Middle+" "+author.Last), " ")
+		if name == "" {
+			name = strings.TrimSpace(author.Nickname)
+		}
+		authors = append(authors, name)
+	}
+	setEbookFields(&metadata, info.Title, authors)
+
+	if len(info.Sequence) > 0 && strings.TrimSpace(info.Sequence[0].Name) != "" {
+		metadata.Series = []string{strings.TrimSpace(info.Sequence[0].Name)}
+		metadata.RawData["series"] = metadata.Series[0]
+		if index, err := strconv.ParseFloat(strings.TrimSpace(info.Sequence[0].Number), 64); err == nil {
+			metadata.RawData["series_index"] = index
+		}
+	}
+	if info.Language != "" {
+		metadata.RawData["language"] = strings.TrimSpace(info.Language)
+	}
+	if len(info.Genres) > 0 {
+		metadata.RawData["subjects"] = info.Genres
+	}
+	if publisher := strings.TrimSpace(description.PublishInfo.Publisher); publisher != "" {
+		metadata.RawData["publisher"] = publisher
+	}
+	if year := strings.TrimSpace(description.PublishInfo.Year); year != "" {
+		metadata.RawData["date"] = year
+	}
+	if isbn := strings.TrimSpace(description.PublishInfo.ISBN); isbn != "" {
+		metadata.RawData["isbn"] = isbn
+	}
+	return metadata, nil
+}
+
+// pdfScanSize bounds how much of each end of a large PDF is searched for its
+// document information, which sits near the end or, when linearized, the start
+const pdfScanSize = 4 << 20
+
+// pdfInfoKey finds an entry of a PDF document information dictionary, such as
+// "/Title (Dune)" or "/Author <FEFF...>"
+var pdfInfoKey = regexp.MustCompile(`/(Title|Author|Subject|Keywords)\s*(\(|<[0-9A-Fa-f\s]*>)`)
+
+// extractPDFMetadata reads the title and author of a PDF from its uncompressed
+// document information dictionary. Later entries win, as incremental updates
+// append a new dictionary. PDFs that keep it in compressed object streams yield an
+// error rather than a book filed under a guessed name.
+func (p *UnifiedMetadataProvider) extractPDFMetadata() (Metadata, error) {
+	data, err := readFileEnds(p.filePath, pdfScanSize)
+	if err != nil {
+		return NewMetadata(), fmt.Errorf("error reading PDF: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return NewMetadata(), fmt.Errorf("not a PDF file: %s", p.filePath)
+	}
+
+	values := make(map[string]string)
+	for _, match := range pdfInfoKey.FindAllSubmatchIndex(data, -1) {
+		key := string(data[match[2]:match[3]])
+		var value string
+		if data[match[4]] == '(' {
+			value = decodePDFText(readPDFLiteral(data[match[5]:]))
+		} else {
+			raw, err := hex.DecodeString(strings.Join(strings.Fields(string(data[match[4]+1:match[5]-1])), ""))
+			if err != nil {
+				continue
+			}
+			value = decodePDFText(raw)
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			values[key] = value
+		}
+	}
+	if values["Title"] == "" {
+		return NewMetadata(), fmt.Errorf("no document information in %s", p.filePath)
+	}
+
+	metadata := newEbookMetadata(p.filePath, "pdf")
+	var authors []string
+	if values["Author"] != "" {
+		authors = []string{values["Author"]}
+	}
+	setEbookFields(&metadata, values["Title"], authors)
+	if values["Subject"] != "" {
+		metadata.RawData["description"] = values["Subject"]
+	}
+	if values["Keywords"] != "" {
+		metadata.RawData["keywords"] = values["Keywords"]
+	}
+	return metadata, nil
+}
+
+// readFileEnds returns a file's content, or only its first and last limit bytes
+// when it is larger than twice that
+func readFileEnds(path string, limit int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() <= 2*limit {
+		return io.ReadAll(file)
+	}
+	data := make([]byte, 2*limit)
+	if _, err := file.ReadAt(data[:limit], 0); err != nil {
+		return nil, err
+	}
+	if _, err := file.ReadAt(data[limit:], info.Size()-limit); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readPDFLiteral reads a PDF literal string up to its closing parenthesis,
+// resolving escapes and balanced parentheses
+func readPDFLiteral(data []byte) []byte {
+	var value []byte
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\\' && i+1 < len(data):
+			i++
+			switch e := data[i]; e {
+			case 'n':
+				value = append(value, '\n')
+			case 'r':
+				value = append(value, '\r')
+			case 't':
+				value = append(value, '\t')
+			case 'b':
+				value = append(value, '\b')
+			case 'f':
+				value = append(value, '\f')
+			case '\r', '\n':
+				// A line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					octal := int(e - '0')
+					for j := 0; j < 2 && i+1 < len(data) && data[i+1] >= '0' && data[i+1] <= '7'; j++ {
+						i++
+						octal = octal*8 + int(data[i]-'0')
+					}
+					value = append(value, byte(octal))
+				} else {
+					value = append(value, e)
+				}
+			}
+		case c == '(':
+			depth++
+			value = append(value, c)
+		case c == ')':
+			if depth == 0 {
+				return value
+			}
+			depth--
+			value = append(value, c)
+		default:
+			value = append(value, c)
+		}
+	}
+	return value
+}
+
+// decodePDFText decodes a PDF text string, UTF-16 when it starts with a byte order
+// mark and otherwise PDFDocEncoding, read as Latin-1
+func decodePDFText(raw []byte) string {
+	if len(raw) >= 2 && raw[0] == 0xFE && raw[1] == 0xFF {
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	if len(raw) >= 3 && raw[0] == 0xEF && raw[1] == 0xBB && raw[2] == 0xBF {
+		return string(raw[3:])
+	}
+	text, _ := charmap.ISO8859_1.NewDecoder().Bytes(raw)
+	return string(text)
+}
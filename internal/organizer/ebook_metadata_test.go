@@ -0,0 +1,190 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildMOBI returns a minimal MOBI file with a full name and the given EXTH records
+func buildMOBI(fullName string, exth map[uint32]string) []byte {
+	const recordOffset = 78 + 8 + 2
+	const mobiHeaderLength = 232
+
+	var records []byte
+	for kind, value := range exth {
+		entry := make([]byte, 8, 8+len(value))
+		binary.BigEndian.PutUint32(entry[0:4], kind)
+		binary.BigEndian.PutUint32(entry[4:8], uint32(8+len(value)))
+		records = append(records, append(entry, value...)...)
+	}
+	exthBlock := make([]byte, 12, 12+len(records))
+	copy(exthBlock, "EXTH")
+	binary.BigEndian.PutUint32(exthBlock[4:8], uint32(12+len(records)))
+	binary.BigEndian.PutUint32(exthBlock[8:12], uint32(len(exth)))
+	exthBlock = append(exthBlock, records...)
+
+	record := make([]byte, 16+mobiHeaderLength)
+	copy(record[16:20], "MOBI")
+	binary.BigEndian.PutUint32(record[20:24], mobiHeaderLength)
+	binary.BigEndian.PutUint32(record[28:32], 65001)
+	binary.BigEndian.PutUint32(record[84:88], uint32(len(record)+len(exthBlock)))
+	binary.BigEndian.PutUint32(record[88:92], uint32(len(fullName)))
+	binary.BigEndian.PutUint32(record[128:132], 0x40)
+	record = append(append(record, exthBlock...), fullName...)
+
+	header := make([]byte, recordOffset)
+	copy(header, "Short_Name")
+	copy(header[60:68], "BOOKMOBI")
+	binary.BigEndian.PutUint16(header[76:78], 1)
+	binary.BigEndian.PutUint32(header[78:82], recordOffset)
+	return append(header, record...)
+}
+
+func TestExtractMOBIMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dune.azw3")
+	require.NoError(t, os.WriteFile(path, buildMOBI("Dune (Full Name)", map[uint32]string{
+		exthAuthor: "Frank Herbert",
+		exthTitle:  "Dune",
+		exthISBN:   "978-0-441-17271-9",
+		exthASIN:   "B00B7NPRY8",
+	}), 0o644))
+
+	metadata, err := NewEbookMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, "mobi", metadata.SourceType)
+	assert.Equal(t, "Dune", metadata.Title)
+	assert.Equal(t, []string{"Frank Herbert"}, metadata.Authors)
+
+	metadata.FillIdentifiers()
+	assert.Equal(t, "9780441172719", metadata.ISBN)
+	assert.Equal(t, "B00B7NPRY8", metadata.ASIN)
+
+	// Without an updated title EXTH record the full name is the title
+	require.NoError(t, os.WriteFile(path, buildMOBI("Dune Messiah", map[uint32]string{exthAuthor: "Frank Herbert"}), 0o644))
+	metadata, err = NewEbookMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, "Dune Messiah", metadata.Title)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a mobi file at all, just some text that is long enough to read a header from"), 0o644))
+	_, err = NewEbookMetadataProvider(path).GetMetadata()
+	assert.ErrorContains(t, err, "not a MOBI file")
+}
+
+func TestExtractFB2Metadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.fb2")
+	// windows-1251 encoded "Пикник на обочине" by Аркадий Стругацкий
+	title := []byte{0xCF, 0xE8, 0xEA, 0xED, 0xE8, 0xEA, 0x20, 0xED, 0xE0, 0x20, 0xEE, 0xE1, 0xEE, 0xF7, 0xE8, 0xED, 0xE5}
+	first := []byte{0xC0, 0xF0, 0xEA, 0xE0, 0xE4, 0xE8, 0xE9}
+	last := []byte{0xD1, 0xF2, 0xF0, 0xF3, 0xE3, 0xE0, 0xF6, 0xEA, 0xE8, 0xE9}
+	var doc []byte
+	doc = append(doc, `<?xml version="1.0" encoding="windows-1251"?>
+<FictionBook xmlns="http://www.gribuser.ru/xml/fictionbook/2.0"><description><title-info>
+<genre>sf</genre><author><first-name>`...)
+	doc = append(doc, first...)
+	doc = append(doc, `</first-name><last-name>`...)
+	doc = append(doc, last...)
+	doc = append(doc, `</last-name></author><book-title>`...)
+	doc = append(doc, title...)
+	doc = append(doc, `</book-title><lang>ru</lang><sequence name="Noon Universe" number="4"/></title-info>
+<publish-info><isbn>978-5-17-084659-4</isbn></publish-info></description><body><p>...</p></body></FictionBook>`...)
+	require.NoError(t, os.WriteFile(path, doc, 0o644))
+
+	metadata, err := NewEbookMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, "fb2", metadata.SourceType)
+	assert.Equal(t, "Пикник на обочине", metadata.Title)
+	assert.Equal(t, []string{"Аркадий Стругацкий"}, metadata.Authors)
+	assert.Equal(t, []string{"Noon Universe"}, metadata.Series)
+	assert.Equal(t, 4.0, metadata.RawData["series_index"])
+	assert.Equal(t, "978-5-17-084659-4", metadata.RawData["isbn"])
+}
+
+func TestExtractPDFMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.pdf")
+	pdf := "%PDF-1.4\n1 0 obj\n<< /Title (Old Title) >>\nendobj\n" +
+		"2 0 obj\n<< /Producer (x) /Title (The Left Hand of \\(Darkness\\)) /Author <FEFF0055007200730075006C0061> >>\nendobj\n" +
+		"trailer\n<< /Info 2 0 R >>\n%%EOF\n"
+	require.NoError(t, os.WriteFile(path, []byte(pdf), 0o644))
+
+	metadata, err := NewEbookMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", metadata.SourceType)
+	assert.Equal(t, "The Left Hand of (Darkness)", metadata.Title)
+	assert.Equal(t, []string{"Ursula"}, metadata.Authors)
+
+	require.NoError(t, os.WriteFile(path, []byte("%PDF-1.7\nstream compressed\n%%EOF\n"), 0o644))
+	_, err = NewEbookMetadataProvider(path).GetMetadata()
+	assert.ErrorContains(t, err, "no document information")
+}
+
+func TestEbookExtensionPolicy(t *testing.T) {
+	policy := EbookExtensionPolicy(ExtensionPolicy{".pdf": ExtensionIgnore})
+	assert.True(t, policy.IsOrganized(".MOBI"))
+	assert.True(t, policy.IsOrganized(".epub"))
+	assert.False(t, policy.IsAudio(".azw3"))
+	assert.False(t, policy.IsOrganized(".mp3"))
+	assert.False(t, policy.IsOrganized(".pdf"), "rules given with --extension win")
+	assert.Equal(t, []string{".azw", ".azw3", ".epub", ".fb2", ".mobi"}, policy.Organized())
+}
+
+func TestOrganizeEbooks(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	// A Calibre-style book folder with its cover and OPF
+	calibre := filepath.Join(baseDir, "Frank Herbert", "Dune (12)")
+	require.NoError(t, os.MkdirAll(calibre, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(calibre, "Dune - Frank Herbert.azw3"),
+		buildMOBI("Dune", map[uint32]string{exthAuthor: "Frank Herbert"}), 0o644))
+	for _, name := range []string{"cover.jpg", "metadata.opf"} {
+		require.NoError(t, os.WriteFile(filepath.Join(calibre, name), []byte(name), 0o644))
+	}
+
+	// A dump folder: the shared cover stays, the same-named one follows its book
+	dump := filepath.Join(baseDir, "downloads")
+	require.NoError(t, os.MkdirAll(dump, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dump, "messiah.mobi"),
+		buildMOBI("Dune Messiah", map[uint32]string{exthAuthor: "Frank Herbert"}), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dump, "messiah.jpg"), []byte("jpg"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dump, "children.mobi"),
+		buildMOBI("Children of Dune", map[uint32]string{exthAuthor: "Frank Herbert"}), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dump, "cover.jpg"), []byte("jpg"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dump, "audio.mp3"), []byte("mp3"), 0o644))
+
+	config := OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		Layout:       "author-title",
+		FieldMapping: DefaultFieldMapping(),
+		Ebooks:       true,
+	}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	dune := filepath.Join(outputDir, "Frank Herbert", "Dune")
+	assert.FileExists(t, filepath.Join(dune, "Dune - Frank Herbert.azw3"))
+	assert.FileExists(t, filepath.Join(dune, "cover.jpg"))
+	assert.FileExists(t, filepath.Join(dune, "metadata.opf"))
+
+	messiah := filepath.Join(outputDir, "Frank Herbert", "Dune Messiah")
+	assert.FileExists(t, filepath.Join(messiah, "messiah.mobi"))
+	assert.FileExists(t, filepath.Join(messiah, "messiah.jpg"))
+	assert.FileExists(t, filepath.Join(outputDir, "Frank Herbert", "Children of Dune", "children.mobi"))
+	assert.FileExists(t, filepath.Join(dump, "cover.jpg"), "a cover shared by several books stays")
+	assert.FileExists(t, filepath.Join(dump, "audio.mp3"), "audio is left alone in ebooks mode")
+
+	config.Undo = true
+	undo, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, undo.Execute())
+	assert.FileExists(t, filepath.Join(calibre, "cover.jpg"))
+	assert.FileExists(t, filepath.Join(dump, "messiah.jpg"))
+}
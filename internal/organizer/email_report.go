@@ -0,0 +1,255 @@
+package organizer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// When a run report is emailed
+const (
+	EmailOnAlways  = "always"  // After every run
+	EmailOnFailure = "failure" // Only after runs that failed or completed with errors
+)
+
+// EmailConfig holds the SMTP server and recipients of emailed run reports. Credentials
+// come from the config file rather than flags, so they stay out of process listings.
+type EmailConfig struct {
+	Host     string
+	Port     int // 587 uses STARTTLS, 465 implicit TLS; defaults to 587
+	Username string
+	Password string
+	From     string
+	To       []string
+	On       string // EmailOnAlways or EmailOnFailure
+}
+
+// Validate reports missing or invalid settings
+func (c EmailConfig) Validate() error {
+	switch {
+	case c.On != EmailOnAlways && c.On != EmailOnFailure:
+		return fmt.Errorf("invalid email-summary %q: must be %s or %s", c.On, EmailOnAlways, EmailOnFailure)
+	case c.Host == "":
+		return fmt.Errorf("email.host is required to email run reports")
+	case c.From == "":
+		return fmt.Errorf("email.from is required to email run reports")
+	case len(c.To) == 0:
+		return fmt.Errorf("email.to needs at least one recipient")
+	}
+	return nil
+}
+
+// ShouldSend reports whether a run with report's status is emailed
+func (c EmailConfig) ShouldSend(report RunReport) bool {
+	if c.On == EmailOnAlways {
+		return true
+	}
+	return report.Status == RunStatusFatal || report.Status == RunStatusCompletedWithErrors
+}
+
+// sendMail delivers a message; tests replace it to capture messages
+var sendMail = deliverMail
+
+// SendReportEmail emails the summary and failure list of a run, with the HTML report
+// as the rich version of the message
+func SendReportEmail(config EmailConfig, report RunReport, context HTMLReportContext) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if context.Generated.IsZero() {
+		context.Generated = time.Now()
+	}
+	message, err := reportEmail(config, report, context)
+	if err != nil {
+		return err
+	}
+	if err := sendMail(config, message); err != nil {
+		return fmt.Errorf("error sending report email: %w", err)
+	}
+	return nil
+}
+
+// reportEmail builds a multipart/alternative message with a plain text summary and
+// the HTML report
+func reportEmail(config EmailConfig, report RunReport, context HTMLReportContext) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	if err := writeQuotedPart(parts, "text/plain; charset=utf-8", []byte(reportText(report, context))); err != nil {
+		return nil, err
+	}
+	var html bytes.Buffer
+	if err := RenderHTMLReport(&html, report, context); err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPart(parts, "text/html; charset=utf-8", html.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", config.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(config.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", reportSubject(report))
+	fmt.Fprintf(&message, "Date: %s\r\n", context.Generated.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+func writeQuotedPart(parts *multipart.Writer, contentType string, content []byte) error {
+	part, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write(content); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// reportSubject names the outcome so failures stand out in an inbox
+func reportSubject(report RunReport) string {
+	switch report.Status {
+	case RunStatusFatal:
+		return "Audiobook Organizer: run failed"
+	case RunStatusCompletedWithErrors:
+		return fmt.Sprintf("Audiobook Organizer: %d moves, %d errors", len(report.Moves), len(report.Errors))
+	case RunStatusNothingToDo:
+		return "Audiobook Organizer: nothing to do"
+	}
+	if report.DryRun {
+		return fmt.Sprintf("Audiobook Organizer: %d moves planned", len(report.Moves))
+	}
+	return fmt.Sprintf("Audiobook Organizer: %d moves", len(report.Moves))
+}
+
+// reportText is the plain text summary of a run, without emoji
+func reportText(report RunReport, context HTMLReportContext) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Status: %s\n", report.Status)
+	if report.DryRun {
+		b.WriteString("Dry run: no files were moved\n")
+	}
+	if context.InputDir != "" {
+		fmt.Fprintf(&b, "Input: %s\n", context.InputDir)
+	}
+	if context.OutputDir != "" {
+		fmt.Fprintf(&b, "Output: %s\n", context.OutputDir)
+	}
+	if report.Error != "" {
+		fmt.Fprintf(&b, "\nError: %s\n", StripDecorations(report.Error))
+	}
+
+	fmt.Fprintf(&b, "\nBooks found: %d\n", report.MetadataFound)
+	if report.MetadataSources.Total() > 0 {
+		fmt.Fprintf(&b, "Metadata sources: %s\n", report.MetadataSources)
+	}
+	fmt.Fprintf(&b, "Moves: %d\n", len(report.Moves))
+	fmt.Fprintf(&b, "Errors: %d\n", len(report.Errors))
+	if len(report.Quarantined) > 0 {
+		fmt.Fprintf(&b, "Quarantined for unsafe path metadata: %d\n", len(report.Quarantined))
+	}
+	if len(report.LowConfidence) > 0 {
+		fmt.Fprintf(&b, "Held back for low metadata confidence: %d\n", len(report.LowConfidence))
+	}
+	if len(report.Deferred) > 0 {
+		fmt.Fprintf(&b, "Deferred while still being written: %d\n", len(report.Deferred))
+	}
+	if len(report.MetadataMissing) > 0 {
+		fmt.Fprintf(&b, "Directories without metadata: %d\n", len(report.MetadataMissing))
+	}
+
+	if len(report.Errors) > 0 {
+		b.WriteString("\nFailures:\n")
+		for _, err := range report.Errors {
+			fmt.Fprintf(&b, "  - %s\n", strings.TrimSpace(StripDecorations(err)))
+		}
+	}
+	if len(report.Quarantined) > 0 {
+		b.WriteString("\nQuarantined:\n")
+		for _, held := range report.Quarantined {
+			fmt.Fprintf(&b, "  - %s (%s %q %s)\n", held.Path, held.Field, held.Value, held.Reason)
+		}
+	}
+	if len(report.LowConfidence) > 0 {
+		b.WriteString("\nHeld back:\n")
+		for _, held := range report.LowConfidence {
+			fmt.Fprintf(&b, "  - %s (%.2f: %s)\n", held.Path, held.Score, strings.Join(held.Reasons, ", "))
+		}
+	}
+	if len(context.UndoCommands) > 0 {
+		b.WriteString("\nTo undo this run:\n")
+		for _, command := range context.UndoCommands {
+			fmt.Fprintf(&b, "  %s\n", command)
+		}
+	}
+	return b.String()
+}
+
+// deliverMail sends message through the configured server, with implicit TLS on
+// port 465 and STARTTLS when the server offers it on other ports
+func deliverMail(config EmailConfig, message []byte) error {
+	port := config.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	if port != 465 {
+		return smtp.SendMail(addr, auth, config.From, config.To, message)
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: config.Host})
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(config.From); err != nil {
+		return err
+	}
+	for _, to := range config.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
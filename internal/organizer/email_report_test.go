@@ -0,0 +1,104 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEmailConfig(on string) EmailConfig {
+	return EmailConfig{
+		Host: "smtp.example.com",
+		From: "nas@example.com",
+		To:   []string{"me@example.com"},
+		On:   on,
+	}
+}
+
+func TestEmailConfigValidate(t *testing.T) {
+	assert.NoError(t, testEmailConfig(EmailOnFailure).Validate())
+
+	invalid := testEmailConfig("sometimes")
+	assert.ErrorContains(t, invalid.Validate(), "invalid email-summary")
+
+	noRecipients := testEmailConfig(EmailOnAlways)
+	noRecipients.To = nil
+	assert.ErrorContains(t, noRecipients.Validate(), "email.to")
+}
+
+func TestEmailConfigShouldSend(t *testing.T) {
+	ok := NewRunReport(Summary{Moves: []MoveSummary{{From: "a", To: "b"}}}, false, nil)
+	withErrors := NewRunReport(Summary{Errors: []string{"boom"}}, false, nil)
+	fatal := NewRunReport(Summary{}, false, errors.New("no input"))
+
+	failureOnly := testEmailConfig(EmailOnFailure)
+	assert.False(t, failureOnly.ShouldSend(ok))
+	assert.True(t, failureOnly.ShouldSend(withErrors))
+	assert.True(t, failureOnly.ShouldSend(fatal))
+	assert.True(t, testEmailConfig(EmailOnAlways).ShouldSend(ok))
+}
+
+func TestSendReportEmail(t *testing.T) {
+	var sent []byte
+	previous := sendMail
+	sendMail = func(config EmailConfig, message []byte) error {
+		sent = message
+		return nil
+	}
+	defer func() { sendMail = previous }()
+
+	report := NewRunReport(Summary{
+		Moves:  []MoveSummary{{From: "/in/a", To: "/out/A/a"}},
+		Errors: []string{"❌ Error moving /in/b: permission denied"},
+	}, false, nil)
+	context := HTMLReportContext{InputDir: "/in", OutputDir: "/out", UndoCommands: []string{"audiobook-organizer --input=/in --undo"}}
+	require.NoError(t, SendReportEmail(testEmailConfig(EmailOnFailure), report, context))
+
+	message, err := mail.ReadMessage(strings.NewReader(string(sent)))
+	require.NoError(t, err)
+	assert.Equal(t, "Audiobook Organizer: 1 moves, 1 errors", message.Header.Get("Subject"))
+	assert.Equal(t, "me@example.com", message.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	var types []string
+	var text string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		types = append(types, part.Header.Get("Content-Type"))
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain") {
+			text = string(body)
+		}
+	}
+	assert.Equal(t, []string{"text/plain; charset=utf-8", "text/html; charset=utf-8"}, types)
+	assert.Contains(t, text, "Failures:\r\n  - Error moving /in/b: permission denied")
+	assert.Contains(t, text, "audiobook-organizer --input=/in --undo")
+}
+
+func TestSendReportEmailReportsDeliveryErrors(t *testing.T) {
+	previous := sendMail
+	sendMail = func(EmailConfig, []byte) error { return errors.New("connection refused") }
+	defer func() { sendMail = previous }()
+
+	err := SendReportEmail(testEmailConfig(EmailOnAlways), NewRunReport(Summary{}, false, nil), HTMLReportContext{})
+	assert.ErrorContains(t, err, "connection refused")
+}
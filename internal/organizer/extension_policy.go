@@ -0,0 +1,175 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExtensionAction decides how files with one extension are handled
+type ExtensionAction string
+
+const (
+	// ExtensionOrganize marks a book file: read for metadata, numbered with track
+	// prefixes, and a book of its own in flat mode
+	ExtensionOrganize ExtensionAction = "organize"
+	// ExtensionCompanion marks a sidecar renamed with the audio file sharing its basename
+	ExtensionCompanion ExtensionAction = "companion"
+	// ExtensionIgnore marks files that are never books and stay in the source directory
+	ExtensionIgnore ExtensionAction = "ignore"
+	// ExtensionDelete marks files removed from a book directory once the rest of the
+	// book has moved, through the trash when one is configured
+	ExtensionDelete ExtensionAction = "delete"
+)
+
+// ExtensionPolicy maps lower-case extensions such as ".mp4" to how their files are
+// handled, overriding the built-in handling of SupportedAudioExtensions, EPUB, and
+// CompanionExtensions. A nil policy is the built-in handling.
+type ExtensionPolicy map[string]ExtensionAction
+
+// ParseExtensionActions parses --extension entries of the form ".mp4=organize". The
+// leading dot is optional and extensions are matched ignoring case.
+func ParseExtensionActions(entries []string) (ExtensionPolicy, error) {
+	actions := make(ExtensionPolicy)
+	for _, entry := range entries {
+		ext, action, ok := strings.Cut(entry, "=")
+		ext = normalizeExtension(ext)
+		parsed := ExtensionAction(strings.ToLower(strings.TrimSpace(action)))
+		if !ok || ext == "." {
+			return nil, fmt.Errorf("invalid extension rule %q (use \".ext=action\")", entry)
+		}
+		switch parsed {
+		case ExtensionOrganize, ExtensionCompanion, ExtensionIgnore, ExtensionDelete:
+		default:
+			return nil, fmt.Errorf("invalid action %q for %s (use organize, companion, ignore, or delete)", action, ext)
+		}
+		actions[ext] = parsed
+	}
+	return actions, nil
+}
+
+// ActionFor returns how files with ext are handled, or "" for files that simply
+// move with their book
+func (p ExtensionPolicy) ActionFor(ext string) ExtensionAction {
+	ext = strings.ToLower(ext)
+	if action, ok := p[ext]; ok {
+		return action
+	}
+
+	switch {
+	case SupportedAudioExtensions[ext] || ext == ".epub":
+		return ExtensionOrganize
+	case CompanionExtensions[ext]:
+		return ExtensionCompanion
+	}
+	return ""
+}
+
+// IsAudio reports whether files with ext are organized as audio files, which is
+// every organized extension but the EbookExtensions
+func (p ExtensionPolicy) IsAudio(ext string) bool {
+	return p.ActionFor(ext) == ExtensionOrganize && !IsEbookFile(ext)
+}
+
+// IsOrganized reports whether files with ext are book files, the audio files and
+// EPUB, or the ebooks of EbookExtensionPolicy
+func (p ExtensionPolicy) IsOrganized(ext string) bool {
+	return p.ActionFor(ext) == ExtensionOrganize
+}
+
+// IsCompanion reports whether files with ext are sidecars that follow their audio file
+func (p ExtensionPolicy) IsCompanion(ext string) bool {
+	return p.ActionFor(ext) == ExtensionCompanion
+}
+
+// Organized lists every extension handled as a book file, sorted
+func (p ExtensionPolicy) Organized() []string {
+	candidates := make(map[string]bool)
+	for ext := range EbookExtensions {
+		candidates[ext] = true
+	}
+	for ext := range SupportedAudioExtensions {
+		candidates[ext] = true
+	}
+	for ext := range p {
+		candidates[ext] = true
+	}
+
+	var exts []string
+	for ext := range candidates {
+		if p.IsOrganized(ext) {
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// skips reports whether a file of a book directory stays behind instead of moving
+// with the book because its extension is set to ignore or delete
+func (p ExtensionPolicy) skips(name string) bool {
+	action := p.ActionFor(filepath.Ext(name))
+	return action == ExtensionIgnore || action == ExtensionDelete
+}
+
+// findAudioFile returns the first audio file in dirPath
+func (p ExtensionPolicy) findAudioFile(dirPath string) (string, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if p.IsAudio(filepath.Ext(entry.Name())) {
+			return filepath.Join(dirPath, entry.Name()), nil
+		}
+	}
+
+	return "", fmt.Errorf("no supported audio files found in directory")
+}
+
+// deleteByExtension removes the files of a book directory whose extension is set to
+// delete, once the rest of the book has moved
+func (o *Organizer) deleteByExtension(sourcePath string, names []string) {
+	for _, name := range names {
+		if o.config.Extensions.ActionFor(filepath.Ext(name)) != ExtensionDelete {
+			continue
+		}
+		path := filepath.Join(sourcePath, name)
+		if o.config.DryRun {
+			PrintYellow("🗑️  Would delete %s", path)
+			continue
+		}
+		if err := o.discard(path); err != nil {
+			o.recordError("❌ Error deleting %s: %v", path, err)
+			continue
+		}
+		if o.config.Verbose {
+			PrintYellow("🗑️  Deleted %s", path)
+		}
+	}
+}
+
+// fileEntryNames returns the names of the files among entries
+func fileEntryNames(entries []os.DirEntry) []string {
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
@@ -0,0 +1,80 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// extensionPolicy parses entries as --extension would
+func extensionPolicy(t *testing.T, entries ...string) ExtensionPolicy {
+	t.Helper()
+	policy, err := ParseExtensionActions(entries)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestParseExtensionActions(t *testing.T) {
+	actions, err := ParseExtensionActions([]string{".MP4=organize", "pdf = Companion"})
+	require.NoError(t, err)
+	assert.Equal(t, ExtensionPolicy{".mp4": ExtensionOrganize, ".pdf": ExtensionCompanion}, actions)
+
+	for _, invalid := range []string{".mp4", "=organize", ".mp4=move"} {
+		_, err := ParseExtensionActions([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestExtensionActions(t *testing.T) {
+	var builtIn ExtensionPolicy
+	assert.True(t, builtIn.IsAudio(".M4A"))
+	assert.False(t, builtIn.IsAudio(".mp4"))
+	assert.True(t, builtIn.IsOrganized(".epub"))
+	assert.False(t, builtIn.IsAudio(".epub"))
+	assert.True(t, builtIn.IsCompanion(".cue"))
+	assert.Equal(t, ExtensionAction(""), builtIn.ActionFor(".txt"))
+
+	policy := extensionPolicy(t, ".mp4=organize", ".m4a=ignore", ".pdf=companion")
+	assert.True(t, policy.IsAudio(".mp4"))
+	assert.True(t, policy.IsOrganized(".MP4"))
+	assert.False(t, policy.IsAudio(".m4a"))
+	assert.False(t, policy.IsOrganized(".m4a"))
+	assert.True(t, policy.IsCompanion(".pdf"))
+	assert.Contains(t, policy.Organized(), ".mp4")
+	assert.NotContains(t, policy.Organized(), ".m4a")
+
+	assert.False(t, IsSupportedAudioFile(".mp4"), "a policy never changes the package defaults")
+	assert.True(t, IsSupportedAudioFile(".m4a"))
+}
+
+func TestOrganizeAppliesExtensionActions(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	source := filepath.Join(base, "Lectures")
+	writeDisc(t, source, `{"title":"Lectures","authors":["Richard Feynman"]}`, "lecture.mp4", "theme.m4a", "download.url")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:    base,
+		OutputDir:  out,
+		Extensions: extensionPolicy(t, ".mp4=organize", ".url=delete", ".m4a=ignore"),
+	})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	target := filepath.Join(out, "Richard Feynman", "Lectures")
+	assert.FileExists(t, filepath.Join(target, "lecture.mp4"))
+	assert.NoFileExists(t, filepath.Join(target, "theme.m4a"))
+	assert.FileExists(t, filepath.Join(source, "theme.m4a"), "ignored files stay in the source")
+	assert.NoFileExists(t, filepath.Join(source, "download.url"))
+	assert.NoFileExists(t, filepath.Join(target, "download.url"))
+
+	_, err = os.Stat(filepath.Join(target, MetadataFileName))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,210 @@
+package organizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultFieldDetectionTopValues is the number of most common values reported per field.
+const DefaultFieldDetectionTopValues = 3
+
+// fieldDetectionCandidates lists raw fields considered for each mapping target, in
+// preference order. Ties in coverage are broken by this order.
+var fieldDetectionCandidates = map[string][]string{
+	"title":   {"title", "album", "track_title"},
+	"series":  {"series", "SERIES", MovementNameField, "album", "content_group"},
+	"authors": {"authors", "artist", "album_artist", "composer", "narrator"},
+	"track":   {"track", "track_number", "trck", "trk"},
+	"disc":    {"disc", "discnumber", "disk", "tpos"},
+}
+
+// FieldDetectionReport summarizes which raw metadata fields are populated across a sample.
+type FieldDetectionReport struct {
+	FilesSampled     int               `json:"files_sampled"`
+	Errors           int               `json:"errors"`
+	Fields           []FieldFrequency  `json:"fields"`
+	Suggestions      []FieldSuggestion `json:"suggestions"`
+	SuggestedMapping FieldMapping      `json:"suggested_mapping"`
+}
+
+// FieldFrequency records how often a raw field is populated and its most common values.
+type FieldFrequency struct {
+	Field     string       `json:"field"`
+	Populated int          `json:"populated"`
+	Percent   float64      `json:"percent"`
+	TopValues []ValueCount `json:"top_values"`
+}
+
+// ValueCount is one distinct value and the number of files it appeared in.
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// FieldSuggestion explains the raw field chosen for one mapping target.
+type FieldSuggestion struct {
+	Target  string  `json:"target"`
+	Field   string  `json:"field"`
+	Percent float64 `json:"percent"`
+	Message string  `json:"message"`
+}
+
+// DetectFieldMapping builds a frequency table of populated raw fields from inspected
+// files and suggests a FieldMapping based on field coverage.
+func DetectFieldMapping(output MetadataInspectionOutput, topValues int) FieldDetectionReport {
+	if topValues <= 0 {
+		topValues = DefaultFieldDetectionTopValues
+	}
+
+	report := FieldDetectionReport{
+		Fields:      []FieldFrequency{},
+		Suggestions: []FieldSuggestion{},
+	}
+	valueCounts := make(map[string]map[string]int)
+	populated := make(map[string]int)
+
+	for _, file := range output.Files {
+		if file.Error != "" {
+			report.Errors++
+			continue
+		}
+		report.FilesSampled++
+		for key, value := range file.RawData {
+			if strings.HasPrefix(key, "_") {
+				continue
+			}
+			str, ok := rawValueString(value)
+			if !ok {
+				continue
+			}
+			populated[key]++
+			if valueCounts[key] == nil {
+				valueCounts[key] = make(map[string]int)
+			}
+			valueCounts[key][str]++
+		}
+	}
+
+	for field, count := range populated {
+		report.Fields = append(report.Fields, FieldFrequency{
+			Field:     field,
+			Populated: count,
+			Percent:   percentOf(count, report.FilesSampled),
+			TopValues: topValueCounts(valueCounts[field], topValues),
+		})
+	}
+	sort.Slice(report.Fields, func(i, j int) bool {
+		if report.Fields[i].Populated != report.Fields[j].Populated {
+			return report.Fields[i].Populated > report.Fields[j].Populated
+		}
+		return report.Fields[i].Field < report.Fields[j].Field
+	})
+	fieldIndex := make(map[string]FieldFrequency, len(report.Fields))
+	for _, freq := range report.Fields {
+		fieldIndex[freq.Field] = freq
+	}
+
+	for _, target := range []string{"title", "series", "authors", "track", "disc"} {
+		// The series field must differ from the chosen title field.
+		suggestion, ok := suggestField(fieldIndex, target, report.SuggestedMapping.TitleField)
+		if !ok {
+			continue
+		}
+		report.Suggestions = append(report.Suggestions, suggestion)
+		switch target {
+		case "title":
+			report.SuggestedMapping.TitleField = suggestion.Field
+		case "series":
+			report.SuggestedMapping.SeriesField = suggestion.Field
+		case "authors":
+			report.SuggestedMapping.AuthorFields = []string{suggestion.Field}
+		case "track":
+			report.SuggestedMapping.TrackField = suggestion.Field
+		case "disc":
+			report.SuggestedMapping.DiscField = suggestion.Field
+		}
+	}
+
+	return report
+}
+
+// suggestField picks the best-covered candidate field for a mapping target.
+func suggestField(
+	fieldIndex map[string]FieldFrequency,
+	target, titleField string,
+) (FieldSuggestion, bool) {
+	var best *FieldFrequency
+	for _, candidate := range fieldDetectionCandidates[target] {
+		freq, ok := fieldIndex[candidate]
+		if !ok || (target == "series" && candidate == titleField) {
+			continue
+		}
+		if best == nil || freq.Populated > best.Populated {
+			best = &freq
+		}
+	}
+	if best == nil {
+		return FieldSuggestion{}, false
+	}
+
+	return FieldSuggestion{
+		Target:  target,
+		Field:   best.Field,
+		Percent: best.Percent,
+		Message: fmt.Sprintf("%s found in '%s' for %.0f%% of files", target, best.Field, best.Percent),
+	}, true
+}
+
+// rawValueString converts a populated raw metadata value to a display string.
+func rawValueString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		v = strings.TrimSpace(v)
+		return v, v != ""
+	case []string:
+		joined := strings.TrimSpace(strings.Join(v, ", "))
+		return joined, joined != ""
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := rawValueString(item); ok {
+				parts = append(parts, str)
+			}
+		}
+		return strings.Join(parts, ", "), len(parts) > 0
+	case int:
+		return fmt.Sprintf("%d", v), v != 0
+	case float64:
+		return fmt.Sprintf("%g", v), v != 0
+	default:
+		str := strings.TrimSpace(fmt.Sprintf("%v", v))
+		return str, str != "" && str != "0"
+	}
+}
+
+func topValueCounts(counts map[string]int, limit int) []ValueCount {
+	values := make([]ValueCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+func percentOf(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
@@ -0,0 +1,54 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFieldMapping(t *testing.T) {
+	files := []MetadataInspectionFile{
+		{RawData: map[string]interface{}{"title": "Ch 1", "album": "Dune", "artist": "Frank Herbert", "track": 1}},
+		{RawData: map[string]interface{}{"title": "Ch 2", "album": "Dune", "artist": "Frank Herbert", "track": 2}},
+		{RawData: map[string]interface{}{"title": "Ch 3", "album": "Dune", "artist": "", "track": 0}},
+		{RawData: map[string]interface{}{"title": "Ch 1", "album": "Emma", "artist": "Jane Austen", "track": 1, "_embedded_source": "x"}},
+		{Error: "failed to extract metadata"},
+	}
+
+	report := DetectFieldMapping(MetadataInspectionOutput{Files: files}, 2)
+
+	assert.Equal(t, 4, report.FilesSampled)
+	assert.Equal(t, 1, report.Errors)
+
+	fields := map[string]FieldFrequency{}
+	for _, field := range report.Fields {
+		fields[field.Field] = field
+	}
+	assert.NotContains(t, fields, "_embedded_source")
+	assert.Equal(t, 4, fields["album"].Populated)
+	assert.Equal(t, 3, fields["artist"].Populated)
+	assert.Equal(t, 75.0, fields["artist"].Percent)
+	assert.Equal(t, []ValueCount{{Value: "Dune", Count: 3}, {Value: "Emma", Count: 1}}, fields["album"].TopValues)
+
+	assert.Equal(t, "title", report.SuggestedMapping.TitleField)
+	assert.Equal(t, "album", report.SuggestedMapping.SeriesField)
+	assert.Equal(t, []string{"artist"}, report.SuggestedMapping.AuthorFields)
+	assert.Equal(t, "track", report.SuggestedMapping.TrackField)
+
+	var messages []string
+	for _, suggestion := range report.Suggestions {
+		messages = append(messages, suggestion.Message)
+	}
+	assert.Contains(t, messages, "series found in 'album' for 100% of files")
+}
+
+func TestDetectFieldMappingEmpty(t *testing.T) {
+	report := DetectFieldMapping(MetadataInspectionOutput{}, 0)
+
+	assert.Equal(t, 0, report.FilesSampled)
+	assert.Empty(t, report.Fields)
+	assert.Empty(t, report.Suggestions)
+	assert.True(t, report.SuggestedMapping.IsEmpty())
+}
@@ -0,0 +1,131 @@
+package organizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fat32MaxFileSize is the largest file FAT32 can hold, one byte short of 4 GiB
+const fat32MaxFileSize = 1<<32 - 1
+
+// restrictedFS describes an output file system with Windows naming rules, such as
+// the FAT32 and exFAT cards and USB sticks many audiobook players read from
+type restrictedFS struct {
+	Name        string
+	MaxFileSize int64 // 0 means no limit below what the disk holds
+}
+
+// restrictedFileSystems maps the type names reported by the OS to their limits
+var restrictedFileSystems = map[string]restrictedFS{
+	"vfat":  {Name: "FAT32", MaxFileSize: fat32MaxFileSize},
+	"msdos": {Name: "FAT32", MaxFileSize: fat32MaxFileSize},
+	"fat":   {Name: "FAT32", MaxFileSize: fat32MaxFileSize},
+	"fat32": {Name: "FAT32", MaxFileSize: fat32MaxFileSize},
+	"exfat": {Name: "exFAT"},
+}
+
+// lookupRestrictedFS returns the limits of a file system type, or nil when it has
+// none worth enforcing
+func lookupRestrictedFS(fsType string) *restrictedFS {
+	if limits, ok := restrictedFileSystems[strings.ToLower(fsType)]; ok {
+		return &limits
+	}
+	return nil
+}
+
+// detectRestrictedFS reports the limits of the file system holding dir. Directories
+// that don't exist yet are looked up through their nearest existing parent.
+func detectRestrictedFS(dir string) *restrictedFS {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+	return lookupRestrictedFS(fileSystemType(dir))
+}
+
+// mountFSType returns the type of the mount containing path, given a table in the
+// /proc/self/mounts format. The longest matching mount point wins.
+func mountFSType(mounts io.Reader, path string) string {
+	var best, bestType string
+	scanner := bufio.NewScanner(mounts)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mountPoint := unescapeMountField(fields[1])
+		if !isUnderMount(path, mountPoint) || len(mountPoint) < len(best) {
+			continue
+		}
+		best, bestType = mountPoint, fields[2]
+	}
+	return bestType
+}
+
+// isUnderMount reports whether path is mountPoint or lies below it
+func isUnderMount(path, mountPoint string) bool {
+	if mountPoint == "/" || path == mountPoint {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(mountPoint, "/")+"/")
+}
+
+// unescapeMountField decodes the octal escapes (\040 for a space) the kernel uses
+// in mount table fields
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if n, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// checkFileSizes warns about files too large for a restricted output file system,
+// or refuses them with Strict so a book is never left half copied
+func (o *Organizer) checkFileSizes(moves []FilePair) error {
+	if o.outputFS == nil || o.outputFS.MaxFileSize == 0 {
+		return nil
+	}
+	for _, move := range moves {
+		info, err := os.Stat(move.From)
+		if err != nil || info.Size() <= o.outputFS.MaxFileSize {
+			continue
+		}
+		if o.config.Strict {
+			return fmt.Errorf(
+				"%s is %d bytes, larger than %s allows (%d bytes)",
+				move.From,
+				info.Size(),
+				o.outputFS.Name,
+				o.outputFS.MaxFileSize,
+			)
+		}
+		PrintYellow(
+			"⚠️  Warning: %s is larger than %s allows and will fail to copy",
+			move.From,
+			o.outputFS.Name,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMounts = `/dev/sda1 / ext4 rw,relatime 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sdb1 /media/user/PLAYER vfat rw,nosuid,nodev 0 0
+/dev/sdc1 /media/user/My\040Card exfat rw,nosuid,nodev 0 0
+`
+
+func TestMountFSType(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/user/books", "ext4"},
+		{"/media/user/PLAYER", "vfat"},
+		{"/media/user/PLAYER/Audiobooks", "vfat"},
+		{"/media/user/PLAYER2", "ext4"},
+		{"/media/user/My Card/Books", "exfat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, mountFSType(strings.NewReader(testMounts), tt.path))
+		})
+	}
+}
+
+func TestLookupRestrictedFS(t *testing.T) {
+	fat := lookupRestrictedFS("FAT32")
+	require.NotNil(t, fat)
+	assert.Equal(t, "FAT32", fat.Name)
+	assert.EqualValues(t, fat32MaxFileSize, fat.MaxFileSize)
+
+	exfat := lookupRestrictedFS("exfat")
+	require.NotNil(t, exfat)
+	assert.Zero(t, exfat.MaxFileSize)
+
+	assert.Nil(t, lookupRestrictedFS("ext4"))
+	assert.Nil(t, lookupRestrictedFS(""))
+}
+
+func TestSanitizePathOnRestrictedOutput(t *testing.T) {
+	org := &Organizer{outputFS: lookupRestrictedFS("exfat")}
+	assert.NotContains(t, org.SanitizePath(`AC\DC`), `\`)
+}
+
+func TestCheckFileSizes(t *testing.T) {
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.m4b")
+	small := filepath.Join(dir, "small.m4b")
+	require.NoError(t, os.WriteFile(big, []byte("0123456789abcdef"), 0o644))
+	require.NoError(t, os.WriteFile(small, []byte("0123"), 0o644))
+	moves := []FilePair{{From: small}, {From: big}}
+	limits := &restrictedFS{Name: "FAT32", MaxFileSize: 8}
+
+	t.Run("warns by default", func(t *testing.T) {
+		org := &Organizer{outputFS: limits}
+		assert.NoError(t, org.checkFileSizes(moves))
+	})
+
+	t.Run("strict refuses", func(t *testing.T) {
+		org := &Organizer{outputFS: limits, config: OrganizerConfig{Strict: true}}
+		err := org.checkFileSizes(moves)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "big.m4b")
+	})
+
+	t.Run("unrestricted output", func(t *testing.T) {
+		org := &Organizer{config: OrganizerConfig{Strict: true}}
+		assert.NoError(t, org.checkFileSizes(moves))
+	})
+}
@@ -0,0 +1,14 @@
+//go:build darwin
+
+package organizer
+
+import "golang.org/x/sys/unix"
+
+// fileSystemType returns the name statfs reports for the file system holding dir
+func fileSystemType(dir string) string {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return ""
+	}
+	return unix.ByteSliceToString(stat.Fstypename[:])
+}
@@ -0,0 +1,15 @@
+//go:build linux
+
+package organizer
+
+import "os"
+
+// fileSystemType returns the kernel's name for the file system holding dir
+func fileSystemType(dir string) string {
+	mounts, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return ""
+	}
+	defer mounts.Close()
+	return mountFSType(mounts, dir)
+}
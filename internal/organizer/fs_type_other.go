@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package organizer
+
+// fileSystemType returns "", so no output file system is treated as restricted
+func fileSystemType(string) string {
+	return ""
+}
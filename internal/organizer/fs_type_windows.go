@@ -0,0 +1,23 @@
+//go:build windows
+
+package organizer
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileSystemType returns the file system name of the volume holding dir, such as
+// NTFS or FAT32
+func fileSystemType(dir string) string {
+	root, err := windows.UTF16PtrFromString(filepath.VolumeName(dir) + `\`)
+	if err != nil {
+		return ""
+	}
+	name := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumeInformation(root, nil, 0, nil, nil, nil, &name[0], uint32(len(name))); err != nil {
+		return ""
+	}
+	return windows.UTF16ToString(name)
+}
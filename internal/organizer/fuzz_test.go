@@ -0,0 +1,197 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jeeftor/audiobook-organizer/internal/planning"
+)
+
+// Names from reported special character and emoji bugs, seeded into every fuzz target
+// below. Inputs the fuzzer finds are kept in testdata/fuzz.
+var fuzzRegressionNames = []string{
+	"The Magician's Nephew",
+	"Book & Title: Special $$ Edition",
+	"Series #1",
+	"Author @ Name",
+	"C.S. Lewis",
+	"Book.With.Dots_And_Underscores",
+	"📚 Dune 🏜️",
+	"👨‍👩‍👧 Family Saga",
+	"Café Müller",
+	"東野 圭吾",
+	"What If?: Serious Answers",
+	"AC/DC: The Story",
+	`Back\Slash`,
+	"..",
+	"../../etc",
+	"...",
+	" . ",
+	"",
+	"CON",
+	"Trailing Dot.",
+	"Tab\tand\nnewline",
+	"nul\x00byte",
+	"\xff\xfe",
+}
+
+// checkPathComponent fails the test when name can't be used as one path component:
+// it is empty, "." or "..", holds a separator, or isn't valid on the target OS
+func checkPathComponent(t *testing.T, what, input, name string, windows bool) {
+	t.Helper()
+	switch {
+	case name == "" || name == "." || name == "..":
+		t.Fatalf("%s(%q) = %q, not a usable path component", what, input, name)
+	case strings.ContainsAny(name, "/\x00"):
+		t.Fatalf("%s(%q) = %q contains a separator or NUL", what, input, name)
+	case windows && strings.ContainsAny(name, `<>:"\|?*`):
+		t.Fatalf("%s(%q) = %q contains a character Windows forbids", what, input, name)
+	case windows && strings.IndexFunc(name, func(r rune) bool { return r < 0x20 }) >= 0:
+		t.Fatalf("%s(%q) = %q contains a control character Windows forbids", what, input, name)
+	case windows && strings.TrimRight(name, " .") != name:
+		t.Fatalf("%s(%q) = %q ends in a space or dot Windows drops", what, input, name)
+	}
+}
+
+func FuzzSanitizePath(f *testing.F) {
+	for _, name := range fuzzRegressionNames {
+		f.Add(name, "")
+		f.Add(name, "_")
+	}
+	f.Fuzz(func(t *testing.T, name, replaceSpace string) {
+		if strings.ContainsAny(replaceSpace, "/\\\x00") || utf8.RuneCountInString(replaceSpace) > 1 {
+			t.Skip("--replace_space is a single safe character")
+		}
+		for _, goos := range []string{"linux", "darwin", "windows"} {
+			sanitized := NamingPolicy{ReplaceSpace: replaceSpace, TargetOS: goos}.Dir(name)
+			if sanitized == "" {
+				// An empty component is dropped by the layout, checked in FuzzTargetPath
+				continue
+			}
+			checkPathComponent(t, "Sanitize/"+goos, name, sanitized, goos == "windows")
+			if again := (NamingPolicy{ReplaceSpace: replaceSpace, TargetOS: goos}).Dir(sanitized); again != sanitized {
+				t.Fatalf("Sanitize/%s isn't idempotent: %q -> %q -> %q", goos, name, sanitized, again)
+			}
+		}
+	})
+}
+
+func FuzzApplyFilenamePattern(f *testing.F) {
+	for _, name := range fuzzRegressionNames {
+		f.Add("{author} - {title}", name, "Frank Herbert", ".mp3")
+		f.Add("{track:02d} {title}", "Dune", name, ".m4b")
+	}
+	f.Fuzz(func(t *testing.T, template, title, author, ext string) {
+		parsed, err := ParseTemplate(template)
+		if err != nil {
+			t.Skip("not a valid template")
+		}
+		renderer := NewTemplateRenderer(parsed, NewAuthorFormatter(AuthorFormatFirstLast))
+		if ext != "" && (ext[0] != '.' || strings.ContainsAny(ext[1:], "./\\\x00") || len(ext) > 6) {
+			t.Skip("extensions come from existing file names")
+		}
+		metadata := Metadata{Title: title, Authors: []string{author}, TrackNumber: 3}
+		name, err := planning.RenderFilename(renderer, metadata, ext, "")
+		if err != nil {
+			return
+		}
+		checkPathComponent(t, "RenderFilename", template+"|"+title+"|"+author, name, false)
+		if !strings.HasSuffix(name, ext) {
+			t.Fatalf("RenderFilename(%q) = %q lost the extension %q", template, name, ext)
+		}
+	})
+}
+
+func FuzzAddTrackPrefix(f *testing.F) {
+	for _, name := range fuzzRegressionNames {
+		f.Add(name+".mp3", 1)
+		f.Add("07 - "+name+".m4b", 7)
+	}
+	f.Add("chapter.mp3", 100)
+	f.Fuzz(func(t *testing.T, name string, track int) {
+		prefixed := AddTrackPrefix(name, track)
+		if track <= 0 {
+			if prefixed != name {
+				t.Fatalf("AddTrackPrefix(%q, %d) = %q, want it unchanged", name, track, prefixed)
+			}
+			return
+		}
+		if strings.Contains(prefixed[len(prefixed)-len(name):], "/") != strings.Contains(name, "/") {
+			t.Fatalf("AddTrackPrefix(%q, %d) = %q changed the directories", name, track, prefixed)
+		}
+		if filepath.Ext(prefixed) != filepath.Ext(name) {
+			t.Fatalf("AddTrackPrefix(%q, %d) = %q changed the extension", name, track, prefixed)
+		}
+		if again := AddTrackPrefix(prefixed, track); again != prefixed {
+			t.Fatalf("AddTrackPrefix isn't idempotent: %q -> %q -> %q", name, prefixed, again)
+		}
+		if !strings.Contains(name, "/") && !strings.ContainsRune(name, 0) {
+			checkPathComponent(t, "AddTrackPrefix", name, prefixed, false)
+		}
+	})
+}
+
+func FuzzCreateAlbumKey(f *testing.F) {
+	for _, name := range fuzzRegressionNames {
+		f.Add(name, "Frank Herbert", "")
+		f.Add("Dune", name, name)
+	}
+	org := &Organizer{}
+	f.Fuzz(func(t *testing.T, title, author, series string) {
+		metadata := Metadata{Title: title, Authors: []string{author}, Series: []string{series}}
+		key := org.createAlbumKey(metadata)
+		if key != org.createAlbumKey(metadata) {
+			t.Fatalf("createAlbumKey(%+v) isn't deterministic", metadata)
+		}
+
+		// Case and surrounding whitespace don't split an album
+		roundTrips := func(s string) bool { return strings.ToLower(strings.ToUpper(s)) == strings.ToLower(s) }
+		if !roundTrips(title) || !roundTrips(author) {
+			return
+		}
+		variant := Metadata{Title: strings.ToUpper(title) + "  ", Authors: []string{" " + strings.ToUpper(author)}, Series: []string{series}}
+		if other := org.createAlbumKey(variant); other != key {
+			t.Fatalf("createAlbumKey differs for %q by %q in another case: %q vs %q", title, author, key, other)
+		}
+	})
+}
+
+// FuzzTargetPath checks end to end that the directory a book is organized into stays
+// below the output directory and never has an empty, "." or ".." component
+func FuzzTargetPath(f *testing.F) {
+	for _, name := range fuzzRegressionNames {
+		f.Add(name, "Frank Herbert", "Dune", "author-series-title")
+		f.Add("Dune", name, name, "author-series-title-number")
+		f.Add(name, name, "", "series-title")
+	}
+	layouts := map[string]bool{"author-series-title": true, "author-series-title-number": true, "author-title": true, "author-only": true, "series-title": true}
+	f.Fuzz(func(t *testing.T, title, author, series, layout string) {
+		if !layouts[layout] {
+			t.Skip("not a layout")
+		}
+		output := filepath.Join(string(filepath.Separator)+"library", "out")
+		config := &OrganizerConfig{BaseDir: output, OutputDir: output, Layout: layout, BookFolder: true}
+		org := &Organizer{config: *config}
+		calculator := NewLayoutCalculator(&org.config, org.SanitizePath)
+
+		metadata := Metadata{Title: title, Authors: []string{author}, Series: []string{series}}
+		if metadata.Validate() != nil {
+			return
+		}
+		target, err := calculator.CalculateTargetPathE(metadata)
+		if err != nil {
+			return
+		}
+		rel, err := filepath.Rel(output, target)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			t.Fatalf("target %q of %q by %q (%q) isn't below the output directory", target, title, author, series)
+		}
+		for _, component := range strings.Split(rel, string(filepath.Separator)) {
+			checkPathComponent(t, "CalculateTargetPathE", title+"|"+author+"|"+series, component, false)
+		}
+	})
+}
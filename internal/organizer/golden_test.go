@@ -0,0 +1,180 @@
+//go:build integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden trees in testdata/golden")
+
+// goldenLibrary is a synthetic library read from testdata/golden/*.yaml
+type goldenLibrary struct {
+	Books []struct {
+		Dir   string                 `yaml:"dir"`
+		Files []string               `yaml:"files"`
+		Tags  map[string]interface{} `yaml:"tags"`
+	} `yaml:"books"`
+}
+
+// goldenLayouts are the built-in layouts every library is organized with
+var goldenLayouts = []string{
+	"author-only",
+	"author-series",
+	"author-title",
+	"author-series-title",
+	"author-series-title-number",
+	"series-title",
+	"series-title-number",
+}
+
+// goldenProfiles are the option combinations every layout is run with
+var goldenProfiles = []struct {
+	name  string
+	apply func(*OrganizerConfig)
+}{
+	{"default", func(*OrganizerConfig) {}},
+	{"replace-space", func(c *OrganizerConfig) { c.ReplaceSpace = "_" }},
+	{"tidy", func(c *OrganizerConfig) {
+		c.Casing = "title"
+		c.StripTitlePrefix = true
+	}},
+}
+
+// TestGoldenLayouts organizes each synthetic library with every layout and profile
+// and compares the output tree with testdata/golden/<library>/<profile>/<layout>.txt.
+// Run with -update after an intended change to rewrite the golden files.
+func TestGoldenLayouts(t *testing.T) {
+	libraries, err := filepath.Glob(filepath.Join("testdata", "golden", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(libraries) == 0 {
+		t.Fatal("no libraries in testdata/golden")
+	}
+
+	for _, libraryPath := range libraries {
+		library := loadGoldenLibrary(t, libraryPath)
+		name := strings.TrimSuffix(filepath.Base(libraryPath), ".yaml")
+		for _, profile := range goldenProfiles {
+			for _, layout := range goldenLayouts {
+				t.Run(name+"/"+profile.name+"/"+layout, func(t *testing.T) {
+					inputDir := t.TempDir()
+					outputDir := t.TempDir()
+					writeGoldenLibrary(t, inputDir, library)
+
+					config := &OrganizerConfig{BaseDir: inputDir, OutputDir: outputDir, Layout: layout}
+					profile.apply(config)
+					org, err := NewOrganizer(config)
+					if err != nil {
+						t.Fatalf("NewOrganizer: %v", err)
+					}
+					if err := org.Execute(); err != nil {
+						t.Fatalf("Execute: %v", err)
+					}
+
+					goldenPath := filepath.Join("testdata", "golden", name, profile.name, layout+".txt")
+					compareGoldenTree(t, goldenPath, listGoldenTree(t, outputDir))
+				})
+			}
+		}
+	}
+}
+
+func loadGoldenLibrary(t *testing.T, path string) goldenLibrary {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var library goldenLibrary
+	if err := yaml.Unmarshal(data, &library); err != nil {
+		t.Fatalf("parsing %s: %v", path, err)
+	}
+	return library
+}
+
+// writeGoldenLibrary creates each book directory with its metadata.json and files
+func writeGoldenLibrary(t *testing.T, inputDir string, library goldenLibrary) {
+	t.Helper()
+	for _, book := range library.Books {
+		bookDir := filepath.Join(inputDir, book.Dir)
+		if err := os.MkdirAll(bookDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		tags, err := json.Marshal(book.Tags)
+		if err != nil {
+			t.Fatalf("encoding tags of %s: %v", book.Dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(bookDir, MetadataFileName), tags, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range book.Files {
+			if err := os.WriteFile(filepath.Join(bookDir, file), []byte(file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// listGoldenTree returns the sorted slash-separated paths of the files below dir,
+// leaving out the undo log and other dotfiles the organizer keeps there
+func listGoldenTree(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func compareGoldenTree(t *testing.T, goldenPath string, tree []string) {
+	t.Helper()
+	got := strings.Join(tree, "\n") + "\n"
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output tree differs from %s\n--- want\n%s--- got\n%s", goldenPath, want, got)
+	}
+}
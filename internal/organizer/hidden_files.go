@@ -0,0 +1,75 @@
+package organizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// HiddenFilePolicy decides what happens to hidden and system files found in a book
+// directory, such as .DS_Store, Thumbs.db, or the state files of sync tools
+type HiddenFilePolicy string
+
+const (
+	// HiddenFilesSkip leaves them in the source directory (the default)
+	HiddenFilesSkip HiddenFilePolicy = "skip"
+	// HiddenFilesDelete removes them once the rest of the book has moved, through the
+	// trash when one is configured
+	HiddenFilesDelete HiddenFilePolicy = "delete"
+	// HiddenFilesMove moves them with the book like any other file
+	HiddenFilesMove HiddenFilePolicy = "move"
+)
+
+// summaryKey is the message that reports what the policy did in the run summary
+func (p HiddenFilePolicy) summaryKey() string {
+	switch p {
+	case HiddenFilesDelete, HiddenFilesMove:
+		return "summary.hidden_files." + string(p)
+	default:
+		return "summary.hidden_files.skip"
+	}
+}
+
+// systemFileNames are files operating systems write next to media, compared in lower case
+var systemFileNames = map[string]bool{
+	"thumbs.db":   true,
+	"ehthumbs.db": true,
+	"desktop.ini": true,
+	"icon\r":      true, // Custom folder icon on macOS
+}
+
+// IsHiddenFile reports whether a file name is a dotfile or a known system file.
+// AppleDouble files ("._Chapter 1.mp3") are dotfiles too.
+func IsHiddenFile(name string) bool {
+	return strings.HasPrefix(name, ".") || systemFileNames[strings.ToLower(name)]
+}
+
+// ParseHiddenFilePolicy parses a --hidden-files value; "" selects HiddenFilesSkip
+func ParseHiddenFilePolicy(value string) (HiddenFilePolicy, error) {
+	switch policy := HiddenFilePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return HiddenFilesSkip, nil
+	case HiddenFilesSkip, HiddenFilesDelete, HiddenFilesMove:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid hidden file policy %q (use skip, delete, or move)", value)
+	}
+}
+
+// hiddenFilePolicy returns the configured policy, defaulting to HiddenFilesSkip
+func (o *Organizer) hiddenFilePolicy() HiddenFilePolicy {
+	if o.config.HiddenFiles == "" {
+		return HiddenFilesSkip
+	}
+	return o.config.HiddenFiles
+}
+
+// deleteHiddenFiles removes the hidden files left behind by a book that has moved
+func (o *Organizer) deleteHiddenFiles(sourcePath string, names []string) {
+	for _, name := range names {
+		path := filepath.Join(sourcePath, name)
+		if err := o.discard(path); err != nil {
+			o.recordError("❌ Error deleting hidden file %s: %v", path, err)
+		}
+	}
+}
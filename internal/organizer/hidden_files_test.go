@@ -0,0 +1,85 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHiddenFile(t *testing.T) {
+	for _, name := range []string{".DS_Store", "._Chapter 1.mp3", ".stfolder", "Thumbs.db", "desktop.ini", "Icon\r"} {
+		assert.True(t, IsHiddenFile(name), name)
+	}
+	for _, name := range []string{"Chapter 1.mp3", "cover.jpg", "metadata.json", "Thumbs.db.mp3"} {
+		assert.False(t, IsHiddenFile(name), name)
+	}
+}
+
+func TestParseHiddenFilePolicy(t *testing.T) {
+	policy, err := ParseHiddenFilePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, HiddenFilesSkip, policy)
+
+	policy, err = ParseHiddenFilePolicy("Delete")
+	require.NoError(t, err)
+	assert.Equal(t, HiddenFilesDelete, policy)
+
+	_, err = ParseHiddenFilePolicy("hide")
+	assert.Error(t, err)
+}
+
+func TestHiddenFilePolicies(t *testing.T) {
+	tests := []struct {
+		policy     HiddenFilePolicy
+		wantSource []string
+		wantTarget []string
+	}{
+		{HiddenFilesSkip, []string{".DS_Store", "Thumbs.db"}, []string{"book.mp3"}},
+		{HiddenFilesDelete, nil, []string{"book.mp3"}},
+		{HiddenFilesMove, nil, []string{".DS_Store", "Thumbs.db", "book.mp3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			base := t.TempDir()
+			source := filepath.Join(base, "Book")
+			target := filepath.Join(t.TempDir(), "Author", "Book")
+			require.NoError(t, os.MkdirAll(source, 0o755))
+			for _, name := range []string{"book.mp3", ".DS_Store", "Thumbs.db"} {
+				require.NoError(t, os.WriteFile(filepath.Join(source, name), []byte(name), 0o644))
+			}
+
+			org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, HiddenFiles: tt.policy})
+			require.NoError(t, err)
+			entries, err := os.ReadDir(source)
+			require.NoError(t, err)
+
+			_, err = org.processDirectoryFiles(entries, source, target, &Metadata{})
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, tt.wantSource, dirNames(t, source))
+			assert.ElementsMatch(t, tt.wantTarget, dirNames(t, target))
+			assert.Len(t, org.summary.HiddenFiles, 2)
+		})
+	}
+}
+
+// dirNames lists the names in dir, or nil when it doesn't exist
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
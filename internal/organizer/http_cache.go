@@ -0,0 +1,115 @@
+package organizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HTTPCacheDirName is the directory of cached HTTP responses in the user cache directory
+const HTTPCacheDirName = "http"
+
+// DefaultHTTPCacheMaxAge is how long a cached response is used before it is fetched again
+const DefaultHTTPCacheMaxAge = 30 * 24 * time.Hour
+
+// userAgent identifies the organizer to the online services it queries
+const userAgent = "audiobook-organizer (+https://github.com/jeeftor/audiobook-organizer)"
+
+// ErrHTTPNotFound is returned for URLs that answered 404, which are cached too
+var ErrHTTPNotFound = errors.New("not found")
+
+// HTTPCache fetches URLs through an on-disk cache shared by every run and every
+// online source, so a library organized twice doesn't ask twice. Responses that
+// answered 200 or 404 are kept for MaxAge; other failures are not cached.
+type HTTPCache struct {
+	Dir     string
+	Client  *http.Client
+	MaxAge  time.Duration
+	Offline bool // Only answer from the cache
+
+	// MinInterval is the least time between two requests, for services that limit
+	// the request rate. Answers from the cache don't wait.
+	MinInterval time.Duration
+	lastRequest time.Time
+}
+
+// NewHTTPCache creates a cache in dir, or in the user cache directory when empty
+func NewHTTPCache(dir string, offline bool) (*HTTPCache, error) {
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("error finding cache directory: %w", err)
+		}
+		dir = filepath.Join(cacheDir, StateDirName, HTTPCacheDirName)
+	}
+	return &HTTPCache{
+		Dir:     dir,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		MaxAge:  DefaultHTTPCacheMaxAge,
+		Offline: offline,
+	}, nil
+}
+
+// Get returns the body of url, reading at most maxBytes. It returns ErrHTTPNotFound
+// when the URL answered 404, and errOffline for uncached URLs when Offline is set.
+func (c *HTTPCache) Get(url string, maxBytes int64) ([]byte, error) {
+	sum := sha256.Sum256([]byte(url))
+	path := filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+	if info, err := os.Stat(path); err == nil && (c.Offline || time.Since(info.ModTime()) < c.MaxAge) {
+		if info.Size() == 0 {
+			return nil, ErrHTTPNotFound
+		}
+		if info.Size() > maxBytes {
+			return nil, fmt.Errorf("%s is larger than %s", url, formatBytes(uint64(maxBytes)))
+		}
+		return os.ReadFile(path)
+	}
+	if c.Offline {
+		return nil, errOffline
+	}
+
+	if wait := c.MinInterval - time.Since(c.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastRequest = time.Now()
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+	response, err := c.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var body []byte
+	switch response.StatusCode {
+	case http.StatusOK:
+		if body, err = io.ReadAll(io.LimitReader(response.Body, maxBytes+1)); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", url, err)
+		}
+		if int64(len(body)) > maxBytes {
+			return nil, fmt.Errorf("%s is larger than %s", url, formatBytes(uint64(maxBytes)))
+		}
+	case http.StatusNotFound:
+		// An empty file records the miss
+	default:
+		return nil, fmt.Errorf("%s: %s", url, response.Status)
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err == nil {
+		_ = os.WriteFile(path, body, 0o644)
+	}
+	if body == nil {
+		return nil, ErrHTTPNotFound
+	}
+	return body, nil
+}
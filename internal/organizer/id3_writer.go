@@ -0,0 +1,167 @@
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"unicode/utf16"
+)
+
+// id3Padding is the free space left in a rewritten ID3v2 tag, so later edits fit
+// without rewriting the audio again
+const id3Padding = 1024
+
+// errID3Unsupported is returned for tags the writer leaves alone: ID3v2.2 and tags
+// using unsynchronisation or an extended header
+var errID3Unsupported = errors.New("unsupported ID3v2 tag")
+
+// id3Frame is one frame of an ID3v2.3 or ID3v2.4 tag, kept as read
+type id3Frame struct {
+	id    string
+	flags [2]byte
+	data  []byte
+}
+
+// setID3TextFrames sets text frames of the MP3 file at path, such as TALB, replacing
+// frames with those IDs and keeping every other frame. A file without an ID3v2 tag
+// gets an ID3v2.3 tag. The file is rewritten through a temporary file beside it.
+func setID3TextFrames(path string, text map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	version := byte(3)
+	var frames []id3Frame
+	audio := data
+	if size := id3v2Size(data); size > 0 {
+		if version = data[3]; version < 3 || data[5]&0xd0 != 0 || size > int64(len(data)) {
+			return fmt.Errorf("%s: %w (version 2.%d, flags %#x)", filepath.Base(path), errID3Unsupported, data[3], data[5])
+		}
+		frames = parseID3Frames(data[10:size], version)
+		audio = data[size:]
+	}
+
+	kept := frames[:0]
+	for _, frame := range frames {
+		if _, replaced := text[frame.id]; !replaced {
+			kept = append(kept, frame)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(text)) {
+		kept = append(kept, id3Frame{id: id, data: encodeID3Text(text[id], version)})
+	}
+
+	var tag bytes.Buffer
+	for _, frame := range kept {
+		tag.WriteString(frame.id)
+		tag.Write(id3FrameSize(len(frame.data), version))
+		tag.Write(frame.flags[:])
+		tag.Write(frame.data)
+	}
+	tag.Write(make([]byte, id3Padding))
+
+	var file bytes.Buffer
+	file.WriteString("ID3")
+	file.Write([]byte{version, 0, 0})
+	file.Write(synchsafe(tag.Len()))
+	file.Write(tag.Bytes())
+	file.Write(audio)
+	return replaceFileContents(path, &file)
+}
+
+// parseID3Frames reads the frames of an ID3v2.3 or ID3v2.4 tag body, stopping at the
+// padding
+func parseID3Frames(body []byte, version byte) []id3Frame {
+	var frames []id3Frame
+	for len(body) >= 10 && body[0] != 0 {
+		size := int(binary.BigEndian.Uint32(body[4:8]))
+		if version == 4 {
+			size = int(body[4]&0x7f)<<21 | int(body[5]&0x7f)<<14 | int(body[6]&0x7f)<<7 | int(body[7]&0x7f)
+		}
+		if size < 0 || 10+size > len(body) {
+			break
+		}
+		frames = append(frames, id3Frame{
+			id:    string(body[:4]),
+			flags: [2]byte{body[8], body[9]},
+			data:  body[10 : 10+size],
+		})
+		body = body[10+size:]
+	}
+	return frames
+}
+
+// encodeID3Text encodes a text frame: ISO-8859-1 when the text allows it, otherwise
+// UTF-8 in ID3v2.4 and UTF-16 with a byte order mark in ID3v2.3
+func encodeID3Text(text string, version byte) []byte {
+	latin1 := make([]byte, 0, len(text)+1)
+	latin1 = append(latin1, 0)
+	for _, r := range text {
+		if r > 0xff {
+			latin1 = nil
+			break
+		}
+		latin1 = append(latin1, byte(r))
+	}
+	switch {
+	case latin1 != nil:
+		return latin1
+	case version == 4:
+		return append([]byte{3}, text...)
+	}
+	encoded := []byte{1, 0xff, 0xfe}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		encoded = binary.LittleEndian.AppendUint16(encoded, unit)
+	}
+	return encoded
+}
+
+// id3FrameSize encodes a frame size: plain in ID3v2.3, synchsafe in ID3v2.4
+func id3FrameSize(size int, version byte) []byte {
+	if version == 4 {
+		return synchsafe(size)
+	}
+	return binary.BigEndian.AppendUint32(nil, uint32(size))
+}
+
+// synchsafe encodes n in four bytes of seven bits each
+func synchsafe(n int) []byte {
+	return []byte{byte(n>>21) & 0x7f, byte(n>>14) & 0x7f, byte(n>>7) & 0x7f, byte(n) & 0x7f}
+}
+
+// replaceFileContents writes content to a temporary file beside path and renames it
+// over path, keeping its permissions
+func replaceFileContents(path string, content io.Reader) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := io.Copy(temp, content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
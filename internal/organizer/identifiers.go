@@ -0,0 +1,65 @@
+package organizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// IdentifiersFileName is the sidecar WriteIdentifiers writes next to each organized book
+const IdentifiersFileName = "identifiers.json"
+
+// BookIdentifiers records the ISBN and ASIN found for one organized book
+type BookIdentifiers struct {
+	Path  string `json:"path"`
+	Title string `json:"title"`
+	ISBN  string `json:"isbn,omitempty"`
+	ASIN  string `json:"asin,omitempty"`
+}
+
+// recordIdentifiers adds the identifiers of a book to the summary when it has any
+func (o *Organizer) recordIdentifiers(path string, metadata Metadata) {
+	if metadata.ISBN == "" && metadata.ASIN == "" {
+		return
+	}
+	o.summary.Identifiers = append(o.summary.Identifiers, BookIdentifiers{
+		Path:  path,
+		Title: metadata.Title,
+		ISBN:  metadata.ISBN,
+		ASIN:  metadata.ASIN,
+	})
+}
+
+// writeIdentifiers writes the identifiers of a book to identifiers.json in dir when
+// WriteIdentifiers is set, so they survive tags that get rewritten or stripped later
+func (o *Organizer) writeIdentifiers(dir string, metadata Metadata) {
+	if !o.config.WriteIdentifiers || o.config.DryRun || o.hasRemoteTarget() ||
+		(metadata.ISBN == "" && metadata.ASIN == "") {
+		return
+	}
+	data, err := json.MarshalIndent(struct {
+		ISBN string `json:"isbn,omitempty"`
+		ASIN string `json:"asin,omitempty"`
+	}{metadata.ISBN, metadata.ASIN}, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, IdentifiersFileName), append(data, '\n'), 0o644)
+	}
+	if err != nil {
+		PrintYellow("⚠️  Warning: couldn't write %s in %s: %v", IdentifiersFileName, dir, err)
+	}
+}
+
+// formatIdentifiers describes the identifiers of metadata, such as
+// "ISBN 9780765326355, ASIN B003ZWFO7E", or "" when it has none
+func formatIdentifiers(metadata Metadata) string {
+	switch {
+	case metadata.ISBN != "" && metadata.ASIN != "":
+		return fmt.Sprintf("ISBN %s, ASIN %s", metadata.ISBN, metadata.ASIN)
+	case metadata.ISBN != "":
+		return "ISBN " + metadata.ISBN
+	case metadata.ASIN != "":
+		return "ASIN " + metadata.ASIN
+	}
+	return ""
+}
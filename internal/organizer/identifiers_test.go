@@ -0,0 +1,49 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizeWritesIdentifiers(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Kings"),
+		`{"title":"The Way of Kings","authors":["Brandon Sanderson"],"isbn":"978-0-7653-2635-5","asin":"B003ZWFO7E"}`, "01.mp3")
+	writeDisc(t, filepath.Join(base, "Dune"), `{"title":"Dune","authors":["Frank Herbert"]}`, "01.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:          base,
+		OutputDir:        out,
+		Layout:           "author-only",
+		WriteIdentifiers: true,
+	})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	assert.Equal(t, []BookIdentifiers{{
+		Path:  filepath.Join(base, "Kings"),
+		Title: "The Way of Kings",
+		ISBN:  "9780765326355",
+		ASIN:  "B003ZWFO7E",
+	}}, NewRunReport(org.GetSummary(), false, nil).Identifiers)
+
+	data, err := os.ReadFile(filepath.Join(out, "Brandon Sanderson", IdentifiersFileName))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"isbn":"9780765326355","asin":"B003ZWFO7E"}`, string(data))
+	assert.NoFileExists(t, filepath.Join(out, "Frank Herbert", IdentifiersFileName))
+}
+
+func TestFormatIdentifiers(t *testing.T) {
+	assert.Equal(t, "ISBN 9780765326355, ASIN B003ZWFO7E", formatIdentifiers(Metadata{ISBN: "9780765326355", ASIN: "B003ZWFO7E"}))
+	assert.Equal(t, "ASIN B003ZWFO7E", formatIdentifiers(Metadata{ASIN: "B003ZWFO7E"}))
+	assert.Empty(t, formatIdentifiers(Metadata{}))
+}
@@ -0,0 +1,19 @@
+package organizer
+
+import "path/filepath"
+
+// IgnoreMarkers are the file names that keep the organizer out of a directory and
+// everything below it. .nomedia follows the Android and media scanner convention;
+// .noorg and .abook-ignore are specific to the organizer.
+var IgnoreMarkers = []string{".nomedia", ".noorg", ".abook-ignore"}
+
+// IgnoreMarker returns the name of the first marker file found in dir, or "" when
+// dir holds none
+func IgnoreMarker(dir string) string {
+	for _, name := range IgnoreMarkers {
+		if fileExists(filepath.Join(dir, name)) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerSkipsDirsWithIgnoreMarker(t *testing.T) {
+	root := t.TempDir()
+	writeBook := func(dir string) {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataFileName),
+			[]byte(`{"title":"Book","authors":["Author"]}`), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "book.mp3"), []byte("audio"), 0o644))
+	}
+	writeBook(filepath.Join(root, "Library", "Book"))
+	writeBook(filepath.Join(root, "Podcasts", "Show", "Episode"))
+	writeBook(filepath.Join(root, "Library", "Sample"))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Podcasts", ".nomedia"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Library", "Sample", ".abook-ignore"), nil, 0o644))
+
+	for _, index := range []*ScanIndex{nil, LoadScanIndex(filepath.Join(t.TempDir(), ScanIndexFileName), "")} {
+		ignored := map[string]string{}
+		var books []string
+		err := NewScanner(ScanOptions{Index: index}).Walk(root, ScanHandler{
+			Book: func(book Book) error {
+				books = append(books, book.Path)
+				return nil
+			},
+			Ignored: func(dir, marker string) { ignored[dir] = marker },
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{filepath.Join(root, "Library", "Book")}, books)
+		assert.Equal(t, map[string]string{
+			filepath.Join(root, "Podcasts"):          ".nomedia",
+			filepath.Join(root, "Library", "Sample"): ".abook-ignore",
+		}, ignored)
+	}
+}
+
+func TestIgnoreMarker(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, "", IgnoreMarker(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".noorg"), nil, 0o644))
+	assert.Equal(t, ".noorg", IgnoreMarker(dir))
+
+	// A directory named like a marker is not a marker
+	other := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(other, ".nomedia"), 0o755))
+	assert.Equal(t, "", IgnoreMarker(other))
+}
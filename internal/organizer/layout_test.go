@@ -474,6 +474,47 @@ func TestLayoutWithSanitization(t *testing.T) {
 	}
 }
 
+// TestLayoutWithCasing tests that casing rewrites path components, including those
+// built by the single-file path builder
+func TestLayoutWithCasing(t *testing.T) {
+	metadata := Metadata{
+		Title:   "THE FINAL EMPIRE",
+		Authors: []string{"brandon sanderson"},
+		Series:  []string{"MISTBORN"},
+	}
+	tests := []struct {
+		casing   string
+		expected string
+	}{
+		{"", filepath.Join("testbase", "brandon sanderson", "MISTBORN", "THE FINAL EMPIRE")},
+		{CasingTitle, filepath.Join("testbase", "Brandon Sanderson", "Mistborn", "The Final Empire")},
+		{CasingSentence, filepath.Join("testbase", "Brandon Sanderson", "Mistborn", "The final empire")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.casing, func(t *testing.T) {
+			config := &OrganizerConfig{BaseDir: "testbase", OutputDir: "testbase", Casing: tt.casing}
+			lc := NewLayoutCalculator(config, func(s string) string { return s })
+			if result := lc.CalculateTargetPath(metadata); result != tt.expected {
+				t.Errorf("CalculateTargetPath() = %v, want %v", result, tt.expected)
+			}
+
+			org := &Organizer{config: *config}
+			result, err := org.calculateAlbumTargetDirE(metadata)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("calculateAlbumTargetDirE() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	if err := (&OrganizerConfig{BaseDir: t.TempDir(), Casing: "upper"}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown casing")
+	}
+}
+
 // TestLayoutWithOutputDir tests that output directory is respected
 func TestLayoutWithOutputDir(t *testing.T) {
 	tests := []struct {
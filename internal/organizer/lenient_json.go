@@ -0,0 +1,188 @@
+package organizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some Windows editors write at the start of a file
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// UnmarshalMetadataJSON parses a metadata.json file into v. Unless strict is set, a
+// UTF-8 byte order mark, comments, trailing commas, and single-quoted strings left
+// by hand editing are accepted. Errors name the line and column in data they were
+// found at.
+func UnmarshalMetadataJSON(data []byte, v interface{}, strict bool) error {
+	normalized, offsets := data, []int(nil)
+	if !strict {
+		var err error
+		if normalized, offsets, err = normalizeJSON(data); err != nil {
+			return err
+		}
+	}
+
+	err := json.Unmarshal(normalized, v)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset is just past the byte that couldn't be parsed
+		return jsonErrorAt(data, originalOffset(offsets, syntaxErr.Offset-1), err)
+	case errors.As(err, &typeErr):
+		return jsonErrorAt(data, originalOffset(offsets, typeErr.Offset-1), err)
+	}
+	return err
+}
+
+// normalizeJSON rewrites the leniencies UnmarshalMetadataJSON accepts into standard
+// JSON. offsets holds the offset in data each byte of the result came from.
+func normalizeJSON(data []byte) ([]byte, []int, error) {
+	out := make([]byte, 0, len(data))
+	offsets := make([]int, 0, len(data))
+	emit := func(b byte, from int) {
+		out = append(out, b)
+		offsets = append(offsets, from)
+	}
+
+	i := 0
+	if bytes.HasPrefix(data, utf8BOM) {
+		i = len(utf8BOM)
+	}
+	for i < len(data) {
+		switch c := data[i]; {
+		case c == '"':
+			end := skipJSONString(data, i)
+			for ; i < end; i++ {
+				emit(data[i], i)
+			}
+		case c == '\'':
+			start := i
+			emit('"', i)
+			for i++; i < len(data) && data[i] != '\''; i++ {
+				switch {
+				case data[i] == '\\' && i+1 < len(data) && data[i+1] == '\'':
+					i++
+					emit('\'', i)
+				case data[i] == '\\' && i+1 < len(data):
+					emit(data[i], i)
+					i++
+					emit(data[i], i)
+				case data[i] == '"':
+					emit('\\', i)
+					emit('"', i)
+				default:
+					emit(data[i], i)
+				}
+			}
+			if i == len(data) {
+				return nil, nil, jsonErrorAt(data, start, errors.New("unterminated single-quoted string"))
+			}
+			emit('"', i)
+			i++
+		case c == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			end, ok := skipJSONComment(data, i)
+			if !ok {
+				return nil, nil, jsonErrorAt(data, i, errors.New("unterminated comment"))
+			}
+			// Keep the line breaks so later errors are reported on the right line
+			for ; i < end; i++ {
+				if data[i] == '\n' {
+					emit('\n', i)
+				}
+			}
+		case c == ',':
+			if next := skipJSONSpace(data, i+1); next < len(data) && (data[next] == '}' || data[next] == ']') {
+				i++
+				continue
+			}
+			emit(c, i)
+			i++
+		default:
+			emit(c, i)
+			i++
+		}
+	}
+	return out, offsets, nil
+}
+
+// skipJSONString returns the offset just past the double-quoted string at start,
+// or len(data) when it is unterminated
+func skipJSONString(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// skipJSONComment returns the offset just past the // or /* comment at start, and
+// false when a /* comment is never closed
+func skipJSONComment(data []byte, start int) (int, bool) {
+	if data[start+1] == '/' {
+		if end := bytes.IndexByte(data[start:], '\n'); end >= 0 {
+			return start + end, true
+		}
+		return len(data), true
+	}
+	if end := bytes.Index(data[start+2:], []byte("*/")); end >= 0 {
+		return start + 2 + end + 2, true
+	}
+	return len(data), false
+}
+
+// skipJSONSpace returns the offset of the first byte from i on that is neither
+// white space nor part of a comment
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case data[i] == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			end, _ := skipJSONComment(data, i)
+			i = end
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// originalOffset maps an offset into normalized JSON back to the input it came from
+func originalOffset(offsets []int, offset int64) int {
+	switch {
+	case offset < 0:
+		return 0
+	case offsets == nil:
+		return int(offset)
+	case int(offset) >= len(offsets):
+		if len(offsets) == 0 {
+			return 0
+		}
+		return offsets[len(offsets)-1] + 1
+	default:
+		return offsets[offset]
+	}
+}
+
+// jsonErrorAt prefixes err with the 1-based line and column of offset in data
+func jsonErrorAt(data []byte, offset int, err error) error {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if data[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	column := utf8.RuneCount(bytes.TrimPrefix(data[lineStart:offset], utf8BOM)) + 1
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
@@ -0,0 +1,72 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalMetadataJSONLenient(t *testing.T) {
+	data := "\xEF\xBB\xBF{\n" +
+		"  // edited by hand\n" +
+		"  'title': 'The Hitchhiker\\'s Guide',\n" +
+		"  \"subtitle\": 'A \"trilogy\" in five parts', /* sic */\n" +
+		"  \"authors\": [\"Douglas Adams\",],\n" +
+		"  \"note\": \"commas, // and 'quotes' stay\",\n" +
+		"}\n"
+
+	var raw map[string]interface{}
+	require.NoError(t, UnmarshalMetadataJSON([]byte(data), &raw, false))
+	assert.Equal(t, "The Hitchhiker's Guide", raw["title"])
+	assert.Equal(t, `A "trilogy" in five parts`, raw["subtitle"])
+	assert.Equal(t, []interface{}{"Douglas Adams"}, raw["authors"])
+	assert.Equal(t, "commas, // and 'quotes' stay", raw["note"])
+}
+
+func TestUnmarshalMetadataJSONErrorLocation(t *testing.T) {
+	var raw map[string]interface{}
+
+	// Positions are those of the file as written, comments and all
+	err := UnmarshalMetadataJSON([]byte("{\n  /* ok */ 'title': 'Dune',\n  \"authors\": [\"Frank Herbert\" \"Brian\"]\n}"), &raw, false)
+	assert.ErrorContains(t, err, "line 3, column 31: invalid character '\"' after array element")
+
+	err = UnmarshalMetadataJSON([]byte("{\"title\": 'Dune}"), &raw, false)
+	assert.ErrorContains(t, err, "line 1, column 11: unterminated single-quoted string")
+
+	err = UnmarshalMetadataJSON([]byte("{\"title\": 42}"), &[]string{}, false)
+	assert.ErrorContains(t, err, "line 1, column 1:")
+}
+
+func TestUnmarshalMetadataJSONStrict(t *testing.T) {
+	var raw map[string]interface{}
+	require.NoError(t, UnmarshalMetadataJSON([]byte(`{"title": "Dune"}`), &raw, true))
+	err := UnmarshalMetadataJSON([]byte("{\"title\": \"Dune\",\n}"), &raw, true)
+	assert.ErrorContains(t, err, "line 2, column 1: invalid character '}'")
+	assert.Error(t, UnmarshalMetadataJSON([]byte("\xEF\xBB\xBF{}"), &raw, true))
+}
+
+func TestScannerReadsHandEditedMetadata(t *testing.T) {
+	baseDir := t.TempDir()
+	bookDir := filepath.Join(baseDir, "Dune")
+	require.NoError(t, os.MkdirAll(bookDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bookDir, MetadataFileName),
+		[]byte("\xEF\xBB\xBF{'title': 'Dune', 'authors': ['Frank Herbert',],}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(bookDir, "audio.mp3"), []byte("audio"), 0o644))
+
+	result, err := NewScanner(ScanOptions{}).Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, "Dune", result.Books[0].Metadata.Title)
+	assert.Equal(t, []string{"Frank Herbert"}, result.Books[0].Metadata.Authors)
+
+	result, err = NewScanner(ScanOptions{StrictJSON: true}).Scan(baseDir)
+	require.NoError(t, err)
+	assert.Empty(t, result.Books)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Err, "error parsing metadata")
+}
@@ -0,0 +1,172 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LockFileName is the lock file a run holds in the library it writes to, so a second
+// run against the same output waits or stops instead of interleaving its moves
+const LockFileName = ".abook-org.lock"
+
+// lockPollInterval is how often a run waiting for the lock checks it again
+var lockPollInterval = time.Second
+
+// unreadableLockAge is how old a lock file that can't be read must be before it
+// counts as stale; a younger one may still be being written by its owner
+const unreadableLockAge = 10 * time.Second
+
+// heldLocks holds the paths of the locks this process holds. A lock naming this
+// process that isn't among them was left by an earlier process that had the same
+// PID, like every run in a container, where the organizer is always PID 1.
+var heldLocks = struct {
+	sync.Mutex
+	paths map[string]bool
+}{paths: make(map[string]bool)}
+
+// LockOwner describes the run holding a library lock
+type LockOwner struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+// LibraryLockedError is returned when another run holds the library lock
+type LibraryLockedError struct {
+	Path  string
+	Owner LockOwner
+}
+
+func (e *LibraryLockedError) Error() string {
+	return fmt.Sprintf(
+		"another run (pid %d on %s, started %s) holds %s\n\nWait for it with --wait=10m, or remove the lock with --force-unlock if that run is gone",
+		e.Owner.PID,
+		e.Owner.Host,
+		e.Owner.Started.Format(time.RFC3339),
+		e.Path,
+	)
+}
+
+// LibraryLock is an advisory lock on a library, held for the length of a run
+type LibraryLock struct {
+	path string
+}
+
+// AcquireLibraryLock takes the lock file in dir, waiting up to wait for another run
+// to release it. Locks left by runs that are no longer running on this host are
+// removed; force removes any lock first.
+func AcquireLibraryLock(dir string, wait time.Duration, force bool) (*LibraryLock, error) {
+	path := filepath.Join(dir, LockFileName)
+	if force {
+		if err := os.Remove(path); err == nil {
+			PrintYellow("🔓 Removed the lock %s", path)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error removing lock: %w", err)
+		}
+	}
+
+	deadline := time.Now().Add(wait)
+	waiting := false
+	for {
+		err := createLockFile(path)
+		if err == nil {
+			heldLocks.Lock()
+			heldLocks.paths[path] = true
+			heldLocks.Unlock()
+			return &LibraryLock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("error creating lock: %w", err)
+		}
+
+		owner, stale := readLockOwner(path)
+		if stale {
+			PrintYellow("🔓 Removing stale lock %s left by pid %d", path, owner.PID)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("error removing stale lock: %w", err)
+			}
+			continue
+		}
+		if !time.Now().Before(deadline) {
+			return nil, &LibraryLockedError{Path: path, Owner: owner}
+		}
+		if !waiting {
+			PrintBlue("⏳ Waiting up to %s for pid %d on %s to finish with %s", wait, owner.PID, owner.Host, dir)
+			waiting = true
+		}
+		time.Sleep(min(lockPollInterval, time.Until(deadline)))
+	}
+}
+
+// createLockFile creates the lock file at path, failing if it exists, and writes this
+// run's owner into it
+func createLockFile(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	host, _ := os.Hostname()
+	err = json.NewEncoder(file).Encode(LockOwner{PID: os.Getpid(), Host: host, Started: time.Now()})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// readLockOwner reads the owner of the lock at path and reports whether the lock is
+// stale: its run is gone from this host, it names this process without this process
+// holding it, or it has been unreadable for a while. Locks of runs on other hosts
+// are never stale, as their processes can't be seen.
+func readLockOwner(path string) (LockOwner, bool) {
+	var owner LockOwner
+	info, err := os.Stat(path)
+	if err != nil {
+		// Released since; try again
+		return owner, errors.Is(err, os.ErrNotExist)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &owner) != nil || owner.PID <= 0 {
+		return owner, time.Since(info.ModTime()) > unreadableLockAge
+	}
+	if host, _ := os.Hostname(); owner.Host != host {
+		return owner, false
+	}
+	if owner.PID == os.Getpid() {
+		heldLocks.Lock()
+		defer heldLocks.Unlock()
+		return owner, !heldLocks.paths[path]
+	}
+	return owner, !processRunning(owner.PID)
+}
+
+// Release removes the lock file. It is safe to call on a nil lock.
+func (l *LibraryLock) Release() error {
+	if l == nil {
+		return nil
+	}
+	heldLocks.Lock()
+	delete(heldLocks.paths, l.path)
+	heldLocks.Unlock()
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lockLibrary takes the lock of the library a run writes to: the output directory,
+// or the directory of the undo log when the output is remote
+func (o *Organizer) lockLibrary() (*LibraryLock, error) {
+	dir := o.layoutCalculator.getTargetBase()
+	if o.hasRemoteTarget() {
+		dir = filepath.Dir(o.GetLogPath())
+	}
+	return AcquireLibraryLock(dir, o.config.LockWait, o.config.ForceUnlock)
+}
@@ -0,0 +1,123 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLock leaves a lock file in dir as another run would
+func writeLock(t *testing.T, dir string, owner LockOwner) {
+	t.Helper()
+	data, err := json.Marshal(owner)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LockFileName), data, 0o644))
+}
+
+func TestLibraryLockExcludesSecondRun(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireLibraryLock(dir, 0, false)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, LockFileName))
+
+	_, err = AcquireLibraryLock(dir, 0, false)
+	var locked *LibraryLockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, os.Getpid(), locked.Owner.PID)
+	assert.Contains(t, err.Error(), "--force-unlock")
+
+	require.NoError(t, lock.Release())
+	assert.NoFileExists(t, filepath.Join(dir, LockFileName))
+	lock, err = AcquireLibraryLock(dir, 0, false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestLibraryLockStaleAndForced(t *testing.T) {
+	dir := t.TempDir()
+	host, _ := os.Hostname()
+
+	// The run that left this lock is gone
+	writeLock(t, dir, LockOwner{PID: 1 << 30, Host: host, Started: time.Now()})
+	lock, err := AcquireLibraryLock(dir, 0, false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+
+	// An earlier process with this process's PID, as in a restarted container
+	writeLock(t, dir, LockOwner{PID: os.Getpid(), Host: host, Started: time.Now()})
+	lock, err = AcquireLibraryLock(dir, 0, false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+
+	// A run on another host can't be checked, so only --force-unlock removes its lock
+	writeLock(t, dir, LockOwner{PID: 1 << 30, Host: host + "-nas", Started: time.Now()})
+	_, err = AcquireLibraryLock(dir, 0, false)
+	require.ErrorAs(t, err, new(*LibraryLockedError))
+	lock, err = AcquireLibraryLock(dir, 0, true)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestLibraryLockWait(t *testing.T) {
+	lockPollInterval = 10 * time.Millisecond
+	defer func() { lockPollInterval = time.Second }()
+	dir := t.TempDir()
+
+	held, err := AcquireLibraryLock(dir, 0, false)
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		held.Release()
+	}()
+
+	lock, err := AcquireLibraryLock(dir, 5*time.Second, false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+
+	held, err = AcquireLibraryLock(dir, 0, false)
+	require.NoError(t, err)
+	defer held.Release()
+	start := time.Now()
+	_, err = AcquireLibraryLock(dir, 30*time.Millisecond, false)
+	require.ErrorAs(t, err, new(*LibraryLockedError))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestExecuteHoldsLibraryLock(t *testing.T) {
+	base := t.TempDir()
+	output := t.TempDir()
+	createBookDir(t, base, "book", "Dune", "Frank Herbert")
+	held, err := AcquireLibraryLock(output, 0, false)
+	require.NoError(t, err)
+
+	newOrganizer := func(dryRun bool) *Organizer {
+		org, err := NewOrganizer(&OrganizerConfig{
+			BaseDir:      base,
+			OutputDir:    output,
+			FieldMapping: DefaultFieldMapping(),
+			DryRun:       dryRun,
+		})
+		require.NoError(t, err)
+		return org
+	}
+
+	CaptureOutput(func() {
+		assert.NoError(t, newOrganizer(true).Execute(), "dry runs don't take the lock")
+		assert.ErrorAs(t, newOrganizer(false).Execute(), new(*LibraryLockedError))
+	})
+	assert.DirExists(t, filepath.Join(base, "book"), "nothing moved while locked")
+
+	require.NoError(t, held.Release())
+	CaptureOutput(func() {
+		require.NoError(t, newOrganizer(false).Execute())
+	})
+	assert.NoFileExists(t, filepath.Join(output, LockFileName), "the lock is released")
+}
@@ -0,0 +1,142 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Survey limits used by first-run setup
+const (
+	DefaultSurveySamples  = 5
+	DefaultSurveyMaxFiles = 5000
+)
+
+// LibrarySurvey is a quick look at an input directory, used to recommend settings
+// before the first real scan
+type LibrarySurvey struct {
+	MetadataFiles int                 `json:"metadata_files"` // metadata.json files found
+	Files         int                 `json:"files"`          // Supported audio and EPUB files
+	BookDirs      int                 `json:"book_dirs"`      // Directories holding supported files
+	LooseFiles    int                 `json:"loose_files"`    // Supported files directly in the input directory
+	Samples       []SurveySample      `json:"samples"`
+	Truncated     bool                `json:"truncated"` // Stopped after the file limit
+	Recommended   SetupRecommendation `json:"recommended"`
+}
+
+// SurveySample is the embedded metadata of one sampled file
+type SurveySample struct {
+	Path     string   `json:"path"`
+	Metadata Metadata `json:"metadata"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// SetupRecommendation is the scan mode and layout suggested by a survey
+type SetupRecommendation struct {
+	UseEmbeddedMetadata bool     `json:"use_embedded_metadata"`
+	Flat                bool     `json:"flat"`
+	Layout              string   `json:"layout"`
+	Reasons             []string `json:"reasons"`
+}
+
+// SurveyLibrary counts metadata.json files and supported files below root, reading
+// the embedded metadata of up to samples files from different directories. The walk
+// stops after maxFiles supported files so huge libraries still answer quickly.
+func SurveyLibrary(root string, samples, maxFiles int) (LibrarySurvey, error) {
+	var survey LibrarySurvey
+	sampledDirs := make(map[string]bool)
+	bookDirs := make(map[string]bool)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsPermission(err) && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if info.Name() == MetadataFileName {
+			survey.MetadataFiles++
+			return nil
+		}
+		if !IsSupportedFile(filepath.Ext(path)) {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		survey.Files++
+		bookDirs[dir] = true
+		if dir == filepath.Clean(root) {
+			survey.LooseFiles++
+		}
+		if len(survey.Samples) < samples && !sampledDirs[dir] {
+			sampledDirs[dir] = true
+			survey.Samples = append(survey.Samples, sampleFile(path))
+		}
+		if maxFiles > 0 && survey.Files >= maxFiles {
+			survey.Truncated = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return survey, err
+	}
+
+	survey.BookDirs = len(bookDirs)
+	survey.Recommended = survey.recommend()
+	return survey, nil
+}
+
+// sampleFile reads only the file's own tags, ignoring any metadata.json next to it
+func sampleFile(path string) SurveySample {
+	metadata, err := NewMetadataProvider(path, true).GetMetadata()
+	if err != nil {
+		return SurveySample{Path: path, Error: err.Error()}
+	}
+	return SurveySample{Path: path, Metadata: metadata}
+}
+
+// recommend picks settings from the survey counts. metadata.json wins when most book
+// folders have one; otherwise embedded tags are used, in flat mode when most files
+// sit loose in the input directory.
+func (s LibrarySurvey) recommend() SetupRecommendation {
+	rec := SetupRecommendation{Layout: "author-series-title"}
+
+	switch {
+	case s.Files == 0:
+		rec.Reasons = append(rec.Reasons, "No audiobook files found yet, so the defaults are kept")
+		return rec
+	case s.MetadataFiles*2 >= s.BookDirs:
+		rec.Reasons = append(rec.Reasons,
+			fmt.Sprintf("%d of %d book folders have %s", min(s.MetadataFiles, s.BookDirs), s.BookDirs, MetadataFileName))
+		return rec
+	}
+
+	rec.UseEmbeddedMetadata = true
+	rec.Reasons = append(rec.Reasons,
+		fmt.Sprintf("Only %d of %d book folders have %s, so file tags are used", s.MetadataFiles, s.BookDirs, MetadataFileName))
+	if s.LooseFiles*2 > s.Files {
+		rec.Flat = true
+		rec.Reasons = append(rec.Reasons,
+			fmt.Sprintf("%d of %d files sit directly in the input folder, so each file is organized on its own", s.LooseFiles, s.Files))
+	}
+
+	tagged, withSeries := 0, 0
+	for _, sample := range s.Samples {
+		if sample.Error != "" {
+			continue
+		}
+		tagged++
+		if sample.Metadata.GetValidSeries() != "" {
+			withSeries++
+		}
+	}
+	if tagged > 0 && withSeries == 0 {
+		rec.Layout = "author-title"
+		rec.Reasons = append(rec.Reasons, "None of the sampled files have series tags")
+	}
+	return rec
+}
@@ -0,0 +1,86 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSurveyLibrary(t *testing.T) {
+	t.Run("metadata.json library", func(t *testing.T) {
+		root := t.TempDir()
+		for _, book := range []string{"One", "Two", "Three"} {
+			createBookDir(t, root, filepath.Join("Author", book), book, "Author")
+		}
+
+		survey, err := SurveyLibrary(root, DefaultSurveySamples, DefaultSurveyMaxFiles)
+		require.NoError(t, err)
+		assert.Equal(t, 3, survey.MetadataFiles)
+		assert.Equal(t, 3, survey.Files)
+		assert.Equal(t, 3, survey.BookDirs)
+		assert.Len(t, survey.Samples, 3)
+		assert.False(t, survey.Recommended.UseEmbeddedMetadata)
+		assert.False(t, survey.Recommended.Flat)
+		assert.Equal(t, "author-series-title", survey.Recommended.Layout)
+	})
+
+	t.Run("loose files without series", func(t *testing.T) {
+		root := t.TempDir()
+		writeFlatFiles(t, root, 4)
+		sub := filepath.Join(root, "Sub")
+		require.NoError(t, os.MkdirAll(sub, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(sub, "book.mp3"), []byte("audio"), 0644))
+
+		survey, err := SurveyLibrary(root, 1, DefaultSurveyMaxFiles)
+		require.NoError(t, err)
+		assert.Equal(t, 0, survey.MetadataFiles)
+		assert.Equal(t, 5, survey.Files)
+		assert.Equal(t, 4, survey.LooseFiles)
+		assert.Len(t, survey.Samples, 1)
+		assert.True(t, survey.Recommended.UseEmbeddedMetadata)
+		assert.True(t, survey.Recommended.Flat)
+		assert.NotEmpty(t, survey.Recommended.Reasons)
+	})
+
+	t.Run("stops at the file limit", func(t *testing.T) {
+		root := t.TempDir()
+		for i := 0; i < 5; i++ {
+			dir := filepath.Join(root, fmt.Sprintf("Book %d", i))
+			require.NoError(t, os.MkdirAll(dir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "01.mp3"), []byte("audio"), 0644))
+		}
+
+		survey, err := SurveyLibrary(root, DefaultSurveySamples, 3)
+		require.NoError(t, err)
+		assert.True(t, survey.Truncated)
+		assert.Equal(t, 3, survey.Files)
+	})
+
+	t.Run("empty directory keeps defaults", func(t *testing.T) {
+		survey, err := SurveyLibrary(t.TempDir(), DefaultSurveySamples, DefaultSurveyMaxFiles)
+		require.NoError(t, err)
+		assert.False(t, survey.Recommended.UseEmbeddedMetadata)
+		assert.Equal(t, "author-series-title", survey.Recommended.Layout)
+	})
+}
+
+func TestSetupRecommendationLayout(t *testing.T) {
+	survey := LibrarySurvey{
+		Files:    2,
+		BookDirs: 2,
+		Samples: []SurveySample{
+			{Path: "a.mp3", Metadata: Metadata{Title: "A", Authors: []string{"X"}}},
+			{Path: "b.mp3", Metadata: Metadata{Title: "B", Authors: []string{"Y"}}},
+		},
+	}
+	assert.Equal(t, "author-title", survey.recommend().Layout)
+
+	survey.Samples[1].Metadata.Series = []string{"Saga #1"}
+	assert.Equal(t, "author-series-title", survey.recommend().Layout)
+}
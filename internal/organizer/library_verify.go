@@ -0,0 +1,145 @@
+package organizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Problems VerifyLogEntries reports for a logged file
+const (
+	VerifyMissing         = "missing"    // Nothing at the logged target
+	VerifySizeChanged     = "size"       // The target's size differs from the logged one
+	VerifyChecksumChanged = "checksum"   // The target's content differs from the logged checksum
+	VerifyUnreadable      = "unreadable" // The target couldn't be checked
+)
+
+// LoggedFileProblem is a logged file that undo would not find as it was left
+type LoggedFileProblem struct {
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// LoggedBookProblems lists the problems of the files of one log entry
+type LoggedBookProblems struct {
+	Timestamp  time.Time           `json:"timestamp"`
+	SourcePath string              `json:"source_path"`
+	TargetPath string              `json:"target_path"`
+	Problems   []LoggedFileProblem `json:"problems"`
+}
+
+// LibraryVerification is the result of checking an undo log against the disk
+type LibraryVerification struct {
+	Entries    int                  `json:"entries"`
+	Files      int                  `json:"files"`      // Files checked at their latest logged target
+	Superseded int                  `json:"superseded"` // Logged moves a later entry moved on from
+	NoSize     int                  `json:"no_size"`    // Files logged without a size, only checked for existence
+	Checksums  int                  `json:"checksums"`  // Files whose content was compared with a logged checksum
+	Books      []LoggedBookProblems `json:"books"`      // Entries with at least one problem
+}
+
+// OK reports whether every logged file is where undo expects it
+func (v LibraryVerification) OK() bool {
+	return len(v.Books) == 0
+}
+
+// VerifyLogEntries replays undo log entries oldest first and checks that every file
+// is still at its latest logged target with the logged size, and with checksums also
+// the logged content. Files a later entry moved again are only checked where that
+// entry put them, so a library organized several times verifies cleanly.
+func VerifyLogEntries(entries []LogEntry, checksums bool) LibraryVerification {
+	type fileRef struct{ entry, file int }
+	latest := make(map[string]fileRef)
+	superseded := make(map[fileRef]bool)
+	for i, entry := range entries {
+		for j, file := range entry.Files {
+			source := filepath.Clean(filepath.Join(entry.SourcePath, file.From))
+			if ref, ok := latest[source]; ok {
+				superseded[ref] = true
+				delete(latest, source)
+			}
+			latest[filepath.Clean(filepath.Join(entry.TargetPath, file.To))] = fileRef{i, j}
+		}
+	}
+
+	result := LibraryVerification{Entries: len(entries), Superseded: len(superseded), Books: []LoggedBookProblems{}}
+	for i, entry := range entries {
+		var problems []LoggedFileProblem
+		for j, file := range entry.Files {
+			if superseded[fileRef{i, j}] {
+				continue
+			}
+			result.Files++
+			problem, checked := verifyLoggedFile(filepath.Join(entry.TargetPath, file.To), file, checksums)
+			if file.Size == 0 {
+				result.NoSize++
+			}
+			if checked {
+				result.Checksums++
+			}
+			if problem != nil {
+				problems = append(problems, *problem)
+			}
+		}
+		if len(problems) > 0 {
+			result.Books = append(result.Books, LoggedBookProblems{
+				Timestamp:  entry.Timestamp,
+				SourcePath: entry.SourcePath,
+				TargetPath: entry.TargetPath,
+				Problems:   problems,
+			})
+		}
+	}
+	return result
+}
+
+// verifyLoggedFile compares one file at path with its log record. It also reports
+// whether the content was compared with a logged checksum.
+func verifyLoggedFile(path string, file FilePair, checksums bool) (*LoggedFileProblem, bool) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &LoggedFileProblem{Path: path, Problem: VerifyMissing}, false
+	}
+	if err != nil {
+		return &LoggedFileProblem{Path: path, Problem: VerifyUnreadable, Detail: err.Error()}, false
+	}
+	if file.Size != 0 && info.Size() != file.Size {
+		return &LoggedFileProblem{
+			Path:    path,
+			Problem: VerifySizeChanged,
+			Detail:  fmt.Sprintf("%d bytes, logged %d", info.Size(), file.Size),
+		}, false
+	}
+	if !checksums || file.SHA256 == "" {
+		return nil, false
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return &LoggedFileProblem{Path: path, Problem: VerifyUnreadable, Detail: err.Error()}, false
+	}
+	if sum != file.SHA256 {
+		return &LoggedFileProblem{Path: path, Problem: VerifyChecksumChanged, Detail: "content changed since it was moved"}, true
+	}
+	return nil, true
+}
+
+// fileSHA256 returns the hex SHA-256 of a file's content
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
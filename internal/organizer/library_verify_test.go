@@ -0,0 +1,86 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLogEntriesAfterRun(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	createBookDir(t, baseDir, "Dune", "Dune", "Frank Herbert")
+	createBookDir(t, baseDir, "Emma", "Emma", "Jane Austen")
+	createBookDir(t, baseDir, "Hobbit", "The Hobbit", "J.R.R. Tolkien")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		FieldMapping: DefaultFieldMapping(),
+		LogChecksums: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	entries, err := ReadLogEntries(org.GetLogPath())
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		for _, file := range entry.Files {
+			assert.NotZero(t, file.Size, file.To)
+			assert.Len(t, file.SHA256, 64, file.To)
+		}
+	}
+
+	result := VerifyLogEntries(entries, true)
+	assert.True(t, result.OK(), "%+v", result.Books)
+	assert.Equal(t, 6, result.Files, "audio and metadata.json of each book")
+	assert.Equal(t, 6, result.Checksums)
+
+	dune := filepath.Join(outputDir, "Frank Herbert", "Dune", TestAudioFileName)
+	emma := filepath.Join(outputDir, "Jane Austen", "Emma", TestAudioFileName)
+	hobbit := filepath.Join(outputDir, "J.R.R. Tolkien", "The Hobbit", TestAudioFileName)
+	require.NoError(t, os.Remove(dune))
+	require.NoError(t, os.WriteFile(emma, []byte("re-encoded"), 0o644))
+	// Same size, different content
+	require.NoError(t, os.WriteFile(hobbit, []byte("AUDIO.MP3"), 0o644))
+
+	problems := make(map[string]string)
+	for _, book := range VerifyLogEntries(entries, true).Books {
+		for _, problem := range book.Problems {
+			problems[problem.Path] = problem.Problem
+		}
+	}
+	assert.Equal(t, map[string]string{
+		dune:   VerifyMissing,
+		emma:   VerifySizeChanged,
+		hobbit: VerifyChecksumChanged,
+	}, problems)
+
+	// Without --checksums the same-size change goes unnoticed
+	assert.Len(t, VerifyLogEntries(entries, false).Books, 2)
+}
+
+func TestVerifyLogEntriesFollowsLaterMoves(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "in")
+	second := filepath.Join(dir, "Author", "Book")
+	third := filepath.Join(dir, "Author", "Series", "Book")
+	require.NoError(t, os.MkdirAll(third, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(third, "01_book.mp3"), []byte("audio"), 0o644))
+
+	entries := []LogEntry{
+		{SourcePath: first, TargetPath: second, Files: []FilePair{{From: "book.mp3", To: "01 book.mp3", Size: 5}}},
+		{SourcePath: second, TargetPath: third, Files: []FilePair{{From: "01 book.mp3", To: "01_book.mp3"}}},
+	}
+	result := VerifyLogEntries(entries, false)
+	assert.True(t, result.OK(), "%+v", result.Books)
+	assert.Equal(t, 1, result.Files)
+	assert.Equal(t, 1, result.Superseded)
+	assert.Equal(t, 1, result.NoSize)
+}
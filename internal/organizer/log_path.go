@@ -0,0 +1,87 @@
+// internal/organizer/log_path.go
+package organizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// StateDirName is the folder below the user state directory that holds undo logs
+// for output directories the organizer cannot write to
+const StateDirName = "audiobook-organizer"
+
+// DefaultLogPath returns where the undo log for dir is kept: .abook-org.log inside
+// dir, or a log in the user state directory when dir is read-only. An existing log
+// in dir always wins so undo finds logs written before the directory became
+// read-only.
+func DefaultLogPath(dir string) string {
+	inDir := filepath.Join(dir, LogFileName)
+	if _, err := os.Stat(inDir); err == nil || dirWritable(dir) {
+		return inDir
+	}
+	if statePath, err := StateLogPath(dir); err == nil {
+		return statePath
+	}
+	return inDir
+}
+
+// StateLogPath names the log for dir in the user state directory
+// ($XDG_STATE_HOME, or ~/.local/state). The name combines the directory's base name
+// with a hash of its absolute path, so every output directory gets its own log.
+func StateLogPath(dir string) (string, error) {
+	stateDir, err := userStateDir()
+	if err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(absDir))
+	name := strings.TrimPrefix(filepath.Base(absDir), ".")
+	if name == "" || name == string(filepath.Separator) {
+		name = "root"
+	}
+	return filepath.Join(stateDir, StateDirName, "logs",
+		name+"-"+hex.EncodeToString(sum[:6])+".log"), nil
+}
+
+// userStateDir follows the XDG base directory spec, using the local app data
+// folder on Windows
+func userStateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	if runtime.GOOS == "windows" {
+		return os.UserCacheDir()
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state"), nil
+}
+
+// dirWritable reports whether files can be created in dir. A directory that does
+// not exist yet counts as writable since the run creates it.
+func dirWritable(dir string) bool {
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return true
+	}
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	probe, err := os.CreateTemp(dir, ".abook-org-write-*")
+	if err != nil {
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return true
+}
@@ -0,0 +1,84 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateLogPath(t *testing.T) {
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	first, err := StateLogPath("/srv/media/audiobooks")
+	require.NoError(t, err)
+	second, err := StateLogPath("/mnt/audiobooks")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, filepath.Join(stateHome, StateDirName, "logs", "audiobooks-")))
+	assert.NotEqual(t, first, second, "directories with the same name get separate logs")
+	again, err := StateLogPath("/srv/media/audiobooks/")
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+}
+
+func TestDefaultLogPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	writable := t.TempDir()
+	assert.Equal(t, filepath.Join(writable, LogFileName), DefaultLogPath(writable))
+
+	missing := filepath.Join(writable, "not-created-yet")
+	assert.Equal(t, filepath.Join(missing, LogFileName), DefaultLogPath(missing))
+
+	if os.Geteuid() == 0 {
+		t.Skip("read-only directories are writable by root")
+	}
+	readOnly := t.TempDir()
+	require.NoError(t, os.Chmod(readOnly, 0o555))
+	t.Cleanup(func() { os.Chmod(readOnly, 0o755) })
+
+	statePath, err := StateLogPath(readOnly)
+	require.NoError(t, err)
+	assert.Equal(t, statePath, DefaultLogPath(readOnly))
+
+	// A log written before the directory became read-only is still used
+	require.NoError(t, os.Chmod(readOnly, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(readOnly, LogFileName), []byte("[]"), 0o644))
+	require.NoError(t, os.Chmod(readOnly, 0o555))
+	assert.Equal(t, filepath.Join(readOnly, LogFileName), DefaultLogPath(readOnly))
+}
+
+func TestOrganizerUsesConfiguredLogPath(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "runs", "library.log")
+	createBookDir(t, baseDir, "Book", "Book", "A")
+
+	config := OrganizerConfig{
+		BaseDir:   baseDir,
+		OutputDir: outputDir,
+		Layout:    "author-title",
+		LogPath:   logPath,
+	}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	assert.Equal(t, logPath, org.GetLogPath())
+	assert.FileExists(t, logPath)
+	assert.NoFileExists(t, filepath.Join(outputDir, LogFileName))
+
+	config.Undo = true
+	undoOrg, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, undoOrg.Execute())
+	assert.FileExists(t, filepath.Join(baseDir, "Book", "audio.mp3"))
+	assert.NoFileExists(t, logPath)
+}
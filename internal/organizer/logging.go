@@ -2,12 +2,16 @@
 package organizer
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -17,6 +21,53 @@ var (
 	ForceDarkMode = false // Set this to true to force dark background everywhere
 )
 
+// QuietMode suppresses decorative output. Only errors are printed, as plain text on stderr.
+var QuietMode = false
+
+// SetQuietMode enables/disables quiet machine mode
+func SetQuietMode(enabled bool) {
+	QuietMode = enabled
+}
+
+// output is where the print helpers write. CaptureOutput swaps it for a buffer.
+var (
+	output   io.Writer = os.Stdout
+	outputMu sync.Mutex
+)
+
+// CaptureOutput runs fn with the print helpers writing to a buffer instead of stdout
+// and returns what fn printed. The TUI uses it to show a real run's output on screen.
+func CaptureOutput(fn func()) string {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	var buf bytes.Buffer
+	previous := output
+	output = &buf
+	defer func() { output = previous }()
+
+	fn()
+	return buf.String()
+}
+
+// StripDecorations removes emoji and pictographic symbols from text, collapsing the
+// leftover leading whitespace so "❌ Error: x" becomes "Error: x".
+func StripDecorations(text string) string {
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) ||
+			(r >= 0xFE00 && r <= 0xFE0F) || r == 0x200D {
+			return -1
+		}
+		return r
+	}, text)
+
+	lines := strings.Split(stripped, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
 // SetForceDarkMode enables/disables forced dark mode
 func SetForceDarkMode(enabled bool) {
 	ForceDarkMode = enabled
@@ -27,6 +78,9 @@ func SetForceDarkMode(enabled bool) {
 
 func (o *Organizer) saveLog() error {
 	logPath := o.GetLogPath()
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return err
+	}
 	data, err := json.MarshalIndent(o.logEntries, "", "  ")
 	if err != nil {
 		return err
@@ -34,74 +88,158 @@ func (o *Organizer) saveLog() error {
 	return os.WriteFile(logPath, data, 0o644)
 }
 
+// undoMoves moves every logged file back to its source directory under its original
+// name, so track prefixes and space replacements are reverted along with the move.
+// Entries are undone newest first, so a file moved twice ends up where it started.
 func (o *Organizer) undoMoves() error {
 	logPath := o.GetLogPath()
-	data, err := os.ReadFile(logPath)
+	entries, err := ReadLogEntries(logPath)
 	if err != nil {
-		return fmt.Errorf("no log file found at %s", logPath)
-	}
-
-	var entries []LogEntry
-	if err := json.Unmarshal(data, &entries); err != nil {
-		return fmt.Errorf("error parsing log: %v", err)
+		return err
 	}
 
-	for _, entry := range entries {
-		PrintYellow("↩️  Restoring files from %s to %s", entry.TargetPath, entry.SourcePath)
-		if err := os.MkdirAll(entry.SourcePath, 0o755); err != nil {
-			PrintRed("❌ Error creating source directory: %v", err)
+	// Albums that can't be restored whole stay in the log for a later undo
+	var kept [][]LogEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		// The entries of one album are restored together or not at all
+		end := i + 1
+		for i > 0 && isSameAlbum(entries[i-1], entries[i]) {
+			i--
+		}
+		album := entries[i:end]
+		if missing := missingAlbumFiles(album); len(missing) > 0 {
+			o.recordError(
+				"❌ Album at %s is incomplete, left in place so it isn't split: missing %s",
+				album[0].TargetPath,
+				strings.Join(missing, ", "),
+			)
+			kept = append(kept, album)
 			continue
 		}
-
-		for _, file := range entry.Files {
-			oldPath := filepath.Join(entry.TargetPath, file.To)
-			newPath := filepath.Join(entry.SourcePath, file.From)
-			if o.config.Verbose {
-				PrintBlue("📦 Moving %s to %s", oldPath, newPath)
-			}
-			if err := os.Rename(oldPath, newPath); err != nil {
-				PrintRed("❌ Error moving %s: %v", oldPath, err)
-			}
+		for j := len(album) - 1; j >= 0; j-- {
+			o.restoreLogEntry(album[j])
 		}
 	}
 
-	if err := os.Remove(logPath); err != nil {
+	if len(kept) > 0 {
+		o.logEntries = nil
+		for i := len(kept) - 1; i >= 0; i-- {
+			o.logEntries = append(o.logEntries, kept[i]...)
+		}
+		return o.saveLog()
+	}
+	if err := o.discard(logPath); err != nil {
 		PrintYellow("⚠️  Warning: couldn't remove log file: %v", err)
 	}
 
 	return nil
 }
 
+// restoreLogEntry moves the files of one log entry back to their source directory
+func (o *Organizer) restoreLogEntry(entry LogEntry) {
+	PrintYellow("↩️  Restoring files from %s to %s", entry.TargetPath, entry.SourcePath)
+	if err := os.MkdirAll(entry.SourcePath, 0o755); err != nil {
+		o.recordError("❌ Error creating source directory: %v", err)
+		return
+	}
+
+	for _, file := range entry.Files {
+		oldPath := filepath.Join(entry.TargetPath, file.To)
+		newPath := filepath.Join(entry.SourcePath, file.From)
+		if o.config.Verbose {
+			PrintBlue("📦 Moving %s to %s", oldPath, newPath)
+		}
+		if isSameLocalFile(oldPath, newPath) {
+			// Linked by --seed-safe; the source never left
+			if err := os.Remove(oldPath); err != nil {
+				o.recordError("❌ Error removing %s: %v", oldPath, err)
+			}
+			continue
+		}
+		if err := o.discardExisting(newPath); err != nil {
+			o.recordError("❌ Error moving %s to trash: %v", newPath, err)
+			continue
+		}
+		if err := os.Rename(oldPath, newPath); err != nil {
+			o.recordError("❌ Error moving %s: %v", oldPath, err)
+		}
+	}
+	o.removeProvenance(entry)
+}
+
+// isSameAlbum reports whether two log entries were written for the same album move
+func isSameAlbum(a, b LogEntry) bool {
+	return a.Album != "" && a.Album == b.Album && a.TargetPath == b.TargetPath
+}
+
+// missingAlbumFiles lists the files of an album's log entries that are no longer at
+// their target
+func missingAlbumFiles(entries []LogEntry) []string {
+	if len(entries) == 0 || entries[0].Album == "" {
+		return nil
+	}
+	var missing []string
+	for _, entry := range entries {
+		for _, file := range entry.Files {
+			if _, err := os.Lstat(filepath.Join(entry.TargetPath, file.To)); err != nil {
+				missing = append(missing, file.To)
+			}
+		}
+	}
+	return missing
+}
+
 func (o *Organizer) printSummary(startTime time.Time) {
 	duration := time.Since(startTime)
+	mode := o.config.Summary
+	msg := o.messages
 
-	PrintBase("\n📊 Summary Report")
-	PrintBase("⏱️  Duration: %v", duration.Round(time.Millisecond))
-
-	PrintGreen("\n📚 Metadata files found: %d", len(o.summary.MetadataFound))
-	if len(o.summary.MetadataFound) > 0 {
-		PrintBase("\n📖 Valid Audiobooks Found:")
+	if mode.showsCounts() {
+		PrintBase("\n📊 %s", msg.Sprintf("summary.report"))
+		PrintBase("⏱️  %s", msg.Sprintf("summary.duration", duration.Round(time.Millisecond)))
+		if profile := o.summary.Profile; profile != nil && profile.Bottleneck != "" {
+			PrintBase("⏱️  %s", msg.Sprintf("summary.profile",
+				100*profile.Share(PhaseMetadata), 100*profile.Share(PhasePlanning), 100*profile.Share(PhaseMoving), profile.Bottleneck))
+		}
+		PrintGreen("\n📚 %s", msg.Sprintf("summary.metadata_found", len(o.summary.MetadataFound)))
+		if o.summary.Sources.Total() > 0 {
+			PrintBase("🧾 %s", msg.Sprintf("summary.metadata_sources", o.summary.Sources))
+		}
+	}
+	if mode.listsEverything() && len(o.summary.MetadataFound) > 0 {
+		PrintBase("\n📖 %s", msg.Sprintf("summary.valid_books"))
+		var found []Metadata
 		for _, path := range o.summary.MetadataFound {
 			data, err := os.ReadFile(path)
 			if err != nil {
 				continue
 			}
-			var metadata Metadata
-			if err := json.Unmarshal(data, &metadata); err != nil {
+			// Only the listed fields are decoded, so a chapter list or narrator of
+			// another shape doesn't keep a book off the list
+			var listed struct {
+				Title   string   `json:"title"`
+				Authors []string `json:"authors"`
+				Series  []string `json:"series"`
+			}
+			if err := UnmarshalMetadataJSON(data, &listed, o.config.StrictJSON); err != nil {
 				continue
 			}
-			if len(metadata.Authors) > 0 && metadata.Title != "" {
-				PrintGreen("  📚 %s by %s", metadata.Title, strings.Join(metadata.Authors, ", "))
-				if len(metadata.Series) > 0 && metadata.Series[0] != "" {
-					cleanedSeries := CleanSeriesName(metadata.Series[0])
-					PrintGreen("     📖 Series: %s", cleanedSeries)
-				}
+			if len(listed.Authors) > 0 && listed.Title != "" {
+				found = append(found, Metadata{Title: listed.Title, Authors: listed.Authors, Series: listed.Series})
+			}
+		}
+		sortByAuthorAndTitle(found, collationFor(o.config.Locale))
+		for _, metadata := range found {
+			PrintGreen("  📚 %s", msg.Sprintf("summary.book_by", metadata.Title, strings.Join(metadata.Authors, ", ")))
+			if len(metadata.Series) > 0 && metadata.Series[0] != "" {
+				cleanedSeries := CleanSeriesName(metadata.Series[0])
+				PrintGreen("     📖 %s", msg.Sprintf("summary.series", cleanedSeries))
 			}
 		}
 	}
 
 	if len(o.summary.MetadataMissing) > 0 {
-		PrintYellow("\n⚠️  Directories without metadata: %d", len(o.summary.MetadataMissing))
+		PrintYellow("\n⚠️  %s", msg.Sprintf("summary.missing_metadata", len(o.summary.MetadataMissing)))
 		if o.config.Verbose {
 			for _, path := range o.summary.MetadataMissing {
 				PrintBase("  - %s", path)
@@ -109,15 +247,112 @@ func (o *Organizer) printSummary(startTime time.Time) {
 		}
 	}
 
-	PrintCyan("\n🔄 Moves planned/executed: %d", len(o.summary.Moves))
-	for _, move := range o.summary.Moves {
-		PrintBase("  From: %s", move.From)
-		PrintBase("  To: %s\n", move.To)
+	if mode.showsCounts() && len(o.summary.SkipListed) > 0 {
+		PrintBlue("\n⏭️  %s", msg.Sprintf("summary.skip_listed", SkipListFileName, len(o.summary.SkipListed)))
+		if o.config.Verbose {
+			for _, path := range o.summary.SkipListed {
+				PrintBase("  - %s", path)
+			}
+		}
+	}
+
+	if len(o.summary.Protected) > 0 {
+		PrintYellow("\n🛡️  %s", msg.Sprintf("summary.protected", len(o.summary.Protected)))
+		for _, protected := range o.summary.Protected {
+			PrintBase("  - %s (%s)", protected.Path, protected.Server)
+		}
+	}
+
+	if len(o.summary.Deferred) > 0 {
+		PrintYellow("\n⏳ %s", msg.Sprintf("summary.deferred", len(o.summary.Deferred)))
+		for _, deferral := range o.summary.Deferred {
+			PrintBase("  - %s (%s)", deferral.Path, deferral.Reason)
+		}
+	}
+
+	if len(o.summary.LeftByLimit) > 0 {
+		PrintYellow("\n⏸️  %s", msg.Sprintf("summary.left_by_limit", o.config.Limit, len(o.summary.LeftByLimit)))
+		if o.config.Verbose {
+			for _, path := range o.summary.LeftByLimit {
+				PrintBase("  - %s", path)
+			}
+		}
+	}
+
+	if len(o.summary.Quarantined) > 0 {
+		PrintRed("\n🚫 %s", msg.Sprintf("summary.quarantined", len(o.summary.Quarantined)))
+		for _, held := range o.summary.Quarantined {
+			PrintBase("  - %s (%s %q %s)", held.Path, held.Field, held.Value, held.Reason)
+		}
+	}
+
+	if len(o.summary.LowConfidence) > 0 {
+		PrintYellow("\n🤔 %s", msg.Sprintf("summary.low_confidence", len(o.summary.LowConfidence)))
+		for _, held := range o.summary.LowConfidence {
+			PrintBase("  - %s (%.2f: %s)", held.Path, held.Score, strings.Join(held.Reasons, ", "))
+		}
+	}
+
+	if mode.showsCounts() && len(o.summary.Seeding) > 0 {
+		PrintBlue("\n🌱 %s", msg.Sprintf("summary.seeding", len(o.summary.Seeding)))
+		if o.config.Verbose {
+			for _, path := range o.summary.Seeding {
+				PrintBase("  - %s", path)
+			}
+		}
+	}
+
+	if mode.showsCounts() && len(o.summary.HiddenFiles) > 0 {
+		PrintBlue("\n🙈 %s", msg.Sprintf(o.hiddenFilePolicy().summaryKey(), len(o.summary.HiddenFiles)))
+		if o.config.Verbose {
+			for _, path := range o.summary.HiddenFiles {
+				PrintBase("  - %s", path)
+			}
+		}
+	}
+
+	if mode.showsCounts() && len(o.summary.Merges) > 0 {
+		PrintCyan("\n🔀 %s", msg.Sprintf("summary.merges", len(o.summary.Merges)))
+		for _, merge := range o.summary.Merges {
+			PrintBase("  - %s (%d added, %d replaced, %d identical, %d kept)",
+				merge.Target, len(merge.Added), len(merge.Replaced), len(merge.Identical), len(merge.Kept))
+			if o.config.Verbose {
+				for _, name := range merge.Kept {
+					PrintBase("      kept existing %s; the incoming copy stays in %s", name, merge.Source)
+				}
+			}
+		}
+	}
+
+	if mode.showsCounts() && len(o.summary.Unsupported) > 0 {
+		PrintYellow("\n📎 %s", msg.Sprintf("summary.unsupported", len(o.summary.Unsupported)))
+		for _, path := range o.summary.Unsupported {
+			PrintBase("  - %s", path)
+		}
+	}
+
+	if mode.showsCounts() && len(o.summary.Carried) > 0 {
+		PrintBlue("\n📎 %s", msg.Sprintf("summary.carried", len(o.summary.Carried)))
+		if o.config.Verbose {
+			for _, move := range o.summary.Carried {
+				PrintBase("  - %s", move.From)
+			}
+		}
+	}
+
+	if mode.showsCounts() {
+		PrintCyan("\n🔄 %s", msg.Sprintf("summary.moves", len(o.summary.Moves)))
+	}
+	if mode.listsEverything() {
+		for _, move := range o.summary.Moves {
+			PrintBase("  %s", msg.Sprintf("summary.move_from", move.From))
+			PrintBase("  %s\n", msg.Sprintf("summary.move_to", move.To))
+		}
 	}
 
 	// Print information about removed empty directories
-	if o.config.RemoveEmpty && len(o.summary.EmptyDirsRemoved) > 0 {
-		PrintYellow("\n🗑️  Empty directories removed: %d", len(o.summary.EmptyDirsRemoved))
+	if mode.showsCounts() && o.config.RemoveEmpty && len(o.summary.EmptyDirsRemoved) > 0 {
+		PrintYellow("\n🗑️  %s", msg.Sprintf("summary.empty_dirs", len(o.summary.EmptyDirsRemoved)))
 		if o.config.Verbose {
 			for _, path := range o.summary.EmptyDirsRemoved {
 				PrintBase("  - %s", path)
@@ -125,10 +360,46 @@ func (o *Organizer) printSummary(startTime time.Time) {
 		}
 	}
 
+	if mode.showsCounts() && len(o.summary.Trashed) > 0 {
+		PrintYellow("\n🗑️  %s", msg.Sprintf("summary.trashed", len(o.summary.Trashed)))
+		if o.config.Verbose {
+			for _, path := range o.summary.Trashed {
+				PrintBase("  - %s", path)
+			}
+		}
+	}
+
+	if mode.showsCounts() && len(o.summary.ReleasesMatched) > 0 {
+		PrintBlue("\n🎼 %s", msg.Sprintf("summary.releases_matched", len(o.summary.ReleasesMatched)))
+		if o.config.Verbose {
+			for _, match := range o.summary.ReleasesMatched {
+				PrintBase("  - %s (%s): %s", match.Title, match.ID, strings.Join(match.Filled, ", "))
+			}
+		}
+	}
+
+	if len(o.summary.AuthorVariants) > 0 {
+		PrintAuthorMergeSuggestions(o.summary.AuthorVariants, o.config.Verbose)
+	}
+	if len(o.summary.AuthorCorrections) > 0 {
+		PrintAuthorCorrections(o.summary.AuthorCorrections)
+	}
+
+	if len(o.summary.FailedAfterRetries) > 0 {
+		PrintYellow("\n🔁 %s", msg.Sprintf("summary.failed_after_retries", len(o.summary.FailedAfterRetries)))
+		for _, path := range o.summary.FailedAfterRetries {
+			PrintBase("  - %s", path)
+		}
+	}
+
+	if len(o.summary.Errors) > 0 {
+		PrintYellow("\n❌ %s", msg.Sprintf("summary.errors", len(o.summary.Errors)))
+	}
+
 	if o.config.DryRun {
-		PrintYellow("\n🔍 This was a dry run - no files were actually moved or directories removed")
+		PrintYellow("\n🔍 %s", msg.Sprintf("summary.dry_run"))
 	} else {
-		PrintGreen("\n✅ Organization complete!")
+		PrintGreen("\n✅ %s", msg.Sprintf("summary.complete"))
 	}
 }
 
@@ -175,15 +446,18 @@ var (
 
 // Print functions that respect the ForceDarkMode setting
 func PrintBase(format string, a ...interface{}) {
-	if len(a) == 0 {
-		fmt.Println(format)
-	} else {
-		fmt.Println(fmt.Sprintf(format, a...))
+	text := format
+	if len(a) > 0 {
+		text = fmt.Sprintf(format, a...)
+	}
+	if QuietMode {
+		return
 	}
+	fmt.Fprintln(output, text)
 }
 
 func PrintRed(format string, a ...interface{}) {
-	printStyled(Styles.Error, format, a...)
+	printErrorStyled(Styles.Error, format, a...)
 }
 
 func PrintGreen(format string, a ...interface{}) {
@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
 )
 
 func TestLogFileCreation(t *testing.T) {
@@ -261,3 +264,139 @@ func TestLogFileInOutputDirectory(t *testing.T) {
 		t.Error("log file is empty")
 	}
 }
+
+func TestCaptureOutput(t *testing.T) {
+	got := CaptureOutput(func() {
+		PrintBase("plain %d", 1)
+		PrintYellow("warning")
+	})
+
+	if !strings.Contains(got, "plain 1") || !strings.Contains(got, "warning") {
+		t.Errorf("CaptureOutput() = %q, want both printed lines", got)
+	}
+	if output != os.Stdout {
+		t.Error("CaptureOutput() did not restore stdout")
+	}
+}
+
+func TestUndoMovesRestoresChainedRenamesNewestFirst(t *testing.T) {
+	baseDir := t.TempDir()
+	first := filepath.Join(baseDir, "Incoming")
+	second := filepath.Join(baseDir, "Author", "Title")
+	third := filepath.Join(baseDir, "Author", "Series", "Title")
+	if err := os.MkdirAll(third, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(third, "01_-_book_part.mp3"), []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same file was moved and renamed twice in one run
+	entries := []LogEntry{
+		{SourcePath: first, TargetPath: second, Files: []FilePair{{From: "book part.mp3", To: "01 - book part.mp3"}}},
+		{SourcePath: second, TargetPath: third, Files: []FilePair{{From: "01 - book part.mp3", To: "01_-_book_part.mp3"}}},
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, LogFileName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: baseDir, Undo: true})
+	if err != nil {
+		t.Fatalf("NewOrganizer() error = %v", err)
+	}
+	if err := org.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(first, "book part.mp3")); err != nil {
+		t.Errorf("file not restored to its original name and location: %v", err)
+	}
+	if len(org.GetSummary().Errors) > 0 {
+		t.Errorf("undo reported errors: %v", org.GetSummary().Errors)
+	}
+}
+
+func TestPrintSummaryModes(t *testing.T) {
+	metadataPath := filepath.Join(t.TempDir(), MetadataFileName)
+	if err := os.WriteFile(metadataPath, []byte(`{"title":"Listed Book","authors":["Author"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	summary := Summary{
+		MetadataFound: []string{metadataPath},
+		Moves:         []MoveSummary{{From: "/in/Listed Book", To: "/out/Author/Listed Book"}},
+		Deferred:      []Deferral{{Path: "/in/Downloading", Reason: "modified 1s ago"}},
+		Errors:        []string{"broken"},
+	}
+
+	tests := []struct {
+		mode    SummaryMode
+		want    []string
+		notWant []string
+	}{
+		{SummaryFull, []string{"Summary Report", "Listed Book by Author", "From: /in/Listed Book", "Moves planned/executed: 1", "/in/Downloading", "Errors: 1"}, nil},
+		{SummaryCompact, []string{"Summary Report", "Moves planned/executed: 1", "/in/Downloading", "Errors: 1"}, []string{"Listed Book by Author", "From: /in/Listed Book"}},
+		{SummaryErrorsOnly, []string{"/in/Downloading", "Errors: 1"}, []string{"Summary Report", "Moves planned/executed", "Listed Book"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			org := &Organizer{config: OrganizerConfig{Summary: tt.mode}, summary: summary}
+			got := CaptureOutput(func() { org.printSummary(time.Now()) })
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("summary missing %q:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("summary contains %q:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintSummaryLanguage(t *testing.T) {
+	messages, err := i18n.New("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	org := &Organizer{
+		messages: messages,
+		summary:  Summary{Moves: []MoveSummary{{From: "/in/Book", To: "/out/Author/Book"}}, Errors: []string{"broken"}},
+	}
+	got := CaptureOutput(func() { org.printSummary(time.Now()) })
+	for _, want := range []string{"Zusammenfassung", "Fehler: 1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Summary Report") {
+		t.Errorf("summary is not translated:\n%s", got)
+	}
+
+	config := OrganizerConfig{BaseDir: t.TempDir(), Language: "xx"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "unsupported language") {
+		t.Errorf("Validate() = %v, want an unsupported language error", err)
+	}
+}
+
+func TestParseSummaryMode(t *testing.T) {
+	for value, want := range map[string]SummaryMode{
+		"":            SummaryFull,
+		"full":        SummaryFull,
+		"Compact":     SummaryCompact,
+		"errors-only": SummaryErrorsOnly,
+	} {
+		got, err := ParseSummaryMode(value)
+		if err != nil || got != want {
+			t.Errorf("ParseSummaryMode(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseSummaryMode("loud"); err == nil {
+		t.Error("ParseSummaryMode(\"loud\") expected an error")
+	}
+}
@@ -0,0 +1,198 @@
+package organizer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergePolicy decides what happens to the files of a book moved into a folder that
+// already holds files, such as a partial copy of the same book from an earlier
+// download
+type MergePolicy string
+
+const (
+	// MergeOverwrite moves every file, replacing files of the same name (the default)
+	MergeOverwrite MergePolicy = "overwrite"
+	// MergeFill moves only the files missing at the target; the rest stay at the source
+	MergeFill MergePolicy = "fill"
+	// MergeReplaceSmaller fills in missing files and replaces files smaller than the
+	// incoming file of the same name, taking them for truncated copies
+	MergeReplaceSmaller MergePolicy = "replace-smaller"
+	// MergeAsk fills in missing files and asks whether each differing file is replaced
+	MergeAsk MergePolicy = "ask"
+)
+
+// ParseMergePolicy parses a --merge value; "" selects MergeOverwrite
+func ParseMergePolicy(value string) (MergePolicy, error) {
+	switch policy := MergePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return MergeOverwrite, nil
+	case MergeOverwrite, MergeFill, MergeReplaceSmaller, MergeAsk:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid merge policy %q (use overwrite, fill, replace-smaller, or ask)", value)
+	}
+}
+
+// BookMerge reports how the files of a book were merged into a target folder that
+// already held files. Files are named as at the target.
+type BookMerge struct {
+	Source    string   `json:"source"`
+	Target    string   `json:"target"`
+	Added     []string `json:"added,omitempty"`     // Missing at the target and moved there
+	Replaced  []string `json:"replaced,omitempty"`  // Replaced the target's file of the same name
+	Identical []string `json:"identical,omitempty"` // Already at the target with the same content; left at the source
+	Kept      []string `json:"kept,omitempty"`      // Differing from the target's file, which was kept; left at the source
+}
+
+// mergeChoice is an answer to the replace prompt of MergeAsk
+type mergeChoice int
+
+const (
+	mergeKeep       mergeChoice = iota
+	mergeReplace                // y: replace this file
+	mergeReplaceAll             // a: replace this and every later differing file
+	mergeKeepAll                // k: keep this and every later differing file
+)
+
+// parseMergeChoice reads an answer to the replace prompt; anything unrecognized keeps
+// the existing file
+func parseMergeChoice(response string) mergeChoice {
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "y", "yes":
+		return mergeReplace
+	case "a", "all":
+		return mergeReplaceAll
+	case "k", "keep":
+		return mergeKeepAll
+	}
+	return mergeKeep
+}
+
+// mergePolicy returns the configured policy, defaulting to MergeOverwrite
+func (o *Organizer) mergePolicy() MergePolicy {
+	if o.config.Merge == "" {
+		return MergeOverwrite
+	}
+	return o.config.Merge
+}
+
+// mergeIntoExisting drops the files of a book that Merge keeps out of an existing
+// target folder and records the merge. Nothing changes with MergeOverwrite, when the
+// target folder doesn't exist yet, or when the book is organized in place.
+func (o *Organizer) mergeIntoExisting(sourcePath, targetPath string, fileNames []FilePair) []FilePair {
+	if o.mergePolicy() == MergeOverwrite || filepath.Clean(sourcePath) == filepath.Clean(targetPath) {
+		return fileNames
+	}
+	if info, err := o.target.Stat(targetPath); err != nil || !info.IsDir() {
+		return fileNames
+	}
+
+	merge := BookMerge{Source: sourcePath, Target: targetPath}
+	var moving []FilePair
+	for _, file := range fileNames {
+		incoming := filepath.Join(sourcePath, file.From)
+		switch o.mergeFile(incoming, filepath.Join(targetPath, file.To)) {
+		case mergeAdded:
+			merge.Added = append(merge.Added, file.To)
+		case mergeReplaced:
+			merge.Replaced = append(merge.Replaced, file.To)
+		case mergeIdentical:
+			merge.Identical = append(merge.Identical, file.To)
+			continue
+		default:
+			merge.Kept = append(merge.Kept, file.To)
+			continue
+		}
+		moving = append(moving, file)
+	}
+
+	PrintCyan("🔀 Merging %s into existing %s: %d added, %d replaced, %d identical, %d kept",
+		sourcePath, targetPath, len(merge.Added), len(merge.Replaced), len(merge.Identical), len(merge.Kept))
+	o.summary.Merges = append(o.summary.Merges, merge)
+	return moving
+}
+
+// mergeOutcome is what Merge does with one incoming file
+type mergeOutcome int
+
+const (
+	mergeKept mergeOutcome = iota
+	mergeAdded
+	mergeReplaced
+	mergeIdentical
+)
+
+// mergeFile compares an incoming file with the file of the same name at the target.
+// Files of the same size are compared by SHA-256, except on a remote target, where
+// the same size counts as the same content.
+func (o *Organizer) mergeFile(incoming, target string) mergeOutcome {
+	existing, err := o.target.Stat(target)
+	if err != nil {
+		return mergeAdded
+	}
+	source, err := os.Stat(incoming)
+	if err != nil || existing.IsDir() {
+		return mergeKept
+	}
+	if source.Size() == existing.Size() && o.sameContent(incoming, target) {
+		return mergeIdentical
+	}
+
+	switch o.mergePolicy() {
+	case MergeReplaceSmaller:
+		if existing.Size() < source.Size() {
+			return mergeReplaced
+		}
+	case MergeAsk:
+		if !o.config.DryRun && o.promptForReplace(target, existing.Size(), source.Size()) {
+			return mergeReplaced
+		}
+	}
+	return mergeKept
+}
+
+// sameContent reports whether two files of the same size hold the same bytes
+func (o *Organizer) sameContent(a, b string) bool {
+	if o.hasRemoteTarget() {
+		return true
+	}
+	aSum, err := fileSHA256(a)
+	if err != nil {
+		return false
+	}
+	bSum, err := fileSHA256(b)
+	return err == nil && aSum == bSum
+}
+
+// promptForReplace asks whether an incoming file replaces the differing file at
+// target. An "a" or "k" answer holds for every later file of the run.
+func (o *Organizer) promptForReplace(target string, existingSize, incomingSize int64) bool {
+	switch o.mergeAnswer {
+	case mergeReplaceAll:
+		return true
+	case mergeKeepAll:
+		return false
+	}
+
+	fmt.Println(RenderWarning("\n🔀 The target already has a different file of the same name:"))
+	fmt.Print(RenderPrompt("  File: "))
+	fmt.Println(RenderPath(target))
+	fmt.Printf("  Existing: %s  Incoming: %s\n", formatBytes(uint64(existingSize)), formatBytes(uint64(incomingSize)))
+	fmt.Println(RenderPrompt("\n  y=replace  n=keep  a=replace all  k=keep all"))
+	fmt.Print(RenderPromptIcon("❓ Replace it with the incoming file? [y/N/a/k] "))
+
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		fmt.Printf(RenderError("Error reading response: %v\n"), err)
+		return false
+	}
+	choice := parseMergeChoice(response)
+	if choice == mergeReplaceAll || choice == mergeKeepAll {
+		o.mergeAnswer = choice
+	}
+	return choice == mergeReplace || choice == mergeReplaceAll
+}
@@ -0,0 +1,127 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergePolicy(t *testing.T) {
+	for value, want := range map[string]MergePolicy{
+		"":                MergeOverwrite,
+		"overwrite":       MergeOverwrite,
+		" Fill ":          MergeFill,
+		"replace-smaller": MergeReplaceSmaller,
+		"ask":             MergeAsk,
+	} {
+		policy, err := ParseMergePolicy(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, policy, value)
+	}
+	_, err := ParseMergePolicy("newest")
+	assert.ErrorContains(t, err, "invalid merge policy")
+}
+
+func TestParseMergeChoice(t *testing.T) {
+	assert.Equal(t, mergeReplace, parseMergeChoice("y\n"))
+	assert.Equal(t, mergeReplaceAll, parseMergeChoice("A"))
+	assert.Equal(t, mergeKeepAll, parseMergeChoice("keep"))
+	assert.Equal(t, mergeKeep, parseMergeChoice(""))
+	assert.Equal(t, mergeKeep, parseMergeChoice("maybe"))
+}
+
+func TestMergeIntoPartialCopy(t *testing.T) {
+	tests := []struct {
+		policy   MergePolicy
+		replaced []string
+		kept     []string
+		part2    string
+	}{
+		{MergeFill, nil, []string{"part2.mp3"}, "pa"},
+		{MergeReplaceSmaller, []string{"part2.mp3"}, nil, "part2.mp3"},
+		{MergeAsk, nil, []string{"part2.mp3"}, "pa"}, // Dry runs don't ask
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			baseDir, outputDir := t.TempDir(), t.TempDir()
+			source := filepath.Join(baseDir, "Dune")
+			writeBook(t, source, map[string]interface{}{"title": "Dune", "authors": []string{"Frank Herbert"}},
+				"part1.mp3", "part2.mp3", "part3.mp3")
+			target := filepath.Join(outputDir, "Frank Herbert", "Dune")
+			writeBook(t, target, nil, "part1.mp3")
+			require.NoError(t, os.WriteFile(filepath.Join(target, "part2.mp3"), []byte("pa"), 0o644))
+
+			org, err := NewOrganizer(&OrganizerConfig{
+				BaseDir:      baseDir,
+				OutputDir:    outputDir,
+				Layout:       "author-title",
+				FieldMapping: DefaultFieldMapping(),
+				Merge:        tt.policy,
+				DryRun:       tt.policy == MergeAsk,
+			})
+			require.NoError(t, err)
+			output := CaptureOutput(func() {
+				require.NoError(t, org.Execute())
+			})
+			assert.Contains(t, output, "Merged into folders that already held files: 1")
+
+			merges := org.GetSummary().Merges
+			require.Len(t, merges, 1)
+			assert.Equal(t, BookMerge{
+				Source:    source,
+				Target:    target,
+				Added:     []string{"metadata.json", "part3.mp3"},
+				Replaced:  tt.replaced,
+				Identical: []string{"part1.mp3"},
+				Kept:      tt.kept,
+			}, merges[0])
+
+			part2, err := os.ReadFile(filepath.Join(target, "part2.mp3"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.part2, string(part2))
+			if tt.policy == MergeAsk {
+				return
+			}
+			assert.FileExists(t, filepath.Join(target, "part3.mp3"))
+			assert.FileExists(t, filepath.Join(source, "part1.mp3"), "identical files stay at the source")
+			assert.NoFileExists(t, filepath.Join(source, "part3.mp3"))
+
+			// Undo only moves back what the merge moved
+			var logged []string
+			for _, entry := range org.logEntries {
+				for _, file := range entry.Files {
+					logged = append(logged, file.To)
+				}
+			}
+			assert.ElementsMatch(t, append([]string{"metadata.json", "part3.mp3"}, tt.replaced...), logged)
+		})
+	}
+}
+
+func TestMergeOverwriteKeepsReplacingFiles(t *testing.T) {
+	baseDir, outputDir := t.TempDir(), t.TempDir()
+	createBookDir(t, baseDir, "Dune", "Dune", "Frank Herbert")
+	target := filepath.Join(outputDir, "Frank Herbert", "Dune")
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "audio.mp3"), []byte("old"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		Layout:       "author-title",
+		FieldMapping: DefaultFieldMapping(),
+	})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+	audio, err := os.ReadFile(filepath.Join(target, "audio.mp3"))
+	require.NoError(t, err)
+	assert.Equal(t, "audio.mp3", string(audio))
+	assert.Empty(t, org.GetSummary().Merges)
+}
@@ -0,0 +1,132 @@
+package organizer
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jeeftor/audiobook-organizer/internal/planning"
+)
+
+// bookMetadataCacheSize bounds how many parsed metadata.json files are kept. Every
+// track in a flat directory shares one file, so a few entries cover a whole scan.
+const bookMetadataCacheSize = 64
+
+// bookMetadataCache keeps recently parsed book-level metadata.json files so hybrid
+// extraction doesn't read and parse the same file again for every track next to it.
+// Entries are checked against the file's size and modification time before use, and
+// the least recently used entry is dropped once the cache is full.
+type bookMetadataCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+}
+
+type cachedBookMetadata struct {
+	path     string
+	modTime  time.Time
+	size     int64
+	strict   bool // Parsed as standard JSON only, so the entry serves strict reads too
+	metadata Metadata
+}
+
+var sharedBookMetadataCache = newBookMetadataCache(bookMetadataCacheSize)
+
+func newBookMetadataCache(max int) *bookMetadataCache {
+	return &bookMetadataCache{
+		max:     max,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns a copy of the cached metadata for path if the file is unchanged. A
+// strict read isn't served an entry that was parsed leniently.
+func (c *bookMetadataCache) get(path string, info os.FileInfo, strict bool) (Metadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[path]
+	if !ok {
+		return Metadata{}, false
+	}
+	entry := element.Value.(*cachedBookMetadata)
+	if !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		c.order.Remove(element)
+		delete(c.entries, path)
+		return Metadata{}, false
+	}
+	if strict && !entry.strict {
+		return Metadata{}, false
+	}
+	c.order.MoveToFront(element)
+	return cloneBookMetadata(entry.metadata), true
+}
+
+// put stores metadata for path, evicting the least recently used entry when full
+func (c *bookMetadataCache) put(path string, info os.FileInfo, metadata Metadata, strict bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cachedBookMetadata{
+		path:     path,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		strict:   strict,
+		metadata: cloneBookMetadata(metadata),
+	}
+	if element, ok := c.entries[path]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[path] = c.order.PushFront(entry)
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedBookMetadata).path)
+	}
+}
+
+func (c *bookMetadataCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// readBookLevelMetadata returns the book-level metadata of the metadata.json at path,
+// parsing it only when it isn't cached
+func readBookLevelMetadata(path string, info os.FileInfo, strict bool) (Metadata, error) {
+	if metadata, ok := sharedBookMetadataCache.get(path, info, strict); ok {
+		return metadata, nil
+	}
+	metadata, err := extractBookLevelMetadataFromJSON(path, strict)
+	if err != nil {
+		return metadata, err
+	}
+	sharedBookMetadataCache.put(path, info, metadata, strict)
+	return metadata, nil
+}
+
+// cloneBookMetadata copies the slices, audio stream, and raw data map so callers
+// can't change a cached entry through the metadata they were given
+func cloneBookMetadata(metadata Metadata) Metadata {
+	clone := metadata
+	clone.Authors = append([]string(nil), metadata.Authors...)
+	clone.Series = append([]string(nil), metadata.Series...)
+	clone.Narrators = append([]string(nil), metadata.Narrators...)
+	clone.Chapters = append([]planning.Chapter(nil), metadata.Chapters...)
+	if metadata.Audio != nil {
+		audio := *metadata.Audio
+		clone.Audio = &audio
+	}
+	if metadata.RawData != nil {
+		clone.RawData = make(map[string]interface{}, len(metadata.RawData))
+		for key, value := range metadata.RawData {
+			clone.RawData[key] = value
+		}
+	}
+	return clone
+}
@@ -0,0 +1,81 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeeftor/audiobook-organizer/internal/planning"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBookJSON(t *testing.T, path, title string) os.FileInfo {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"title": title, "authors": []string{"Author"}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info
+}
+
+func TestBookMetadataCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := newBookMetadataCache(2)
+	paths := []string{
+		filepath.Join(dir, "a.json"),
+		filepath.Join(dir, "b.json"),
+		filepath.Join(dir, "c.json"),
+	}
+	var infos []os.FileInfo
+	for _, path := range paths {
+		info := writeBookJSON(t, path, "Book")
+		metadata, err := extractBookLevelMetadataFromJSON(path, false)
+		require.NoError(t, err)
+		cache.put(path, info, metadata, false)
+		infos = append(infos, info)
+	}
+
+	// The oldest entry is evicted once the cache is full
+	assert.Equal(t, 2, cache.len())
+	_, ok := cache.get(paths[0], infos[0], false)
+	assert.False(t, ok)
+
+	// Callers get a copy they can change freely
+	metadata, ok := cache.get(paths[1], infos[1], false)
+	require.True(t, ok)
+	metadata.Authors[0] = "Changed"
+	metadata.RawData["title"] = "Changed"
+	metadata, _ = cache.get(paths[1], infos[1], false)
+	assert.Equal(t, []string{"Author"}, metadata.Authors)
+	assert.Equal(t, "Book", metadata.RawData["title"])
+
+	// Including the book details
+	detailed := NewMetadata()
+	detailed.Narrators = []string{"Narrator"}
+	detailed.Chapters = []planning.Chapter{{Title: "Opening", End: 60}}
+	detailed.Audio = &AudioInfo{Codec: "aac"}
+	cache.put(paths[1], infos[1], detailed, false)
+	metadata, _ = cache.get(paths[1], infos[1], false)
+	metadata.Narrators[0] = "Changed"
+	metadata.Chapters[0].Title = "Changed"
+	metadata.Audio.Codec = "Changed"
+	metadata, _ = cache.get(paths[1], infos[1], false)
+	assert.Equal(t, []string{"Narrator"}, metadata.Narrators)
+	assert.Equal(t, "Opening", metadata.Chapters[0].Title)
+	assert.Equal(t, "aac", metadata.Audio.Codec)
+
+	// A rewritten file is read again
+	info := writeBookJSON(t, paths[2], "A Longer Title")
+	_, ok = cache.get(paths[2], info, false)
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.len())
+
+	// Leniently parsed entries don't serve strict reads
+	_, ok = cache.get(paths[1], infos[1], true)
+	assert.False(t, ok)
+}
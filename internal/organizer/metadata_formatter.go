@@ -137,7 +137,9 @@ func (mf *MetadataFormatter) FormatMetadataWithMapping() string {
 	switch mf.metadata.SourceType {
 	case "audio":
 		mf.formatAudioFields(&sb)
-	case "epub":
+	case "json":
+		mf.formatBookDetails(&sb)
+	case "epub", "mobi", "fb2", "pdf":
 		mf.formatEPUBFields(&sb)
 	}
 
@@ -164,6 +166,10 @@ func (mf *MetadataFormatter) getFileTypeDisplay() (string, string) {
 			return IconColor("🔊"), IconColor("M4A Audio")
 		case ".flac":
 			return IconColor("🎶"), IconColor("FLAC Audio")
+		case ".ogg", ".opus":
+			return IconColor("🎶"), IconColor(strings.ToUpper(ext[1:]) + " Audio")
+		case ".aac", ".wma", ".wav":
+			return IconColor("🔊"), IconColor(strings.ToUpper(ext[1:]) + " Audio")
 		case "":
 			return IconColor("❓"), IconColor("UNKNOWN")
 		default:
@@ -171,6 +177,15 @@ func (mf *MetadataFormatter) getFileTypeDisplay() (string, string) {
 		}
 	case "epub":
 		return IconColor("📚"), IconColor("EPUB Book")
+	case "mobi":
+		if ext := strings.ToLower(filepath.Ext(mf.metadata.SourcePath)); ext == ".azw" || ext == ".azw3" {
+			return IconColor("📚"), IconColor(strings.ToUpper(ext[1:]) + " Book")
+		}
+		return IconColor("📚"), IconColor("MOBI Book")
+	case "fb2":
+		return IconColor("📚"), IconColor("FB2 Book")
+	case "pdf":
+		return IconColor("📄"), IconColor("PDF Document")
 	default:
 		return IconColor("📄"), IconColor("Metadata")
 	}
@@ -185,6 +200,13 @@ func (mf *MetadataFormatter) formatCoreFields(sb *strings.Builder) {
 		)
 	}
 
+	// Derived title: what --strip-title-prefix keeps when the title repeats other fields
+	if stripped, ok := StripRedundantTitle(mf.metadata); ok {
+		sb.WriteString(
+			fmt.Sprintf("%s Title (derived): %s (with --strip-title-prefix)\n", IconColor("✂️"), stripped),
+		)
+	}
+
 	// Authors - with source indicator
 	if len(mf.metadata.Authors) > 0 {
 		sourceIndicator := mf.formatSourceIndicator("authors")
@@ -196,6 +218,9 @@ func (mf *MetadataFormatter) formatCoreFields(sb *strings.Builder) {
 				sourceIndicator,
 			),
 		)
+		if alias, ok := mf.metadata.RawData[authorAliasField].(string); ok {
+			sb.WriteString(fmt.Sprintf("   (author alias for %s)\n", alias))
+		}
 	}
 
 	// Series - with source indicator and series index if available
@@ -237,6 +262,17 @@ func (mf *MetadataFormatter) formatCoreFields(sb *strings.Builder) {
 		sourceIndicator := mf.formatSourceIndicator("disc_number")
 		sb.WriteString(fmt.Sprintf("%s Disc: %d%s\n", IconColor("💿"), discNum, sourceIndicator))
 	}
+
+	// Audio stream - read from the file headers, so embedded even in hybrid mode
+	if mf.metadata.Audio != nil {
+		sourceIndicator := mf.formatSourceIndicator("track")
+		sb.WriteString(fmt.Sprintf("%s Audio: %s%s\n", IconColor("🎚️"), mf.metadata.Audio, sourceIndicator))
+	}
+
+	// Identifiers - normalized ISBN and ASIN
+	if identifiers := formatIdentifiers(mf.metadata); identifiers != "" {
+		sb.WriteString(fmt.Sprintf("%s Identifiers: %s\n", IconColor("🔖"), identifiers))
+	}
 }
 
 func (mf *MetadataFormatter) formatAudioFields(sb *strings.Builder) {
@@ -312,6 +348,25 @@ func (mf *MetadataFormatter) formatAudioFields(sb *strings.Builder) {
 	}
 }
 
+// formatBookDetails shows the Audiobookshelf fields of a metadata.json
+func (mf *MetadataFormatter) formatBookDetails(sb *strings.Builder) {
+	if mf.metadata.Subtitle != "" {
+		sb.WriteString(fmt.Sprintf("%s Subtitle: %s\n", IconColor("📝"), mf.metadata.Subtitle))
+	}
+	if len(mf.metadata.Narrators) > 0 {
+		sb.WriteString(fmt.Sprintf("%s Narrators: %s\n", IconColor("🗣️"), strings.Join(mf.metadata.Narrators, ", ")))
+	}
+	if mf.metadata.Publisher != "" {
+		sb.WriteString(fmt.Sprintf("%s Publisher: %s\n", IconColor("🏢"), mf.metadata.Publisher))
+	}
+	if mf.metadata.Year > 0 {
+		sb.WriteString(fmt.Sprintf("%s Year: %d\n", IconColor("📅"), mf.metadata.Year))
+	}
+	if len(mf.metadata.Chapters) > 0 {
+		sb.WriteString(fmt.Sprintf("%s Chapters: %d\n", IconColor("📑"), len(mf.metadata.Chapters)))
+	}
+}
+
 func (mf *MetadataFormatter) formatEPUBFields(sb *strings.Builder) {
 	// Publisher - clean display
 	if publisher, ok := mf.metadata.RawData["publisher"].(string); ok && publisher != "" {
@@ -353,10 +408,7 @@ func (mf *MetadataFormatter) FormatFieldMappingAndValues() string {
 	trackColor := TrackNumberColor
 
 	// Title field and value
-	titleField := "title"
-	if mf.fieldMapping.TitleField != "" {
-		titleField = mf.fieldMapping.TitleField
-	}
+	titleField := mf.mappedFieldName("title", mf.fieldMapping.TitleField)
 	sb.WriteString(fmt.Sprintf("   %s %s: %s → %s\n",
 		IconColor("📖"),
 		IconColor("Title"),
@@ -364,10 +416,7 @@ func (mf *MetadataFormatter) FormatFieldMappingAndValues() string {
 		titleColor(mf.metadata.Title)))
 
 	// Series field and value
-	seriesField := "series"
-	if mf.fieldMapping.SeriesField != "" {
-		seriesField = mf.fieldMapping.SeriesField
-	}
+	seriesField := mf.mappedFieldName("series", mf.fieldMapping.SeriesField)
 	if len(mf.metadata.Series) > 0 {
 		seriesValue := strings.Join(mf.metadata.Series, ", ")
 		sb.WriteString(fmt.Sprintf("   %s %s: %s → %s\n",
@@ -392,10 +441,7 @@ func (mf *MetadataFormatter) FormatFieldMappingAndValues() string {
 	}
 
 	// Track field and value
-	trackField := "track"
-	if mf.fieldMapping.TrackField != "" {
-		trackField = mf.fieldMapping.TrackField
-	}
+	trackField := mf.mappedFieldName("track", mf.fieldMapping.TrackField)
 	if mf.metadata.TrackNumber > 0 {
 		sb.WriteString(fmt.Sprintf("   %s %s: %s → %s\n",
 			IconColor("🔢"),
@@ -407,6 +453,37 @@ func (mf *MetadataFormatter) FormatFieldMappingAndValues() string {
 	return sb.String()
 }
 
+// mappedFieldName names the candidate that supplied a mapped field. For a fallback
+// chain the whole chain follows, so "mvnm (series, mvnm, album)" shows that mvnm was
+// used because series was empty.
+func (mf *MetadataFormatter) mappedFieldName(field, spec string) string {
+	candidates := FieldCandidates(spec)
+	if len(candidates) == 0 {
+		return field
+	}
+	if len(candidates) == 1 {
+		return describeFieldCandidate(candidates[0])
+	}
+
+	source := mf.metadata.FieldSource(field)
+	chain := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		chain[i] = describeFieldCandidate(candidate)
+	}
+	if source == "" {
+		return fmt.Sprintf("no value (%s)", strings.Join(chain, ", "))
+	}
+	return fmt.Sprintf("%s (%s)", describeFieldCandidate(source), strings.Join(chain, ", "))
+}
+
+// describeFieldCandidate returns a field name, or a quoted literal for literal candidates
+func describeFieldCandidate(candidate string) string {
+	if literal, ok := strings.CutPrefix(candidate, FieldLiteralPrefix); ok {
+		return fmt.Sprintf("%q", strings.TrimSpace(literal))
+	}
+	return candidate
+}
+
 // FormatMetadata returns a simple formatted string showing the metadata
 func (mf *MetadataFormatter) FormatMetadata() string {
 	var sb strings.Builder
@@ -448,5 +525,9 @@ func (mf *MetadataFormatter) FormatMetadata() string {
 		sb.WriteString(fmt.Sprintf("%s Track: %d\n", IconColor("🔢"), mf.metadata.TrackNumber))
 	}
 
+	if mf.metadata.Audio != nil {
+		sb.WriteString(fmt.Sprintf("%s Audio: %s\n", IconColor("🎚️"), mf.metadata.Audio))
+	}
+
 	return sb.String()
 }
@@ -397,6 +397,47 @@ func TestFormatMetadataFieldMappingDisplay(t *testing.T) {
 	}
 }
 
+func TestFormatMetadataFieldMappingShowsFallbackSource(t *testing.T) {
+	metadata := Metadata{
+		Title:      "Test Book",
+		Authors:    []string{"Test Author"},
+		SourcePath: "test.mp3",
+		RawData:    map[string]interface{}{"mvnm": "Saga"},
+	}
+	fieldMapping := FieldMapping{TitleField: "album,title", SeriesField: "series,mvnm,=Standalone"}
+	metadata.ApplyFieldMapping(fieldMapping)
+
+	formatted := NewMetadataFormatter(metadata, fieldMapping).FormatMetadataWithMapping()
+	for _, want := range []string{
+		`title (album, title)`,
+		`mvnm (series, mvnm, "Standalone")`,
+	} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("Expected formatted output to contain %q, got:\n%s", want, formatted)
+		}
+	}
+}
+
+func TestFormatMetadataDerivedTitle(t *testing.T) {
+	metadata := Metadata{
+		Title:      "Mistborn 01 - The Final Empire",
+		Authors:    []string{"Brandon Sanderson"},
+		Series:     []string{"Mistborn #1"},
+		SourcePath: "test.mp3",
+	}
+
+	formatted := NewMetadataFormatter(metadata, FieldMapping{}).FormatMetadataWithMapping()
+	if !strings.Contains(formatted, "Title (derived): The Final Empire") {
+		t.Errorf("Expected the derived title in the output, got:\n%s", formatted)
+	}
+
+	metadata.Title = "The Final Empire"
+	formatted = NewMetadataFormatter(metadata, FieldMapping{}).FormatMetadataWithMapping()
+	if strings.Contains(formatted, "derived") {
+		t.Errorf("Expected no derived title for a clean title, got:\n%s", formatted)
+	}
+}
+
 func TestFormatMetadataWithEmptyValues(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -10,7 +10,10 @@ import (
 // MetadataInspectionConfig controls non-interactive metadata inspection.
 type MetadataInspectionConfig struct {
 	UseEmbeddedMetadata bool
+	StrictJSON          bool // Parse metadata.json as standard JSON only (see UnmarshalMetadataJSON)
 	FieldMapping        FieldMapping
+	MaxFiles            int  // When > 0, stop scanning after this many supported files
+	Explain             bool // Record which raw field supplied each mapped field (see Metadata.ExplainFieldMapping)
 }
 
 // MetadataInspectionOutput contains metadata inspection results and summary data.
@@ -29,7 +32,9 @@ type MetadataInspectionFile struct {
 	TrackNumber int                    `json:"track_number"`
 	TrackTitle  string                 `json:"track_title,omitempty"`
 	Album       string                 `json:"album"`
+	Audio       *AudioInfo             `json:"audio,omitempty"`
 	RawData     map[string]interface{} `json:"raw_data,omitempty"`
+	Explanation []FieldExplanation     `json:"explanation,omitempty"` // With Explain
 	Error       string                 `json:"error,omitempty"`
 	Metadata    Metadata               `json:"-"`
 }
@@ -40,18 +45,34 @@ type MetadataInspectionSummary struct {
 	Errors       int `json:"errors"`
 }
 
-// ExtractMappedMetadata extracts metadata from a provider and applies field mapping.
+// ExtractMappedMetadata extracts metadata from a provider and applies field mapping,
+// unless the provider comes from NewMappedMetadataProvider.
 func ExtractMappedMetadata(provider MetadataProvider, fieldMapping FieldMapping) (Metadata, error) {
 	metadata, err := provider.GetMetadata()
 	if err != nil {
 		return Metadata{}, err
 	}
+	return applyProviderMapping(metadata, providerFieldMapping(provider, fieldMapping)), nil
+}
 
+// providerFieldMapping returns the mapping ExtractMappedMetadata applies to the
+// metadata of provider: none for a provider whose metadata is already mapped
+func providerFieldMapping(provider MetadataProvider, fieldMapping FieldMapping) FieldMapping {
+	if static, ok := provider.(*StaticMetadataProvider); ok && static.mapped {
+		return FieldMapping{}
+	}
+	return fieldMapping
+}
+
+// applyProviderMapping applies fieldMapping, unless it is empty, and fills in the
+// identifiers and details
+func applyProviderMapping(metadata Metadata, fieldMapping FieldMapping) Metadata {
 	if !fieldMapping.IsEmpty() {
 		metadata.ApplyFieldMapping(fieldMapping)
 	}
-
-	return metadata, nil
+	metadata.FillIdentifiers()
+	metadata.FillDetails()
+	return metadata
 }
 
 // InspectMetadataDirectory scans supported files under inputDir and extracts metadata per file.
@@ -85,6 +106,9 @@ func InspectMetadataDirectory(
 		if !IsSupportedFile(filepath.Ext(path)) {
 			return nil
 		}
+		if config.MaxFiles > 0 && output.Summary.FilesScanned >= config.MaxFiles {
+			return filepath.SkipAll
+		}
 
 		output.Summary.FilesScanned++
 		file := InspectMetadataFile(path, config)
@@ -114,12 +138,17 @@ func InspectMetadataFile(path string, config MetadataInspectionConfig) MetadataI
 		Series:     []string{},
 	}
 
-	provider := NewMetadataProvider(path, config.UseEmbeddedMetadata)
-	metadata, err := ExtractMappedMetadata(provider, config.FieldMapping)
+	provider := newMetadataProvider(path, config.UseEmbeddedMetadata, nil, config.StrictJSON)
+	metadata, err := provider.GetMetadata()
 	if err != nil {
 		file.Error = fmt.Sprintf("failed to extract metadata: %v", err)
 		return file
 	}
+	fieldMapping := providerFieldMapping(provider, config.FieldMapping)
+	if config.Explain {
+		file.Explanation = metadata.ExplainFieldMapping(fieldMapping)
+	}
+	metadata = applyProviderMapping(metadata, fieldMapping)
 
 	file.SourceType = metadata.SourceType
 	file.Title = metadata.Title
@@ -128,6 +157,7 @@ func InspectMetadataFile(path string, config MetadataInspectionConfig) MetadataI
 	file.TrackNumber = metadata.TrackNumber
 	file.TrackTitle = metadata.TrackTitle
 	file.Album = metadata.Album
+	file.Audio = metadata.Audio
 	file.RawData = metadata.RawData
 	file.Metadata = metadata
 	return file
@@ -138,9 +168,16 @@ func MetadataSourceTypeForPath(path string) string {
 	switch strings.ToLower(filepath.Ext(path)) {
 	case ".epub":
 		return "epub"
-	case ".mp3", ".m4b", ".m4a", ".ogg", ".flac":
-		return "audio"
+	case ".mobi", ".azw", ".azw3":
+		return "mobi"
+	case ".fb2":
+		return "fb2"
+	case ".pdf":
+		return "pdf"
 	default:
+		if IsSupportedAudioFile(filepath.Ext(path)) {
+			return "audio"
+		}
 		return "unknown"
 	}
 }
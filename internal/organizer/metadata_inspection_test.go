@@ -57,6 +57,23 @@ func TestExtractMappedMetadata_AppliesFieldMapping(t *testing.T) {
 	}
 }
 
+func TestExtractMappedMetadata_SkipsMappedProvider(t *testing.T) {
+	provider := NewMappedMetadataProvider(Metadata{
+		Title:   "Edited Title",
+		Album:   "Album Tag",
+		Authors: []string{"Edited Author"},
+		RawData: map[string]interface{}{"artist": "Artist Tag"},
+	})
+
+	metadata, err := ExtractMappedMetadata(provider, FieldMapping{TitleField: "album", AuthorFields: []string{"artist"}})
+	if err != nil {
+		t.Fatalf("ExtractMappedMetadata() error = %v", err)
+	}
+	if metadata.Title != "Edited Title" || len(metadata.Authors) != 1 || metadata.Authors[0] != "Edited Author" {
+		t.Errorf("got %q by %v, want the metadata unmapped", metadata.Title, metadata.Authors)
+	}
+}
+
 func TestPrepareMetadata_UsesSharedMappedExtraction(t *testing.T) {
 	mapping := FieldMapping{
 		TitleField:   "album",
@@ -3,7 +3,6 @@ package organizer
 
 import (
 	"archive/zip"
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -20,16 +19,26 @@ import (
 type UnifiedMetadataProvider struct {
 	filePath        string
 	sourceType      string
-	useEmbeddedOnly bool // If true, ignore metadata.json and use only embedded metadata
+	useEmbeddedOnly bool            // If true, ignore metadata.json and use only embedded metadata
+	extensions      ExtensionPolicy // Which files are audio files
+	strictJSON      bool            // Parse metadata.json as standard JSON only (see UnmarshalMetadataJSON)
 }
 
 // NewMetadataProvider creates a unified metadata provider that auto-detects file type
 // useEmbeddedOnly: if true, ignore metadata.json and use only embedded metadata from audio files
 func NewMetadataProvider(path string, useEmbeddedOnly bool) *UnifiedMetadataProvider {
+	return newMetadataProvider(path, useEmbeddedOnly, nil, false)
+}
+
+// newMetadataProvider is NewMetadataProvider with the audio files of extensions,
+// parsing metadata.json strictly when strictJSON is set
+func newMetadataProvider(path string, useEmbeddedOnly bool, extensions ExtensionPolicy, strictJSON bool) *UnifiedMetadataProvider {
 	return &UnifiedMetadataProvider{
 		filePath:        path,
-		sourceType:      detectSourceType(path, useEmbeddedOnly),
+		sourceType:      detectSourceType(path, useEmbeddedOnly, extensions),
 		useEmbeddedOnly: useEmbeddedOnly,
+		extensions:      extensions,
+		strictJSON:      strictJSON,
 	}
 }
 
@@ -40,6 +49,12 @@ func (p *UnifiedMetadataProvider) GetMetadata() (Metadata, error) {
 		return p.extractJSONMetadata()
 	case "epub":
 		return p.extractEPUBMetadata()
+	case "mobi":
+		return p.extractMOBIMetadata()
+	case "fb2":
+		return p.extractFB2Metadata()
+	case "pdf":
+		return p.extractPDFMetadata()
 	case "audio":
 		return p.extractAudioMetadata()
 	default:
@@ -48,16 +63,23 @@ func (p *UnifiedMetadataProvider) GetMetadata() (Metadata, error) {
 }
 
 // detectSourceType determines the file type based on extension
-func detectSourceType(path string, useEmbeddedOnly bool) string {
+func detectSourceType(path string, useEmbeddedOnly bool, extensions ExtensionPolicy) string {
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".json":
 		return "json"
 	case ".epub":
 		return "epub"
-	case ".mp3", ".m4b", ".m4a", ".ogg", ".flac":
-		return "audio"
+	case ".mobi", ".azw", ".azw3":
+		return "mobi"
+	case ".fb2":
+		return "fb2"
+	case ".pdf":
+		return "pdf"
 	default:
+		if extensions.IsAudio(ext) {
+			return "audio"
+		}
 		// Try to detect if it's a directory with specific files
 		if info, err := os.Stat(path); err == nil && info.IsDir() {
 			// If useEmbeddedOnly is true, skip metadata.json detection
@@ -69,7 +91,7 @@ func detectSourceType(path string, useEmbeddedOnly bool) string {
 			if _, err := FindEPUBInDirectory(path); err == nil {
 				return "epub"
 			}
-			if _, err := FindAudioFileInDirectory(path); err == nil {
+			if _, err := extensions.findAudioFile(path); err == nil {
 				return "audio"
 			}
 		}
@@ -98,7 +120,7 @@ func (p *UnifiedMetadataProvider) extractJSONMetadata() (Metadata, error) {
 	}
 
 	var rawData map[string]interface{}
-	if err := json.Unmarshal(data, &rawData); err != nil {
+	if err := UnmarshalMetadataJSON(data, &rawData, p.strictJSON); err != nil {
 		return NewMetadata(), fmt.Errorf("error parsing metadata: %v", err)
 	}
 
@@ -108,10 +130,11 @@ func (p *UnifiedMetadataProvider) extractJSONMetadata() (Metadata, error) {
 
 	// HYBRID: Extract file-level metadata from audio file if available
 	// Track numbers and disc numbers come from the actual audio file, not metadata.json
-	if audioPath, err := FindAudioFileInDirectory(dirPath); err == nil {
+	if audioPath, err := p.extensions.findAudioFile(dirPath); err == nil {
 		if fileLevelMetadata, err := extractFileLevelMetadata(audioPath); err == nil {
-			// Merge file-level metadata (track#, disc#) into book-level metadata
+			// Merge file-level metadata (track#, disc#, audio stream) into book-level metadata
 			metadata.TrackNumber = fileLevelMetadata.TrackNumber
+			metadata.Audio = fileLevelMetadata.Audio
 			if fileLevelMetadata.RawData != nil {
 				if metadata.RawData == nil {
 					metadata.RawData = make(map[string]interface{})
@@ -191,19 +214,22 @@ func (p *UnifiedMetadataProvider) extractJSONMetadata() (Metadata, error) {
 		metadata.RawData["_embedded_source"] = savedEmbeddedSource
 	}
 
+	// Audiobookshelf fields: subtitle, narrators, publisher, publishedYear, description, chapters
+	metadata.FillDetails()
+
 	return metadata, nil
 }
 
 // extractBookLevelMetadataFromJSON extracts ONLY book-level metadata from metadata.json
 // Does NOT perform any audio file lookups (used for hybrid mode)
-func extractBookLevelMetadataFromJSON(jsonPath string) (Metadata, error) {
+func extractBookLevelMetadataFromJSON(jsonPath string, strict bool) (Metadata, error) {
 	data, err := os.ReadFile(jsonPath)
 	if err != nil {
 		return NewMetadata(), fmt.Errorf("error reading metadata file: %v", err)
 	}
 
 	var rawData map[string]interface{}
-	if err := json.Unmarshal(data, &rawData); err != nil {
+	if err := UnmarshalMetadataJSON(data, &rawData, strict); err != nil {
 		return NewMetadata(), fmt.Errorf("error parsing metadata: %v", err)
 	}
 
@@ -232,6 +258,7 @@ func extractBookLevelMetadataFromJSON(jsonPath string) (Metadata, error) {
 			}
 		}
 	}
+	metadata.FillDetails()
 
 	return metadata, nil
 }
@@ -309,6 +336,17 @@ func (p *UnifiedMetadataProvider) extractEPUBMetadata() (Metadata, error) {
 	}
 	if len(info.Identifier) > 0 {
 		metadata.RawData["identifier"] = info.Identifier[0].Value
+		// Every identifier is kept with its scheme, such as "ISBN:9780765326355",
+		// so an ISBN or ASIN is found even when it isn't listed first
+		identifiers := make([]string, 0, len(info.Identifier))
+		for _, id := range info.Identifier {
+			if id.Scheme != "" {
+				identifiers = append(identifiers, id.Scheme+":"+id.Value)
+			} else {
+				identifiers = append(identifiers, id.Value)
+			}
+		}
+		metadata.RawData["identifiers"] = identifiers
 	}
 	metadata.RawData["subjects"] = info.Subject
 
@@ -325,7 +363,7 @@ func (p *UnifiedMetadataProvider) extractAudioMetadata() (Metadata, error) {
 	if info, err := os.Stat(p.filePath); err == nil && info.IsDir() {
 		dirPath = p.filePath
 		var err error
-		audioPath, err = FindAudioFileInDirectory(p.filePath)
+		audioPath, err = p.extensions.findAudioFile(p.filePath)
 		if err != nil {
 			return NewMetadata(), err
 		}
@@ -339,9 +377,9 @@ func (p *UnifiedMetadataProvider) extractAudioMetadata() (Metadata, error) {
 	metadataJSONPath := filepath.Join(dirPath, "metadata.json")
 	var bookMetadata *Metadata
 	if !p.useEmbeddedOnly {
-		if _, err := os.Stat(metadataJSONPath); err == nil {
+		if info, err := os.Stat(metadataJSONPath); err == nil {
 			// metadata.json exists - extract ONLY book-level metadata from it (no audio file lookup)
-			if jsonMeta, err := extractBookLevelMetadataFromJSON(metadataJSONPath); err == nil {
+			if jsonMeta, err := readBookLevelMetadata(metadataJSONPath, info, p.strictJSON); err == nil {
 				bookMetadata = &jsonMeta
 			}
 		}
@@ -353,7 +391,7 @@ func (p *UnifiedMetadataProvider) extractAudioMetadata() (Metadata, error) {
 	}
 	defer file.Close()
 
-	m, err := tag.ReadFrom(file)
+	m, err := readAudioTags(file)
 	if err != nil {
 		return NewMetadata(), fmt.Errorf("error reading audio metadata: %v", err)
 	}
@@ -427,25 +465,43 @@ func (p *UnifiedMetadataProvider) extractAudioMetadata() (Metadata, error) {
 	metadata.RawData["disc_total"] = discTotal
 	metadata.RawData["discnumber"] = discNum // Alias for disc
 
-	// Look for narrator information
-	if val, ok := rawTags["TXXX:NARRATOR"]; ok {
-		if str, ok := val.(string); ok {
-			metadata.RawData["narrator"] = strings.TrimSpace(str)
+	// Expose TXXX frames, MP4 freeform atoms, and movement tags under their real names
+	exposeRawTags(m.Format(), rawTags, metadata.RawData)
+	if m.Format() == tag.MP4 {
+		for key, val := range readMP4MovementAtoms(file) {
+			if _, exists := metadata.RawData[key]; !exists {
+				metadata.RawData[key] = val
+			}
 		}
 	}
-	if val, ok := rawTags["TXXX:Narrator"]; ok {
-		if str, ok := val.(string); ok {
-			metadata.RawData["narrator"] = strings.TrimSpace(str)
-		}
+
+	// Chapter count and playing time for the {chapters} and {duration} template fields
+	chapters, duration := readContainerTiming(file, m.Format(), rawTags)
+	if chapters > 0 {
+		metadata.RawData[ChaptersField] = chapters
+	}
+	if duration > 0 {
+		metadata.RawData[DurationField] = duration.Seconds()
 	}
+	metadata.Audio = readAudioInfo(file, duration)
 
-	// Look for series information
-	if val, ok := rawTags["TXXX:SERIES"]; ok {
-		if str, ok := val.(string); ok {
-			metadata.RawData["series"] = strings.TrimSpace(str)
-			metadata.Series = []string{strings.TrimSpace(str)}
+	// Look for narrator information
+	if narrator, ok := lookupRawFold(metadata.RawData, "NARRATOR", "NARRATEDBY"); ok {
+		metadata.RawData["narrator"] = narrator
+	}
+
+	// Look for series information, falling back to MVNM/MVIN movement tags
+	series, hasExplicitSeries := lookupRawFold(metadata.RawData, "SERIES")
+	if hasExplicitSeries {
+		metadata.RawData["series"] = series
+		metadata.Series = []string{series}
+		if part, ok := lookupRawFold(metadata.RawData, "SERIES-PART", "SERIES_PART", "SERIESPART"); ok {
+			if idx, err := strconv.ParseFloat(part, 64); err == nil && idx > 0 {
+				metadata.RawData["series_index"] = idx
+			}
 		}
 	}
+	applyMovementSeries(&metadata, hasExplicitSeries)
 
 	// Content group might contain series info
 	if val, ok := rawTags["TIT1"]; ok {
@@ -462,6 +518,8 @@ func (p *UnifiedMetadataProvider) extractAudioMetadata() (Metadata, error) {
 		audioTrackTotal := metadata.RawData["track_total"]
 		audioDiscTotal := metadata.RawData["disc_total"]
 		audioDiscNumber := metadata.RawData["discnumber"]
+		audioChapters := metadata.RawData[ChaptersField]
+		audioDuration := metadata.RawData[DurationField]
 
 		// Use book-level metadata for these fields (from JSON)
 		metadata.Title = bookMetadata.Title
@@ -491,6 +549,12 @@ func (p *UnifiedMetadataProvider) extractAudioMetadata() (Metadata, error) {
 		if audioDiscNumber != nil {
 			metadata.RawData["discnumber"] = audioDiscNumber
 		}
+		if audioChapters != nil {
+			metadata.RawData[ChaptersField] = audioChapters
+		}
+		if audioDuration != nil {
+			metadata.RawData[DurationField] = audioDuration
+		}
 
 		// Mark as JSON source type (hybrid mode) and track embedded source
 		metadata.SourceType = "json"
@@ -624,27 +688,10 @@ func FindEPUBInDirectory(dirPath string) (string, error) {
 	return "", fmt.Errorf("no EPUB file found in directory")
 }
 
+// FindAudioFileInDirectory returns the first file in dirPath with one of the
+// SupportedAudioExtensions
 func FindAudioFileInDirectory(dirPath string) (string, error) {
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		return "", fmt.Errorf("error reading directory: %v", err)
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		lowerName := strings.ToLower(entry.Name())
-		if strings.HasSuffix(lowerName, ".mp3") ||
-			strings.HasSuffix(lowerName, ".m4b") ||
-			strings.HasSuffix(lowerName, ".m4a") ||
-			strings.HasSuffix(lowerName, ".ogg") ||
-			strings.HasSuffix(lowerName, ".flac") {
-			return filepath.Join(dirPath, entry.Name()), nil
-		}
-	}
-
-	return "", fmt.Errorf("no supported audio files found in directory")
+	return ExtensionPolicy(nil).findAudioFile(dirPath)
 }
 
 // Legacy provider interfaces for backward compatibility
@@ -702,7 +749,7 @@ func extractFileLevelMetadata(audioPath string) (Metadata, error) {
 	}
 	defer file.Close()
 
-	m, err := tag.ReadFrom(file)
+	m, err := readAudioTags(file)
 	if err != nil {
 		return NewMetadata(), fmt.Errorf("error reading audio metadata: %v", err)
 	}
@@ -733,6 +780,15 @@ func extractFileLevelMetadata(audioPath string) (Metadata, error) {
 		trackTotal = total
 	}
 
+	// Audio stream details, so copies of a book can be compared
+	_, duration := readContainerTiming(file, m.Format(), rawTags)
+	metadata.Audio = readAudioInfo(file, duration)
+
+	// The file's own title tag names its chapter; other tags stay available to the
+	// track title field mapping
+	metadata.TrackTitle = strings.TrimSpace(m.Title())
+	exposeRawTags(m.Format(), rawTags, metadata.RawData)
+
 	// Store only file-level metadata
 	metadata.RawData["track"] = trackNum
 	metadata.RawData["track_total"] = trackTotal
@@ -753,7 +809,13 @@ type JSONMetadataProvider struct {
 // Deprecated: Use NewMetadataProvider(path, false) directly instead.
 // This wrapper is kept for backwards compatibility but adds no additional functionality.
 func NewJSONMetadataProvider(path string) *JSONMetadataProvider {
-	return &JSONMetadataProvider{NewMetadataProvider(path, false)}
+	return newJSONMetadataProvider(path, false)
+}
+
+// newJSONMetadataProvider is NewJSONMetadataProvider, parsing metadata.json strictly
+// when strictJSON is set
+func newJSONMetadataProvider(path string, strictJSON bool) *JSONMetadataProvider {
+	return &JSONMetadataProvider{newMetadataProvider(path, false, nil, strictJSON)}
 }
 
 // EPUBMetadataProvider is a convenience wrapper around UnifiedMetadataProvider.
@@ -332,3 +332,43 @@ func verifyPathSanitization(t *testing.T, path string, replaceSpace string) {
 		}
 	}
 }
+
+func TestJSONMetadataAudiobookshelfFields(t *testing.T) {
+	dir := t.TempDir()
+	metadata := `{
+  "title": "Leviathan Wakes",
+  "subtitle": "The Expanse, Book 1",
+  "authors": ["James S. A. Corey"],
+  "narrators": ["Jefferson Mays"],
+  "series": ["The Expanse #1"],
+  "publishedYear": "2011",
+  "publisher": "Hachette Audio",
+  "description": "Humanity has colonized the solar system.",
+  "chapters": [
+    {"id": 0, "start": 0, "end": 1200.5, "title": "Prologue: Julie"},
+    {"id": 1, "start": 1200.5, "end": 2400, "title": "Holden"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, MetadataFileName), []byte(metadata), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ExtractMappedMetadata(NewJSONMetadataProvider(filepath.Join(dir, MetadataFileName)), DefaultFieldMapping())
+	if err != nil {
+		t.Fatalf("ExtractMappedMetadata() error: %v", err)
+	}
+	if got.Subtitle != "The Expanse, Book 1" || got.Publisher != "Hachette Audio" || got.Year != 2011 {
+		t.Errorf("Subtitle, Publisher, Year = %q, %q, %d", got.Subtitle, got.Publisher, got.Year)
+	}
+	if len(got.Narrators) != 1 || got.Narrators[0] != "Jefferson Mays" {
+		t.Errorf("Narrators = %v", got.Narrators)
+	}
+	if len(got.Chapters) != 2 || got.Chapters[1].Title != "Holden" || got.Chapters[1].Start != 1200.5 {
+		t.Errorf("Chapters = %+v", got.Chapters)
+	}
+
+	calc := NewLayoutCalculator(&OrganizerConfig{LayoutTemplate: "{author}/{year} - {title}{ (narrator)}"}, nil)
+	if path := calc.CalculateTargetPath(got); !strings.HasSuffix(path, filepath.Join("James S. A. Corey", "2011 - Leviathan Wakes (Jefferson Mays)")) {
+		t.Errorf("CalculateTargetPath() = %q", path)
+	}
+}
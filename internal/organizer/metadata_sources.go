@@ -0,0 +1,67 @@
+package organizer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SourceCounts tallies where the metadata of each book found came from, so a run
+// shows how much of the library relies on weak sources
+type SourceCounts struct {
+	JSON     int `json:"json"`     // metadata.json files
+	Hybrid   int `json:"hybrid"`   // metadata.json merged with track and disc tags from the audio
+	Audio    int `json:"audio"`    // Tags embedded in audio files
+	EPUB     int `json:"epub"`     // EPUB package metadata
+	Ebook    int `json:"ebook"`    // MOBI, AZW3, FB2, and PDF metadata
+	Fallback int `json:"fallback"` // No readable metadata, titled by the file name
+	Other    int `json:"other"`    // Any other provider, such as an Audiobookshelf library
+}
+
+// add counts one book by the source its metadata was read from
+func (c *SourceCounts) add(metadata Metadata) {
+	switch metadata.SourceType {
+	case "json":
+		if _, hybrid := metadata.RawData["_embedded_source"]; hybrid {
+			c.Hybrid++
+		} else {
+			c.JSON++
+		}
+	case "audio":
+		c.Audio++
+	case "epub":
+		c.EPUB++
+	case "mobi", "fb2", "pdf":
+		c.Ebook++
+	case "":
+		c.Fallback++
+	default:
+		c.Other++
+	}
+}
+
+// Total returns the number of books counted
+func (c SourceCounts) Total() int {
+	return c.JSON + c.Hybrid + c.Audio + c.EPUB + c.Ebook + c.Fallback + c.Other
+}
+
+// String lists the non-zero counts, e.g. "12 metadata.json, 3 embedded audio"
+func (c SourceCounts) String() string {
+	var parts []string
+	for _, count := range []struct {
+		n     int
+		label string
+	}{
+		{c.JSON, "metadata.json"},
+		{c.Hybrid, "hybrid"},
+		{c.Audio, "embedded audio"},
+		{c.EPUB, "EPUB"},
+		{c.Ebook, "ebook"},
+		{c.Fallback, "fallback"},
+		{c.Other, "other"},
+	} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.label))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
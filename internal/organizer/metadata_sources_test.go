@@ -0,0 +1,25 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceCountsAdd(t *testing.T) {
+	var counts SourceCounts
+	counts.add(Metadata{SourceType: "json", RawData: map[string]interface{}{}})
+	counts.add(Metadata{SourceType: "json", RawData: map[string]interface{}{"_embedded_source": "/books/01.mp3"}})
+	counts.add(Metadata{SourceType: "audio"})
+	counts.add(Metadata{SourceType: "audio"})
+	counts.add(Metadata{SourceType: "epub"})
+	counts.add(Metadata{Title: "01.mp3", Authors: []string{"Unknown Author"}})
+	counts.add(Metadata{SourceType: "abs"})
+
+	assert.Equal(t, SourceCounts{JSON: 1, Hybrid: 1, Audio: 2, EPUB: 1, Fallback: 1, Other: 1}, counts)
+	assert.Equal(t, 7, counts.Total())
+	assert.Equal(t, "1 metadata.json, 1 hybrid, 2 embedded audio, 1 EPUB, 1 fallback, 1 other", counts.String())
+	assert.Equal(t, "3 EPUB", SourceCounts{EPUB: 3}.String())
+}
@@ -0,0 +1,58 @@
+package organizer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MovePlanSeparator separates the source and target of a line of a move plan
+const MovePlanSeparator = " -> "
+
+// WriteMovePlan writes one "SRC -> DST" line per planned file move. Lines are sorted
+// and free of color, sources are relative to the input directory and targets to the
+// output directory, and paths use forward slashes, so plans can be committed to git
+// and diffed between runs or machines. With an Order other than OrderScan the lines
+// keep the order the files would be moved in instead.
+func (o *Organizer) WriteMovePlan(w io.Writer) error {
+	sourceBase := planBase(o.config.BaseDir)
+	targetBase := planBase(o.layoutCalculator.getTargetBase())
+	lines := make([]string, 0, len(o.summary.FileMoves))
+	for _, move := range o.summary.FileMoves {
+		lines = append(lines,
+			planPath(sourceBase, move.From)+MovePlanSeparator+planPath(targetBase, move.To),
+		)
+	}
+	if !o.config.Order.holds() {
+		sort.Strings(lines)
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planBase returns the directory plan paths are relative to: dir itself, or the
+// directory holding it when a single file is organized
+func planBase(dir string) string {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// planPath returns path relative to base with forward slashes, or the whole path
+// when it lies outside base
+func planPath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = path
+	}
+	return filepath.ToSlash(rel)
+}
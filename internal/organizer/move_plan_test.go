@@ -0,0 +1,48 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMovePlan(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	books := map[string]string{
+		"Book B": `{"title":"Zeta","authors":["Jane Doe"]}`,
+		"Book A": `{"title":"Alpha","authors":["Bob Roe"]}`,
+	}
+	for dir, metadata := range books {
+		require.NoError(t, os.MkdirAll(filepath.Join(base, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(base, dir, MetadataFileName), []byte(metadata), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(base, dir, "part 1.mp3"), []byte("x"), 0o644))
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out, DryRun: true})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	var plan bytes.Buffer
+	require.NoError(t, org.WriteMovePlan(&plan))
+	assert.Equal(t, `Book A/metadata.json -> Bob Roe/Alpha/metadata.json
+Book A/part 1.mp3 -> Bob Roe/Alpha/part 1.mp3
+Book B/metadata.json -> Jane Doe/Zeta/metadata.json
+Book B/part 1.mp3 -> Jane Doe/Zeta/part 1.mp3
+`, plan.String())
+}
+
+func TestPlanPath(t *testing.T) {
+	base := filepath.Join("library", "in")
+	assert.Equal(t, "Author/Book/a.mp3", planPath(base, filepath.Join(base, "Author", "Book", "a.mp3")))
+	outside := filepath.Join("elsewhere", "a.mp3")
+	assert.Equal(t, filepath.ToSlash(outside), planPath(base, outside))
+}
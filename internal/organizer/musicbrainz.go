@@ -0,0 +1,337 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMusicBrainzURL is the MusicBrainz web service queried by --musicbrainz. Any
+// mirror answering /ws/2 in the same format can replace it.
+const DefaultMusicBrainzURL = "https://musicbrainz.org"
+
+// musicBrainzInterval keeps requests below the one per second MusicBrainz allows
+const musicBrainzInterval = 1100 * time.Millisecond
+
+// Limits of a MusicBrainz search. Releases scoring below minReleaseScore are other
+// books; only the best few are fetched in full to compare their playing time.
+const (
+	minReleaseScore    = 90
+	maxReleasesFetched = 3
+	durationTolerance  = 0.05 // Fraction of the playing time two copies may differ by
+)
+
+// reEditionSuffix matches a bracketed suffix tags add to a title, such as
+// " (Unabridged)" or " [Dramatized]", which release titles don't carry
+var reEditionSuffix = regexp.MustCompile(`\s*[(\[][^()\[\]]*[)\]]\s*$`)
+
+// ReleaseMatch records a MusicBrainz audiobook release that filled in metadata a
+// book was missing
+type ReleaseMatch struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Series    string   `json:"series,omitempty"` // "Name #N" when the release is numbered
+	Year      int      `json:"year,omitempty"`
+	Publisher string   `json:"publisher,omitempty"`
+	ISBN      string   `json:"isbn,omitempty"`     // From the barcode, when it is one
+	Duration  float64  `json:"duration,omitempty"` // Seconds, the sum of the track lengths
+	Filled    []string `json:"filled"`             // Fields the release filled in
+	Books     int      `json:"books"`              // Books, or files in flat mode, it filled in
+}
+
+// MusicBrainzLookup finds the audiobook release of a book on MusicBrainz by its
+// title and author, and by its playing time when known, as open data alternative to
+// the Audible catalog
+type MusicBrainzLookup struct {
+	Cache   *HTTPCache
+	BaseURL string
+}
+
+// NewMusicBrainzLookup creates a lookup against baseURL (DefaultMusicBrainzURL when
+// empty) through cache
+func NewMusicBrainzLookup(cache *HTTPCache, baseURL string) *MusicBrainzLookup {
+	if baseURL == "" {
+		baseURL = DefaultMusicBrainzURL
+	}
+	return &MusicBrainzLookup{Cache: cache, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// musicBrainzRelease is the part of a MusicBrainz release the lookup reads, as
+// returned by a search or a lookup with recordings and series relationships
+type musicBrainzRelease struct {
+	ID           string `json:"id"`
+	Score        int    `json:"score"`
+	Title        string `json:"title"`
+	Date         string `json:"date"`
+	Barcode      string `json:"barcode"`
+	ArtistCredit []struct {
+		Name string `json:"name"`
+	} `json:"artist-credit"`
+	ReleaseGroup struct {
+		SecondaryTypes []string            `json:"secondary-types"`
+		Relations      []musicBrainzSeries `json:"relations"`
+	} `json:"release-group"`
+	Relations []musicBrainzSeries `json:"relations"`
+	LabelInfo []struct {
+		Label struct {
+			Name string `json:"name"`
+		} `json:"label"`
+	} `json:"label-info"`
+	Media []struct {
+		Tracks []struct {
+			Length int `json:"length"` // Milliseconds
+		} `json:"tracks"`
+	} `json:"media"`
+}
+
+// musicBrainzSeries is a relationship of a release or release group, of which the
+// lookup reads those placing it in a series
+type musicBrainzSeries struct {
+	Type       string `json:"type"`
+	TargetType string `json:"target-type"`
+	Series     struct {
+		Name string `json:"name"`
+	} `json:"series"`
+	Attributes map[string]string `json:"attribute-values"`
+}
+
+// Find returns the audiobook release of metadata's title by its first author. When
+// the book's playing time is known, releases more than durationTolerance longer or
+// shorter are other editions and are skipped, and the closest one wins. It returns
+// ErrHTTPNotFound when no release matches.
+func (l *MusicBrainzLookup) Find(metadata Metadata) (*ReleaseMatch, error) {
+	if metadata.Title == "" || len(metadata.Authors) == 0 {
+		return nil, ErrHTTPNotFound
+	}
+	candidates, err := l.search(metadata.Title, metadata.Authors[0])
+	if err != nil {
+		return nil, err
+	}
+
+	duration := bookDuration(metadata)
+	var best *ReleaseMatch
+	bestDiff := math.Inf(1) // Releases without track lengths rank last
+	for _, candidate := range candidates {
+		if best != nil && duration == 0 {
+			break // Without a playing time the best scoring release wins
+		}
+		release, err := l.release(candidate.ID)
+		if err != nil {
+			return nil, err
+		}
+		match := release.match()
+		diff := math.Inf(1)
+		if duration > 0 && match.Duration > 0 {
+			if diff = math.Abs(match.Duration - duration); diff > duration*durationTolerance {
+				continue
+			}
+		}
+		if best == nil || diff < bestDiff {
+			best, bestDiff = &match, diff
+		}
+	}
+	if best == nil {
+		return nil, ErrHTTPNotFound
+	}
+	return best, nil
+}
+
+// search returns the best scoring audiobook releases of title by author
+func (l *MusicBrainzLookup) search(title, author string) ([]musicBrainzRelease, error) {
+	if bare := reEditionSuffix.ReplaceAllString(title, ""); bare != "" {
+		title = bare
+	}
+	query := url.Values{
+		"query": {fmt.Sprintf(`release:%s AND artist:%s AND secondarytype:audiobook`, luceneQuote(title), luceneQuote(author))},
+		"fmt":   {"json"},
+		"limit": {"10"},
+	}
+	data, err := l.Cache.Get(l.BaseURL+"/ws/2/release/?"+query.Encode(), 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Releases []musicBrainzRelease `json:"releases"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("MusicBrainz search for %q returned invalid JSON: %w", title, err)
+	}
+
+	wantTitle := normalizeForComparison(title, true)
+	wantAuthor := normalizeForComparison(author, false)
+	var candidates []musicBrainzRelease
+	for _, release := range result.Releases {
+		if release.Score < minReleaseScore || !release.isAudiobook() {
+			continue
+		}
+		if normalizeForComparison(release.Title, true) != wantTitle || !release.creditsAuthor(wantAuthor) {
+			continue
+		}
+		if candidates = append(candidates, release); len(candidates) == maxReleasesFetched {
+			break
+		}
+	}
+	return candidates, nil
+}
+
+// release fetches a release with its tracks and series
+func (l *MusicBrainzLookup) release(id string) (musicBrainzRelease, error) {
+	query := url.Values{
+		"inc": {"recordings+labels+release-groups+series-rels+release-group-level-rels"},
+		"fmt": {"json"},
+	}
+	data, err := l.Cache.Get(fmt.Sprintf("%s/ws/2/release/%s?%s", l.BaseURL, url.PathEscape(id), query.Encode()), 4<<20)
+	if err != nil {
+		return musicBrainzRelease{}, err
+	}
+	var release musicBrainzRelease
+	if err := json.Unmarshal(data, &release); err != nil {
+		return musicBrainzRelease{}, fmt.Errorf("MusicBrainz release %s returned invalid JSON: %w", id, err)
+	}
+	return release, nil
+}
+
+// isAudiobook reports whether the release group is an audiobook or audio drama
+func (r musicBrainzRelease) isAudiobook() bool {
+	for _, kind := range r.ReleaseGroup.SecondaryTypes {
+		if strings.EqualFold(kind, "Audiobook") || strings.EqualFold(kind, "Audio drama") {
+			return true
+		}
+	}
+	return false
+}
+
+// creditsAuthor reports whether one of the release's artists is author, normalized
+// by normalizeForComparison, or a similar spelling of it
+func (r musicBrainzRelease) creditsAuthor(author string) bool {
+	for _, credit := range r.ArtistCredit {
+		name := normalizeForComparison(credit.Name, false)
+		if name == author || stringSimilarity(name, author) >= authorSimilarityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns what the release knows about its book
+func (r musicBrainzRelease) match() ReleaseMatch {
+	match := ReleaseMatch{ID: r.ID, Title: r.Title}
+	if len(r.Date) >= 4 {
+		match.Year, _ = strconv.Atoi(r.Date[:4])
+	}
+	if len(r.LabelInfo) > 0 {
+		match.Publisher = r.LabelInfo[0].Label.Name
+	}
+	if barcode := NormalizeISBN(r.Barcode); len(barcode) == 13 && (strings.HasPrefix(barcode, "978") || strings.HasPrefix(barcode, "979")) {
+		match.ISBN = barcode
+	}
+	var milliseconds int
+	for _, medium := range r.Media {
+		for _, track := range medium.Tracks {
+			milliseconds += track.Length
+		}
+	}
+	match.Duration = float64(milliseconds) / 1000
+
+	// The series of the release itself is more specific than that of its group
+	for _, relation := range append(r.Relations, r.ReleaseGroup.Relations...) {
+		if relation.TargetType != "series" || relation.Type != "part of" || relation.Series.Name == "" {
+			continue
+		}
+		match.Series = relation.Series.Name
+		if number := strings.TrimSpace(relation.Attributes["number"]); number != "" {
+			match.Series += " #" + number
+		}
+		break
+	}
+	return match
+}
+
+// luceneQuote quotes s as one phrase of a MusicBrainz search query
+func luceneQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// bookDuration returns the playing time of the whole book, or 0 when only that of
+// the one track the metadata was read from is known
+func bookDuration(metadata Metadata) float64 {
+	if _, total := metadata.RawData["duration"]; metadata.TrackNumber > 0 && !total {
+		return 0
+	}
+	return metadata.GetDuration()
+}
+
+// fillFromMusicBrainz fills the series, year, publisher, and ISBN metadata is
+// missing from its MusicBrainz release, with MusicBrainz set. Tagged values are
+// never replaced. A failure is only a warning; when the service is unreachable the
+// rest of the run answers from the cache.
+func (o *Organizer) fillFromMusicBrainz(metadata Metadata) Metadata {
+	if !o.config.MusicBrainz {
+		return metadata
+	}
+	if metadata.GetValidSeries() != "" && metadata.Year > 0 && metadata.Publisher != "" && metadata.ISBN != "" {
+		return metadata
+	}
+	if o.releaseLookup == nil {
+		cache, err := NewHTTPCache("", o.config.NoNetwork)
+		if err != nil {
+			PrintYellow("⚠️  Warning: MusicBrainz lookup disabled: %v", err)
+			o.config.MusicBrainz = false
+			return metadata
+		}
+		cache.MinInterval = musicBrainzInterval
+		o.releaseLookup = NewMusicBrainzLookup(cache, o.config.MusicBrainzURL)
+	}
+
+	match, err := o.releaseLookup.Find(metadata)
+	switch {
+	case errors.Is(err, ErrHTTPNotFound) || errors.Is(err, errOffline):
+		o.debugLog("No MusicBrainz release found for %s", metadata.Title)
+		return metadata
+	case err != nil:
+		PrintYellow("⚠️  Warning: %v; using cached MusicBrainz lookups only", err)
+		o.releaseLookup.Cache.Offline = true
+		return metadata
+	}
+
+	if metadata.GetValidSeries() == "" && match.Series != "" {
+		metadata.Series = []string{match.Series}
+		match.Filled = append(match.Filled, "series")
+	}
+	if metadata.Year == 0 && match.Year > 0 {
+		metadata.Year = match.Year
+		match.Filled = append(match.Filled, "year")
+	}
+	if metadata.Publisher == "" && match.Publisher != "" {
+		metadata.Publisher = match.Publisher
+		match.Filled = append(match.Filled, "publisher")
+	}
+	if metadata.ISBN == "" && match.ISBN != "" {
+		metadata.ISBN = match.ISBN
+		match.Filled = append(match.Filled, "isbn")
+	}
+	if len(match.Filled) > 0 {
+		o.recordReleaseMatch(*match)
+	}
+	return metadata
+}
+
+// recordReleaseMatch adds a release that filled in metadata to the summary, or counts
+// one more book for a release already there
+func (o *Organizer) recordReleaseMatch(match ReleaseMatch) {
+	for i := range o.summary.ReleasesMatched {
+		if o.summary.ReleasesMatched[i].ID == match.ID {
+			o.summary.ReleasesMatched[i].Books++
+			return
+		}
+	}
+	PrintGreen("🎼 Filled in %s of %s from MusicBrainz release %s", strings.Join(match.Filled, ", "), match.Title, match.ID)
+	match.Books = 1
+	o.summary.ReleasesMatched = append(o.summary.ReleasesMatched, match)
+}
@@ -0,0 +1,121 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMusicBrainzService serves a MusicBrainz search for "Dune" with an unabridged
+// and an abridged audiobook release, and releases the lookup must not pick
+func newMusicBrainzService(t *testing.T) *MusicBrainzLookup {
+	t.Helper()
+	release := func(id, title string, score int, types []string) map[string]any {
+		return map[string]any{
+			"id": id, "score": score, "title": title,
+			"artist-credit": []map[string]string{{"name": "Frank Herbert"}},
+			"release-group": map[string]any{"secondary-types": types},
+		}
+	}
+	tracks := func(hours ...int) []map[string]any {
+		var list []map[string]any
+		for _, h := range hours {
+			list = append(list, map[string]any{"length": h * 3600 * 1000})
+		}
+		return []map[string]any{{"tracks": list}}
+	}
+	details := map[string]map[string]any{
+		"unabridged": {
+			"id": "unabridged", "title": "Dune", "date": "2007-01-16", "barcode": "978-0-441-01359-3",
+			"label-info": []map[string]any{{"label": map[string]string{"name": "Macmillan Audio"}}},
+			"media":      tracks(10, 11),
+			"release-group": map[string]any{"relations": []map[string]any{{
+				"type": "part of", "target-type": "series",
+				"series":           map[string]string{"name": "Dune Chronicles"},
+				"attribute-values": map[string]string{"number": "1"},
+			}}},
+		},
+		"abridged": {"id": "abridged", "title": "Dune", "date": "1993", "media": tracks(3, 3)},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch id := strings.TrimPrefix(r.URL.Path, "/ws/2/release/"); {
+		case id == "" && !strings.HasPrefix(r.URL.Query().Get("query"), `release:"Dune" AND artist:"Frank Herbert" AND`):
+			json.NewEncoder(w).Encode(map[string]any{"releases": []any{}})
+		case id == "":
+			json.NewEncoder(w).Encode(map[string]any{"releases": []map[string]any{
+				release("soundtrack", "Dune", 100, nil),
+				release("unabridged", "Dune", 100, []string{"Audiobook"}),
+				release("messiah", "Dune Messiah", 95, []string{"Audiobook"}),
+				release("abridged", "Dune", 98, []string{"Audiobook"}),
+				release("weak", "Dune", 40, []string{"Audiobook"}),
+			}})
+		case details[id] != nil:
+			assert.Contains(t, r.URL.Query().Get("inc"), "series-rels")
+			json.NewEncoder(w).Encode(details[id])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cache, err := NewHTTPCache(t.TempDir(), false)
+	require.NoError(t, err)
+	return NewMusicBrainzLookup(cache, server.URL)
+}
+
+func TestMusicBrainzLookupFind(t *testing.T) {
+	lookup := newMusicBrainzService(t)
+	book := func(title string, hours float64) Metadata {
+		metadata := Metadata{Title: title, Authors: []string{"Frank Herbert"}}
+		if hours > 0 {
+			metadata.RawData = map[string]interface{}{"duration": hours * 3600}
+		}
+		return metadata
+	}
+
+	match, err := lookup.Find(book("Dune (Unabridged)", 0))
+	require.NoError(t, err)
+	assert.Equal(t, ReleaseMatch{
+		ID: "unabridged", Title: "Dune", Series: "Dune Chronicles #1", Year: 2007,
+		Publisher: "Macmillan Audio", ISBN: "9780441013593", Duration: 21 * 3600,
+	}, *match, "without a playing time the best scoring release wins")
+
+	match, err = lookup.Find(book("Dune", 6.1))
+	require.NoError(t, err)
+	assert.Equal(t, "abridged", match.ID, "the playing time picks the edition")
+
+	_, err = lookup.Find(book("Dune", 12))
+	assert.ErrorIs(t, err, ErrHTTPNotFound, "no edition is 12 hours long")
+	_, err = lookup.Find(book("Children of Dune", 0))
+	assert.ErrorIs(t, err, ErrHTTPNotFound)
+}
+
+func TestFillFromMusicBrainz(t *testing.T) {
+	org := &Organizer{config: OrganizerConfig{MusicBrainz: true}, releaseLookup: newMusicBrainzService(t)}
+
+	tagged := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}, Publisher: "Recorded Books"}
+	var filled Metadata
+	CaptureOutput(func() {
+		filled = org.fillFromMusicBrainz(tagged)
+		org.fillFromMusicBrainz(tagged)
+	})
+	assert.Equal(t, []string{"Dune Chronicles #1"}, filled.Series)
+	assert.Equal(t, 2007, filled.Year)
+	assert.Equal(t, "9780441013593", filled.ISBN)
+	assert.Equal(t, "Recorded Books", filled.Publisher, "tagged values are kept")
+
+	require.Len(t, org.summary.ReleasesMatched, 1)
+	assert.Equal(t, []string{"series", "year", "isbn"}, org.summary.ReleasesMatched[0].Filled)
+	assert.Equal(t, 2, org.summary.ReleasesMatched[0].Books)
+
+	org.config.MusicBrainz = false
+	assert.Equal(t, tagged, org.fillFromMusicBrainz(tagged), "the lookup is off by default")
+}
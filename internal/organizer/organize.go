@@ -12,342 +12,166 @@ import (
 	"time"
 )
 
-// processDirectory is the main entry point for processing each directory during filepath.Walk.
-// It handles both flat and hierarchical processing modes based on configuration.
-func (o *Organizer) processDirectory(path string, info os.FileInfo, err error) error {
-	if err != nil {
-		return o.handleDirectoryError(err, path)
+// organizeLibrary scans root and organizes each book as the scanner finds it. Unless
+// a full scan is requested, directories unchanged since the last run are skipped.
+func (o *Organizer) organizeLibrary(root string) error {
+	options := ScanOptionsFromConfig(&o.config)
+	if o.useScanIndex() {
+		o.scanIndex = LoadScanIndex(o.scanIndexPath(root), o.scanIndexFingerprint(root))
+		options.Index = o.scanIndex
+	}
+
+	scanner := NewScanner(options)
+	err := scanner.Walk(root, ScanHandler{
+		Book:      o.organizeScannedBook,
+		Unmatched: o.handleMissingMetadata,
+		Skipped: func(path string) {
+			o.summary.SkipListed = append(o.summary.SkipListed, path)
+		},
+		Protected: func(protected ProtectedDir) {
+			PrintYellow("🛡️  Skipping %s folder %s (use --allow-protected to organize it)", protected.Server, protected.Path)
+			o.summary.Protected = append(o.summary.Protected, protected)
+		},
+		Ignored: func(dir, marker string) {
+			if o.config.Verbose {
+				PrintBlue("🙈 Skipping %s: it contains %s", dir, marker)
+			}
+		},
+		Deferred: func(deferral Deferral) {
+			o.summary.Deferred = append(o.summary.Deferred, deferral)
+		},
+		LowConfidence: func(held LowConfidence) {
+			o.summary.LowConfidence = append(o.summary.LowConfidence, held)
+		},
+		Unsupported: func(path string) {
+			o.summary.Unsupported = append(o.summary.Unsupported, path)
+		},
+		Error: o.handleBookError,
+	})
+	if err == nil {
+		err = o.confirmHeldPlan()
 	}
-
-	if o.config.Flat {
-		return o.handleFlatMode(path, info, nil)
+	if err == nil {
+		err = o.organizeHeldBooks()
 	}
-
-	return o.handleHierarchicalMode(path, info)
-}
-
-// handleDirectoryError processes errors encountered during directory traversal.
-// It gracefully handles non-existent paths (which may have been moved) and reports other errors.
-func (o *Organizer) handleDirectoryError(err error, path string) error {
-	if os.IsNotExist(err) {
-		if o.config.Verbose {
-			PrintYellow("⏩ Skipping non-existent path (likely moved): %s", path)
-		}
-		return nil
+	if err == nil {
+		o.organizeDiscSets()
+		o.carryUnsupported()
 	}
-	return err
-}
-
-// handleFlatMode processes files in flat mode (each file independently)
-func (o *Organizer) handleFlatMode(path string, info os.FileInfo, err error) error {
-	if err != nil {
+	o.scanReadTime = scanner.Progress().MetadataTime
+	if filtered := scanner.Progress().BooksFiltered; filtered > 0 {
+		PrintBlue("🔎 Left out %d books that don't match the --only filters", filtered)
+	}
+	if err != nil || o.scanIndex == nil {
 		return err
 	}
 
-	// Skip output directory to avoid processing files we just organized
-	if o.config.OutputDir != "" &&
-		(path == o.config.OutputDir || isSubPathOf(o.config.OutputDir, path)) {
-		return nil
+	if skipped := o.scanIndex.Skipped(); skipped > 0 {
+		PrintBlue("⚡ Skipped %d unchanged directories (use --full-scan to read everything)", skipped)
 	}
-
-	// Skip directories in flat mode, but don't skip traversal
-	if info.IsDir() {
-		// We still want to traverse subdirectories to find files
-		// but we don't need to process the directory itself
-		return nil
-	}
-
-	if err := o.processFlatDirectory(path, info); err != nil {
-		if o.config.SkipErrors {
-			PrintYellow("⏩ Skipping %s: %v", filepath.Base(path), err)
-			return nil
+	if !o.config.DryRun {
+		if err := o.scanIndex.Save(); err != nil {
+			PrintYellow("⚠️  Warning: couldn't save scan index: %v", err)
 		}
-		return err
 	}
 	return nil
 }
 
-// handleHierarchicalMode processes directories in standard hierarchical mode,
-// organizing books into nested directory structures based on metadata.
-func (o *Organizer) handleHierarchicalMode(path string, info os.FileInfo) error {
-	if !info.IsDir() {
-		return nil
-	}
-
-	if o.shouldSkipOutputDirectory(path) {
-		return filepath.SkipDir
-	}
-
-	if len(o.config.AllowedSourcePaths) > 0 && !contains(o.config.AllowedSourcePaths, path) {
-		return nil
-	}
+// organizeScannedBook organizes a single book reported by the scanner.
+func (o *Organizer) organizeScannedBook(book Book) error {
+	o.summary.MetadataFound = append(o.summary.MetadataFound, book.MetadataPath)
+	o.summary.Sources.add(book.Metadata)
+	o.rememberSnapshot(book)
+	o.checkAuthorVariant(book.Path, book.Metadata)
 
-	organized, err := o.tryOrganizeWithMetadata(path)
-	if err != nil {
-		PrintRed("❌ Error processing %s: %v", path, err)
+	if o.holdOrderedBook(book) {
 		return nil
 	}
-
-	if organized {
-		return filepath.SkipDir
-	}
-
-	o.handleMissingMetadata(path)
-	return nil
-}
-
-// shouldSkipOutputDirectory checks if the current path is the output directory
-// and should be skipped during processing to avoid recursive organization.
-func (o *Organizer) shouldSkipOutputDirectory(path string) bool {
-	return o.config.OutputDir != "" && path == o.config.OutputDir
+	return o.organizeBook(book)
 }
 
-// handleMissingMetadata logs directories that don't contain any usable metadata.
-func (o *Organizer) handleMissingMetadata(path string) {
-	o.summary.MetadataMissing = append(o.summary.MetadataMissing, path)
-	if o.config.Verbose {
-		PrintYellow("⚠️  No metadata found in %s", path)
+// organizeBook moves a scanned book, or holds it when it is one disc of a split rip
+func (o *Organizer) organizeBook(book Book) error {
+	if o.leftByLimit(book) {
+		return nil
 	}
-}
+	o.profile.beginBook(book.Path, book.readTime)
+	defer o.profile.endBook()
 
-// processFlatDirectory processes a directory in flat mode, scanning for audio files
-// and organizing them individually or as multi-file albums. Also handles special test environments.
-func (o *Organizer) processFlatDirectory(path string, info os.FileInfo) error {
-	// Skip non-directory files when trying to process them as directories
-	if !info.IsDir() {
-		// Check if this is a supported file type before processing
-		ext := strings.ToLower(filepath.Ext(path))
-		if IsSupportedFile(ext) {
-			// Process individual file
-			return o.OrganizeSingleFile(path, nil)
-		} else {
-			// Skip unsupported files silently
-			if o.config.Verbose {
-				PrintYellow("⏩ Skipping unsupported file: %s", path)
-			}
-			return nil
+	if o.config.Flat {
+		if err := o.OrganizeSingleFile(book.Path, book.Provider); err != nil {
+			return o.handleBookError(book.Path, err)
 		}
-	}
-
-	if o.config.Verbose {
-		PrintBlue("🔍 Processing directory in flat mode: %s", path)
-	}
-
-	// Handle test environment first
-	if o.handleTestBookDirectory(path) {
 		return nil
 	}
 
-	// Check if this directory contains multiple audio files that should be treated as an album
-	if o.shouldProcessAsAlbum(path) {
-		return o.ProcessMultiFileAlbum(path)
-	}
-
-	// Process audio files in the directory individually
-	return o.processSupportedFilesInDirectory(path)
-}
-
-// handleTestBookDirectory checks for and processes special test_book directories
-// used in testing environments. Returns true if a test book was found and processed.
-func (o *Organizer) handleTestBookDirectory(path string) bool {
-	testBookDir := filepath.Join(path, TestBookDirName)
-	if !o.fileOps.DirectoryExists(testBookDir) {
-		return false
-	}
-
-	metadataFile := filepath.Join(testBookDir, MetadataFileName)
-	audioFile := filepath.Join(testBookDir, TestAudioFileName)
-
-	if !o.fileOps.AllFilesExist(metadataFile, audioFile) {
-		return false
-	}
-
-	if err := o.processTestBook(metadataFile, audioFile, testBookDir); err != nil {
-		PrintRed("❌ Error processing test book: %v", err)
-	}
-
-	return true
-}
-
-// processTestBook processes a single test book with known metadata.json and audio.mp3 files.
-// This is used for testing and development environments.
-func (o *Organizer) processTestBook(metadataFile, audioFile, testBookDir string) error {
-	metadata, err := o.readMetadataFromJSON(metadataFile)
-	if err != nil {
-		return fmt.Errorf("error reading metadata from JSON: %w", err)
-	}
-
-	targetDir := o.calculateTestBookTargetDir(metadata)
-	targetAudioPath := filepath.Join(targetDir, metadata.Title+".mp3")
-
-	if o.config.Verbose {
-		message := o.formatTestBookMove(audioFile, targetAudioPath)
-		fmt.Println(message)
-	}
-
-	if o.config.DryRun {
+	if o.holdDiscBook(book) {
 		return nil
 	}
 
-	if err := o.fileOps.CreateDirIfNotExists(targetDir); err != nil {
-		return fmt.Errorf("error creating target directory: %w", err)
+	switch book.Source {
+	case BookSourceEPUB:
+		PrintGreen("📚 Found metadata in EPUB file: %s", book.MetadataPath)
+	case BookSourceAudio:
+		PrintGreen("🔊 Found metadata in audio file: %s", book.MetadataPath)
 	}
 
-	if err := o.moveFile(audioFile, targetAudioPath); err != nil {
-		return fmt.Errorf("error moving file: %w", err)
+	if err := o.OrganizeAudiobook(book.Path, book.Provider); err != nil {
+		return o.handleBookError(book.Path, err)
 	}
-
-	o.summary.Moves = append(o.summary.Moves, MoveSummary{
-		From: testBookDir,
-		To:   targetDir,
-	})
-
 	return nil
 }
 
-// calculateTestBookTargetDir calculates the target directory for test books
-// based on author and series information from metadata.
-func (o *Organizer) calculateTestBookTargetDir(metadata Metadata) string {
-	author := metadata.GetFirstAuthor("Unknown")
-
-	if validSeries := metadata.GetValidSeries(); validSeries != "" {
-		return filepath.Join(o.config.OutputDir, author, validSeries)
-	}
-
-	return filepath.Join(o.config.OutputDir, author)
-}
-
-// processAudioFilesInDirectory should be renamed to processSupportedFilesInDirectory
-// and updated to handle all supported file types in flat mode
-func (o *Organizer) processSupportedFilesInDirectory(path string) error {
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return fmt.Errorf("error reading directory: %w", err)
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			if o.config.Verbose {
-				PrintYellow(
-					"⏩ Skipping subdirectory in flat mode: %s",
-					filepath.Join(path, entry.Name()),
-				)
-			}
-			continue
-		}
-
-		filePath := filepath.Join(path, entry.Name())
-		ext := strings.ToLower(filepath.Ext(filePath))
-
-		// Clean, centralized check for supported file types
-		if IsSupportedFile(ext) {
-			if err := o.OrganizeSingleFile(filePath, nil); err != nil {
-				PrintRed("❌ Error organizing file %s: %v", filePath, err)
-			}
-		} else if o.config.Verbose {
-			PrintYellow("⏩ Skipping unsupported file type: %s", filePath)
-		}
+// checkAuthorVariant records a book for the author spelling check when it is enabled
+func (o *Organizer) checkAuthorVariant(path string, metadata Metadata) {
+	if !o.config.CheckAuthors {
+		return
 	}
-
-	return nil
-}
-
-// tryOrganizeWithMetadata attempts to organize a directory using available metadata sources.
-// It tries embedded metadata first (if enabled), then falls back to JSON metadata files.
-func (o *Organizer) tryOrganizeWithMetadata(path string) (bool, error) {
-	if o.config.UseEmbeddedMetadata {
-		if organized, err := o.tryEmbeddedMetadata(path); organized || err != nil {
-			return organized, err
-		}
+	if o.authorVariants == nil {
+		o.authorVariants = NewAuthorVariantDetector()
 	}
-
-	return o.tryJSONMetadata(path)
+	o.authorVariants.Add(path, o.applyAuthorAliases(metadata))
 }
 
-// tryEmbeddedMetadata attempts to extract and use metadata embedded within files.
-// It tries EPUB files first, then audio files as fallback options.
-func (o *Organizer) tryEmbeddedMetadata(path string) (bool, error) {
-	// Try EPUB first
-	if organized, err := o.tryEPUBMetadata(path); organized || err != nil {
-		return organized, err
-	}
-
-	// Try audio files as fallback
-	return o.tryAudioMetadata(path)
-}
+// handleBookError records a failed book. Hierarchical runs, and books that failed
+// after retries, always continue with the next book; flat runs stop otherwise
+// unless SkipErrors is set.
+func (o *Organizer) handleBookError(path string, err error) error {
+	// Retry the book on the next incremental scan
+	o.scanIndex.Invalidate(path)
 
-// tryEPUBMetadata attempts to extract metadata from EPUB files in the directory
-// and organize the audiobook based on that metadata.
-func (o *Organizer) tryEPUBMetadata(path string) (bool, error) {
-	epubPath, err := FindEPUBInDirectory(path)
-	if err != nil {
-		if o.config.Verbose {
-			PrintYellow("⚠️ No EPUB files found in %s", path)
-		}
-		return false, nil
+	// Metadata naming an unsafe path is reported, not an error that could stop a run
+	if o.quarantine(path, err) {
+		return nil
 	}
 
-	epubProvider := NewEPUBMetadataProvider(epubPath)
-	metadata, err := epubProvider.GetMetadata()
-
-	if err != nil || !metadata.IsValid() {
-		if o.config.Verbose {
-			PrintYellow("⚠️ EPUB found but metadata extraction failed: %s", epubPath)
-		}
-		return false, nil
+	// A network file system that kept failing shouldn't stop the books after it
+	if o.recordRetriesExhausted(path, err) || !o.config.Flat {
+		o.recordError("❌ Error processing %s: %v", path, err)
+		return nil
 	}
 
-	PrintGreen("📚 Found metadata in EPUB file: %s", epubPath)
-	if err := o.OrganizeAudiobook(path, epubProvider); err != nil {
-		return false, fmt.Errorf("error organizing with EPUB metadata: %v", err)
+	if o.config.SkipErrors {
+		PrintYellow("⏩ Skipping %s: %v", filepath.Base(path), err)
+		o.summary.Errors = append(o.summary.Errors, fmt.Sprintf("%s: %v", path, err))
+		return nil
 	}
-
-	return true, nil
+	return err
 }
 
-// tryAudioMetadata attempts to extract metadata from audio files in the directory
-// and organize the audiobook based on that metadata.
-func (o *Organizer) tryAudioMetadata(path string) (bool, error) {
-	audioPath, err := FindAudioFileInDirectory(path)
-	if err != nil {
-		if o.config.Verbose {
-			PrintYellow("⚠️ No supported audio files found in %s", path)
-		}
-		return false, nil
-	}
-
-	audioProvider := NewAudioMetadataProvider(audioPath)
-	metadata, err := audioProvider.GetMetadata()
-
-	if err != nil || !metadata.IsValid() {
-		if o.config.Verbose {
-			PrintYellow("⚠️ Audio file found but metadata extraction failed: %s", audioPath)
-		}
-		return false, nil
-	}
-
-	PrintGreen("🔊 Found metadata in audio file: %s", audioPath)
-	if err := o.OrganizeAudiobook(path, audioProvider); err != nil {
-		return false, fmt.Errorf("error organizing with audio metadata: %v", err)
-	}
-
-	return true, nil
+// recordError prints an error and records it in the summary so the run can report
+// that it completed with errors.
+func (o *Organizer) recordError(format string, a ...interface{}) {
+	PrintRed(format, a...)
+	o.summary.Errors = append(o.summary.Errors, StripDecorations(fmt.Sprintf(format, a...)))
 }
 
-// tryJSONMetadata attempts to find and use a metadata.json file in the directory
-// for organizing the audiobook.
-func (o *Organizer) tryJSONMetadata(path string) (bool, error) {
-	metadataPath := filepath.Join(path, MetadataFileName)
-	if !o.fileOps.FileExists(metadataPath) {
-		return false, nil
-	}
-
-	o.summary.MetadataFound = append(o.summary.MetadataFound, metadataPath)
-	if err := o.OrganizeAudiobook(path, NewJSONMetadataProvider(metadataPath)); err != nil {
-		return false, fmt.Errorf("error organizing with JSON metadata: %v", err)
+// handleMissingMetadata logs directories that don't contain any usable metadata.
+func (o *Organizer) handleMissingMetadata(path string) {
+	o.summary.MetadataMissing = append(o.summary.MetadataMissing, path)
+	if o.config.Verbose {
+		PrintYellow("⚠️  No metadata found in %s", path)
 	}
-
-	return true, nil
 }
 
 // OrganizeAudiobook is the main function for organizing a complete audiobook directory.
@@ -363,14 +187,17 @@ func (o *Organizer) OrganizeAudiobook(sourcePath string, provider MetadataProvid
 	if err := metadata.Validate(); err != nil {
 		return err
 	}
+	o.recordIdentifiers(sourcePath, metadata)
 
+	planned := o.profile.enter(PhasePlanning)
 	targetPath, err := o.layoutCalculator.CalculateTargetPathE(metadata)
+	planned()
 	if err != nil {
 		return fmt.Errorf("error calculating target path: %w", err)
 	}
 
 	if o.isAlreadyInCorrectLocation(sourcePath, targetPath) {
-		return nil
+		return o.renameInPlace(sourcePath, &metadata)
 	}
 
 	if o.shouldSkipMove(metadata, sourcePath, targetPath) {
@@ -380,24 +207,46 @@ func (o *Organizer) OrganizeAudiobook(sourcePath string, provider MetadataProvid
 	return o.executeMove(sourcePath, targetPath, &metadata)
 }
 
+// renameInPlace renames the files of a book already in its target directory whose
+// names changed. Files with equivalent names are left alone, so a book that is
+// fully organized is not touched at all.
+func (o *Organizer) renameInPlace(sourcePath string, metadata *Metadata) error {
+	entries, err := os.ReadDir(sourcePath)
+	if err != nil {
+		return fmt.Errorf("error reading source directory: %w", err)
+	}
+	planned := o.profile.enter(PhasePlanning)
+	fileNames, _ := o.planDirectoryFiles(entries, sourcePath, metadata)
+	planned()
+	for _, file := range o.keepEquivalentNames(fileNames) {
+		if file.From != file.To {
+			return o.executeMove(sourcePath, sourcePath, metadata)
+		}
+	}
+	return nil
+}
+
 // prepareMetadata extracts metadata from a provider and applies field mapping
 // configuration to ensure proper title, author, and series assignment.
 func (o *Organizer) prepareMetadata(provider MetadataProvider) (Metadata, error) {
+	defer o.profile.enter(PhaseMetadata)()
+
 	metadata, err := ExtractMappedMetadata(provider, o.config.FieldMapping)
 	if err != nil {
 		return Metadata{}, fmt.Errorf("error getting metadata: %w", err)
 	}
 
-	return metadata, nil
+	return o.fillFromMusicBrainz(o.canonicalizeAuthors(o.applyAuthorAliases(metadata))), nil
 }
 
 // isAlreadyInCorrectLocation checks if the source path is already the same as
-// the calculated target path, avoiding unnecessary moves.
+// the calculated target path, or differs only in equivalent names (see
+// NamingPolicy.Equivalent), avoiding unnecessary moves.
 func (o *Organizer) isAlreadyInCorrectLocation(sourcePath, targetPath string) bool {
 	cleanSourcePath := filepath.Clean(sourcePath)
 	cleanTargetPath := filepath.Clean(targetPath)
 
-	if cleanSourcePath == cleanTargetPath {
+	if cleanSourcePath == cleanTargetPath || o.isEquivalentPath(cleanSourcePath, cleanTargetPath) {
 		if o.config.Verbose {
 			PrintGreen("✅ Book already in correct location: %s", cleanSourcePath)
 		}
@@ -406,6 +255,23 @@ func (o *Organizer) isAlreadyInCorrectLocation(sourcePath, targetPath string) bo
 	return false
 }
 
+// isEquivalentPath reports whether two clean paths have the same number of
+// components and every pair of components is equivalent under the naming policy
+func (o *Organizer) isEquivalentPath(a, b string) bool {
+	aParts := strings.Split(a, string(filepath.Separator))
+	bParts := strings.Split(b, string(filepath.Separator))
+	if len(aParts) != len(bParts) {
+		return false
+	}
+	naming := o.naming()
+	for i := range aParts {
+		if !naming.Equivalent(aParts[i], bParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // shouldSkipMove determines if a move operation should be skipped based on
 // user prompts or other configuration settings.
 func (o *Organizer) shouldSkipMove(metadata Metadata, sourcePath, targetPath string) bool {
@@ -419,13 +285,23 @@ func (o *Organizer) shouldSkipMove(metadata Metadata, sourcePath, targetPath str
 // executeMove performs the actual file moving operation for an audiobook directory,
 // including logging and cleanup of empty directories.
 func (o *Organizer) executeMove(sourcePath, targetPath string, metadata *Metadata) error {
-	fileNames, err := o.moveFiles(sourcePath, targetPath, metadata)
+	defer o.profile.enter(PhaseMoving)()
+
+	if o.sourceChanged(sourcePath) {
+		return nil
+	}
+	targetPath, bookName := o.singleFileBookTarget(sourcePath, targetPath)
+	fileNames, err := o.moveFiles(sourcePath, targetPath, bookName, metadata)
 	if err != nil {
 		return err
 	}
 
 	if !o.config.DryRun {
 		o.updateLogAndCleanup(sourcePath, targetPath, fileNames)
+		o.writeIdentifiers(targetPath, *metadata)
+		if bookName == "" {
+			o.fetchCover(targetPath, *metadata)
+		}
 	}
 
 	return nil
@@ -452,8 +328,11 @@ func (o *Organizer) OrganizeSingleFile(filePath string, provider MetadataProvide
 	if err := metadata.Validate(); err != nil {
 		return err
 	}
+	o.recordIdentifiers(filePath, metadata)
 
+	planned := o.profile.enter(PhasePlanning)
 	targetPath, err := o.calculateSingleFileTargetPathE(filePath, metadata)
+	planned()
 	if err != nil {
 		return fmt.Errorf("error calculating target path: %w", err)
 	}
@@ -472,6 +351,7 @@ func (o *Organizer) OrganizeSingleFile(filePath string, provider MetadataProvide
 // StaticMetadataProvider returns caller-supplied metadata for a known source path.
 type StaticMetadataProvider struct {
 	metadata Metadata
+	mapped   bool
 }
 
 // NewStaticMetadataProvider creates a metadata provider from already-loaded metadata.
@@ -479,6 +359,13 @@ func NewStaticMetadataProvider(metadata Metadata) *StaticMetadataProvider {
 	return &StaticMetadataProvider{metadata: metadata}
 }
 
+// NewMappedMetadataProvider creates a metadata provider for metadata the field mapping
+// was already applied to, with edits made after it, which the mapping is not applied
+// to again
+func NewMappedMetadataProvider(metadata Metadata) *StaticMetadataProvider {
+	return &StaticMetadataProvider{metadata: metadata, mapped: true}
+}
+
 // GetMetadata returns the static metadata.
 func (p *StaticMetadataProvider) GetMetadata() (Metadata, error) {
 	return p.metadata, nil
@@ -493,10 +380,17 @@ func (o *Organizer) OrganizePathWithMetadata(sourcePath string, metadata Metadat
 
 	provider := NewStaticMetadataProvider(metadata)
 	o.summary.MetadataFound = append(o.summary.MetadataFound, sourcePath)
+	o.summary.Sources.add(metadata)
+	o.checkAuthorVariant(sourcePath, metadata)
 	if info.IsDir() {
-		return o.OrganizeAudiobook(sourcePath, provider)
+		err = o.OrganizeAudiobook(sourcePath, provider)
+	} else {
+		err = o.OrganizeSingleFile(sourcePath, provider)
+	}
+	if err != nil && o.quarantine(sourcePath, err) {
+		return nil
 	}
-	return o.OrganizeSingleFile(sourcePath, provider)
+	return err
 }
 
 // calculateSingleFileTargetPath determines the complete target path for a single file
@@ -514,11 +408,14 @@ func (o *Organizer) calculateSingleFileTargetPathE(
 	if err != nil {
 		return "", err
 	}
-	targetFileName := filepath.Base(filePath)
+	if o.layoutCalculator.flattensSingleFiles() && o.isWholeBookFile(filePath, metadata) {
+		return o.layoutCalculator.SingleFilePath(targetDir, filepath.Base(filePath)), nil
+	}
+	namer := o.fileNamer()
 	if ShouldAddTrackPrefix(metadata.TrackNumber, TrackTotalFromMetadata(metadata)) {
-		targetFileName = AddTrackPrefix(targetFileName, metadata.TrackNumber)
+		namer = namer.WithTrackPrefix(metadata.TrackNumber)
 	}
-	return filepath.Join(targetDir, targetFileName), nil
+	return filepath.Join(targetDir, namer.Normalize(filepath.Base(filePath))), nil
 }
 
 // calculateSingleFileTargetDir determines the target directory for a single file
@@ -534,21 +431,31 @@ func (o *Organizer) calculateSingleFileTargetDirE(
 ) (string, error) {
 	baseDir := o.getBaseDirForSingleFile(filePath)
 
-	if strings.TrimSpace(o.config.LayoutTemplate) != "" {
+	// Book folders follow the layout exactly as a book directory's would
+	if strings.TrimSpace(o.config.LayoutTemplate) != "" || o.config.BookFolder {
 		return o.layoutCalculator.CalculateTargetPathInBaseE(metadata, baseDir)
 	}
 
+	return safeTargetPath(metadata, baseDir, func() (string, error) {
+		return o.singleFileTargetDir(metadata, baseDir), nil
+	})
+}
+
+// singleFileTargetDir builds the directory of a single file below baseDir per a
+// built-in layout
+func (o *Organizer) singleFileTargetDir(metadata Metadata, baseDir string) string {
 	// Use PathBuilder for cleaner path construction
+	metadata = PathMetadata(metadata, o.config.Casing, o.config.Subtitle, o.config.StripTitlePrefix)
 	pathBuilder := NewPathBuilder().WithSanitizer(o.SanitizePath)
 
 	switch o.config.Layout {
 	case "author-only":
-		return pathBuilder.AddAuthor(strings.Join(metadata.Authors, ",")).Build(baseDir), nil
+		return pathBuilder.AddAuthor(strings.Join(metadata.Authors, ",")).Build(baseDir)
 	case "author-title":
 		return pathBuilder.
 			AddAuthor(strings.Join(metadata.Authors, ",")).
 			AddTitle(metadata.Title).
-			Build(baseDir), nil
+			Build(baseDir)
 	case "author-series-title", "":
 		pathBuilder.AddAuthor(strings.Join(metadata.Authors, ","))
 		if validSeries := metadata.GetValidSeries(); validSeries != "" {
@@ -561,12 +468,12 @@ func (o *Organizer) calculateSingleFileTargetDirE(
 			// No series, just add the title
 			pathBuilder.AddTitle(metadata.Title)
 		}
-		return pathBuilder.Build(baseDir), nil
+		return pathBuilder.Build(baseDir)
 	default:
 		return pathBuilder.
 			AddAuthor(strings.Join(metadata.Authors, ",")).
 			AddTitle(metadata.Title).
-			Build(baseDir), nil
+			Build(baseDir)
 	}
 }
 
@@ -582,23 +489,37 @@ func (o *Organizer) getBaseDirForSingleFile(filePath string) string {
 // executeSingleFileMove performs the actual moving of a single file, including
 // directory creation, dry-run handling, and logging.
 func (o *Organizer) executeSingleFileMove(filePath, targetPath string, metadata Metadata) error {
+	defer o.profile.enter(PhaseMoving)()
+
+	if o.sourceChanged(filePath) {
+		return nil
+	}
 	targetDir := filepath.Dir(targetPath)
 
 	if err := o.fileOps.CreateDirIfNotExists(targetDir); err != nil {
 		return fmt.Errorf("error creating target directory: %w", err)
 	}
 
+	if err := o.checkFileSizes([]FilePair{{From: filePath}}); err != nil {
+		return err
+	}
+
+	sourceDir := filepath.Dir(filePath)
+	companions := o.ebookCompanions(filePath, filepath.Base(targetPath))
 	if o.config.DryRun {
-		message := o.formatDryRunMove(filePath, targetPath)
-		fmt.Println(message)
+		o.printFileLine(sourceDir, o.formatDryRunMove(filePath, targetPath))
 		// Add to summary even in dry-run mode
 		o.addSingleFileMoveToSummary(filePath, targetPath)
+		for _, companion := range companions {
+			from, to := filepath.Join(sourceDir, companion.From), filepath.Join(targetDir, companion.To)
+			o.printFileLine(sourceDir, o.formatDryRunMove(from, to))
+			o.recordFileMove(from, to)
+		}
 		return nil
 	}
 
 	if o.config.Verbose {
-		message := o.formatVerboseMove(filePath, targetPath)
-		fmt.Println(message)
+		o.printFileLine(filepath.Dir(filePath), o.formatVerboseMove(filePath, targetPath))
 	}
 
 	if err := o.moveFile(filePath, targetPath); err != nil {
@@ -607,32 +528,89 @@ func (o *Organizer) executeSingleFileMove(filePath, targetPath string, metadata
 	}
 
 	o.addSingleFileMoveToSummary(filePath, targetPath)
-	originalName := filepath.Base(filePath)
-	targetName := filepath.Base(targetPath)
-	o.updateLogAndCleanup(
-		filepath.Dir(filePath),
-		filepath.Dir(targetPath),
-		[]FilePair{{From: originalName, To: targetName}},
-	)
+	fileNames := []FilePair{{From: filepath.Base(filePath), To: filepath.Base(targetPath)}}
+	for _, companion := range companions {
+		from, to := filepath.Join(sourceDir, companion.From), filepath.Join(targetDir, companion.To)
+		if err := o.moveFile(from, to); err != nil {
+			o.recordError("❌ Error moving %s: %v", from, err)
+			continue
+		}
+		o.recordFileMove(from, to)
+		fileNames = append(fileNames, companion)
+	}
+	o.writeIdentifiers(targetDir, metadata)
+	if o.singleFileHasFolder(filePath, metadata) {
+		o.fetchCover(targetDir, metadata)
+	}
+	o.updateLogAndCleanup(sourceDir, targetDir, fileNames)
 
 	return nil
 }
 
+// ebookCovers are the folder-wide cover and metadata files an ebook manager such as
+// Calibre keeps next to a book, matched ignoring case
+var ebookCovers = map[string]bool{"cover.jpg": true, "cover.jpeg": true, "cover.png": true, "metadata.opf": true}
+
+// ebookCompanions returns the files that move with an ebook in Ebooks mode, named
+// for targetName: sidecars sharing its basename, such as "Dune.jpg" or "Dune.opf",
+// and the folder's cover image and metadata.opf when it is the folder's only book.
+// A folder once seen with several books keeps its cover after the others moved.
+func (o *Organizer) ebookCompanions(filePath, targetName string) []FilePair {
+	if !o.config.Ebooks {
+		return nil
+	}
+	dir := filepath.Dir(filePath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	ebookName := filepath.Base(filePath)
+	names := fileEntryNames(entries)
+	for _, name := range names {
+		if name != ebookName && o.config.Extensions.IsOrganized(filepath.Ext(name)) {
+			if o.sharedEbookDirs == nil {
+				o.sharedEbookDirs = make(map[string]bool)
+			}
+			o.sharedEbookDirs[dir] = true
+		}
+	}
+	only := !o.sharedEbookDirs[dir] && filepath.Clean(dir) != filepath.Clean(o.config.BaseDir)
+
+	var companions []FilePair
+	for _, name := range names {
+		ext := filepath.Ext(name)
+		if name == ebookName || o.config.Extensions.IsOrganized(ext) || o.config.Extensions.skips(name) {
+			continue
+		}
+		if o.config.Extensions.IsCompanion(ext) || strings.EqualFold(ext, ".opf") {
+			if suffix, ok := companionSuffix(ebookName, name); ok {
+				companions = append(companions, FilePair{From: name, To: CompanionTargetName(targetName, suffix)})
+				continue
+			}
+		}
+		if only && ebookCovers[strings.ToLower(name)] {
+			companions = append(companions, FilePair{From: name, To: name})
+		}
+	}
+	return companions
+}
+
 // addSingleFileMoveToSummary adds a single file move operation to the summary.
 func (o *Organizer) addSingleFileMoveToSummary(filePath, targetPath string) {
 	o.summary.Moves = append(o.summary.Moves, MoveSummary{
 		From: filePath,
 		To:   targetPath,
 	})
+	o.recordFileMove(filePath, targetPath)
 }
 
-// String formatting functions - return formatted strings instead of directly printing
-
-// formatTestBookMove returns a formatted string for test book move operations.
-func (o *Organizer) formatTestBookMove(audioFile, targetAudioPath string) string {
-	return fmt.Sprintf("📦 Moving %s to %s", audioFile, targetAudioPath)
+// recordFileMove adds one file to the move plan
+func (o *Organizer) recordFileMove(from, to string) {
+	o.summary.FileMoves = append(o.summary.FileMoves, MoveSummary{From: from, To: to})
 }
 
+// String formatting functions - return formatted strings instead of directly printing
+
 // formatDryRunMove returns a formatted string for dry-run move operations.
 func (o *Organizer) formatDryRunMove(filePath, targetPath string) string {
 	coloredPath := o.formatColoredPath(filePath, targetPath)
@@ -659,7 +637,7 @@ func (o *Organizer) formatColoredSourcePath(filePath string) string {
 
 	inputFilename := filepath.Base(filePath)
 	inputDir := filepath.Dir(filePath)
-	result.WriteString(inputDir + "/")
+	result.WriteString(inputDir + string(filepath.Separator))
 
 	trackPrefixRegex := regexp.MustCompile(`^(\d+)\s*-\s*`)
 	if matches := trackPrefixRegex.FindStringSubmatch(inputFilename); len(matches) > 1 {
@@ -694,19 +672,19 @@ func (o *Organizer) formatPathComponentsWithColors(pathParts []string) string {
 
 	// Series
 	if len(pathParts) > 2 {
-		result.WriteString("/")
+		result.WriteRune(filepath.Separator)
 		result.WriteString(SeriesColor(pathParts[1]))
 	}
 
 	// Title
 	if len(pathParts) > 3 {
-		result.WriteString("/")
+		result.WriteRune(filepath.Separator)
 		result.WriteString(TitleColor(pathParts[2]))
 	}
 
 	// Filename
 	if len(pathParts) > 0 {
-		result.WriteString("/")
+		result.WriteRune(filepath.Separator)
 		filename := pathParts[len(pathParts)-1]
 		result.WriteString(o.formatColoredFilename(filename))
 	}
@@ -763,10 +741,10 @@ func (o *Organizer) logMetadataIfVerbose(metadata Metadata, provider MetadataPro
 	}
 
 	providerIcon, providerType := getProviderTypeDisplay(provider)
-	fmt.Printf("\n%s Found %s\n", providerIcon, providerType)
+	fmt.Fprintf(output, "\n%s Found %s\n", providerIcon, providerType)
 	formatter := NewMetadataFormatter(metadata, o.config.FieldMapping)
-	fmt.Print(formatter.FormatMetadataWithMapping())
-	fmt.Println()
+	fmt.Fprint(output, formatter.FormatMetadataWithMapping())
+	fmt.Fprintln(output)
 }
 
 // getProviderTypeDisplay returns appropriate icon and description for different metadata providers.
@@ -776,6 +754,8 @@ func getProviderTypeDisplay(provider MetadataProvider) (string, string) {
 		return "📋", "JSON metadata file"
 	case *EPUBMetadataProvider:
 		return "📚", "EPUB embedded metadata"
+	case *EbookMetadataProvider:
+		return "📚", "Ebook embedded metadata"
 	case *AudioMetadataProvider:
 		return "🎵", "Audio embedded metadata"
 	case *FileMetadataProvider:
@@ -800,34 +780,73 @@ func (o *Organizer) getMetadataProvider(filePath string) (MetadataProvider, erro
 	case ".epub":
 		// Track metadata file in summary
 		o.summary.MetadataFound = append(o.summary.MetadataFound, filePath)
+		o.summary.Sources.EPUB++
 		return NewEPUBMetadataProvider(filePath), nil
-	case ".mp3", ".m4b", ".m4a":
+	case ".mobi", ".azw", ".azw3", ".fb2", ".pdf":
+		if !o.config.Extensions.IsOrganized(ext) {
+			return nil, fmt.Errorf("unsupported file type: %s", ext)
+		}
+		o.summary.MetadataFound = append(o.summary.MetadataFound, filePath)
+		o.summary.Sources.Ebook++
+		return NewEbookMetadataProvider(filePath), nil
+	default:
+		if !o.config.Extensions.IsAudio(ext) {
+			return nil, fmt.Errorf("unsupported file type: %s", ext)
+		}
 		// Track metadata file in summary
 		o.summary.MetadataFound = append(o.summary.MetadataFound, filePath)
+		o.summary.Sources.Audio++
 		return NewAudioMetadataProvider(filePath), nil
-	default:
-		return nil, fmt.Errorf("unsupported file type: %s", ext)
 	}
 }
 
 // updateLogAndCleanup records the move operation in logs and cleans up empty directories.
 func (o *Organizer) updateLogAndCleanup(sourcePath, targetPath string, fileNames []FilePair) {
-	o.logEntries = append(o.logEntries, LogEntry{
+	o.appendLogEntry(LogEntry{
 		Timestamp:  time.Now(),
 		SourcePath: sourcePath,
 		TargetPath: targetPath,
 		Files:      fileNames,
 	})
+}
 
+// appendLogEntry adds an entry to the undo log and saves it
+func (o *Organizer) appendLogEntry(entry LogEntry) {
+	o.recordLoggedFiles(entry)
+	o.writeProvenance(&entry)
+	o.noteSeriesDir(entry.TargetPath)
+	o.logEntries = append(o.logEntries, entry)
 	if err := o.saveLog(); err != nil {
 		PrintYellow("⚠️  Warning: couldn't save log: %v", err)
 	}
 }
 
+// recordLoggedFiles notes the size of each file of a log entry at its target, and
+// with LogChecksums its checksum, so VerifyLogEntries can tell whether undo will
+// find the file as it was left
+func (o *Organizer) recordLoggedFiles(entry LogEntry) {
+	for i := range entry.Files {
+		file := &entry.Files[i]
+		target := filepath.Join(entry.TargetPath, file.To)
+		info, err := o.target.Stat(target)
+		if err != nil {
+			o.debugLog("Couldn't record the size of %s: %v", target, err)
+			continue
+		}
+		file.Size = info.Size()
+		// Remote files would have to be downloaded again to hash them
+		if o.config.LogChecksums && !o.hasRemoteTarget() {
+			if file.SHA256, err = fileSHA256(target); err != nil {
+				o.debugLog("Couldn't record the checksum of %s: %v", target, err)
+			}
+		}
+	}
+}
+
 // readMetadataFromJSON reads and processes metadata from a JSON file,
 // applying field mapping configuration.
 func (o *Organizer) readMetadataFromJSON(filePath string) (Metadata, error) {
-	provider := NewJSONMetadataProvider(filePath)
+	provider := newJSONMetadataProvider(filePath, o.config.StrictJSON)
 	metadata, err := provider.GetMetadata()
 	if err != nil {
 		return Metadata{}, err
@@ -904,7 +923,7 @@ func (o *Organizer) cleanEmptyParents(dir string, stopAt string) error {
 		// but don't go beyond the input directory
 		if parentDir != o.config.BaseDir {
 			if err := o.cleanEmptyParents(parentDir, o.config.BaseDir); err != nil {
-				PrintRed("❌ Error cleaning parent directories: %v", err)
+				o.recordError("❌ Error cleaning parent directories: %v", err)
 			}
 		}
 	}
@@ -915,26 +934,11 @@ func (o *Organizer) cleanEmptyParents(dir string, stopAt string) error {
 
 // isSubPathOf checks if a child path is a subdirectory of a parent path.
 func isSubPathOf(parent, child string) bool {
-	parent = filepath.Clean(parent)
-	child = filepath.Clean(child)
-
-	// Split both paths into components
-	parentParts := strings.Split(parent, string(filepath.Separator))
-	childParts := strings.Split(child, string(filepath.Separator))
-
-	// Child path must be longer than parent path
-	if len(childParts) <= len(parentParts) {
+	rel, err := filepath.Rel(filepath.Clean(parent), filepath.Clean(child))
+	if err != nil || rel == "." || rel == ".." {
 		return false
 	}
-
-	// Check if child starts with parent
-	for i := range parentParts {
-		if parentParts[i] != childParts[i] {
-			return false
-		}
-	}
-
-	return true
+	return !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // isEmptyDir is defined in organizer.go - removing duplicate
@@ -956,14 +960,28 @@ func (o *Organizer) moveFile(source, target string) error {
 
 	o.debugLog("moveFile: source=%s, target=%s", source, target)
 
+	// Remote targets always upload through a staged transaction, which also links
+	// rather than moves files that must stay in place for seeding
+	if o.hasRemoteTarget() || o.keepsSources([]FilePair{{From: source}}) {
+		return o.moveBookFiles(
+			filepath.Dir(target),
+			[]FilePair{{From: source, To: filepath.Base(target)}},
+		)
+	}
+
 	// Create target directory if it doesn't exist
 	targetDir := filepath.Dir(target)
 	if err := o.fileOps.CreateDirIfNotExists(targetDir); err != nil {
 		return fmt.Errorf("error creating target directory: %w", err)
 	}
 
+	// Keep whatever is already at the target when a trash directory is configured
+	if err := o.discardExisting(target); err != nil {
+		return fmt.Errorf("error moving existing target to trash: %w", err)
+	}
+
 	// Try to use os.Rename first (most efficient)
-	err := os.Rename(source, target)
+	err := o.retry(source, func() error { return os.Rename(source, target) })
 	if err != nil {
 		// If rename fails (e.g., cross-device link), fall back to copy and delete
 		o.debugLog("Rename failed, falling back to copy and delete: %v", err)
@@ -975,7 +993,24 @@ func (o *Organizer) moveFile(source, target string) error {
 }
 
 // copyAndDeleteFile performs a copy-and-delete operation when os.Rename fails.
+// The copy is retried on transient errors; the source is only removed once.
 func (o *Organizer) copyAndDeleteFile(source, target, targetDir string) error {
+	if err := o.retry(source, func() error { return o.copyVerified(source, target) }); err != nil {
+		return err
+	}
+
+	// Remove source file
+	if err := o.discard(source); err != nil {
+		return fmt.Errorf("error removing source file: %w", err)
+	}
+	o.debugLog("Successfully removed source file %s", source)
+
+	// Sync the target directory to ensure all changes are written to disk
+	return o.syncTargetDirectory(targetDir)
+}
+
+// copyVerified copies source to target and checks the copy reached the disk whole
+func (o *Organizer) copyVerified(source, target string) error {
 	sourceFile, err := os.Open(source)
 	if err != nil {
 		return fmt.Errorf("error opening source file: %w", err)
@@ -1002,18 +1037,22 @@ func (o *Organizer) copyAndDeleteFile(source, target, targetDir string) error {
 	}
 	o.debugLog("Successfully wrote %d bytes to target file %s", n, target)
 
-	// Remove source file
-	if err := os.Remove(source); err != nil {
-		return fmt.Errorf("error removing source file: %w", err)
+	// Only give up the source once the copy is verified on disk
+	if err := targetFile.Sync(); err != nil {
+		return fmt.Errorf("error syncing target file: %w", err)
 	}
-	o.debugLog("Successfully removed source file %s", source)
-
-	// Sync the target directory to ensure all changes are written to disk
-	return o.syncTargetDirectory(targetDir)
+	if info, err := os.Stat(target); err != nil || info.Size() != int64(len(data)) {
+		return fmt.Errorf("copy verification failed for %s; source left in place", target)
+	}
+	return nil
 }
 
-// syncTargetDirectory ensures that directory changes are written to disk.
+// syncTargetDirectory ensures that directory changes are written to disk. Remote
+// targets are left to the server.
 func (o *Organizer) syncTargetDirectory(targetDir string) error {
+	if o.hasRemoteTarget() {
+		return nil
+	}
 	targetDirFile, err := os.Open(targetDir)
 	if err != nil {
 		return fmt.Errorf("error opening target directory: %w", err)
@@ -1028,9 +1067,11 @@ func (o *Organizer) syncTargetDirectory(targetDir string) error {
 }
 
 // moveFiles moves all files from a source directory to a target directory,
-// handling track number prefixes and maintaining a list of moved files.
+// handling track number prefixes and maintaining a list of moved files. A
+// non-empty bookName names the audio file of a single-file book moved without
+// its folder (see singleFileBookTarget).
 func (o *Organizer) moveFiles(
-	sourcePath, targetPath string,
+	sourcePath, targetPath, bookName string,
 	dirMetadata *Metadata,
 ) ([]FilePair, error) {
 	if o.config.Verbose {
@@ -1043,29 +1084,32 @@ func (o *Organizer) moveFiles(
 		return nil, fmt.Errorf("error reading source directory: %w", err)
 	}
 
-	// Create target directory if it doesn't exist
-	if err := o.fileOps.CreateDirIfNotExists(targetPath); err != nil {
-		return nil, fmt.Errorf("error creating target directory: %w", err)
-	}
-
-	o.summary.Moves = append(o.summary.Moves, MoveSummary{
-		From: sourcePath,
-		To:   targetPath,
-	})
-
 	// Get metadata if not provided
 	if dirMetadata == nil {
 		dirMetadata = o.getDirectoryMetadata(sourcePath)
 	}
 
-	return o.processDirectoryFiles(entries, sourcePath, targetPath, dirMetadata)
+	fileNames, err := o.processBookFiles(entries, sourcePath, targetPath, bookName, dirMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	to := targetPath
+	if bookName != "" {
+		to = filepath.Join(targetPath, bookName)
+	}
+	o.summary.Moves = append(o.summary.Moves, MoveSummary{
+		From: sourcePath,
+		To:   to,
+	})
+	return fileNames, nil
 }
 
 // getDirectoryMetadata attempts to load metadata from a metadata.json file in the directory.
 func (o *Organizer) getDirectoryMetadata(sourcePath string) *Metadata {
 	metadataPath := filepath.Join(sourcePath, MetadataFileName)
 	if o.fileOps.FileExists(metadataPath) {
-		provider := NewJSONMetadataProvider(metadataPath)
+		provider := newJSONMetadataProvider(metadataPath, o.config.StrictJSON)
 		if md, err := provider.GetMetadata(); err == nil {
 			md.ApplyFieldMapping(o.config.FieldMapping) // Changed from 'metadata' to 'md'
 			return &md
@@ -1075,36 +1119,151 @@ func (o *Organizer) getDirectoryMetadata(sourcePath string) *Metadata {
 }
 
 // processDirectoryFiles processes individual files in a directory for moving.
+// The files of a book are moved as one transaction: either all of them reach the
+// target directory or the source directory is left as it was.
 func (o *Organizer) processDirectoryFiles(
 	entries []os.DirEntry,
 	sourcePath, targetPath string,
 	dirMetadata *Metadata,
 ) ([]FilePair, error) {
-	var fileNames []FilePair
+	return o.processBookFiles(entries, sourcePath, targetPath, "", dirMetadata)
+}
+
+// processBookFiles is processDirectoryFiles for a single-file book whose audio file
+// is renamed to bookName when it is not empty
+func (o *Organizer) processBookFiles(
+	entries []os.DirEntry,
+	sourcePath, targetPath, bookName string,
+	dirMetadata *Metadata,
+) ([]FilePair, error) {
+	var moves []FilePair
+	planned := o.profile.enter(PhasePlanning)
+	fileNames, hidden := o.planDirectoryFiles(entries, sourcePath, dirMetadata)
+	if filepath.Clean(sourcePath) == filepath.Clean(targetPath) {
+		fileNames = o.keepEquivalentNames(fileNames)
+	}
+	if bookName != "" {
+		fileNames = o.nameAfterBook(fileNames, bookName)
+	} else {
+		fileNames = o.mergeIntoExisting(sourcePath, targetPath, fileNames)
+	}
+	for _, file := range fileNames {
+		sourceName := filepath.Join(sourcePath, file.From)
+		targetFullPath := filepath.Join(targetPath, file.To)
+		if sourceName != targetFullPath && (o.config.Verbose || o.config.DryRun) {
+			o.printFileLine(sourcePath, o.formatFileMove(sourceName, targetFullPath, o.config.DryRun))
+		}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue // Skip subdirectories
+		moves = append(moves, FilePair{From: sourceName, To: file.To})
+	}
+	o.endFileLines()
+	planned()
+
+	if err := o.checkFileSizes(moves); err != nil {
+		return nil, err
+	}
+
+	if !o.config.DryRun && len(moves) > 0 {
+		if err := o.moveBookFiles(targetPath, moves); err != nil {
+			return nil, fmt.Errorf("error moving book, source left untouched: %w", err)
+		}
+		if o.hiddenFilePolicy() == HiddenFilesDelete {
+			o.deleteHiddenFiles(sourcePath, hidden)
 		}
+	}
+	o.deleteByExtension(sourcePath, fileEntryNames(entries))
 
-		sourceName := filepath.Join(sourcePath, entry.Name())
-		targetName := o.calculateFileTargetName(sourcePath, entry.Name(), dirMetadata)
-		targetFullPath := filepath.Join(targetPath, targetName)
-		fileNames = append(fileNames, FilePair{From: entry.Name(), To: targetName})
+	for _, name := range hidden {
+		o.summary.HiddenFiles = append(o.summary.HiddenFiles, filepath.Join(sourcePath, name))
+	}
+	for _, move := range moves {
+		if target := filepath.Join(targetPath, move.To); target != move.From {
+			o.recordFileMove(move.From, target)
+		}
+	}
 
-		if o.config.Verbose || o.config.DryRun {
-			message := o.formatFileMove(sourceName, targetFullPath, o.config.DryRun)
-			fmt.Println(message)
+	return fileNames, nil
+}
+
+// planDirectoryFiles names the files of a book directory in their target directory.
+// Subdirectories are skipped, and hidden files are returned separately unless they
+// move with HiddenFilesMove.
+func (o *Organizer) planDirectoryFiles(
+	entries []os.DirEntry,
+	sourcePath string,
+	dirMetadata *Metadata,
+) (fileNames []FilePair, hidden []string) {
+	return o.planBookFiles(entries, sourcePath, dirMetadata, discTracks{})
+}
+
+// planBookFiles is planDirectoryFiles for one disc of a book merged from several disc
+// folders when the disc number is positive. Every audio file of a disc gets a
+// disc-aware track prefix, or a continuous one with ContinuousTracks, numbered by
+// position when it has no track number.
+func (o *Organizer) planBookFiles(
+	entries []os.DirEntry,
+	sourcePath string,
+	dirMetadata *Metadata,
+	disc discTracks,
+) (fileNames []FilePair, hidden []string) {
+	policy := o.hiddenFilePolicy()
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || o.config.Extensions.skips(entry.Name()) {
+			continue
 		}
+		if IsHiddenFile(entry.Name()) {
+			hidden = append(hidden, entry.Name())
+			if policy != HiddenFilesMove {
+				continue
+			}
+		}
+		names = append(names, entry.Name())
+	}
+	companions := o.config.Extensions.MatchCompanions(names)
 
-		if !o.config.DryRun {
-			if err := o.moveFile(sourceName, targetFullPath); err != nil {
-				PrintRed("❌ Error moving %s: %v", sourceName, err)
+	positions := make(map[string]int)
+	for _, name := range names {
+		if o.config.Extensions.IsAudio(filepath.Ext(name)) {
+			positions[name] = len(positions) + 1
+		}
+	}
+	normalizer := func(name string) *FilenameNormalizer {
+		normalizer := o.fileNormalizer(sourcePath, name, dirMetadata)
+		if disc.number > 0 && positions[name] > 0 {
+			if !normalizer.addTrackPrefix {
+				normalizer = normalizer.WithTrackPrefix(positions[name])
+			}
+			if disc.width > 0 {
+				normalizer = normalizer.WithContinuousTrack(disc.offset, disc.width)
+			} else {
+				normalizer = normalizer.WithDisc(disc.number)
 			}
 		}
+		return normalizer
 	}
 
-	return fileNames, nil
+	for _, name := range names {
+		targetName := normalizer(name).Normalize(name)
+		if audioName, ok := companions[name]; ok {
+			// Sidecars take the name their audio file gets so they stay associated
+			targetName = normalizer(audioName).NormalizeCompanion(name, audioName)
+		}
+		fileNames = append(fileNames, FilePair{From: name, To: targetName})
+	}
+	return fileNames, hidden
+}
+
+// keepEquivalentNames keeps the current name of each file of a book that stays in
+// its directory when the planned name is equivalent to it
+func (o *Organizer) keepEquivalentNames(fileNames []FilePair) []FilePair {
+	naming := o.naming()
+	for i, file := range fileNames {
+		if naming.Equivalent(file.From, file.To) {
+			fileNames[i].To = file.From
+		}
+	}
+	return fileNames
 }
 
 // calculateFileTargetName determines the target filename, adding track prefixes when appropriate.
@@ -1112,43 +1271,65 @@ func (o *Organizer) calculateFileTargetName(
 	sourcePath, fileName string,
 	dirMetadata *Metadata,
 ) string {
-	// Use the FilenameNormalizer for consistent processing
-	normalizer := NewFilenameNormalizer()
+	return o.fileNormalizer(sourcePath, fileName, dirMetadata).Normalize(fileName)
+}
 
-	if IsSupportedAudioFile(filepath.Ext(fileName)) {
-		trackNumber, trackTotal := o.resolveFileTrackMetadata(sourcePath, fileName, dirMetadata)
+// fileNormalizer configures the FilenameNormalizer for one file of a book
+func (o *Organizer) fileNormalizer(
+	sourcePath, fileName string,
+	dirMetadata *Metadata,
+) *FilenameNormalizer {
+	normalizer := o.fileNamer()
+
+	if o.config.Extensions.IsAudio(filepath.Ext(fileName)) {
+		trackNumber, trackTotal, trackTitle := o.resolveFileTrackMetadata(sourcePath, fileName, dirMetadata)
 		if ShouldAddTrackPrefix(trackNumber, trackTotal) {
-			normalizer = normalizer.WithTrackPrefix(trackNumber)
+			normalizer = normalizer.WithTrackPrefix(trackNumber).WithTrackTitle(trackTitle)
 		}
 	}
 
-	// Apply space replacement if configured
-	if o.config.ReplaceSpace != "" {
-		normalizer = normalizer.WithSpaceReplacement(o.config.ReplaceSpace)
-	}
-
-	return normalizer.Normalize(fileName)
+	return normalizer
 }
 
 // resolveFileTrackMetadata prefers embedded per-file track metadata over book-level values.
+// The track title is only resolved when files are named after their track titles.
 func (o *Organizer) resolveFileTrackMetadata(
 	sourcePath, fileName string,
 	dirMetadata *Metadata,
-) (trackNumber, trackTotal int) {
-	if IsSupportedAudioFile(filepath.Ext(fileName)) {
+) (trackNumber, trackTotal int, trackTitle string) {
+	if o.config.Extensions.IsAudio(filepath.Ext(fileName)) {
 		filePath := filepath.Join(sourcePath, fileName)
-		if fileMetadata, err := extractFileLevelMetadata(filePath); err == nil {
+		read := o.profile.enter(PhaseMetadata)
+		fileMetadata, err := extractFileLevelMetadata(filePath)
+		read()
+		if err == nil {
+			trackTitle = o.fileTrackTitle(fileMetadata, dirMetadata)
 			trackNumber = fileMetadata.TrackNumber
 			trackTotal = TrackTotalFromMetadata(fileMetadata)
 			if trackNumber > 0 {
-				return trackNumber, trackTotal
+				return trackNumber, trackTotal, trackTitle
 			}
 		}
 	}
 
 	if dirMetadata != nil {
-		return dirMetadata.TrackNumber, TrackTotalFromMetadata(*dirMetadata)
+		return dirMetadata.TrackNumber, TrackTotalFromMetadata(*dirMetadata), trackTitle
 	}
 
-	return 0, 0
+	return 0, 0, trackTitle
+}
+
+// fileTrackTitle returns the chapter name of one file of a book for --track-titles,
+// or "" when it is disabled or the file's title only repeats the book's title
+func (o *Organizer) fileTrackTitle(fileMetadata Metadata, dirMetadata *Metadata) string {
+	if !o.config.TrackTitles {
+		return ""
+	}
+	fileMetadata.ApplyFieldMapping(FieldMapping{TrackTitleField: o.config.FieldMapping.TrackTitleField})
+	trackTitle := strings.TrimSpace(fileMetadata.TrackTitle)
+	if dirMetadata != nil &&
+		(strings.EqualFold(trackTitle, dirMetadata.Title) || strings.EqualFold(trackTitle, dirMetadata.Album)) {
+		return ""
+	}
+	return trackTitle
 }
@@ -7,7 +7,7 @@ import (
 	"testing"
 )
 
-func TestProcessDirectory(t *testing.T) {
+func TestOrganizeLibrary(t *testing.T) {
 	tests := []struct {
 		name          string
 		flat          bool
@@ -90,16 +90,7 @@ func TestProcessDirectory(t *testing.T) {
 			}
 			testPath := tt.setupFunc(t, tempDir)
 
-			// Get file info (might not exist for error testing)
-			var info os.FileInfo
-			var pathErr error
-			if stat, err := os.Stat(testPath); err == nil {
-				info = stat
-			} else {
-				pathErr = err
-			}
-
-			err = org.processDirectory(testPath, info, pathErr)
+			err = org.organizeLibrary(testPath)
 
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -110,30 +101,13 @@ func TestProcessDirectory(t *testing.T) {
 	}
 }
 
-func TestHandleDirectoryError(t *testing.T) {
-	tempDir, err := os.MkdirTemp("", "error_test")
-	if err != nil {
-		t.Fatalf("Failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	config := OrganizerConfig{
-		BaseDir:      tempDir,
-		DryRun:       true,
-		Verbose:      true,
-		FieldMapping: DefaultFieldMapping(),
-	}
-
-	org, err := NewOrganizer(&config)
-	if err != nil {
-		t.Fatalf("NewOrganizer() error = %v", err)
-	}
-
+func TestScannerHandleWalkError(t *testing.T) {
 	tests := []struct {
-		name        string
-		inputError  error
-		path        string
-		expectError bool
+		name           string
+		inputError     error
+		path           string
+		skipUnreadable bool
+		expectError    bool
 	}{
 		{
 			name:        "nonexistent file should not error",
@@ -147,6 +121,13 @@ func TestHandleDirectoryError(t *testing.T) {
 			path:        "/restricted/file.mp3",
 			expectError: true,
 		},
+		{
+			name:           "permission error skipped when unreadable paths are skipped",
+			inputError:     os.ErrPermission,
+			path:           "/restricted/file.mp3",
+			skipUnreadable: true,
+			expectError:    false,
+		},
 		{
 			name:        "other errors should be returned",
 			inputError:  os.ErrInvalid,
@@ -157,7 +138,8 @@ func TestHandleDirectoryError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := org.handleDirectoryError(tt.inputError, tt.path)
+			scanner := NewScanner(ScanOptions{SkipUnreadable: tt.skipUnreadable})
+			err := scanner.handleWalkError(tt.path, tt.inputError)
 
 			if tt.expectError && err == nil {
 				t.Error("Expected error to be returned")
@@ -562,6 +544,24 @@ func TestIsSubPathOf(t *testing.T) {
 			child:    "/parent-similar",
 			expected: false,
 		},
+		{
+			name:     "child of the root",
+			parent:   "/",
+			child:    "/parent",
+			expected: true,
+		},
+		{
+			name:     "trailing separator on parent",
+			parent:   "/parent/",
+			child:    "/parent/child",
+			expected: true,
+		},
+		{
+			name:     "name starting with dots",
+			parent:   "/parent",
+			child:    "/parent/..hidden",
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -641,6 +641,40 @@ func TestCalculateFileTargetNameUsesPerFileTrackNumbers(t *testing.T) {
 	}
 }
 
+func TestProcessDirectoryFilesRenamesCompanionsWithTrack(t *testing.T) {
+	bookDir := t.TempDir()
+	for _, name := range []string{"Chapter 3.mp3", "Chapter 3.cue", "Chapter 3.en.srt", "cover.jpg"} {
+		if err := os.WriteFile(filepath.Join(bookDir, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	entries, err := os.ReadDir(bookDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: bookDir, DryRun: true})
+	if err != nil {
+		t.Fatalf("NewOrganizer() error = %v", err)
+	}
+	pairs, err := org.processDirectoryFiles(entries, bookDir, t.TempDir(), &Metadata{TrackNumber: 3})
+	if err != nil {
+		t.Fatalf("processDirectoryFiles() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Chapter 3.mp3":    "03 - Chapter 3.mp3",
+		"Chapter 3.cue":    "03 - Chapter 3.cue",
+		"Chapter 3.en.srt": "03 - Chapter 3.en.srt",
+		"cover.jpg":        "cover.jpg",
+	}
+	for _, pair := range pairs {
+		if want[pair.From] != pair.To {
+			t.Errorf("%s → %s, want %s", pair.From, pair.To, want[pair.From])
+		}
+	}
+}
+
 func TestCalculateSingleFileTargetPathSkipsSingleTrackPrefix(t *testing.T) {
 	tempDir := t.TempDir()
 	outputDir := filepath.Join(tempDir, "output")
@@ -679,6 +713,75 @@ func TestCalculateSingleFileTargetPathSkipsSingleTrackPrefix(t *testing.T) {
 	}
 }
 
+func TestOrganizeAudiobookIsIdempotent(t *testing.T) {
+	metadata := Metadata{Title: "Zeta Book", Authors: []string{"Jane Doe"}, TrackNumber: 3}
+
+	tests := []struct {
+		name      string
+		bookDir   string // Where the book starts, below the library
+		fileName  string
+		wantDir   string
+		wantFile  string
+		wantMoves int
+	}{
+		{
+			name:      "organized with the same options",
+			bookDir:   filepath.Join("Jane_Doe", "Zeta_Book"),
+			fileName:  "03_-_Chapter_3.mp3",
+			wantDir:   filepath.Join("Jane_Doe", "Zeta_Book"),
+			wantFile:  "03_-_Chapter_3.mp3",
+			wantMoves: 0,
+		},
+		{
+			name:      "organized before spaces were replaced",
+			bookDir:   filepath.Join("Jane Doe", "Zeta Book"),
+			fileName:  "03 - Chapter 3.mp3",
+			wantDir:   filepath.Join("Jane Doe", "Zeta Book"),
+			wantFile:  "03 - Chapter 3.mp3",
+			wantMoves: 0,
+		},
+		{
+			name:      "in place but missing its track prefix",
+			bookDir:   filepath.Join("Jane_Doe", "Zeta_Book"),
+			fileName:  "Chapter_3.mp3",
+			wantDir:   filepath.Join("Jane_Doe", "Zeta_Book"),
+			wantFile:  "03_-_Chapter_3.mp3",
+			wantMoves: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			library := t.TempDir()
+			bookDir := filepath.Join(library, tt.bookDir)
+			if err := os.MkdirAll(bookDir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(bookDir, tt.fileName), []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			org, err := NewOrganizer(&OrganizerConfig{BaseDir: library, ReplaceSpace: "_"})
+			if err != nil {
+				t.Fatalf("NewOrganizer() error = %v", err)
+			}
+			CaptureOutput(func() {
+				err = org.OrganizePathWithMetadata(bookDir, metadata)
+			})
+			if err != nil {
+				t.Fatalf("OrganizePathWithMetadata() error = %v", err)
+			}
+
+			if _, err := os.Stat(filepath.Join(library, tt.wantDir, tt.wantFile)); err != nil {
+				t.Errorf("want %s in %s: %v", tt.wantFile, tt.wantDir, err)
+			}
+			if got := len(org.GetSummary().FileMoves); got != tt.wantMoves {
+				t.Errorf("got %d file moves, want %d", got, tt.wantMoves)
+			}
+		})
+	}
+}
+
 // Helper function to detect Windows (for tests that need to skip on Windows)
 func isWindows() bool {
 	return strings.Contains(strings.ToLower(os.Getenv("OS")), "windows")
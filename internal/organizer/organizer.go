@@ -2,24 +2,25 @@
 package organizer
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/fatih/color"
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
+	"github.com/jeeftor/audiobook-organizer/internal/planning"
 )
 
 // Constants
 const (
-	LogFileName        = ".abook-org.log"
-	TestBookDirName    = "test_book"
-	MetadataFileName   = "metadata.json"
-	TestAudioFileName  = "audio.mp3"
-	TrackPrefixFormat  = "%02d - "
-	InvalidSeriesValue = "__INVALID_SERIES__"
+	LogFileName       = ".abook-org.log"
+	TestBookDirName   = "test_book"
+	MetadataFileName  = "metadata.json"
+	TestAudioFileName = "audio.mp3"
 )
 
 // OrganizerConfig contains all configuration parameters for an Organizer
@@ -33,13 +34,74 @@ type OrganizerConfig struct {
 	Prompt              bool
 	RemoveEmpty         bool
 	UseEmbeddedMetadata bool
+	StrictJSON          bool // Parse metadata.json as standard JSON only (see UnmarshalMetadataJSON)
 	Flat                bool
+	Ebooks              bool   // Organize an ebook library: every EPUB, MOBI, AZW3, FB2, or PDF file is a book; implies Flat
+	MinDepth            int    // Books lie at least this many levels below BaseDir (see ScanOptions.MinDepth)
+	MaxDepth            int    // Folders deeper than this many levels below BaseDir are not scanned (0 = no limit)
 	SkipErrors          bool   // Skip files with missing/invalid metadata instead of stopping
 	Layout              string // Directory structure layout (author-series-title, author-title, author-only)
 	LayoutTemplate      string // Custom directory layout template overriding Layout when set
 	AuthorFormat        string
-	FieldMapping        FieldMapping // Configuration for mapping metadata fields
-	AllowedSourcePaths  []string     // When non-empty, only process book dirs whose path is in this list
+	Casing              string           // Path component casing: "preserve" (default), "title", or "sentence"
+	Subtitle            string           // How the subtitle joins the title in paths: "keep" (default), "colon", "dash", or "drop"
+	StripTitlePrefix    bool             // Drop a leading author or series name and number the other fields repeat from the title folder
+	FieldMapping        FieldMapping     // Configuration for mapping metadata fields
+	AllowedSourcePaths  []string         // When non-empty, only process book dirs whose path is in this list
+	Filter              BookFilter       // Only organize books matching these paths, authors, and title
+	TrashDir            string           // When set, overwritten or deleted files are moved here instead
+	SFTPIdentityFile    string           // Private key for sftp:// output; defaults to ~/.ssh keys and ssh-agent
+	SFTPKnownHostsFile  string           // known_hosts file for sftp:// output; defaults to ~/.ssh/known_hosts
+	FullScan            bool             // Read every directory instead of skipping those unchanged since the last run
+	CheckAuthors        bool             // Report titles found under several similar author spellings
+	LogPath             string           // Undo log location; defaults to DefaultLogPath of the output directory
+	LogChecksums        bool             // Record a SHA-256 of every moved file in the undo log for VerifyLogEntries
+	MinFileAge          time.Duration    // Defer books with a file modified more recently than this
+	SizeSettle          time.Duration    // Defer books whose size changes over this interval
+	MinConfidence       float64          // Hold back books whose embedded or file metadata scores below this (0 = off)
+	SeedSafe            bool             // Hardlink or copy books instead of moving them, so torrents keep seeding
+	TorrentDirs         []string         // With SeedSafe, only books referenced by torrent data here are kept in place
+	AuthorLookup        bool             // Look authors up in an external authority and suggest canonical names
+	ApplyAuthorLookup   bool             // Use the suggested names when building paths (pseudonyms excepted)
+	AuthorAuthorityURL  string           // Authority queried by AuthorLookup; defaults to DefaultAuthorAuthorityURL
+	NoNetwork           bool             // Never make network requests; AuthorLookup answers from its cache only
+	AuthorAliases       []AuthorAlias    // Pen names shelved under another author folder
+	WriteIdentifiers    bool             // Write identifiers.json with the ISBN and ASIN next to each organized book
+	SeriesReadme        bool             // Write SERIES.md into each series folder a run adds books to
+	KeepProvenance      bool             // Write the original folder name and leftover text files to original-folder.txt in each organized book
+	Strict              bool             // Refuse files too large for a FAT32 output instead of warning
+	HiddenFiles         HiddenFilePolicy // What happens to dotfiles and system files in book directories; "" skips them
+	TrackTitles         bool             // Name the tracks of multi-file books "NN - <track title>" from their own tags
+	MergeDiscs          bool             // Merge sibling "Book CD1", "Book CD2" folders with matching tags into one book
+	ContinuousTracks    bool             // Number the tracks of merged discs on from one disc to the next ("13 - ") instead of by disc ("2-01 - ")
+	ContinuousTrackTags bool             // Also write the continuous track numbers into the MP3 tags of merged discs
+	AllowProtectedDirs  bool             // Organize inside media server folders (Audiobookshelf metadata, Plex, Calibre) too
+	Order               BookOrder        // Order books are organized in; "" organizes each as the scan finds it
+	Limit               int              // Organize at most this many books per run, in Order (0 = no limit)
+	Summary             SummaryMode      // How much of the end-of-run summary is printed; "" prints everything
+	FileLines           int              // Per-file lines printed for each book before the rest are coalesced; 0 prints all
+	ProgressInterval    time.Duration    // How often a book with coalesced file lines prints a count; 0 uses DefaultProgressInterval
+	Locale              string           // Locale names are sorted and filed under (see NewCollation); "" uses the root collation order
+	Language            string           // Language of the run summary (see i18n.New); "" is English
+	DetailLog           io.Writer        // Receives every per-file line, including those coalesced on screen
+	Extensions          ExtensionPolicy  // Per-extension organize, companion, ignore, or delete rules; nil is the built-in handling
+	Retries             int              // Times a file operation failing with a transient error, like EIO or ESTALE on NFS or SMB, is retried
+	RetryDelay          time.Duration    // Wait before the first retry, doubled for each later one; 0 uses DefaultRetryDelay
+	LockWait            time.Duration    // How long to wait for another run to release the library lock; 0 fails at once
+	ForceUnlock         bool             // Remove the library lock left by another run before taking it
+	ProfileReport       bool             // Time metadata reading, planning, and moving per book into Summary.Profile
+	SingleFileLayout    SingleFileLayout // Whether books of one audio file get a folder; "" gives them one
+	BookFolder          bool             // Give every book a folder of its own, named after its title where the layout has none
+	FetchCovers         bool             // Download cover art for books without one into their folder
+	MusicBrainz         bool             // Fill missing series, year, publisher, and ISBN from the book's MusicBrainz audiobook release
+	MusicBrainzURL      string           // Web service queried by MusicBrainz; defaults to DefaultMusicBrainzURL
+	CarryUnsupported    int64            // Flat mode: move unsupported files up to this many bytes with the book beside them; 0 leaves them in place
+	Merge               MergePolicy      // How a book moves into a folder that already holds files; "" overwrites files of the same name
+
+	// ConfirmPlan is called with the warnings of a real run's plan when it looks
+	// destructive, under the library lock and before any book moves. An error stops
+	// the run; nil runs without checking.
+	ConfirmPlan func([]PlanWarning) error
 }
 
 // Validate checks if the configuration is valid and returns helpful error messages
@@ -78,8 +140,19 @@ func (c *OrganizerConfig) Validate() error {
 		)
 	}
 
-	// If output directory is specified, validate it
-	if c.OutputDir != "" {
+	// Remote outputs are checked when the organizer connects
+	if IsRemoteOutput(c.OutputDir) {
+		if c.TrashDir != "" {
+			return fmt.Errorf("--trash-dir is not supported with a remote output directory")
+		}
+		if c.Undo {
+			return fmt.Errorf(
+				"undo is not supported with a remote output directory\n\nThe operation log is kept in the input directory, but files can't be moved back from %s",
+				c.OutputDir,
+			)
+		}
+	} else if c.OutputDir != "" {
+		// If output directory is specified, validate it
 		// Check if output directory exists or can be created
 		if _, err := os.Stat(c.OutputDir); err != nil {
 			if os.IsNotExist(err) && !c.DryRun {
@@ -119,6 +192,78 @@ func (c *OrganizerConfig) Validate() error {
 		)
 	}
 
+	if !planning.ValidSubtitleStyle(c.Subtitle) {
+		return fmt.Errorf(
+			"invalid subtitle style: %s\n\nValid options are:\n  keep (default)\n  colon\n  dash\n  drop",
+			c.Subtitle,
+		)
+	}
+
+	if !planning.ValidCasing(c.Casing) {
+		return fmt.Errorf(
+			"invalid casing: %s\n\nValid options are:\n  preserve (default)\n  title\n  sentence",
+			c.Casing,
+		)
+	}
+
+	if _, err := ParseHiddenFilePolicy(string(c.HiddenFiles)); err != nil {
+		return err
+	}
+	if _, err := ParseSummaryMode(string(c.Summary)); err != nil {
+		return err
+	}
+	if _, err := ParseBookOrder(string(c.Order)); err != nil {
+		return err
+	}
+	if c.ContinuousTracks && !c.MergeDiscs {
+		return fmt.Errorf("--continuous-tracks requires --merge-discs")
+	}
+	if c.ContinuousTrackTags && !c.ContinuousTracks {
+		return fmt.Errorf("--continuous-track-tags requires --continuous-tracks")
+	}
+	if _, err := ParseMergePolicy(string(c.Merge)); err != nil {
+		return err
+	}
+	if c.CarryUnsupported < 0 {
+		return fmt.Errorf("invalid carry-unsupported size %d: use 0 to leave unsupported files in place", c.CarryUnsupported)
+	}
+	if c.CarryUnsupported > 0 && !c.Flat {
+		return fmt.Errorf("--carry-unsupported requires --flat")
+	}
+	if c.Limit < 0 {
+		return fmt.Errorf("invalid limit %d: use 0 for no limit", c.Limit)
+	}
+	if layout, err := ParseSingleFileLayout(string(c.SingleFileLayout)); err != nil {
+		return err
+	} else if layout == SingleFileFile && c.BookFolder {
+		return fmt.Errorf("--book-folder and --single-file-layout=file can't be combined: one gives every book a folder, the other moves single-file books without one")
+	}
+	if _, err := NewCollation(c.Locale); err != nil {
+		return err
+	}
+	if _, err := i18n.New(c.Language); err != nil {
+		return err
+	}
+
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("min-confidence must be between 0 and 1, got: %g", c.MinConfidence)
+	}
+	if c.Retries < 0 {
+		return fmt.Errorf("retries must not be negative, got: %d", c.Retries)
+	}
+	if c.LockWait < 0 {
+		return fmt.Errorf("wait must not be negative, got: %s", c.LockWait)
+	}
+	if c.RetryDelay < 0 {
+		return fmt.Errorf("retry-delay must not be negative, got: %s", c.RetryDelay)
+	}
+	if c.FileLines < 0 {
+		return fmt.Errorf("file-lines must not be negative, got: %d", c.FileLines)
+	}
+	if err := ValidateScanDepth(c.MinDepth, c.MaxDepth); err != nil {
+		return err
+	}
+
 	// Validate replace_space character (should be single char or empty)
 	if len(c.ReplaceSpace) > 1 {
 		return fmt.Errorf(
@@ -133,6 +278,7 @@ func (c *OrganizerConfig) Validate() error {
 // FileOps handles file system operations with dry-run support
 type FileOps struct {
 	dryRun bool
+	target TargetFS // Where target directories are created; nil means the local disk
 }
 
 // NewFileOps creates a new file operations handler
@@ -145,6 +291,9 @@ func (f *FileOps) CreateDirIfNotExists(dir string) error {
 	if f.dryRun {
 		return nil
 	}
+	if f.target != nil {
+		return f.target.MkdirAll(dir)
+	}
 	return os.MkdirAll(dir, 0o755)
 }
 
@@ -174,6 +323,7 @@ func (f *FileOps) AllFilesExist(files ...string) bool {
 type LayoutCalculator struct {
 	config    *OrganizerConfig
 	sanitizer func(string) string
+	collation *Collation // Files {author_initial} folders by the config's locale
 }
 
 // NewLayoutCalculator creates a new layout calculator
@@ -181,6 +331,7 @@ func NewLayoutCalculator(config *OrganizerConfig, sanitizer func(string) string)
 	return &LayoutCalculator{
 		config:    config,
 		sanitizer: sanitizer,
+		collation: collationFor(config.Locale),
 	}
 }
 
@@ -200,112 +351,50 @@ func (lc *LayoutCalculator) CalculateTargetPathInBaseE(
 	metadata Metadata,
 	targetBase string,
 ) (string, error) {
-	if strings.TrimSpace(lc.config.LayoutTemplate) != "" {
-		return lc.calculateCustomTemplatePath(metadata, targetBase)
-	}
-
-	authorDir := lc.sanitizer(strings.Join(metadata.Authors, ","))
-	titleDir := lc.sanitizer(metadata.Title)
-
-	switch lc.config.Layout {
-	case "author-only":
-		return filepath.Join(targetBase, authorDir), nil
-	case "author-series":
-		// Author/Series layout (no title subdirectory)
-		// Used for multi-file audiobooks where each file is a chapter
-		if validSeries := metadata.GetValidSeries(); validSeries != "" {
-			seriesDir := lc.sanitizer(validSeries)
-			return filepath.Join(targetBase, authorDir, seriesDir), nil
-		}
-		// If no series, fall back to author/title
-		return filepath.Join(targetBase, authorDir, titleDir), nil
-	case "author-title":
-		return filepath.Join(targetBase, authorDir, titleDir), nil
-	case "author-series-title", "":
-		return filepath.Join(targetBase, authorDir, lc.calculateSeriesPath(titleDir, metadata)), nil
-	case "author-series-title-number":
-		return filepath.Join(
-			targetBase,
-			authorDir,
-			lc.calculateSeriesPathWithNumber(titleDir, metadata),
-		), nil
-	case "series-title":
-		return filepath.Join(targetBase, lc.calculateSeriesPath(titleDir, metadata)), nil
-	case "series-title-number":
-		return filepath.Join(targetBase, lc.calculateSeriesPathWithNumber(titleDir, metadata)), nil
-	default:
-		return filepath.Join(targetBase, authorDir, titleDir), nil
-	}
-}
-
-func (lc *LayoutCalculator) calculateCustomTemplatePath(
-	metadata Metadata,
-	targetBase string,
-) (string, error) {
-	template := strings.TrimSpace(lc.config.LayoutTemplate)
-	if filepath.IsAbs(template) || filepath.VolumeName(template) != "" ||
-		startsWithPathSeparator(template) {
-		return "", fmt.Errorf("layout template must be relative")
-	}
-
-	segments := splitLayoutTemplateSegments(template)
-	pathSegments := make([]string, 0, len(segments))
-	for _, segment := range segments {
-		if segment == "" {
-			continue
+	return safeTargetPath(metadata, targetBase, func() (string, error) {
+		layout := lc.layout()
+		targetDir, err := layout.TargetDir(metadata, targetBase)
+		if err != nil || !lc.config.BookFolder || layout.HasBookFolder(metadata) {
+			return targetDir, err
 		}
-		if segment == "." || segment == ".." {
-			return "", fmt.Errorf("layout template must not contain traversal segment %q", segment)
+		// BookFolder: files the layout leaves in an author or series folder get their own
+		title := PathMetadata(metadata, lc.config.Casing, lc.config.Subtitle, lc.config.StripTitlePrefix).Title
+		if lc.sanitizer != nil {
+			title = lc.sanitizer(title)
 		}
-
-		rendered, err := renderTemplateSegment(segment, metadata, lc.config.AuthorFormat)
-		if err != nil {
-			return "", err
-		}
-		rendered = strings.TrimSpace(rendered)
-		if rendered == "" {
-			continue
-		}
-		if rendered == "." || rendered == ".." {
-			return "", fmt.Errorf("layout template rendered unsafe segment %q", rendered)
-		}
-		pathSegments = append(pathSegments, lc.sanitizer(rendered))
-	}
-	if len(pathSegments) == 0 {
-		return "", fmt.Errorf("layout template rendered no usable path segments")
-	}
-
-	return filepath.Join(append([]string{targetBase}, pathSegments...)...), nil
+		return filepath.Join(targetDir, title), nil
+	})
 }
 
-func renderTemplateSegment(segment string, metadata Metadata, authorFormat string) (string, error) {
-	template, err := ParseTemplate(segment)
+// safeTargetPath checks the metadata before target computes a directory below base
+// and the directory after, so no tag can place a book outside base. Both checks
+// return an *UnsafePathError, which quarantines the book.
+func safeTargetPath(metadata Metadata, base string, target func() (string, error)) (string, error) {
+	if err := CheckPathMetadata(metadata); err != nil {
+		return "", err
+	}
+	path, err := target()
 	if err != nil {
 		return "", err
 	}
-	renderer := NewTemplateRenderer(template, NewAuthorFormatter(parseAuthorFormat(authorFormat)))
-	return renderer.Render(metadata)
-}
-
-func parseAuthorFormat(authorFormat string) AuthorFormat {
-	switch strings.ToLower(strings.TrimSpace(authorFormat)) {
-	case "last-first":
-		return AuthorFormatLastFirst
-	case "preserve":
-		return AuthorFormatPreserve
-	default:
-		return AuthorFormatFirstLast
+	if err := CheckTargetPath(base, path); err != nil {
+		return "", err
 	}
+	return path, nil
 }
 
-func splitLayoutTemplateSegments(template string) []string {
-	return strings.FieldsFunc(template, func(r rune) bool {
-		return r == '/' || r == '\\'
-	})
-}
-
-func startsWithPathSeparator(template string) bool {
-	return strings.HasPrefix(template, "/") || strings.HasPrefix(template, "\\")
+// layout describes the configured layout for the planning core
+func (lc *LayoutCalculator) layout() planning.Layout {
+	return planning.Layout{
+		Name:         lc.config.Layout,
+		Template:     lc.config.LayoutTemplate,
+		AuthorFormat: lc.config.AuthorFormat,
+		Casing:       lc.config.Casing,
+		Subtitle:     lc.config.Subtitle,
+		StripTitle:   lc.config.StripTitlePrefix,
+		Sanitize:     lc.sanitizer,
+		Initial:      lc.collation.Initial,
+	}
 }
 
 // getTargetBase returns the base directory for organizing files
@@ -316,38 +405,6 @@ func (lc *LayoutCalculator) getTargetBase() string {
 	return lc.config.BaseDir
 }
 
-// calculateSeriesPath handles series-based path calculation
-// Returns the series/title portion of the path (e.g., "Series/Title" or just "Title")
-func (lc *LayoutCalculator) calculateSeriesPath(titleDir string, metadata Metadata) string {
-	if validSeries := metadata.GetValidSeries(); validSeries != "" {
-		seriesDir := lc.sanitizer(validSeries)
-		return filepath.Join(seriesDir, titleDir)
-	}
-	return titleDir
-}
-
-// calculateSeriesPathWithNumber handles series-based path calculation with series number in title
-// Returns the series/title portion of the path (e.g., "Series/#1 - Title" or just "Title")
-func (lc *LayoutCalculator) calculateSeriesPathWithNumber(
-	titleDir string,
-	metadata Metadata,
-) string {
-	if validSeries := metadata.GetValidSeries(); validSeries != "" {
-		seriesDir := lc.sanitizer(validSeries)
-
-		// Get series number and prefix the title with it
-		seriesNumber := GetSeriesNumberFromMetadata(metadata)
-		if seriesNumber != "" {
-			numberedTitle := fmt.Sprintf("#%s - %s", seriesNumber, titleDir)
-			return filepath.Join(seriesDir, numberedTitle)
-		}
-
-		// If no series number, fall back to regular series path
-		return filepath.Join(seriesDir, titleDir)
-	}
-	return titleDir
-}
-
 // Organizer is the main struct that performs audiobook organization
 type Organizer struct {
 	config           OrganizerConfig
@@ -355,6 +412,33 @@ type Organizer struct {
 	logEntries       []LogEntry
 	fileOps          *FileOps
 	layoutCalculator *LayoutCalculator
+	trash            *Trash
+	target           TargetFS
+	scanIndex        *ScanIndex
+	authorVariants   *AuthorVariantDetector
+	promptMemory     promptMemory
+	mergeAnswer      mergeChoice   // Replace or keep answer given for every later file with MergeAsk
+	torrents         *TorrentIndex // Loaded from TorrentDirs; nil keeps every book in SeedSafe mode
+	authorLookup     *AuthorLookup
+	authorChecked    map[string]bool // Author names already looked up this run
+	logPath          string          // Resolved by GetLogPath for logPathBase
+	logPathBase      string
+	outputFS         *restrictedFS       // Set when the local output is FAT32 or exFAT
+	discSets         map[string]*discSet // Disc folders held for MergeDiscs, by parent and book name
+	discSetOrder     []*discSet
+	heldBooks        []heldBook                // Books waiting for the end of the scan, for Order
+	limitBooks       map[string]bool           // Books organized this run, for Limit
+	limitLeft        map[string]bool           // Books left for a later run by Limit
+	snapshots        map[string]sourceSnapshot // Files of each scanned book, by path, checked before it is moved
+	lines            *fileLines                // Per-file lines of the book being moved, for FileLines
+	messages         *i18n.Catalog             // Translations for Language; nil is English
+	seriesDirs       map[string]bool           // Folders above the books moved this run, for SeriesReadme
+	sharedEbookDirs  map[string]bool           // Ebooks mode: folders seen holding several books, whose covers stay
+	profile          *runProfiler              // Set by Execute for ProfileReport; nil times nothing
+	scanReadTime     time.Duration             // Time the last scan spent reading metadata
+	audioCounts      map[string]int            // Audio files per source folder, for SingleFileLayout in flat mode
+	coverFetcher     *CoverFetcher             // Created by the first book that needs a cover, with FetchCovers
+	releaseLookup    *MusicBrainzLookup        // Created by the first book looked up, with MusicBrainz
 }
 
 // NewOrganizer creates a new Organizer with the provided configuration
@@ -364,12 +448,26 @@ func NewOrganizer(config *OrganizerConfig) (*Organizer, error) {
 		return nil, err
 	}
 
+	// Ebook libraries are organized file by file from the books' own metadata
+	if config.Ebooks {
+		config.Flat = true
+		config.UseEmbeddedMetadata = true
+		config.Extensions = EbookExtensionPolicy(config.Extensions)
+	}
+
+	messages, _ := i18n.New(config.Language)
 	org := &Organizer{
-		config:  *config,
-		fileOps: NewFileOps(config.DryRun),
+		config:   *config,
+		fileOps:  NewFileOps(config.DryRun),
+		target:   localTargetFS{},
+		messages: messages,
 	}
 
-	org.layoutCalculator = NewLayoutCalculator(config, org.SanitizePath)
+	// Use the organizer's own config so paths resolved later are picked up
+	org.layoutCalculator = NewLayoutCalculator(&org.config, org.SanitizePath)
+	if config.TrashDir != "" {
+		org.trash = NewTrash(config.TrashDir, time.Now())
+	}
 
 	// Set the verbose mode flag for the metadata providers
 	SetVerboseMode(config.Verbose)
@@ -382,13 +480,22 @@ func NewOrganizer(config *OrganizerConfig) (*Organizer, error) {
 	return org, nil
 }
 
-// GetLogPath returns the path where operation logs are stored
+// GetLogPath returns the path where operation logs are stored: LogPath when set,
+// otherwise the DefaultLogPath of the output directory. With a remote output the
+// log stays in the local input directory.
 func (o *Organizer) GetLogPath() string {
+	if o.config.LogPath != "" {
+		return o.config.LogPath
+	}
 	logBase := o.config.BaseDir
-	if o.config.OutputDir != "" {
+	if o.config.OutputDir != "" && !o.hasRemoteTarget() {
 		logBase = o.config.OutputDir
 	}
-	return filepath.Join(logBase, LogFileName)
+	// The writability check runs once per directory, not on every log save
+	if o.logPath == "" || o.logPathBase != logBase {
+		o.logPath, o.logPathBase = DefaultLogPath(logBase), logBase
+	}
+	return o.logPath
 }
 
 // BaseDir returns the resolved base directory currently used by the organizer.
@@ -419,7 +526,16 @@ func (o *Organizer) ResolvePaths() error {
 	}
 	o.config.BaseDir = resolvedBaseDir
 
-	if o.config.OutputDir != "" {
+	if IsRemoteOutput(o.config.OutputDir) {
+		PrintBlue("🌐 Connecting to %s...", redactURL(o.config.OutputDir))
+		target, remoteDir, err := openRemoteTarget(o.config.OutputDir, &o.config)
+		if err != nil {
+			return err
+		}
+		o.target = target
+		o.fileOps.target = target
+		o.config.OutputDir = remoteDir
+	} else if o.config.OutputDir != "" && !o.hasRemoteTarget() {
 		cleanOut := filepath.Clean(o.config.OutputDir)
 		absOut, err := filepath.Abs(cleanOut)
 		if err != nil {
@@ -432,6 +548,16 @@ func (o *Organizer) ResolvePaths() error {
 		o.config.OutputDir = resolvedOutputDir
 	}
 
+	if !o.hasRemoteTarget() {
+		o.outputFS = detectRestrictedFS(o.layoutCalculator.getTargetBase())
+		if o.outputFS != nil {
+			PrintYellow(
+				"💾 Output is on %s: using Windows file name rules",
+				o.outputFS.Name,
+			)
+		}
+	}
+
 	// Resolve symlinks in AllowedSourcePaths so comparisons against Walk paths work on macOS.
 	for i, p := range o.config.AllowedSourcePaths {
 		clean := filepath.Clean(p)
@@ -445,14 +571,42 @@ func (o *Organizer) ResolvePaths() error {
 		}
 		o.config.AllowedSourcePaths[i] = resolved
 	}
+	for i, p := range o.config.Filter.Paths {
+		abs, err := filepath.Abs(filepath.Clean(p))
+		if err != nil {
+			return fmt.Errorf("error resolving --only-path %s: %v", p, err)
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return fmt.Errorf("error resolving --only-path %s: %v", p, err)
+		}
+		o.config.Filter.Paths[i] = resolved
+	}
 
 	return nil
 }
 
+// Close releases the connection to a remote output. It is safe to call more than once.
+func (o *Organizer) Close() error {
+	target := o.target
+	o.target = localTargetFS{}
+	if target == nil {
+		return nil
+	}
+	return target.Close()
+}
+
+// hasRemoteTarget reports whether books are written to a remote output
+func (o *Organizer) hasRemoteTarget() bool {
+	_, local := o.target.(localTargetFS)
+	return !local
+}
+
 // Finish writes pending logs, removes configured empty directories, and prints the summary.
 func (o *Organizer) Finish(startTime time.Time) error {
+	o.endFileLines()
 	if !o.config.DryRun && len(o.logEntries) > 0 {
-		color.Blue("💾 Saving operation log...")
+		PrintBlue("💾 Saving operation log...")
 		if err := o.saveLog(); err != nil {
 			return fmt.Errorf("error saving log: %v", err)
 		}
@@ -460,20 +614,43 @@ func (o *Organizer) Finish(startTime time.Time) error {
 
 	// Remove empty directories after all moves are complete
 	if err := o.removeEmptySourceDirs(); err != nil {
-		color.Red("❌ Error removing empty directories: %v", err)
+		o.recordError("❌ Error removing empty directories: %v", err)
 	}
+	o.writeSeriesReadmes()
 
+	if o.authorVariants != nil {
+		o.summary.AuthorVariants = o.authorVariants.Suggestions()
+	}
+	if o.authorLookup != nil {
+		if err := o.authorLookup.Save(); err != nil {
+			PrintYellow("⚠️  Warning: couldn't save author lookup cache: %v", err)
+		}
+	}
+
+	o.summary.Profile = o.profile.report(o.scanReadTime)
 	o.printSummary(startTime)
+	if o.config.DryRun {
+		o.checkPlan()
+		if len(o.summary.PlanWarnings) > 0 {
+			PrintPlanWarnings(o.summary.PlanWarnings)
+			PrintYellow("   Without --dry-run you'll be asked to confirm; pass --yes-i-know to run it unattended")
+		}
+	}
 	return nil
 }
 
-// Execute runs the main organization process
+// Execute runs the main organization process and closes any remote output when done
 func (o *Organizer) Execute() error {
+	defer o.Close()
+
 	// Clean and resolve the paths to absolute, symlink-free paths.
-	color.Blue("🔍 Resolving paths...")
+	PrintBlue("🔍 Resolving paths...")
 	if err := o.ResolvePaths(); err != nil {
 		return err
 	}
+	if err := o.loadTorrentIndex(); err != nil {
+		return err
+	}
 
 	// Check if the base path is a file rather than a directory
 	fileInfo, err := os.Stat(o.config.BaseDir)
@@ -484,7 +661,7 @@ func (o *Organizer) Execute() error {
 	// If it's a single file, process it directly
 	if !fileInfo.IsDir() {
 		if o.config.Verbose {
-			color.Blue("🔍 Processing single file: %s", o.config.BaseDir)
+			PrintBlue("🔍 Processing single file: %s", o.config.BaseDir)
 		}
 
 		// In flat mode, we need embedded metadata
@@ -496,18 +673,42 @@ func (o *Organizer) Execute() error {
 		return o.OrganizeSingleFile(o.config.BaseDir, nil)
 	}
 
+	// Runs that change the library hold its lock, so two don't interleave their moves
+	if !o.config.DryRun {
+		lock, err := o.lockLibrary()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				PrintYellow("⚠️  Warning: couldn't remove lock: %v", err)
+			}
+		}()
+	}
+
 	if o.config.Undo {
-		color.Yellow("↩️  Undoing previous operations...")
+		PrintYellow("↩️  Undoing previous operations...")
 		return o.undoMoves()
 	}
 
+	if err := o.probePermissions(); err != nil {
+		return err
+	}
+
 	if o.config.DryRun {
-		color.Yellow("🔍 Running in dry-run mode - no files will be moved")
+		PrintYellow("🔍 Running in dry-run mode - no files will be moved")
 	}
 
 	startTime := time.Now()
-	color.Blue("📚 Scanning for audiobooks...")
-	err = filepath.Walk(o.config.BaseDir, o.processDirectory)
+	if o.config.ProfileReport {
+		o.profile = newRunProfiler(startTime)
+	}
+	PrintBlue("📚 Scanning for audiobooks...")
+	err = o.organizeLibrary(o.config.BaseDir)
+	var notConfirmed planNotConfirmedError
+	if errors.As(err, &notConfirmed) {
+		return notConfirmed.error
+	}
 	if err != nil {
 		return fmt.Errorf("error walking directory: %v", err)
 	}
@@ -543,7 +744,7 @@ func (o *Organizer) removeEmptyDirs(dir string) error {
 	}
 
 	if o.config.Verbose {
-		color.Yellow("🗑️  Removing empty directory: %s", dir)
+		PrintYellow("🗑️  Removing empty directory: %s", dir)
 	}
 
 	if !o.config.DryRun {
@@ -593,7 +794,7 @@ func (o *Organizer) removeEmptySourceDirs() error {
 		var removedAny bool
 		for _, dir := range emptyDirs {
 			if err := o.removeEmptyDir(dir); err != nil {
-				PrintRed("❌ Error removing directory %s: %v", dir, err)
+				o.recordError("❌ Error removing directory %s: %v", dir, err)
 			} else {
 				removedAny = true
 			}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -544,3 +545,58 @@ func TestOrganizerExecuteTiming(t *testing.T) {
 		t.Errorf("Execute took too long: %v", duration)
 	}
 }
+
+func TestOrganizerExecuteConcurrentInstances(t *testing.T) {
+	type library struct {
+		baseDir   string
+		outputDir string
+		author    string
+		org       *Organizer
+	}
+
+	libraries := make([]*library, 2)
+	for i, author := range []string{"Author One", "Author Two"} {
+		lib := &library{baseDir: t.TempDir(), outputDir: t.TempDir(), author: author}
+		for _, title := range []string{"First", "Second", "Third"} {
+			createBookDir(t, lib.baseDir, title, title, author)
+		}
+		org, err := NewOrganizer(&OrganizerConfig{BaseDir: lib.baseDir, OutputDir: lib.outputDir})
+		if err != nil {
+			t.Fatalf("NewOrganizer() error = %v", err)
+		}
+		lib.org = org
+		libraries[i] = lib
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(libraries))
+	for i, lib := range libraries {
+		wg.Add(1)
+		go func(i int, org *Organizer) {
+			defer wg.Done()
+			errs[i] = org.Execute()
+		}(i, lib.org)
+	}
+	wg.Wait()
+
+	for i, lib := range libraries {
+		if errs[i] != nil {
+			t.Fatalf("Execute() for %s error = %v", lib.author, errs[i])
+		}
+		summary := lib.org.GetSummary()
+		if len(summary.Moves) != 3 {
+			t.Errorf("%s: moves = %d, want 3", lib.author, len(summary.Moves))
+		}
+		for _, move := range summary.Moves {
+			if !strings.HasPrefix(move.To, lib.outputDir) {
+				t.Errorf("%s: move %s -> %s left its own output directory", lib.author, move.From, move.To)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(lib.outputDir, lib.author, "Second", "audio.mp3")); err != nil {
+			t.Errorf("%s: organized book missing: %v", lib.author, err)
+		}
+		if _, err := os.Stat(lib.org.GetLogPath()); err != nil {
+			t.Errorf("%s: undo log missing: %v", lib.author, err)
+		}
+	}
+}
@@ -11,24 +11,40 @@ import (
 func createBookDir(t *testing.T, baseDir, name, title, author string) string {
 	t.Helper()
 	bookDir := filepath.Join(baseDir, name)
-	if err := os.MkdirAll(bookDir, 0o755); err != nil {
-		t.Fatalf("failed to create book directory %s: %v", bookDir, err)
-	}
 	meta := map[string]interface{}{
 		"title":   title,
 		"authors": []string{author},
 	}
-	metaBytes, err := json.Marshal(meta)
-	if err != nil {
-		t.Fatalf("failed to marshal metadata: %v", err)
+	writeBook(t, bookDir, meta, "audio.mp3")
+	return bookDir
+}
+
+// writeBook creates dir with a metadata.json holding meta, unless meta is nil, and
+// one file per name whose content is the name. It returns the files as moves into a
+// book folder.
+func writeBook(t *testing.T, dir string, meta map[string]interface{}, names ...string) []FilePair {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create book directory %s: %v", dir, err)
 	}
-	if err := os.WriteFile(filepath.Join(bookDir, "metadata.json"), metaBytes, 0o644); err != nil {
-		t.Fatalf("failed to write metadata.json: %v", err)
+	if meta != nil {
+		metaBytes, err := json.Marshal(meta)
+		if err != nil {
+			t.Fatalf("failed to marshal metadata: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, MetadataFileName), metaBytes, 0o644); err != nil {
+			t.Fatalf("failed to write metadata.json: %v", err)
+		}
 	}
-	if err := os.WriteFile(filepath.Join(bookDir, "audio.mp3"), []byte("fake audio data"), 0o644); err != nil {
-		t.Fatalf("failed to write audio.mp3: %v", err)
+	var moves []FilePair
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		moves = append(moves, FilePair{From: path, To: name})
 	}
-	return bookDir
+	return moves
 }
 
 // --- Change 1: AllowedSourcePaths ---
@@ -4,7 +4,6 @@ package organizer
 import (
 	"fmt"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"strings"
 )
@@ -16,8 +15,6 @@ var (
 	// Additional problematic characters to sanitize for all platforms
 	// Note: Removed apostrophe (') from this list to ensure consistent behavior across platforms
 	commonProblematicChars = []string{"<", ">", ":", "|", "?", "*", "`", "\""}
-	// Regex to trim leading/trailing underscores, spaces, and dots
-	reTrim = regexp.MustCompile(`(^[_ .]+)|([_ .]+$)`)
 )
 
 // SupportedAudioExtensions as a map for O(1) lookup instead of slice iteration
@@ -26,82 +23,33 @@ var SupportedAudioExtensions = map[string]bool{
 	".m4b":  true,
 	".m4a":  true,
 	".ogg":  true,
+	".opus": true,
 	".flac": true,
+	".aac":  true,
+	".wma":  true,
+	".wav":  true,
 }
 
-// SanitizePath sanitizes a file path string by replacing invalid characters based on the current OS.
-// On Windows, it replaces '<', '>', ':', '"', '/', '\', '|', '?', '*' with underscores.
-// On Unix systems, it replaces '/' and other problematic characters with underscores.
-// If ReplaceSpace is set, it also replaces spaces with the specified character.
-func (o *Organizer) SanitizePath(s string) string {
-	// First replace spaces if configured
-	if o.config.ReplaceSpace != "" {
-		s = strings.ReplaceAll(s, " ", o.config.ReplaceSpace)
-	}
-
-	// Then handle OS-specific invalid characters
-	var invalidChars []string
-	if runtime.GOOS == "windows" {
-		invalidChars = append(windowsInvalidChars, commonProblematicChars...)
-	} else if runtime.GOOS == "darwin" {
-		invalidChars = []string{":"}
-	} else {
-		// Linux/Unix: only replace truly problematic characters
-		// We're keeping apostrophes intact for consistent behavior with tests
-		invalidChars = unixInvalidChars
-		// Only add common problematic chars that aren't apostrophes
-		for _, char := range commonProblematicChars {
-			invalidChars = append(invalidChars, char)
-		}
-	}
-
-	// Replace invalid characters with underscore
-	for _, char := range invalidChars {
-		s = strings.ReplaceAll(s, char, "_")
+// naming returns the policy every target directory and file name of this run follows.
+// Names are cleaned for the current OS, except that FAT32 and exFAT outputs always
+// get the Windows rules.
+func (o *Organizer) naming() NamingPolicy {
+	goos := runtime.GOOS
+	if o.outputFS != nil {
+		goos = "windows"
 	}
-
-	// Trim leading and trailing spaces, dots, and underscores using regex
-	s = reTrim.ReplaceAllString(s, "")
-
-	return s
+	return NamingPolicy{ReplaceSpace: o.config.ReplaceSpace, TargetOS: goos}
 }
 
-// CleanSeriesName removes trailing series numbers (e.g., " #1") from series names.
-// This is now public so it can be used throughout the package.
-func CleanSeriesName(series string) string {
-	if idx := strings.LastIndex(series, " #"); idx != -1 {
-		return strings.TrimSpace(series[:idx])
-	}
-	return series
+// SanitizePath sanitizes a directory name component following the run's naming
+// policy. See planning.Sanitize for the rules.
+func (o *Organizer) SanitizePath(s string) string {
+	return o.naming().Dir(s)
 }
 
-// ExtractSeriesNumber extracts the series number from a series string (e.g., "Mistborn #1" -> "1").
-// Returns an empty string if no series number is found.
-func ExtractSeriesNumber(series string) string {
-	if idx := strings.LastIndex(series, " #"); idx != -1 {
-		return strings.TrimSpace(series[idx+2:])
-	}
-	return ""
-}
-
-// GetSeriesNumberFromMetadata extracts the series number from metadata.
-// It first checks RawData for series_index, then falls back to parsing the series string.
-func GetSeriesNumberFromMetadata(metadata Metadata) string {
-	// First try to get series_index from RawData
-	if seriesIndex, ok := metadata.RawData["series_index"].(float64); ok && seriesIndex > 0 {
-		// Format as integer if it's a whole number, otherwise with decimal
-		if seriesIndex == float64(int(seriesIndex)) {
-			return fmt.Sprintf("%d", int(seriesIndex))
-		}
-		return fmt.Sprintf("%.1f", seriesIndex)
-	}
-
-	// Fall back to extracting from series string
-	if series := metadata.GetFullValidSeries(); series != "" {
-		return ExtractSeriesNumber(series)
-	}
-
-	return ""
+// fileNamer returns a FilenameNormalizer following the run's naming policy
+func (o *Organizer) fileNamer() *FilenameNormalizer {
+	return NewFilenameNormalizer().WithNaming(o.naming())
 }
 
 // IsSupportedAudioFile checks if a file extension represents a supported audio format.
@@ -110,134 +58,179 @@ func IsSupportedAudioFile(ext string) bool {
 	return SupportedAudioExtensions[strings.ToLower(ext)]
 }
 
-// ShouldAddTrackPrefix reports whether a track number prefix should be added to a filename.
-// Single-track audiobooks (track_total == 1) keep their original filename.
-func ShouldAddTrackPrefix(trackNumber, trackTotal int) bool {
-	if trackNumber <= 0 {
-		return false
-	}
-	return trackTotal != 1
+// NormalizeFilename provides various filename normalization options.
+type FilenameNormalizer struct {
+	naming         NamingPolicy
+	addTrackPrefix bool
+	trackNumber    int
+	discNumber     int
+	trackWidth     int // Digits of a continuous track prefix; 0 for the usual prefixes
+	trackTitle     string
 }
 
-// TrackTotalFromMetadata returns the total track count from metadata raw data.
-func TrackTotalFromMetadata(metadata Metadata) int {
-	if metadata.RawData == nil {
-		return 0
-	}
-	switch value := metadata.RawData["track_total"].(type) {
-	case int:
-		return value
-	case float64:
-		return int(value)
-	default:
-		return 0
-	}
+// NewFilenameNormalizer creates a new filename normalizer with the given options.
+func NewFilenameNormalizer() *FilenameNormalizer {
+	return &FilenameNormalizer{}
 }
 
-// AddTrackPrefix adds a track number prefix to a filename if not already present.
-// Returns the original filename if track number is 0 or prefix already exists.
-func AddTrackPrefix(filename string, trackNumber int) string {
-	if trackNumber <= 0 {
-		return filename
-	}
-
-	ext := filepath.Ext(filename)
-	baseName := strings.TrimSuffix(filename, ext)
+// WithNaming configures the normalizer to follow a naming policy.
+func (fn *FilenameNormalizer) WithNaming(naming NamingPolicy) *FilenameNormalizer {
+	fn.naming = naming
+	return fn
+}
 
-	prefix := fmt.Sprintf(TrackPrefixFormat, trackNumber)
-	if strings.HasPrefix(baseName, prefix) {
-		return filename
-	}
+// WithSpaceReplacement configures the normalizer to replace spaces.
+func (fn *FilenameNormalizer) WithSpaceReplacement(replacement string) *FilenameNormalizer {
+	fn.naming.ReplaceSpace = replacement
+	return fn
+}
 
-	return fmt.Sprintf("%s%s%s", prefix, baseName, ext)
+// WithTrackPrefix configures the normalizer to add a track number prefix.
+func (fn *FilenameNormalizer) WithTrackPrefix(trackNumber int) *FilenameNormalizer {
+	fn.addTrackPrefix = true
+	fn.trackNumber = trackNumber
+	return fn
 }
 
-// HasTrackPrefix checks if a filename already has a track number prefix.
-func HasTrackPrefix(filename string) bool {
-	// Look for pattern like "01 - ", "02 - ", etc.
-	if len(filename) < 5 {
-		return false
-	}
+// WithDisc numbers the track prefix by disc as well ("2-05 - "), for books merged
+// from several disc folders.
+func (fn *FilenameNormalizer) WithDisc(discNumber int) *FilenameNormalizer {
+	fn.discNumber = discNumber
+	return fn
+}
 
-	// Check if it starts with digits followed by " - "
-	if filename[2] == ' ' && filename[3] == '-' && filename[4] == ' ' {
-		first := filename[0]
-		second := filename[1]
-		return first >= '0' && first <= '9' && second >= '0' && second <= '9'
-	}
+// WithContinuousTrack numbers the track prefix across the discs of a merged book
+// instead of by disc: with 12 tracks on the discs before it, track 1 becomes "13 - ".
+// The prefix is padded to width digits so the tracks sort in playing order.
+func (fn *FilenameNormalizer) WithContinuousTrack(offset, width int) *FilenameNormalizer {
+	fn.trackNumber += offset
+	fn.trackWidth = width
+	return fn
+}
 
-	return false
+// WithTrackTitle names the file after its track title instead of its current name.
+// It only applies together with a track prefix: "03 - The Storm.mp3".
+func (fn *FilenameNormalizer) WithTrackTitle(title string) *FilenameNormalizer {
+	fn.trackTitle = title
+	return fn
 }
 
-// ExtractTrackNumber extracts the track number from a filename prefix.
-// Returns 0 if no track number prefix is found.
-func ExtractTrackNumber(filename string) int {
-	if !HasTrackPrefix(filename) {
-		return 0
+// Normalize applies all configured normalizations to the filename.
+func (fn *FilenameNormalizer) Normalize(filename string) string {
+	result := filename
+	if fn.addTrackPrefix && fn.trackTitle != "" {
+		result = fn.naming.Dir(fn.trackTitle) + filepath.Ext(filename)
 	}
 
-	// Extract the two-digit number from the beginning
-	trackStr := filename[:2]
-	var trackNum int
-	if _, err := fmt.Sscanf(trackStr, "%d", &trackNum); err == nil {
-		return trackNum
+	// Add track prefix if configured, unless an earlier run already added it with
+	// spaces replaced
+	if fn.addTrackPrefix && fn.discNumber > 0 {
+		prefix := fmt.Sprintf(DiscTrackPrefixFormat, fn.discNumber, fn.trackNumber)
+		if !strings.HasPrefix(fn.naming.File(result), fn.naming.File(prefix)) {
+			result = prefix + RemoveTrackPrefix(result)
+		}
+	} else if fn.addTrackPrefix && fn.trackWidth > 0 {
+		prefix := fmt.Sprintf("%0*d - ", fn.trackWidth, fn.trackNumber)
+		if !strings.HasPrefix(fn.naming.File(result), fn.naming.File(prefix)) {
+			result = prefix + RemoveTrackPrefix(result)
+		}
+	} else if fn.addTrackPrefix {
+		prefix := fn.naming.File(fmt.Sprintf(TrackPrefixFormat, fn.trackNumber))
+		if !strings.HasPrefix(fn.naming.File(result), prefix) {
+			result = AddTrackPrefix(result, fn.trackNumber)
+		}
 	}
 
-	return 0
+	return fn.naming.File(result)
 }
 
-// RemoveTrackPrefix removes the track number prefix from a filename if present.
-func RemoveTrackPrefix(filename string) string {
-	if !HasTrackPrefix(filename) {
-		return filename
+// NormalizeCompanion names a companion of audioName to match the name Normalize gives
+// audioName, keeping the companion's own suffix (".cue", ".en.srt", ".mp3.jpg").
+func (fn *FilenameNormalizer) NormalizeCompanion(companion, audioName string) string {
+	suffix, ok := companionSuffix(audioName, companion)
+	if !ok {
+		return fn.Normalize(companion)
 	}
+	return CompanionTargetName(fn.Normalize(audioName), fn.naming.File(suffix))
+}
 
-	// Remove the "XX - " prefix (5 characters)
-	return filename[5:]
+// CompanionExtensions are the sidecar files that belong to the audio file sharing
+// their basename: cue sheets, cover art, NFO files, and subtitles or lyrics.
+var CompanionExtensions = map[string]bool{
+	".cue":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".nfo":  true,
+	".srt":  true,
+	".lrc":  true,
 }
 
-// NormalizeFilename provides various filename normalization options.
-type FilenameNormalizer struct {
-	replaceSpaces    bool
-	spaceReplacement string
-	addTrackPrefix   bool
-	trackNumber      int
+// IsCompanionFile checks if a file extension is a sidecar that follows its audio file
+func IsCompanionFile(ext string) bool {
+	return CompanionExtensions[strings.ToLower(ext)]
 }
 
-// NewFilenameNormalizer creates a new filename normalizer with the given options.
-func NewFilenameNormalizer() *FilenameNormalizer {
-	return &FilenameNormalizer{}
+// CompanionSuffix returns what follows the basename of audioName in companion, such
+// as ".cue" for "Book.cue", ".en.srt" for "Book.en.srt", or ".mp3.jpg" for
+// "Book.mp3.jpg", all next to "Book.mp3". It reports false when companion is not a
+// sidecar of audioName.
+func CompanionSuffix(audioName, companion string) (string, bool) {
+	if !IsCompanionFile(filepath.Ext(companion)) {
+		return "", false
+	}
+	return companionSuffix(audioName, companion)
 }
 
-// WithSpaceReplacement configures the normalizer to replace spaces.
-func (fn *FilenameNormalizer) WithSpaceReplacement(replacement string) *FilenameNormalizer {
-	fn.replaceSpaces = true
-	fn.spaceReplacement = replacement
-	return fn
+// companionSuffix is CompanionSuffix for a file already known to be a companion
+func companionSuffix(audioName, companion string) (string, bool) {
+	base := strings.TrimSuffix(audioName, filepath.Ext(audioName))
+	if base == "" || !strings.HasPrefix(companion, base+".") {
+		return "", false
+	}
+	return companion[len(base):], true
 }
 
-// WithTrackPrefix configures the normalizer to add a track number prefix.
-func (fn *FilenameNormalizer) WithTrackPrefix(trackNumber int) *FilenameNormalizer {
-	fn.addTrackPrefix = true
-	fn.trackNumber = trackNumber
-	return fn
+// CompanionTargetName is the companion's new name once its audio file is renamed to
+// audioTarget
+func CompanionTargetName(audioTarget, suffix string) string {
+	return strings.TrimSuffix(audioTarget, filepath.Ext(audioTarget)) + suffix
 }
 
-// Normalize applies all configured normalizations to the filename.
-func (fn *FilenameNormalizer) Normalize(filename string) string {
-	result := filename
+// MatchCompanions maps each sidecar among names to the audio file among names it
+// belongs to. When several audio files could claim a sidecar, the one with the
+// longest basename wins, so "Book.Part 2.cue" follows "Book.Part 2.mp3" rather than
+// "Book.mp3".
+func MatchCompanions(names []string) map[string]string {
+	return ExtensionPolicy(nil).MatchCompanions(names)
+}
 
-	// Add track prefix if configured
-	if fn.addTrackPrefix {
-		result = AddTrackPrefix(result, fn.trackNumber)
+// MatchCompanions is MatchCompanions for the audio and companion files of p
+func (p ExtensionPolicy) MatchCompanions(names []string) map[string]string {
+	var audio []string
+	for _, name := range names {
+		if p.IsAudio(filepath.Ext(name)) {
+			audio = append(audio, name)
+		}
 	}
 
-	// Replace spaces if configured
-	if fn.replaceSpaces && fn.spaceReplacement != "" {
-		result = strings.ReplaceAll(result, " ", fn.spaceReplacement)
+	companions := make(map[string]string)
+	for _, name := range names {
+		if !p.IsCompanion(filepath.Ext(name)) {
+			continue
+		}
+		best, bestSuffix := "", ""
+		for _, audioName := range audio {
+			suffix, ok := companionSuffix(audioName, name)
+			if ok && (best == "" || len(suffix) < len(bestSuffix)) {
+				best, bestSuffix = audioName, suffix
+			}
+		}
+		if best != "" {
+			companions[name] = best
+		}
 	}
-
-	return result
+	return companions
 }
 
 // PathValidator provides validation for file and directory paths.
@@ -440,6 +433,10 @@ var SupportedExtensions = map[string]bool{
 	".m4b":  true,
 	".m4a":  true,
 	".ogg":  true,
+	".opus": true,
 	".flac": true,
+	".aac":  true,
+	".wma":  true,
+	".wav":  true,
 	".epub": true,
 }
@@ -0,0 +1,66 @@
+package organizer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// PathPreview is the planned target of one book
+type PathPreview struct {
+	SourcePath string `json:"source_path"`
+	TargetPath string `json:"target_path,omitempty"` // The book directory, or the file in flat mode
+	Error      string `json:"error,omitempty"`
+}
+
+// errPreviewLimit stops the walk once a preview has enough books
+var errPreviewLimit = errors.New("preview limit reached")
+
+// PreviewPaths plans where the first limit books below the base directory would be
+// moved, without moving anything. The walk stops as soon as enough books are found,
+// so previewing a large library is as quick as previewing a small one. A limit of 0
+// previews every book.
+func (o *Organizer) PreviewPaths(limit int) ([]PathPreview, error) {
+	var previews []PathPreview
+	scanner := NewScanner(ScanOptionsFromConfig(&o.config))
+	err := scanner.Walk(o.config.BaseDir, ScanHandler{
+		Book: func(book Book) error {
+			previews = append(previews, o.previewPath(book))
+			if limit > 0 && len(previews) >= limit {
+				return errPreviewLimit
+			}
+			return nil
+		},
+		Error: func(path string, err error) error {
+			return nil
+		},
+	})
+	if err != nil && !errors.Is(err, errPreviewLimit) {
+		return previews, err
+	}
+	return previews, nil
+}
+
+// previewPath plans the target of one scanned book
+func (o *Organizer) previewPath(book Book) PathPreview {
+	preview := PathPreview{SourcePath: book.Path}
+	metadata, err := o.prepareMetadata(book.Provider)
+	if err == nil {
+		err = metadata.Validate()
+	}
+	if err != nil {
+		preview.Error = err.Error()
+		return preview
+	}
+
+	targetDir, err := o.layoutCalculator.CalculateTargetPathE(metadata)
+	if err != nil {
+		preview.Error = fmt.Sprintf("error calculating target path: %v", err)
+		return preview
+	}
+	preview.TargetPath = targetDir
+	if o.config.Flat {
+		preview.TargetPath = filepath.Join(targetDir, filepath.Base(book.Path))
+	}
+	return preview
+}
@@ -0,0 +1,57 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewPaths(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeBook(t, filepath.Join(base, "a"), map[string]interface{}{
+		"title":   "Dune",
+		"authors": []string{"Frank Herbert"},
+		"series":  []string{"Dune #1"},
+	}, "book.mp3")
+	writeBook(t, filepath.Join(base, "b"), map[string]interface{}{"title": "", "authors": []string{}}, "book.mp3")
+	createBookDir(t, base, "c", "Emma", "Jane Austen")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out, Layout: "author-series-title"})
+	require.NoError(t, err)
+
+	previews, err := org.PreviewPaths(0)
+	require.NoError(t, err)
+	require.Len(t, previews, 3)
+
+	assert.Equal(t, filepath.Join(base, "a"), previews[0].SourcePath)
+	assert.Equal(t, filepath.Join(out, "Frank Herbert", "Dune", "Dune"), previews[0].TargetPath)
+	assert.Empty(t, previews[0].Error)
+	assert.NotEmpty(t, previews[1].Error)
+	assert.Empty(t, previews[1].TargetPath)
+	assert.Equal(t, filepath.Join(out, "Jane Austen", "Emma"), previews[2].TargetPath)
+
+	// Nothing is moved
+	assert.DirExists(t, filepath.Join(base, "a"))
+	assert.NoDirExists(t, filepath.Join(out, "Frank Herbert"))
+}
+
+func TestPreviewPathsStopsAtLimit(t *testing.T) {
+	base := t.TempDir()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		createBookDir(t, base, name, "Book "+name, "Author")
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, Layout: "author-title"})
+	require.NoError(t, err)
+
+	previews, err := org.PreviewPaths(2)
+	require.NoError(t, err)
+	require.Len(t, previews, 2)
+	assert.Equal(t, filepath.Join(base, "Author", "Book a"), previews[0].TargetPath)
+	assert.Equal(t, filepath.Join(base, "Author", "Book b"), previews[1].TargetPath)
+}
@@ -1,7 +1,10 @@
 package organizer
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -330,3 +333,213 @@ func TestSanitizePathWithProblematicMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchCompanions(t *testing.T) {
+	names := []string{
+		"Book.mp3",
+		"Book.cue",
+		"Book.en.srt",
+		"Book.mp3.jpg",
+		"Book.Part 2.mp3",
+		"Book.Part 2.cue",
+		"Book.Part 20.nfo",
+		"cover.jpg",
+		"metadata.json",
+	}
+	want := map[string]string{
+		"Book.cue":        "Book.mp3",
+		"Book.en.srt":     "Book.mp3",
+		"Book.mp3.jpg":    "Book.mp3",
+		"Book.Part 2.cue": "Book.Part 2.mp3",
+		// No "Book.Part 20" audio, so the closest owner is "Book"
+		"Book.Part 20.nfo": "Book.mp3",
+	}
+
+	got := MatchCompanions(names)
+	if len(got) != len(want) {
+		t.Fatalf("MatchCompanions() = %v, want %v", got, want)
+	}
+	for companion, audio := range want {
+		if got[companion] != audio {
+			t.Errorf("MatchCompanions()[%q] = %q, want %q", companion, got[companion], audio)
+		}
+	}
+}
+
+func TestFilenameNormalizerNormalizeCompanion(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer *FilenameNormalizer
+		companion  string
+		audio      string
+		want       string
+	}{
+		{
+			name:       "track prefix follows the audio file",
+			normalizer: NewFilenameNormalizer().WithTrackPrefix(3),
+			companion:  "Chapter 3.cue",
+			audio:      "Chapter 3.mp3",
+			want:       "03 - Chapter 3.cue",
+		},
+		{
+			name:       "multi-extension suffix is kept",
+			normalizer: NewFilenameNormalizer().WithTrackPrefix(1).WithSpaceReplacement("_"),
+			companion:  "Part One.en.srt",
+			audio:      "Part One.m4b",
+			want:       "01_-_Part_One.en.srt",
+		},
+		{
+			name:       "audio extension inside the sidecar name",
+			normalizer: NewFilenameNormalizer().WithTrackPrefix(2),
+			companion:  "Part.2.mp3.jpg",
+			audio:      "Part.2.mp3",
+			want:       "02 - Part.2.mp3.jpg",
+		},
+		{
+			name:       "unrelated file is normalized on its own",
+			normalizer: NewFilenameNormalizer().WithTrackPrefix(4),
+			companion:  "front cover.jpg",
+			audio:      "Chapter 4.mp3",
+			want:       "04 - front cover.jpg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.normalizer.NormalizeCompanion(tt.companion, tt.audio); got != tt.want {
+				t.Errorf("NormalizeCompanion(%q, %q) = %q, want %q", tt.companion, tt.audio, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilenameNormalizerKeepsReplacedTrackPrefix(t *testing.T) {
+	normalizer := NewFilenameNormalizer().WithTrackPrefix(1).WithSpaceReplacement("_")
+	if got := normalizer.Normalize("01_-_Part_One.mp3"); got != "01_-_Part_One.mp3" {
+		t.Errorf("Normalize() = %q, want the existing prefix kept", got)
+	}
+	if got := normalizer.Normalize("Part One.mp3"); got != "01_-_Part_One.mp3" {
+		t.Errorf("Normalize() = %q, want %q", got, "01_-_Part_One.mp3")
+	}
+}
+
+func TestFilenameNormalizerTrackTitle(t *testing.T) {
+	normalizer := NewFilenameNormalizer().WithTrackPrefix(3).WithTrackTitle("The Storm: Part 1/2")
+	if got, want := normalizer.Normalize("track03.mp3"), "03 - The Storm_ Part 1_2.mp3"; got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+	if got, want := normalizer.NormalizeCompanion("track03.cue", "track03.mp3"), "03 - The Storm_ Part 1_2.cue"; got != want {
+		t.Errorf("NormalizeCompanion() = %q, want %q", got, want)
+	}
+
+	// Without a track number the file keeps its own name
+	untracked := NewFilenameNormalizer().WithTrackTitle("The Storm")
+	if got := untracked.Normalize("track03.mp3"); got != "track03.mp3" {
+		t.Errorf("Normalize() = %q, want the name unchanged", got)
+	}
+}
+
+func TestFileTrackTitle(t *testing.T) {
+	book := &Metadata{Title: "The Book", Album: "The Book"}
+	file := Metadata{
+		TrackTitle: "Chapter One: Arrival",
+		RawData:    map[string]interface{}{"subtitle": "Arrival"},
+	}
+
+	org := &Organizer{}
+	if got := org.fileTrackTitle(file, book); got != "" {
+		t.Errorf("fileTrackTitle() = %q, want \"\" without --track-titles", got)
+	}
+
+	org.config.TrackTitles = true
+	if got := org.fileTrackTitle(file, book); got != "Chapter One: Arrival" {
+		t.Errorf("fileTrackTitle() = %q, want the file's title tag", got)
+	}
+
+	org.config.FieldMapping.TrackTitleField = "subtitle,track_title"
+	if got := org.fileTrackTitle(file, book); got != "Arrival" {
+		t.Errorf("fileTrackTitle() = %q, want the mapped field", got)
+	}
+
+	org.config.FieldMapping.TrackTitleField = ""
+	file.TrackTitle = "the book"
+	if got := org.fileTrackTitle(file, book); got != "" {
+		t.Errorf("fileTrackTitle() = %q, want \"\" for a title repeating the book's", got)
+	}
+}
+
+// TestReplaceSpaceAppliesToDirsAndFiles checks that book directories, single files,
+// and albums all name directories and files with the same space replacement
+func TestReplaceSpaceAppliesToDirsAndFiles(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	bookDir := filepath.Join(base, "Some Book")
+	if err := os.MkdirAll(bookDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Part One.mp3", "Part One.cue"} {
+		if err := os.WriteFile(filepath.Join(bookDir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      base,
+		OutputDir:    out,
+		ReplaceSpace: "_",
+		DryRun:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := Metadata{
+		Title:       "The Final Empire",
+		Authors:     []string{"Brandon Sanderson"},
+		TrackNumber: 1,
+		RawData:     map[string]interface{}{"track_total": 2},
+	}
+
+	assertNoSpaces := func(t *testing.T, path string) {
+		t.Helper()
+		rel, err := filepath.Rel(out, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(rel, " ") {
+			t.Errorf("%s keeps a space below the output directory", rel)
+		}
+	}
+
+	t.Run("book directory", func(t *testing.T) {
+		entries, err := os.ReadDir(bookDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetDir := org.layoutCalculator.CalculateTargetPath(metadata)
+		assertNoSpaces(t, targetDir)
+		files, err := org.processDirectoryFiles(entries, bookDir, targetDir, &metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			assertNoSpaces(t, filepath.Join(targetDir, file.To))
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		assertNoSpaces(t, org.calculateSingleFileTargetPath(filepath.Join(bookDir, "Part One.mp3"), metadata))
+	})
+
+	t.Run("album", func(t *testing.T) {
+		org.summary.Moves = nil
+		album := NewAlbumGroup(metadata)
+		album.Files = []string{filepath.Join(bookDir, "Part One.mp3")}
+		if err := org.organizeAlbumGroup(album); err != nil {
+			t.Fatal(err)
+		}
+		if len(org.summary.Moves) != 1 {
+			t.Fatalf("got %d album moves, want 1", len(org.summary.Moves))
+		}
+		assertNoSpaces(t, org.summary.Moves[0].To)
+	})
+}
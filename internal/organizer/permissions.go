@@ -0,0 +1,121 @@
+package organizer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// permissionProbeDirs bounds how many input directories are checked before a run, so
+// the probe stays quick on large libraries
+const permissionProbeDirs = 50
+
+// permissionsDoc explains how to give the organizer access to a library
+const permissionsDoc = "https://github.com/jeeftor/audiobook-organizer/blob/master/docs/troubleshooting.md#permission-denied"
+
+// probePermissions checks, before anything moves, that the organizer can read a
+// sample of the input tree and write where the run needs to. A library it has no
+// access to then fails once, naming the directory's owner, instead of failing for
+// every file halfway through the run.
+func (o *Organizer) probePermissions() error {
+	moveSources := !o.config.DryRun && !o.config.SeedSafe
+	for _, dir := range o.sampleInputDirs(permissionProbeDirs) {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrPermission) {
+			return permissionError("read", "input directory", dir, err)
+		}
+		if file := firstRegularFile(dir, entries); file != "" {
+			if f, err := os.Open(file); errors.Is(err, fs.ErrPermission) {
+				return permissionError("read", "input file", file, err)
+			} else if err == nil {
+				f.Close()
+			}
+		}
+		if moveSources {
+			if err := writeAccess(dir); err != nil {
+				return permissionError("move files out of", "input directory", dir, err)
+			}
+		}
+	}
+
+	if o.config.DryRun || o.config.OutputDir == "" || o.hasRemoteTarget() {
+		return nil
+	}
+	outputDir := nearestExistingDir(o.config.OutputDir)
+	if err := writeAccess(outputDir); err != nil {
+		return permissionError("write to", "output directory", outputDir, err)
+	}
+	return nil
+}
+
+// sampleInputDirs lists up to limit directories of the input tree, breadth first so
+// the sample covers the top levels of the library. The output directory, media server
+// folders, and directories with an ignore marker are left out, as the scan leaves
+// them out.
+func (o *Organizer) sampleInputDirs(limit int) []string {
+	dirs := []string{o.config.BaseDir}
+	for i := 0; i < len(dirs) && len(dirs) < limit; i++ {
+		entries, err := os.ReadDir(dirs[i])
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || len(dirs) >= limit {
+				continue
+			}
+			dir := filepath.Join(dirs[i], entry.Name())
+			if o.isOutputDir(dir) || IgnoreMarker(dir) != "" ||
+				(!o.config.AllowProtectedDirs && ProtectedDirServer(dir) != "") {
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// isOutputDir reports whether dir is the local output directory or lies below it
+func (o *Organizer) isOutputDir(dir string) bool {
+	return o.config.OutputDir != "" && !o.hasRemoteTarget() &&
+		(dir == o.config.OutputDir || isSubPathOf(o.config.OutputDir, dir))
+}
+
+// firstRegularFile returns the path of the first regular file among entries of dir
+func firstRegularFile(dir string, entries []os.DirEntry) string {
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			return filepath.Join(dir, entry.Name())
+		}
+	}
+	return ""
+}
+
+// nearestExistingDir returns dir, or its closest ancestor that exists when the run
+// still has to create dir
+func nearestExistingDir(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// permissionError describes a directory or file the run can't use, with who owns it
+// and who the organizer runs as
+func permissionError(action, what, path string, err error) error {
+	message := fmt.Sprintf("permission denied: cannot %s %s %s", action, what, path)
+	if owner := describeOwner(path); owner != "" {
+		message += " (" + owner + ")"
+	}
+	return fmt.Errorf(
+		"%s\n\nRun the organizer as a user with access, or change the ownership or mode of the directory.\nSee %s\n\n%w",
+		message, permissionsDoc, err,
+	)
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package organizer
+
+import "os"
+
+// writeAccess checks that files can be created in dir by creating and removing one
+func writeAccess(dir string) error {
+	probe, err := os.CreateTemp(dir, ".abook-org-write-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// describeOwner returns "", as file ownership isn't reported on this platform
+func describeOwner(string) string {
+	return ""
+}
@@ -0,0 +1,61 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbePermissionsAllowsAccessibleLibrary(t *testing.T) {
+	base := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Book"), `{"title":"Book","authors":["Author"]}`, "01.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:   base,
+		OutputDir: filepath.Join(t.TempDir(), "not-created-yet"),
+	})
+	require.NoError(t, err)
+	assert.NoError(t, org.probePermissions())
+}
+
+func TestPermissionErrorNamesOwner(t *testing.T) {
+	dir := t.TempDir()
+	err := permissionError("write to", "output directory", dir, fs.ErrPermission)
+
+	assert.ErrorIs(t, err, fs.ErrPermission)
+	assert.Contains(t, err.Error(), "permission denied: cannot write to output directory "+dir)
+	assert.Contains(t, err.Error(), "troubleshooting.md#permission-denied")
+	if runtime.GOOS != "windows" {
+		assert.Contains(t, err.Error(), "owned by")
+		assert.Contains(t, err.Error(), "running as")
+	}
+}
+
+func TestProbePermissionsRejectsUnwritableOutput(t *testing.T) {
+	if os.Geteuid() == 0 || runtime.GOOS == "windows" {
+		t.Skip("read-only directories are writable by root")
+	}
+	base := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Book"), `{"title":"Book","authors":["Author"]}`, "01.mp3")
+	out := t.TempDir()
+	require.NoError(t, os.Chmod(out, 0o555))
+	t.Cleanup(func() { os.Chmod(out, 0o755) })
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out})
+	require.NoError(t, err)
+	err = org.probePermissions()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot write to output directory "+out)
+
+	// A dry run writes nothing, so it only needs to read
+	org, err = NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out, DryRun: true})
+	require.NoError(t, err)
+	assert.NoError(t, org.probePermissions())
+}
@@ -0,0 +1,45 @@
+//go:build unix
+
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// writeAccess checks that files can be created in and removed from dir without
+// touching it, so the probe doesn't change the modification time of the library
+func writeAccess(dir string) error {
+	if err := unix.Access(dir, unix.W_OK|unix.X_OK); err != nil {
+		return &os.PathError{Op: "access", Path: dir, Err: err}
+	}
+	return nil
+}
+
+// describeOwner names the owner and mode of path and the user the organizer runs
+// as, such as "owned by root (uid 0), mode drwxr-xr-x, running as uid 1000 (alice)"
+func describeOwner(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("owned by %s, mode %s, running as %s",
+		describeUID(int(stat.Uid)), info.Mode(), describeUID(os.Geteuid()))
+}
+
+// describeUID formats a uid with its user name when it has one
+func describeUID(uid int) string {
+	if u, err := user.LookupId(strconv.Itoa(uid)); err == nil && u.Username != "" {
+		return fmt.Sprintf("%s (uid %d)", u.Username, uid)
+	}
+	return fmt.Sprintf("uid %d", uid)
+}
@@ -0,0 +1,135 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MovePlanDiff compares a computed move plan with the moves recorded by a previous run
+type MovePlanDiff struct {
+	Drifted []MoveDrift   `json:"drifted"` // Books the previous run placed that would move again
+	Stable  []string      `json:"stable"`  // Previous targets the current plan leaves in place
+	New     []MoveSummary `json:"new"`     // Planned moves unrelated to the previous run
+}
+
+// MoveDrift describes a previously organized book whose computed target changed
+type MoveDrift struct {
+	From           string `json:"from"`
+	PreviousTarget string `json:"previous_target"`
+	PlannedTarget  string `json:"planned_target"`
+}
+
+// HasDrift reports whether any previously organized book would move again
+func (d MovePlanDiff) HasDrift() bool {
+	return len(d.Drifted) > 0
+}
+
+// ReadLogEntries reads the move log written by a previous organize run
+func ReadLogEntries(logPath string) ([]LogEntry, error) {
+	data, err := os.ReadFile(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no log file found at %s", logPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading log %s: %w", logPath, err)
+	}
+
+	var entries []LogEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing log: %v", err)
+	}
+	return entries, nil
+}
+
+// DiffMovePlan classifies planned moves against a previous run's log entries.
+// A planned move starting where the previous run put a book is drift; a planned move
+// from a previous source (for example after an undo) is stable when it lands on the
+// same target again. Previous entries the plan does not touch stayed in place.
+func DiffMovePlan(previous []LogEntry, plan []MoveSummary) MovePlanDiff {
+	diff := MovePlanDiff{
+		Drifted: []MoveDrift{},
+		Stable:  []string{},
+		New:     []MoveSummary{},
+	}
+
+	// Index both directory-level and file-level paths so directory, album, and flat
+	// mode plans all match their log entries.
+	byTarget := make(map[string]int)
+	bySource := make(map[string]int)
+	targetOf := make(map[string]string)
+	for i, entry := range previous {
+		source := filepath.Clean(entry.SourcePath)
+		target := filepath.Clean(entry.TargetPath)
+		byTarget[target] = i
+		bySource[source] = i
+		targetOf[source] = target
+		for _, file := range entry.Files {
+			fileSource := filepath.Join(source, file.From)
+			fileTarget := filepath.Join(target, file.To)
+			byTarget[fileTarget] = i
+			bySource[fileSource] = i
+			targetOf[fileSource] = fileTarget
+		}
+	}
+
+	touched := make(map[int]bool)
+	for _, move := range plan {
+		from := filepath.Clean(move.From)
+		to := filepath.Clean(move.To)
+
+		if i, ok := byTarget[from]; ok {
+			touched[i] = true
+			diff.Drifted = append(diff.Drifted, MoveDrift{From: from, PreviousTarget: from, PlannedTarget: to})
+			continue
+		}
+		if i, ok := bySource[from]; ok {
+			touched[i] = true
+			if targetOf[from] == to {
+				diff.Stable = append(diff.Stable, to)
+			} else {
+				diff.Drifted = append(diff.Drifted, MoveDrift{
+					From:           from,
+					PreviousTarget: targetOf[from],
+					PlannedTarget:  to,
+				})
+			}
+			continue
+		}
+		diff.New = append(diff.New, move)
+	}
+
+	for i, entry := range previous {
+		if !touched[i] {
+			diff.Stable = append(diff.Stable, filepath.Clean(entry.TargetPath))
+		}
+	}
+
+	return diff
+}
+
+// PrintMovePlanDiff prints a move plan diff grouped by drifted, new, and stable books
+func PrintMovePlanDiff(diff MovePlanDiff, verbose bool) {
+	PrintCyan("\n🔀 Plan compared with previous run")
+
+	PrintYellow("\n⚠️  Would move again (metadata drift): %d", len(diff.Drifted))
+	for _, drift := range diff.Drifted {
+		PrintBase("  %s", drift.From)
+		PrintBase("    was: %s", drift.PreviousTarget)
+		PrintBase("    now: %s", drift.PlannedTarget)
+	}
+
+	PrintBlue("\n🆕 Not in previous run: %d", len(diff.New))
+	for _, move := range diff.New {
+		PrintBase("  %s -> %s", move.From, move.To)
+	}
+
+	PrintGreen("\n✅ Stable: %d", len(diff.Stable))
+	if verbose {
+		for _, target := range diff.Stable {
+			PrintBase("  %s", target)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffMovePlan(t *testing.T) {
+	previous := []LogEntry{
+		{SourcePath: "/in/Dune", TargetPath: "/out/Frank Herbert/Dune", Files: []FilePair{{From: "01.mp3", To: "01.mp3"}}},
+		{SourcePath: "/in/Emma", TargetPath: "/out/Jane Austen/Emma", Files: []FilePair{{From: "emma.m4b", To: "emma.m4b"}}},
+		{SourcePath: "/in/Hobbit", TargetPath: "/out/Tolkien/Hobbit", Files: []FilePair{{From: "a.mp3", To: "a.mp3"}}},
+	}
+	plan := []MoveSummary{
+		// Tags were edited after the previous run
+		{From: "/out/Frank Herbert/Dune", To: "/out/Frank Herbert/Dune Saga/Dune"},
+		// Previous run was undone and the book lands in the same place again
+		{From: "/in/Hobbit", To: "/out/Tolkien/Hobbit"},
+		{From: "/in/Persuasion", To: "/out/Jane Austen/Persuasion"},
+	}
+
+	diff := DiffMovePlan(previous, plan)
+
+	require.Len(t, diff.Drifted, 1)
+	assert.Equal(t, "/out/Frank Herbert/Dune", diff.Drifted[0].PreviousTarget)
+	assert.Equal(t, "/out/Frank Herbert/Dune Saga/Dune", diff.Drifted[0].PlannedTarget)
+	assert.ElementsMatch(t, []string{"/out/Tolkien/Hobbit", "/out/Jane Austen/Emma"}, diff.Stable)
+	assert.Equal(t, []MoveSummary{{From: "/in/Persuasion", To: "/out/Jane Austen/Persuasion"}}, diff.New)
+	assert.True(t, diff.HasDrift())
+}
+
+func TestDiffMovePlanFlatModeFiles(t *testing.T) {
+	previous := []LogEntry{
+		{SourcePath: "/in", TargetPath: "/out/Author/Book", Files: []FilePair{{From: "book.m4b", To: "book.m4b"}}},
+	}
+	plan := []MoveSummary{{From: "/in/book.m4b", To: "/out/Author/Other/book.m4b"}}
+
+	diff := DiffMovePlan(previous, plan)
+
+	require.Len(t, diff.Drifted, 1)
+	assert.Equal(t, "/out/Author/Book/book.m4b", diff.Drifted[0].PreviousTarget)
+	assert.Empty(t, diff.Stable)
+	assert.Empty(t, diff.New)
+}
+
+func TestReadLogEntries(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, LogFileName)
+	entries := []LogEntry{{SourcePath: "/in/a", TargetPath: "/out/a", Files: []FilePair{{From: "1.mp3", To: "1.mp3"}}}}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(logPath, data, 0o644))
+
+	read, err := ReadLogEntries(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/out/a", read[0].TargetPath)
+
+	_, err = ReadLogEntries(filepath.Join(dir, "missing.log"))
+	assert.ErrorContains(t, err, "no log file found")
+
+	// Other errors are reported as they are
+	_, err = ReadLogEntries(dir)
+	assert.ErrorContains(t, err, "error reading log "+dir)
+	assert.NotContains(t, err.Error(), "no log file found")
+}
@@ -0,0 +1,197 @@
+package organizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PlanConfidence tags how sure a plan warning is that the plan is a mistake
+type PlanConfidence string
+
+const (
+	PlanConfidenceMedium PlanConfidence = "medium"
+	PlanConfidenceHigh   PlanConfidence = "high"
+)
+
+// PlanWarning describes a plan that looks destructive, like most of a library
+// filed under "Unknown Author" or a whole organized library moving again
+type PlanWarning struct {
+	Confidence PlanConfidence `json:"confidence"`
+	Message    string         `json:"message"`
+}
+
+// Thresholds of the plan checks. Plans of fewer books than planWarningMinBooks are
+// too small for the shares to mean much.
+const (
+	planWarningMinBooks       = 5
+	unknownAuthorShare        = 0.5 // More books than this under a placeholder author warn
+	unknownAuthorHighShare    = 0.8
+	collapsedBooks            = 50 // This many books or more landing in one folder warn
+	collapsedHighBooks        = 100
+	relocatedLibraryShare     = 0.5 // More of the previous run's books than this moving again warn
+	relocatedLibraryHighShare = 0.9
+)
+
+// CheckPlan looks for signs that a move plan would wreck a library: most books
+// filed under a placeholder author, many books collapsing into one folder, or most
+// of the books a previous run organized moving again, as when the layout changed.
+// targetBase is the directory the plan's targets are under, and previous holds the
+// previous run's log entries, if any.
+func CheckPlan(plan, fileMoves []MoveSummary, targetBase string, previous []LogEntry) []PlanWarning {
+	var warnings []PlanWarning
+	books := len(plan)
+	if books < planWarningMinBooks {
+		return nil
+	}
+
+	// A single-file move's target is the file; its book folder is the one it is in
+	singleFiles := make(map[string]bool, len(fileMoves))
+	for _, move := range fileMoves {
+		singleFiles[filepath.Clean(move.From)] = true
+	}
+	perFolder := make(map[string]int)
+	unknown := 0
+	for _, move := range plan {
+		folder := filepath.Clean(move.To)
+		if singleFiles[filepath.Clean(move.From)] {
+			folder = filepath.Dir(folder)
+		}
+		perFolder[folder]++
+		if hasPlaceholderAuthor(folder, targetBase) {
+			unknown++
+		}
+	}
+
+	if share := float64(unknown) / float64(books); share > unknownAuthorShare {
+		warnings = append(warnings, PlanWarning{
+			Confidence: confidenceAbove(share, unknownAuthorHighShare),
+			Message: fmt.Sprintf(
+				"%d of %d books (%.0f%%) would be filed under a placeholder author such as \"Unknown Author\"; check the author field mapping",
+				unknown, books, share*100,
+			),
+		})
+	}
+
+	folders := make([]string, 0, len(perFolder))
+	for folder, count := range perFolder {
+		if count >= collapsedBooks {
+			folders = append(folders, folder)
+		}
+	}
+	sort.Strings(folders)
+	for _, folder := range folders {
+		count := perFolder[folder]
+		confidence := PlanConfidenceMedium
+		if count >= collapsedHighBooks {
+			confidence = PlanConfidenceHigh
+		}
+		warnings = append(warnings, PlanWarning{
+			Confidence: confidence,
+			Message:    fmt.Sprintf("%d books would land in one folder: %s", count, folder),
+		})
+	}
+
+	if len(previous) >= planWarningMinBooks {
+		drifted := len(DiffMovePlan(previous, plan).Drifted)
+		if share := float64(drifted) / float64(len(previous)); share > relocatedLibraryShare {
+			warnings = append(warnings, PlanWarning{
+				Confidence: confidenceAbove(share, relocatedLibraryHighShare),
+				Message: fmt.Sprintf(
+					"%d of the %d books the previous run organized (%.0f%%) would move again; did the layout or field mapping change?",
+					drifted, len(previous), share*100,
+				),
+			})
+		}
+	}
+	return warnings
+}
+
+// confidenceAbove returns high confidence for a share at or above high, and medium otherwise
+func confidenceAbove(share, high float64) PlanConfidence {
+	if share >= high {
+		return PlanConfidenceHigh
+	}
+	return PlanConfidenceMedium
+}
+
+// hasPlaceholderAuthor reports whether a folder below targetBase is named for a
+// placeholder author, such as "Unknown Author" or "Various Artists"
+func hasPlaceholderAuthor(folder, targetBase string) bool {
+	rel, err := filepath.Rel(targetBase, folder)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = folder
+	}
+	for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+		if genericAuthors[strings.ToLower(strings.TrimSpace(name))] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlan sets the summary's plan warnings from the moves planned so far
+func (o *Organizer) checkPlan() {
+	o.summary.PlanWarnings = o.planWarnings(o.summary.Moves, o.summary.FileMoves)
+}
+
+// planWarnings checks a plan against the library's target base and the previous
+// run's log entries
+func (o *Organizer) planWarnings(plan, fileMoves []MoveSummary) []PlanWarning {
+	// A missing or unreadable log just means there is no previous run to compare with
+	previous, _ := ReadLogEntries(o.GetLogPath())
+	return CheckPlan(plan, fileMoves, o.layoutCalculator.getTargetBase(), previous)
+}
+
+// confirmsPlan reports whether a real run asks ConfirmPlan about its plan, holding
+// every book until the scan is done so nothing moves before it is checked
+func (o *Organizer) confirmsPlan() bool {
+	return o.config.ConfirmPlan != nil && !o.config.DryRun
+}
+
+// confirmHeldPlan checks where the books held during the scan would go and, when the
+// plan looks destructive, passes its warnings to ConfirmPlan before any book moves.
+// Books that can't be planned or are already in place are left out of the check.
+func (o *Organizer) confirmHeldPlan() error {
+	if !o.confirmsPlan() {
+		return nil
+	}
+	var plan, fileMoves []MoveSummary
+	for _, book := range o.heldBooks {
+		preview := o.previewPath(book.Book)
+		if preview.TargetPath == "" || filepath.Clean(preview.TargetPath) == filepath.Clean(book.Path) {
+			continue
+		}
+		move := MoveSummary{From: book.Path, To: preview.TargetPath}
+		plan = append(plan, move)
+		if o.config.Flat {
+			fileMoves = append(fileMoves, move)
+		}
+	}
+
+	o.summary.PlanWarnings = o.planWarnings(plan, fileMoves)
+	if len(o.summary.PlanWarnings) == 0 {
+		return nil
+	}
+	if err := o.config.ConfirmPlan(o.summary.PlanWarnings); err != nil {
+		return planNotConfirmedError{err}
+	}
+	return nil
+}
+
+// planNotConfirmedError carries the error ConfirmPlan stopped a run with, which
+// Execute returns as it is rather than as a scan error
+type planNotConfirmedError struct{ error }
+
+// PrintPlanWarnings prints the warnings of a plan. They are printed as errors, so
+// they still show on stderr with --quiet or --format=plan.
+func PrintPlanWarnings(warnings []PlanWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+	PrintRed("\n🚨 This plan looks destructive:")
+	for _, warning := range warnings {
+		PrintRed("  [%s confidence] %s", warning.Confidence, warning.Message)
+	}
+}
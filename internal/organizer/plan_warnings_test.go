@@ -0,0 +1,137 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPlanPlaceholderAuthors(t *testing.T) {
+	var plan []MoveSummary
+	for i := 0; i < 10; i++ {
+		author := "Unknown Author"
+		if i < 3 {
+			author = "Ursula K. Le Guin"
+		}
+		plan = append(plan, MoveSummary{
+			From: fmt.Sprintf("/in/book%d", i),
+			To:   filepath.Join("/out", author, fmt.Sprintf("Book %d", i)),
+		})
+	}
+
+	warnings := CheckPlan(plan, nil, "/out", nil)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, PlanConfidenceMedium, warnings[0].Confidence)
+	assert.Contains(t, warnings[0].Message, "7 of 10 books (70%)")
+
+	// Too few books to judge
+	assert.Empty(t, CheckPlan(plan[5:9], nil, "/out", nil))
+}
+
+func TestCheckPlanCollapsedFolder(t *testing.T) {
+	var plan, fileMoves []MoveSummary
+	for i := 0; i < collapsedHighBooks; i++ {
+		from := fmt.Sprintf("/in/track%03d.mp3", i)
+		to := fmt.Sprintf("/out/Various/track%03d.mp3", i)
+		plan = append(plan, MoveSummary{From: from, To: to})
+		fileMoves = append(fileMoves, MoveSummary{From: from, To: to})
+	}
+
+	warnings := CheckPlan(plan, fileMoves, "/out", nil)
+	require.Len(t, warnings, 2, "placeholder author and collapsed folder")
+	assert.Equal(t, PlanConfidenceHigh, warnings[1].Confidence)
+	assert.Equal(t, "100 books would land in one folder: /out/Various", warnings[1].Message)
+}
+
+func TestCheckPlanRelocatedLibrary(t *testing.T) {
+	var previous []LogEntry
+	var plan []MoveSummary
+	for i := 0; i < 10; i++ {
+		organized := fmt.Sprintf("/lib/Author %d/Book %d", i, i)
+		previous = append(previous, LogEntry{SourcePath: fmt.Sprintf("/in/book%d", i), TargetPath: organized})
+		plan = append(plan, MoveSummary{From: organized, To: fmt.Sprintf("/lib/Author %d/Series/Book %d", i, i)})
+	}
+
+	warnings := CheckPlan(plan, nil, "/lib", previous)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, PlanConfidenceHigh, warnings[0].Confidence)
+	assert.Contains(t, warnings[0].Message, "10 of the 10 books the previous run organized")
+
+	assert.Empty(t, CheckPlan(plan[:5], nil, "/lib", previous), "half the library moving is not flagged")
+}
+
+func TestPlanWarningsDryRun(t *testing.T) {
+	base := t.TempDir()
+	for i := 0; i < planWarningMinBooks; i++ {
+		createBookDir(t, base, fmt.Sprintf("book%d", i), fmt.Sprintf("Book %d", i), "Unknown Author")
+	}
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      base,
+		OutputDir:    t.TempDir(),
+		FieldMapping: DefaultFieldMapping(),
+		DryRun:       true,
+	})
+	require.NoError(t, err)
+
+	// A dry run prints the warnings prominently
+	output := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+	assert.Contains(t, output, "This plan looks destructive:")
+	assert.Contains(t, output, "[high confidence] 5 of 5 books (100%)")
+	assert.Len(t, NewRunReport(org.GetSummary(), true, nil).PlanWarnings, 1)
+}
+
+func TestConfirmPlanBeforeMoving(t *testing.T) {
+	for _, confirm := range []bool{false, true} {
+		t.Run(fmt.Sprint(confirm), func(t *testing.T) {
+			base, output := t.TempDir(), t.TempDir()
+			for i := 0; i < planWarningMinBooks; i++ {
+				createBookDir(t, base, fmt.Sprintf("book%d", i), fmt.Sprintf("Book %d", i), "Unknown Author")
+			}
+			var asked []PlanWarning
+			org, err := NewOrganizer(&OrganizerConfig{
+				BaseDir:      base,
+				OutputDir:    output,
+				FieldMapping: DefaultFieldMapping(),
+				ConfirmPlan: func(warnings []PlanWarning) error {
+					asked = warnings
+					// The run holds the library lock while it asks
+					_, err := os.Stat(filepath.Join(output, LockFileName))
+					assert.NoError(t, err)
+					if !confirm {
+						return errors.New("run cancelled")
+					}
+					return nil
+				},
+			})
+			require.NoError(t, err)
+
+			var runErr error
+			CaptureOutput(func() {
+				runErr = org.Execute()
+			})
+			require.Len(t, asked, 1)
+			assert.Equal(t, PlanConfidenceHigh, asked[0].Confidence)
+			assert.Contains(t, asked[0].Message, "5 of 5 books (100%)")
+			assert.Equal(t, asked, org.GetSummary().PlanWarnings)
+
+			_, statErr := os.Stat(filepath.Join(base, "book0", "audio.mp3"))
+			if !confirm {
+				assert.EqualError(t, runErr, "run cancelled")
+				assert.NoError(t, statErr, "nothing moves before the plan is confirmed")
+				return
+			}
+			require.NoError(t, runErr)
+			assert.True(t, os.IsNotExist(statErr))
+			assert.Len(t, org.GetSummary().Moves, planWarningMinBooks)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package organizer
+
+import "github.com/jeeftor/audiobook-organizer/internal/planning"
+
+// Metadata, templates, and layout rules live in the dependency-light planning
+// package so they can also be compiled to WebAssembly for the web UI.
+type (
+	FieldMapping     = planning.FieldMapping
+	Metadata         = planning.Metadata
+	Template         = planning.Template
+	TemplateRenderer = planning.TemplateRenderer
+	TemplateField    = planning.TemplateField
+	AuthorFormatter  = planning.AuthorFormatter
+	AuthorFormat     = planning.AuthorFormat
+	AudioInfo        = planning.AudioInfo
+	NamingPolicy     = planning.NamingPolicy
+	UnsafePathError  = planning.UnsafePathError
+	FieldExplanation = planning.FieldExplanation
+	CandidateResult  = planning.CandidateResult
+)
+
+const (
+	InvalidSeriesValue    = planning.InvalidSeriesValue
+	TrackPrefixFormat     = planning.TrackPrefixFormat
+	DiscTrackPrefixFormat = planning.DiscTrackPrefixFormat
+	FieldLiteralPrefix    = planning.FieldLiteralPrefix
+	CandidateUsed         = planning.CandidateUsed
+	CandidateEmpty        = planning.CandidateEmpty
+	CandidateNotTried     = planning.CandidateNotTried
+
+	AuthorFormatFirstLast = planning.AuthorFormatFirstLast
+	AuthorFormatLastFirst = planning.AuthorFormatLastFirst
+	AuthorFormatPreserve  = planning.AuthorFormatPreserve
+
+	CasingPreserve = planning.CasingPreserve
+	CasingTitle    = planning.CasingTitle
+	CasingSentence = planning.CasingSentence
+
+	SubtitleKeep  = planning.SubtitleKeep
+	SubtitleColon = planning.SubtitleColon
+	SubtitleDash  = planning.SubtitleDash
+	SubtitleDrop  = planning.SubtitleDrop
+)
+
+var (
+	DefaultFieldMapping         = planning.DefaultFieldMapping
+	AudioFieldMapping           = planning.AudioFieldMapping
+	EpubFieldMapping            = planning.EpubFieldMapping
+	FieldCandidates             = planning.FieldCandidates
+	NewMetadata                 = planning.NewMetadata
+	CleanSeriesName             = planning.CleanSeriesName
+	ExtractSeriesNumber         = planning.ExtractSeriesNumber
+	GetSeriesNumberFromMetadata = planning.GetSeriesNumberFromMetadata
+	ShouldAddTrackPrefix        = planning.ShouldAddTrackPrefix
+	TrackTotalFromMetadata      = planning.TrackTotalFromMetadata
+	AddTrackPrefix              = planning.AddTrackPrefix
+	HasTrackPrefix              = planning.HasTrackPrefix
+	ExtractTrackNumber          = planning.ExtractTrackNumber
+	RemoveTrackPrefix           = planning.RemoveTrackPrefix
+	ParseTemplate               = planning.ParseTemplate
+	NewTemplateRenderer         = planning.NewTemplateRenderer
+	ValidateTemplate            = planning.ValidateTemplate
+	GetAvailableFields          = planning.GetAvailableFields
+	NewAuthorFormatter          = planning.NewAuthorFormatter
+	DetectFormat                = planning.DetectFormat
+	ConvertToFirstLast          = planning.ConvertToFirstLast
+	ConvertToLastFirst          = planning.ConvertToLastFirst
+	ApplyCasing                 = planning.ApplyCasing
+	ApplyMetadataCasing         = planning.ApplyMetadataCasing
+	StripRedundantTitle         = planning.StripRedundantTitle
+	PathMetadata                = planning.PathMetadata
+	ApplySubtitleStyle          = planning.ApplySubtitleStyle
+	NameInitial                 = planning.NameInitial
+	FormatDuration              = planning.FormatDuration
+	CheckPathMetadata           = planning.CheckPathMetadata
+	CheckTargetPath             = planning.CheckTargetPath
+	NormalizeISBN               = planning.NormalizeISBN
+)
@@ -0,0 +1,187 @@
+package organizer
+
+import (
+	"sort"
+	"time"
+)
+
+// ProfilePhase is one stage of organizing a book, timed by ProfileReport
+type ProfilePhase string
+
+const (
+	// PhaseMetadata reads tags and metadata.json files, including each track's own tags
+	PhaseMetadata ProfilePhase = "metadata"
+	// PhasePlanning computes target paths and file names
+	PhasePlanning ProfilePhase = "planning"
+	// PhaseMoving moves, copies, or links the files and writes the undo log, or prints
+	// the planned moves in a dry run
+	PhaseMoving ProfilePhase = "moving"
+)
+
+// PhaseTimes are the milliseconds spent in each phase
+type PhaseTimes struct {
+	MetadataMS float64 `json:"metadata_ms"`
+	PlanningMS float64 `json:"planning_ms"`
+	MovingMS   float64 `json:"moving_ms"`
+}
+
+// BookProfile is how long one book took to organize
+type BookProfile struct {
+	Path string `json:"path"`
+	PhaseTimes
+	TotalMS float64 `json:"total_ms"`
+}
+
+// RunProfile breaks the time of a run down by phase and book, for ProfileReport.
+// OtherMS is the time outside the phases: walking directories, books left out,
+// saving the log, and removing empty directories. Books are listed slowest first.
+type RunProfile struct {
+	TotalMS    float64       `json:"total_ms"`
+	Phases     PhaseTimes    `json:"phases"`
+	OtherMS    float64       `json:"other_ms"`
+	Bottleneck ProfilePhase  `json:"bottleneck,omitempty"` // The phase that took longest
+	Books      []BookProfile `json:"books"`
+}
+
+// phaseDurations accumulates the time spent in each phase
+type phaseDurations map[ProfilePhase]time.Duration
+
+func (d phaseDurations) times() PhaseTimes {
+	return PhaseTimes{
+		MetadataMS: milliseconds(d[PhaseMetadata]),
+		PlanningMS: milliseconds(d[PhasePlanning]),
+		MovingMS:   milliseconds(d[PhaseMoving]),
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// bookTiming is the time spent on one book so far
+type bookTiming struct {
+	path   string
+	phases phaseDurations
+	total  time.Duration
+}
+
+// runProfiler times the phases of a run. Phases nest: entering one pauses the phase
+// it was entered from, so reading a track's tags while planning counts as metadata
+// and every moment is counted once. A nil profiler times nothing.
+type runProfiler struct {
+	started time.Time
+	totals  phaseDurations
+	books   []*bookTiming
+	current *bookTiming // Book being organized; nil between books
+	since   time.Time   // Start of the current book
+	stack   []ProfilePhase
+	entered time.Time // When the innermost phase was entered or resumed
+}
+
+func newRunProfiler(started time.Time) *runProfiler {
+	return &runProfiler{started: started, totals: make(phaseDurations)}
+}
+
+// beginBook starts timing a book whose metadata the scanner read in readTime
+func (p *runProfiler) beginBook(path string, readTime time.Duration) {
+	if p == nil {
+		return
+	}
+	p.current = &bookTiming{path: path, phases: phaseDurations{PhaseMetadata: readTime}}
+	p.books = append(p.books, p.current)
+	p.since = time.Now()
+}
+
+// endBook stops timing the current book
+func (p *runProfiler) endBook() {
+	if p == nil || p.current == nil {
+		return
+	}
+	p.current.total = time.Since(p.since) + p.current.phases[PhaseMetadata]
+	p.current = nil
+}
+
+// enter starts timing phase and returns the function that ends it, for use as
+// defer o.profile.enter(PhaseMoving)()
+func (p *runProfiler) enter(phase ProfilePhase) func() {
+	if p == nil {
+		return func() {}
+	}
+	p.pause()
+	p.stack = append(p.stack, phase)
+	return func() {
+		p.pause()
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+}
+
+// pause adds the time since the innermost phase was entered to it
+func (p *runProfiler) pause() {
+	now := time.Now()
+	if n := len(p.stack); n > 0 {
+		elapsed := now.Sub(p.entered)
+		p.totals[p.stack[n-1]] += elapsed
+		if p.current != nil {
+			p.current.phases[p.stack[n-1]] += elapsed
+		}
+	}
+	p.entered = now
+}
+
+// report builds the profile of the run. scanRead is the time the scanner spent
+// reading metadata, which the books only carry for themselves.
+func (p *runProfiler) report(scanRead time.Duration) *RunProfile {
+	if p == nil {
+		return nil
+	}
+	totals := make(phaseDurations, len(p.totals))
+	for phase, d := range p.totals {
+		totals[phase] = d
+	}
+	totals[PhaseMetadata] += scanRead
+
+	total := time.Since(p.started)
+	profile := &RunProfile{
+		TotalMS: milliseconds(total),
+		Phases:  totals.times(),
+		Books:   make([]BookProfile, 0, len(p.books)),
+	}
+	var inPhases time.Duration
+	var longest time.Duration
+	for _, phase := range []ProfilePhase{PhaseMetadata, PhasePlanning, PhaseMoving} {
+		inPhases += totals[phase]
+		if totals[phase] > longest {
+			longest, profile.Bottleneck = totals[phase], phase
+		}
+	}
+	profile.OtherMS = milliseconds(max(total-inPhases, 0))
+
+	for _, book := range p.books {
+		profile.Books = append(profile.Books, BookProfile{
+			Path:       book.path,
+			PhaseTimes: book.phases.times(),
+			TotalMS:    milliseconds(book.total),
+		})
+	}
+	sort.SliceStable(profile.Books, func(i, j int) bool {
+		return profile.Books[i].TotalMS > profile.Books[j].TotalMS
+	})
+	return profile
+}
+
+// Share returns the part of the run's time spent in phase, from 0 to 1
+func (p *RunProfile) Share(phase ProfilePhase) float64 {
+	if p == nil || p.TotalMS == 0 {
+		return 0
+	}
+	var ms float64
+	switch phase {
+	case PhaseMetadata:
+		ms = p.Phases.MetadataMS
+	case PhasePlanning:
+		ms = p.Phases.PlanningMS
+	case PhaseMoving:
+		ms = p.Phases.MovingMS
+	}
+	return ms / p.TotalMS
+}
@@ -0,0 +1,77 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunProfilerNestedPhases(t *testing.T) {
+	profiler := newRunProfiler(time.Now())
+	profiler.beginBook("/in/Dune", 5*time.Millisecond)
+
+	planned := profiler.enter(PhasePlanning)
+	time.Sleep(10 * time.Millisecond)
+	read := profiler.enter(PhaseMetadata)
+	time.Sleep(20 * time.Millisecond)
+	read()
+	planned()
+	profiler.endBook()
+
+	profile := profiler.report(5 * time.Millisecond)
+	require.Len(t, profile.Books, 1)
+	book := profile.Books[0]
+	assert.Equal(t, "/in/Dune", book.Path)
+	assert.GreaterOrEqual(t, book.MetadataMS, 25.0, "the scanner's read and the nested read")
+	assert.GreaterOrEqual(t, book.PlanningMS, 10.0)
+	assert.LessOrEqual(t, book.MetadataMS+book.PlanningMS, book.TotalMS, "a nested phase pauses the outer one")
+	assert.Equal(t, PhaseMetadata, profile.Bottleneck)
+	assert.Equal(t, book.MetadataMS, profile.Phases.MetadataMS)
+}
+
+func TestNilRunProfiler(t *testing.T) {
+	var profiler *runProfiler
+	profiler.beginBook("/in/Dune", time.Second)
+	profiler.enter(PhaseMoving)()
+	profiler.endBook()
+	assert.Nil(t, profiler.report(time.Second))
+}
+
+func TestExecuteProfileReport(t *testing.T) {
+	base := t.TempDir()
+	output := t.TempDir()
+	createBookDir(t, base, "dune", "Dune", "Frank Herbert")
+	createBookDir(t, base, "emma", "Emma", "Jane Austen")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:       base,
+		OutputDir:     output,
+		FieldMapping:  DefaultFieldMapping(),
+		ProfileReport: true,
+	})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	profile := NewRunReport(org.GetSummary(), false, nil).Profile
+	require.NotNil(t, profile)
+	require.Len(t, profile.Books, 2)
+	paths := []string{profile.Books[0].Path, profile.Books[1].Path}
+	assert.ElementsMatch(t, []string{filepath.Join(base, "dune"), filepath.Join(base, "emma")}, paths)
+	assert.GreaterOrEqual(t, profile.Books[0].TotalMS, profile.Books[1].TotalMS, "slowest first")
+	assert.Positive(t, profile.Phases.MovingMS)
+	assert.NotEmpty(t, profile.Bottleneck)
+
+	unprofiled, err := NewOrganizer(&OrganizerConfig{BaseDir: output, FieldMapping: DefaultFieldMapping(), DryRun: true})
+	require.NoError(t, err)
+	CaptureOutput(func() {
+		require.NoError(t, unprofiled.Execute())
+	})
+	assert.Nil(t, unprofiled.GetSummary().Profile)
+}
@@ -3,8 +3,11 @@ package organizer
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 )
 
 // PromptForDirectoryRemoval asks the user for confirmation before removing an empty directory
@@ -31,11 +34,99 @@ func (o *Organizer) PromptForDirectoryRemoval(dir string, isParent bool) bool {
 	return response == "y" || response == "yes"
 }
 
+// promptChoice is an answer to the move confirmation prompt
+type promptChoice int
+
+const (
+	promptNo        promptChoice = iota
+	promptYes                    // y: move this book
+	promptYesAll                 // a: move this and every later book
+	promptSkipRest               // s: skip this and every later book
+	promptYesAuthor              // A: move this and every later book by the same author
+)
+
+// parsePromptChoice reads an answer to the move prompt. Only the uppercase "A"
+// means "yes for this author"; everything else is case-insensitive, and anything
+// unrecognized is a no.
+func parsePromptChoice(response string) promptChoice {
+	response = strings.TrimSpace(response)
+	if response == "A" {
+		return promptYesAuthor
+	}
+	switch strings.ToLower(response) {
+	case "y", "yes":
+		return promptYes
+	case "a", "all":
+		return promptYesAll
+	case "s", "skip":
+		return promptSkipRest
+	case "author":
+		return promptYesAuthor
+	}
+	return promptNo
+}
+
+// promptMemory holds the batch answers given to the move prompt for the rest of a run
+type promptMemory struct {
+	yesAll   bool
+	skipRest bool
+	authors  map[string]bool
+}
+
+// promptAuthorKey identifies a book's authors for "yes for this author"
+func promptAuthorKey(metadata Metadata) string {
+	return strings.ToLower(strings.Join(metadata.Authors, ", "))
+}
+
+// remembered returns the answer an earlier batch choice gives for this book
+func (m *promptMemory) remembered(metadata Metadata) (bool, bool) {
+	switch {
+	case m.skipRest:
+		return false, true
+	case m.yesAll:
+		return true, true
+	case m.authors[promptAuthorKey(metadata)]:
+		return true, true
+	}
+	return false, false
+}
+
+// remember records a choice and reports whether the book should move
+func (m *promptMemory) remember(choice promptChoice, metadata Metadata) bool {
+	switch choice {
+	case promptYes:
+		return true
+	case promptYesAll:
+		m.yesAll = true
+		return true
+	case promptSkipRest:
+		m.skipRest = true
+		return false
+	case promptYesAuthor:
+		if key := promptAuthorKey(metadata); key != "" {
+			if m.authors == nil {
+				m.authors = make(map[string]bool)
+			}
+			m.authors[key] = true
+		}
+		return true
+	}
+	return false
+}
+
 // PromptForConfirmation asks the user for confirmation before moving files.
-// It displays the book metadata and the proposed move operation.
+// It displays the book metadata and the proposed move operation, and when the
+// target already exists, what it holds next to the incoming book.
 // Returns true if the user confirms with 'y' or 'yes' (case insensitive),
 // returns false for any other input including empty input or errors.
+// Batch answers are remembered for the rest of the run: 'a' moves every later
+// book, 's' skips every later book, and 'A' moves every later book by the same
+// authors without asking again.
 func (o *Organizer) PromptForConfirmation(metadata Metadata, sourcePath, targetPath string) bool {
+	if move, ok := o.promptMemory.remembered(metadata); ok {
+		return move
+	}
+
 	fmt.Println(RenderWarning("\n📖 Book found:"))
 
 	// Title
@@ -63,8 +154,10 @@ func (o *Organizer) PromptForConfirmation(metadata Metadata, sourcePath, targetP
 	fmt.Print("  ")
 	fmt.Print(RenderPrompt("To: "))
 	fmt.Println(RenderPath(targetPath))
+	o.writeTargetComparison(os.Stdout, metadata, sourcePath, targetPath)
 
-	fmt.Print(RenderPromptIcon("\n❓ Proceed with move? [y/N] "))
+	fmt.Println(RenderPrompt("\n  y=yes  n=no  a=yes to all  s=skip the rest  A=yes for this author"))
+	fmt.Print(RenderPromptIcon("❓ Proceed with move? [y/N/a/s/A] "))
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -73,6 +166,135 @@ func (o *Organizer) PromptForConfirmation(metadata Metadata, sourcePath, targetP
 		return false
 	}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes"
+	return o.promptMemory.remember(parsePromptChoice(response), metadata)
+}
+
+// maxComparedFiles caps the existing files listed when a move goes into a folder
+// that already holds files
+const maxComparedFiles = 10
+
+// folderFile is one file of a folder shown in the target comparison
+type folderFile struct {
+	name string
+	size int64
+}
+
+// listFolderFiles returns the regular files directly in path, or path itself when it
+// is a file, with their total size. It reports false when path doesn't exist.
+func listFolderFiles(path string) ([]folderFile, int64, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, false
+	}
+	if !info.IsDir() {
+		return []folderFile{{filepath.Base(path), info.Size()}}, info.Size(), true
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, 0, false
+	}
+	var files []folderFile
+	var total int64
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, folderFile{entry.Name(), info.Size()})
+			total += info.Size()
+		}
+	}
+	return files, total, true
+}
+
+// existingTargetMetadata reads the metadata of the book already in dir from its
+// metadata.json, or else from the tags of its first audio file
+func (o *Organizer) existingTargetMetadata(dir string, files []folderFile) (Metadata, bool) {
+	if metadata, err := o.readMetadataFromJSON(filepath.Join(dir, MetadataFileName)); err == nil {
+		return metadata, true
+	}
+	for _, file := range files {
+		if !o.config.Extensions.IsAudio(filepath.Ext(file.name)) {
+			continue
+		}
+		provider := NewAudioMetadataProvider(filepath.Join(dir, file.name))
+		if metadata, err := ExtractMappedMetadata(provider, o.config.FieldMapping); err == nil {
+			return metadata, true
+		}
+		break
+	}
+	return Metadata{}, false
+}
+
+// writeTargetComparison shows what already exists at targetPath next to the incoming
+// book: the metadata of both side by side, with differing rows marked, and the
+// existing files, marking those the incoming book has a file of the same name for.
+// It writes nothing when the target doesn't exist yet or is remote.
+func (o *Organizer) writeTargetComparison(w io.Writer, metadata Metadata, sourcePath, targetPath string) {
+	if o.hasRemoteTarget() {
+		return
+	}
+	existing, existingSize, ok := listFolderFiles(targetPath)
+	if !ok || len(existing) == 0 {
+		return
+	}
+	incoming, incomingSize, _ := listFolderFiles(sourcePath)
+
+	fmt.Fprintln(w, RenderWarning(fmt.Sprintf("\n⚠️  The target already holds %d file(s) (%s):", len(existing), formatBytes(uint64(existingSize)))))
+
+	rows := [][3]string{{"Files", fmt.Sprintf("%d (%s)", len(existing), formatBytes(uint64(existingSize))), fmt.Sprintf("%d (%s)", len(incoming), formatBytes(uint64(incomingSize)))}}
+	if current, found := o.existingTargetMetadata(targetPath, existing); found {
+		rows = append([][3]string{
+			{"Title", current.Title, metadata.Title},
+			{"Authors", strings.Join(current.Authors, ", "), strings.Join(metadata.Authors, ", ")},
+			{"Series", firstSeries(current), firstSeries(metadata)},
+			{"Identifiers", formatIdentifiers(current), formatIdentifiers(metadata)},
+		}, rows...)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "   \t\tExisting\tIncoming")
+	for _, row := range rows {
+		mark := " "
+		if row[1] != row[2] {
+			mark = "≠"
+		}
+		fmt.Fprintf(table, "  %s\t%s\t%s\t%s\n", mark, row[0], orDash(row[1]), orDash(row[2]))
+	}
+	table.Flush()
+
+	incomingNames := make(map[string]bool, len(incoming))
+	for _, file := range incoming {
+		incomingNames[file.name] = true
+	}
+	fmt.Fprintln(w, RenderPrompt("\n  Existing files:"))
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, file := range existing {
+		if i == maxComparedFiles {
+			fmt.Fprintf(table, "    … and %d more\n", len(existing)-maxComparedFiles)
+			break
+		}
+		note := ""
+		if incomingNames[file.name] {
+			note = "incoming file of the same name"
+		}
+		fmt.Fprintf(table, "    %s\t%s\t%s\n", file.name, formatBytes(uint64(file.size)), note)
+	}
+	table.Flush()
+}
+
+// firstSeries returns the cleaned first series of metadata, or ""
+func firstSeries(metadata Metadata) string {
+	if len(metadata.Series) == 0 {
+		return ""
+	}
+	return CleanSeriesName(metadata.Series[0])
+}
+
+// orDash shows empty table cells as "-"
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
 }
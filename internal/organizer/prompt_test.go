@@ -3,6 +3,7 @@ package organizer
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -123,3 +124,142 @@ func TestPromptConfirmation(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePromptChoice(t *testing.T) {
+	tests := map[string]promptChoice{
+		"y\n":      promptYes,
+		"YES\n":    promptYes,
+		"a\n":      promptYesAll,
+		"all\n":    promptYesAll,
+		"s\n":      promptSkipRest,
+		"Skip\n":   promptSkipRest,
+		"A\n":      promptYesAuthor,
+		"author\n": promptYesAuthor,
+		"n\n":      promptNo,
+		"\n":       promptNo,
+		"maybe\n":  promptNo,
+	}
+	for input, want := range tests {
+		if got := parsePromptChoice(input); got != want {
+			t.Errorf("parsePromptChoice(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestPromptRemembersBatchChoices(t *testing.T) {
+	herbert := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}}
+	herbert2 := Metadata{Title: "Dune Messiah", Authors: []string{"Frank Herbert"}}
+	weir := Metadata{Title: "The Martian", Authors: []string{"Andy Weir"}}
+
+	tests := []struct {
+		name   string
+		inputs []string // Answer read for each prompted book; "" when no prompt is expected
+		books  []Metadata
+		want   []bool
+	}{
+		{
+			name:   "yes to all",
+			inputs: []string{"n\n", "a\n", ""},
+			books:  []Metadata{herbert, herbert2, weir},
+			want:   []bool{false, true, true},
+		},
+		{
+			name:   "skip the rest",
+			inputs: []string{"y\n", "s\n", ""},
+			books:  []Metadata{herbert, weir, herbert2},
+			want:   []bool{true, false, false},
+		},
+		{
+			name:   "yes for this author",
+			inputs: []string{"A\n", "n\n", ""},
+			books:  []Metadata{herbert, weir, herbert2},
+			want:   []bool{true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			org, err := NewOrganizer(&OrganizerConfig{BaseDir: tempDir, Prompt: true})
+			if err != nil {
+				t.Fatalf("NewOrganizer() error = %v", err)
+			}
+
+			oldStdin, oldStdout := os.Stdin, os.Stdout
+			defer func() { os.Stdin, os.Stdout = oldStdin, oldStdout }()
+			_, w, _ := os.Pipe()
+			defer w.Close()
+			os.Stdout = w
+
+			for i, book := range tt.books {
+				// An empty stdin makes an unexpected prompt answer no
+				inputPath := filepath.Join(tempDir, "input")
+				if err := os.WriteFile(inputPath, []byte(tt.inputs[i]), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				input, err := os.Open(inputPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				os.Stdin = input
+
+				got := org.PromptForConfirmation(book, "/source", "/target")
+				input.Close()
+				if got != tt.want[i] {
+					t.Errorf("book %d (%s) = %v, want %v", i, book.Title, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteTargetComparison(t *testing.T) {
+	tempDir := t.TempDir()
+	source := filepath.Join(tempDir, "incoming")
+	target := filepath.Join(tempDir, "Frank Herbert", "Dune")
+	writeBook(t, source, map[string]interface{}{"title": "Dune", "authors": []string{"Frank Herbert"}, "series": []string{"Dune #1"}}, "01.mp3", "02.mp3")
+	writeBook(t, target, map[string]interface{}{"title": "Dune", "authors": []string{"Frank Herbert"}}, "01.mp3", "cover.jpg")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: tempDir, Prompt: true, FieldMapping: DefaultFieldMapping()})
+	if err != nil {
+		t.Fatalf("NewOrganizer() error = %v", err)
+	}
+	metadata := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}, Series: []string{"Dune #1"}}
+
+	var out strings.Builder
+	org.writeTargetComparison(&out, metadata, source, target)
+	got := out.String()
+	for _, want := range []string{"The target already holds 3 file(s)", "Existing", "Incoming", "cover.jpg"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("comparison missing %q:\n%s", want, got)
+		}
+	}
+	// Rows that differ are marked, and existing files the book would replace noted
+	for prefix, differs := range map[string]bool{"Title": false, "Authors": false, "Series": true, "Files": true} {
+		if line := lineWith(got, prefix); strings.Contains(line, "≠") != differs {
+			t.Errorf("%s row %q: marked as different = %v, want %v", prefix, line, !differs, differs)
+		}
+	}
+	if line := lineWith(got, "01.mp3"); !strings.Contains(line, "incoming file of the same name") {
+		t.Errorf("01.mp3 row %q doesn't note the incoming file", line)
+	}
+	if line := lineWith(got, "cover.jpg"); strings.Contains(line, "incoming") {
+		t.Errorf("cover.jpg row %q notes an incoming file", line)
+	}
+
+	out.Reset()
+	org.writeTargetComparison(&out, metadata, source, filepath.Join(tempDir, "missing"))
+	if out.Len() != 0 {
+		t.Errorf("comparison for a new target = %q, want nothing", out.String())
+	}
+}
+
+// lineWith returns the first line of text containing substr
+func lineWith(text, substr string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, substr) {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProtectedDir records a directory left out because a media server manages it
+type ProtectedDir struct {
+	Path   string `json:"path"`
+	Server string `json:"server"` // What manages the directory, e.g. "Audiobookshelf metadata"
+}
+
+// absMetadataSubdirs are the folders Audiobookshelf keeps in its metadata directory
+var absMetadataSubdirs = []string{"items", "authors", "cache", "backups", "logs", "streams"}
+
+// ProtectedDirServer reports what manages dir when it belongs to a media server
+// rather than holding books: the Audiobookshelf metadata and config folders and its
+// cover caches, Plex Media Server data, or a Calibre library. Moving anything inside
+// these breaks the server, so the scanner leaves them alone. It returns "" for an
+// ordinary directory.
+func ProtectedDirServer(dir string) string {
+	name := filepath.Base(dir)
+	lower := strings.ToLower(name)
+
+	switch {
+	case strings.HasPrefix(lower, ".abs"):
+		return "Audiobookshelf"
+	case lower == "metadata" && countDirs(dir, absMetadataSubdirs...) >= 2:
+		return "Audiobookshelf metadata"
+	case lower == "cache" && countDirs(dir, "covers", "images") > 0:
+		return "cover cache"
+	case name == "Plex Media Server" || lower == ".plex":
+		return "Plex Media Server"
+	case lower == ".calnotes" || lower == ".caltrash":
+		return "Calibre"
+	}
+
+	if fileExists(filepath.Join(dir, "absdatabase.sqlite")) {
+		return "Audiobookshelf config"
+	}
+	if fileExists(filepath.Join(dir, "metadata.db")) {
+		return "Calibre library"
+	}
+	return ""
+}
+
+// countDirs counts how many of the named subdirectories exist in dir
+func countDirs(dir string, names ...string) int {
+	count := 0
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+			count++
+		}
+	}
+	return count
+}
+
+// fileExists reports whether path is an existing regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,74 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedDirServer(t *testing.T) {
+	root := t.TempDir()
+	mkdirs := func(paths ...string) {
+		for _, path := range paths {
+			require.NoError(t, os.MkdirAll(filepath.Join(root, path), 0o755))
+		}
+	}
+	touch := func(path string) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte("x"), 0o644))
+	}
+
+	mkdirs("abs/metadata/items", "abs/metadata/authors", "abs/config", "abs/.abs-tmp",
+		"Book/cache/covers", "Plex Media Server", "Calibre Library/.calnotes",
+		"Books/metadata", "Books/Emma")
+	touch("abs/config/absdatabase.sqlite")
+	touch("Calibre Library/metadata.db")
+
+	tests := []struct {
+		path   string
+		server string
+	}{
+		{"abs/metadata", "Audiobookshelf metadata"},
+		{"abs/config", "Audiobookshelf config"},
+		{"abs/.abs-tmp", "Audiobookshelf"},
+		{"Book/cache", "cover cache"},
+		{"Plex Media Server", "Plex Media Server"},
+		{"Calibre Library", "Calibre library"},
+		{"Calibre Library/.calnotes", "Calibre"},
+		{"Books/metadata", ""}, // A book folder that happens to be called metadata
+		{"Books/Emma", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.server, ProtectedDirServer(filepath.Join(root, tt.path)))
+		})
+	}
+}
+
+func TestScannerSkipsProtectedDirs(t *testing.T) {
+	root := t.TempDir()
+	writeBook := func(dir string) {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataFileName),
+			[]byte(`{"title":"Book","authors":["Author"]}`), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "book.mp3"), []byte("audio"), 0o644))
+	}
+	writeBook(filepath.Join(root, "library", "Book"))
+	writeBook(filepath.Join(root, "metadata", "items", "li_1"))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "metadata", "cache"), 0o755))
+
+	result, err := NewScanner(ScanOptions{}).Scan(root)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, filepath.Join(root, "library", "Book"), result.Books[0].Path)
+	assert.Equal(t, []ProtectedDir{{Path: filepath.Join(root, "metadata"), Server: "Audiobookshelf metadata"}}, result.Protected)
+
+	result, err = NewScanner(ScanOptions{AllowProtected: true}).Scan(root)
+	require.NoError(t, err)
+	assert.Len(t, result.Books, 2)
+	assert.Empty(t, result.Protected)
+}
@@ -0,0 +1,103 @@
+package organizer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProvenanceFileName is the file KeepProvenance writes into each organized book folder
+const ProvenanceFileName = "original-folder.txt"
+
+// maxProvenanceTextSize caps the size of the leftover text files copied into the
+// provenance file, so a stray log or e-book in a download folder isn't duplicated
+const maxProvenanceTextSize = 64 << 10
+
+// provenanceTextExtensions are the leftover files worth keeping, such as release
+// notes naming the narrator or bitrate
+var provenanceTextExtensions = map[string]bool{".txt": true, ".nfo": true}
+
+// writeProvenance appends the name of the folder a logged book came from, and the
+// small text files left behind in it, to the provenance file in the book's target
+// when KeepProvenance is set. Download folder names often carry the narrator,
+// edition, or bitrate the tags lack, and RemoveEmpty would otherwise delete them
+// with the folder. Entries of one album share the file, one section per folder.
+// The entry records the file so undo removes it again.
+func (o *Organizer) writeProvenance(entry *LogEntry) {
+	source, target := filepath.Clean(entry.SourcePath), filepath.Clean(entry.TargetPath)
+	if !o.config.KeepProvenance || o.hasRemoteTarget() || source == target || source == o.config.BaseDir {
+		return
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Original folder: %s\n", filepath.Base(source))
+	fmt.Fprintf(&text, "Path: %s\n", source)
+	fmt.Fprintf(&text, "Organized: %s\n", entry.Timestamp.Format(time.RFC3339))
+	for _, name := range leftoverTextFiles(source) {
+		content, err := os.ReadFile(filepath.Join(source, name))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&text, "\n--- %s ---\n%s", name, content)
+		if !strings.HasSuffix(string(content), "\n") {
+			text.WriteString("\n")
+		}
+	}
+
+	path := filepath.Join(target, ProvenanceFileName)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err == nil {
+		if info, statErr := file.Stat(); statErr == nil && info.Size() > 0 {
+			_, err = file.WriteString("\n")
+		}
+		if err == nil {
+			_, err = file.WriteString(text.String())
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		PrintYellow("⚠️  Warning: couldn't write %s in %s: %v", ProvenanceFileName, target, err)
+		return
+	}
+	entry.Provenance = ProvenanceFileName
+}
+
+// leftoverTextFiles lists the small text files still in dir after its book moved
+func leftoverTextFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || strings.HasPrefix(name, ".") ||
+			!provenanceTextExtensions[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+		if info, err := entry.Info(); err != nil || info.Size() > maxProvenanceTextSize {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// removeProvenance deletes the provenance file an undone log entry wrote
+func (o *Organizer) removeProvenance(entry LogEntry) {
+	if entry.Provenance == "" {
+		return
+	}
+	path := filepath.Join(entry.TargetPath, entry.Provenance)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		o.recordError("❌ Error removing %s: %v", path, err)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepProvenance(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	source := filepath.Join(baseDir, "Dune (2007) [Scott Brick] 64kbps")
+	writeBook(t, source, map[string]interface{}{"title": "Dune", "authors": []string{"Frank Herbert"}}, "audio.mp3", "release.nfo")
+
+	config := OrganizerConfig{
+		BaseDir:        baseDir,
+		OutputDir:      outputDir,
+		FieldMapping:   DefaultFieldMapping(),
+		KeepProvenance: true,
+		Extensions:     ExtensionPolicy{".nfo": ExtensionIgnore},
+	}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	target := filepath.Join(outputDir, "Frank Herbert", "Dune")
+	data, err := os.ReadFile(filepath.Join(target, ProvenanceFileName))
+	require.NoError(t, err)
+	provenance := string(data)
+	assert.Contains(t, provenance, "Original folder: Dune (2007) [Scott Brick] 64kbps\n")
+	assert.Contains(t, provenance, "Path: "+source+"\n")
+	assert.Contains(t, provenance, "--- release.nfo ---\nrelease.nfo\n")
+
+	entries, err := ReadLogEntries(org.GetLogPath())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ProvenanceFileName, entries[0].Provenance)
+
+	config.Undo = true
+	undo, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, undo.Execute())
+	assert.FileExists(t, filepath.Join(source, "audio.mp3"))
+	assert.NoFileExists(t, filepath.Join(target, ProvenanceFileName))
+}
+
+func TestKeepProvenanceOff(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	createBookDir(t, baseDir, "Dune [Unabridged]", "Dune", "Frank Herbert")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: baseDir, OutputDir: outputDir, FieldMapping: DefaultFieldMapping()})
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	assert.NoFileExists(t, filepath.Join(outputDir, "Frank Herbert", "Dune", ProvenanceFileName))
+	entries, err := ReadLogEntries(org.GetLogPath())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Provenance)
+}
@@ -0,0 +1,32 @@
+package organizer
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+// Quarantine records a book left where it is because its metadata would name a path
+// outside the output directory, or a folder or file no file system should hold
+type Quarantine struct {
+	Path   string `json:"path"`
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// quarantine records the book at path as quarantined when err is an unsafe path, and
+// reports whether it was
+func (o *Organizer) quarantine(path string, err error) bool {
+	var unsafe *UnsafePathError
+	if !errors.As(err, &unsafe) {
+		return false
+	}
+	PrintRed("🚫 Quarantined %s: its %s %q %s", filepath.Base(path), unsafe.Field, unsafe.Value, unsafe.Reason)
+	o.summary.Quarantined = append(o.summary.Quarantined, Quarantine{
+		Path:   path,
+		Field:  unsafe.Field,
+		Value:  unsafe.Value,
+		Reason: unsafe.Reason,
+	})
+	return true
+}
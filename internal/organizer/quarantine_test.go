@@ -0,0 +1,62 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuarantineUnsafeMetadata(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "in")
+	output := filepath.Join(root, "out")
+	require.NoError(t, os.MkdirAll(output, 0o755))
+	evil := createBookDir(t, base, "evil", "../../../escaped", "Mallory")
+	createBookDir(t, base, "good", "Dune", "Frank Herbert")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      base,
+		OutputDir:    output,
+		Layout:       "author-title",
+		FieldMapping: DefaultFieldMapping(),
+	})
+	require.NoError(t, err)
+	printed := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	summary := org.GetSummary()
+	require.Len(t, summary.Quarantined, 1)
+	assert.Equal(t, Quarantine{Path: evil, Field: "title", Value: "../../../escaped", Reason: `contains a ".." path element`}, summary.Quarantined[0])
+	assert.Empty(t, summary.Errors, "a quarantined book isn't an error")
+	assert.Contains(t, printed, "Quarantined for metadata naming an unsafe path, left in place: 1")
+
+	assert.DirExists(t, evil, "the quarantined book stays where it is")
+	assert.NoDirExists(t, filepath.Join(root, "escaped"))
+	assert.DirExists(t, filepath.Join(output, "Frank Herbert", "Dune"))
+}
+
+func TestQuarantineMetadataFromCaller(t *testing.T) {
+	base := t.TempDir()
+	book := createBookDir(t, base, "book", "Dune", "Frank Herbert")
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      base,
+		OutputDir:    t.TempDir(),
+		FieldMapping: DefaultFieldMapping(),
+		DryRun:       true,
+	})
+	require.NoError(t, err)
+
+	metadata := Metadata{Title: "Dune", Authors: []string{`C:\Users`}}
+	CaptureOutput(func() {
+		require.NoError(t, org.OrganizePathWithMetadata(book, metadata))
+	})
+	require.Len(t, org.GetSummary().Quarantined, 1)
+	assert.Equal(t, "author", org.GetSummary().Quarantined[0].Field)
+	assert.Empty(t, org.GetSummary().Moves)
+}
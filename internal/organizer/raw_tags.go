@@ -0,0 +1,246 @@
+// internal/organizer/raw_tags.go
+package organizer
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/dhowden/tag"
+)
+
+// Raw field names used for movement-based series detection.
+const (
+	MovementNameField   = "MVNM"
+	MovementNumberField = "MVIN"
+)
+
+// knownMP4Atoms lists the standard MP4 atom keys returned by the tag library.
+// Anything else in an MP4 raw tag map came from a "----" freeform atom.
+var knownMP4Atoms = map[string]bool{
+	"aART":       true,
+	"trkn":       true,
+	"trkn_count": true,
+	"disk":       true,
+	"disk_count": true,
+	"cprt":       true,
+	"covr":       true,
+	"keyw":       true,
+	"tmpo":       true,
+	"cpil":       true,
+}
+
+// exposeRawTags copies user-defined tag fields into rawData under their real names.
+// ID3v2 TXXX frames are keyed by their description (e.g. "SERIES"), MP4 freeform
+// atoms by their name (e.g. "series-part"), and ID3v2 MVNM/MVIN movement frames by
+// their frame ID. Existing keys in rawData are never overwritten.
+func exposeRawTags(format tag.Format, rawTags map[string]interface{}, rawData map[string]interface{}) {
+	setIfMissing := func(key, value string) {
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "" || value == "" {
+			return
+		}
+		if _, exists := rawData[key]; !exists {
+			rawData[key] = value
+		}
+	}
+
+	for key, val := range rawTags {
+		switch format {
+		case tag.ID3v2_2, tag.ID3v2_3, tag.ID3v2_4:
+			frameID := stripFrameSuffix(key)
+			switch {
+			case frameID == "TXXX" || frameID == "TXX":
+				if comm, ok := val.(*tag.Comm); ok {
+					setIfMissing(comm.Description, comm.Text)
+				}
+			case frameID == MovementNameField || frameID == MovementNumberField:
+				if b, ok := val.([]byte); ok {
+					setIfMissing(frameID, decodeID3TextFrame(b))
+				}
+			}
+		case asfFormat, riffInfoFormat:
+			if str, ok := val.(string); ok {
+				setIfMissing(key, str)
+			}
+		case tag.MP4:
+			if strings.HasPrefix(key, "\xa9") || knownMP4Atoms[key] {
+				continue
+			}
+			if str, ok := val.(string); ok {
+				setIfMissing(key, str)
+			}
+		}
+	}
+}
+
+// stripFrameSuffix removes the "_N" suffix the tag library appends to repeated ID3 frames.
+func stripFrameSuffix(key string) string {
+	if idx := strings.Index(key, "_"); idx > 0 {
+		return key[:idx]
+	}
+	return key
+}
+
+// decodeID3TextFrame decodes a raw ID3v2 text frame payload (encoding byte + text).
+func decodeID3TextFrame(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	enc, data := b[0], b[1:]
+	switch enc {
+	case 1, 2: // UTF-16 with BOM, UTF-16BE
+		var order binary.ByteOrder = binary.BigEndian
+		if enc == 1 && len(data) >= 2 {
+			if data[0] == 0xFF && data[1] == 0xFE {
+				order = binary.LittleEndian
+			}
+			if (data[0] == 0xFF && data[1] == 0xFE) || (data[0] == 0xFE && data[1] == 0xFF) {
+				data = data[2:]
+			}
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			units = append(units, order.Uint16(data[i:i+2]))
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	default: // ISO-8859-1 and UTF-8
+		return strings.TrimRight(string(data), "\x00")
+	}
+}
+
+// lookupRawFold returns the first string value whose key matches one of names, ignoring case.
+func lookupRawFold(rawData map[string]interface{}, names ...string) (string, bool) {
+	for _, name := range names {
+		for key, val := range rawData {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			if str, ok := val.(string); ok && strings.TrimSpace(str) != "" {
+				return strings.TrimSpace(str), true
+			}
+		}
+	}
+	return "", false
+}
+
+// applyMovementSeries maps MVNM/MVIN movement tags onto series and series_index.
+// Audiobook taggers such as Mp3tag and Audiobookshelf store the series name in the
+// movement name and the book number in the movement index. An explicit series tag wins.
+func applyMovementSeries(metadata *Metadata, hasExplicitSeries bool) {
+	movementName, ok := lookupRawFold(metadata.RawData, MovementNameField)
+	if !ok || hasExplicitSeries {
+		return
+	}
+
+	metadata.Series = []string{movementName}
+	metadata.RawData["series"] = movementName
+
+	if _, exists := metadata.RawData["series_index"]; exists {
+		return
+	}
+	if movementNumber, ok := lookupRawFold(metadata.RawData, MovementNumberField); ok {
+		// MVIN may be written as "3" or "3/12"
+		movementNumber = strings.TrimSpace(strings.Split(movementNumber, "/")[0])
+		if idx, err := strconv.ParseFloat(movementNumber, 64); err == nil && idx > 0 {
+			metadata.RawData["series_index"] = idx
+		}
+	}
+}
+
+// readMP4MovementAtoms reads the ©mvn (movement name) and ©mvi (movement index)
+// atoms from an MP4 file. The tag library skips these atoms, so they are parsed here.
+func readMP4MovementAtoms(r io.ReadSeeker) map[string]string {
+	result := make(map[string]string)
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return result
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return result
+	}
+	walkMP4Atoms(r, end, result)
+	return result
+}
+
+// walkMP4Atoms descends through moov/udta/meta/ilst collecting movement atoms until limit.
+func walkMP4Atoms(r io.ReadSeeker, limit int64, result map[string]string) {
+	header := make([]byte, 8)
+	for {
+		start, err := r.Seek(0, io.SeekCurrent)
+		if err != nil || start+8 > limit {
+			return
+		}
+		if _, err := io.ReadFull(r, header); err != nil {
+			return
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		name := string(header[4:8])
+		if size < 8 || start+size > limit {
+			return
+		}
+		atomEnd := start + size
+
+		switch name {
+		case "meta":
+			// meta carries a 4-byte version/flags field before its children
+			if _, err := r.Seek(4, io.SeekCurrent); err != nil {
+				return
+			}
+			fallthrough
+		case "moov", "udta", "ilst":
+			walkMP4Atoms(r, atomEnd, result)
+		case "\xa9mvn", "\xa9mvi":
+			if value := readMP4DataAtom(r, atomEnd, name == "\xa9mvi"); value != "" {
+				if name == "\xa9mvn" {
+					result[MovementNameField] = value
+				} else {
+					result[MovementNumberField] = value
+				}
+			}
+		}
+
+		if _, err := r.Seek(atomEnd, io.SeekStart); err != nil {
+			return
+		}
+	}
+}
+
+// maxMP4TagPayload bounds the movement atoms read, far above any real name or index,
+// so a corrupt size can't make the reader allocate gigabytes
+const maxMP4TagPayload = 1 << 20
+
+// readMP4DataAtom reads the child "data" atom of an ilst entry as text or an integer.
+// The atom must end by parentEnd, the end offset of the entry holding it.
+func readMP4DataAtom(r io.ReadSeeker, parentEnd int64, numeric bool) string {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return ""
+	}
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return ""
+	}
+	size := int64(binary.BigEndian.Uint32(header[:4]))
+	if string(header[4:8]) != "data" || size < 16 || start+size > parentEnd || size-16 > maxMP4TagPayload {
+		return ""
+	}
+	payload := make([]byte, size-16)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return ""
+	}
+	if numeric {
+		var n uint64
+		for _, b := range payload {
+			n = n<<8 | uint64(b)
+		}
+		if n == 0 {
+			return ""
+		}
+		return strconv.FormatUint(n, 10)
+	}
+	return strings.TrimSpace(string(payload))
+}
@@ -0,0 +1,128 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/dhowden/tag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExposeRawTagsID3TXXX(t *testing.T) {
+	rawTags := map[string]interface{}{
+		"TXXX":   &tag.Comm{Description: "SERIES", Text: "The Expanse"},
+		"TXXX_0": &tag.Comm{Description: "series-part", Text: "3"},
+		"TXXX_1": &tag.Comm{Description: "title", Text: "Should not override"},
+		"MVNM":   append([]byte{3}, []byte("Discworld")...),
+		"MVIN":   append([]byte{0}, []byte("7/41\x00")...),
+	}
+	rawData := map[string]interface{}{"title": "Original"}
+
+	exposeRawTags(tag.ID3v2_4, rawTags, rawData)
+
+	assert.Equal(t, "The Expanse", rawData["SERIES"])
+	assert.Equal(t, "3", rawData["series-part"])
+	assert.Equal(t, "Original", rawData["title"])
+	assert.Equal(t, "Discworld", rawData["MVNM"])
+	assert.Equal(t, "7/41", rawData["MVIN"])
+}
+
+func TestExposeRawTagsMP4Freeform(t *testing.T) {
+	rawTags := map[string]interface{}{
+		"\xa9alb":     "Album",
+		"trkn":        3,
+		"SERIES":      "Mistborn",
+		"series-part": "2",
+	}
+	rawData := map[string]interface{}{}
+
+	exposeRawTags(tag.MP4, rawTags, rawData)
+
+	assert.Equal(t, "Mistborn", rawData["SERIES"])
+	assert.Equal(t, "2", rawData["series-part"])
+	assert.NotContains(t, rawData, "\xa9alb")
+	assert.NotContains(t, rawData, "trkn")
+}
+
+func TestDecodeID3TextFrameUTF16(t *testing.T) {
+	payload := []byte{1, 0xFF, 0xFE, 'D', 0, 'u', 0, 'n', 0, 'e', 0}
+	assert.Equal(t, "Dune", decodeID3TextFrame(payload))
+}
+
+func TestApplyMovementSeries(t *testing.T) {
+	tests := []struct {
+		name           string
+		rawData        map[string]interface{}
+		explicit       bool
+		expectedSeries []string
+		expectedIndex  interface{}
+	}{
+		{
+			name:           "movement maps to series and index",
+			rawData:        map[string]interface{}{"MVNM": "Discworld", "MVIN": "7/41"},
+			expectedSeries: []string{"Discworld"},
+			expectedIndex:  7.0,
+		},
+		{
+			name:           "explicit series wins",
+			rawData:        map[string]interface{}{"MVNM": "Discworld", "MVIN": "7"},
+			explicit:       true,
+			expectedSeries: []string{"Album Series"},
+		},
+		{
+			name:           "no movement keeps album fallback",
+			rawData:        map[string]interface{}{},
+			expectedSeries: []string{"Album Series"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := Metadata{Series: []string{"Album Series"}, RawData: tt.rawData}
+			applyMovementSeries(&metadata, tt.explicit)
+
+			assert.Equal(t, tt.expectedSeries, metadata.Series)
+			assert.Equal(t, tt.expectedIndex, metadata.RawData["series_index"])
+		})
+	}
+}
+
+func TestReadMP4MovementAtoms(t *testing.T) {
+	atom := func(name string, children ...[]byte) []byte {
+		body := bytes.Join(children, nil)
+		buf := make([]byte, 8, 8+len(body))
+		binary.BigEndian.PutUint32(buf, uint32(8+len(body)))
+		copy(buf[4:], name)
+		return append(buf, body...)
+	}
+	data := func(payload []byte) []byte {
+		return atom("data", append(make([]byte, 8), payload...))
+	}
+
+	ilst := atom("ilst",
+		atom("\xa9nam", data([]byte("Title"))),
+		atom("\xa9mvn", data([]byte("The Stormlight Archive"))),
+		atom("\xa9mvi", data([]byte{0, 4})),
+	)
+	meta := atom("meta", append(make([]byte, 4), ilst...))
+	file := append(atom("ftyp", []byte("M4A ")), atom("moov", atom("udta", meta))...)
+
+	result := readMP4MovementAtoms(bytes.NewReader(file))
+
+	assert.Equal(t, "The Stormlight Archive", result[MovementNameField])
+	assert.Equal(t, "4", result[MovementNumberField])
+
+	// A data atom claiming more bytes than its entry holds is not read
+	corrupt := data([]byte("Mistborn"))
+	binary.BigEndian.PutUint32(corrupt, 0xFFFFFFF0)
+	ilst = atom("ilst", atom("\xa9mvn", corrupt), atom("\xa9mvi", data([]byte{0, 4})))
+	meta = atom("meta", append(make([]byte, 4), ilst...))
+	file = append(atom("ftyp", []byte("M4A ")), atom("moov", atom("udta", meta))...)
+
+	result = readMP4MovementAtoms(bytes.NewReader(file))
+	assert.Empty(t, result[MovementNameField])
+	assert.Equal(t, "4", result[MovementNumberField], "later entries are still read")
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/jeeftor/audiobook-organizer/internal/planning"
 )
 
 // RenamerConfig contains all configuration for renaming operations
@@ -19,12 +22,16 @@ type RenamerConfig struct {
 	Recursive           bool                 // Recursively process subdirectories
 	FieldMapping        FieldMapping         // Field mapping for metadata
 	ReplaceSpace        string               // Character to replace spaces
+	Subtitle            string               // How {title} carries the subtitle: "keep" (default), "colon", "dash", or "drop"
 	StrictMode          bool                 // Error on missing template fields
 	PreservePath        bool                 // Only rename filename, keep directory
 	PromptEnabled       bool                 // Prompt before renaming each file
 	UseEmbeddedMetadata bool                 // Force embedded metadata, ignore metadata.json
+	StrictJSON          bool                 // Parse metadata.json as standard JSON only (see UnmarshalMetadataJSON)
 	AllowedCurrentPaths []string             // When non-empty, only process these current file paths
 	MetadataResolver    FileMetadataResolver // Optional per-file metadata source, such as ABS
+	Locale              string               // Locale {author_initial} files names under; "" uses the root collation order
+	Extensions          ExtensionPolicy      // Which files are renamed and which follow them; nil is the built-in handling
 }
 
 // FileMetadataResolver provides metadata for a file being renamed.
@@ -80,6 +87,10 @@ func (c *RenamerConfig) Validate() error {
 		}
 	}
 
+	if !planning.ValidSubtitleStyle(c.Subtitle) {
+		return fmt.Errorf("invalid subtitle style: %s\n\nValid options are:\n  keep (default)\n  colon\n  dash\n  drop", c.Subtitle)
+	}
+
 	// Validate author format
 	switch c.AuthorFormat {
 	case AuthorFormatFirstLast, AuthorFormatLastFirst, AuthorFormatPreserve:
@@ -91,6 +102,10 @@ func (c *RenamerConfig) Validate() error {
 		)
 	}
 
+	if _, err := NewCollation(c.Locale); err != nil {
+		return err
+	}
+
 	// Validate replace_space character (should be single char or empty)
 	if len(c.ReplaceSpace) > 1 {
 		return fmt.Errorf(
@@ -123,9 +138,10 @@ type RenameCandidate struct {
 	CurrentPath  string
 	ProposedPath string
 	Metadata     Metadata
-	IsNoOp       bool   // File already has target name
-	IsConflict   bool   // Duplicate target name
-	Error        string // If preview generation failed
+	IsNoOp       bool       // File already has target name
+	IsConflict   bool       // Duplicate target name
+	Error        string     // If preview generation failed
+	Companions   []FilePair // Sidecars renamed with the file, as full paths
 }
 
 // RenameSummary tracks rename operation results
@@ -182,6 +198,7 @@ func NewRenamer(config *RenamerConfig) (*Renamer, error) {
 	// Create template renderer
 	authorFormatter := NewAuthorFormatter(config.AuthorFormat)
 	renderer := NewTemplateRenderer(template, authorFormatter)
+	renderer.SetInitial(collationFor(config.Locale).Initial)
 
 	return &Renamer{
 		config:           *config,
@@ -216,6 +233,12 @@ func (r *Renamer) Execute() error {
 			continue
 		}
 		r.summary.FilesRenamed++
+
+		for _, companion := range candidate.Companions {
+			if err := r.RenameFile(companion.From, companion.To); err != nil {
+				r.summary.Errors = append(r.summary.Errors, err.Error())
+			}
+		}
 	}
 
 	// 4. Save log
@@ -251,7 +274,7 @@ func (r *Renamer) ScanFiles() ([]RenameCandidate, error) {
 
 		// Check if supported file type
 		ext := strings.ToLower(filepath.Ext(path))
-		if !IsSupportedFile(ext) {
+		if !r.config.Extensions.IsOrganized(ext) {
 			return nil
 		}
 		if len(allowedPaths) > 0 {
@@ -271,7 +294,7 @@ func (r *Renamer) ScanFiles() ([]RenameCandidate, error) {
 			metadata, err = r.config.MetadataResolver.MetadataForPath(path)
 		} else {
 			// NewMetadataProvider auto-detects and does hybrid extraction.
-			provider := NewMetadataProvider(path, r.config.UseEmbeddedMetadata)
+			provider := newMetadataProvider(path, r.config.UseEmbeddedMetadata, r.config.Extensions, r.config.StrictJSON)
 			metadata, err = provider.GetMetadata()
 		}
 		if err != nil {
@@ -315,9 +338,68 @@ func (r *Renamer) ScanFiles() ([]RenameCandidate, error) {
 	}
 
 	r.finalizePreviewSummary(candidates)
+	if err := r.attachCompanions(candidates); err != nil {
+		return candidates, err
+	}
 	return candidates, nil
 }
 
+// attachCompanions finds the sidecars (.cue, .jpg, .nfo, .srt, ...) sharing each
+// renamed file's basename so they are renamed with it. A sidecar whose new name is
+// already taken is left alone rather than overwritten.
+func (r *Renamer) attachCompanions(candidates []RenameCandidate) error {
+	dirCompanions := make(map[string]map[string]string)
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.Error != "" || candidate.IsNoOp {
+			continue
+		}
+
+		dir := filepath.Dir(candidate.CurrentPath)
+		companions, ok := dirCompanions[dir]
+		if !ok {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", dir, err)
+			}
+			var names []string
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					names = append(names, entry.Name())
+				}
+			}
+			companions = r.config.Extensions.MatchCompanions(names)
+			dirCompanions[dir] = companions
+		}
+
+		audioName := filepath.Base(candidate.CurrentPath)
+		for name, owner := range companions {
+			if owner != audioName {
+				continue
+			}
+			suffix, _ := companionSuffix(audioName, name)
+			if r.config.ReplaceSpace != "" {
+				suffix = strings.ReplaceAll(suffix, " ", r.config.ReplaceSpace)
+			}
+			target := filepath.Join(
+				filepath.Dir(candidate.ProposedPath),
+				CompanionTargetName(filepath.Base(candidate.ProposedPath), suffix),
+			)
+			if _, err := os.Stat(target); err == nil {
+				continue
+			}
+			candidate.Companions = append(candidate.Companions, FilePair{
+				From: filepath.Join(dir, name),
+				To:   target,
+			})
+		}
+		sort.Slice(candidate.Companions, func(a, b int) bool {
+			return candidate.Companions[a].From < candidate.Companions[b].From
+		})
+	}
+	return nil
+}
+
 func (r *Renamer) allowedCurrentPaths() (map[string]struct{}, error) {
 	if len(r.config.AllowedCurrentPaths) == 0 {
 		return nil, nil
@@ -386,21 +468,17 @@ func (r *Renamer) finalizePreviewSummary(candidates []RenameCandidate) {
 
 // GenerateNewPath generates the new path for a file based on metadata
 func (r *Renamer) GenerateNewPath(currentPath string, metadata Metadata) (string, error) {
-	// Render template to get new filename (without extension)
-	newFilename, err := r.templateRenderer.Render(metadata)
+	metadata.Title = ApplySubtitleStyle(metadata, r.config.Subtitle)
+	newFilename, err := planning.RenderFilename(
+		r.templateRenderer,
+		metadata,
+		filepath.Ext(currentPath),
+		r.config.ReplaceSpace,
+	)
 	if err != nil {
 		return "", err
 	}
 
-	// Sanitize filename
-	newFilename = r.sanitizeFilename(newFilename)
-
-	// Preserve extension
-	ext := filepath.Ext(currentPath)
-	if !strings.HasSuffix(newFilename, ext) {
-		newFilename += ext
-	}
-
 	// Construct new path
 	var newPath string
 	if r.config.PreservePath {
@@ -415,23 +493,6 @@ func (r *Renamer) GenerateNewPath(currentPath string, metadata Metadata) (string
 	return newPath, nil
 }
 
-// sanitizeFilename sanitizes a filename
-func (r *Renamer) sanitizeFilename(filename string) string {
-	// Replace spaces if configured
-	if r.config.ReplaceSpace != "" {
-		filename = strings.ReplaceAll(filename, " ", r.config.ReplaceSpace)
-	}
-
-	// Use SanitizePath logic (create temporary organizer for this)
-	// For now, just do basic sanitization
-	invalidChars := []string{"<", ">", ":", "\"", "/", "\\", "|", "?", "*"}
-	for _, char := range invalidChars {
-		filename = strings.ReplaceAll(filename, char, "_")
-	}
-
-	return filename
-}
-
 // RenameFile renames a single file
 func (r *Renamer) RenameFile(oldPath, newPath string) error {
 	if r.config.Verbose {
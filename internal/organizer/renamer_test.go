@@ -101,6 +101,58 @@ func TestRenamerScanFilesUsesConfiguredMetadataResolver(t *testing.T) {
 	}
 }
 
+func TestRenamerRenamesCompanionFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := []string{"source.mp3", "source.cue", "source.en.srt", "source.mp3.jpg", "cover.jpg", "07 - ABS Title.nfo", "source.nfo"}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	renamer, err := NewRenamer(&RenamerConfig{
+		BaseDir:      tmpDir,
+		Template:     "{track} - {title}",
+		AuthorFormat: AuthorFormatFirstLast,
+		MetadataResolver: renameTestMetadataResolver{metadata: Metadata{
+			Title:       "ABS Title",
+			TrackNumber: 7,
+			RawData:     map[string]interface{}{},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewRenamer() error = %v", err)
+	}
+	if err := renamer.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for _, name := range []string{
+		"07 - ABS Title.mp3",
+		"07 - ABS Title.cue",
+		"07 - ABS Title.en.srt",
+		"07 - ABS Title.mp3.jpg",
+		"cover.jpg",
+		"source.nfo", // Its new name is already taken, so it stays put
+	} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("expected %s after rename: %v", name, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "07 - ABS Title.nfo")); string(data) != "07 - ABS Title.nfo" {
+		t.Errorf("existing file was overwritten by a sidecar, content = %q", data)
+	}
+
+	if err := renamer.UndoRenames(); err != nil {
+		t.Fatalf("UndoRenames() error = %v", err)
+	}
+	for _, name := range files {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("expected %s after undo: %v", name, err)
+		}
+	}
+}
+
 func TestRenamer_ScanFiles(t *testing.T) {
 	// Note: This test uses dummy audio files which can't be parsed.
 	// The test verifies that the renamer properly handles errors and returns
@@ -303,6 +355,20 @@ func TestRenamer_GenerateNewFilename(t *testing.T) {
 			}
 		})
 	}
+
+	config.Subtitle = SubtitleDash
+	renamer, err = NewRenamer(config)
+	if err != nil {
+		t.Fatalf("NewRenamer() error: %v", err)
+	}
+	metadata := Metadata{Title: "Dune", Subtitle: "Deluxe Edition", Authors: []string{"Frank Herbert"}}
+	newPath, err := renamer.GenerateNewPath(filepath.Join(tmpDir, "dune.m4b"), metadata)
+	if err != nil {
+		t.Fatalf("GenerateNewPath() error: %v", err)
+	}
+	if got, want := filepath.Base(newPath), "Frank Herbert - Dune - Deluxe Edition.m4b"; got != want {
+		t.Errorf("GenerateNewPath() with --subtitle=dash = %q, want %q", got, want)
+	}
 }
 
 func createDummyRenameBook(t *testing.T, root, dirName, audioName string) string {
@@ -0,0 +1,125 @@
+package organizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RunStatus describes the overall outcome of an organize run.
+type RunStatus string
+
+const (
+	RunStatusOK                  RunStatus = "ok"
+	RunStatusFatal               RunStatus = "fatal"
+	RunStatusCompletedWithErrors RunStatus = "completed_with_errors"
+	RunStatusNothingToDo         RunStatus = "nothing_to_do"
+)
+
+// Status derives the run outcome from the summary. Runs with recorded errors
+// completed with errors; runs without planned or executed moves had nothing to do.
+func (s Summary) Status() RunStatus {
+	if len(s.Errors) > 0 {
+		return RunStatusCompletedWithErrors
+	}
+	if len(s.Moves) == 0 {
+		return RunStatusNothingToDo
+	}
+	return RunStatusOK
+}
+
+// RunReport is the machine-readable result of an organize run.
+type RunReport struct {
+	Status             RunStatus               `json:"status"`
+	DryRun             bool                    `json:"dry_run"`
+	Error              string                  `json:"error,omitempty"`
+	MetadataFound      int                     `json:"metadata_found"`
+	MetadataSources    SourceCounts            `json:"metadata_sources"`
+	MetadataMissing    []string                `json:"metadata_missing"`
+	Moves              []MoveSummary           `json:"moves"`
+	EmptyDirsRemoved   []string                `json:"empty_dirs_removed"`
+	Errors             []string                `json:"errors"`
+	Trashed            []string                `json:"trashed,omitempty"`
+	PlanDiff           *MovePlanDiff           `json:"plan_diff,omitempty"`
+	AuthorVariants     []AuthorMergeSuggestion `json:"author_variants,omitempty"`
+	AuthorCorrections  []AuthorCorrection      `json:"author_corrections,omitempty"`
+	SkipListed         []string                `json:"skip_listed,omitempty"`
+	Protected          []ProtectedDir          `json:"protected,omitempty"`
+	Deferred           []Deferral              `json:"deferred,omitempty"`
+	LowConfidence      []LowConfidence         `json:"low_confidence,omitempty"`
+	Seeding            []string                `json:"seeding,omitempty"`
+	HiddenFiles        []string                `json:"hidden_files,omitempty"`
+	Identifiers        []BookIdentifiers       `json:"identifiers,omitempty"`
+	TrackMaps          []TrackMap              `json:"track_maps,omitempty"` // Books whose files were renamed
+	PlanWarnings       []PlanWarning           `json:"plan_warnings,omitempty"`
+	FailedAfterRetries []string                `json:"failed_after_retries,omitempty"`
+	Profile            *RunProfile             `json:"profile,omitempty"`
+	CoversFetched      []FetchedCover          `json:"covers_fetched,omitempty"`
+	LeftByLimit        []string                `json:"left_by_limit,omitempty"`
+	Quarantined        []Quarantine            `json:"quarantined,omitempty"`
+	ReleasesMatched    []ReleaseMatch          `json:"releases_matched,omitempty"`
+	Unsupported        []string                `json:"unsupported,omitempty"`
+	Carried            []MoveSummary           `json:"carried,omitempty"`
+	Merges             []BookMerge             `json:"merges,omitempty"`
+}
+
+// NewRunReport builds a run report from a summary. A non-nil fatalErr marks the run fatal.
+func NewRunReport(summary Summary, dryRun bool, fatalErr error) RunReport {
+	report := RunReport{
+		Status:             summary.Status(),
+		DryRun:             dryRun,
+		MetadataFound:      len(summary.MetadataFound),
+		MetadataSources:    summary.Sources,
+		MetadataMissing:    nonNilMetadataStrings(summary.MetadataMissing),
+		Moves:              summary.Moves,
+		EmptyDirsRemoved:   nonNilMetadataStrings(summary.EmptyDirsRemoved),
+		Errors:             nonNilMetadataStrings(summary.Errors),
+		Trashed:            summary.Trashed,
+		AuthorVariants:     summary.AuthorVariants,
+		AuthorCorrections:  summary.AuthorCorrections,
+		SkipListed:         summary.SkipListed,
+		Protected:          summary.Protected,
+		Deferred:           summary.Deferred,
+		LowConfidence:      summary.LowConfidence,
+		Seeding:            summary.Seeding,
+		HiddenFiles:        summary.HiddenFiles,
+		Identifiers:        summary.Identifiers,
+		TrackMaps:          BuildTrackMaps(summary.FileMoves),
+		PlanWarnings:       summary.PlanWarnings,
+		FailedAfterRetries: summary.FailedAfterRetries,
+		Profile:            summary.Profile,
+		CoversFetched:      summary.CoversFetched,
+		LeftByLimit:        summary.LeftByLimit,
+		Quarantined:        summary.Quarantined,
+		ReleasesMatched:    summary.ReleasesMatched,
+		Unsupported:        summary.Unsupported,
+		Carried:            summary.Carried,
+		Merges:             summary.Merges,
+	}
+	if report.Moves == nil {
+		report.Moves = []MoveSummary{}
+	}
+	if fatalErr != nil {
+		report.Status = RunStatusFatal
+		report.Error = fatalErr.Error()
+	}
+	return report
+}
+
+// WriteRunReport writes a run report as indented JSON to path, or to stdout when path is "-".
+func WriteRunReport(path string, report RunReport) error {
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("error creating report file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,117 @@
+package organizer
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed report.html.tmpl
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// HTMLReportContext is what the HTML report shows beyond the RunReport: where the run
+// happened and how to undo it
+type HTMLReportContext struct {
+	InputDir     string
+	OutputDir    string
+	UndoCommands []string  // Commands that restore the run; empty for dry runs and failed runs
+	Generated    time.Time // Defaults to now
+	Locale       string    // Locale the move tree is sorted in; "" uses the root collation order
+}
+
+// moveNode is one directory of the move tree. Directories that books were moved
+// into list where each came from.
+type moveNode struct {
+	Name     string
+	Children []*moveNode
+	Sources  []string
+	Books    int // Moves into this directory and the ones below it
+}
+
+// WriteHTMLReport writes a self-contained HTML page describing the run to path
+func WriteHTMLReport(path string, report RunReport, context HTMLReportContext) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating HTML report: %w", err)
+	}
+	if err := RenderHTMLReport(file, report, context); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// RenderHTMLReport writes the HTML report of a run to w
+func RenderHTMLReport(w io.Writer, report RunReport, context HTMLReportContext) error {
+	if context.Generated.IsZero() {
+		context.Generated = time.Now()
+	}
+	root := context.OutputDir
+	if root == "" {
+		root = context.InputDir
+	}
+
+	return htmlReport.Execute(w, struct {
+		RunReport
+		HTMLReportContext
+		Root     string
+		MoveTree []*moveNode
+	}{
+		RunReport:         report,
+		HTMLReportContext: context,
+		Root:              root,
+		MoveTree:          buildMoveTree(report.Moves, root, collationFor(context.Locale)),
+	})
+}
+
+// buildMoveTree nests the move targets below root by directory, in collation order
+func buildMoveTree(moves []MoveSummary, root string, collation *Collation) []*moveNode {
+	top := &moveNode{}
+	for _, move := range moves {
+		target := move.To
+		if rel, err := filepath.Rel(root, move.To); err == nil && root != "" && !strings.HasPrefix(rel, "..") {
+			target = rel
+		}
+
+		node := top
+		node.Books++
+		for _, part := range strings.Split(filepath.ToSlash(target), "/") {
+			if part == "" {
+				continue
+			}
+			node = node.child(part)
+			node.Books++
+		}
+		node.Sources = append(node.Sources, move.From)
+	}
+	top.sort(collation)
+	return top.Children
+}
+
+func (n *moveNode) child(name string) *moveNode {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	child := &moveNode{Name: name}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+func (n *moveNode) sort(collation *Collation) {
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		return collation.Compare(n.Children[i].Name, n.Children[j].Name) < 0
+	})
+	for _, child := range n.Children {
+		child.sort(collation)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMoveTree(t *testing.T) {
+	root := filepath.Join("/library")
+	tree := buildMoveTree([]MoveSummary{
+		{From: "/in/b", To: filepath.Join(root, "Zelazny", "Lord of Light")},
+		{From: "/in/a1", To: filepath.Join(root, "Asimov", "Foundation", "Foundation")},
+		{From: "/in/a2", To: filepath.Join(root, "Asimov", "Foundation", "Foundation and Empire")},
+	}, root, nil)
+
+	require.Len(t, tree, 2)
+	assert.Equal(t, "Asimov", tree[0].Name)
+	assert.Equal(t, 2, tree[0].Books)
+	assert.Equal(t, "Zelazny", tree[1].Name)
+
+	series := tree[0].Children[0]
+	assert.Equal(t, "Foundation", series.Name)
+	require.Len(t, series.Children, 2)
+	assert.Equal(t, []string{"/in/a2"}, series.Children[1].Sources)
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	summary := Summary{
+		MetadataFound: []string{"a", "b"},
+		Moves: []MoveSummary{
+			{From: "/in/Foundation", To: filepath.Join("/library", "Asimov", "Foundation")},
+		},
+		Errors:        []string{"❌ Error moving <script>: permission denied"},
+		LowConfidence: []LowConfidence{{Path: "/in/Track 1", Score: 0.3, Reasons: []string{"placeholder title"}}},
+	}
+	report := NewRunReport(summary, false, nil)
+	path := filepath.Join(t.TempDir(), "report.html")
+
+	err := WriteHTMLReport(path, report, HTMLReportContext{
+		InputDir:     "/in",
+		OutputDir:    "/library",
+		UndoCommands: []string{"audiobook-organizer --input=/in --undo"},
+		Generated:    time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	html := string(data)
+
+	assert.Contains(t, html, "Status: completed_with_errors")
+	assert.Contains(t, html, "2026-01-02 03:04 UTC")
+	assert.Contains(t, html, "<details><summary>Asimov")
+	assert.Contains(t, html, "&larr; /in/Foundation")
+	assert.Contains(t, html, "Held back for low metadata confidence (1)")
+	assert.Contains(t, html, "audiobook-organizer --input=/in --undo")
+	assert.Contains(t, html, "&lt;script&gt;", "metadata must be escaped")
+	assert.False(t, strings.Contains(html, "<script>"), "report must not contain scripts")
+}
@@ -0,0 +1,73 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary Summary
+		want    RunStatus
+	}{
+		{"no moves", Summary{}, RunStatusNothingToDo},
+		{"moves", Summary{Moves: []MoveSummary{{From: "a", To: "b"}}}, RunStatusOK},
+		{
+			"moves with errors",
+			Summary{Moves: []MoveSummary{{From: "a", To: "b"}}, Errors: []string{"boom"}},
+			RunStatusCompletedWithErrors,
+		},
+		{"errors only", Summary{Errors: []string{"boom"}}, RunStatusCompletedWithErrors},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.summary.Status())
+		})
+	}
+}
+
+func TestNewRunReport(t *testing.T) {
+	report := NewRunReport(Summary{MetadataFound: []string{"a", "b"}}, true, nil)
+	assert.Equal(t, RunStatusNothingToDo, report.Status)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.MetadataFound)
+	assert.NotNil(t, report.Moves)
+	assert.NotNil(t, report.Errors)
+
+	fatal := NewRunReport(Summary{Moves: []MoveSummary{{From: "a", To: "b"}}}, false, errors.New("no input"))
+	assert.Equal(t, RunStatusFatal, fatal.Status)
+	assert.Equal(t, "no input", fatal.Error)
+}
+
+func TestWriteRunReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	report := NewRunReport(Summary{
+		Moves:  []MoveSummary{{From: "in/book", To: "out/Author/Book"}},
+		Errors: []string{"Error moving book: denied"},
+	}, false, nil)
+	require.NoError(t, WriteRunReport(path, report))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "completed_with_errors", decoded["status"])
+	assert.Len(t, decoded["moves"], 1)
+	assert.Equal(t, []interface{}{"Error moving book: denied"}, decoded["errors"])
+}
+
+func TestStripDecorations(t *testing.T) {
+	assert.Equal(t, "Error: failed", StripDecorations("❌ Error: failed"))
+	assert.Equal(t, "Moving\nDone", StripDecorations("📦 Moving\n✅ Done"))
+	assert.Equal(t, "Plain text", StripDecorations("Plain text"))
+}
@@ -0,0 +1,99 @@
+package organizer
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// Defaults for retrying file operations that fail with transient errors
+const (
+	DefaultRetries    = 3
+	DefaultRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
+)
+
+// transientErrnos are the errors network file systems such as NFS and SMB return
+// for a moment and not again, so the operation is worth repeating
+var transientErrnos = []syscall.Errno{
+	syscall.EIO,
+	syscall.ESTALE,
+	syscall.EAGAIN,
+	syscall.EINTR,
+	syscall.EBUSY,
+	syscall.ETIMEDOUT,
+	syscall.ECONNRESET,
+	syscall.ECONNABORTED,
+}
+
+// retrySleep waits between attempts; tests replace it to run without waiting
+var retrySleep = time.Sleep
+
+// RetriesExhaustedError is a transient error that persisted through every retry
+type RetriesExhaustedError struct {
+	Path     string
+	Attempts int
+	Err      error
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("%s still failing after %d attempts: %v", e.Path, e.Attempts, e.Err)
+}
+
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransientError reports whether err is one a network file system may not return
+// again, such as EIO, ESTALE, or a timeout. Missing files, permissions, and full
+// disks are permanent.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, errno := range transientErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// retry runs op on path, repeating it up to Retries more times while it fails with
+// a transient error. The wait starts at RetryDelay and doubles after each attempt.
+// A transient error that outlasts every retry is returned as a RetriesExhaustedError.
+func (o *Organizer) retry(path string, op func() error) error {
+	delay := o.config.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	attempts := o.config.Retries + 1
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !IsTransientError(err) {
+			return err
+		}
+		if attempt == attempts {
+			if attempts == 1 {
+				return err
+			}
+			return &RetriesExhaustedError{Path: path, Attempts: attempts, Err: err}
+		}
+		PrintYellow("⚠️  %s: %v; retrying in %s (attempt %d of %d)", path, err, delay, attempt+1, attempts)
+		retrySleep(delay)
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// recordRetriesExhausted adds a book to the summary's failed-after-retries list when
+// err is a transient error that outlasted every retry
+func (o *Organizer) recordRetriesExhausted(path string, err error) bool {
+	var exhausted *RetriesExhaustedError
+	if !errors.As(err, &exhausted) {
+		return false
+	}
+	o.summary.FailedAfterRetries = append(o.summary.FailedAfterRetries, path)
+	return true
+}
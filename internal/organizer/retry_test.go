@@ -0,0 +1,95 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutRetryWaits records the waits between retries instead of sleeping
+func withoutRetryWaits(t *testing.T) *[]time.Duration {
+	var waits []time.Duration
+	retrySleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { retrySleep = time.Sleep })
+	return &waits
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(&os.PathError{Op: "rename", Path: "/nfs/a", Err: syscall.ESTALE}))
+	assert.True(t, IsTransientError(&os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.EIO}))
+	assert.True(t, IsTransientError(os.ErrDeadlineExceeded))
+	assert.False(t, IsTransientError(&os.PathError{Op: "open", Path: "/nfs/a", Err: syscall.ENOENT}))
+	assert.False(t, IsTransientError(fs.ErrPermission))
+	assert.False(t, IsTransientError(nil))
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	waits := withoutRetryWaits(t)
+	o := &Organizer{config: OrganizerConfig{Retries: 3, RetryDelay: 100 * time.Millisecond}}
+
+	calls := 0
+	err := o.retry("/nfs/book/part1.mp3", func() error {
+		if calls++; calls < 3 {
+			return &os.PathError{Op: "write", Path: "/nfs/book/part1.mp3", Err: syscall.EIO}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *waits)
+
+	calls = 0
+	err = o.retry("/nfs/book/part1.mp3", func() error {
+		calls++
+		return syscall.ESTALE
+	})
+	var exhausted *RetriesExhaustedError
+	require.ErrorAs(t, err, &exhausted)
+	assert.Equal(t, 4, exhausted.Attempts)
+	assert.Equal(t, 4, calls)
+	assert.ErrorIs(t, err, syscall.ESTALE)
+
+	// Permanent errors are returned at once
+	calls = 0
+	err = o.retry("/nfs/book/part1.mp3", func() error {
+		calls++
+		return fs.ErrNotExist
+	})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryDisabled(t *testing.T) {
+	withoutRetryWaits(t)
+	o := &Organizer{}
+
+	calls := 0
+	err := o.retry("/nfs/a", func() error {
+		calls++
+		return syscall.EIO
+	})
+	assert.ErrorIs(t, err, syscall.EIO)
+	assert.False(t, errors.As(err, new(*RetriesExhaustedError)))
+	assert.Equal(t, 1, calls)
+}
+
+func TestFailedAfterRetriesContinuesFlatRun(t *testing.T) {
+	o := &Organizer{config: OrganizerConfig{Flat: true}}
+	exhausted := &RetriesExhaustedError{Path: "/nfs/a.mp3", Attempts: 4, Err: syscall.EIO}
+
+	CaptureOutput(func() {
+		assert.NoError(t, o.handleBookError("/in/a.mp3", exhausted))
+		assert.Error(t, o.handleBookError("/in/b.mp3", errors.New("bad tags")), "other errors still stop a flat run")
+	})
+	assert.Equal(t, []string{"/in/a.mp3"}, o.summary.FailedAfterRetries)
+	assert.Len(t, o.summary.Errors, 1)
+	assert.Equal(t, []string{"/in/a.mp3"}, NewRunReport(o.summary, false, nil).FailedAfterRetries)
+}
@@ -0,0 +1,252 @@
+package organizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScanIndexFileName is the name of the incremental scan index. An Organizer keeps it
+// next to its undo log, with a hash of the input directory added (see scanIndexPath).
+const ScanIndexFileName = ".abook-org-index.json"
+
+const scanIndexVersion = 1
+
+// ScanIndex remembers the modification time and size of every directory a scan
+// visited, plus the subdirectories it had and the metadata files of the books found in
+// it. A later scan skips reading a directory whose stamps are unchanged and descends
+// only into its recorded subdirectories, so a run where one new book arrived touches
+// little more than one stat per directory.
+//
+// Directory times change when entries are added, removed, or renamed. Metadata files
+// are stamped separately so edited tags or metadata.json are picked up too.
+type ScanIndex struct {
+	path        string
+	fingerprint string
+	previous    map[string]indexedDir
+	current     map[string]indexedDir
+	invalid     map[string]bool
+	skipped     int
+}
+
+// indexedDir is the recorded state of one directory
+type indexedDir struct {
+	ModTime int64                `json:"mtime"`
+	Size    int64                `json:"size"`
+	Subdirs []string             `json:"subdirs,omitempty"`
+	Files   map[string]fileStamp `json:"files,omitempty"` // Metadata sources of books found here
+}
+
+// fileStamp is the recorded state of one metadata file
+type fileStamp struct {
+	ModTime int64 `json:"mtime"`
+	Size    int64 `json:"size"`
+}
+
+type scanIndexFile struct {
+	Version     int                   `json:"version"`
+	Fingerprint string                `json:"fingerprint"`
+	Dirs        map[string]indexedDir `json:"dirs"`
+}
+
+// LoadScanIndex reads the index at path. The previous state is ignored when it is
+// missing, unreadable, or was written for a different fingerprint, which makes the
+// next scan a full one.
+func LoadScanIndex(path, fingerprint string) *ScanIndex {
+	index := &ScanIndex{
+		path:        path,
+		fingerprint: fingerprint,
+		previous:    map[string]indexedDir{},
+		current:     map[string]indexedDir{},
+		invalid:     map[string]bool{},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return index
+	}
+	var file scanIndexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return index
+	}
+	if file.Version == scanIndexVersion && file.Fingerprint == fingerprint && file.Dirs != nil {
+		index.previous = file.Dirs
+	}
+	return index
+}
+
+// Save writes the state recorded by the last scan
+func (x *ScanIndex) Save() error {
+	data, err := json.Marshal(scanIndexFile{
+		Version:     scanIndexVersion,
+		Fingerprint: x.fingerprint,
+		Dirs:        x.current,
+	})
+	if err != nil {
+		return err
+	}
+	// Written in place so an existing index doesn't change the directory's own time
+	return os.WriteFile(x.path, data, 0o644)
+}
+
+// Skipped returns how many unchanged directories the last scan did not read
+func (x *ScanIndex) Skipped() int {
+	if x == nil {
+		return 0
+	}
+	return x.skipped
+}
+
+// Invalidate forgets path and its parent directory so the next scan reads them again.
+// Callers use it for books that failed or were not organized.
+func (x *ScanIndex) Invalidate(path string) {
+	if x == nil {
+		return
+	}
+	for _, p := range []string{path, filepath.Dir(path)} {
+		x.invalid[p] = true
+		delete(x.current, p)
+	}
+}
+
+// unchanged reports whether dir matches its recorded state and returns its recorded
+// subdirectories. An unchanged directory is carried over into the new index.
+func (x *ScanIndex) unchanged(dir string, info os.FileInfo) ([]string, bool) {
+	entry, ok := x.previous[dir]
+	if !ok || entry.ModTime != info.ModTime().UnixNano() || entry.Size != info.Size() {
+		return nil, false
+	}
+	for name, stamp := range entry.Files {
+		fileInfo, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || stampOf(fileInfo) != stamp {
+			return nil, false
+		}
+	}
+
+	x.current[dir] = entry
+	x.skipped++
+	return entry.Subdirs, true
+}
+
+// record notes the state of a directory before it is read
+func (x *ScanIndex) record(dir string, info os.FileInfo) {
+	if x.invalid[dir] {
+		return
+	}
+	x.current[dir] = indexedDir{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+}
+
+// setSubdirs completes a recorded directory once its entries have been read
+func (x *ScanIndex) setSubdirs(dir string, subdirs []string) {
+	if entry, ok := x.current[dir]; ok {
+		entry.Subdirs = subdirs
+		x.current[dir] = entry
+	}
+}
+
+// recordBook stamps the metadata file a book in dir was read from
+func (x *ScanIndex) recordBook(dir, metadataPath string) {
+	entry, ok := x.current[dir]
+	if !ok {
+		return
+	}
+	name, err := filepath.Rel(dir, metadataPath)
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(metadataPath)
+	if err != nil {
+		return
+	}
+	if entry.Files == nil {
+		entry.Files = map[string]fileStamp{}
+	}
+	entry.Files[name] = stampOf(info)
+	x.current[dir] = entry
+}
+
+func stampOf(info os.FileInfo) fileStamp {
+	return fileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+}
+
+// scanIndexPath returns where the scan index of root is kept: next to the undo log
+// rather than in root, which may be a seeding torrent folder, and named after root so
+// several input directories organized into one output each keep their own index
+func (o *Organizer) scanIndexPath(root string) string {
+	sum := sha256.Sum256([]byte(root))
+	name := strings.TrimSuffix(ScanIndexFileName, ".json") + "-" + hex.EncodeToString(sum[:6]) + ".json"
+	return filepath.Join(filepath.Dir(o.GetLogPath()), name)
+}
+
+// scanIndexFingerprint identifies the settings that decide which books a scan finds
+// and where they go. An index written under different settings is not reused.
+func (o *Organizer) scanIndexFingerprint(root string) string {
+	data, _ := json.Marshal(struct {
+		Root                string
+		OutputDir           string
+		Flat                bool
+		UseEmbeddedMetadata bool
+		FieldMapping        FieldMapping
+		Layout              string
+		LayoutTemplate      string
+		AuthorFormat        string
+		ReplaceSpace        string
+		Casing              string
+		StripTitlePrefix    bool
+		AuthorAliases       []AuthorAlias
+		TrackTitles         bool
+		MergeDiscs          bool
+		MinConfidence       float64
+		HiddenFiles         HiddenFilePolicy
+		Extensions          ExtensionPolicy
+		AllowProtectedDirs  bool
+		Locale              string
+		SeedSafe            bool
+		TorrentDirs         []string
+		WriteIdentifiers    bool
+		Strict              bool
+		MinDepth            int    `json:",omitempty"` // Left out when unset, so existing indexes stay valid
+		MaxDepth            int    `json:",omitempty"`
+		Subtitle            string `json:",omitempty"`
+	}{
+		root,
+		o.config.OutputDir,
+		o.config.Flat,
+		o.config.UseEmbeddedMetadata,
+		o.config.FieldMapping,
+		o.config.Layout,
+		o.config.LayoutTemplate,
+		o.config.AuthorFormat,
+		o.config.ReplaceSpace,
+		o.config.Casing,
+		o.config.StripTitlePrefix,
+		o.config.AuthorAliases,
+		o.config.TrackTitles,
+		o.config.MergeDiscs,
+		o.config.MinConfidence,
+		o.config.HiddenFiles,
+		o.config.Extensions,
+		o.config.AllowProtectedDirs,
+		o.config.Locale,
+		o.config.SeedSafe,
+		o.config.TorrentDirs,
+		o.config.WriteIdentifiers,
+		o.config.Strict,
+		o.config.MinDepth,
+		o.config.MaxDepth,
+		o.config.Subtitle,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// useScanIndex reports whether this run can scan incrementally. Selective, filtered,
+// and interactive runs always scan everything so they never mark unvisited books as
+// seen, and the author check and lookup need to see every book to report on it.
+func (o *Organizer) useScanIndex() bool {
+	return !o.config.FullScan && !o.config.Prompt && !o.config.CheckAuthors && !o.config.AuthorLookup &&
+		len(o.config.AllowedSourcePaths) == 0 && o.config.Filter.IsEmpty()
+}
@@ -0,0 +1,154 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// indexedScan runs one scan with the index at indexPath and saves it, returning the
+// paths of the books found
+func indexedScan(t *testing.T, root, indexPath string) ([]string, *ScanIndex) {
+	t.Helper()
+	index := LoadScanIndex(indexPath, "test")
+	result, err := NewScanner(ScanOptions{Index: index}).Scan(root)
+	require.NoError(t, err)
+	require.NoError(t, index.Save())
+
+	var paths []string
+	for _, book := range result.Books {
+		paths = append(paths, book.Path)
+	}
+	return paths, index
+}
+
+func TestScannerIndexSkipsUnchangedDirectories(t *testing.T) {
+	root := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), ScanIndexFileName)
+	bookA := createBookDir(t, root, "BookA", "Book A", "Author A")
+	bookB := createBookDir(t, filepath.Join(root, "nested"), "BookB", "Book B", "Author B")
+
+	books, index := indexedScan(t, root, indexPath)
+	assert.Equal(t, []string{bookA, bookB}, books)
+	assert.Zero(t, index.Skipped())
+
+	books, index = indexedScan(t, root, indexPath)
+	assert.Empty(t, books)
+	assert.Positive(t, index.Skipped())
+
+	// A new book changes only its parent directory
+	bookC := createBookDir(t, filepath.Join(root, "nested"), "BookC", "Book C", "Author C")
+	books, _ = indexedScan(t, root, indexPath)
+	assert.Equal(t, []string{bookC}, books)
+
+	// Editing metadata in place leaves the directory time alone but is still seen
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.WriteFile(filepath.Join(bookA, MetadataFileName), []byte(`{"title":"Book A2","authors":["Author A"]}`), 0o644))
+	require.NoError(t, os.Chtimes(filepath.Join(bookA, MetadataFileName), later, later))
+	books, _ = indexedScan(t, root, indexPath)
+	assert.Equal(t, []string{bookA}, books)
+
+	// A different fingerprint means a full scan
+	index = LoadScanIndex(indexPath, "other settings")
+	result, err := NewScanner(ScanOptions{Index: index}).Scan(root)
+	require.NoError(t, err)
+	assert.Len(t, result.Books, 3)
+}
+
+func TestScannerIndexRetriesErrors(t *testing.T) {
+	root := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), ScanIndexFileName)
+	broken := filepath.Join(root, "Broken")
+	require.NoError(t, os.MkdirAll(broken, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(broken, MetadataFileName), []byte("{not json"), 0o644))
+
+	for run := 0; run < 2; run++ {
+		index := LoadScanIndex(indexPath, "test")
+		result, err := NewScanner(ScanOptions{Index: index}).Scan(root)
+		require.NoError(t, err)
+		require.Len(t, result.Errors, 1, "run %d", run)
+		require.NoError(t, index.Save())
+	}
+}
+
+func TestOrganizerIncrementalScan(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	createBookDir(t, baseDir, "BookA", "Book A", "Author A")
+	createBookDir(t, baseDir, "Failing", "", "")
+
+	run := func(config OrganizerConfig) Summary {
+		config.BaseDir = baseDir
+		config.OutputDir = outputDir
+		org, err := NewOrganizer(&config)
+		require.NoError(t, err)
+		require.NoError(t, org.Execute())
+		return org.GetSummary()
+	}
+
+	indexFiles := func() []string {
+		files, err := filepath.Glob(filepath.Join(outputDir, ".abook-org-index-*.json"))
+		require.NoError(t, err)
+		return files
+	}
+
+	// Dry runs read the index but never write it
+	run(OrganizerConfig{DryRun: true})
+	assert.Empty(t, indexFiles())
+
+	summary := run(OrganizerConfig{})
+	assert.Len(t, summary.Moves, 1)
+	assert.Len(t, indexFiles(), 1, "the index is kept next to the undo log")
+	assert.NoFileExists(t, filepath.Join(baseDir, ScanIndexFileName), "the input directory is never written to")
+
+	bookB := createBookDir(t, baseDir, "BookB", "Book B", "Author B")
+	summary = run(OrganizerConfig{})
+	require.Len(t, summary.Moves, 1)
+	assert.Equal(t, bookB, summary.Moves[0].From)
+	assert.NotEmpty(t, summary.Errors, "the failing book is retried")
+
+	summary = run(OrganizerConfig{FullScan: true})
+	assert.Empty(t, summary.Moves)
+	assert.Contains(t, summary.MetadataFound, filepath.Join(baseDir, "Failing", MetadataFileName))
+}
+
+func TestScanIndexFingerprintCoversScanSettings(t *testing.T) {
+	root := t.TempDir()
+	fingerprint := func(config OrganizerConfig) string {
+		config.BaseDir = root
+		org, err := NewOrganizer(&config)
+		require.NoError(t, err)
+		return org.scanIndexFingerprint(root)
+	}
+
+	base := fingerprint(OrganizerConfig{})
+	for name, config := range map[string]OrganizerConfig{
+		"author aliases": {AuthorAliases: []AuthorAlias{{Name: "Richard Bachman", Author: "Stephen King"}}},
+		"track titles":   {TrackTitles: true},
+		"merge discs":    {MergeDiscs: true},
+		"min confidence": {MinConfidence: 0.5},
+		"hidden files":   {HiddenFiles: HiddenFilesMove},
+		"extensions":     {Extensions: ExtensionPolicy{".mp4": ExtensionOrganize}},
+		"protected dirs": {AllowProtectedDirs: true},
+		"locale":         {Locale: "sv"},
+	} {
+		assert.NotEqual(t, base, fingerprint(config), name)
+	}
+}
+
+func TestScanIndexPathFollowsTheUndoLog(t *testing.T) {
+	root := t.TempDir()
+	logDir := t.TempDir()
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: root, LogPath: filepath.Join(logDir, "undo.log")})
+	require.NoError(t, err)
+
+	path := org.scanIndexPath(root)
+	assert.Equal(t, logDir, filepath.Dir(path))
+	assert.NotEqual(t, path, org.scanIndexPath(t.TempDir()), "each input directory gets its own index")
+}
@@ -0,0 +1,875 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metadata sources reported on a scanned Book
+const (
+	BookSourceJSON  = "json"
+	BookSourceEPUB  = "epub"
+	BookSourceAudio = "audio"
+	BookSourceEbook = "ebook" // MOBI, AZW3, FB2, or PDF file
+	BookSourceFile  = "file"  // Flat mode file read through the auto-detecting provider
+)
+
+// ScanOptions controls how a Scanner discovers books. The CLI, TUI, and web UI all
+// build their view of a library from these options so discovery cannot drift.
+type ScanOptions struct {
+	Flat                bool            // Treat every supported file as its own book
+	UseEmbeddedMetadata bool            // Prefer EPUB/audio tags over metadata.json
+	StrictJSON          bool            // Parse metadata.json as standard JSON only (see UnmarshalMetadataJSON)
+	OutputDir           string          // Skipped while scanning
+	AllowedSourcePaths  []string        // When non-empty, only these book paths (or their directories) are returned
+	Filter              BookFilter      // Only books matching the filter are returned
+	FieldMapping        FieldMapping    // Applied to every book's metadata
+	FallbackToFilename  bool            // Flat mode: keep unreadable files, titled by their filename
+	SkipUnreadable      bool            // Skip directories that cannot be read instead of failing the scan
+	Index               *ScanIndex      // When set, unchanged directories are skipped and the index is updated
+	MaxGroupBooks       int             // Flat mode: books held per directory for album detection (0 = DefaultMaxGroupBooks)
+	MinFileAge          time.Duration   // Books with a file modified more recently than this are deferred
+	SizeSettle          time.Duration   // Books whose size changes over this interval are deferred (0 = off)
+	MinConfidence       float64         // Embedded or file metadata scoring below this is not trusted (0 = off)
+	AllowProtected      bool            // Descend into directories managed by media servers (see ProtectedDirServer)
+	Extensions          ExtensionPolicy // Which files are books and companions; nil is the built-in handling
+	MinDepth            int             // Paths fewer levels below the root are not books; the root's entries are level 1
+	MaxDepth            int             // Paths more levels below the root are not read (0 = no limit)
+	Progress            func(ScanProgress)
+}
+
+// DefaultMaxGroupBooks bounds how many flat-mode books a directory may hold while it
+// waits to be grouped. No real album has this many tracks, so a larger directory is a
+// dump of unrelated files and is passed on in plain chunks instead.
+const DefaultMaxGroupBooks = 1000
+
+// ScanOptionsFromConfig returns the scan options an Organizer with config uses
+func ScanOptionsFromConfig(config *OrganizerConfig) ScanOptions {
+	return ScanOptions{
+		Flat:                config.Flat,
+		UseEmbeddedMetadata: config.UseEmbeddedMetadata,
+		StrictJSON:          config.StrictJSON,
+		OutputDir:           config.OutputDir,
+		AllowedSourcePaths:  config.AllowedSourcePaths,
+		Filter:              config.Filter,
+		FieldMapping:        config.FieldMapping,
+		MinFileAge:          config.MinFileAge,
+		SizeSettle:          config.SizeSettle,
+		MinConfidence:       config.MinConfidence,
+		AllowProtected:      config.AllowProtectedDirs,
+		Extensions:          config.Extensions,
+		MinDepth:            config.MinDepth,
+		MaxDepth:            config.MaxDepth,
+	}
+}
+
+// ScanProgress reports how far a scan has got
+type ScanProgress struct {
+	Path          string
+	DirsScanned   int
+	FilesScanned  int
+	BooksFound    int
+	BooksFiltered int // Books left out because they didn't match the filter
+	SkipListed    int // Paths left out because they are on the skip list
+	Protected     int // Directories left out because a media server manages them
+	Ignored       int // Directories left out because they hold an ignore marker file
+	Deferred      int // Books left for a later run because they are still being written
+	LowConfidence int // Books held back because their metadata looks unreliable
+	Unsupported   int // Flat mode: files left in place because their type isn't organized
+
+	MetadataTime time.Duration // Time spent reading metadata, including books left out
+}
+
+// Book is one organizable unit found by a Scanner: a book directory in hierarchical
+// mode, or a single file in flat mode
+type Book struct {
+	Path         string           `json:"path"`
+	Source       string           `json:"source"`
+	MetadataPath string           `json:"metadata_path"` // File the metadata was read from
+	Metadata     Metadata         `json:"metadata"`
+	Provider     MetadataProvider `json:"-"`
+
+	snapshot sourceSnapshot // Files of the book when it was read, checked again before moving
+	readTime time.Duration  // Time the scanner spent reading its metadata
+}
+
+// Group collects flat-mode books that share a directory. Album is true when the files
+// carry consistent metadata and belong together as one multi-file book.
+type Group struct {
+	Dir   string `json:"dir"`
+	Name  string `json:"name"`
+	Album bool   `json:"album"`
+	Books []Book `json:"books"`
+}
+
+// TrackNumber returns the track number of the i-th book in the group, falling back
+// to its position when the file has none
+func (g Group) TrackNumber(i int) int {
+	if n := g.Books[i].Metadata.TrackNumber; n > 0 {
+		return n
+	}
+	return i + 1
+}
+
+// ScanError records a path whose metadata could not be read
+type ScanError struct {
+	Path string `json:"path"`
+	Err  string `json:"error"`
+}
+
+// Deferral records a book left for a later run because it is still being written
+type Deferral struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ScanResult is everything a completed scan found
+type ScanResult struct {
+	Books         []Book          `json:"books"`
+	Groups        []Group         `json:"groups,omitempty"` // Flat mode only
+	Unmatched     []string        `json:"unmatched,omitempty"`
+	Skipped       []string        `json:"skipped,omitempty"` // Paths on the skip list
+	Protected     []ProtectedDir  `json:"protected,omitempty"`
+	Deferred      []Deferral      `json:"deferred,omitempty"`
+	LowConfidence []LowConfidence `json:"low_confidence,omitempty"`
+	Unsupported   []string        `json:"unsupported,omitempty"` // Flat mode only
+	Errors        []ScanError     `json:"errors,omitempty"`
+}
+
+// ScanHandler receives results while a scan is running. Returning an error from Book,
+// Group, or Error stops the scan.
+//
+// Group receives flat-mode books one directory at a time, as soon as the walk has left
+// that directory, so only the directories currently being walked are held in memory.
+// A directory with more than MaxGroupBooks files is passed on in several non-album
+// groups.
+type ScanHandler struct {
+	Book        func(Book) error
+	Group       func(Group) error
+	Unmatched   func(dir string)
+	Skipped     func(path string)        // A path left out because it is on the skip list
+	Protected   func(ProtectedDir)       // A directory left out because a media server manages it
+	Ignored     func(dir, marker string) // A directory left out because it holds an ignore marker file
+	Deferred    func(Deferral)           // A book left for a later run because it is still being written
+	Unsupported func(path string)        // Flat mode: a file left in place because its type isn't organized
+	Error       func(path string, err error) error
+
+	LowConfidence func(LowConfidence) // A book held back because its metadata scored below MinConfidence
+}
+
+// Scanner discovers books below a directory
+type Scanner struct {
+	opts     ScanOptions
+	progress ScanProgress
+	pending  []*pendingGroup // Flat-mode directories still being walked, innermost last
+	buffered int             // Books currently held in pending
+	peak     int             // Most books ever held in pending
+	skip     *SkipList       // Loaded from the root of each walk
+	deferred map[string]bool // Flat mode: directories whose files are still being written
+	root     string          // Root of the current walk, which depths are counted from
+}
+
+// pendingGroup collects the books of one directory until the walk leaves it
+type pendingGroup struct {
+	dir     string
+	books   []Book
+	chunked bool // Already passed on in part, so it can no longer be an album
+}
+
+// NewScanner creates a Scanner with the given options
+func NewScanner(opts ScanOptions) *Scanner {
+	return &Scanner{opts: opts}
+}
+
+// Scan walks root and returns every book found. Flat-mode results are also grouped by
+// directory into albums.
+func (s *Scanner) Scan(root string) (*ScanResult, error) {
+	result := &ScanResult{}
+	handler := ScanHandler{
+		Book: func(book Book) error {
+			result.Books = append(result.Books, book)
+			return nil
+		},
+		Unmatched: func(dir string) {
+			result.Unmatched = append(result.Unmatched, dir)
+		},
+		Skipped: func(path string) {
+			result.Skipped = append(result.Skipped, path)
+		},
+		Protected: func(protected ProtectedDir) {
+			result.Protected = append(result.Protected, protected)
+		},
+		Deferred: func(deferral Deferral) {
+			result.Deferred = append(result.Deferred, deferral)
+		},
+		LowConfidence: func(held LowConfidence) {
+			result.LowConfidence = append(result.LowConfidence, held)
+		},
+		Unsupported: func(path string) {
+			result.Unsupported = append(result.Unsupported, path)
+		},
+		Error: func(path string, err error) error {
+			result.Errors = append(result.Errors, ScanError{Path: path, Err: err.Error()})
+			return nil
+		},
+	}
+	if s.opts.Flat {
+		handler.Group = func(group Group) error {
+			result.Groups = append(result.Groups, group)
+			return nil
+		}
+	}
+
+	if err := s.Walk(root, handler); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Walk streams books to handler as they are found, so callers can act on each book
+// before the rest of the tree has been read
+func (s *Scanner) Walk(root string, handler ScanHandler) error {
+	s.progress = ScanProgress{}
+	s.pending, s.buffered, s.peak = nil, 0, 0
+	s.deferred = nil
+	s.root = filepath.Clean(root)
+
+	s.skip = nil
+	if info, statErr := os.Stat(root); statErr == nil && info.IsDir() {
+		skip, err := LoadSkipList(root)
+		if err != nil {
+			return err
+		}
+		s.skip = skip
+	}
+
+	var err error
+	if s.opts.Index != nil {
+		err = s.walkIndexed(root, handler)
+	} else {
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return s.handleWalkError(path, err)
+			}
+			return s.visit(path, info, handler)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return s.finishGroups("", handler)
+}
+
+// visit handles one path of the walk
+func (s *Scanner) visit(path string, info os.FileInfo, handler ScanHandler) error {
+	depth := s.depth(path)
+	if s.isOutputPath(path) || (s.opts.MaxDepth > 0 && depth > s.opts.MaxDepth) {
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}
+	if s.skip.Contains(path) {
+		return s.skipListed(path, info, handler)
+	}
+	if s.skipProtected(path, info, handler) || s.skipMarked(path, info, handler) {
+		return filepath.SkipDir
+	}
+
+	// The walk is depth first, so every pending directory that doesn't contain path
+	// has been read completely
+	if err := s.finishGroups(path, handler); err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		s.progress.DirsScanned++
+	} else {
+		s.progress.FilesScanned++
+	}
+	s.progress.Path = path
+	s.reportProgress()
+
+	if s.aboveMinDepth(depth, info.IsDir()) {
+		return nil
+	}
+	if s.opts.Flat {
+		return s.visitFlat(path, info, handler)
+	}
+	return s.visitHierarchical(path, info, handler)
+}
+
+// walkIndexed walks root like filepath.Walk but skips directories the index reports
+// as unchanged, descending only into their recorded subdirectories
+func (s *Scanner) walkIndexed(root string, handler ScanHandler) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return s.handleWalkError(root, err)
+	}
+	err = s.walkIndexedPath(root, info, handler)
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+func (s *Scanner) walkIndexedPath(path string, info os.FileInfo, handler ScanHandler) error {
+	if !info.IsDir() {
+		return s.visit(path, info, handler)
+	}
+	// Checked before the index so a listed directory is never recorded as read
+	if s.skip.Contains(path) {
+		return s.skipListed(path, info, handler)
+	}
+	if s.skipProtected(path, info, handler) || s.skipMarked(path, info, handler) {
+		return filepath.SkipDir
+	}
+
+	index := s.opts.Index
+	if subdirs, ok := index.unchanged(path, info); ok {
+		for _, name := range subdirs {
+			if err := s.walkIndexedChild(filepath.Join(path, name), handler); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	index.record(path, info)
+	if err := s.visit(path, info, handler); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		index.Invalidate(path)
+		return s.handleWalkError(path, err)
+	}
+	var subdirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			subdirs = append(subdirs, entry.Name())
+		}
+	}
+	index.setSubdirs(path, subdirs)
+
+	for _, entry := range entries {
+		err := s.walkIndexedChild(filepath.Join(path, entry.Name()), handler)
+		if err == filepath.SkipDir && !entry.IsDir() {
+			return nil // SkipDir from a file skips the rest of its directory
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkIndexedChild walks one entry of a directory, absorbing SkipDir for directories
+func (s *Scanner) walkIndexedChild(path string, handler ScanHandler) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return s.handleWalkError(path, err)
+	}
+	err = s.walkIndexedPath(path, info, handler)
+	if err == filepath.SkipDir && info.IsDir() {
+		return nil
+	}
+	return err
+}
+
+// handleWalkError skips paths that vanished during the scan (typically books that
+// were just moved) and, when configured, directories that cannot be read
+func (s *Scanner) handleWalkError(path string, err error) error {
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if s.opts.SkipUnreadable && os.IsPermission(err) {
+		return nil
+	}
+	return err
+}
+
+// skipListed leaves out a path on the skip list, with everything below it
+func (s *Scanner) skipListed(path string, info os.FileInfo, handler ScanHandler) error {
+	s.progress.SkipListed++
+	s.reportProgress()
+	if handler.Skipped != nil {
+		handler.Skipped(path)
+	}
+	if info.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// skipProtected leaves out a directory managed by a media server, with everything
+// below it, reporting whether it did
+func (s *Scanner) skipProtected(path string, info os.FileInfo, handler ScanHandler) bool {
+	if s.opts.AllowProtected || !info.IsDir() {
+		return false
+	}
+	server := ProtectedDirServer(path)
+	if server == "" {
+		return false
+	}
+	s.progress.Protected++
+	s.reportProgress()
+	if handler.Protected != nil {
+		handler.Protected(ProtectedDir{Path: path, Server: server})
+	}
+	return true
+}
+
+// skipMarked leaves out a directory holding one of the IgnoreMarkers, with everything
+// below it, reporting whether it did
+func (s *Scanner) skipMarked(path string, info os.FileInfo, handler ScanHandler) bool {
+	if !info.IsDir() {
+		return false
+	}
+	marker := IgnoreMarker(path)
+	if marker == "" {
+		return false
+	}
+	s.progress.Ignored++
+	s.reportProgress()
+	if handler.Ignored != nil {
+		handler.Ignored(path, marker)
+	}
+	return true
+}
+
+// deferIfUnstable leaves dir for a later run when its files are still being written,
+// reporting whether it did. The index forgets dir so the next run reads it again.
+func (s *Scanner) deferIfUnstable(dir string, recursive bool, handler ScanHandler) (bool, error) {
+	check := stabilityCheck{minFileAge: s.opts.MinFileAge, sizeSettle: s.opts.SizeSettle, now: time.Now}
+	reason, err := check.unstable(dir, recursive)
+	if err != nil {
+		return false, s.emitError(handler, dir, err)
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	s.opts.Index.Invalidate(dir)
+	s.progress.Deferred++
+	s.reportProgress()
+	if handler.Deferred != nil {
+		handler.Deferred(Deferral{Path: dir, Reason: reason})
+	}
+	return true, nil
+}
+
+// depth returns how many levels below the walk's root path lies, as find counts
+// them: 0 for the root and 1 for its entries
+func (s *Scanner) depth(path string) int {
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// aboveMinDepth reports whether a path at depth is too shallow to be a book. The
+// walk still descends through such directories, and in flat mode the directory
+// just above MinDepth is still checked for files being written, as its files count.
+func (s *Scanner) aboveMinDepth(depth int, isDir bool) bool {
+	if s.opts.Flat && isDir {
+		return depth+1 < s.opts.MinDepth
+	}
+	return depth < s.opts.MinDepth
+}
+
+// ValidateScanDepth checks --min-depth and --max-depth
+func ValidateScanDepth(minDepth, maxDepth int) error {
+	if minDepth < 0 || maxDepth < 0 {
+		return fmt.Errorf("min-depth and max-depth must not be negative, got: %d and %d", minDepth, maxDepth)
+	}
+	if maxDepth > 0 && minDepth > maxDepth {
+		return fmt.Errorf("min-depth %d is deeper than max-depth %d, so no book could be found", minDepth, maxDepth)
+	}
+	return nil
+}
+
+func (s *Scanner) isOutputPath(path string) bool {
+	return s.opts.OutputDir != "" &&
+		(path == s.opts.OutputDir || isSubPathOf(s.opts.OutputDir, path))
+}
+
+func (s *Scanner) isAllowed(path string) bool {
+	if !s.opts.Filter.MatchesPath(path) {
+		return false
+	}
+	if len(s.opts.AllowedSourcePaths) == 0 {
+		return true
+	}
+	return contains(s.opts.AllowedSourcePaths, path) ||
+		(s.opts.Flat && contains(s.opts.AllowedSourcePaths, filepath.Dir(path)))
+}
+
+func (s *Scanner) reportProgress() {
+	if s.opts.Progress != nil {
+		s.opts.Progress(s.progress)
+	}
+}
+
+// Progress returns the counts of the current or last walk
+func (s *Scanner) Progress() ScanProgress {
+	return s.progress
+}
+
+func (s *Scanner) emit(handler ScanHandler, book Book) error {
+	if !s.opts.Filter.MatchesMetadata(book.Metadata) {
+		s.progress.BooksFiltered++
+		return nil
+	}
+	if s.opts.Index != nil {
+		dir := book.Path
+		if s.opts.Flat {
+			dir = filepath.Dir(book.Path)
+		}
+		s.opts.Index.recordBook(dir, book.MetadataPath)
+	}
+	s.progress.BooksFound++
+	s.reportProgress()
+	if handler.Book != nil {
+		if err := handler.Book(book); err != nil {
+			return err
+		}
+	}
+	if s.opts.Flat && handler.Group != nil {
+		return s.bufferGroupBook(handler, book)
+	}
+	return nil
+}
+
+// bufferGroupBook holds a flat-mode book until its directory is finished. A directory
+// that reaches MaxGroupBooks is passed on early so the buffer never grows past it.
+func (s *Scanner) bufferGroupBook(handler ScanHandler, book Book) error {
+	dir := filepath.Dir(book.Path)
+	var group *pendingGroup
+	if n := len(s.pending); n > 0 && s.pending[n-1].dir == dir {
+		group = s.pending[n-1]
+	} else {
+		group = &pendingGroup{dir: dir}
+		s.pending = append(s.pending, group)
+	}
+
+	group.books = append(group.books, book)
+	s.buffered++
+	if s.buffered > s.peak {
+		s.peak = s.buffered
+	}
+
+	limit := s.opts.MaxGroupBooks
+	if limit <= 0 {
+		limit = DefaultMaxGroupBooks
+	}
+	if len(group.books) < limit {
+		return nil
+	}
+	group.chunked = true
+	return s.flushGroup(handler, group)
+}
+
+// finishGroups passes on every pending directory that doesn't contain path, or all of
+// them when path is empty
+func (s *Scanner) finishGroups(path string, handler ScanHandler) error {
+	for len(s.pending) > 0 {
+		group := s.pending[len(s.pending)-1]
+		if path != "" && (path == group.dir || isSubPathOf(group.dir, path)) {
+			return nil
+		}
+		s.pending = s.pending[:len(s.pending)-1]
+		if err := s.flushGroup(handler, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Scanner) flushGroup(handler ScanHandler, group *pendingGroup) error {
+	books := group.books
+	group.books = nil
+	s.buffered -= len(books)
+	if len(books) == 0 {
+		return nil
+	}
+	if group.chunked {
+		return handler.Group(Group{Dir: group.dir, Books: books})
+	}
+	return handler.Group(newFlatGroup(group.dir, books))
+}
+
+func (s *Scanner) emitError(handler ScanHandler, path string, err error) error {
+	s.opts.Index.Invalidate(path)
+	if handler.Error == nil {
+		return nil
+	}
+	return handler.Error(path, err)
+}
+
+// visitHierarchical treats each directory with usable metadata as a book and does not
+// descend into it
+func (s *Scanner) visitHierarchical(path string, info os.FileInfo, handler ScanHandler) error {
+	if !info.IsDir() || !s.isAllowed(path) {
+		return nil
+	}
+
+	started := time.Now()
+	book, held, found, err := s.readDirectoryBook(path)
+	book.readTime = time.Since(started)
+	s.progress.MetadataTime += book.readTime
+	if err != nil {
+		return s.emitError(handler, path, err)
+	}
+	if held != nil {
+		s.holdBack(handler, *held)
+		return filepath.SkipDir
+	}
+	if !found {
+		if handler.Unmatched != nil {
+			handler.Unmatched(path)
+		}
+		return nil
+	}
+
+	if deferred, err := s.deferIfUnstable(path, true, handler); deferred || err != nil {
+		if err != nil {
+			return err
+		}
+		return filepath.SkipDir
+	}
+	if book.snapshot, err = takeSourceSnapshot(path); err != nil {
+		return s.emitError(handler, path, err)
+	}
+	if err := s.emit(handler, book); err != nil {
+		return err
+	}
+	return filepath.SkipDir
+}
+
+// readDirectoryBook finds metadata for a book directory. Embedded metadata is tried
+// first when enabled, falling back to metadata.json. Embedded metadata scoring below
+// MinConfidence also falls back; without a metadata.json the book is returned as held.
+func (s *Scanner) readDirectoryBook(dir string) (Book, *LowConfidence, bool, error) {
+	var held *LowConfidence
+	if s.opts.UseEmbeddedMetadata {
+		if epubPath, err := FindEPUBInDirectory(dir); err == nil {
+			book, low, ok := s.readEmbeddedBook(dir, BookSourceEPUB, epubPath, NewEPUBMetadataProvider(epubPath))
+			if ok {
+				return book, nil, true, nil
+			}
+			held = firstLowConfidence(held, low)
+		}
+		if audioPath, err := s.opts.Extensions.findAudioFile(dir); err == nil {
+			book, low, ok := s.readEmbeddedBook(dir, BookSourceAudio, audioPath, NewAudioMetadataProvider(audioPath))
+			if ok {
+				return book, nil, true, nil
+			}
+			held = firstLowConfidence(held, low)
+		}
+	}
+
+	metadataPath := filepath.Join(dir, MetadataFileName)
+	if _, err := os.Stat(metadataPath); err != nil {
+		return Book{}, held, false, nil
+	}
+
+	provider := newJSONMetadataProvider(metadataPath, s.opts.StrictJSON)
+	metadata, err := ExtractMappedMetadata(provider, s.opts.FieldMapping)
+	if err != nil {
+		return Book{}, nil, false, fmt.Errorf("error reading %s: %w", MetadataFileName, err)
+	}
+	return Book{
+		Path:         dir,
+		Source:       BookSourceJSON,
+		MetadataPath: metadataPath,
+		Metadata:     metadata,
+		Provider:     provider,
+	}, nil, true, nil
+}
+
+// readEmbeddedBook accepts embedded metadata only when it is valid and trusted, so an
+// untagged or badly tagged file falls through to the next source. Metadata that is
+// valid but scores below MinConfidence is returned as low.
+func (s *Scanner) readEmbeddedBook(
+	dir, source, metadataPath string,
+	provider MetadataProvider,
+) (Book, *LowConfidence, bool) {
+	raw, err := provider.GetMetadata()
+	if err != nil || !raw.IsValid() {
+		return Book{}, nil, false
+	}
+	metadata, err := ExtractMappedMetadata(provider, s.opts.FieldMapping)
+	if err != nil {
+		return Book{}, nil, false
+	}
+	if low := s.lowConfidence(dir, metadataPath, metadata); low != nil {
+		return Book{}, low, false
+	}
+	return Book{
+		Path:         dir,
+		Source:       source,
+		MetadataPath: metadataPath,
+		Metadata:     metadata,
+		Provider:     provider,
+	}, nil, true
+}
+
+func firstLowConfidence(held, low *LowConfidence) *LowConfidence {
+	if held != nil {
+		return held
+	}
+	return low
+}
+
+// reportUnsupported passes on a flat-mode file whose type isn't organized. Hidden
+// files, metadata.json and extensions set to ignore or delete are left alone on
+// purpose, so they aren't reported.
+func (s *Scanner) reportUnsupported(path string, handler ScanHandler) {
+	name := filepath.Base(path)
+	if IsHiddenFile(name) || strings.EqualFold(name, MetadataFileName) || s.opts.Extensions.skips(name) {
+		return
+	}
+	s.progress.Unsupported++
+	if handler.Unsupported != nil {
+		handler.Unsupported(path)
+	}
+}
+
+// visitFlat treats every supported file as a book. A directory whose files are still
+// being written is deferred as a whole, so an album is never organized half downloaded.
+func (s *Scanner) visitFlat(path string, info os.FileInfo, handler ScanHandler) error {
+	if info.IsDir() {
+		deferred, err := s.deferIfUnstable(path, false, handler)
+		if deferred {
+			if s.deferred == nil {
+				s.deferred = make(map[string]bool)
+			}
+			s.deferred[path] = true
+		}
+		return err
+	}
+	if s.deferred[filepath.Dir(path)] || !s.isAllowed(path) {
+		return nil
+	}
+	if !s.opts.Extensions.IsOrganized(filepath.Ext(path)) {
+		s.reportUnsupported(path, handler)
+		return nil
+	}
+
+	source, provider := s.flatProvider(path)
+	started := time.Now()
+	metadata, err := ExtractMappedMetadata(provider, s.opts.FieldMapping)
+	readTime := time.Since(started)
+	s.progress.MetadataTime += readTime
+	if err != nil {
+		if !s.opts.FallbackToFilename {
+			return s.emitError(handler, path, fmt.Errorf("error getting metadata: %w", err))
+		}
+		metadata = Metadata{Title: filepath.Base(path), Authors: []string{"Unknown Author"}}
+	}
+	if held := s.lowConfidence(path, path, metadata); held != nil {
+		s.holdBack(handler, *held)
+		return nil
+	}
+	snapshot, err := takeSourceSnapshot(path)
+	if err != nil {
+		return s.emitError(handler, path, err)
+	}
+
+	return s.emit(handler, Book{
+		Path:         path,
+		Source:       source,
+		MetadataPath: path,
+		Metadata:     metadata,
+		Provider:     provider,
+		snapshot:     snapshot,
+		readTime:     readTime,
+	})
+}
+
+// flatProvider picks the provider for a flat-mode file. With embedded metadata the
+// file's own tags are used; otherwise a sibling metadata.json is merged in as well.
+func (s *Scanner) flatProvider(path string) (string, MetadataProvider) {
+	if !s.opts.UseEmbeddedMetadata {
+		return BookSourceFile, newMetadataProvider(path, false, s.opts.Extensions, s.opts.StrictJSON)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".epub") {
+		return BookSourceEPUB, NewEPUBMetadataProvider(path)
+	}
+	if IsEbookFile(filepath.Ext(path)) {
+		return BookSourceEbook, NewEbookMetadataProvider(path)
+	}
+	return BookSourceAudio, NewAudioMetadataProvider(path)
+}
+
+// GroupFlatBooks groups flat-mode books by directory. A directory whose files share a
+// title and author becomes an album ordered by track number.
+func GroupFlatBooks(books []Book) []Group {
+	var order []string
+	byDir := make(map[string][]Book)
+	for _, book := range books {
+		dir := filepath.Dir(book.Path)
+		if _, seen := byDir[dir]; !seen {
+			order = append(order, dir)
+		}
+		byDir[dir] = append(byDir[dir], book)
+	}
+
+	groups := make([]Group, 0, len(order))
+	for _, dir := range order {
+		groups = append(groups, newFlatGroup(dir, byDir[dir]))
+	}
+	return groups
+}
+
+// newFlatGroup groups the books of one directory, as an album ordered by track number
+// when their metadata is consistent
+func newFlatGroup(dir string, books []Book) Group {
+	title, artist, consistent := albumIdentity(books)
+	if len(books) < 2 || !consistent {
+		return Group{Dir: dir, Books: books}
+	}
+
+	name := title
+	if artist != "" {
+		name = artist + " - " + title
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		a, b := books[i].Metadata.TrackNumber, books[j].Metadata.TrackNumber
+		if a > 0 && b > 0 && a != b {
+			return a < b
+		}
+		return books[i].Path < books[j].Path
+	})
+	return Group{Dir: dir, Name: name, Album: true, Books: books}
+}
+
+// albumIdentity returns the shared title and first author of books, and whether their
+// metadata is consistent enough to treat them as one album
+func albumIdentity(books []Book) (string, string, bool) {
+	if len(books) == 0 {
+		return "", "", false
+	}
+
+	title := books[0].Metadata.Title
+	artist := books[0].Metadata.GetFirstAuthor("")
+	for _, book := range books[1:] {
+		currentTitle := book.Metadata.Title
+		currentArtist := book.Metadata.GetFirstAuthor("")
+		if currentTitle == title &&
+			(artist == "" || currentArtist == "" || currentArtist == artist) {
+			continue
+		}
+		if !HasTrackNumberPattern(currentTitle, title) && !HasCommonPrefix(currentTitle, title) {
+			return title, artist, false
+		}
+	}
+	return title, artist, true
+}
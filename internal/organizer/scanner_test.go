@@ -0,0 +1,229 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerHierarchical(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := filepath.Join(baseDir, "output")
+	bookA := createBookDir(t, baseDir, "BookA", "Book A", "Author A")
+	bookB := createBookDir(t, filepath.Join(baseDir, "nested"), "BookB", "Book B", "Author B")
+	createBookDir(t, outputDir, "Done", "Done", "Author C")
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "empty"), 0o755))
+
+	var progress []ScanProgress
+	scanner := NewScanner(ScanOptions{
+		OutputDir: outputDir,
+		Progress:  func(p ScanProgress) { progress = append(progress, p) },
+	})
+	result, err := scanner.Scan(baseDir)
+	require.NoError(t, err)
+
+	require.Len(t, result.Books, 2)
+	assert.Equal(t, bookA, result.Books[0].Path)
+	assert.Equal(t, BookSourceJSON, result.Books[0].Source)
+	assert.Equal(t, filepath.Join(bookA, MetadataFileName), result.Books[0].MetadataPath)
+	assert.Equal(t, "Book A", result.Books[0].Metadata.Title)
+	assert.Equal(t, bookB, result.Books[1].Path)
+	assert.Contains(t, result.Unmatched, filepath.Join(baseDir, "empty"))
+	assert.NotContains(t, result.Unmatched, outputDir)
+	assert.Empty(t, result.Groups)
+
+	require.NotEmpty(t, progress)
+	assert.Equal(t, 2, progress[len(progress)-1].BooksFound)
+}
+
+func TestScannerAllowedSourcePaths(t *testing.T) {
+	baseDir := t.TempDir()
+	createBookDir(t, baseDir, "BookA", "Book A", "Author A")
+	bookB := createBookDir(t, baseDir, "BookB", "Book B", "Author B")
+
+	result, err := NewScanner(ScanOptions{AllowedSourcePaths: []string{bookB}}).Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, bookB, result.Books[0].Path)
+}
+
+func TestScannerReportsUnreadableMetadata(t *testing.T) {
+	baseDir := t.TempDir()
+	bookDir := filepath.Join(baseDir, "Broken")
+	require.NoError(t, os.MkdirAll(bookDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bookDir, MetadataFileName), []byte("{not json"), 0o644))
+
+	result, err := NewScanner(ScanOptions{}).Scan(baseDir)
+	require.NoError(t, err)
+	assert.Empty(t, result.Books)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, bookDir, result.Errors[0].Path)
+}
+
+func TestScannerFlatFallbackToFilename(t *testing.T) {
+	baseDir := t.TempDir()
+	file := filepath.Join(baseDir, "mystery.mp3")
+	require.NoError(t, os.WriteFile(file, []byte("not really audio"), 0o644))
+
+	result, err := NewScanner(ScanOptions{Flat: true, UseEmbeddedMetadata: true}).Scan(baseDir)
+	require.NoError(t, err)
+	assert.Empty(t, result.Books)
+	require.Len(t, result.Errors, 1)
+
+	result, err = NewScanner(ScanOptions{Flat: true, FallbackToFilename: true}).Scan(baseDir)
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, "mystery.mp3", result.Books[0].Metadata.Title)
+	require.Len(t, result.Groups, 1)
+	assert.False(t, result.Groups[0].Album)
+}
+
+func TestGroupFlatBooks(t *testing.T) {
+	book := func(path, title, author string, track int) Book {
+		return Book{
+			Path:     path,
+			Metadata: Metadata{Title: title, Authors: []string{author}, TrackNumber: track},
+		}
+	}
+
+	groups := GroupFlatBooks([]Book{
+		book("/lib/album/b.mp3", "Saga", "Author", 2),
+		book("/lib/album/a.mp3", "Saga", "Author", 1),
+		book("/lib/album/c.mp3", "Saga", "Author", 0),
+		book("/lib/mixed/x.mp3", "Cooking", "Chef", 0),
+		book("/lib/mixed/y.mp3", "Astronomy", "Scientist", 0),
+	})
+
+	require.Len(t, groups, 2)
+	album := groups[0]
+	assert.True(t, album.Album)
+	assert.Equal(t, "Author - Saga", album.Name)
+	assert.Equal(t, "/lib/album/a.mp3", album.Books[0].Path)
+	assert.Equal(t, "/lib/album/b.mp3", album.Books[1].Path)
+	assert.Equal(t, 3, album.TrackNumber(2))
+
+	assert.False(t, groups[1].Album)
+	assert.Len(t, groups[1].Books, 2)
+}
+
+// writeFlatFiles creates count untagged audio files in dir
+func writeFlatFiles(tb testing.TB, dir string, count int) {
+	tb.Helper()
+	require.NoError(tb, os.MkdirAll(dir, 0o755))
+	for i := 0; i < count; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("file%05d.mp3", i))
+		require.NoError(tb, os.WriteFile(name, []byte("not really audio"), 0o644))
+	}
+}
+
+func TestScannerStreamsFlatGroups(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFlatFiles(t, filepath.Join(baseDir, "a"), 2)
+	writeFlatFiles(t, filepath.Join(baseDir, "a", "sub"), 1)
+	writeFlatFiles(t, filepath.Join(baseDir, "b"), 1)
+
+	var events []string
+	err := NewScanner(ScanOptions{Flat: true, FallbackToFilename: true}).Walk(baseDir, ScanHandler{
+		Book: func(book Book) error {
+			events = append(events, "book "+filepath.Base(filepath.Dir(book.Path)))
+			return nil
+		},
+		Group: func(group Group) error {
+			events = append(events, fmt.Sprintf("group %s %d", filepath.Base(group.Dir), len(group.Books)))
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	// Each directory is passed on once the walk leaves it, before later books are read
+	assert.Equal(t, []string{
+		"book a", "book a", "book sub",
+		"group sub 1", "group a 2",
+		"book b", "group b 1",
+	}, events)
+}
+
+func TestScannerBoundsGroupBuffer(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFlatFiles(t, baseDir, 25)
+
+	var sizes []int
+	scanner := NewScanner(ScanOptions{Flat: true, FallbackToFilename: true, MaxGroupBooks: 10})
+	err := scanner.Walk(baseDir, ScanHandler{
+		Group: func(group Group) error {
+			assert.False(t, group.Album)
+			sizes = append(sizes, len(group.Books))
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 10, 5}, sizes)
+	assert.Equal(t, 10, scanner.peak)
+	assert.Zero(t, scanner.buffered)
+}
+
+func TestScannerDepthLimits(t *testing.T) {
+	baseDir := t.TempDir()
+	createBookDir(t, baseDir, "Loose Book", "Loose Book", "Nobody")
+	createBookDir(t, filepath.Join(baseDir, "Frank Herbert"), "Dune", "Dune", "Frank Herbert")
+	createBookDir(t, filepath.Join(baseDir, "Frank Herbert", "extras"), "Bonus", "Bonus", "Frank Herbert")
+
+	titles := func(opts ScanOptions) []string {
+		result, err := NewScanner(opts).Scan(baseDir)
+		require.NoError(t, err)
+		var found []string
+		for _, book := range result.Books {
+			found = append(found, book.Metadata.Title)
+		}
+		sort.Strings(found)
+		return found
+	}
+
+	assert.Equal(t, []string{"Bonus", "Dune", "Loose Book"}, titles(ScanOptions{}))
+	assert.Equal(t, []string{"Dune", "Loose Book"}, titles(ScanOptions{MaxDepth: 2}))
+	assert.Equal(t, []string{"Dune"}, titles(ScanOptions{MinDepth: 2, MaxDepth: 2}))
+
+	// In flat mode the limits apply to the files: Author/Book/audio.mp3 is level 3
+	flat := ScanOptions{Flat: true, FallbackToFilename: true}
+	assert.Equal(t, []string{"audio.mp3", "audio.mp3", "audio.mp3"}, titles(flat))
+	flat.MaxDepth = 3
+	assert.Len(t, titles(flat), 2)
+	flat.MinDepth = 3
+	assert.Len(t, titles(flat), 1)
+}
+
+func TestValidateScanDepth(t *testing.T) {
+	assert.NoError(t, ValidateScanDepth(0, 0))
+	assert.NoError(t, ValidateScanDepth(2, 0))
+	assert.NoError(t, ValidateScanDepth(2, 2))
+	assert.ErrorContains(t, ValidateScanDepth(3, 2), "deeper than max-depth")
+	assert.ErrorContains(t, ValidateScanDepth(-1, 0), "must not be negative")
+}
+
+// BenchmarkScannerFlatDump streams a large flat directory through the group handler.
+// The peak-books metric stays at MaxGroupBooks however many files the directory has.
+func BenchmarkScannerFlatDump(b *testing.B) {
+	baseDir := b.TempDir()
+	writeFlatFiles(b, baseDir, 5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	peak := 0
+	for i := 0; i < b.N; i++ {
+		scanner := NewScanner(ScanOptions{Flat: true, FallbackToFilename: true, MaxGroupBooks: 500})
+		err := scanner.Walk(baseDir, ScanHandler{Group: func(Group) error { return nil }})
+		require.NoError(b, err)
+		peak = scanner.peak
+	}
+	b.ReportMetric(float64(peak), "peak-books")
+	if peak > 500 {
+		b.Fatalf("held %d books, want at most 500", peak)
+	}
+}
@@ -0,0 +1,50 @@
+package organizer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadSelectionFile reads the book paths listed in a selection file, one per line.
+// Blank lines and lines starting with # are ignored.
+func ReadSelectionFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening selection file: %v", err)
+	}
+	defer file.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading selection file: %v", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("selection file %s lists no books", path)
+	}
+	return paths, nil
+}
+
+// WriteSelectionFile writes paths to a selection file that ReadSelectionFile and
+// --selection accept, after a comment header
+func WriteSelectionFile(path, header string, paths []string) error {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+		if line != "" {
+			b.WriteString("# " + line + "\n")
+		}
+	}
+	for _, p := range paths {
+		b.WriteString(p + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
@@ -0,0 +1,62 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectionFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selection.txt")
+	paths := []string{"/books/Dune", "/books/Emma/emma.m4b"}
+
+	require.NoError(t, WriteSelectionFile(path, "Selected in the TUI\n", paths))
+	got, err := ReadSelectionFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, paths, got)
+}
+
+func TestReadSelectionFileSkipsCommentsAndBlankLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selection.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# books\n\n  /books/Dune  \n#/books/Skipped\n"), 0o644))
+
+	got, err := ReadSelectionFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/books/Dune"}, got)
+}
+
+func TestReadSelectionFileErrors(t *testing.T) {
+	_, err := ReadSelectionFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(empty, []byte("# nothing\n"), 0o644))
+	_, err = ReadSelectionFile(empty)
+	assert.ErrorContains(t, err, "lists no books")
+}
+
+func TestOrganizerSelectionLimitsRun(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	for _, title := range []string{"Dune", "Emma"} {
+		createBookDir(t, baseDir, title, title, "Someone")
+	}
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:            baseDir,
+		OutputDir:          outputDir,
+		Layout:             "author-title",
+		AllowedSourcePaths: []string{filepath.Join(baseDir, "Dune")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	assert.FileExists(t, filepath.Join(outputDir, "Someone", "Dune", "audio.mp3"))
+	assert.NoDirExists(t, filepath.Join(outputDir, "Someone", "Emma"))
+	assert.FileExists(t, filepath.Join(baseDir, "Emma", "audio.mp3"))
+}
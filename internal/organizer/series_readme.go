@@ -0,0 +1,94 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SeriesReadmeFileName is the summary SeriesReadme writes into each series folder
+const SeriesReadmeFileName = "SERIES.md"
+
+// noteSeriesDir remembers the folder above a book moved by this run, which is its
+// series folder in layouts that have one
+func (o *Organizer) noteSeriesDir(targetDir string) {
+	if !o.config.SeriesReadme || o.hasRemoteTarget() {
+		return
+	}
+	if o.seriesDirs == nil {
+		o.seriesDirs = make(map[string]bool)
+	}
+	o.seriesDirs[filepath.Dir(filepath.Clean(targetDir))] = true
+}
+
+// writeSeriesReadmes rewrites the SERIES.md of every series folder this run added a
+// book to. A folder counts as a series folder when the books below it belong to one
+// series whose name matches the folder's, so author folders of layouts without
+// series folders get no file.
+func (o *Organizer) writeSeriesReadmes() {
+	dirs := make([]string, 0, len(o.seriesDirs))
+	for dir := range o.seriesDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	// Organized books each have their own folder, even when they came from a flat dump
+	options := ScanOptions{
+		UseEmbeddedMetadata: o.config.UseEmbeddedMetadata || o.config.Flat,
+		StrictJSON:          o.config.StrictJSON,
+		FieldMapping:        o.config.FieldMapping,
+		SkipUnreadable:      true,
+		Extensions:          o.config.Extensions,
+	}
+	for _, dir := range dirs {
+		result, err := NewScanner(options).Scan(dir)
+		if err != nil {
+			PrintYellow("⚠️  Warning: couldn't read series folder %s: %v", dir, err)
+			continue
+		}
+		report := BuildSeriesReport(result.Books, o.layoutCalculator.collation)
+		if len(report) != 1 || !o.naming().Equivalent(filepath.Base(dir), o.layoutCalculator.sanitizer(report[0].Series)) {
+			continue
+		}
+		path := filepath.Join(dir, SeriesReadmeFileName)
+		if err := os.WriteFile(path, []byte(formatSeriesReadme(report[0], dir)), 0o644); err != nil {
+			PrintYellow("⚠️  Warning: couldn't write %s: %v", path, err)
+			continue
+		}
+		o.debugLog("Wrote %s", path)
+	}
+}
+
+// formatSeriesReadme renders a series as Markdown: its books in series order with
+// their numbers, narrators, and playing times, and the gaps in the numbering
+func formatSeriesReadme(status SeriesStatus, dir string) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "# %s\n\nby %s\n\n", escapeMarkdownTable(status.Series), escapeMarkdownTable(status.Author))
+	text.WriteString("| # | Title | Narrators | Duration | Folder |\n")
+	text.WriteString("|---|-------|-----------|----------|--------|\n")
+	for _, book := range status.Books {
+		folder, err := filepath.Rel(dir, book.Path)
+		if err != nil {
+			folder = book.Path
+		}
+		fmt.Fprintf(&text, "| %s | %s | %s | %s | %s |\n",
+			orDash(book.Number),
+			escapeMarkdownTable(book.Title),
+			orDash(escapeMarkdownTable(strings.Join(book.Narrators, ", "))),
+			orDash(FormatDuration(book.Duration)),
+			escapeMarkdownTable(filepath.ToSlash(folder)),
+		)
+	}
+	if len(status.Gaps) > 0 {
+		fmt.Fprintf(&text, "\nNot in the library: #%s\n", strings.Join(status.Gaps, ", #"))
+	}
+	text.WriteString("\n_Written by audiobook-organizer and rewritten when books are added to this series._\n")
+	return text.String()
+}
+
+// escapeMarkdownTable keeps a value from breaking out of its Markdown table cell
+func escapeMarkdownTable(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
@@ -0,0 +1,60 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesReadme(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	writeBook(t, filepath.Join(baseDir, "one"), map[string]interface{}{
+		"title": "Dune", "authors": []string{"Frank Herbert"}, "series": []string{"Dune #1"},
+		"narrators": []string{"Scott Brick", "Orlagh Cassidy"}, "duration": 21*3600 + 2*60,
+	}, "audio.mp3")
+	writeBook(t, filepath.Join(baseDir, "three"), map[string]interface{}{
+		"title": "Children of Dune", "authors": []string{"Frank Herbert"}, "series": []string{"Dune #3"},
+	}, "audio.mp3")
+	createBookDir(t, baseDir, "standalone", "The Dragon in the Sea", "Frank Herbert")
+
+	config := OrganizerConfig{BaseDir: baseDir, OutputDir: outputDir, FieldMapping: DefaultFieldMapping(), SeriesReadme: true}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	readme := filepath.Join(outputDir, "Frank Herbert", "Dune", SeriesReadmeFileName)
+	data, err := os.ReadFile(readme)
+	require.NoError(t, err)
+	assert.Equal(t, `# Dune
+
+by Frank Herbert
+
+| # | Title | Narrators | Duration | Folder |
+|---|-------|-----------|----------|--------|
+| 1 | Dune | Scott Brick, Orlagh Cassidy | 21h02m | Dune |
+| 3 | Children of Dune | - | - | Children of Dune |
+
+Not in the library: #2
+
+_Written by audiobook-organizer and rewritten when books are added to this series._
+`, string(data))
+	assert.NoFileExists(t, filepath.Join(outputDir, "Frank Herbert", SeriesReadmeFileName), "the author folder is not a series folder")
+
+	// A later run adding a book rewrites the file
+	writeBook(t, filepath.Join(baseDir, "two"), map[string]interface{}{
+		"title": "Dune Messiah", "authors": []string{"Frank Herbert"}, "series": []string{"Dune #2"},
+	}, "audio.mp3")
+	org, err = NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+	data, err = os.ReadFile(readme)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "| 2 | Dune Messiah | - | - | Dune Messiah |\n| 3 |")
+	assert.NotContains(t, string(data), "Not in the library")
+}
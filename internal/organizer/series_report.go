@@ -0,0 +1,253 @@
+package organizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSeriesCatalogURL is the Audible catalog API queried by series report --online
+const DefaultSeriesCatalogURL = "https://api.audible.com"
+
+// SeriesBook is one numbered or unnumbered entry of a series
+type SeriesBook struct {
+	Title     string   `json:"title"`
+	Number    string   `json:"number,omitempty"`
+	Path      string   `json:"path,omitempty"` // Empty for books only known to the catalog
+	Narrators []string `json:"narrators,omitempty"`
+	Duration  float64  `json:"duration,omitempty"` // Seconds, when the metadata has it
+}
+
+// SeriesStatus describes how complete one series of the library is
+type SeriesStatus struct {
+	Author     string       `json:"author"`
+	Series     string       `json:"series"`
+	Dir        string       `json:"dir"`
+	Books      []SeriesBook `json:"books"`                // Owned books, in series order
+	Unnumbered []string     `json:"unnumbered,omitempty"` // Owned books without a series number
+	Gaps       []string     `json:"gaps,omitempty"`       // Whole numbers below the highest owned one that are missing
+	Missing    []SeriesBook `json:"missing,omitempty"`    // Catalog entries not in the library
+	Checked    bool         `json:"checked"`              // The catalog was asked about this series
+	Error      string       `json:"error,omitempty"`      // Why the catalog check failed
+}
+
+// Complete reports whether nothing is known to be missing or unnumbered
+func (s SeriesStatus) Complete() bool {
+	return len(s.Unnumbered) == 0 && len(s.Gaps) == 0 && len(s.Missing) == 0
+}
+
+// SeriesCatalog lists every book of a series from an outside source
+type SeriesCatalog interface {
+	SeriesBooks(author, series string) ([]SeriesBook, error)
+}
+
+// BuildSeriesReport groups books by author and series, sorted in the collation order,
+// and finds unnumbered entries and gaps in the numbering. Books without a series are
+// left out.
+func BuildSeriesReport(books []Book, collation *Collation) []SeriesStatus {
+	byKey := make(map[string]*SeriesStatus)
+	var order []string
+	for _, book := range books {
+		metadata := book.Metadata
+		series := metadata.GetValidSeries()
+		if series == "" {
+			continue
+		}
+		author := metadata.GetFirstAuthor("Unknown Author")
+		key := normalizeForComparison(author, true) + "\x00" + normalizeForComparison(series, true)
+		status, ok := byKey[key]
+		if !ok {
+			status = &SeriesStatus{Author: author, Series: series, Dir: filepath.Dir(book.Path)}
+			byKey[key] = status
+			order = append(order, key)
+		}
+
+		entry := SeriesBook{
+			Title:     metadata.Title,
+			Number:    GetSeriesNumberFromMetadata(metadata),
+			Path:      book.Path,
+			Narrators: metadata.GetNarrators(),
+			Duration:  metadata.GetDuration(),
+		}
+		status.Books = append(status.Books, entry)
+		if entry.Number == "" {
+			status.Unnumbered = append(status.Unnumbered, entry.Title)
+		}
+	}
+
+	report := make([]SeriesStatus, 0, len(order))
+	for _, key := range order {
+		status := byKey[key]
+		sortSeriesBooks(status.Books)
+		status.Gaps = seriesGaps(status.Books)
+		report = append(report, *status)
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		if byAuthor := collation.Compare(report[i].Author, report[j].Author); byAuthor != 0 {
+			return byAuthor < 0
+		}
+		return collation.Compare(report[i].Series, report[j].Series) < 0
+	})
+	return report
+}
+
+// CheckSeriesOnline adds the catalog entries each series is missing. A series the
+// catalog can't answer for keeps its offline findings and records the error.
+func CheckSeriesOnline(report []SeriesStatus, catalog SeriesCatalog) {
+	for i := range report {
+		status := &report[i]
+		entries, err := catalog.SeriesBooks(status.Author, status.Series)
+		if err != nil {
+			status.Error = err.Error()
+			continue
+		}
+		status.Checked = true
+
+		owned := make(map[string]bool)
+		for _, book := range status.Books {
+			if number := normalizeSeriesNumber(book.Number); number != "" {
+				owned["#"+number] = true
+			}
+			owned[normalizeForComparison(book.Title, false)] = true
+		}
+		for _, entry := range entries {
+			number := normalizeSeriesNumber(entry.Number)
+			if (number != "" && owned["#"+number]) || owned[normalizeForComparison(entry.Title, false)] {
+				continue
+			}
+			status.Missing = append(status.Missing, entry)
+		}
+		sortSeriesBooks(status.Missing)
+	}
+}
+
+// sortSeriesBooks orders books by series number, unnumbered books last
+func sortSeriesBooks(books []SeriesBook) {
+	sort.SliceStable(books, func(i, j int) bool {
+		a, aOK := parseSeriesNumber(books[i].Number)
+		b, bOK := parseSeriesNumber(books[j].Number)
+		if aOK != bOK {
+			return aOK
+		}
+		return aOK && a < b
+	})
+}
+
+// seriesGaps lists the whole numbers from 1 to the highest owned number that no
+// owned book has
+func seriesGaps(books []SeriesBook) []string {
+	have := make(map[int]bool)
+	highest := 0
+	for _, book := range books {
+		number, ok := parseSeriesNumber(book.Number)
+		if !ok || number < 1 {
+			continue
+		}
+		if number == float64(int(number)) {
+			have[int(number)] = true // Novellas such as #1.5 don't fill a gap
+		}
+		if int(number) > highest {
+			highest = int(number)
+		}
+	}
+	var gaps []string
+	for n := 1; n <= highest; n++ {
+		if !have[n] {
+			gaps = append(gaps, strconv.Itoa(n))
+		}
+	}
+	return gaps
+}
+
+func parseSeriesNumber(number string) (float64, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	return value, err == nil
+}
+
+// normalizeSeriesNumber makes "01", "1", and "1.0" compare equal
+func normalizeSeriesNumber(number string) string {
+	value, ok := parseSeriesNumber(number)
+	if !ok {
+		return strings.TrimSpace(number)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// AudibleCatalog looks series up in the public Audible catalog API
+type AudibleCatalog struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewAudibleCatalog creates a catalog for baseURL, DefaultSeriesCatalogURL when empty.
+// Regional stores such as https://api.audible.co.uk answer in the same format.
+func NewAudibleCatalog(baseURL string) *AudibleCatalog {
+	if baseURL == "" {
+		baseURL = DefaultSeriesCatalogURL
+	}
+	return &AudibleCatalog{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SeriesBooks searches the catalog for the series and returns the products that
+// belong to it, one per series position
+func (c *AudibleCatalog) SeriesBooks(author, series string) ([]SeriesBook, error) {
+	query := url.Values{
+		"keywords":        {series},
+		"author":          {author},
+		"num_results":     {"50"},
+		"response_groups": {"series,product_attrs"},
+	}
+	response, err := c.HTTPClient.Get(c.BaseURL + "/1.0/catalog/products?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("catalog lookup for %s failed: %w", series, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("catalog lookup for %s failed: %s", series, response.Status)
+	}
+
+	var result struct {
+		Products []struct {
+			Title  string `json:"title"`
+			Series []struct {
+				Title    string `json:"title"`
+				Sequence string `json:"sequence"`
+			} `json:"series"`
+		} `json:"products"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("catalog lookup for %s returned invalid JSON: %w", series, err)
+	}
+
+	want := normalizeForComparison(series, false)
+	seen := make(map[string]bool)
+	var books []SeriesBook
+	for _, product := range result.Products {
+		for _, s := range product.Series {
+			if normalizeForComparison(s.Title, false) != want {
+				continue
+			}
+			// Audible lists several editions of a book under the same position
+			key := normalizeSeriesNumber(s.Sequence)
+			if key == "" {
+				key = normalizeForComparison(product.Title, false)
+			}
+			if seen[key] {
+				break
+			}
+			seen[key] = true
+			books = append(books, SeriesBook{Title: product.Title, Number: s.Sequence})
+			break
+		}
+	}
+	return books, nil
+}
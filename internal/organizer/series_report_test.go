@@ -0,0 +1,111 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesTestBook(path, title, author, series string) Book {
+	return Book{Path: path, Metadata: Metadata{Title: title, Authors: []string{author}, Series: []string{series}}}
+}
+
+func TestBuildSeriesReport(t *testing.T) {
+	report := BuildSeriesReport([]Book{
+		seriesTestBook("/lib/Sanderson/Stormlight/Oathbringer", "Oathbringer", "Brandon Sanderson", "The Stormlight Archive #3"),
+		seriesTestBook("/lib/Sanderson/Stormlight/Way of Kings", "The Way of Kings", "Brandon Sanderson", "The Stormlight Archive #1"),
+		seriesTestBook("/lib/Sanderson/Stormlight/Edgedancer", "Edgedancer", "Brandon Sanderson", "The Stormlight Archive #2.5"),
+		seriesTestBook("/lib/Sanderson/Stormlight/Dawnshard", "Dawnshard", "brandon sanderson", "The Stormlight Archive"),
+		seriesTestBook("/lib/Adams/Hitchhiker/Guide", "The Hitchhiker's Guide", "Douglas Adams", "Hitchhiker #1"),
+		{Path: "/lib/Herbert/Dune", Metadata: Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}}},
+	}, nil)
+	require.Len(t, report, 2, "books without a series are left out")
+
+	assert.Equal(t, "Douglas Adams", report[1].Author)
+	assert.True(t, report[1].Complete())
+
+	stormlight := report[0]
+	assert.Equal(t, "The Stormlight Archive", stormlight.Series)
+	assert.Equal(t, "/lib/Sanderson/Stormlight", stormlight.Dir)
+	var titles []string
+	for _, book := range stormlight.Books {
+		titles = append(titles, book.Title)
+	}
+	assert.Equal(t, []string{"The Way of Kings", "Edgedancer", "Oathbringer", "Dawnshard"}, titles)
+	assert.Equal(t, []string{"Dawnshard"}, stormlight.Unnumbered)
+	assert.Equal(t, []string{"2"}, stormlight.Gaps, "a novella doesn't fill a gap")
+	assert.False(t, stormlight.Complete())
+}
+
+type fakeSeriesCatalog map[string][]SeriesBook
+
+func (c fakeSeriesCatalog) SeriesBooks(author, series string) ([]SeriesBook, error) {
+	books, ok := c[series]
+	if !ok {
+		return nil, errors.New("unavailable")
+	}
+	return books, nil
+}
+
+func TestCheckSeriesOnline(t *testing.T) {
+	report := BuildSeriesReport([]Book{
+		seriesTestBook("/lib/a/1", "The Way of Kings", "Brandon Sanderson", "The Stormlight Archive #01"),
+		seriesTestBook("/lib/a/2", "Words of Radiance", "Brandon Sanderson", "The Stormlight Archive"),
+		seriesTestBook("/lib/b/1", "Guide", "Douglas Adams", "Hitchhiker #1"),
+	}, nil)
+	CheckSeriesOnline(report, fakeSeriesCatalog{
+		"The Stormlight Archive": {
+			{Title: "Rhythm of War", Number: "4"},
+			{Title: "Words of Radiance", Number: "2"},
+			{Title: "The Way of Kings", Number: "1"},
+			{Title: "Oathbringer", Number: "3"},
+		},
+	})
+
+	assert.Equal(t, "unavailable", report[1].Error)
+	assert.False(t, report[1].Checked)
+	assert.True(t, report[1].Complete(), "offline findings are kept when the catalog fails")
+
+	stormlight := report[0]
+	assert.True(t, stormlight.Checked)
+	assert.Equal(t, []SeriesBook{{Title: "Oathbringer", Number: "3"}, {Title: "Rhythm of War", Number: "4"}}, stormlight.Missing,
+		"owned books match by number or title")
+}
+
+func TestAudibleCatalogSeriesBooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/1.0/catalog/products", r.URL.Path)
+		assert.Equal(t, "Mistborn", r.URL.Query().Get("keywords"))
+		assert.Equal(t, "Brandon Sanderson", r.URL.Query().Get("author"))
+		json.NewEncoder(w).Encode(map[string]any{"products": []map[string]any{
+			{"title": "The Final Empire", "series": []map[string]any{{"title": "Mistborn", "sequence": "1"}}},
+			{"title": "The Final Empire (Dramatized)", "series": []map[string]any{{"title": "Mistborn", "sequence": "1"}}},
+			{"title": "The Well of Ascension", "series": []map[string]any{{"title": "The Cosmere", "sequence": "5"}, {"title": "Mistborn", "sequence": "2"}}},
+			{"title": "Elantris", "series": []map[string]any{{"title": "The Cosmere", "sequence": "1"}}},
+			{"title": "Secret History", "series": []map[string]any{{"title": "Mistborn"}}},
+		}})
+	}))
+	defer server.Close()
+
+	books, err := NewAudibleCatalog(server.URL+"/").SeriesBooks("Brandon Sanderson", "Mistborn")
+	require.NoError(t, err)
+	assert.Equal(t, []SeriesBook{
+		{Title: "The Final Empire", Number: "1"},
+		{Title: "The Well of Ascension", Number: "2"},
+		{Title: "Secret History"},
+	}, books)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	_, err = NewAudibleCatalog(failing.URL).SeriesBooks("Brandon Sanderson", "Mistborn")
+	assert.Error(t, err)
+}
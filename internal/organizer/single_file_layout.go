@@ -0,0 +1,153 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SingleFileLayout decides whether a book made of one audio file, like most M4B
+// books, gets a folder of its own
+type SingleFileLayout string
+
+const (
+	// SingleFileFolder puts the file in the book's folder, Author/Series/Title/Title.m4b
+	// (the default, as Audiobookshelf recommends)
+	SingleFileFolder SingleFileLayout = "folder"
+	// SingleFileFile puts the file where the book's folder would be, named after it:
+	// Author/Series/Title.m4b
+	SingleFileFile SingleFileLayout = "file"
+)
+
+// ParseSingleFileLayout parses a --single-file-layout value; "" selects SingleFileFolder
+func ParseSingleFileLayout(value string) (SingleFileLayout, error) {
+	switch layout := SingleFileLayout(strings.ToLower(strings.TrimSpace(value))); layout {
+	case "":
+		return SingleFileFolder, nil
+	case SingleFileFolder, SingleFileFile:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("invalid single-file layout %q (use folder or file)", value)
+	}
+}
+
+// flattensSingleFiles reports whether single-file books are moved without a folder.
+// The author-only layout has no book folder to replace, so it keeps its files as
+// they are.
+func (lc *LayoutCalculator) flattensSingleFiles() bool {
+	return lc.config.SingleFileLayout == SingleFileFile &&
+		(strings.TrimSpace(lc.config.LayoutTemplate) != "" || lc.config.Layout != "author-only")
+}
+
+// SingleFilePath returns where the only file of a book goes when the layout puts
+// the book in bookDir: inside it, or with SingleFileFile, in its parent named after
+// it and keeping the file's extension
+func (lc *LayoutCalculator) SingleFilePath(bookDir, fileName string) string {
+	if !lc.flattensSingleFiles() {
+		return filepath.Join(bookDir, fileName)
+	}
+	return filepath.Clean(bookDir) + filepath.Ext(fileName)
+}
+
+// singleFileBookTarget returns the folder a book directory's files are moved to and
+// the name its audio file takes there. A book of one audio file, whose other files
+// are all sidecars of it, goes beside targetPath with SingleFileFile; any other book
+// keeps targetPath and an empty name. Books that get identifiers.json or
+// original-folder.txt, and books renamed in place, keep their folder.
+func (o *Organizer) singleFileBookTarget(sourcePath, targetPath string) (string, string) {
+	if !o.layoutCalculator.flattensSingleFiles() || o.config.WriteIdentifiers || o.config.KeepProvenance ||
+		filepath.Clean(sourcePath) == filepath.Clean(targetPath) {
+		return targetPath, ""
+	}
+	entries, err := os.ReadDir(sourcePath)
+	if err != nil {
+		return targetPath, ""
+	}
+
+	var names []string
+	audio := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || o.config.Extensions.skips(name) ||
+			(IsHiddenFile(name) && o.hiddenFilePolicy() != HiddenFilesMove) {
+			continue
+		}
+		if o.config.Extensions.IsAudio(filepath.Ext(name)) {
+			if audio != "" {
+				return targetPath, ""
+			}
+			audio = name
+		}
+		names = append(names, name)
+	}
+	if audio == "" {
+		return targetPath, ""
+	}
+	companions := o.config.Extensions.MatchCompanions(names)
+	for _, name := range names {
+		if name != audio && companions[name] != audio {
+			return targetPath, "" // A cover.jpg or metadata.json of its own needs the folder
+		}
+	}
+
+	flattened := o.layoutCalculator.SingleFilePath(targetPath, audio)
+	return filepath.Dir(flattened), filepath.Base(flattened)
+}
+
+// isWholeBookFile reports whether a file organized in flat mode is a book of its
+// own rather than one track of several: an audio file without a track number of
+// several that is an M4B or the only audio file in its folder. Loose untagged
+// tracks of one book would otherwise all be named after it.
+func (o *Organizer) isWholeBookFile(filePath string, metadata Metadata) bool {
+	ext := filepath.Ext(filePath)
+	if !o.config.Extensions.IsAudio(ext) || ShouldAddTrackPrefix(metadata.TrackNumber, TrackTotalFromMetadata(metadata)) {
+		return false
+	}
+	if strings.EqualFold(ext, ".m4b") {
+		return true
+	}
+	return o.audioFilesIn(filepath.Dir(filePath)) == 1
+}
+
+// audioFilesIn counts the audio files of dir, reading each folder once per run
+func (o *Organizer) audioFilesIn(dir string) int {
+	if count, ok := o.audioCounts[dir]; ok {
+		return count
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && o.config.Extensions.IsAudio(filepath.Ext(entry.Name())) {
+			count++
+		}
+	}
+	if o.audioCounts == nil {
+		o.audioCounts = make(map[string]int)
+	}
+	o.audioCounts[dir] = count
+	return count
+}
+
+// nameAfterBook renames the planned files of a single-file book: the audio file to
+// bookName and its sidecars to match, as "Title.cue" for "Title.m4b"
+func (o *Organizer) nameAfterBook(fileNames []FilePair, bookName string) []FilePair {
+	names := make([]string, len(fileNames))
+	for i, file := range fileNames {
+		names[i] = file.From
+	}
+	companions := o.config.Extensions.MatchCompanions(names)
+
+	renamed := make([]FilePair, len(fileNames))
+	for i, file := range fileNames {
+		renamed[i] = FilePair{From: file.From, To: bookName}
+		if audio, ok := companions[file.From]; ok {
+			suffix, _ := companionSuffix(audio, file.From)
+			renamed[i].To = CompanionTargetName(bookName, suffix)
+		}
+	}
+	return renamed
+}
@@ -0,0 +1,117 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSingleFileLayout(t *testing.T) {
+	for value, want := range map[string]SingleFileLayout{"": SingleFileFolder, "folder": SingleFileFolder, " FILE ": SingleFileFile} {
+		got, err := ParseSingleFileLayout(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, value)
+	}
+	_, err := ParseSingleFileLayout("flat")
+	assert.ErrorContains(t, err, "use folder or file")
+}
+
+func TestSingleFileLayoutMovesBookWithoutFolder(t *testing.T) {
+	dune := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}, Series: []string{"Dune #1"}}
+
+	tests := []struct {
+		name   string
+		layout SingleFileLayout
+		files  []string
+		want   []string
+	}{
+		{
+			name:   "file",
+			layout: SingleFileFile,
+			files:  []string{"rip.m4b", "rip.cue"},
+			want:   []string{"Frank Herbert/Dune/Dune.m4b", "Frank Herbert/Dune/Dune.cue"},
+		},
+		{
+			name:   "folder",
+			layout: SingleFileFolder,
+			files:  []string{"rip.m4b"},
+			want:   []string{"Frank Herbert/Dune/Dune/rip.m4b"},
+		},
+		{
+			name:   "file with a cover of its own keeps the folder",
+			layout: SingleFileFile,
+			files:  []string{"rip.m4b", "cover.jpg"},
+			want:   []string{"Frank Herbert/Dune/Dune/rip.m4b", "Frank Herbert/Dune/Dune/cover.jpg"},
+		},
+		{
+			name:   "file with several tracks keeps the folder",
+			layout: SingleFileFile,
+			files:  []string{"01.mp3", "02.mp3"},
+			want:   []string{"Frank Herbert/Dune/Dune/01.mp3", "Frank Herbert/Dune/Dune/02.mp3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			output := t.TempDir()
+			book := filepath.Join(base, "download")
+			writeBook(t, book, nil, tt.files...)
+
+			org, err := NewOrganizer(&OrganizerConfig{
+				BaseDir:          base,
+				OutputDir:        output,
+				FieldMapping:     DefaultFieldMapping(),
+				SingleFileLayout: tt.layout,
+			})
+			require.NoError(t, err)
+			CaptureOutput(func() {
+				require.NoError(t, org.OrganizePathWithMetadata(book, dune))
+			})
+
+			for _, want := range tt.want {
+				assert.FileExists(t, filepath.Join(output, filepath.FromSlash(want)))
+			}
+			require.Len(t, org.GetSummary().Moves, 1)
+			if tt.name == "file" {
+				assert.Equal(t, filepath.Join(output, "Frank Herbert", "Dune", "Dune.m4b"), org.GetSummary().Moves[0].To)
+			}
+		})
+	}
+}
+
+func TestSingleFileLayoutFlatMode(t *testing.T) {
+	base := t.TempDir()
+	writeBook(t, filepath.Join(base, "loose"), nil, "Dune.m4b")
+	writeBook(t, filepath.Join(base, "album"), nil, "01.mp3", "02.mp3")
+	writeBook(t, filepath.Join(base, "single"), nil, "Emma.mp3")
+
+	newOrganizer := func(config OrganizerConfig) *Organizer {
+		config.BaseDir, config.OutputDir, config.Flat = base, "/out", true
+		org, err := NewOrganizer(&config)
+		require.NoError(t, err)
+		return org
+	}
+	metadata := Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}}
+
+	org := newOrganizer(OrganizerConfig{Layout: "author-title", SingleFileLayout: SingleFileFile})
+	assert.Equal(t, filepath.Join("/out", "Frank Herbert", "Dune.m4b"),
+		org.calculateSingleFileTargetPath(filepath.Join(base, "loose", "Dune.m4b"), metadata))
+	assert.Equal(t, filepath.Join("/out", "Frank Herbert", "Dune.mp3"),
+		org.calculateSingleFileTargetPath(filepath.Join(base, "single", "Emma.mp3"), metadata))
+	assert.Equal(t, filepath.Join("/out", "Frank Herbert", "Dune", "01.mp3"),
+		org.calculateSingleFileTargetPath(filepath.Join(base, "album", "01.mp3"), metadata),
+		"a loose track of several keeps the folder")
+
+	org = newOrganizer(OrganizerConfig{Layout: "author-only", SingleFileLayout: SingleFileFile})
+	assert.Equal(t, filepath.Join("/out", "Frank Herbert", "Dune.m4b"),
+		org.calculateSingleFileTargetPath(filepath.Join(base, "loose", "Dune.m4b"), metadata),
+		"author-only has no book folder to replace")
+
+	org = newOrganizer(OrganizerConfig{Layout: "author-title"})
+	assert.Equal(t, filepath.Join("/out", "Frank Herbert", "Dune", "Dune.m4b"),
+		org.calculateSingleFileTargetPath(filepath.Join(base, "loose", "Dune.m4b"), metadata))
+}
@@ -0,0 +1,158 @@
+package organizer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SkipListFileName is the list of never-organize paths kept in the input directory
+const SkipListFileName = ".abook-org-skip"
+
+// SkipList holds the directories and files below an input directory that must never
+// be organized, such as rips in progress or DRM backups. Entries are stored relative
+// to the input directory so the list keeps working when the library is mounted
+// elsewhere.
+type SkipList struct {
+	root    string
+	entries []string // Relative, slash-separated, sorted
+}
+
+// LoadSkipList reads the skip list of the input directory root. A missing file is an
+// empty list.
+func LoadSkipList(root string) (*SkipList, error) {
+	list := &SkipList{root: root}
+	file, err := os.Open(list.path())
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening skip list: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if entry, ok := list.entry(line); ok {
+			list.entries = append(list.entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading skip list: %v", err)
+	}
+	sort.Strings(list.entries)
+	return list, nil
+}
+
+func (l *SkipList) path() string {
+	return filepath.Join(l.root, SkipListFileName)
+}
+
+// entry converts a path, absolute or relative to the input directory, to its stored
+// form. Paths outside the input directory are rejected.
+func (l *SkipList) entry(path string) (string, bool) {
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return "", false
+		}
+		path = rel
+	}
+	path = filepath.Clean(path)
+	if path == "." || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(path), true
+}
+
+// Entries returns the skipped paths relative to the input directory
+func (l *SkipList) Entries() []string {
+	return append([]string(nil), l.entries...)
+}
+
+// Len returns the number of entries
+func (l *SkipList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.entries)
+}
+
+// Contains reports whether path, as found by walking the input directory, is a listed
+// entry or lies below one
+func (l *SkipList) Contains(path string) bool {
+	if l.Len() == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(l.root, path)
+	if err != nil {
+		return false
+	}
+	entry := filepath.ToSlash(rel)
+	for _, skipped := range l.entries {
+		if entry == skipped || strings.HasPrefix(entry, skipped+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Add lists path, which must be inside the input directory. It reports whether the
+// list changed.
+func (l *SkipList) Add(path string) (bool, error) {
+	entry, ok := l.entry(path)
+	if !ok {
+		return false, fmt.Errorf("%s is not inside %s", path, l.root)
+	}
+	for _, skipped := range l.entries {
+		if skipped == entry {
+			return false, nil
+		}
+	}
+	l.entries = append(l.entries, entry)
+	sort.Strings(l.entries)
+	return true, nil
+}
+
+// Remove unlists path and reports whether it was listed
+func (l *SkipList) Remove(path string) bool {
+	entry, ok := l.entry(path)
+	if !ok {
+		return false
+	}
+	for i, skipped := range l.entries {
+		if skipped == entry {
+			l.entries = append(l.entries[:i], l.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the list back to the input directory, removing the file when the list
+// is empty
+func (l *SkipList) Save() error {
+	if len(l.entries) == 0 {
+		if err := os.Remove(l.path()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing skip list: %v", err)
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("# Paths the audiobook organizer never touches, relative to this directory\n")
+	for _, entry := range l.entries {
+		b.WriteString(entry + "\n")
+	}
+	if err := os.WriteFile(l.path(), []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("error writing skip list: %v", err)
+	}
+	return nil
+}
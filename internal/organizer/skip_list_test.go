@@ -0,0 +1,142 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipList(t *testing.T) {
+	root := t.TempDir()
+
+	list, err := LoadSkipList(root)
+	require.NoError(t, err)
+	assert.Equal(t, 0, list.Len(), "a missing file is an empty list")
+
+	added, err := list.Add(filepath.Join(root, "Incoming", "Rip"))
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = list.Add("Incoming/Rip/")
+	require.NoError(t, err)
+	assert.False(t, added, "relative and absolute forms are the same entry")
+	_, err = list.Add("DRM")
+	require.NoError(t, err)
+
+	_, err = list.Add(filepath.Join(filepath.Dir(root), "elsewhere"))
+	assert.Error(t, err, "paths outside the input directory are rejected")
+	_, err = list.Add("../elsewhere")
+	assert.Error(t, err)
+
+	require.NoError(t, list.Save())
+	loaded, err := LoadSkipList(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DRM", "Incoming/Rip"}, loaded.Entries())
+
+	assert.True(t, loaded.Contains(filepath.Join(root, "Incoming", "Rip")))
+	assert.True(t, loaded.Contains(filepath.Join(root, "Incoming", "Rip", "01.mp3")))
+	assert.False(t, loaded.Contains(filepath.Join(root, "Incoming", "Ripper")))
+	assert.False(t, loaded.Contains(filepath.Join(root, "Incoming")))
+
+	assert.True(t, loaded.Remove("DRM"))
+	assert.False(t, loaded.Remove("DRM"))
+	assert.True(t, loaded.Remove(filepath.Join(root, "Incoming", "Rip")))
+	require.NoError(t, loaded.Save())
+	assert.NoFileExists(t, filepath.Join(root, SkipListFileName), "an empty list removes the file")
+}
+
+func TestScannerHonorsSkipList(t *testing.T) {
+	root := t.TempDir()
+	createBookDir(t, root, "Keep", "Keep", "A")
+	createBookDir(t, root, "Rip", "Rip", "A")
+	createBookDir(t, root, filepath.Join("Nested", "DRM"), "DRM", "A")
+
+	list, err := LoadSkipList(root)
+	require.NoError(t, err)
+	_, err = list.Add("Rip")
+	require.NoError(t, err)
+	_, err = list.Add("Nested")
+	require.NoError(t, err)
+	require.NoError(t, list.Save())
+
+	titles := func(result *ScanResult) []string {
+		var titles []string
+		for _, book := range result.Books {
+			titles = append(titles, book.Metadata.Title)
+		}
+		return titles
+	}
+
+	t.Run("hierarchical", func(t *testing.T) {
+		scanner := NewScanner(ScanOptions{})
+		result, err := scanner.Scan(root)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Keep"}, titles(result))
+		assert.ElementsMatch(t, []string{filepath.Join(root, "Rip"), filepath.Join(root, "Nested")}, result.Skipped)
+		assert.Equal(t, 2, scanner.Progress().SkipListed)
+	})
+
+	t.Run("indexed", func(t *testing.T) {
+		indexPath := filepath.Join(t.TempDir(), ScanIndexFileName)
+		for i := 0; i < 2; i++ {
+			index := LoadScanIndex(indexPath, "test")
+			result, err := NewScanner(ScanOptions{Index: index}).Scan(root)
+			require.NoError(t, err)
+			assert.Len(t, result.Skipped, 2)
+			require.NoError(t, index.Save())
+			if i == 0 {
+				assert.Equal(t, []string{"Keep"}, titles(result))
+			}
+		}
+
+		// Unlisting a directory brings it back even though the index has seen the tree
+		require.NoError(t, os.Remove(filepath.Join(root, SkipListFileName)))
+		index := LoadScanIndex(indexPath, "test")
+		result, err := NewScanner(ScanOptions{Index: index}).Scan(root)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Rip", "DRM"}, titles(result))
+		require.NoError(t, list.Save())
+	})
+
+	t.Run("flat files", func(t *testing.T) {
+		flatRoot := t.TempDir()
+		for _, name := range []string{"keep.mp3", "partial.mp3"} {
+			require.NoError(t, os.WriteFile(filepath.Join(flatRoot, name), []byte("audio"), 0o644))
+		}
+		flatList, err := LoadSkipList(flatRoot)
+		require.NoError(t, err)
+		_, err = flatList.Add("partial.mp3")
+		require.NoError(t, err)
+		require.NoError(t, flatList.Save())
+
+		result, err := NewScanner(ScanOptions{Flat: true, FallbackToFilename: true, SkipUnreadable: true}).Scan(flatRoot)
+		require.NoError(t, err)
+		require.Len(t, result.Books, 1)
+		assert.Equal(t, filepath.Join(flatRoot, "keep.mp3"), result.Books[0].Path)
+		assert.Equal(t, []string{filepath.Join(flatRoot, "partial.mp3")}, result.Skipped)
+	})
+}
+
+func TestOrganizerReportsSkipListedBooks(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	createBookDir(t, baseDir, "Keep", "Keep", "A")
+	createBookDir(t, baseDir, "Rip", "Rip", "A")
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, SkipListFileName), []byte("# test\nRip\n"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:   baseDir,
+		OutputDir: outputDir,
+		Layout:    "author-title",
+	})
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	assert.FileExists(t, filepath.Join(outputDir, "A", "Keep", "audio.mp3"))
+	assert.FileExists(t, filepath.Join(baseDir, "Rip", "audio.mp3"))
+	assert.Len(t, org.GetSummary().SkipListed, 1)
+}
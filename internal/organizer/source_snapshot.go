@@ -0,0 +1,114 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sourceSnapshot records the size and modification time of every file of a book when
+// the scanner read it, so a book a downloader touches later in a long run is caught
+// before its files are moved
+type sourceSnapshot map[string]stabilityFile
+
+// takeSourceSnapshot records the files of the book at path: every file below a book
+// directory, or the file itself in flat mode. A missing path gives an empty snapshot.
+func takeSourceSnapshot(path string) (sourceSnapshot, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return sourceSnapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return sourceSnapshot{path: {path: path, size: info.Size(), modTime: info.ModTime()}}, nil
+	}
+
+	files, err := stabilityFiles(path, true)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(sourceSnapshot, len(files))
+	for _, file := range files {
+		snapshot[file.path] = file
+	}
+	return snapshot, nil
+}
+
+// change describes the first difference between the snapshot and current, such as
+// "added Track 12.mp3", or returns "" when the files are the same
+func (s sourceSnapshot) change(current sourceSnapshot, root string) string {
+	name := func(path string) string {
+		if rel, err := filepath.Rel(root, path); err == nil && rel != "." {
+			return rel
+		}
+		return filepath.Base(path)
+	}
+
+	for _, path := range sortedSnapshotPaths(s) {
+		now, ok := current[path]
+		before := s[path]
+		switch {
+		case !ok:
+			return "removed " + name(path)
+		case now.size != before.size:
+			return fmt.Sprintf("%s changed size from %d to %d bytes", name(path), before.size, now.size)
+		case !now.modTime.Equal(before.modTime):
+			return name(path) + " was modified"
+		}
+	}
+	for _, path := range sortedSnapshotPaths(current) {
+		if _, ok := s[path]; !ok {
+			return "added " + name(path)
+		}
+	}
+	return ""
+}
+
+func sortedSnapshotPaths(s sourceSnapshot) []string {
+	paths := make([]string, 0, len(s))
+	for path := range s {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// rememberSnapshot keeps the snapshot the scanner took of a book until it is moved
+func (o *Organizer) rememberSnapshot(book Book) {
+	if book.snapshot == nil {
+		return
+	}
+	if o.snapshots == nil {
+		o.snapshots = make(map[string]sourceSnapshot)
+	}
+	o.snapshots[book.Path] = book.snapshot
+}
+
+// sourceChanged reports whether the files of the book at path changed since the
+// scanner read it. A changed book is deferred to a later run instead of being moved
+// half written; books organized without a scan are never reported as changed.
+func (o *Organizer) sourceChanged(path string) bool {
+	snapshot, ok := o.snapshots[path]
+	if !ok {
+		return false
+	}
+	current, err := takeSourceSnapshot(path)
+	if err != nil {
+		return false
+	}
+	change := snapshot.change(current, path)
+	if change == "" {
+		return false
+	}
+
+	PrintYellow("⏳ Skipping %s: it changed during the run (%s)", path, change)
+	o.scanIndex.Invalidate(path)
+	o.summary.Deferred = append(o.summary.Deferred, Deferral{
+		Path:   path,
+		Reason: "changed during the run: " + change,
+	})
+	return true
+}
@@ -0,0 +1,80 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceSnapshotChange(t *testing.T) {
+	dir := t.TempDir()
+	writeDisc(t, dir, `{"title":"Dune","authors":["Frank Herbert"]}`, "01.mp3", "02.mp3")
+	before, err := takeSourceSnapshot(dir)
+	require.NoError(t, err)
+
+	current, err := takeSourceSnapshot(dir)
+	require.NoError(t, err)
+	assert.Empty(t, before.change(current, dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "03.mp3"), []byte("new"), 0o644))
+	current, err = takeSourceSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "added 03.mp3", before.change(current, dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "02.mp3"), []byte("02.mp3 grown"), 0o644))
+	current, err = takeSourceSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "02.mp3 changed size from 6 to 12 bytes", before.change(current, dir))
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "01.mp3")))
+	current, err = takeSourceSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "removed 01.mp3", before.change(current, dir))
+
+	file := filepath.Join(dir, "cover.jpg")
+	single, err := takeSourceSnapshot(file)
+	require.NoError(t, err)
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(file, later, later))
+	current, err = takeSourceSnapshot(file)
+	require.NoError(t, err)
+	assert.Equal(t, "cover.jpg was modified", single.change(current, file))
+}
+
+func TestOrganizeDefersBooksChangedDuringRun(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Dune"), `{"title":"Dune","authors":["Frank Herbert"]}`, "01.mp3")
+	writeDisc(t, filepath.Join(base, "Emma"), `{"title":"Emma","authors":["Jane Austen"]}`, "01.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out})
+	require.NoError(t, err)
+
+	// The downloader adds a track to Dune after the scanner read it
+	scanner := NewScanner(ScanOptionsFromConfig(&org.config))
+	CaptureOutput(func() {
+		require.NoError(t, scanner.Walk(base, ScanHandler{
+			Book: func(book Book) error {
+				if filepath.Base(book.Path) == "Dune" {
+					require.NoError(t, os.WriteFile(filepath.Join(book.Path, "02.mp3"), []byte("02"), 0o644))
+				}
+				return org.organizeScannedBook(book)
+			},
+		}))
+	})
+
+	assert.DirExists(t, filepath.Join(base, "Dune"), "a changed book stays where it is")
+	assert.NoDirExists(t, filepath.Join(out, "Frank Herbert"))
+	assert.DirExists(t, filepath.Join(out, "Jane Austen", "Emma"))
+	require.Len(t, org.summary.Deferred, 1)
+	assert.Equal(t, Deferral{
+		Path:   filepath.Join(base, "Dune"),
+		Reason: "changed during the run: added 02.mp3",
+	}, org.summary.Deferred[0])
+}
@@ -0,0 +1,129 @@
+package organizer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// partialDownloadExtensions are the suffixes download clients and browsers give files
+// they haven't finished writing. A book holding one is never organized.
+var partialDownloadExtensions = map[string]bool{
+	".part":       true, // Firefox, wget, yt-dlp, Transmission
+	".partial":    true,
+	".!qb":        true, // qBittorrent
+	".crdownload": true, // Chrome
+}
+
+// sizeCheckHorizon bounds which books get the SizeSettle check. Writing a file updates
+// its modification time, so a book untouched for this long cannot still be growing and
+// is not worth the wait.
+const sizeCheckHorizon = 10 * time.Minute
+
+// IsPartialDownload reports whether path is a file a download client is still writing
+func IsPartialDownload(path string) bool {
+	return partialDownloadExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// stabilityCheck decides whether a book is complete enough to organize
+type stabilityCheck struct {
+	minFileAge time.Duration // Files modified more recently than this defer their book
+	sizeSettle time.Duration // Wait this long and defer the book if its size changed
+	now        func() time.Time
+}
+
+// unstable returns why the files in dir are not ready to be organized, or "" when they
+// are. With recursive false only the files directly in dir are checked.
+func (c stabilityCheck) unstable(dir string, recursive bool) (string, error) {
+	files, err := stabilityFiles(dir, recursive)
+	if err != nil {
+		return "", err
+	}
+
+	var newest time.Time
+	var size int64
+	for _, file := range files {
+		if IsPartialDownload(file.path) {
+			return fmt.Sprintf("partial download %s", filepath.Base(file.path)), nil
+		}
+		if file.modTime.After(newest) {
+			newest = file.modTime
+		}
+		size += file.size
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	age := c.now().Sub(newest)
+	if c.minFileAge > 0 && age < c.minFileAge {
+		return fmt.Sprintf("modified %s ago", age.Round(time.Second)), nil
+	}
+	if c.sizeSettle <= 0 || age >= sizeCheckHorizon {
+		return "", nil
+	}
+
+	time.Sleep(c.sizeSettle)
+	files, err = stabilityFiles(dir, recursive)
+	if err != nil {
+		return "", err
+	}
+	var settled int64
+	for _, file := range files {
+		settled += file.size
+	}
+	if settled != size {
+		return fmt.Sprintf("size changed from %d to %d bytes", size, settled), nil
+	}
+	return "", nil
+}
+
+type stabilityFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// stabilityFiles lists the regular files in dir, descending into subdirectories
+// (disc folders and the like) when recursive is set
+func stabilityFiles(dir string, recursive bool) ([]stabilityFile, error) {
+	var files []stabilityFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		// The organizer's own logs, index, and staging directories say nothing about
+		// whether a download has finished
+		if path != dir && strings.HasPrefix(entry.Name(), ".abook-") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		files = append(files, stabilityFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
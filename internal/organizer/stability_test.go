@@ -0,0 +1,130 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ageFiles sets the modification time of every file below dir to age ago
+func ageFiles(t *testing.T, dir string, age time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-age)
+	require.NoError(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, old, old)
+	}))
+}
+
+func TestIsPartialDownload(t *testing.T) {
+	assert.True(t, IsPartialDownload("/dl/book.m4b.part"))
+	assert.True(t, IsPartialDownload("/dl/01.mp3.!qB"))
+	assert.True(t, IsPartialDownload("/dl/book.epub.crdownload"))
+	assert.False(t, IsPartialDownload("/dl/book.m4b"))
+	assert.False(t, IsPartialDownload("/dl/party.mp3"))
+}
+
+func TestScannerDefersBooksStillBeingWritten(t *testing.T) {
+	baseDir := t.TempDir()
+	done := createBookDir(t, baseDir, "Done", "Done", "Author")
+	downloading := createBookDir(t, baseDir, "Downloading", "Downloading", "Author")
+	fresh := createBookDir(t, baseDir, "Fresh", "Fresh", "Author")
+	require.NoError(t, os.MkdirAll(filepath.Join(downloading, "CD2"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloading, "CD2", "02.mp3.part"), []byte("half"), 0o644))
+	ageFiles(t, done, time.Hour)
+	ageFiles(t, downloading, time.Hour)
+	// The organizer's own files never make a book look unfinished
+	require.NoError(t, os.WriteFile(filepath.Join(done, LogFileName), []byte("{}"), 0o644))
+
+	var progress ScanProgress
+	result, err := NewScanner(ScanOptions{
+		MinFileAge: time.Minute,
+		Progress:   func(p ScanProgress) { progress = p },
+	}).Scan(baseDir)
+	require.NoError(t, err)
+
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, done, result.Books[0].Path)
+	require.Len(t, result.Deferred, 2)
+	assert.Equal(t, downloading, result.Deferred[0].Path)
+	assert.Contains(t, result.Deferred[0].Reason, "02.mp3.part")
+	assert.Equal(t, fresh, result.Deferred[1].Path)
+	assert.Contains(t, result.Deferred[1].Reason, "modified")
+	assert.Equal(t, 2, progress.Deferred)
+	assert.NotContains(t, result.Unmatched, filepath.Join(downloading, "CD2"), "a deferred book is not descended into")
+
+	result, err = NewScanner(ScanOptions{}).Scan(baseDir)
+	require.NoError(t, err)
+	assert.Len(t, result.Books, 2, "recent files are only deferred with MinFileAge")
+	require.Len(t, result.Deferred, 1, "partial downloads are always deferred")
+}
+
+func TestScannerDefersFlatDirectoriesStillBeingWritten(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFlatFiles(t, filepath.Join(baseDir, "done"), 2)
+	writeFlatFiles(t, filepath.Join(baseDir, "album"), 2)
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "album", "file00002.mp3.!qB"), []byte("half"), 0o644))
+	writeFlatFiles(t, filepath.Join(baseDir, "album", "bonus"), 1)
+
+	result, err := NewScanner(ScanOptions{Flat: true, FallbackToFilename: true}).Scan(baseDir)
+	require.NoError(t, err)
+
+	require.Len(t, result.Deferred, 1)
+	assert.Equal(t, filepath.Join(baseDir, "album"), result.Deferred[0].Path)
+	var paths []string
+	for _, book := range result.Books {
+		paths = append(paths, book.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		filepath.Join(baseDir, "done", "file00000.mp3"),
+		filepath.Join(baseDir, "done", "file00001.mp3"),
+		filepath.Join(baseDir, "album", "bonus", "file00000.mp3"),
+	}, paths, "only the unfinished directory itself is held back")
+}
+
+func TestStabilityCheckSizeSettle(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "book.m4b")
+	require.NoError(t, os.WriteFile(file, []byte("start"), 0o644))
+
+	check := stabilityCheck{sizeSettle: 50 * time.Millisecond, now: time.Now}
+	reason, err := check.unstable(dir, true)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "an unchanged size is stable")
+
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+			if err == nil {
+				f.WriteString("more")
+				f.Close()
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	reason, err = check.unstable(dir, true)
+	close(stop)
+	<-stopped
+	require.NoError(t, err)
+	assert.Contains(t, reason, "size changed")
+
+	ageFiles(t, dir, time.Hour)
+	reason, err = check.unstable(dir, true)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "books untouched for a while are not re-measured")
+}
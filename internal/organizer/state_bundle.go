@@ -0,0 +1,363 @@
+package organizer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stateBundleVersion is the format of the bundles ExportState writes
+const stateBundleVersion = 1
+
+// stateManifestName is the manifest entry of a state bundle
+const stateManifestName = "manifest.json"
+
+// Kinds of file in a state bundle
+const (
+	StateConfig      = "config"       // The config file, with author aliases and other settings
+	StateAuthorCache = "author-cache" // Answers of the author lookup
+	StateSkipList    = "skip-list"    // Paths of the input directory that are never organized
+	StateUndoLog     = "undo-log"     // Moves of the last run, for undo and --diff-log
+)
+
+// StateManifest describes a state bundle: where it was exported from and its files
+type StateManifest struct {
+	Version   int         `json:"version"`
+	Created   time.Time   `json:"created"`
+	InputDir  string      `json:"input_dir,omitempty"`
+	OutputDir string      `json:"output_dir,omitempty"`
+	Files     []StateFile `json:"files"`
+}
+
+// StateFile is one file of a state bundle
+type StateFile struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"` // Entry name in the bundle
+	Path string `json:"path"` // Where it was exported from, or imported to
+}
+
+// StateLocations says where the curation state of a library is kept. Empty paths
+// use the defaults: the author lookup cache in the user cache directory and the
+// DefaultLogPath of the output directory, or of the input directory without one.
+// An empty ConfigFile leaves the config file out.
+type StateLocations struct {
+	InputDir    string
+	OutputDir   string
+	ConfigFile  string
+	AuthorCache string
+	LogPath     string
+}
+
+// StateImport reports what ImportState did with one file of a bundle
+type StateImport struct {
+	StateFile
+	Action string // "written", "merged", or why the file was skipped
+}
+
+// resolve fills in the default paths of locations
+func (l StateLocations) resolve() (StateLocations, error) {
+	var err error
+	if l.InputDir != "" {
+		if l.InputDir, err = filepath.Abs(l.InputDir); err != nil {
+			return l, err
+		}
+	}
+	if l.OutputDir != "" {
+		if l.OutputDir, err = filepath.Abs(l.OutputDir); err != nil {
+			return l, err
+		}
+	}
+	if l.AuthorCache == "" {
+		if l.AuthorCache, err = DefaultAuthorLookupCachePath(); err != nil {
+			return l, err
+		}
+	}
+	if l.LogPath == "" {
+		if logBase := l.libraryDir(); logBase != "" {
+			l.LogPath = DefaultLogPath(logBase)
+		}
+	}
+	return l, nil
+}
+
+// libraryDir is the directory organized books are written to
+func (l StateLocations) libraryDir() string {
+	if l.OutputDir != "" {
+		return l.OutputDir
+	}
+	return l.InputDir
+}
+
+// ExportState writes the config file, author lookup cache, skip list, and undo log
+// found at locations to w as a gzipped tar bundle. Missing files are left out.
+func ExportState(w io.Writer, locations StateLocations) (StateManifest, error) {
+	locations, err := locations.resolve()
+	if err != nil {
+		return StateManifest{}, err
+	}
+	manifest := StateManifest{
+		Version:   stateBundleVersion,
+		Created:   time.Now().UTC(),
+		InputDir:  locations.InputDir,
+		OutputDir: locations.OutputDir,
+	}
+
+	candidates := []StateFile{
+		{Kind: StateConfig, Name: "config/" + filepath.Base(locations.ConfigFile), Path: locations.ConfigFile},
+		{Kind: StateAuthorCache, Name: "cache/" + AuthorLookupCacheName, Path: locations.AuthorCache},
+		{Kind: StateUndoLog, Name: "library/" + LogFileName, Path: locations.LogPath},
+	}
+	if locations.InputDir != "" {
+		candidates = append(candidates, StateFile{
+			Kind: StateSkipList,
+			Name: "library/" + SkipListFileName,
+			Path: filepath.Join(locations.InputDir, SkipListFileName),
+		})
+	}
+
+	contents := make(map[string][]byte)
+	for _, file := range candidates {
+		if file.Path == "" {
+			continue
+		}
+		data, err := os.ReadFile(file.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return manifest, fmt.Errorf("error reading %s: %w", file.Path, err)
+		}
+		manifest.Files = append(manifest.Files, file)
+		contents[file.Name] = data
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, err
+	}
+	if err := writeTarEntry(archive, stateManifestName, manifestData, manifest.Created); err != nil {
+		return manifest, err
+	}
+	for _, file := range manifest.Files {
+		if err := writeTarEntry(archive, file.Name, contents[file.Name], manifest.Created); err != nil {
+			return manifest, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, err
+	}
+	return manifest, gz.Close()
+}
+
+// writeTarEntry adds a regular file to a tar archive
+func writeTarEntry(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := archive.Write(data)
+	return err
+}
+
+// ReadStateBundle reads the manifest and files of a bundle written by ExportState
+func ReadStateBundle(r io.Reader) (StateManifest, map[string][]byte, error) {
+	var manifest StateManifest
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("not a state bundle: %w", err)
+	}
+	defer gz.Close()
+
+	contents := make(map[string][]byte)
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, fmt.Errorf("error reading state bundle: %w", err)
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return manifest, nil, fmt.Errorf("error reading %s from state bundle: %w", header.Name, err)
+		}
+		contents[header.Name] = data
+	}
+
+	data, ok := contents[stateManifestName]
+	if !ok {
+		return manifest, nil, fmt.Errorf("not a state bundle: %s is missing", stateManifestName)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, nil, fmt.Errorf("error reading state bundle manifest: %w", err)
+	}
+	if manifest.Version > stateBundleVersion {
+		return manifest, nil, fmt.Errorf("state bundle version %d is newer than this version supports (%d)", manifest.Version, stateBundleVersion)
+	}
+	return manifest, contents, nil
+}
+
+// ImportState restores a bundle written by ExportState to locations. The author
+// lookup cache and skip list are merged with what is already there. The config
+// file and undo log replace existing ones only with overwrite; paths in the undo log
+// are moved from the exported input and output directories to those of locations.
+// A config file is only imported when locations names one.
+func ImportState(r io.Reader, locations StateLocations, overwrite bool) ([]StateImport, error) {
+	manifest, contents, err := ReadStateBundle(r)
+	if err != nil {
+		return nil, err
+	}
+	locations, err = locations.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []StateImport
+	for _, file := range manifest.Files {
+		data, ok := contents[file.Name]
+		if !ok {
+			return results, fmt.Errorf("state bundle is missing %s", file.Name)
+		}
+		result := StateImport{StateFile: file}
+		switch file.Kind {
+		case StateConfig:
+			result.Path = locations.ConfigFile
+			result.Action, err = importStateFile(result.Path, data, overwrite)
+		case StateAuthorCache:
+			result.Path = locations.AuthorCache
+			result.Action, err = mergeAuthorCache(result.Path, data)
+		case StateSkipList:
+			result.Path = filepath.Join(locations.InputDir, SkipListFileName)
+			if locations.InputDir == "" {
+				result.Path, result.Action = "", "skipped: no input directory given"
+				break
+			}
+			result.Action, err = mergeSkipList(locations.InputDir, data)
+		case StateUndoLog:
+			result.Path = locations.LogPath
+			var entries []LogEntry
+			if err = json.Unmarshal(data, &entries); err != nil {
+				err = fmt.Errorf("error reading %s: %w", file.Name, err)
+				break
+			}
+			rebaseLogEntries(entries, manifest, locations)
+			if data, err = json.MarshalIndent(entries, "", "  "); err == nil {
+				result.Action, err = importStateFile(result.Path, data, overwrite)
+			}
+		default:
+			result.Path, result.Action = "", "skipped: unknown kind "+file.Kind
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// importStateFile writes data to path unless a file is there and overwrite is off
+func importStateFile(path string, data []byte, overwrite bool) (string, error) {
+	if path == "" {
+		return "skipped: no destination given", nil
+	}
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "skipped: already exists (use --force to replace it)", nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return "written", nil
+}
+
+// mergeAuthorCache adds the names of an exported author lookup cache that the cache
+// at path doesn't know yet
+func mergeAuthorCache(path string, data []byte) (string, error) {
+	var imported, existing map[string]json.RawMessage
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return "", fmt.Errorf("error reading exported author cache: %w", err)
+	}
+	current, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return importStateFile(path, data, false)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading author cache: %w", err)
+	}
+	if err := json.Unmarshal(current, &existing); err != nil {
+		return "", fmt.Errorf("error reading author cache %s: %w", path, err)
+	}
+	for name, records := range imported {
+		if _, ok := existing[name]; !ok {
+			existing[name] = records
+		}
+	}
+	merged, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if _, err := importStateFile(path, merged, true); err != nil {
+		return "", err
+	}
+	return "merged", nil
+}
+
+// mergeSkipList adds the entries of an exported skip list to the skip list of root
+func mergeSkipList(root string, data []byte) (string, error) {
+	list, err := LoadSkipList(root)
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Entries are relative, so they apply to the library wherever it is mounted
+		if _, err := list.Add(filepath.FromSlash(line)); err != nil {
+			return "", err
+		}
+	}
+	if err := list.Save(); err != nil {
+		return "", err
+	}
+	return "merged", nil
+}
+
+// rebaseLogEntries moves the paths of undo log entries from the input and output
+// directories of the export to those of locations
+func rebaseLogEntries(entries []LogEntry, manifest StateManifest, locations StateLocations) {
+	rebase := func(path string) string {
+		for _, dirs := range [][2]string{
+			{manifest.OutputDir, locations.OutputDir},
+			{manifest.InputDir, locations.InputDir},
+		} {
+			if dirs[0] == "" || dirs[1] == "" {
+				continue
+			}
+			if rel, err := filepath.Rel(dirs[0], path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return filepath.Join(dirs[1], rel)
+			}
+		}
+		return path
+	}
+	for i := range entries {
+		entries[i].SourcePath = rebase(entries[i].SourcePath)
+		entries[i].TargetPath = rebase(entries[i].TargetPath)
+	}
+}
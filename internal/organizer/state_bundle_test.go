@@ -0,0 +1,95 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportState(t *testing.T) {
+	laptop := t.TempDir()
+	from := StateLocations{
+		InputDir:    filepath.Join(laptop, "downloads"),
+		OutputDir:   filepath.Join(laptop, "audiobooks"),
+		ConfigFile:  filepath.Join(laptop, ".audiobook-organizer.yaml"),
+		AuthorCache: filepath.Join(laptop, "cache", AuthorLookupCacheName),
+	}
+	for _, dir := range []string{from.InputDir, from.OutputDir, filepath.Dir(from.AuthorCache)} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+	require.NoError(t, os.WriteFile(from.ConfigFile, []byte("author-alias:\n  - Richard Bachman=Stephen King\n"), 0o644))
+	require.NoError(t, os.WriteFile(from.AuthorCache, []byte(`{"stephen king": [], "le guin": []}`), 0o644))
+	skip, err := LoadSkipList(from.InputDir)
+	require.NoError(t, err)
+	_, err = skip.Add("Incoming/Rip")
+	require.NoError(t, err)
+	require.NoError(t, skip.Save())
+	logData := `[{"timestamp": "2024-05-01T10:00:00Z", "source_path": "` + filepath.Join(from.InputDir, "Dune") +
+		`", "target_path": "` + filepath.Join(from.OutputDir, "Frank Herbert", "Dune") + `", "files": []}]`
+	require.NoError(t, os.WriteFile(filepath.Join(from.OutputDir, LogFileName), []byte(logData), 0o644))
+
+	var bundle bytes.Buffer
+	manifest, err := ExportState(&bundle, from)
+	require.NoError(t, err)
+	assert.Len(t, manifest.Files, 4)
+
+	nas := t.TempDir()
+	to := StateLocations{
+		InputDir:    filepath.Join(nas, "downloads"),
+		OutputDir:   filepath.Join(nas, "audiobooks"),
+		ConfigFile:  filepath.Join(nas, ".audiobook-organizer.yaml"),
+		AuthorCache: filepath.Join(nas, "cache", AuthorLookupCacheName),
+	}
+	require.NoError(t, os.MkdirAll(to.InputDir, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(to.AuthorCache), 0o755))
+	require.NoError(t, os.WriteFile(to.ConfigFile, []byte("verbose: true\n"), 0o644))
+	require.NoError(t, os.WriteFile(to.AuthorCache, []byte(`{"stephen king": [{"name": "Stephen King"}]}`), 0o644))
+
+	results, err := ImportState(bytes.NewReader(bundle.Bytes()), to, false)
+	require.NoError(t, err)
+	actions := make(map[string]string)
+	for _, result := range results {
+		actions[result.Kind] = result.Action
+	}
+	assert.Equal(t, map[string]string{
+		StateConfig:      "skipped: already exists (use --force to replace it)",
+		StateAuthorCache: "merged",
+		StateSkipList:    "merged",
+		StateUndoLog:     "written",
+	}, actions)
+
+	config, err := os.ReadFile(to.ConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, "verbose: true\n", string(config), "the config is only replaced with overwrite")
+
+	cache, err := os.ReadFile(to.AuthorCache)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"stephen king": [{"name": "Stephen King"}], "le guin": []}`, string(cache))
+
+	skip, err = LoadSkipList(to.InputDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Incoming/Rip"}, skip.Entries())
+
+	entries, err := ReadLogEntries(filepath.Join(to.OutputDir, LogFileName))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Join(to.InputDir, "Dune"), entries[0].SourcePath)
+	assert.Equal(t, filepath.Join(to.OutputDir, "Frank Herbert", "Dune"), entries[0].TargetPath)
+
+	_, err = ImportState(bytes.NewReader(bundle.Bytes()), to, true)
+	require.NoError(t, err)
+	config, err = os.ReadFile(to.ConfigFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "Richard Bachman=Stephen King")
+}
+
+func TestReadStateBundleRejectsOtherFiles(t *testing.T) {
+	_, _, err := ReadStateBundle(bytes.NewReader([]byte("not a bundle")))
+	assert.ErrorContains(t, err, "not a state bundle")
+}
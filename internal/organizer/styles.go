@@ -2,6 +2,8 @@ package organizer
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -143,7 +145,7 @@ func PrintSuccess(format string, a ...interface{}) {
 
 // PrintError prints text with Error style (red)
 func PrintError(format string, a ...interface{}) {
-	printStyled(Styles.Error, format, a...)
+	printErrorStyled(Styles.Error, format, a...)
 }
 
 // PrintWarning prints text with Warning style (yellow)
@@ -153,11 +155,27 @@ func PrintWarning(format string, a ...interface{}) {
 
 // Helper function to print styled text
 func printStyled(style lipgloss.Style, format string, a ...interface{}) {
-	if len(a) == 0 {
-		fmt.Println(style.Render(format))
-	} else {
-		fmt.Println(style.Render(fmt.Sprintf(format, a...)))
+	text := format
+	if len(a) > 0 {
+		text = fmt.Sprintf(format, a...)
 	}
+	if QuietMode {
+		return
+	}
+	fmt.Fprintln(output, style.Render(text))
+}
+
+// printErrorStyled prints error text; in quiet mode it is written undecorated to stderr
+func printErrorStyled(style lipgloss.Style, format string, a ...interface{}) {
+	text := format
+	if len(a) > 0 {
+		text = fmt.Sprintf(format, a...)
+	}
+	if QuietMode {
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(StripDecorations(text)))
+		return
+	}
+	fmt.Fprintln(output, style.Render(text))
 }
 
 // Metadata-specific styling functions
@@ -0,0 +1,42 @@
+package organizer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SummaryMode decides how much of the end-of-run summary is printed. The JSON and
+// HTML reports always hold everything.
+type SummaryMode string
+
+const (
+	// SummaryFull lists every book found and every move (the default)
+	SummaryFull SummaryMode = "full"
+	// SummaryCompact prints counts, listing only books and folders that need attention
+	SummaryCompact SummaryMode = "compact"
+	// SummaryErrorsOnly prints only the sections that need attention
+	SummaryErrorsOnly SummaryMode = "errors-only"
+)
+
+// ParseSummaryMode parses a --summary value; "" selects SummaryFull
+func ParseSummaryMode(value string) (SummaryMode, error) {
+	switch mode := SummaryMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return SummaryFull, nil
+	case SummaryFull, SummaryCompact, SummaryErrorsOnly:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid summary mode %q (use full, compact, or errors-only)", value)
+	}
+}
+
+// listsEverything reports whether the summary lists every book found and every move
+func (m SummaryMode) listsEverything() bool {
+	return m == SummaryFull || m == ""
+}
+
+// showsCounts reports whether sections that need no attention, like moves and
+// trashed files, are printed at all
+func (m SummaryMode) showsCounts() bool {
+	return m != SummaryErrorsOnly
+}
@@ -0,0 +1,219 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TagBackupDirName is the folder below the input directory that FixTags copies files
+// into before changing their tags, unless another backup directory is given
+const TagBackupDirName = ".abook-tag-backups"
+
+// tagFixFields are the album-wide tags FixTags makes consistent, with the ID3v2
+// frame each is written to
+var tagFixFields = []struct {
+	name  string
+	frame string
+}{
+	{"album", "TALB"},
+	{"artist", "TPE1"},
+	{"album_artist", "TPE2"},
+}
+
+// TagFix is one tag of one file that differs from the rest of its album
+type TagFix struct {
+	Path  string `json:"path"`
+	Field string `json:"field"` // album, artist, or album_artist
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// TagFixGroup is an album whose tracks disagree on an album-wide tag, with the
+// changes that bring the outliers in line with the majority
+type TagFixGroup struct {
+	Dir         string   `json:"dir"`
+	Album       string   `json:"album"` // The majority album tag, or the scanner's album name
+	Tracks      int      `json:"tracks"`
+	Fixes       []TagFix `json:"fixes,omitempty"`
+	Unsupported []string `json:"unsupported,omitempty"` // Outliers in a format FixTags can't write
+}
+
+// TagFixResult is the outcome of FixTags
+type TagFixResult struct {
+	Groups    []TagFixGroup `json:"groups"`
+	Files     int           `json:"files"`                // Files changed, or that would be with DryRun
+	BackupDir string        `json:"backup_dir,omitempty"` // Where the originals were copied
+	Errors    []string      `json:"errors,omitempty"`
+}
+
+// TagFixOptions controls FixTags
+type TagFixOptions struct {
+	DryRun    bool
+	NoBackup  bool
+	BackupDir string // Defaults to TagBackupDirName in the input directory
+	Now       time.Time
+}
+
+// PlanTagFixes finds the tags of an album group that differ from the value most of
+// its tracks share. A field without a strict majority is left alone, since the
+// group may then be several books; so are tags of tracks that have no audio tags.
+func PlanTagFixes(group Group) TagFixGroup {
+	plan := TagFixGroup{Dir: group.Dir, Album: group.Name, Tracks: len(group.Books)}
+	unsupported := make(map[string]bool)
+	for _, field := range tagFixFields {
+		counts := make(map[string]int)
+		for _, book := range group.Books {
+			counts[rawTagValue(book.Metadata, field.name)]++
+		}
+		majority, count := "", 0
+		for value, n := range counts {
+			if n > count || (n == count && value < majority) {
+				majority, count = value, n
+			}
+		}
+		if majority == "" || count*2 <= len(group.Books) {
+			continue
+		}
+		if field.name == "album" {
+			plan.Album = majority
+		}
+
+		for _, book := range group.Books {
+			value := rawTagValue(book.Metadata, field.name)
+			if value == majority {
+				continue
+			}
+			if !strings.EqualFold(filepath.Ext(book.Path), ".mp3") {
+				if !unsupported[book.Path] {
+					unsupported[book.Path] = true
+					plan.Unsupported = append(plan.Unsupported, book.Path)
+				}
+				continue
+			}
+			plan.Fixes = append(plan.Fixes, TagFix{Path: book.Path, Field: field.name, From: value, To: majority})
+		}
+	}
+	sort.SliceStable(plan.Fixes, func(i, j int) bool { return plan.Fixes[i].Path < plan.Fixes[j].Path })
+	return plan
+}
+
+// rawTagValue returns a tag as read from the audio file, before any field mapping
+func rawTagValue(metadata Metadata, field string) string {
+	value, _ := metadata.RawData[field].(string)
+	return strings.TrimSpace(value)
+}
+
+// FindTagFixes scans dir for album groups, directories of tracks the flat-mode scan
+// treats as one album, and plans the fixes of those with inconsistent tags
+func FindTagFixes(dir string) ([]TagFixGroup, error) {
+	var groups []TagFixGroup
+	scanner := NewScanner(ScanOptions{Flat: true, UseEmbeddedMetadata: true, SkipUnreadable: true})
+	err := scanner.Walk(dir, ScanHandler{
+		Group: func(group Group) error {
+			if !group.Album || len(group.Books) < 2 {
+				return nil
+			}
+			if plan := PlanTagFixes(group); len(plan.Fixes) > 0 || len(plan.Unsupported) > 0 {
+				groups = append(groups, plan)
+			}
+			return nil
+		},
+	})
+	return groups, err
+}
+
+// FixTags rewrites the outlier tags of every album group below dir to match the
+// majority, copying each file into a timestamped backup folder first. With DryRun it
+// only reports what it would change.
+func FixTags(dir string, options TagFixOptions) (TagFixResult, error) {
+	groups, err := FindTagFixes(dir)
+	if err != nil {
+		return TagFixResult{}, err
+	}
+	result := TagFixResult{Groups: groups}
+
+	// All fixes of one file are written together
+	frames := make(map[string]map[string]string)
+	var paths []string
+	for _, group := range groups {
+		for _, fix := range group.Fixes {
+			if frames[fix.Path] == nil {
+				frames[fix.Path] = make(map[string]string)
+				paths = append(paths, fix.Path)
+			}
+			for _, field := range tagFixFields {
+				if field.name == fix.Field {
+					frames[fix.Path][field.frame] = fix.To
+				}
+			}
+		}
+	}
+	if options.DryRun {
+		result.Files = len(paths)
+		return result, nil
+	}
+
+	var backup *Trash
+	if !options.NoBackup && len(paths) > 0 {
+		if options.BackupDir == "" {
+			options.BackupDir = filepath.Join(dir, TagBackupDirName)
+		}
+		if options.Now.IsZero() {
+			options.Now = time.Now()
+		}
+		if backup, err = newTagBackup(options.BackupDir, options.Now); err != nil {
+			return result, err
+		}
+		result.BackupDir = backup.sessionDir
+	}
+
+	for _, path := range paths {
+		if backup != nil {
+			if err := backup.copy(path, dir); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+		}
+		if err := setID3TextFrames(path, frames[path]); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		result.Files++
+	}
+	return result, nil
+}
+
+// newTagBackup creates the backup folder of one FixTags run. The backup directory
+// holds an ignore marker, so the copies are never scanned as books.
+func newTagBackup(dir string, now time.Time) (*Trash, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating backup directory: %w", err)
+	}
+	marker := filepath.Join(dir, IgnoreMarkers[0])
+	if _, err := os.Stat(marker); os.IsNotExist(err) {
+		if err := os.WriteFile(marker, nil, 0o644); err != nil {
+			return nil, fmt.Errorf("error creating backup directory: %w", err)
+		}
+	}
+	return NewTrash(dir, now), nil
+}
+
+// copy copies path into the session folder at its place below base
+func (t *Trash) copy(path, base string) error {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = strings.TrimPrefix(path, filepath.VolumeName(path))
+	}
+	dest := filepath.Join(t.sessionDir, rel)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("error backing up %s: %w", path, err)
+	}
+	if err := copyFileContents(path, dest); err != nil {
+		return fmt.Errorf("error backing up %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTaggedMP3 writes an MP3 file with ID3v2.3 text frames in front of fake audio
+func writeTaggedMP3(t *testing.T, path string, frames map[string]string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("fake mpeg audio"), 0o644))
+	require.NoError(t, setID3TextFrames(path, frames))
+}
+
+func readTags(t *testing.T, path string) Metadata {
+	t.Helper()
+	metadata, err := NewAudioMetadataProvider(path).GetMetadata()
+	require.NoError(t, err)
+	return metadata
+}
+
+func TestSetID3TextFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.mp3")
+	writeTaggedMP3(t, path, map[string]string{"TIT2": "Chapter 1", "TALB": "Dune", "TPE1": "Frank Herbert"})
+
+	require.NoError(t, setID3TextFrames(path, map[string]string{"TALB": "Dune – Part One", "TPE2": "Herbert"}))
+	metadata := readTags(t, path)
+	assert.Equal(t, "Chapter 1", metadata.Title, "other frames are kept")
+	assert.Equal(t, "Dune – Part One", metadata.Album, "text beyond ISO-8859-1 is written as UTF-16")
+	assert.Equal(t, "Herbert", metadata.RawData["album_artist"])
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fake mpeg audio", string(data[id3v2Size(data):]), "the audio is kept")
+}
+
+func TestFixTagsRewritesOutliers(t *testing.T) {
+	dir := t.TempDir()
+	book := filepath.Join(dir, "Dune")
+	for i, album := range []string{"Dune", "Dune", "Dune (Unabridged)", "Dune"} {
+		artist := "Frank Herbert"
+		if i == 1 {
+			artist = "Herbert, Frank"
+		}
+		writeTaggedMP3(t, filepath.Join(book, "Chapter "+string(rune('1'+i))+".mp3"),
+			map[string]string{"TIT2": "Chapter " + string(rune('1'+i)), "TALB": album, "TPE1": artist})
+	}
+
+	result, err := FixTags(dir, TagFixOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, result.Groups, 1)
+	assert.Equal(t, "Dune", result.Groups[0].Album)
+	assert.Equal(t, []TagFix{
+		{Path: filepath.Join(book, "Chapter 2.mp3"), Field: "artist", From: "Herbert, Frank", To: "Frank Herbert"},
+		{Path: filepath.Join(book, "Chapter 3.mp3"), Field: "album", From: "Dune (Unabridged)", To: "Dune"},
+	}, result.Groups[0].Fixes)
+	assert.Equal(t, 2, result.Files)
+	assert.Equal(t, "Dune (Unabridged)", readTags(t, filepath.Join(book, "Chapter 3.mp3")).Album, "a dry run changes nothing")
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	result, err = FixTags(dir, TagFixOptions{Now: now})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 2, result.Files)
+	assert.Equal(t, "Dune", readTags(t, filepath.Join(book, "Chapter 3.mp3")).Album)
+	assert.Equal(t, []string{"Frank Herbert"}, readTags(t, filepath.Join(book, "Chapter 2.mp3")).Authors)
+
+	backup := filepath.Join(dir, TagBackupDirName, now.Format(TrashSessionFormat), "Dune", "Chapter 3.mp3")
+	assert.Equal(t, "Dune (Unabridged)", readTags(t, backup).Album, "the original is backed up")
+
+	result, err = FixTags(dir, TagFixOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Groups, "the backups aren't scanned and the album is consistent now")
+}
+
+func TestPlanTagFixesNeedsMajority(t *testing.T) {
+	books := []Book{
+		{Path: "/in/a.mp3", Metadata: Metadata{RawData: map[string]interface{}{"album": "Dune"}}},
+		{Path: "/in/b.mp3", Metadata: Metadata{RawData: map[string]interface{}{"album": "Dune"}}},
+		{Path: "/in/c.m4a", Metadata: Metadata{RawData: map[string]interface{}{"album": "Emma"}}},
+		{Path: "/in/d.mp3", Metadata: Metadata{RawData: map[string]interface{}{"album": "Emma"}}},
+	}
+	assert.Empty(t, PlanTagFixes(Group{Dir: "/in", Album: true, Books: books}).Fixes, "two books of two tracks")
+
+	plan := PlanTagFixes(Group{Dir: "/in", Album: true, Books: books[:3]})
+	assert.Empty(t, plan.Fixes)
+	assert.Equal(t, []string{"/in/c.m4a"}, plan.Unsupported)
+}
@@ -0,0 +1,194 @@
+package organizer
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeeftor/audiobook-organizer/internal/remote"
+)
+
+// TargetFS is the file system organized books are written to. The source library is
+// always read locally; the target is the local disk unless the output directory is a
+// remote URL such as sftp://user@host/path or rclone:remote:path.
+type TargetFS interface {
+	Stat(name string) (fs.FileInfo, error)
+	MkdirAll(dir string) error
+	MkdirTemp(dir, pattern string) (string, error)
+	Create(name string) (io.WriteCloser, error)
+	Rename(oldPath, newPath string) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	// FreeSpace returns the bytes available below dir, or errors.ErrUnsupported
+	FreeSpace(dir string) (uint64, error)
+	Close() error
+}
+
+// targetUploader is implemented by targets with a faster way to copy a local file
+// than streaming it through Create
+type targetUploader interface {
+	Upload(source, target string) error
+}
+
+// localTargetFS writes to the local file system
+type localTargetFS struct{}
+
+func (localTargetFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+func (localTargetFS) MkdirAll(dir string) error             { return os.MkdirAll(dir, 0o755) }
+func (localTargetFS) MkdirTemp(dir, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+func (localTargetFS) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (localTargetFS) Rename(oldPath, newPath string) error       { return os.Rename(oldPath, newPath) }
+func (localTargetFS) Remove(name string) error                   { return os.Remove(name) }
+func (localTargetFS) RemoveAll(name string) error                { return os.RemoveAll(name) }
+func (localTargetFS) FreeSpace(dir string) (uint64, error)       { return LocalFreeSpace(dir) }
+func (localTargetFS) Close() error                               { return nil }
+
+// sftpTargetFS writes to a remote host over SFTP. Organizer paths are built with
+// filepath, so they are converted to the slash-separated form SFTP expects.
+type sftpTargetFS struct {
+	client *remote.SFTPClient
+}
+
+func (s sftpTargetFS) Stat(name string) (fs.FileInfo, error) {
+	return s.client.Stat(filepath.ToSlash(name))
+}
+
+func (s sftpTargetFS) MkdirAll(dir string) error {
+	return s.client.MkdirAll(filepath.ToSlash(dir))
+}
+
+func (s sftpTargetFS) MkdirTemp(dir, pattern string) (string, error) {
+	name, err := s.client.MkdirTemp(filepath.ToSlash(dir), pattern)
+	return filepath.FromSlash(name), err
+}
+
+func (s sftpTargetFS) Create(name string) (io.WriteCloser, error) {
+	return s.client.Create(filepath.ToSlash(name))
+}
+
+func (s sftpTargetFS) Rename(oldPath, newPath string) error {
+	return s.client.Rename(filepath.ToSlash(oldPath), filepath.ToSlash(newPath))
+}
+
+func (s sftpTargetFS) Remove(name string) error {
+	return s.client.Remove(filepath.ToSlash(name))
+}
+
+func (s sftpTargetFS) RemoveAll(name string) error {
+	return s.client.RemoveAll(filepath.ToSlash(name))
+}
+
+func (s sftpTargetFS) FreeSpace(dir string) (uint64, error) {
+	return s.client.FreeSpace(filepath.ToSlash(dir))
+}
+
+func (s sftpTargetFS) Close() error {
+	return s.client.Close()
+}
+
+// RcloneOutputPrefix marks an output directory handed to rclone, as in rclone:gdrive:books
+const RcloneOutputPrefix = "rclone:"
+
+// IsRemoteOutput reports whether an output directory names a remote URL rather than
+// a local path
+func IsRemoteOutput(dir string) bool {
+	if strings.HasPrefix(dir, RcloneOutputPrefix) {
+		return true
+	}
+	scheme, _, found := strings.Cut(dir, "://")
+	// A one-letter scheme would be a Windows drive letter
+	return found && len(scheme) > 1 && !strings.ContainsAny(scheme, `/\`)
+}
+
+// openRemoteTarget connects to a remote output URL and returns the target file system
+// together with the absolute remote directory books are written to
+func openRemoteTarget(outputDir string, config *OrganizerConfig) (TargetFS, string, error) {
+	if rcloneDir, ok := strings.CutPrefix(outputDir, RcloneOutputPrefix); ok {
+		return openRcloneTarget(rcloneDir, config)
+	}
+
+	u, err := url.Parse(outputDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid output URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "sftp":
+		client, err := remote.DialSFTP(u, remote.SFTPOptions{
+			IdentityFile:   config.SFTPIdentityFile,
+			KnownHostsFile: config.SFTPKnownHostsFile,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		// An empty path or one starting with /~/ is relative to the login directory
+		remotePath := u.Path
+		switch {
+		case remotePath == "":
+			remotePath = "."
+		case strings.HasPrefix(remotePath, "/~/"):
+			remotePath = remotePath[len("/~/"):]
+		}
+		if !config.DryRun {
+			if err := client.MkdirAll(remotePath); err != nil {
+				client.Close()
+				return nil, "", fmt.Errorf("cannot create output directory %s: %w", u.Redacted(), err)
+			}
+		}
+		resolved, err := client.RealPath(remotePath)
+		if err != nil && config.DryRun && path.IsAbs(remotePath) {
+			// A dry run doesn't create the directory, so it may not exist yet
+			resolved, err = path.Clean(remotePath), nil
+		}
+		if err != nil {
+			client.Close()
+			return nil, "", fmt.Errorf("error resolving output directory %s: %w", u.Redacted(), err)
+		}
+		return sftpTargetFS{client: client}, filepath.FromSlash(resolved), nil
+	case "smb":
+		return nil, "", fmt.Errorf(
+			"smb:// output is not supported directly\n\nMount the share and pass the mounted path:\n  --out=/mnt/nas/audiobooks",
+		)
+	default:
+		return nil, "", fmt.Errorf("unsupported output URL scheme %q (supported: sftp, rclone)", u.Scheme)
+	}
+}
+
+// openRcloneTarget prepares an rclone remote:path output. Paths keep rclone's syntax,
+// so summaries and logs show them the way rclone users expect.
+func openRcloneTarget(dir string, config *OrganizerConfig) (TargetFS, string, error) {
+	if name, _, found := strings.Cut(dir, ":"); !found || name == "" {
+		return nil, "", fmt.Errorf(
+			"invalid rclone output %q\n\nUse the name of a configured rclone remote:\n  --out=rclone:gdrive:audiobooks",
+			RcloneOutputPrefix+dir,
+		)
+	}
+
+	client, err := remote.NewRclone("")
+	if err != nil {
+		return nil, "", err
+	}
+	if !config.DryRun {
+		if err := client.MkdirAll(dir); err != nil {
+			return nil, "", fmt.Errorf("cannot create output directory %s: %w", dir, err)
+		}
+	}
+	return client, dir, nil
+}
+
+// redactURL hides a password in a remote output URL for display
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
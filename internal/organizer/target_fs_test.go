@@ -0,0 +1,143 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemoteTarget stands in for a remote output: it writes to a local directory but
+// is not a localTargetFS, so the organizer takes the remote code paths
+type fakeRemoteTarget struct {
+	localTargetFS
+	freeSpace uint64
+	uploads   int
+	closed    bool
+}
+
+func (f *fakeRemoteTarget) Create(name string) (io.WriteCloser, error) {
+	f.uploads++
+	return os.Create(name)
+}
+
+func (f *fakeRemoteTarget) FreeSpace(string) (uint64, error) { return f.freeSpace, nil }
+
+func (f *fakeRemoteTarget) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newRemoteTestOrganizer(t *testing.T, baseDir, outputDir string, target *fakeRemoteTarget) *Organizer {
+	t.Helper()
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: baseDir, OutputDir: outputDir})
+	require.NoError(t, err)
+	org.target = target
+	org.fileOps.target = target
+	return org
+}
+
+func TestOrganizerRemoteTargetUploadsBooks(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	bookDir := createBookDir(t, baseDir, "MyBook", "My Book", "My Author")
+	target := &fakeRemoteTarget{freeSpace: 1 << 30}
+
+	org := newRemoteTestOrganizer(t, baseDir, outputDir, target)
+	require.NoError(t, org.Execute())
+
+	assert.FileExists(t, filepath.Join(outputDir, "My Author", "My Book", "audio.mp3"))
+	assert.NoFileExists(t, filepath.Join(bookDir, "audio.mp3"))
+	assert.Equal(t, 2, target.uploads)
+	assert.True(t, target.closed)
+
+	// The log stays with the local input
+	assert.FileExists(t, filepath.Join(baseDir, LogFileName))
+	assert.NoFileExists(t, filepath.Join(outputDir, LogFileName))
+
+	entries, err := os.ReadDir(filepath.Join(outputDir, "My Author"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "staging directory should be removed")
+}
+
+func TestOrganizerRemoteTargetChecksFreeSpace(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	bookDir := createBookDir(t, baseDir, "MyBook", "My Book", "My Author")
+	target := &fakeRemoteTarget{freeSpace: 4}
+
+	org := newRemoteTestOrganizer(t, baseDir, outputDir, target)
+	org.config.SkipErrors = true
+	require.NoError(t, org.Execute())
+
+	assert.FileExists(t, filepath.Join(bookDir, "audio.mp3"))
+	assert.NoDirExists(t, filepath.Join(outputDir, "My Author", "My Book"))
+	assert.Zero(t, target.uploads)
+}
+
+func TestOrganizerLocalTargetChecksFreeSpace(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	available, err := localTargetFS{}.FreeSpace(filepath.Join(outputDir, "Not", "Yet", "Created"))
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("free space is not reported on this platform")
+	}
+	require.NoError(t, err)
+
+	// A sparse file claims more bytes than the disk has without taking them
+	source := filepath.Join(baseDir, "book.m4b")
+	file, err := os.Create(source)
+	require.NoError(t, err)
+	require.NoError(t, file.Truncate(int64(available)+1<<30))
+	require.NoError(t, file.Close())
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: baseDir, OutputDir: outputDir})
+	require.NoError(t, err)
+	moves := []FilePair{{From: source, To: "book.m4b"}}
+	targetDir := filepath.Join(outputDir, "My Author", "My Book")
+
+	onSameFileSystem = func(string, string) bool { return false }
+	err = org.checkFreeSpace(targetDir, moves)
+	onSameFileSystem = sameFileSystem
+	assert.ErrorContains(t, err, "not enough free space for "+targetDir)
+
+	if sameFileSystem(baseDir, outputDir) {
+		assert.NoError(t, org.checkFreeSpace(targetDir, moves), "a rename within one file system takes no space")
+	}
+}
+
+func TestIsRemoteOutput(t *testing.T) {
+	assert.True(t, IsRemoteOutput("sftp://user@seedbox/books"))
+	assert.True(t, IsRemoteOutput("smb://nas/share"))
+	assert.True(t, IsRemoteOutput("rclone:gdrive:audiobooks"))
+	assert.False(t, IsRemoteOutput("/srv/audiobooks"))
+	assert.False(t, IsRemoteOutput(`C:\audiobooks`))
+	assert.False(t, IsRemoteOutput("./sftp://not-a-url"))
+	assert.False(t, IsRemoteOutput(""))
+}
+
+func TestValidateRemoteOutput(t *testing.T) {
+	baseDir := t.TempDir()
+
+	config := OrganizerConfig{BaseDir: baseDir, OutputDir: "sftp://user@seedbox/books"}
+	require.NoError(t, config.Validate())
+
+	config.TrashDir = filepath.Join(baseDir, "trash")
+	assert.Error(t, config.Validate())
+
+	config.TrashDir = ""
+	config.Undo = true
+	assert.Error(t, config.Validate())
+
+	_, _, err := openRemoteTarget("smb://nas/share", &config)
+	assert.ErrorContains(t, err, "Mount the share")
+
+	_, _, err = openRemoteTarget("rclone:audiobooks", &config)
+	assert.ErrorContains(t, err, "configured rclone remote")
+}
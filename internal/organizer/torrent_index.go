@@ -0,0 +1,237 @@
+package organizer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TorrentIndex holds the files referenced by a torrent client's .torrent and
+// .fastresume data, so books that are still seeding can be left in place
+type TorrentIndex struct {
+	byName   map[string][]string // File name -> slash-separated paths relative to a save directory
+	torrents int
+}
+
+// LoadTorrentIndex reads every .torrent, .fastresume, and .resume file below dirs, such
+// as qBittorrent's BT_backup, Transmission's torrents, or Deluge's state directory.
+// Files that aren't valid torrent data are ignored.
+func LoadTorrentIndex(dirs []string) (*TorrentIndex, error) {
+	index := &TorrentIndex{byName: make(map[string][]string)}
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".torrent", ".fastresume", ".resume":
+			default:
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if index.add(data) {
+				index.torrents++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading torrent directory %s: %w", dir, err)
+		}
+	}
+	return index, nil
+}
+
+// add records the files of one bencoded torrent or resume file, reporting whether it
+// described any. Resume data only lists files when the client stored the torrent's
+// info dictionary in it.
+func (t *TorrentIndex) add(data []byte) bool {
+	value, _, err := decodeBencode(data)
+	if err != nil {
+		return false
+	}
+	root, _ := value.(map[string]any)
+	info, _ := root["info"].(map[string]any)
+	name := bencodeString(info, "name")
+	if name == "" {
+		return false
+	}
+
+	files, _ := info["files"].([]any)
+	if len(files) == 0 {
+		t.addPath(name) // Single-file torrent
+		return true
+	}
+	for _, file := range files {
+		entry, _ := file.(map[string]any)
+		parts, _ := entry["path.utf-8"].([]any)
+		if len(parts) == 0 {
+			parts, _ = entry["path"].([]any)
+		}
+		elements := []string{name}
+		for _, part := range parts {
+			if s, ok := part.(string); ok {
+				elements = append(elements, s)
+			}
+		}
+		t.addPath(path.Join(elements...))
+	}
+	return true
+}
+
+func (t *TorrentIndex) addPath(rel string) {
+	name := path.Base(rel)
+	t.byName[name] = append(t.byName[name], rel)
+}
+
+// Len returns how many torrents the index was built from
+func (t *TorrentIndex) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.torrents
+}
+
+// Contains reports whether file is part of an indexed torrent. Save directories are
+// ignored, since clients often run in containers that see the library at another
+// path; a file matches when its path ends with the torrent's name and file path.
+func (t *TorrentIndex) Contains(file string) bool {
+	if t == nil {
+		return false
+	}
+	slashed := filepath.ToSlash(filepath.Clean(file))
+	for _, rel := range t.byName[path.Base(slashed)] {
+		if slashed == rel || strings.HasSuffix(slashed, "/"+rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTorrentIndex reads the configured torrent directories once per run
+func (o *Organizer) loadTorrentIndex() error {
+	if len(o.config.TorrentDirs) == 0 || o.torrents != nil {
+		return nil
+	}
+	index, err := LoadTorrentIndex(o.config.TorrentDirs)
+	if err != nil {
+		return err
+	}
+	o.torrents = index
+	PrintBlue("🌱 Loaded %d torrents; their books are linked instead of moved", index.Len())
+	return nil
+}
+
+// keepsSources reports whether the files of a book must stay where they are. In
+// SeedSafe mode that is every book, or with TorrentDirs only books with a file that
+// is part of a torrent.
+func (o *Organizer) keepsSources(moves []FilePair) bool {
+	if !o.config.SeedSafe && len(o.config.TorrentDirs) == 0 {
+		return false
+	}
+	if len(o.config.TorrentDirs) == 0 || o.torrents == nil {
+		return true // Without a loaded index, keep everything rather than guess
+	}
+	for _, move := range moves {
+		if o.torrents.Contains(move.From) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordSeeding notes a book linked into targetDir with its sources left in place
+func (o *Organizer) recordSeeding(targetDir string) {
+	if n := len(o.summary.Seeding); n > 0 && o.summary.Seeding[n-1] == targetDir {
+		return // Flat-mode files of one album arrive one at a time
+	}
+	o.summary.Seeding = append(o.summary.Seeding, targetDir)
+}
+
+func bencodeString(dict map[string]any, key string) string {
+	if s, ok := dict[key+".utf-8"].(string); ok && s != "" {
+		return s
+	}
+	s, _ := dict[key].(string)
+	return s
+}
+
+var errBencode = errors.New("invalid bencode data")
+
+// decodeBencode decodes one bencoded value from data, returning it and the rest of
+// data. Strings decode to string, integers to int64, lists to []any, and
+// dictionaries to map[string]any.
+func decodeBencode(data []byte) (any, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, errBencode
+	}
+	switch data[0] {
+	case 'i':
+		end := bytes.IndexByte(data, 'e')
+		if end < 0 {
+			return nil, nil, errBencode
+		}
+		n, err := strconv.ParseInt(string(data[1:end]), 10, 64)
+		if err != nil {
+			return nil, nil, errBencode
+		}
+		return n, data[end+1:], nil
+	case 'l':
+		var list []any
+		rest := data[1:]
+		for len(rest) > 0 && rest[0] != 'e' {
+			value, next, err := decodeBencode(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			list = append(list, value)
+			rest = next
+		}
+		if len(rest) == 0 {
+			return nil, nil, errBencode
+		}
+		return list, rest[1:], nil
+	case 'd':
+		dict := make(map[string]any)
+		rest := data[1:]
+		for len(rest) > 0 && rest[0] != 'e' {
+			key, next, err := decodeBencode(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, nil, errBencode
+			}
+			value, next, err := decodeBencode(next)
+			if err != nil {
+				return nil, nil, err
+			}
+			dict[keyString] = value
+			rest = next
+		}
+		if len(rest) == 0 {
+			return nil, nil, errBencode
+		}
+		return dict, rest[1:], nil
+	default:
+		colon := bytes.IndexByte(data, ':')
+		if colon < 0 {
+			return nil, nil, errBencode
+		}
+		length, err := strconv.Atoi(string(data[:colon]))
+		if err != nil || length < 0 || colon+1+length > len(data) {
+			return nil, nil, errBencode
+		}
+		start := colon + 1
+		return string(data[start : start+length]), data[start+length:], nil
+	}
+}
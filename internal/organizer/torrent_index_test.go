@@ -0,0 +1,167 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bencode encodes strings, ints, lists, and maps for test torrent files
+func bencode(value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%d:%s", len(v), v)
+	case int:
+		return fmt.Sprintf("i%de", v)
+	case []any:
+		var b strings.Builder
+		b.WriteString("l")
+		for _, item := range v {
+			b.WriteString(bencode(item))
+		}
+		b.WriteString("e")
+		return b.String()
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("d")
+		for _, key := range keys {
+			b.WriteString(bencode(key) + bencode(v[key]))
+		}
+		b.WriteString("e")
+		return b.String()
+	}
+	panic(fmt.Sprintf("unsupported bencode value %T", value))
+}
+
+// writeTorrent writes a multi-file .torrent for name with the given files
+func writeTorrent(t *testing.T, dir, name string, files ...string) {
+	t.Helper()
+	var list []any
+	for _, file := range files {
+		list = append(list, map[string]any{"length": 5, "path": []any{file}})
+	}
+	data := bencode(map[string]any{
+		"announce": "http://tracker.invalid/announce",
+		"info":     map[string]any{"name": name, "piece length": 16384, "pieces": "\x00\x01", "files": list},
+	})
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".torrent"), []byte(data), 0o644))
+}
+
+func TestDecodeBencode(t *testing.T) {
+	value, rest, err := decodeBencode([]byte("d4:listli1ei-2ee3:str5:hello3:zip0:eXYZ"))
+	require.NoError(t, err)
+	assert.Equal(t, "XYZ", string(rest))
+	assert.Equal(t, map[string]any{
+		"list": []any{int64(1), int64(-2)},
+		"str":  "hello",
+		"zip":  "",
+	}, value)
+
+	for _, bad := range []string{"", "i12", "l1:a", "5:abc", "di1e1:ae", "x"} {
+		_, _, err := decodeBencode([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestTorrentIndex(t *testing.T) {
+	torrentDir := t.TempDir()
+	writeTorrent(t, torrentDir, "Dune", "01.mp3", "02.mp3")
+	single := bencode(map[string]any{"info": map[string]any{"name": "Solo.m4b", "length": 5}})
+	require.NoError(t, os.WriteFile(filepath.Join(torrentDir, "abc.fastresume"),
+		[]byte(bencode(map[string]any{"save_path": "/downloads", "info": map[string]any{"name": "Solo.m4b", "length": 5}})), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(torrentDir, "solo.torrent"), []byte(single), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(torrentDir, "broken.torrent"), []byte("not bencode"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(torrentDir, "bare.fastresume"), []byte(bencode(map[string]any{"save_path": "/x"})), 0o644))
+
+	index, err := LoadTorrentIndex([]string{torrentDir})
+	require.NoError(t, err)
+	assert.Equal(t, 3, index.Len())
+
+	assert.True(t, index.Contains("/media/downloads/Dune/01.mp3"), "save directories don't have to match")
+	assert.True(t, index.Contains("/data/Solo.m4b"))
+	assert.False(t, index.Contains("/media/downloads/Dune/03.mp3"))
+	assert.False(t, index.Contains("/media/downloads/NotDune/01.mp3"))
+	assert.False(t, index.Contains("/media/downloads/XDune/01.mp3"))
+
+	var empty *TorrentIndex
+	assert.False(t, empty.Contains("/data/Solo.m4b"))
+}
+
+func TestOrganizerSeedSafeLinksTorrentBooks(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := filepath.Join(baseDir, "out")
+	torrentDir := filepath.Join(t.TempDir(), "BT_backup")
+	createBookDir(t, baseDir, "Seeding", "Seeding", "A")
+	createBookDir(t, baseDir, "Done", "Done", "A")
+	writeTorrent(t, torrentDir, "Seeding", "audio.mp3")
+
+	config := OrganizerConfig{
+		BaseDir:     baseDir,
+		OutputDir:   outputDir,
+		Layout:      "author-title",
+		TorrentDirs: []string{torrentDir},
+	}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	source := filepath.Join(baseDir, "Seeding", "audio.mp3")
+	target := filepath.Join(outputDir, "A", "Seeding", "audio.mp3")
+	assert.FileExists(t, source, "the seeding book stays in place")
+	assert.FileExists(t, filepath.Join(baseDir, "Seeding", MetadataFileName))
+	assert.True(t, isSameLocalFile(source, target), "the output is a hardlink")
+	assert.NoFileExists(t, filepath.Join(baseDir, "Done", "audio.mp3"), "other books are still moved")
+	assert.FileExists(t, filepath.Join(outputDir, "A", "Done", "audio.mp3"))
+	assert.Equal(t, []string{filepath.Join(outputDir, "A", "Seeding")}, org.GetSummary().Seeding)
+
+	// A second run finds the links in place and leaves them alone
+	org, err = NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+	assert.True(t, isSameLocalFile(source, target))
+	assert.Empty(t, org.GetSummary().Errors)
+}
+
+func TestOrganizerSeedSafeKeepsEveryBook(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	createBookDir(t, baseDir, "Book", "Book", "A")
+
+	config := OrganizerConfig{
+		BaseDir:   baseDir,
+		OutputDir: outputDir,
+		Layout:    "author-title",
+		SeedSafe:  true,
+	}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	source := filepath.Join(baseDir, "Book", "audio.mp3")
+	target := filepath.Join(outputDir, "A", "Book", "audio.mp3")
+	assert.FileExists(t, source)
+	assert.FileExists(t, target)
+	assert.Len(t, org.GetSummary().Seeding, 1)
+
+	config.Undo = true
+	org, err = NewOrganizer(&config)
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+	assert.FileExists(t, source, "undo leaves the seeding source alone")
+	assert.NoFileExists(t, target)
+	assert.Empty(t, org.GetSummary().Errors)
+}
@@ -0,0 +1,73 @@
+package organizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TrackRename is one file of a book whose name changed as it moved
+type TrackRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TrackMap lists the renamed files of one book, so scripts can carry playback
+// progress and bookmarks kept per file, as Audiobookshelf does, over to the new names
+type TrackMap struct {
+	Source string        `json:"source"` // Folder the book's files came from
+	Target string        `json:"target"` // Folder they were moved to
+	Files  []TrackRename `json:"files"`
+}
+
+// BuildTrackMaps groups file moves by the folders they went from and to, keeping the
+// books with at least one file whose name changed, in the order they were moved
+func BuildTrackMaps(moves []MoveSummary) []TrackMap {
+	type folders struct{ source, target string }
+	var order []folders
+	byBook := make(map[folders][]TrackRename)
+	for _, move := range moves {
+		from, to := filepath.Base(move.From), filepath.Base(move.To)
+		if from == to {
+			continue
+		}
+		book := folders{filepath.Dir(move.From), filepath.Dir(move.To)}
+		if _, seen := byBook[book]; !seen {
+			order = append(order, book)
+		}
+		byBook[book] = append(byBook[book], TrackRename{From: from, To: to})
+	}
+
+	maps := make([]TrackMap, 0, len(order))
+	for _, book := range order {
+		maps = append(maps, TrackMap{Source: book.source, Target: book.target, Files: byBook[book]})
+	}
+	return maps
+}
+
+// RenamedFiles returns the files of a log entry whose name changed
+func RenamedFiles(files []FilePair) []TrackRename {
+	var renamed []TrackRename
+	for _, file := range files {
+		if filepath.Base(file.From) != filepath.Base(file.To) {
+			renamed = append(renamed, TrackRename{From: file.From, To: file.To})
+		}
+	}
+	return renamed
+}
+
+// WriteTrackMaps writes track maps as indented JSON to path
+func WriteTrackMaps(path string, maps []TrackMap) error {
+	if maps == nil {
+		maps = []TrackMap{}
+	}
+	data, err := json.MarshalIndent(maps, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing track map: %w", err)
+	}
+	return nil
+}
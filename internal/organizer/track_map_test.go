@@ -0,0 +1,60 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrackMaps(t *testing.T) {
+	maps := BuildTrackMaps([]MoveSummary{
+		{From: "/in/Dune/dune_01.mp3", To: "/out/Frank Herbert/Dune/01 - Dune.mp3"},
+		{From: "/in/Dune/cover.jpg", To: "/out/Frank Herbert/Dune/cover.jpg"},
+		{From: "/in/Hobbit/hobbit.m4b", To: "/out/J.R.R. Tolkien/The Hobbit/hobbit.m4b"},
+		{From: "/in/Dune/dune_02.mp3", To: "/out/Frank Herbert/Dune/02 - Dune.mp3"},
+	})
+	assert.Equal(t, []TrackMap{{
+		Source: "/in/Dune",
+		Target: "/out/Frank Herbert/Dune",
+		Files: []TrackRename{
+			{From: "dune_01.mp3", To: "01 - Dune.mp3"},
+			{From: "dune_02.mp3", To: "02 - Dune.mp3"},
+		},
+	}}, maps)
+	assert.Empty(t, BuildTrackMaps(nil))
+}
+
+func TestTrackMapsInReport(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	writeBook(t, filepath.Join(baseDir, "Dune"), map[string]interface{}{"title": "Dune", "authors": []string{"Frank Herbert"}},
+		"Part 1.mp3", "Part 2.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:      baseDir,
+		OutputDir:    outputDir,
+		FieldMapping: DefaultFieldMapping(),
+		ReplaceSpace: "_",
+	})
+	require.NoError(t, err)
+	require.NoError(t, org.Execute())
+
+	report := NewRunReport(org.GetSummary(), false, nil)
+	require.Len(t, report.TrackMaps, 1)
+	assert.Equal(t, filepath.Join(baseDir, "Dune"), report.TrackMaps[0].Source)
+	assert.Contains(t, report.TrackMaps[0].Files, TrackRename{From: "Part 1.mp3", To: "Part_1.mp3"})
+
+	path := filepath.Join(t.TempDir(), "track-map.json")
+	require.NoError(t, WriteTrackMaps(path, report.TrackMaps))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var written []TrackMap
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, report.TrackMaps, written)
+}
@@ -0,0 +1,172 @@
+package organizer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashSessionFormat names the timestamped folder each run moves discarded files into
+const TrashSessionFormat = "20060102-150405"
+
+// Trash moves files the organizer would otherwise overwrite or delete into a
+// timestamped folder below a trash directory. A nil *Trash deletes files directly.
+type Trash struct {
+	sessionDir string
+}
+
+// NewTrash returns a trash for one run rooted at dir
+func NewTrash(dir string, now time.Time) *Trash {
+	return &Trash{sessionDir: filepath.Join(dir, now.Format(TrashSessionFormat))}
+}
+
+// Discard removes path, moving it into the trash session when the trash is enabled.
+// The original absolute path is kept below the session folder so files with the same
+// name never collide. It returns the trashed location, or "" when the file was deleted.
+func (t *Trash) Discard(path string) (string, error) {
+	return t.discardAs(path, path)
+}
+
+// discardAs trashes the file at path as if it were still at original, for files
+// moved aside before being given up
+func (t *Trash) discardAs(path, original string) (string, error) {
+	if t == nil {
+		return "", os.Remove(path)
+	}
+
+	absPath, err := filepath.Abs(original)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", path, err)
+	}
+	relPath := strings.TrimPrefix(absPath, filepath.VolumeName(absPath))
+	dest := filepath.Join(t.sessionDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", fmt.Errorf("error creating trash directory: %w", err)
+	}
+
+	if err := os.Rename(path, dest); err != nil {
+		// The trash may live on another filesystem
+		if err := copyFileContents(path, dest); err != nil {
+			return "", fmt.Errorf("error moving %s to trash: %w", path, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("error removing %s after trashing: %w", path, err)
+		}
+	}
+	return dest, nil
+}
+
+// copyFileContents copies src to dst and syncs dst to disk
+func copyFileContents(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// discard removes a file through the configured trash and records trashed files
+func (o *Organizer) discard(path string) error {
+	return o.discardAs(path, path)
+}
+
+// discardAs is discard for a file moved aside from original, which is where the
+// trash files it
+func (o *Organizer) discardAs(path, original string) error {
+	trashed, err := o.trash.discardAs(path, original)
+	if err != nil {
+		return err
+	}
+	if trashed != "" {
+		o.summary.Trashed = append(o.summary.Trashed, trashed)
+		if o.config.Verbose {
+			PrintYellow("🗑️  Moved %s to trash: %s", original, trashed)
+		}
+	}
+	return nil
+}
+
+// discardExisting moves an existing file at target out of the way before it would be
+// overwritten. Without a trash the file is left for the caller to overwrite as before.
+func (o *Organizer) discardExisting(target string) error {
+	if o.trash == nil {
+		return nil
+	}
+	info, err := o.target.Stat(target)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+	return o.discard(target)
+}
+
+// ParseTrashAge parses a purge age such as "30d", "2w", or any time.ParseDuration value
+func ParseTrashAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid age %q (use values like 30d, 2w, or 12h)", value)
+	}
+	return duration, nil
+}
+
+// PurgeTrash deletes trash sessions in dir older than olderThan and returns the
+// removed session folders. Entries that are not trash sessions are left alone.
+func PurgeTrash(dir string, olderThan time.Duration, now time.Time, dryRun bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading trash directory: %w", err)
+	}
+
+	cutoff := now.Add(-olderThan)
+	var purged []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		created, err := time.ParseInLocation(TrashSessionFormat, entry.Name(), now.Location())
+		if err != nil || !created.Before(cutoff) {
+			continue
+		}
+
+		sessionDir := filepath.Join(dir, entry.Name())
+		if !dryRun {
+			if err := os.RemoveAll(sessionDir); err != nil {
+				return purged, fmt.Errorf("error purging %s: %w", sessionDir, err)
+			}
+		}
+		purged = append(purged, sessionDir)
+	}
+
+	sort.Strings(purged)
+	return purged, nil
+}
@@ -0,0 +1,116 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashDiscard(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "book", "01.mp3")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("audio"), 0o644))
+
+	now := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	trash := NewTrash(filepath.Join(dir, "trash"), now)
+	trashed, err := trash.Discard(file)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, file)
+	assert.Equal(t, filepath.Join(dir, "trash", "20261001-123000", file), trashed)
+	data, err := os.ReadFile(trashed)
+	require.NoError(t, err)
+	assert.Equal(t, "audio", string(data))
+}
+
+func TestTrashDiscardNilDeletes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "old.log")
+	require.NoError(t, os.WriteFile(file, []byte("log"), 0o644))
+
+	var trash *Trash
+	trashed, err := trash.Discard(file)
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+	assert.NoFileExists(t, file)
+}
+
+func TestMoveFileTrashesOverwrittenTarget(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "in", "book.m4b")
+	target := filepath.Join(dir, "out", "book.m4b")
+	require.NoError(t, os.MkdirAll(filepath.Dir(source), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o755))
+	require.NoError(t, os.WriteFile(source, []byte("new"), 0o644))
+	require.NoError(t, os.WriteFile(target, []byte("existing"), 0o644))
+
+	config := OrganizerConfig{BaseDir: filepath.Join(dir, "in"), TrashDir: filepath.Join(dir, "trash")}
+	org, err := NewOrganizer(&config)
+	require.NoError(t, err)
+
+	require.NoError(t, org.moveFile(source, target))
+
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	require.Len(t, org.summary.Trashed, 1)
+	data, err = os.ReadFile(org.summary.Trashed[0])
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(data))
+}
+
+func TestParseTrashAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"xd", 0, true},
+		{"soon", 0, true},
+		{"-1h", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTrashAge(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local)
+	old := filepath.Join(dir, now.AddDate(0, 0, -40).Format(TrashSessionFormat))
+	recent := filepath.Join(dir, now.AddDate(0, 0, -5).Format(TrashSessionFormat))
+	other := filepath.Join(dir, "keep-me")
+	for _, d := range []string{old, recent, other} {
+		require.NoError(t, os.MkdirAll(d, 0o755))
+	}
+
+	purged, err := PurgeTrash(dir, 30*24*time.Hour, now, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{old}, purged)
+	assert.DirExists(t, old)
+
+	purged, err = PurgeTrash(dir, 30*24*time.Hour, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{old}, purged)
+	assert.NoDirExists(t, old)
+	assert.DirExists(t, recent)
+	assert.DirExists(t, other)
+}
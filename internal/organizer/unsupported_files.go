@@ -0,0 +1,101 @@
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// carryUnsupported settles the files flat mode found but doesn't organize, like a
+// .txt description or a .jpg cover next to an audio file. With CarryUnsupported,
+// those no larger than it move into the folder the books of their directory went
+// to; the rest stay in Summary.Unsupported. A directory whose books went to several
+// folders keeps its files, as there is no telling which book they belong to, and a
+// file never replaces one already at its target.
+func (o *Organizer) carryUnsupported() {
+	if len(o.summary.Unsupported) == 0 {
+		return
+	}
+	moved := make(map[string]bool, len(o.summary.FileMoves))
+	for _, move := range o.summary.FileMoves {
+		moved[move.From] = true
+	}
+	targets := o.flatTargetDirs()
+
+	var left []string
+	carried := make(map[string][]FilePair)
+	planned := make(map[string]bool)
+	for _, path := range o.summary.Unsupported {
+		if moved[path] {
+			// Already moved as an ebook companion
+			continue
+		}
+		target, ok := o.carryTarget(path, targets[filepath.Dir(path)], planned)
+		if !ok {
+			left = append(left, path)
+			continue
+		}
+		if !o.config.DryRun {
+			if err := o.moveFile(path, target); err != nil {
+				o.recordError("❌ Error moving %s: %v", path, err)
+				left = append(left, path)
+				continue
+			}
+		}
+		planned[target] = true
+		o.summary.Carried = append(o.summary.Carried, MoveSummary{From: path, To: target})
+		o.recordFileMove(path, target)
+		carried[filepath.Dir(path)] = append(carried[filepath.Dir(path)], FilePair{From: filepath.Base(path), To: filepath.Base(target)})
+	}
+	o.summary.Unsupported = left
+
+	if o.config.DryRun {
+		for _, move := range o.summary.Carried {
+			o.printFileLine(filepath.Dir(move.From), o.formatDryRunMove(move.From, move.To))
+		}
+		return
+	}
+	sources := make([]string, 0, len(carried))
+	for dir := range carried {
+		sources = append(sources, dir)
+	}
+	sort.Strings(sources)
+	for _, dir := range sources {
+		o.updateLogAndCleanup(dir, targets[dir], carried[dir])
+	}
+}
+
+// carryTarget returns where an unsupported file moves to within targetDir, or false
+// when it stays: nothing is carried, its directory has no single target, it is too
+// large, or a file is already there
+func (o *Organizer) carryTarget(path, targetDir string, planned map[string]bool) (string, bool) {
+	if o.config.CarryUnsupported <= 0 || targetDir == "" {
+		return "", false
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > o.config.CarryUnsupported {
+		return "", false
+	}
+	target := filepath.Join(targetDir, o.naming().File(filepath.Base(path)))
+	if planned[target] {
+		return "", false
+	}
+	if _, err := o.target.Stat(target); err == nil {
+		return "", false
+	}
+	return target, true
+}
+
+// flatTargetDirs maps each source directory of the books moved in flat mode to the
+// folder they went to, or to "" when they went to several
+func (o *Organizer) flatTargetDirs() map[string]string {
+	targets := make(map[string]string)
+	for _, move := range o.summary.Moves {
+		source, target := filepath.Dir(move.From), filepath.Dir(move.To)
+		if seen, ok := targets[source]; ok && seen != target {
+			target = ""
+		}
+		targets[source] = target
+	}
+	return targets
+}
@@ -0,0 +1,108 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerReportsUnsupportedFlatFiles(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFlatFiles(t, baseDir, 1)
+	for _, name := range []string{"notes.txt", "cover.jpg", ".DS_Store", MetadataFileName} {
+		require.NoError(t, os.WriteFile(filepath.Join(baseDir, name), []byte("x"), 0o644))
+	}
+
+	scanner := NewScanner(ScanOptions{Flat: true, FallbackToFilename: true})
+	result, err := scanner.Scan(baseDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(baseDir, "notes.txt"),
+		filepath.Join(baseDir, "cover.jpg"),
+	}, result.Unsupported, "hidden files and metadata.json aren't reported")
+	assert.Equal(t, 2, scanner.Progress().Unsupported)
+
+	policy, err := ParseExtensionActions([]string{".txt=ignore"})
+	require.NoError(t, err)
+	result, err = NewScanner(ScanOptions{Flat: true, FallbackToFilename: true, Extensions: policy}).Scan(baseDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(baseDir, "cover.jpg")}, result.Unsupported, "ignored extensions are left alone on purpose")
+}
+
+func TestCarryUnsupported(t *testing.T) {
+	baseDir := t.TempDir()
+	outputDir := t.TempDir()
+	write := func(rel string, size int) string {
+		path := filepath.Join(baseDir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+		return path
+	}
+	notes := write("Dune/notes.txt", 100)
+	large := write("Dune/scan.pdf", 4096)
+	shared := write("Mixed/readme.txt", 100)
+	taken := write("Emma/cover.jpg", 100)
+	duneTarget := filepath.Join(outputDir, "Frank Herbert", "Dune")
+	emmaTarget := filepath.Join(outputDir, "Jane Austen", "Emma")
+	require.NoError(t, os.MkdirAll(duneTarget, 0o755))
+	require.NoError(t, os.MkdirAll(emmaTarget, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(emmaTarget, "cover.jpg"), []byte("other"), 0o644))
+
+	org, err := NewOrganizer(&OrganizerConfig{
+		BaseDir:          baseDir,
+		OutputDir:        outputDir,
+		Flat:             true,
+		CarryUnsupported: 1024,
+	})
+	require.NoError(t, err)
+	org.summary.Moves = []MoveSummary{
+		{From: filepath.Join(baseDir, "Dune", "01.mp3"), To: filepath.Join(duneTarget, "01.mp3")},
+		{From: filepath.Join(baseDir, "Dune", "02.mp3"), To: filepath.Join(duneTarget, "02.mp3")},
+		{From: filepath.Join(baseDir, "Mixed", "a.mp3"), To: filepath.Join(duneTarget, "a.mp3")},
+		{From: filepath.Join(baseDir, "Mixed", "b.mp3"), To: filepath.Join(emmaTarget, "b.mp3")},
+		{From: filepath.Join(baseDir, "Emma", "emma.mp3"), To: filepath.Join(emmaTarget, "emma.mp3")},
+	}
+	org.summary.Unsupported = []string{notes, large, shared, taken}
+
+	output := CaptureOutput(org.carryUnsupported)
+
+	assert.FileExists(t, filepath.Join(duneTarget, "notes.txt"))
+	assert.NoFileExists(t, notes)
+	assert.Equal(t, []MoveSummary{{From: notes, To: filepath.Join(duneTarget, "notes.txt")}}, org.summary.Carried)
+	assert.Equal(t, []string{large, shared, taken}, org.summary.Unsupported,
+		"too large, in a directory of several books, and with a file at the target")
+	require.Len(t, org.logEntries, 1)
+	assert.Equal(t, []FilePair{{From: "notes.txt", To: "notes.txt", Size: 100}}, org.logEntries[0].Files)
+	assert.Empty(t, org.summary.Errors, output)
+}
+
+func TestCarryUnsupportedDryRun(t *testing.T) {
+	baseDir := t.TempDir()
+	notes := filepath.Join(baseDir, "notes.txt")
+	require.NoError(t, os.WriteFile(notes, []byte("notes"), 0o644))
+	target := filepath.Join(t.TempDir(), "Frank Herbert", "Dune")
+
+	org := &Organizer{config: OrganizerConfig{Flat: true, DryRun: true, CarryUnsupported: 1024}}
+	org.target = localTargetFS{}
+	org.summary.Moves = []MoveSummary{{From: filepath.Join(baseDir, "dune.m4b"), To: filepath.Join(target, "dune.m4b")}}
+	org.summary.FileMoves = []MoveSummary{{From: filepath.Join(baseDir, "dune.jpg"), To: filepath.Join(target, "dune.jpg")}}
+	org.summary.Unsupported = []string{notes, filepath.Join(baseDir, "dune.jpg")}
+
+	output := CaptureOutput(org.carryUnsupported)
+	assert.Contains(t, output, "[DRY-RUN] Would move")
+	assert.FileExists(t, notes)
+	assert.Empty(t, org.summary.Unsupported, "a file already moved as an ebook companion isn't left behind")
+	assert.Len(t, org.summary.Carried, 1)
+}
+
+func TestCarryUnsupportedRequiresFlat(t *testing.T) {
+	_, err := NewOrganizer(&OrganizerConfig{BaseDir: t.TempDir(), CarryUnsupported: 1024})
+	assert.ErrorContains(t, err, "--carry-unsupported requires --flat")
+	_, err = NewOrganizer(&OrganizerConfig{BaseDir: t.TempDir(), Flat: true, CarryUnsupported: -1})
+	assert.ErrorContains(t, err, "invalid carry-unsupported size")
+}
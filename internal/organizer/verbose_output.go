@@ -0,0 +1,103 @@
+package organizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// DefaultProgressInterval is how often a book whose file lines are coalesced reports
+// how many more files it has moved
+const DefaultProgressInterval = 5 * time.Second
+
+// ansiSequence matches the terminal escape sequences of styled output
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// OpenDetailLog opens path for appending, creating it when needed, for
+// OrganizerConfig.DetailLog
+func OpenDetailLog(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening detail log: %w", err)
+	}
+	return file, nil
+}
+
+// logDetail writes one per-file line to the DetailLog, if any, with a timestamp and
+// without colors
+func (o *Organizer) logDetail(text string) {
+	if o.config.DetailLog == nil {
+		return
+	}
+	fmt.Fprintf(o.config.DetailLog, "%s %s\n", time.Now().Format(time.RFC3339), ansiSequence.ReplaceAllString(text, ""))
+}
+
+// fileLines coalesces the per-file lines of one book. The first FileLines lines are
+// printed; the rest only reach the detail log, and the screen gets a count of them
+// every ProgressInterval and when the book is done.
+type fileLines struct {
+	book      string
+	shown     int
+	pending   int // Lines coalesced since the last count was printed
+	lastCount time.Time
+}
+
+// printFileLine prints one per-file line of the book at book, which is a book
+// directory, or the directory of a flat-mode file so an album's files coalesce too
+func (o *Organizer) printFileLine(book, message string) {
+	if o.lines != nil && o.lines.book != book {
+		o.endFileLines()
+	}
+	if o.lines == nil {
+		o.lines = &fileLines{book: book}
+	}
+
+	o.logDetail(message)
+	lines := o.lines
+	if o.config.FileLines <= 0 || lines.shown < o.config.FileLines {
+		lines.shown++
+		PrintBase("%s", message)
+		return
+	}
+
+	if lines.pending == 0 && lines.lastCount.IsZero() {
+		lines.lastCount = time.Now()
+	}
+	lines.pending++
+	if time.Since(lines.lastCount) >= o.progressInterval() {
+		o.printFileCount(false)
+	}
+}
+
+// endFileLines prints the count of the current book's coalesced lines, if any
+func (o *Organizer) endFileLines() {
+	if o.lines == nil {
+		return
+	}
+	o.printFileCount(true)
+	o.lines = nil
+}
+
+func (o *Organizer) printFileCount(done bool) {
+	lines := o.lines
+	if lines.pending == 0 {
+		return
+	}
+	if done {
+		PrintBase("   … and %d more files of %s", lines.pending, filepath.Base(lines.book))
+	} else {
+		PrintBase("   … %d more files of %s", lines.pending, filepath.Base(lines.book))
+	}
+	lines.pending = 0
+	lines.lastCount = time.Now()
+}
+
+// progressInterval returns the configured ProgressInterval, or the default
+func (o *Organizer) progressInterval() time.Duration {
+	if o.config.ProgressInterval > 0 {
+		return o.config.ProgressInterval
+	}
+	return DefaultProgressInterval
+}
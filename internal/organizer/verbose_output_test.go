@@ -0,0 +1,51 @@
+//go:build !integration
+
+package organizer
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLinesCoalesceLargeBooks(t *testing.T) {
+	base := t.TempDir()
+	out := t.TempDir()
+	tracks := make([]string, 12)
+	for i := range tracks {
+		tracks[i] = fmt.Sprintf("%02d.mp3", i+1)
+	}
+	writeDisc(t, filepath.Join(base, "Dune"), `{"title":"Dune","authors":["Frank Herbert"]}`, tracks...)
+
+	var detail bytes.Buffer
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: out, Verbose: true, FileLines: 3, DetailLog: &detail})
+	require.NoError(t, err)
+	output := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+
+	assert.Equal(t, 3, strings.Count(output, "📦 Moving"))
+	// 12 tracks, metadata.json, and cover.jpg
+	assert.Contains(t, output, "… and 11 more files of Dune")
+	assert.Equal(t, 14, strings.Count(detail.String(), "📦 Moving"), "the detail log gets every line")
+	assert.NotContains(t, detail.String(), "\x1b[", "the detail log is written without colors")
+	assert.FileExists(t, filepath.Join(out, "Frank Herbert", "Dune", "12.mp3"))
+}
+
+func TestFileLinesUnlimitedByDefault(t *testing.T) {
+	base := t.TempDir()
+	writeDisc(t, filepath.Join(base, "Dune"), `{"title":"Dune","authors":["Frank Herbert"]}`, "01.mp3", "02.mp3")
+
+	org, err := NewOrganizer(&OrganizerConfig{BaseDir: base, OutputDir: t.TempDir(), DryRun: true})
+	require.NoError(t, err)
+	output := CaptureOutput(func() {
+		require.NoError(t, org.Execute())
+	})
+	assert.Equal(t, 4, strings.Count(output, "[DRY-RUN] Moving"))
+	assert.NotContains(t, output, "more files of")
+}
@@ -0,0 +1,63 @@
+package planning
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AudioInfo describes the audio stream of the file a book's metadata was read from.
+// Zero fields are unknown. Bitrate is the container's nominal rate, or the average
+// over the whole file when the container doesn't record one.
+type AudioInfo struct {
+	Codec      string  `json:"codec,omitempty"`
+	Bitrate    int     `json:"bitrate,omitempty"`     // kbit/s
+	SampleRate int     `json:"sample_rate,omitempty"` // Hz
+	Channels   int     `json:"channels,omitempty"`
+	Duration   float64 `json:"duration,omitempty"` // Seconds
+}
+
+// IsZero reports whether nothing is known about the stream
+func (a AudioInfo) IsZero() bool {
+	return a == AudioInfo{}
+}
+
+// String summarizes the stream for display, such as
+// "AAC, 64 kbps, stereo, 44.1 kHz, 11h23m"
+func (a AudioInfo) String() string {
+	var parts []string
+	if a.Codec != "" {
+		parts = append(parts, a.Codec)
+	}
+	if a.Bitrate > 0 {
+		parts = append(parts, fmt.Sprintf("%d kbps", a.Bitrate))
+	}
+	switch a.Channels {
+	case 0:
+	case 1:
+		parts = append(parts, "mono")
+	case 2:
+		parts = append(parts, "stereo")
+	default:
+		parts = append(parts, fmt.Sprintf("%d channels", a.Channels))
+	}
+	if a.SampleRate > 0 {
+		parts = append(parts, strings.TrimSuffix(fmt.Sprintf("%.1f", float64(a.SampleRate)/1000), ".0")+" kHz")
+	}
+	if duration := FormatDuration(a.Duration); duration != "" {
+		parts = append(parts, duration)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Better reports whether a is the higher quality copy of the same recording: the
+// higher bitrate, then more channels, then the higher sample rate. Copies that can't
+// be told apart are not better than each other.
+func (a AudioInfo) Better(b AudioInfo) bool {
+	if a.Bitrate != b.Bitrate {
+		return a.Bitrate > b.Bitrate
+	}
+	if a.Channels != b.Channels {
+		return a.Channels > b.Channels
+	}
+	return a.SampleRate > b.SampleRate
+}
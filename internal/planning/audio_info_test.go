@@ -0,0 +1,36 @@
+package planning
+
+import "testing"
+
+func TestAudioInfoString(t *testing.T) {
+	tests := []struct {
+		info     AudioInfo
+		expected string
+	}{
+		{AudioInfo{Codec: "AAC", Bitrate: 64, Channels: 2, SampleRate: 44100, Duration: 11*3600 + 23*60}, "AAC, 64 kbps, stereo, 44.1 kHz, 11h23m"},
+		{AudioInfo{Codec: "MP3", Channels: 1, SampleRate: 22050}, "MP3, mono, 22.1 kHz"},
+		{AudioInfo{Codec: "Opus", Channels: 6, SampleRate: 48000}, "Opus, 6 channels, 48 kHz"},
+		{AudioInfo{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.expected {
+			t.Errorf("%+v.String() = %q, want %q", tt.info, got, tt.expected)
+		}
+	}
+}
+
+func TestAudioInfoBetter(t *testing.T) {
+	low := AudioInfo{Codec: "MP3", Bitrate: 64, Channels: 1}
+	high := AudioInfo{Codec: "AAC", Bitrate: 128, Channels: 1}
+	stereo := AudioInfo{Codec: "MP3", Bitrate: 64, Channels: 2}
+
+	if !high.Better(low) || low.Better(high) {
+		t.Error("higher bitrate should be better")
+	}
+	if !stereo.Better(low) {
+		t.Error("more channels should break a bitrate tie")
+	}
+	if low.Better(low) {
+		t.Error("identical copies should not be better than each other")
+	}
+}
@@ -0,0 +1,100 @@
+package planning
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Chapter is one chapter of a book, as Audiobookshelf lists them in metadata.json,
+// with its start and end in seconds
+type Chapter struct {
+	Title string  `json:"title"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// yearFields name the raw fields that may hold the publication year: the keys of
+// Audiobookshelf's metadata.json and API, and the year tag of audio files
+var yearFields = []string{"publishedYear", "published_year", "year", "publishedDate", "published_date", "date"}
+
+var leadingYearPattern = regexp.MustCompile(`^\s*(\d{4})\b`)
+
+// FillDetails sets the subtitle, narrators, publisher, year, description, and
+// chapters from the raw fields when they aren't set yet. ApplyFieldMapping calls
+// it, so metadata only needs it when it isn't mapped.
+func (m *Metadata) FillDetails() {
+	if m.Subtitle == "" {
+		m.Subtitle = stringifyTemplateValue(rawTemplateValue(*m, "subtitle"))
+	}
+	if len(m.Narrators) == 0 {
+		m.Narrators = narratorValuesFromMetadata(*m)
+	}
+	if m.Publisher == "" {
+		m.Publisher = stringifyTemplateValue(rawTemplateValue(*m, "publisher"))
+	}
+	if m.Year == 0 {
+		m.Year = rawPublishedYear(*m)
+	}
+	if m.Description == "" {
+		m.Description = stringifyTemplateValue(rawTemplateValue(*m, "description"))
+	}
+	if len(m.Chapters) == 0 {
+		m.Chapters = rawChapters(rawTemplateValue(*m, "chapters"))
+	}
+}
+
+// GetYear returns the publication year, or 0 when it isn't known
+func (m *Metadata) GetYear() int {
+	if m.Year > 0 {
+		return m.Year
+	}
+	return rawPublishedYear(*m)
+}
+
+// rawPublishedYear reads the year from the first year field holding one, which may
+// be a number, a string such as "2006", or a date such as "2006-08-01"
+func rawPublishedYear(metadata Metadata) int {
+	for _, field := range yearFields {
+		switch value := rawTemplateValue(metadata, field).(type) {
+		case int:
+			if value > 0 {
+				return value
+			}
+		case float64:
+			if value > 0 {
+				return int(value)
+			}
+		case string:
+			if match := leadingYearPattern.FindStringSubmatch(value); match != nil {
+				year, _ := strconv.Atoi(match[1])
+				return year
+			}
+		}
+	}
+	return 0
+}
+
+// rawChapters reads a metadata.json chapter list. Counts, like those read from audio
+// containers, and entries that aren't objects give no chapters.
+func rawChapters(value interface{}) []Chapter {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	chapters := make([]Chapter, 0, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		chapter := Chapter{Title: strings.TrimSpace(stringifyTemplateValue(fields["title"]))}
+		chapter.Start, _ = fields["start"].(float64)
+		chapter.End, _ = fields["end"].(float64)
+		chapters = append(chapters, chapter)
+	}
+	if len(chapters) == 0 {
+		return nil
+	}
+	return chapters
+}
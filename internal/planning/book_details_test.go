@@ -0,0 +1,65 @@
+package planning
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFillDetails(t *testing.T) {
+	metadata := Metadata{RawData: map[string]interface{}{
+		"subtitle":      "Book One of the Stormlight Archive",
+		"narrators":     []interface{}{"Michael Kramer", "Kate Reading"},
+		"publisher":     "Macmillan Audio",
+		"publishedYear": "2010",
+		"description":   "<p>Roshar is a world of stone and storms.</p>",
+		"chapters": []interface{}{
+			map[string]interface{}{"id": 0.0, "start": 0.0, "end": 95.5, "title": "Prelude"},
+			map[string]interface{}{"id": 1.0, "start": 95.5, "end": 1800.0, "title": " Prologue "},
+		},
+	}}
+	metadata.FillDetails()
+
+	if metadata.Subtitle != "Book One of the Stormlight Archive" {
+		t.Errorf("Subtitle = %q", metadata.Subtitle)
+	}
+	if want := []string{"Michael Kramer", "Kate Reading"}; !reflect.DeepEqual(metadata.Narrators, want) {
+		t.Errorf("Narrators = %v, want %v", metadata.Narrators, want)
+	}
+	if metadata.Publisher != "Macmillan Audio" || metadata.Year != 2010 {
+		t.Errorf("Publisher, Year = %q, %d", metadata.Publisher, metadata.Year)
+	}
+	if metadata.Description == "" {
+		t.Error("Description not set")
+	}
+	want := []Chapter{{Title: "Prelude", End: 95.5}, {Title: "Prologue", Start: 95.5, End: 1800}}
+	if !reflect.DeepEqual(metadata.Chapters, want) {
+		t.Errorf("Chapters = %+v, want %+v", metadata.Chapters, want)
+	}
+}
+
+func TestGetYear(t *testing.T) {
+	tests := []struct {
+		raw  map[string]interface{}
+		want int
+	}{
+		{map[string]interface{}{"publishedYear": "2010"}, 2010},
+		{map[string]interface{}{"year": 1965.0}, 1965},
+		{map[string]interface{}{"publishedDate": "2006-08-01"}, 2006},
+		{map[string]interface{}{"year": 1999, "publishedYear": "2001"}, 2001},
+		{map[string]interface{}{"publishedYear": "unknown"}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		metadata := Metadata{RawData: tt.raw}
+		if got := metadata.GetYear(); got != tt.want {
+			t.Errorf("GetYear(%v) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+
+	// Chapter counts read from audio containers aren't chapter lists
+	metadata := Metadata{RawData: map[string]interface{}{"chapters": 12}}
+	metadata.FillDetails()
+	if metadata.Chapters != nil {
+		t.Errorf("Chapters = %+v, want none", metadata.Chapters)
+	}
+}
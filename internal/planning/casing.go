@@ -0,0 +1,176 @@
+package planning
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Casing styles for path components
+const (
+	CasingPreserve = "preserve" // Keep the tag's casing (default)
+	CasingTitle    = "title"    // "The Way of Kings"
+	CasingSentence = "sentence" // "The way of kings"
+)
+
+// ValidCasing reports whether casing names a casing style; empty means preserve
+func ValidCasing(casing string) bool {
+	switch casing {
+	case "", CasingPreserve, CasingTitle, CasingSentence:
+		return true
+	}
+	return false
+}
+
+// titleSmallWords stay lowercase inside a Title Case component
+var titleSmallWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "but": true, "by": true,
+	"for": true, "from": true, "in": true, "into": true, "nor": true, "of": true, "on": true,
+	"or": true, "over": true, "per": true, "the": true, "to": true, "up": true, "via": true,
+	"vs": true, "with": true,
+}
+
+// casingAcronyms are always uppercase. Words that are also common English words,
+// such as "us", are left out since an all-caps tag can't tell the two apart.
+var casingAcronyms = map[string]bool{
+	"AI": true, "BBC": true, "CIA": true, "CSI": true, "DNA": true, "FBI": true, "JFK": true,
+	"KGB": true, "MI5": true, "MI6": true, "NASA": true, "NYPD": true, "SAS": true, "TV": true,
+	"UFO": true, "UK": true, "USA": true, "WWI": true, "WWII": true,
+}
+
+// casingRomanNumerals are uppercased so "Rocky ii" becomes "Rocky II"
+var casingRomanNumerals = map[string]bool{
+	"II": true, "III": true, "IV": true, "VI": true, "VII": true, "VIII": true, "IX": true,
+	"XI": true, "XII": true, "XIII": true, "XIV": true, "XV": true, "XVI": true, "XVII": true,
+	"XVIII": true, "XIX": true, "XX": true,
+}
+
+// ApplyCasing rewrites the casing of one path component. Title Case capitalizes every
+// word except small words such as "of" and "the" in the middle; Sentence case only
+// capitalizes the first word and the first word after a colon or dash. Both keep
+// acronyms and Roman numerals uppercase, and in mixed-case text they keep words
+// with capitals past the first letter ("McCoy", "NASA") as tagged, since only
+// ALL-CAPS or all-lowercase text hides what was meant.
+func ApplyCasing(s, casing string) string {
+	if casing != CasingTitle && casing != CasingSentence {
+		return s
+	}
+	uniform := !hasUpper(s) || !hasLower(s)
+
+	words := strings.Split(s, " ")
+	last := len(words) - 1
+	for last > 0 && !hasLetter(words[last]) {
+		last--
+	}
+	startOfPhrase := true
+	for i, word := range words {
+		prefix, core, suffix := splitWordPunctuation(word)
+		if core == "" {
+			if word == "-" || word == "–" || word == "—" {
+				startOfPhrase = true
+			}
+			continue
+		}
+
+		upper := strings.ToUpper(core)
+		_, firstSize := utf8.DecodeRuneInString(core)
+		switch {
+		case casingAcronyms[upper] || casingRomanNumerals[upper]:
+			core = upper
+		case !uniform && hasUpper(core[firstSize:]):
+			// Deliberate capitals such as "McCoy" or "iPhone"
+		case casing == CasingTitle && !startOfPhrase && i != last && titleSmallWords[strings.ToLower(core)]:
+			core = strings.ToLower(core)
+		case casing == CasingTitle || startOfPhrase || strings.EqualFold(core, "i") || strings.HasPrefix(strings.ToLower(core), "i'"):
+			core = capitalizeWord(core)
+		default:
+			core = strings.ToLower(core)
+		}
+		words[i] = prefix + core + suffix
+		startOfPhrase = strings.HasSuffix(suffix, ":") || strings.HasSuffix(suffix, "?") || strings.HasSuffix(suffix, "!")
+	}
+	return strings.Join(words, " ")
+}
+
+// NameCasing is the casing applied to author names for a casing style. Names are
+// never sentence cased, which would lowercase surnames.
+func NameCasing(casing string) string {
+	if casing == CasingSentence {
+		return CasingTitle
+	}
+	return casing
+}
+
+// ApplyMetadataCasing returns metadata with the title, series, and album in casing
+// and the authors in NameCasing(casing)
+func ApplyMetadataCasing(metadata Metadata, casing string) Metadata {
+	if casing != CasingTitle && casing != CasingSentence {
+		return metadata
+	}
+	metadata.Title = ApplyCasing(metadata.Title, casing)
+	metadata.Album = ApplyCasing(metadata.Album, casing)
+	metadata.Authors = applyCasingAll(metadata.Authors, NameCasing(casing))
+	metadata.Series = applyCasingAll(metadata.Series, casing)
+	return metadata
+}
+
+// applyCasingAll returns a cased copy of values, leaving the caller's slice alone
+func applyCasingAll(values []string, casing string) []string {
+	if values == nil {
+		return nil
+	}
+	cased := make([]string, len(values))
+	for i, value := range values {
+		cased[i] = ApplyCasing(value, casing)
+	}
+	return cased
+}
+
+// capitalizeWord lowercases a word and capitalizes its first letter, each part of a
+// hyphenated word or initials ("Sci-Fi", "J.R.R."), and names such as "O'Brien" and
+// "McCoy". Other apostrophes keep the next letter lowercase ("Don't").
+func capitalizeWord(word string) string {
+	runes := []rune(strings.ToLower(word))
+	capitalizeNext := true
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r):
+			if capitalizeNext {
+				runes[i] = unicode.ToUpper(r)
+			}
+			capitalizeNext = false
+		case r == '-' || r == '.' || r == '/':
+			capitalizeNext = true
+		case (r == '\'' || r == '’') && i == 1 && (runes[0] == 'o' || runes[0] == 'O') && len(runes) > 3:
+			capitalizeNext = true
+		default:
+			capitalizeNext = false
+		}
+	}
+	if len(runes) > 3 && runes[0] == 'M' && runes[1] == 'c' && unicode.IsLetter(runes[2]) {
+		runes[2] = unicode.ToUpper(runes[2])
+	}
+	return string(runes)
+}
+
+// splitWordPunctuation separates a word's leading and trailing punctuation, such as
+// the brackets of "(Unabridged)", from its letters
+func splitWordPunctuation(word string) (prefix, core, suffix string) {
+	start := strings.IndexFunc(word, isWordRune)
+	if start < 0 {
+		return word, "", ""
+	}
+	end := strings.LastIndexFunc(word, isWordRune)
+	_, size := utf8.DecodeRuneInString(word[end:])
+	return word[:start], word[start : end+size], word[end+size:]
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func hasUpper(s string) bool { return strings.IndexFunc(s, unicode.IsUpper) >= 0 }
+
+func hasLower(s string) bool { return strings.IndexFunc(s, unicode.IsLower) >= 0 }
+
+func hasLetter(s string) bool { return strings.IndexFunc(s, unicode.IsLetter) >= 0 }
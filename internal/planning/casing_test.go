@@ -0,0 +1,61 @@
+package planning
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyCasing(t *testing.T) {
+	tests := []struct {
+		input    string
+		casing   string
+		expected string
+	}{
+		{"THE WAY OF KINGS", CasingTitle, "The Way of Kings"},
+		{"the way of kings", CasingTitle, "The Way of Kings"},
+		{"a tale of two cities", CasingTitle, "A Tale of Two Cities"},
+		{"what are you looking at", CasingTitle, "What Are You Looking At"},
+		{"DUNE: THE BATTLE OF CORRIN", CasingTitle, "Dune: The Battle of Corrin"},
+		{"#1 - the final empire", CasingTitle, "#1 - The Final Empire"},
+		{"THE FBI FILES PART II", CasingTitle, "The FBI Files Part II"},
+		{"DON'T PANIC", CasingTitle, "Don't Panic"},
+		{"o'brien's sci-fi (unabridged)", CasingTitle, "O'Brien's Sci-Fi (Unabridged)"},
+		{"J.R.R. TOLKIEN", CasingTitle, "J.R.R. Tolkien"},
+		{"ANNE MCCAFFREY", CasingTitle, "Anne McCaffrey"},
+		{"the iPhone and NASA story", CasingTitle, "The iPhone and NASA Story"},
+		{"THE WAY OF KINGS", CasingSentence, "The way of kings"},
+		{"The Hobbit: Or There And Back Again", CasingSentence, "The hobbit: Or there and back again"},
+		{"WHAT I SAW IN THE UK", CasingSentence, "What I saw in the UK"},
+		{"THE WAY OF KINGS", CasingPreserve, "THE WAY OF KINGS"},
+		{"the way of kings", "", "the way of kings"},
+		{"", CasingTitle, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.casing+"/"+tt.input, func(t *testing.T) {
+			if got := ApplyCasing(tt.input, tt.casing); got != tt.expected {
+				t.Errorf("ApplyCasing(%q, %q) = %q, want %q", tt.input, tt.casing, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLayoutCasing(t *testing.T) {
+	metadata := Metadata{
+		Title:   "THE FINAL EMPIRE",
+		Authors: []string{"BRANDON SANDERSON"},
+		Series:  []string{"mistborn #1"},
+	}
+	layout := Layout{Name: "author-series-title-number", Casing: CasingSentence}
+
+	got, err := layout.TargetDir(metadata, "/books")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("/books", "Brandon Sanderson", "Mistborn", "#1 - The final empire"); got != want {
+		t.Errorf("TargetDir() = %q, want %q (authors are never sentence cased)", got, want)
+	}
+	if metadata.Authors[0] != "BRANDON SANDERSON" {
+		t.Errorf("TargetDir() changed the caller's metadata: %v", metadata.Authors)
+	}
+}
@@ -0,0 +1,91 @@
+package planning
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Status of a field mapping candidate in a FieldExplanation
+const (
+	CandidateUsed     = "used"      // Supplied the mapped value
+	CandidateEmpty    = "empty"     // Tried, but had no value
+	CandidateNotTried = "not_tried" // After the candidate that supplied the value
+)
+
+// CandidateResult is what one field mapping candidate gave for a file
+type CandidateResult struct {
+	Candidate string `json:"candidate"`
+	Status    string `json:"status"`          // CandidateUsed, CandidateEmpty, or CandidateNotTried
+	Value     string `json:"value,omitempty"` // Set when Status is CandidateUsed
+}
+
+// FieldExplanation tells which raw field supplied one mapped field of a file
+type FieldExplanation struct {
+	Field      string            `json:"field"` // "title", "authors", "series", or "track"
+	Value      string            `json:"value,omitempty"`
+	Source     string            `json:"source,omitempty"` // Candidate that supplied Value; "" when kept as the metadata source read it
+	Candidates []CandidateResult `json:"candidates"`
+}
+
+// ExplainFieldMapping reports, for the title, authors, series, and track, which
+// candidate of mapping ApplyFieldMapping takes the value from and which it tries
+// and skips on the way. It is called on metadata as read, before the mapping is
+// applied, and leaves it unchanged.
+func (m Metadata) ExplainFieldMapping(mapping FieldMapping) []FieldExplanation {
+	mapped := m
+	mapped.fieldSources = nil // Not shared with m
+	mapped.ApplyFieldMapping(mapping)
+	originalTitle := m.Title
+
+	title := explainCandidates("title", mapping.TitleField, func(candidate string) string {
+		return m.titleCandidate(candidate)
+	})
+	title.Value = mapped.Title
+
+	series := explainCandidates("series", mapping.SeriesField, func(candidate string) string {
+		return strings.Join(m.seriesCandidate(candidate, originalTitle), ", ")
+	})
+	series.Value = strings.Join(mapped.Series, ", ")
+
+	authors := FieldExplanation{Field: "authors", Value: strings.Join(mapped.Authors, ", "), Candidates: []CandidateResult{}}
+	var sources []string
+	for _, field := range mapping.AuthorFields {
+		result := CandidateResult{Candidate: field, Status: CandidateEmpty}
+		if val := m.getRawValue(field); val != "" {
+			result.Status, result.Value = CandidateUsed, val
+			sources = append(sources, field)
+		}
+		authors.Candidates = append(authors.Candidates, result)
+	}
+	authors.Source = strings.Join(sources, ", ")
+
+	track := explainCandidates("track", mapping.TrackField, func(candidate string) string {
+		if num := m.trackCandidate(candidate); num > 0 {
+			return strconv.Itoa(num)
+		}
+		return ""
+	})
+	if mapped.TrackNumber > 0 {
+		track.Value = strconv.Itoa(mapped.TrackNumber)
+	}
+
+	return []FieldExplanation{title, authors, series, track}
+}
+
+// explainCandidates tries the candidates of spec in order like ApplyFieldMapping,
+// taking the first one value returns something for
+func explainCandidates(field, spec string, value func(candidate string) string) FieldExplanation {
+	explanation := FieldExplanation{Field: field, Candidates: []CandidateResult{}}
+	for _, candidate := range FieldCandidates(spec) {
+		result := CandidateResult{Candidate: candidate, Status: CandidateNotTried}
+		if explanation.Source == "" {
+			result.Status = CandidateEmpty
+			if val := value(candidate); val != "" {
+				result.Status, result.Value = CandidateUsed, val
+				explanation.Source = candidate
+			}
+		}
+		explanation.Candidates = append(explanation.Candidates, result)
+	}
+	return explanation
+}
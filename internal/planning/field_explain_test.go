@@ -0,0 +1,60 @@
+package planning
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplainFieldMapping(t *testing.T) {
+	metadata := Metadata{
+		Title:       "Chapter 1",
+		Album:       "Dune",
+		TrackNumber: 3,
+		RawData: map[string]interface{}{
+			"title":        "Chapter 1",
+			"album":        "Dune",
+			"artist":       "Frank Herbert",
+			"album_artist": "",
+		},
+	}
+	mapping := FieldMapping{
+		TitleField:   "album,title",
+		SeriesField:  "series,mvnm,=Standalone,album",
+		AuthorFields: []string{"album_artist", "artist"},
+		TrackField:   "trck,track",
+	}
+
+	got := metadata.ExplainFieldMapping(mapping)
+	want := []FieldExplanation{
+		{Field: "title", Value: "Dune", Source: "album", Candidates: []CandidateResult{
+			{Candidate: "album", Status: CandidateUsed, Value: "Dune"},
+			{Candidate: "title", Status: CandidateNotTried},
+		}},
+		{Field: "authors", Value: "Frank Herbert", Source: "artist", Candidates: []CandidateResult{
+			{Candidate: "album_artist", Status: CandidateEmpty},
+			{Candidate: "artist", Status: CandidateUsed, Value: "Frank Herbert"},
+		}},
+		{Field: "series", Value: "Standalone", Source: "=Standalone", Candidates: []CandidateResult{
+			{Candidate: "series", Status: CandidateEmpty},
+			{Candidate: "mvnm", Status: CandidateEmpty},
+			{Candidate: "=Standalone", Status: CandidateUsed, Value: "Standalone"},
+			{Candidate: "album", Status: CandidateNotTried},
+		}},
+		{Field: "track", Value: "3", Source: "track", Candidates: []CandidateResult{
+			{Candidate: "trck", Status: CandidateEmpty},
+			{Candidate: "track", Status: CandidateUsed, Value: "3"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExplainFieldMapping() =\n%+v\nwant\n%+v", got, want)
+	}
+	if metadata.Title != "Chapter 1" || len(metadata.Series) != 0 {
+		t.Errorf("ExplainFieldMapping changed the metadata: %+v", metadata)
+	}
+
+	// Fields the mapping leaves alone keep the value as read
+	got = metadata.ExplainFieldMapping(FieldMapping{TitleField: "subtitle"})
+	if got[0].Value != "Chapter 1" || got[0].Source != "" || got[0].Candidates[0].Status != CandidateEmpty {
+		t.Errorf("title explanation = %+v, want the title as read", got[0])
+	}
+}
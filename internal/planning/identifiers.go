@@ -0,0 +1,172 @@
+package planning
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// isbnFields and asinFields name the raw fields that hold an identifier and nothing
+// else, matched ignoring case: metadata.json keys, audio tags such as the ASIN
+// freeform atom of Audible files, and the fields of an Audiobookshelf item.
+var (
+	isbnFields = []string{"isbn", "isbn_13", "isbn13", "isbn_10", "isbn10", "abs_isbn"}
+	asinFields = []string{"asin", "audible_asin", "audibleasin", "abs_asin"}
+)
+
+// identifierTextFields name free-text fields that may mention an identifier among
+// other text, such as "ASIN: B00DEKDOTC" in a comment tag or "urn:isbn:..." in an
+// EPUB identifier. Only labelled or well-formed identifiers are taken from them.
+var identifierTextFields = []string{"identifiers", "identifier", "comment", "description"}
+
+var (
+	isbnPattern         = regexp.MustCompile(`(?i)\b(?:97[89][- ]?)?\d{1,5}[- ]?\d{1,7}[- ]?\d{1,7}[- ]?[\dX]\b`)
+	labelledISBNPattern = regexp.MustCompile(`(?i)isbn(?:[- ]?1[03])?[:\s]+([\d][\d\- X]{8,16}[\dX])`)
+	asinPattern         = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+	labelledASINPattern = regexp.MustCompile(`(?i)\basin[:\s]+([A-Z0-9]{10})\b`)
+	audibleASINPattern  = regexp.MustCompile(`\bB0[A-Z0-9]{8}\b`)
+)
+
+// FillIdentifiers sets ISBN and ASIN from the raw fields when they aren't set yet.
+// ApplyFieldMapping calls it, so metadata only needs it when it isn't mapped.
+func (m *Metadata) FillIdentifiers() {
+	if m.ISBN == "" {
+		m.ISBN = m.findISBN()
+	}
+	if m.ASIN == "" {
+		m.ASIN = m.findASIN()
+	}
+}
+
+func (m *Metadata) findISBN() string {
+	for _, field := range isbnFields {
+		if isbn := NormalizeISBN(m.rawValueFold(field)); isbn != "" {
+			return isbn
+		}
+	}
+	for _, field := range identifierTextFields {
+		text := m.rawValueFold(field)
+		if match := labelledISBNPattern.FindStringSubmatch(text); match != nil {
+			if isbn := NormalizeISBN(match[1]); isbn != "" {
+				return isbn
+			}
+		}
+		// EPUB identifiers hold nothing but the identifier, so an unlabelled
+		// number is trusted there when its check digit is right
+		if field == "identifiers" || field == "identifier" {
+			for _, candidate := range isbnPattern.FindAllString(text, -1) {
+				if isbn := NormalizeISBN(candidate); isbn != "" {
+					return isbn
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (m *Metadata) findASIN() string {
+	for _, field := range asinFields {
+		if asin := strings.ToUpper(strings.TrimSpace(m.rawValueFold(field))); asinPattern.MatchString(asin) {
+			return asin
+		}
+	}
+	for _, field := range identifierTextFields {
+		text := m.rawValueFold(field)
+		if match := labelledASINPattern.FindStringSubmatch(text); match != nil {
+			return strings.ToUpper(match[1])
+		}
+		if asin := audibleASINPattern.FindString(text); asin != "" {
+			return asin
+		}
+	}
+	return ""
+}
+
+// rawValueFold returns the raw field named field, ignoring case, as a string
+func (m *Metadata) rawValueFold(field string) string {
+	if _, ok := m.RawData[field]; ok {
+		return rawIdentifierString(m.RawData[field])
+	}
+	for key, value := range m.RawData {
+		if strings.EqualFold(key, field) {
+			return rawIdentifierString(value)
+		}
+	}
+	return ""
+}
+
+// rawIdentifierString flattens a raw value into text; lists are joined by spaces
+func rawIdentifierString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, " ")
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, " ")
+	case float64:
+		// A bare ISBN in metadata.json decodes as a number
+		return fmt.Sprintf("%.0f", v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// NormalizeISBN strips hyphens and spaces from an ISBN-10 or ISBN-13 and returns it
+// when its check digit is valid, or "" otherwise
+func NormalizeISBN(value string) string {
+	value = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "urn:isbn:")
+	var digits strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == 'x' || r == 'X':
+			digits.WriteRune('X')
+		case r == '-' || r == ' ':
+		default:
+			return ""
+		}
+	}
+
+	isbn := digits.String()
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			digit := int(r - '0')
+			if r == 'X' {
+				if i != 9 {
+					return ""
+				}
+				digit = 10
+			}
+			sum += (10 - i) * digit
+		}
+		if sum%11 == 0 {
+			return isbn
+		}
+	case 13:
+		if strings.Contains(isbn, "X") {
+			return ""
+		}
+		sum := 0
+		for i, r := range isbn {
+			digit := int(r - '0')
+			if i%2 == 1 {
+				digit *= 3
+			}
+			sum += digit
+		}
+		if sum%10 == 0 {
+			return isbn
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package planning
+
+import "testing"
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"978-0-7653-2635-5", "9780765326355"},
+		{"urn:isbn:9780765326355", "9780765326355"},
+		{"0-7653-2635-3", "0765326353"},
+		{"080442957X", "080442957X"},
+		{"9780765326356", ""}, // bad check digit
+		{"12345", ""},
+		{"ISBN 9780765326355", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeISBN(tt.input); got != tt.want {
+			t.Errorf("NormalizeISBN(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFillIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      map[string]interface{}
+		wantISBN string
+		wantASIN string
+	}{
+		{
+			name:     "metadata.json fields",
+			raw:      map[string]interface{}{"isbn": "978-0-7653-2635-5", "asin": "b003zwfo7e"},
+			wantISBN: "9780765326355",
+			wantASIN: "B003ZWFO7E",
+		},
+		{
+			name:     "ISBN decoded as a JSON number",
+			raw:      map[string]interface{}{"isbn": float64(9780765326355)},
+			wantISBN: "9780765326355",
+		},
+		{
+			name:     "audio tag names keep their case",
+			raw:      map[string]interface{}{"ASIN": "B003ZWFO7E", "AUDIBLE_ASIN": "B000000000"},
+			wantASIN: "B003ZWFO7E",
+		},
+		{
+			name:     "EPUB identifiers with schemes",
+			raw:      map[string]interface{}{"identifiers": []string{"uuid:1b2c3d", "ISBN:0765326353"}},
+			wantISBN: "0765326353",
+		},
+		{
+			name:     "labelled identifiers in a comment",
+			raw:      map[string]interface{}{"comment": "Audible release. ASIN: B003ZWFO7E, ISBN-13: 978-0-7653-2635-5"},
+			wantISBN: "9780765326355",
+			wantASIN: "B003ZWFO7E",
+		},
+		{
+			name: "unlabelled numbers in a comment are ignored",
+			raw:  map[string]interface{}{"comment": "Ripped 2019, 9780765326355 bitrate 64"},
+		},
+		{
+			name: "invalid values are ignored",
+			raw:  map[string]interface{}{"isbn": "not an isbn", "asin": "too-short"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := Metadata{RawData: tt.raw}
+			metadata.FillIdentifiers()
+			if metadata.ISBN != tt.wantISBN {
+				t.Errorf("ISBN = %q, want %q", metadata.ISBN, tt.wantISBN)
+			}
+			if metadata.ASIN != tt.wantASIN {
+				t.Errorf("ASIN = %q, want %q", metadata.ASIN, tt.wantASIN)
+			}
+		})
+	}
+}
+
+func TestApplyFieldMappingFillsIdentifiers(t *testing.T) {
+	metadata := Metadata{
+		Title:   "The Way of Kings",
+		ISBN:    "9780765326355",
+		RawData: map[string]interface{}{"isbn": "0765326353", "asin": "B003ZWFO7E"},
+	}
+	metadata.ApplyFieldMapping(DefaultFieldMapping())
+	if metadata.ISBN != "9780765326355" {
+		t.Errorf("ISBN = %q, want the one already set", metadata.ISBN)
+	}
+	if metadata.ASIN != "B003ZWFO7E" {
+		t.Errorf("ASIN = %q, want B003ZWFO7E", metadata.ASIN)
+	}
+}
@@ -0,0 +1,212 @@
+package planning
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Layout describes how a book's metadata becomes a directory below the output base
+type Layout struct {
+	Name         string              // Built-in layout such as "author-series-title"
+	Template     string              // Custom layout template, overrides Name when set
+	AuthorFormat string              // "first-last", "last-first" or "preserve" for templates
+	Casing       string              // CasingTitle or CasingSentence rewrites tag casing; see ApplyCasing
+	Subtitle     string              // SubtitleColon, SubtitleDash, or SubtitleDrop rewrites the title; see ApplySubtitleStyle
+	StripTitle   bool                // Drop a title prefix repeating the author or series; see StripRedundantTitle
+	Sanitize     func(string) string // Cleans each rendered path component
+	Initial      func(string) string // Letter a name is filed under in templates; defaults to NameInitial
+}
+
+// PathMetadata returns metadata as path components use it: the title with its
+// subtitle in the subtitle style and without a redundant prefix when stripTitle is
+// set, and every field in casing
+func PathMetadata(metadata Metadata, casing, subtitle string, stripTitle bool) Metadata {
+	metadata.Title = ApplySubtitleStyle(metadata, subtitle)
+	if stripTitle {
+		metadata.Title, _ = StripRedundantTitle(metadata)
+	}
+	return ApplyMetadataCasing(metadata, casing)
+}
+
+// TargetDir returns the directory for a book below targetBase
+func (l Layout) TargetDir(metadata Metadata, targetBase string) (string, error) {
+	metadata = PathMetadata(metadata, l.Casing, l.Subtitle, l.StripTitle)
+	if strings.TrimSpace(l.Template) != "" {
+		return l.customTemplatePath(metadata, targetBase)
+	}
+
+	// A name that sanitizes to nothing, such as a title of only dots, would otherwise
+	// drop out of the path and put the book in the folder above
+	var err error
+	dir := func(field, value string) string {
+		name := l.sanitize(value)
+		if err == nil && (name == "" || name == "." || name == "..") {
+			err = fmt.Errorf("%s %q leaves no usable folder name", field, value)
+		}
+		return name
+	}
+	authorDir := func() string { return dir("author", strings.Join(metadata.Authors, ",")) }
+	titleDir := func() string { return dir("title", metadata.Title) }
+
+	var path string
+	switch l.Name {
+	case "author-only":
+		path = filepath.Join(targetBase, authorDir())
+	case "author-series":
+		// Author/Series layout (no title subdirectory)
+		// Used for multi-file audiobooks where each file is a chapter
+		if validSeries := metadata.GetValidSeries(); validSeries != "" {
+			path = filepath.Join(targetBase, authorDir(), dir("series", validSeries))
+		} else {
+			// If no series, fall back to author/title
+			path = filepath.Join(targetBase, authorDir(), titleDir())
+		}
+	case "author-title":
+		path = filepath.Join(targetBase, authorDir(), titleDir())
+	case "author-series-title", "":
+		path = filepath.Join(targetBase, authorDir(), l.seriesPath(titleDir(), metadata))
+	case "author-series-title-number":
+		path = filepath.Join(
+			targetBase,
+			authorDir(),
+			l.seriesPathWithNumber(titleDir(), metadata),
+		)
+	case "series-title":
+		path = filepath.Join(targetBase, l.seriesPath(titleDir(), metadata))
+	case "series-title-number":
+		path = filepath.Join(targetBase, l.seriesPathWithNumber(titleDir(), metadata))
+	default:
+		path = filepath.Join(targetBase, authorDir(), titleDir())
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// HasBookFolder reports whether the folder TargetDir gives a book is the book's own.
+// It is not for author-only, for author-series when the book has a series, and for
+// templates whose last folder doesn't render {title}.
+func (l Layout) HasBookFolder(metadata Metadata) bool {
+	if template := strings.TrimSpace(l.Template); template != "" {
+		segments := splitLayoutTemplateSegments(template)
+		if len(segments) == 0 {
+			return false
+		}
+		parsed, err := ParseTemplate(segments[len(segments)-1])
+		return err == nil && parsed.UsesField("title")
+	}
+	switch l.Name {
+	case "author-only":
+		return false
+	case "author-series":
+		return metadata.GetValidSeries() == ""
+	}
+	return true
+}
+
+func (l Layout) sanitize(s string) string {
+	if l.Sanitize == nil {
+		return s
+	}
+	return l.Sanitize(s)
+}
+
+func (l Layout) customTemplatePath(metadata Metadata, targetBase string) (string, error) {
+	template := strings.TrimSpace(l.Template)
+	if filepath.IsAbs(template) || filepath.VolumeName(template) != "" ||
+		startsWithPathSeparator(template) {
+		return "", fmt.Errorf("layout template must be relative")
+	}
+
+	segments := splitLayoutTemplateSegments(template)
+	pathSegments := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if segment == "." || segment == ".." {
+			return "", fmt.Errorf("layout template must not contain traversal segment %q", segment)
+		}
+
+		rendered, err := l.renderTemplateSegment(segment, metadata)
+		if err != nil {
+			return "", err
+		}
+		rendered = strings.TrimSpace(rendered)
+		if rendered == "" {
+			continue
+		}
+		if rendered == "." || rendered == ".." {
+			return "", fmt.Errorf("layout template rendered unsafe segment %q", rendered)
+		}
+		pathSegments = append(pathSegments, l.sanitize(rendered))
+	}
+	if len(pathSegments) == 0 {
+		return "", fmt.Errorf("layout template rendered no usable path segments")
+	}
+
+	return filepath.Join(append([]string{targetBase}, pathSegments...)...), nil
+}
+
+func (l Layout) renderTemplateSegment(segment string, metadata Metadata) (string, error) {
+	template, err := ParseTemplate(segment)
+	if err != nil {
+		return "", err
+	}
+	renderer := NewTemplateRenderer(template, NewAuthorFormatter(parseAuthorFormat(l.AuthorFormat)))
+	renderer.SetInitial(l.Initial)
+	return renderer.Render(metadata)
+}
+
+// parseAuthorFormat maps an author format name to an AuthorFormat, defaulting to first-last
+func parseAuthorFormat(authorFormat string) AuthorFormat {
+	switch strings.ToLower(strings.TrimSpace(authorFormat)) {
+	case "last-first":
+		return AuthorFormatLastFirst
+	case "preserve":
+		return AuthorFormatPreserve
+	default:
+		return AuthorFormatFirstLast
+	}
+}
+
+func splitLayoutTemplateSegments(template string) []string {
+	return strings.FieldsFunc(template, func(r rune) bool {
+		return r == '/' || r == '\\'
+	})
+}
+
+func startsWithPathSeparator(template string) bool {
+	return strings.HasPrefix(template, "/") || strings.HasPrefix(template, "\\")
+}
+
+// seriesPath handles series-based path calculation
+// Returns the series/title portion of the path (e.g., "Series/Title" or just "Title")
+func (l Layout) seriesPath(titleDir string, metadata Metadata) string {
+	if validSeries := metadata.GetValidSeries(); validSeries != "" {
+		seriesDir := l.sanitize(validSeries)
+		return filepath.Join(seriesDir, titleDir)
+	}
+	return titleDir
+}
+
+// seriesPathWithNumber handles series-based path calculation with series number in title
+// Returns the series/title portion of the path (e.g., "Series/#1 - Title" or just "Title")
+func (l Layout) seriesPathWithNumber(titleDir string, metadata Metadata) string {
+	if validSeries := metadata.GetValidSeries(); validSeries != "" {
+		seriesDir := l.sanitize(validSeries)
+
+		// Get series number and prefix the title with it
+		seriesNumber := GetSeriesNumberFromMetadata(metadata)
+		if seriesNumber != "" {
+			numberedTitle := fmt.Sprintf("#%s - %s", seriesNumber, titleDir)
+			return filepath.Join(seriesDir, numberedTitle)
+		}
+
+		// If no series number, fall back to regular series path
+		return filepath.Join(seriesDir, titleDir)
+	}
+	return titleDir
+}
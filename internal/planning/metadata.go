@@ -0,0 +1,507 @@
+// Package planning holds the pure planning rules of the organizer: metadata and
+// field mapping, layout templates, author formatting, and path sanitization. It
+// depends only on the standard library so it can be compiled to WebAssembly.
+package planning
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InvalidSeriesValue marks a series that must be ignored when building paths
+const InvalidSeriesValue = "__INVALID_SERIES__"
+
+// FieldMapping defines how fields map to our final fields. The title, series, and
+// track fields may list comma-separated fallbacks tried in order, and a candidate
+// starting with FieldLiteralPrefix is a literal value: "series,mvnm,=Standalone".
+type FieldMapping struct {
+	TitleField      string   `json:"title_field,omitempty"`       // "title", "album", "series", "album,title"
+	SeriesField     string   `json:"series_field,omitempty"`      // "series", "album", "series,mvnm,album"
+	AuthorFields    []string `json:"author_fields,omitempty"`     // ["artist", "album_artist"] or ["authors"]
+	TrackField      string   `json:"track_field,omitempty"`       // "track", "track_number", "trck", "trk"
+	DiscField       string   `json:"disc_field,omitempty"`        // "disc", "discnumber", "disk", "tpos"
+	TrackTitleField string   `json:"track_title_field,omitempty"` // Chapter name for track-title file names: "track_title", "subtitle"
+}
+
+// FieldLiteralPrefix marks a field mapping candidate as a literal value instead of a
+// field name, so "=Standalone" supplies "Standalone" when no earlier candidate has a value
+const FieldLiteralPrefix = "="
+
+// FieldCandidates splits a title, series, or track mapping into its ordered
+// candidates: "series, mvnm, album" tries series, then mvnm, then album
+func FieldCandidates(spec string) []string {
+	var candidates []string
+	for _, candidate := range strings.Split(spec, ",") {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// fieldLiteral returns the value of a literal candidate
+func fieldLiteral(candidate string) (string, bool) {
+	if !strings.HasPrefix(candidate, FieldLiteralPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(candidate, FieldLiteralPrefix)), true
+}
+
+// IsEmpty returns true if the field mapping is empty
+func (fm FieldMapping) IsEmpty() bool {
+	return fm.TitleField == "" && fm.SeriesField == "" && len(fm.AuthorFields) == 0 &&
+		fm.TrackField == "" &&
+		fm.DiscField == "" &&
+		fm.TrackTitleField == ""
+}
+
+// DefaultFieldMapping returns the default field mapping
+func DefaultFieldMapping() FieldMapping {
+	return FieldMapping{
+		TitleField:   "title",
+		SeriesField:  "series",
+		AuthorFields: []string{"authors"},
+		TrackField:   "track",
+	}
+}
+
+// AudioFieldMapping returns field mapping for audio files
+func AudioFieldMapping() FieldMapping {
+	return FieldMapping{
+		TitleField:   "title",
+		SeriesField:  "album",
+		AuthorFields: []string{"artist", "album_artist"},
+		TrackField:   "track",
+	}
+}
+
+// EpubFieldMapping returns field mapping for EPUB files
+func EpubFieldMapping() FieldMapping {
+	return FieldMapping{
+		TitleField:   "title",
+		SeriesField:  "series",
+		AuthorFields: []string{"authors"},
+		TrackField:   "track",
+	}
+}
+
+// Metadata contains the essential fields we need for audiobook organization
+type Metadata struct {
+	// Core identification fields
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Series      []string `json:"series"`
+	TrackNumber int      `json:"track_number,omitempty"`
+
+	// Additional core fields
+	Album      string `json:"album,omitempty"`
+	TrackTitle string `json:"track_title,omitempty"`
+
+	// Book identifiers found in the source, normalized without hyphens
+	ISBN string `json:"isbn,omitempty"`
+	ASIN string `json:"asin,omitempty"`
+
+	// Book details, such as those of an Audiobookshelf metadata.json (see FillDetails)
+	Subtitle    string    `json:"subtitle,omitempty"`
+	Narrators   []string  `json:"narrators,omitempty"`
+	Publisher   string    `json:"publisher,omitempty"`
+	Year        int       `json:"published_year,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Chapters    []Chapter `json:"chapters,omitempty"`
+
+	// Source information
+	SourceType string `json:"source_type"` // "epub", "audio", "json"
+	SourcePath string `json:"source_path"`
+
+	// Audio stream of the source file, when it was read from the audio headers
+	Audio *AudioInfo `json:"audio,omitempty"`
+
+	// Raw data from the source for field mapping and advanced use
+	RawData map[string]interface{} `json:"raw_data,omitempty"`
+
+	// Field mapping configuration (moved from embedded to separate processor)
+	fieldMapping FieldMapping
+	// Candidates that supplied the mapped title, series, and track
+	fieldSources map[string]string
+}
+
+// NewMetadata creates a new Metadata instance
+func NewMetadata() Metadata {
+	return Metadata{
+		RawData: make(map[string]interface{}),
+	}
+}
+
+// GetFirstAuthor returns the first author or a default value if no authors exist
+func (m *Metadata) GetFirstAuthor(defaultValue string) string {
+	if len(m.Authors) > 0 && m.Authors[0] != "" {
+		return m.Authors[0]
+	}
+	return defaultValue
+}
+
+// GetFullValidSeries returns the first valid series name with series number intact
+// Sorts the series array to ensure consistent series choice
+func (m *Metadata) GetFullValidSeries() string {
+	sort.Strings(m.Series)
+	if len(m.Series) > 0 && m.Series[0] != "" && m.Series[0] != InvalidSeriesValue {
+		return m.Series[0]
+	}
+	return ""
+}
+
+// GetValidSeries returns the first valid series name, cleaning it of series numbers
+func (m *Metadata) GetValidSeries() string {
+	return CleanSeriesName(m.GetFullValidSeries())
+}
+
+// GetNarrators returns the narrators, read from the "narrators" list or "narrator"
+// field unless FillDetails set them
+func (m *Metadata) GetNarrators() []string {
+	return narratorValuesFromMetadata(*m)
+}
+
+// GetDuration returns the playing time in seconds from the "duration" field or the
+// audio stream, or 0 when neither is known
+func (m *Metadata) GetDuration() float64 {
+	switch duration := rawTemplateValue(*m, "duration").(type) {
+	case float64:
+		return duration
+	case int:
+		return float64(duration)
+	}
+	if m.Audio != nil {
+		return m.Audio.Duration
+	}
+	return 0
+}
+
+// IsValid checks if metadata contains the minimum required fields
+func (m *Metadata) IsValid() bool {
+	return m.Title != "" && len(m.Authors) > 0 && m.Authors[0] != ""
+}
+
+// Validate ensures that essential metadata fields (title and authors) are present
+func (m *Metadata) Validate() error {
+	if len(m.Authors) == 0 || m.Authors[0] == "" {
+		return fmt.Errorf("missing author information")
+	}
+
+	if m.Title == "" {
+		return fmt.Errorf("missing title information")
+	}
+
+	return nil
+}
+
+// ApplyFieldMapping applies the field mapping configuration to set the final fields
+func (m *Metadata) ApplyFieldMapping(mapping FieldMapping) {
+	m.fieldMapping = mapping
+
+	// Store original title for potential use in series mapping
+	originalTitle := m.Title
+
+	// Apply title field mapping, taking the first candidate with a value
+	for _, candidate := range FieldCandidates(mapping.TitleField) {
+		if val := m.titleCandidate(candidate); val != "" {
+			m.Title = val
+			m.setFieldSource("title", candidate)
+			break
+		}
+	}
+
+	// Apply series field mapping
+	for _, candidate := range FieldCandidates(mapping.SeriesField) {
+		if series := m.seriesCandidate(candidate, originalTitle); len(series) > 0 {
+			m.Series = series
+			m.setFieldSource("series", candidate)
+			break
+		}
+	}
+
+	// Apply author field mapping
+	if len(mapping.AuthorFields) > 0 {
+		var allAuthors []string
+		for _, field := range mapping.AuthorFields {
+			if val := m.getRawValue(field); val != "" {
+				// Split authors by common delimiters if needed
+				authors := splitAuthors(val)
+				for _, author := range authors {
+					if !contains(allAuthors, author) {
+						allAuthors = append(allAuthors, author)
+					}
+				}
+			}
+		}
+		if len(allAuthors) > 0 {
+			m.Authors = allAuthors
+		}
+	}
+
+	// Apply track title field mapping
+	for _, candidate := range FieldCandidates(mapping.TrackTitleField) {
+		if val := m.trackTitleCandidate(candidate, originalTitle); val != "" {
+			m.TrackTitle = val
+			m.setFieldSource("track_title", candidate)
+			break
+		}
+	}
+
+	// Apply track field mapping
+	for _, candidate := range FieldCandidates(mapping.TrackField) {
+		if track := m.trackCandidate(candidate); track > 0 {
+			m.TrackNumber = track
+			m.setFieldSource("track", candidate)
+			break
+		}
+	}
+
+	m.FillIdentifiers()
+	m.FillDetails()
+}
+
+// titleCandidate returns the title a field mapping candidate supplies, or ""
+func (m *Metadata) titleCandidate(candidate string) string {
+	if literal, ok := fieldLiteral(candidate); ok {
+		return literal
+	}
+	switch candidate {
+	case "title":
+		return m.Title
+	case "series":
+		if len(m.Series) > 0 {
+			return m.Series[0]
+		}
+		return ""
+	case "album":
+		return m.Album
+	case "track_title":
+		return m.TrackTitle
+	default:
+		return m.getRawValue(candidate)
+	}
+}
+
+// seriesCandidate returns the series a field mapping candidate supplies, or nil
+func (m *Metadata) seriesCandidate(candidate, originalTitle string) []string {
+	if literal, ok := fieldLiteral(candidate); ok {
+		if literal == "" {
+			return nil
+		}
+		return []string{literal}
+	}
+	switch candidate {
+	case "series":
+		if len(m.Series) > 0 && m.Series[0] != "" {
+			return m.Series
+		}
+	case "title":
+		if originalTitle != "" {
+			return []string{originalTitle}
+		}
+	default:
+		if val := m.getRawValue(candidate); val != "" {
+			return []string{val}
+		}
+	}
+	return nil
+}
+
+// trackTitleCandidate returns the track title a field mapping candidate supplies, or ""
+func (m *Metadata) trackTitleCandidate(candidate, originalTitle string) string {
+	if literal, ok := fieldLiteral(candidate); ok {
+		return literal
+	}
+	switch candidate {
+	case "title":
+		return originalTitle
+	case "track_title":
+		return m.TrackTitle
+	default:
+		return m.getRawValue(candidate)
+	}
+}
+
+// trackCandidate returns the track number a field mapping candidate supplies, or 0
+func (m *Metadata) trackCandidate(candidate string) int {
+	if literal, ok := fieldLiteral(candidate); ok {
+		num, _ := strconv.Atoi(literal)
+		return num
+	}
+	if candidate == "track" {
+		return m.TrackNumber
+	}
+	switch v := m.RawData[candidate].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		// Try to parse string as int
+		if num, err := strconv.Atoi(v); err == nil {
+			return num
+		}
+	}
+	return 0
+}
+
+// setFieldSource records the candidate that supplied a mapped field
+func (m *Metadata) setFieldSource(field, candidate string) {
+	if m.fieldSources == nil {
+		m.fieldSources = make(map[string]string)
+	}
+	m.fieldSources[field] = candidate
+}
+
+// FieldSource returns the field mapping candidate that supplied the mapped "title",
+// "series", "track", or "track_title", or "" when the mapping left the field unchanged
+func (m *Metadata) FieldSource(field string) string {
+	return m.fieldSources[field]
+}
+
+// FormatFieldMappingAndValues returns a formatted string showing the current field mapping and values
+func (m *Metadata) FormatFieldMappingAndValues() string {
+	var sb strings.Builder
+
+	sb.WriteString("Field Mappings:\n")
+	sb.WriteString(fmt.Sprintf("  Title Field: %s\n", m.fieldMapping.TitleField))
+	sb.WriteString(fmt.Sprintf("  Series Field: %s\n", m.fieldMapping.SeriesField))
+	sb.WriteString(fmt.Sprintf("  Author Fields: %v\n", m.fieldMapping.AuthorFields))
+	sb.WriteString(fmt.Sprintf("  Track Field: %s\n", m.fieldMapping.TrackField))
+
+	sb.WriteString("\nCurrent Values:\n")
+	sb.WriteString(fmt.Sprintf("  Title: %s\n", m.Title))
+	if len(m.Series) > 0 {
+		sb.WriteString(fmt.Sprintf("  Series: %v\n", m.Series))
+	}
+	if len(m.Authors) > 0 {
+		sb.WriteString(fmt.Sprintf("  Authors: %v\n", m.Authors))
+	}
+	if m.TrackNumber > 0 {
+		sb.WriteString(fmt.Sprintf("  Track Number: %d\n", m.TrackNumber))
+	}
+
+	return sb.String()
+}
+
+// getRawValue safely extracts string values from raw data
+func (m *Metadata) getRawValue(field string) string {
+	if val, ok := m.RawData[field]; ok {
+		if strVal, ok := val.(string); ok {
+			return strVal
+		}
+		// Handle []string for authors
+		if sliceVal, ok := val.([]string); ok && len(sliceVal) > 0 {
+			return strings.Join(sliceVal, ", ")
+		}
+		// JSON metadata arrays decode to []interface{}.
+		if sliceVal, ok := val.([]interface{}); ok {
+			values := make([]string, 0, len(sliceVal))
+			for _, item := range sliceVal {
+				if strVal, ok := item.(string); ok {
+					values = append(values, strVal)
+				}
+			}
+			if len(values) > 0 {
+				return strings.Join(values, ", ")
+			}
+		}
+	}
+
+	// Handle special cases for built-in fields
+	switch field {
+	case "title":
+		return m.Title
+	case "album":
+		return m.Album
+	case "series":
+		if len(m.Series) > 0 {
+			return m.Series[0]
+		}
+	case "authors":
+		return strings.Join(m.Authors, ", ")
+	}
+
+	return ""
+}
+
+// splitAuthors splits a string containing multiple authors into a slice of individual authors
+// It handles common delimiters like semicolons, commas, and slashes
+func splitAuthors(authorsStr string) []string {
+	// Common delimiters: semicolon, comma, slash, newline, or multiple spaces
+	delimiters := []string{";", ",", "/", "\n", "  "}
+
+	// Replace all delimiters with a consistent delimiter
+	replaced := authorsStr
+	for _, delim := range delimiters[1:] {
+		replaced = strings.ReplaceAll(replaced, delim, delimiters[0])
+	}
+
+	// Split by the first delimiter
+	authors := strings.Split(replaced, delimiters[0])
+
+	// Clean up each author name
+	for i, author := range authors {
+		authors[i] = strings.TrimSpace(author)
+	}
+
+	// Remove any empty strings
+	var result []string
+	for _, author := range authors {
+		if author != "" {
+			result = append(result, author)
+		}
+	}
+
+	return result
+}
+
+// Helper function to check if a string is in a slice
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanSeriesName removes trailing series numbers (e.g., " #1") from series names.
+// This is now public so it can be used throughout the package.
+func CleanSeriesName(series string) string {
+	if idx := strings.LastIndex(series, " #"); idx != -1 {
+		return strings.TrimSpace(series[:idx])
+	}
+	return series
+}
+
+// ExtractSeriesNumber extracts the series number from a series string (e.g., "Mistborn #1" -> "1").
+// Returns an empty string if no series number is found.
+func ExtractSeriesNumber(series string) string {
+	if idx := strings.LastIndex(series, " #"); idx != -1 {
+		return strings.TrimSpace(series[idx+2:])
+	}
+	return ""
+}
+
+// GetSeriesNumberFromMetadata extracts the series number from metadata.
+// It first checks RawData for series_index, then falls back to parsing the series string.
+func GetSeriesNumberFromMetadata(metadata Metadata) string {
+	// First try to get series_index from RawData
+	if seriesIndex, ok := metadata.RawData["series_index"].(float64); ok && seriesIndex > 0 {
+		// Format as integer if it's a whole number, otherwise with decimal
+		if seriesIndex == float64(int(seriesIndex)) {
+			return fmt.Sprintf("%d", int(seriesIndex))
+		}
+		return fmt.Sprintf("%.1f", seriesIndex)
+	}
+
+	// Fall back to extracting from series string
+	if series := metadata.GetFullValidSeries(); series != "" {
+		return ExtractSeriesNumber(series)
+	}
+
+	return ""
+}
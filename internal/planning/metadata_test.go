@@ -1,4 +1,4 @@
-package organizer
+package planning
 
 import (
 	"testing"
@@ -320,3 +320,81 @@ func equalStringSlices(got, want []string) bool {
 	}
 	return true
 }
+
+func TestMetadataApplyFieldMappingFallbackChains(t *testing.T) {
+	tests := []struct {
+		name       string
+		mapping    FieldMapping
+		metadata   Metadata
+		wantTitle  string
+		wantSeries string
+		wantTrack  int
+		wantSource map[string]string
+	}{
+		{
+			name:    "later candidate supplies empty fields",
+			mapping: FieldMapping{TitleField: "title", SeriesField: "series, mvnm, album", TrackField: "track,trck"},
+			metadata: Metadata{
+				Title:   "Book",
+				Album:   "Album",
+				RawData: map[string]interface{}{"mvnm": "Saga", "trck": "7"},
+			},
+			wantTitle:  "Book",
+			wantSeries: "Saga",
+			wantTrack:  7,
+			wantSource: map[string]string{"title": "title", "series": "mvnm", "track": "trck"},
+		},
+		{
+			name:       "literal default",
+			mapping:    FieldMapping{SeriesField: "series,mvnm,=Standalone"},
+			metadata:   Metadata{Title: "Book"},
+			wantTitle:  "Book",
+			wantSeries: "Standalone",
+			wantSource: map[string]string{"series": "=Standalone"},
+		},
+		{
+			name:       "first candidate wins",
+			mapping:    FieldMapping{TitleField: "album,title", SeriesField: "series,=Standalone"},
+			metadata:   Metadata{Title: "Track 1", Album: "Book", Series: []string{"Saga"}},
+			wantTitle:  "Book",
+			wantSeries: "Saga",
+			wantSource: map[string]string{"title": "album", "series": "series"},
+		},
+		{
+			name:       "no candidate keeps original",
+			mapping:    FieldMapping{TitleField: "album,mvnm"},
+			metadata:   Metadata{Title: "Book"},
+			wantTitle:  "Book",
+			wantSource: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := tt.metadata
+			metadata.ApplyFieldMapping(tt.mapping)
+
+			if metadata.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", metadata.Title, tt.wantTitle)
+			}
+			if got := metadata.GetValidSeries(); got != tt.wantSeries {
+				t.Errorf("Series = %q, want %q", got, tt.wantSeries)
+			}
+			if metadata.TrackNumber != tt.wantTrack {
+				t.Errorf("TrackNumber = %d, want %d", metadata.TrackNumber, tt.wantTrack)
+			}
+			for _, field := range []string{"title", "series", "track"} {
+				if got := metadata.FieldSource(field); got != tt.wantSource[field] {
+					t.Errorf("FieldSource(%q) = %q, want %q", field, got, tt.wantSource[field])
+				}
+			}
+		})
+	}
+}
+
+func TestFieldCandidates(t *testing.T) {
+	got := FieldCandidates(" series, mvnm,,=Standalone ")
+	if want := []string{"series", "mvnm", "=Standalone"}; !equalStringSlices(got, want) {
+		t.Errorf("FieldCandidates() = %v, want %v", got, want)
+	}
+}
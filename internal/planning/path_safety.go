@@ -0,0 +1,110 @@
+package planning
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// reDrivePath matches a Windows drive path such as "C:\" or "c:/"
+var reDrivePath = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// UnsafePathError reports metadata that would name a path outside the output
+// directory, or a path component no file system should hold. The sanitizer would
+// mostly defuse such values, but a tag spelled like a path is more likely an attack
+// or a broken tagger than a name, so the book is left alone instead.
+type UnsafePathError struct {
+	Field  string // Metadata field, or "path" for the rendered target
+	Value  string
+	Reason string
+}
+
+func (e *UnsafePathError) Error() string {
+	return fmt.Sprintf("unsafe %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// CheckPathMetadata rejects the metadata fields that name folders and files when one
+// holds a ".." path element, looks like an absolute path, or holds a control character
+func CheckPathMetadata(metadata Metadata) error {
+	fields := []struct {
+		name   string
+		values []string
+	}{
+		{"title", []string{metadata.Title}},
+		{"subtitle", []string{metadata.Subtitle}},
+		{"album", []string{metadata.Album}},
+		{"author", metadata.Authors},
+		{"series", metadata.Series},
+		{"narrator", metadata.Narrators},
+	}
+	for _, field := range fields {
+		for _, value := range field.values {
+			if reason := unsafeValue(value); reason != "" {
+				return &UnsafePathError{Field: field.name, Value: value, Reason: reason}
+			}
+		}
+	}
+	return nil
+}
+
+// unsafeValue returns why a metadata value can't name a path, or "" when it can
+func unsafeValue(value string) string {
+	if strings.IndexFunc(value, isControl) >= 0 {
+		return "contains a control character"
+	}
+	if strings.HasPrefix(value, "/") || strings.HasPrefix(value, `\`) || reDrivePath.MatchString(value) {
+		return "looks like an absolute path"
+	}
+	for _, element := range strings.FieldsFunc(value, isSeparator) {
+		if strings.TrimSpace(element) == ".." {
+			return "contains a \"..\" path element"
+		}
+	}
+	return ""
+}
+
+// CheckComponent returns an error when name can't be one component of a target path:
+// it is empty, "." or "..", or holds a separator or control character
+func CheckComponent(name string) error {
+	reason := ""
+	switch {
+	case name == "":
+		reason = "is empty"
+	case name == "." || name == "..":
+		reason = "is a traversal element"
+	case strings.IndexFunc(name, isSeparator) >= 0:
+		reason = "contains a path separator"
+	case strings.IndexFunc(name, isControl) >= 0:
+		reason = "contains a control character"
+	default:
+		return nil
+	}
+	return &UnsafePathError{Field: "path component", Value: name, Reason: reason}
+}
+
+// CheckTargetPath returns an error unless target lies below base and each component
+// between them passes CheckComponent
+func CheckTargetPath(base, target string) error {
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return &UnsafePathError{Field: "path", Value: target, Reason: "is outside " + base}
+	}
+	if rel == "." {
+		return &UnsafePathError{Field: "path", Value: target, Reason: "is the output directory itself"}
+	}
+	for _, component := range strings.Split(rel, string(filepath.Separator)) {
+		if err := CheckComponent(component); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
@@ -0,0 +1,59 @@
+package planning
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPathMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		field    string // Empty when the metadata is safe
+	}{
+		{"plain", Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}}, ""},
+		{"dots and slashes in a name", Metadata{Title: "AC/DC... Live", Authors: []string{"C.S. Lewis"}}, ""},
+		{"parent title", Metadata{Title: "..", Authors: []string{"Frank Herbert"}}, "title"},
+		{"traversal author", Metadata{Title: "Dune", Authors: []string{"Frank Herbert", "../../etc"}}, "author"},
+		{"windows traversal series", Metadata{Title: "Dune", Series: []string{`Saga\ .. \x`}}, "series"},
+		{"absolute title", Metadata{Title: "/etc/cron.d"}, "title"},
+		{"drive album", Metadata{Title: "Dune", Album: `C:\Windows`}, "album"},
+		{"newline narrator", Metadata{Title: "Dune", Narrators: []string{"Scott\nBrick"}}, "narrator"},
+		{"nul subtitle", Metadata{Title: "Dune", Subtitle: "Book\x00One"}, "subtitle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPathMetadata(tt.metadata)
+			var unsafe *UnsafePathError
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("CheckPathMetadata() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &unsafe) || unsafe.Field != tt.field {
+				t.Fatalf("CheckPathMetadata() = %v, want an unsafe %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestCheckTargetPath(t *testing.T) {
+	base := filepath.Join(string(filepath.Separator)+"library", "out")
+	tests := []struct {
+		target string
+		safe   bool
+	}{
+		{filepath.Join(base, "Frank Herbert", "Dune"), true},
+		{filepath.Join(base, "..", "escaped"), false},
+		{filepath.Join(base, "..", "out2", "Dune"), false},
+		{base, false},
+		{filepath.Join(base, "Frank Herbert", "Du\tne"), false},
+	}
+	for _, tt := range tests {
+		if err := CheckTargetPath(base, tt.target); (err == nil) != tt.safe {
+			t.Errorf("CheckTargetPath(%q) = %v, want safe %v", tt.target, err, tt.safe)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package planning
+
+import "path/filepath"
+
+// PreviewRequest asks for the target paths of a set of books without touching a filesystem
+type PreviewRequest struct {
+	Layout           string        `json:"layout"`
+	LayoutTemplate   string        `json:"layout_template,omitempty"`
+	FilenameTemplate string        `json:"filename_template,omitempty"`
+	AuthorFormat     string        `json:"author_format,omitempty"`
+	ReplaceSpace     string        `json:"replace_space,omitempty"`
+	Casing           string        `json:"casing,omitempty"` // "preserve", "title", or "sentence"
+	StripTitle       bool          `json:"strip_title_prefix,omitempty"`
+	TargetOS         string        `json:"target_os,omitempty"` // GOOS rules used for sanitizing, defaults to "linux"
+	OutputDir        string        `json:"output_dir,omitempty"`
+	FieldMapping     FieldMapping  `json:"field_mapping,omitempty"`
+	Books            []PreviewBook `json:"books"`
+}
+
+// PreviewBook is one book of a PreviewRequest
+type PreviewBook struct {
+	SourcePath string   `json:"source_path"`
+	Metadata   Metadata `json:"metadata"`
+}
+
+// PreviewResult is the planned location of one book
+type PreviewResult struct {
+	SourcePath string `json:"source_path"`
+	TargetDir  string `json:"target_dir,omitempty"`
+	Filename   string `json:"filename,omitempty"` // Set when the request has a filename template
+	Error      string `json:"error,omitempty"`
+}
+
+// PreviewResponse holds one result per requested book, in request order
+type PreviewResponse struct {
+	Results []PreviewResult `json:"results"`
+	Error   string          `json:"error,omitempty"` // Set when the templates themselves are invalid
+}
+
+// Preview plans target directories and file names for the requested books. It is the
+// entry point used by the WebAssembly build of the planner.
+func Preview(request PreviewRequest) PreviewResponse {
+	naming := NamingPolicy{ReplaceSpace: request.ReplaceSpace, TargetOS: request.TargetOS}
+	if naming.TargetOS == "" {
+		naming.TargetOS = "linux"
+	}
+	layout := Layout{
+		Name:         request.Layout,
+		Template:     request.LayoutTemplate,
+		AuthorFormat: request.AuthorFormat,
+		Casing:       request.Casing,
+		StripTitle:   request.StripTitle,
+		Sanitize:     naming.Dir,
+	}
+
+	var renderer *TemplateRenderer
+	if request.FilenameTemplate != "" {
+		template, err := ParseTemplate(request.FilenameTemplate)
+		if err != nil {
+			return PreviewResponse{Results: []PreviewResult{}, Error: err.Error()}
+		}
+		renderer = NewTemplateRenderer(
+			template,
+			NewAuthorFormatter(parseAuthorFormat(request.AuthorFormat)),
+		)
+	}
+
+	results := make([]PreviewResult, 0, len(request.Books))
+	for _, book := range request.Books {
+		metadata := book.Metadata
+		if !request.FieldMapping.IsEmpty() {
+			metadata.ApplyFieldMapping(request.FieldMapping)
+		}
+
+		result := PreviewResult{SourcePath: book.SourcePath}
+		targetDir, err := layout.TargetDir(metadata, request.OutputDir)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.TargetDir = targetDir
+
+		if renderer != nil {
+			filename, err := RenderFilename(
+				renderer,
+				metadata,
+				filepath.Ext(book.SourcePath),
+				naming.ReplaceSpace,
+			)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Filename = filename
+		}
+		results = append(results, result)
+	}
+
+	return PreviewResponse{Results: results}
+}
@@ -0,0 +1,137 @@
+package planning
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPreview(t *testing.T) {
+	book := PreviewBook{
+		SourcePath: "/in/The Final Empire.m4b",
+		Metadata: Metadata{
+			Title:   "The Final Empire",
+			Authors: []string{"Brandon Sanderson"},
+			Series:  []string{"Mistborn #1"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		request      PreviewRequest
+		wantDir      string
+		wantFilename string
+		wantErr      bool
+	}{
+		{
+			name:    "built-in layout",
+			request: PreviewRequest{Layout: "author-series-title-number", OutputDir: "/books"},
+			wantDir: filepath.Join("/books", "Brandon Sanderson", "Mistborn", "#1 - The Final Empire"),
+		},
+		{
+			name: "custom template with filename",
+			request: PreviewRequest{
+				LayoutTemplate:   "{author}/{title}",
+				FilenameTemplate: "{author} - {title}",
+				AuthorFormat:     "last-first",
+				ReplaceSpace:     "_",
+			},
+			wantDir:      filepath.Join("Sanderson,_Brandon", "The_Final_Empire"),
+			wantFilename: "Sanderson,_Brandon_-_The_Final_Empire.m4b",
+		},
+		{
+			name:    "windows sanitizing",
+			request: PreviewRequest{Layout: "author-title", TargetOS: "windows"},
+			wantDir: filepath.Join("Brandon Sanderson", "The Final Empire"),
+		},
+		{
+			name:    "traversal in template",
+			request: PreviewRequest{LayoutTemplate: "../{title}"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Books = []PreviewBook{book}
+			response := Preview(tt.request)
+			if response.Error != "" {
+				t.Fatalf("Preview() error = %s", response.Error)
+			}
+			if len(response.Results) != 1 {
+				t.Fatalf("Preview() results = %d, want 1", len(response.Results))
+			}
+			result := response.Results[0]
+			if (result.Error != "") != tt.wantErr {
+				t.Fatalf("Preview() result error = %q, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.TargetDir != tt.wantDir {
+				t.Errorf("Preview() target dir = %q, want %q", result.TargetDir, tt.wantDir)
+			}
+			if result.Filename != tt.wantFilename {
+				t.Errorf("Preview() filename = %q, want %q", result.Filename, tt.wantFilename)
+			}
+		})
+	}
+}
+
+func TestPreviewInvalidFilenameTemplate(t *testing.T) {
+	response := Preview(PreviewRequest{FilenameTemplate: "{title} {}"})
+	if response.Error == "" {
+		t.Error("Preview() expected an error for an empty placeholder")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		input        string
+		replaceSpace string
+		goos         string
+		want         string
+	}{
+		{"AC/DC: Live?", "", "linux", "AC_DC_ Live"},
+		{"AC/DC: Live?", "", "darwin", "AC_DC_ Live?"},
+		{`a\b:c`, "", "windows", "a_b_c"},
+		{" .Title. ", "", "linux", "Title"},
+		{"Two Words", ".", "linux", "Two.Words"},
+	}
+
+	for _, tt := range tests {
+		if got := Sanitize(tt.input, tt.replaceSpace, tt.goos); got != tt.want {
+			t.Errorf("Sanitize(%q, %q, %q) = %q, want %q", tt.input, tt.replaceSpace, tt.goos, got, tt.want)
+		}
+	}
+}
+
+func TestNamingPolicy(t *testing.T) {
+	naming := NamingPolicy{ReplaceSpace: "_", TargetOS: "linux"}
+	if got := naming.Dir("Brandon Sanderson"); got != "Brandon_Sanderson" {
+		t.Errorf("Dir() = %q, want %q", got, "Brandon_Sanderson")
+	}
+	if got := naming.File("01 - Part One.mp3"); got != "01_-_Part_One.mp3" {
+		t.Errorf("File() = %q, want %q", got, "01_-_Part_One.mp3")
+	}
+	if got := (NamingPolicy{}).File("Part One.mp3"); got != "Part One.mp3" {
+		t.Errorf("File() without replacement = %q, want it unchanged", got)
+	}
+}
+
+func TestNamingPolicyEquivalent(t *testing.T) {
+	naming := NamingPolicy{ReplaceSpace: "_", TargetOS: "linux"}
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"01 - Part One.mp3", "01 - Part One.mp3", true},
+		{"01_-_Part_One.mp3", "01 - Part One.mp3", true},
+		{"01_-_Part_One.mp3", "01_-_01_-_Part_One.mp3", true},
+		{"Part One.mp3", "01 - Part One.mp3", false},
+		{"02 - Part One.mp3", "01 - 02 - Part One.mp3", false},
+		{"Brandon_Sanderson", "Brandon Sanderson", true},
+		{"Mistborn", "The Final Empire", false},
+	}
+	for _, tt := range tests {
+		if got := naming.Equivalent(tt.a, tt.b); got != tt.want {
+			t.Errorf("Equivalent(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
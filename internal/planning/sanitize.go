@@ -0,0 +1,143 @@
+package planning
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Invalid characters per OS
+var (
+	windowsInvalidChars = []string{"<", ">", ":", "\"", "/", "\\", "|", "?", "*"}
+	unixInvalidChars    = []string{"/"}
+	// Additional problematic characters to sanitize for all platforms
+	// Note: Removed apostrophe (') from this list to ensure consistent behavior across platforms
+	commonProblematicChars = []string{"<", ">", ":", "|", "?", "*", "`", "\""}
+	// Regex to trim leading/trailing underscores, spaces, and dots
+	reTrim = regexp.MustCompile(`(^[_ .]+)|([_ .]+$)`)
+)
+
+// Sanitize cleans one path component for the target operating system goos.
+// On Windows, it replaces '<', '>', ':', '"', '/', '\', '|', '?', '*' and control
+// characters with underscores; NUL is replaced on every system.
+// On macOS, it replaces '/' and ':'; on other Unix systems, '/' and other
+// problematic characters.
+// If replaceSpace is set, it also replaces spaces with that character.
+func Sanitize(s, replaceSpace, goos string) string {
+	// First replace spaces if configured
+	if replaceSpace != "" {
+		s = strings.ReplaceAll(s, " ", replaceSpace)
+	}
+
+	// Then handle OS-specific invalid characters
+	var invalidChars []string
+	if goos == "windows" {
+		invalidChars = append(append([]string{}, windowsInvalidChars...), commonProblematicChars...)
+	} else if goos == "darwin" {
+		invalidChars = []string{"/", ":"}
+	} else {
+		// Linux/Unix: only replace truly problematic characters
+		// We're keeping apostrophes intact for consistent behavior with tests
+		invalidChars = append(append([]string{}, unixInvalidChars...), commonProblematicChars...)
+	}
+
+	// Replace invalid characters with underscore
+	for _, char := range invalidChars {
+		s = strings.ReplaceAll(s, char, "_")
+	}
+	s = replaceControlChars(s, goos == "windows")
+
+	// Trim leading and trailing spaces, dots, and underscores using regex
+	return reTrim.ReplaceAllString(s, "")
+}
+
+// NamingPolicy is how metadata becomes directory and file names. Every place that
+// names a target path goes through one, so directories and files always agree on
+// space replacement.
+type NamingPolicy struct {
+	ReplaceSpace string // Replaces each space when set
+	TargetOS     string // GOOS whose rules Dir applies
+}
+
+// Dir cleans one directory component with Sanitize
+func (p NamingPolicy) Dir(component string) string {
+	return Sanitize(component, p.ReplaceSpace, p.TargetOS)
+}
+
+// File applies the policy to the name of a file moved into a target directory. The
+// name came from an existing file, so only spaces are replaced.
+func (p NamingPolicy) File(name string) string {
+	if p.ReplaceSpace == "" {
+		return name
+	}
+	return strings.ReplaceAll(name, " ", p.ReplaceSpace)
+}
+
+// Equivalent reports whether two names differ only in ways organizing again would
+// not improve: spaces written as "_" or the replacement character, or a track prefix
+// that was added twice. Such names are left alone, so organizing an organized
+// library renames nothing.
+func (p NamingPolicy) Equivalent(a, b string) bool {
+	return a == b || p.canonical(a) == p.canonical(b)
+}
+
+// canonical spells spaces as spaces and collapses repeated track prefixes
+func (p NamingPolicy) canonical(name string) string {
+	name = strings.ReplaceAll(name, "_", " ")
+	if p.ReplaceSpace != "" {
+		name = strings.ReplaceAll(name, p.ReplaceSpace, " ")
+	}
+	for HasTrackPrefix(name) && strings.HasPrefix(name[5:], name[:5]) {
+		name = name[5:]
+	}
+	return name
+}
+
+// SanitizeFilename cleans a rendered rename template. Unlike Sanitize it applies the
+// same character set on every OS, so renamed files stay portable between systems.
+func SanitizeFilename(filename, replaceSpace string) string {
+	// Replace spaces if configured
+	if replaceSpace != "" {
+		filename = strings.ReplaceAll(filename, " ", replaceSpace)
+	}
+
+	for _, char := range windowsInvalidChars {
+		filename = strings.ReplaceAll(filename, char, "_")
+	}
+
+	return replaceControlChars(filename, true)
+}
+
+// replaceControlChars replaces NUL, which ends a path in every OS API, with an
+// underscore, and with all set every other control character Windows forbids too
+func replaceControlChars(s string, all bool) string {
+	return strings.Map(func(r rune) rune {
+		if r == 0 || (all && r < 0x20) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// RenderFilename renders a rename template for a file with extension ext, sanitizing
+// the result and appending the extension when the template did not produce it
+func RenderFilename(
+	renderer *TemplateRenderer,
+	metadata Metadata,
+	ext, replaceSpace string,
+) (string, error) {
+	filename, err := renderer.Render(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	filename = SanitizeFilename(filename, replaceSpace)
+	if strings.Trim(filename, " .") == "" {
+		// Only an extension, or "." and "..", would hide or misplace the file
+		return "", fmt.Errorf("template rendered no usable file name for %q", metadata.Title)
+	}
+	if !strings.HasSuffix(filename, ext) {
+		filename += ext
+	}
+	return filename, nil
+}
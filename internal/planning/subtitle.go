@@ -0,0 +1,73 @@
+package planning
+
+import "strings"
+
+// Subtitle styles decide how a book's subtitle appears with its title in paths
+const (
+	SubtitleKeep  = "keep"  // Keep the title as tagged (default)
+	SubtitleColon = "colon" // "Leviathan Wakes: The Expanse, Book 1"
+	SubtitleDash  = "dash"  // "Leviathan Wakes - The Expanse, Book 1"
+	SubtitleDrop  = "drop"  // "Leviathan Wakes"
+)
+
+// subtitleSeparators are the ways a subtitle is commonly appended to a title tag
+var subtitleSeparators = []string{": ", " - ", " – ", " — "}
+
+// ValidSubtitleStyle reports whether style names a subtitle style; empty means keep
+func ValidSubtitleStyle(style string) bool {
+	switch style {
+	case "", SubtitleKeep, SubtitleColon, SubtitleDash, SubtitleDrop:
+		return true
+	}
+	return false
+}
+
+// GetSubtitle returns the subtitle, read from the "subtitle" field unless
+// FillDetails set it
+func (m *Metadata) GetSubtitle() string {
+	if m.Subtitle != "" {
+		return m.Subtitle
+	}
+	return strings.TrimSpace(stringifyTemplateValue(rawTemplateValue(*m, "subtitle")))
+}
+
+// SplitSubtitle separates the title of metadata from its subtitle. A known
+// subtitle is taken off the end of a title that repeats it; without one, a title
+// such as "Dune: Deluxe Edition" is split at its first colon.
+func SplitSubtitle(metadata Metadata) (title, subtitle string) {
+	title = strings.TrimSpace(metadata.Title)
+	subtitle = metadata.GetSubtitle()
+	if subtitle == "" {
+		if before, after, found := strings.Cut(title, ": "); found && before != "" && after != "" {
+			return strings.TrimSpace(before), strings.TrimSpace(after)
+		}
+		return title, ""
+	}
+	if strings.EqualFold(title, subtitle) {
+		return title, ""
+	}
+	for _, separator := range subtitleSeparators {
+		cut := len(title) - len(separator) - len(subtitle)
+		if cut > 0 && strings.EqualFold(title[cut:], separator+subtitle) {
+			return strings.TrimSpace(title[:cut]), subtitle
+		}
+	}
+	return title, subtitle
+}
+
+// ApplySubtitleStyle returns the title of metadata with its subtitle joined in
+// style, or without it for SubtitleDrop. Other styles keep the title as tagged.
+func ApplySubtitleStyle(metadata Metadata, style string) string {
+	if style != SubtitleColon && style != SubtitleDash && style != SubtitleDrop {
+		return metadata.Title
+	}
+	title, subtitle := SplitSubtitle(metadata)
+	switch {
+	case subtitle == "" || style == SubtitleDrop:
+		return title
+	case style == SubtitleColon:
+		return title + ": " + subtitle
+	default:
+		return title + " - " + subtitle
+	}
+}
@@ -0,0 +1,72 @@
+package planning
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestApplySubtitleStyle(t *testing.T) {
+	tagged := Metadata{
+		Title:   "Leviathan Wakes: The Expanse, Book 1",
+		RawData: map[string]interface{}{"subtitle": "The Expanse, Book 1"},
+	}
+	separate := Metadata{Title: "Leviathan Wakes", Subtitle: "The Expanse, Book 1"}
+	unknown := Metadata{Title: "Dune: Deluxe Edition"}
+	plain := Metadata{Title: "Dune"}
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		style    string
+		want     string
+	}{
+		{"keep leaves the tag alone", tagged, SubtitleKeep, "Leviathan Wakes: The Expanse, Book 1"},
+		{"empty style keeps", separate, "", "Leviathan Wakes"},
+		{"drop a repeated subtitle", tagged, SubtitleDrop, "Leviathan Wakes"},
+		{"dash a repeated subtitle", tagged, SubtitleDash, "Leviathan Wakes - The Expanse, Book 1"},
+		{"colon a separate subtitle", separate, SubtitleColon, "Leviathan Wakes: The Expanse, Book 1"},
+		{"dash a separate subtitle", separate, SubtitleDash, "Leviathan Wakes - The Expanse, Book 1"},
+		{"drop a separate subtitle", separate, SubtitleDrop, "Leviathan Wakes"},
+		{"drop after the colon without a subtitle field", unknown, SubtitleDrop, "Dune"},
+		{"dash at the colon without a subtitle field", unknown, SubtitleDash, "Dune - Deluxe Edition"},
+		{"no subtitle at all", plain, SubtitleColon, "Dune"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplySubtitleStyle(tt.metadata, tt.style); got != tt.want {
+				t.Errorf("ApplySubtitleStyle(%q) = %q, want %q", tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutSubtitle(t *testing.T) {
+	metadata := Metadata{
+		Title:    "Leviathan Wakes - The Expanse, Book 1",
+		Authors:  []string{"James S. A. Corey"},
+		Subtitle: "The Expanse, Book 1",
+	}
+
+	layout := Layout{Name: "author-title", Subtitle: SubtitleDrop}
+	got, err := layout.TargetDir(metadata, "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("out", "James S. A. Corey", "Leviathan Wakes"); got != want {
+		t.Errorf("TargetDir() = %q, want %q", got, want)
+	}
+
+	// {subtitle} still renders when the title drops it
+	layout = Layout{Template: "{author}/{title}{ (subtitle)}", Subtitle: SubtitleDrop}
+	got, err = layout.TargetDir(metadata, "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("out", "James S. A. Corey", "Leviathan Wakes (The Expanse, Book 1)"); got != want {
+		t.Errorf("TargetDir() = %q, want %q", got, want)
+	}
+
+	if ValidSubtitleStyle("parens") {
+		t.Error("ValidSubtitleStyle(\"parens\") = true")
+	}
+}
@@ -1,4 +1,4 @@
-package organizer
+package planning
 
 import (
 	"fmt"
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // Template represents a parsed filename template
@@ -42,6 +43,7 @@ type templatePart struct {
 type TemplateRenderer struct {
 	template        *Template
 	authorFormatter *AuthorFormatter
+	initial         func(string) string // Files a name under a letter; see SetInitial
 }
 
 // TemplateField describes an available template field
@@ -59,11 +61,15 @@ var (
 )
 
 var knownTemplateFields = []string{
+	"author_initial",
 	"series_number",
 	"series_count",
 	"series_full",
 	"narrators",
 	"narrator",
+	"subtitle",
+	"chapters",
+	"duration",
 	"authors",
 	"author",
 	"series",
@@ -71,6 +77,8 @@ var knownTemplateFields = []string{
 	"album",
 	"track",
 	"year",
+	"isbn",
+	"asin",
 }
 
 func init() {
@@ -156,6 +164,26 @@ func parseBraceToken(rawSpec string) (templateToken, error) {
 	}, nil
 }
 
+// UsesField reports whether the template renders field, alone or inside a composite
+// placeholder
+func (t *Template) UsesField(field string) bool {
+	for _, token := range t.tokens {
+		switch token.kind {
+		case tokenSimple:
+			if token.value == field {
+				return true
+			}
+		case tokenComposite:
+			for _, part := range token.composite {
+				if part.isField && part.field == field {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func isSimpleFieldSpec(spec string) bool {
 	if strings.Contains(spec, " ") {
 		return false
@@ -262,7 +290,30 @@ func NewTemplateRenderer(template *Template, authorFormatter *AuthorFormatter) *
 	return &TemplateRenderer{
 		template:        template,
 		authorFormatter: authorFormatter,
+		initial:         NameInitial,
+	}
+}
+
+// SetInitial replaces the function that picks the letter {author_initial} files a
+// name under, so callers can fold letters for a locale
+func (tr *TemplateRenderer) SetInitial(initial func(string) string) {
+	if initial != nil {
+		tr.initial = initial
+	}
+}
+
+// NameInitial returns the uppercased first letter of name, skipping leading
+// punctuation, or "#" when name starts with a digit or has no letters
+func NameInitial(name string) string {
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r):
+			return string(unicode.ToUpper(r))
+		case unicode.IsDigit(r):
+			return "#"
+		}
 	}
+	return "#"
 }
 
 // Render applies metadata to template and returns filename
@@ -337,6 +388,13 @@ func (tr *TemplateRenderer) resolveField(fieldName string, metadata Metadata) st
 		}
 		return ""
 
+	case "author_initial":
+		// Shelve people by surname, as libraries do
+		if len(metadata.Authors) > 0 {
+			return tr.initial(NewAuthorFormatter(AuthorFormatLastFirst).FormatAuthor(metadata.Authors[0]))
+		}
+		return ""
+
 	case "authors":
 		if len(metadata.Authors) == 0 {
 			return ""
@@ -373,20 +431,32 @@ func (tr *TemplateRenderer) resolveField(fieldName string, metadata Metadata) st
 		return ""
 
 	case "year":
-		if year, ok := rawTemplateValue(metadata, fieldName, normalizedFieldName).(int); ok {
+		if year := metadata.GetYear(); year > 0 {
 			return fmt.Sprintf("%d", year)
 		}
-		if year, ok := rawTemplateValue(metadata, fieldName, normalizedFieldName).(float64); ok {
-			return fmt.Sprintf("%d", int(year))
-		}
 		return ""
 
+	case "subtitle":
+		return metadata.GetSubtitle()
+
+	case "chapters":
+		return resolveChapterCount(metadata)
+
+	case "duration":
+		return resolveDuration(metadata)
+
 	case "narrator":
 		return resolveFirstNarrator(metadata)
 
 	case "narrators":
 		return resolveAllNarrators(metadata)
 
+	case "isbn":
+		return metadata.ISBN
+
+	case "asin":
+		return metadata.ASIN
+
 	default:
 		return stringifyTemplateValue(rawTemplateValue(metadata, fieldName, normalizedFieldName))
 	}
@@ -435,6 +505,55 @@ func rawTemplateValue(metadata Metadata, fieldNames ...string) interface{} {
 	return nil
 }
 
+// resolveChapterCount returns the number of chapters, read from the audio container
+// or counted from a metadata.json chapter list.
+func resolveChapterCount(metadata Metadata) string {
+	switch chapters := rawTemplateValue(metadata, "chapters").(type) {
+	case int:
+		if chapters > 0 {
+			return strconv.Itoa(chapters)
+		}
+	case float64:
+		if chapters > 0 {
+			return strconv.Itoa(int(chapters))
+		}
+	case []interface{}:
+		if len(chapters) > 0 {
+			return strconv.Itoa(len(chapters))
+		}
+	}
+	if len(metadata.Chapters) > 0 {
+		return strconv.Itoa(len(metadata.Chapters))
+	}
+	return ""
+}
+
+// resolveDuration returns the playing time as hours and minutes, such as "11h23m",
+// or minutes alone under an hour.
+func resolveDuration(metadata Metadata) string {
+	var seconds float64
+	switch duration := rawTemplateValue(metadata, "duration").(type) {
+	case float64:
+		seconds = duration
+	case int:
+		seconds = float64(duration)
+	}
+	return FormatDuration(seconds)
+}
+
+// FormatDuration renders a playing time in seconds the way {duration} does. Times
+// are rounded to the nearest minute; zero or less renders as "".
+func FormatDuration(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	minutes := int(seconds/60 + 0.5)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
 // resolveFirstNarrator returns the first narrator from metadata.
 func resolveFirstNarrator(metadata Metadata) string {
 	values := narratorValuesFromMetadata(metadata)
@@ -454,6 +573,9 @@ func resolveAllNarrators(metadata Metadata) string {
 }
 
 func narratorValuesFromMetadata(metadata Metadata) []string {
+	if len(metadata.Narrators) > 0 {
+		return metadata.Narrators
+	}
 	if metadata.RawData == nil {
 		return nil
 	}
@@ -532,6 +654,11 @@ func GetAvailableFields() []TemplateField {
 			Description: "First author (with format control)",
 			Example:     "Brandon Sanderson",
 		},
+		{
+			Name:        "author_initial",
+			Description: "First letter of the author's surname, for A-Z folders",
+			Example:     "S",
+		},
 		{
 			Name:        "authors",
 			Description: "All authors (comma-separated)",
@@ -567,9 +694,14 @@ func GetAvailableFields() []TemplateField {
 			Description: "Track number (zero-padded)",
 			Example:     "01",
 		},
+		{
+			Name:        "subtitle",
+			Description: "Subtitle (metadata.json or tags)",
+			Example:     "Book One of the Stormlight Archive",
+		},
 		{
 			Name:        "year",
-			Description: "Publication year",
+			Description: "Publication year (also from publishedYear or a publication date)",
 			Example:     "2006",
 		},
 		{
@@ -582,6 +714,26 @@ func GetAvailableFields() []TemplateField {
 			Description: "All narrators (comma-separated)",
 			Example:     "Michael Kramer, Kate Reading",
 		},
+		{
+			Name:        "chapters",
+			Description: "Number of chapters in the file",
+			Example:     "43",
+		},
+		{
+			Name:        "duration",
+			Description: "Playing time in hours and minutes",
+			Example:     "11h23m",
+		},
+		{
+			Name:        "isbn",
+			Description: "ISBN from the EPUB, tags, or metadata.json",
+			Example:     "9780765326355",
+		},
+		{
+			Name:        "asin",
+			Description: "Audible ASIN from the tags or metadata.json",
+			Example:     "B003ZWFO7E",
+		},
 	}
 }
 
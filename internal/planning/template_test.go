@@ -1,4 +1,4 @@
-package organizer
+package planning
 
 import (
 	"testing"
@@ -245,6 +245,75 @@ func TestTemplateRender(t *testing.T) {
 			want:    "The Fifth Season (Robin Miles)",
 			wantErr: false,
 		},
+		{
+			name:     "chapters and duration",
+			template: "{title} ({chapters} chs, {duration})",
+			metadata: Metadata{
+				Title: "Dune",
+				RawData: map[string]interface{}{
+					"chapters": 43,
+					"duration": 40980.0,
+				},
+			},
+			want: "Dune (43 chs, 11h23m)",
+		},
+		{
+			name:     "chapter list from metadata.json and short duration",
+			template: "{title} ({chapters} chs, {duration})",
+			metadata: Metadata{
+				Title: "Novella",
+				RawData: map[string]interface{}{
+					"chapters": []interface{}{map[string]interface{}{"title": "One"}, map[string]interface{}{"title": "Two"}},
+					"duration": 2710,
+				},
+			},
+			want: "Novella (2 chs, 45m)",
+		},
+		{
+			name:     "composite timing group dropped when unknown",
+			template: "{title}{ (chapters chs, duration)}",
+			metadata: Metadata{
+				Title:   "Dune",
+				RawData: map[string]interface{}{},
+			},
+			want: "Dune",
+		},
+		{
+			name:     "asin",
+			template: "{title} [{asin}]",
+			metadata: Metadata{
+				Title: "The Way of Kings",
+				ASIN:  "B003ZWFO7E",
+			},
+			want: "The Way of Kings [B003ZWFO7E]",
+		},
+		{
+			name:     "missing asin dropped with its group",
+			template: "{title}{ [asin]}",
+			metadata: Metadata{
+				Title: "The Way of Kings",
+			},
+			want: "The Way of Kings",
+		},
+		{
+			name:     "audiobookshelf published year and subtitle",
+			template: "{year} - {title}{: subtitle}",
+			metadata: Metadata{
+				Title:   "The Way of Kings",
+				RawData: map[string]interface{}{"publishedYear": "2010", "subtitle": "Stormlight 1"},
+			},
+			want: "2010 - The Way of Kings: Stormlight 1",
+		},
+		{
+			name:     "typed narrators and year",
+			template: "{title} ({narrator}, {year})",
+			metadata: Metadata{
+				Title:     "The Way of Kings",
+				Narrators: []string{"Michael Kramer", "Kate Reading"},
+				Year:      2010,
+			},
+			want: "The Way of Kings (Michael Kramer, 2010)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -415,3 +484,42 @@ func TestValidateTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestNameInitial(t *testing.T) {
+	tests := map[string]string{
+		"Sanderson, Brandon": "S",
+		"ångström":           "Å",
+		"'Salem's Lot":       "S",
+		"1984 Collective":    "#",
+		"":                   "#",
+	}
+	for name, want := range tests {
+		if got := NameInitial(name); got != want {
+			t.Errorf("NameInitial(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLayoutAuthorInitial(t *testing.T) {
+	metadata := Metadata{Authors: []string{"Anders Ångström"}, Title: "Light"}
+
+	got, err := Layout{Template: "{author_initial}/{author}/{title}"}.TargetDir(metadata, "/books")
+	if err != nil {
+		t.Fatalf("TargetDir() error = %v", err)
+	}
+	if want := "/books/Å/Anders Ångström/Light"; got != want {
+		t.Errorf("TargetDir() = %q, want %q", got, want)
+	}
+
+	layout := Layout{
+		Template: "{author_initial}/{author}/{title}",
+		Initial:  func(string) string { return "A" },
+	}
+	got, err = layout.TargetDir(metadata, "/books")
+	if err != nil {
+		t.Fatalf("TargetDir() error = %v", err)
+	}
+	if want := "/books/A/Anders Ångström/Light"; got != want {
+		t.Errorf("TargetDir() with Initial = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package planning
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// titleSeparator is the punctuation that ends a redundant prefix, as in
+// "Mistborn 01 - " or "Brandon Sanderson: "
+const titleSeparator = `\s*(?:[-–—:.,)]\s*)+`
+
+// leadingSeriesNumber matches a series number with an optional marker, as in "01",
+// "#1", "Book 1", or "Vol. 2.5"
+const leadingSeriesNumber = `(?:(?:book|bk|vol|volume|part|no|#)\.?\s*)?(\d+(?:\.\d+)?)`
+
+var leadingNumberOnly = regexp.MustCompile(`(?i)^\s*` + leadingSeriesNumber + titleSeparator)
+
+// StripRedundantTitle returns metadata.Title without a leading author name or series
+// name and number that only repeat the book's other fields, such as the
+// "Mistborn 01 - " of "Mistborn 01 - The Final Empire". A leading number is only
+// stripped when it is the book's series number, so a number the other fields
+// don't record is never lost. It reports false when the title is left as is.
+func StripRedundantTitle(metadata Metadata) (string, bool) {
+	title := strings.TrimSpace(metadata.Title)
+	series := metadata.GetValidSeries()
+	number := GetSeriesNumberFromMetadata(metadata)
+
+	rest := title
+	for changed := true; changed; {
+		changed = false
+		for _, author := range metadata.Authors {
+			if stripped, ok := stripLeadingName(rest, author); ok {
+				rest, changed = stripped, true
+			}
+		}
+		if series != "" {
+			if stripped, ok := stripLeadingSeries(rest, series, number); ok {
+				rest, changed = stripped, true
+			}
+		}
+	}
+
+	if rest == title || strings.IndexFunc(rest, unicode.IsLetter) < 0 {
+		return metadata.Title, false
+	}
+	return rest, true
+}
+
+// stripLeadingName removes name and the separator after it from the start of title
+func stripLeadingName(title, name string) (string, bool) {
+	pattern := namePattern(name)
+	if pattern == "" {
+		return title, false
+	}
+	re := regexp.MustCompile(`(?i)^\s*` + pattern + titleSeparator)
+	loc := re.FindStringIndex(title)
+	if loc == nil {
+		return title, false
+	}
+	return title[loc[1]:], true
+}
+
+// stripLeadingSeries removes the series name, its number, or both from the start of
+// title when followed by a separator. A number must match the series number.
+func stripLeadingSeries(title, series, number string) (string, bool) {
+	re := regexp.MustCompile(`(?i)^\s*` + namePattern(series) + `\s*,?\s*(?:` + leadingSeriesNumber + `)?` + titleSeparator)
+	match := re.FindStringSubmatchIndex(title)
+	if match == nil {
+		// "01 - The Final Empire" repeats the number without the series name
+		match = leadingNumberOnly.FindStringSubmatchIndex(title)
+		if match == nil {
+			return title, false
+		}
+	}
+	if match[2] >= 0 && !sameSeriesNumber(title[match[2]:match[3]], number) {
+		return title, false
+	}
+	return title[match[1]:], true
+}
+
+// namePattern matches name case-insensitively with any run of whitespace between words
+func namePattern(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return strings.Join(words, `\s+`)
+}
+
+func sameSeriesNumber(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil && x == y
+}
@@ -0,0 +1,109 @@
+package planning
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStripRedundantTitle(t *testing.T) {
+	numbered := map[string]interface{}{"series_index": 1.0}
+	tests := []struct {
+		name     string
+		metadata Metadata
+		expected string
+		stripped bool
+	}{
+		{
+			name:     "series name and number",
+			metadata: Metadata{Title: "Mistborn 01 - The Final Empire", Series: []string{"Mistborn #1"}},
+			expected: "The Final Empire",
+			stripped: true,
+		},
+		{
+			name:     "series with book marker and colon",
+			metadata: Metadata{Title: "mistborn, Book 1: The Final Empire", Series: []string{"Mistborn"}, RawData: numbered},
+			expected: "The Final Empire",
+			stripped: true,
+		},
+		{
+			name:     "series name only",
+			metadata: Metadata{Title: "The Stormlight Archive - Oathbringer", Series: []string{"The Stormlight Archive #3"}},
+			expected: "Oathbringer",
+			stripped: true,
+		},
+		{
+			name:     "number only",
+			metadata: Metadata{Title: "#1 - The Final Empire", Series: []string{"Mistborn"}, RawData: numbered},
+			expected: "The Final Empire",
+			stripped: true,
+		},
+		{
+			name: "author then series",
+			metadata: Metadata{
+				Title:   "Brandon Sanderson - Mistborn 1 - The Final Empire",
+				Authors: []string{"Brandon Sanderson"},
+				Series:  []string{"Mistborn #1"},
+			},
+			expected: "The Final Empire",
+			stripped: true,
+		},
+		{
+			name:     "number not recorded elsewhere is kept",
+			metadata: Metadata{Title: "Mistborn 01 - The Final Empire", Series: []string{"Mistborn"}},
+			expected: "Mistborn 01 - The Final Empire",
+		},
+		{
+			name:     "different number is kept",
+			metadata: Metadata{Title: "Mistborn 02 - The Well of Ascension", Series: []string{"Mistborn #1"}},
+			expected: "Mistborn 02 - The Well of Ascension",
+		},
+		{
+			name:     "series name inside the title",
+			metadata: Metadata{Title: "Dune Messiah", Series: []string{"Dune #2"}},
+			expected: "Dune Messiah",
+		},
+		{
+			name:     "nothing left",
+			metadata: Metadata{Title: "Mistborn 1 -", Series: []string{"Mistborn #1"}},
+			expected: "Mistborn 1 -",
+		},
+		{
+			name:     "possessive author",
+			metadata: Metadata{Title: "Tolkien's Letters", Authors: []string{"Tolkien"}},
+			expected: "Tolkien's Letters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stripped := StripRedundantTitle(tt.metadata)
+			if got != tt.expected || stripped != tt.stripped {
+				t.Errorf("StripRedundantTitle() = %q, %v, want %q, %v", got, stripped, tt.expected, tt.stripped)
+			}
+		})
+	}
+}
+
+func TestLayoutStripTitle(t *testing.T) {
+	metadata := Metadata{
+		Title:   "Mistborn 01 - The Final Empire",
+		Authors: []string{"Brandon Sanderson"},
+		Series:  []string{"Mistborn #1"},
+	}
+
+	for _, tt := range []struct {
+		strip    bool
+		expected string
+	}{
+		{false, filepath.Join("/books", "Brandon Sanderson", "Mistborn", "Mistborn 01 - The Final Empire")},
+		{true, filepath.Join("/books", "Brandon Sanderson", "Mistborn", "The Final Empire")},
+	} {
+		got, err := Layout{StripTitle: tt.strip}.TargetDir(metadata, "/books")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.expected {
+			t.Errorf("TargetDir(strip=%v) = %q, want %q", tt.strip, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package planning
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TrackPrefixFormat is the filename prefix added for multi-track books
+const TrackPrefixFormat = "%02d - "
+
+// DiscTrackPrefixFormat is the filename prefix for books merged from several discs,
+// numbering tracks by disc so they sort in playing order: "2-05 - "
+const DiscTrackPrefixFormat = "%d-%02d - "
+
+// ShouldAddTrackPrefix reports whether a track number prefix should be added to a filename.
+// Single-track audiobooks (track_total == 1) keep their original filename.
+func ShouldAddTrackPrefix(trackNumber, trackTotal int) bool {
+	if trackNumber <= 0 {
+		return false
+	}
+	return trackTotal != 1
+}
+
+// TrackTotalFromMetadata returns the total track count from metadata raw data.
+func TrackTotalFromMetadata(metadata Metadata) int {
+	if metadata.RawData == nil {
+		return 0
+	}
+	switch value := metadata.RawData["track_total"].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	default:
+		return 0
+	}
+}
+
+// AddTrackPrefix adds a track number prefix to a filename if not already present.
+// Returns the original filename if track number is 0 or prefix already exists.
+func AddTrackPrefix(filename string, trackNumber int) string {
+	if trackNumber <= 0 {
+		return filename
+	}
+
+	ext := filepath.Ext(filename)
+	baseName := strings.TrimSuffix(filename, ext)
+
+	prefix := fmt.Sprintf(TrackPrefixFormat, trackNumber)
+	if strings.HasPrefix(baseName, prefix) {
+		return filename
+	}
+
+	return fmt.Sprintf("%s%s%s", prefix, baseName, ext)
+}
+
+// HasTrackPrefix checks if a filename already has a track number prefix.
+func HasTrackPrefix(filename string) bool {
+	// Look for pattern like "01 - ", "02 - ", etc.
+	if len(filename) < 5 {
+		return false
+	}
+
+	// Check if it starts with digits followed by " - "
+	if filename[2] == ' ' && filename[3] == '-' && filename[4] == ' ' {
+		first := filename[0]
+		second := filename[1]
+		return first >= '0' && first <= '9' && second >= '0' && second <= '9'
+	}
+
+	return false
+}
+
+// ExtractTrackNumber extracts the track number from a filename prefix.
+// Returns 0 if no track number prefix is found.
+func ExtractTrackNumber(filename string) int {
+	if !HasTrackPrefix(filename) {
+		return 0
+	}
+
+	// Extract the two-digit number from the beginning
+	trackStr := filename[:2]
+	var trackNum int
+	if _, err := fmt.Sscanf(trackStr, "%d", &trackNum); err == nil {
+		return trackNum
+	}
+
+	return 0
+}
+
+// RemoveTrackPrefix removes the track number prefix from a filename if present.
+func RemoveTrackPrefix(filename string) string {
+	if !HasTrackPrefix(filename) {
+		return filename
+	}
+
+	// Remove the "XX - " prefix (5 characters)
+	return filename[5:]
+}
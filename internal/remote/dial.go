@@ -0,0 +1,170 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPOptions configures how DialSFTP authenticates
+type SFTPOptions struct {
+	IdentityFile   string        // Private key; defaults to the usual ~/.ssh keys
+	KnownHostsFile string        // Defaults to ~/.ssh/known_hosts
+	Timeout        time.Duration // Connection timeout; defaults to 30 seconds
+}
+
+// DialSFTP connects to the host of an sftp:// URL and starts an SFTP session.
+// Authentication tries, in order, a password in the URL, the ssh-agent and key files.
+// The host key must be present in the known_hosts file.
+func DialSFTP(u *url.URL, opts SFTPOptions) (*SFTPClient, error) {
+	if u.Scheme != "sftp" {
+		return nil, fmt.Errorf("unsupported remote scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("sftp URL %q has no host", u.Redacted())
+	}
+
+	username := u.User.Username()
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("sftp URL %q has no user: %w", u.Redacted(), err)
+		}
+		username = current.Username
+	}
+
+	hostKeyCallback, err := hostKeyCallback(opts.KnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, closers := authMethods(u, opts.IdentityFile)
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("no SSH credentials for %s: add a key, start ssh-agent or pass --sftp-identity", u.Host)
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+
+	conn, err := ssh.Dial("tcp", net.JoinHostPort(u.Hostname(), port), &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         timeout,
+	})
+	if err != nil {
+		closeAll(closers)
+		return nil, fmt.Errorf("connecting to %s: %w", u.Host, err)
+	}
+	closers = append(closers, conn)
+
+	session, err := conn.NewSession()
+	if err != nil {
+		closeAll(closers)
+		return nil, fmt.Errorf("opening SSH session on %s: %w", u.Host, err)
+	}
+	closers = append(closers, session)
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		closeAll(closers)
+		return nil, fmt.Errorf("starting sftp subsystem on %s: %w", u.Host, err)
+	}
+
+	client, err := NewSFTPClient(stdout, stdin)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+	client.closers = closers
+	return client, nil
+}
+
+func hostKeyCallback(knownHostsFile string) (ssh.HostKeyCallback, error) {
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating known_hosts: %w", err)
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading known hosts %s: %w (connect once with ssh to record the host key)", knownHostsFile, err)
+	}
+	return callback, nil
+}
+
+func authMethods(u *url.URL, identityFile string) ([]ssh.AuthMethod, []io.Closer) {
+	var methods []ssh.AuthMethod
+	var closers []io.Closer
+
+	if password, ok := u.User.Password(); ok {
+		methods = append(methods, ssh.Password(password))
+	}
+
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			closers = append(closers, conn)
+		}
+	}
+
+	var keyFiles []string
+	if identityFile != "" {
+		keyFiles = []string{identityFile}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			keyFiles = append(keyFiles, filepath.Join(home, ".ssh", name))
+		}
+	}
+
+	var signers []ssh.Signer
+	for _, keyFile := range keyFiles {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			// Encrypted keys are left to the ssh-agent
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	return methods, closers
+}
+
+func closeAll(closers []io.Closer) {
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i].Close()
+	}
+}
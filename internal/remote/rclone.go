@@ -0,0 +1,247 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// rclone exit codes for missing paths
+const (
+	rcloneDirNotFound  = 3
+	rcloneFileNotFound = 4
+)
+
+// Rclone writes to cloud storage (Google Drive, S3, B2, ...) by running the rclone
+// command line tool. Paths use rclone's remote:path syntax and may be built with
+// filepath; rclone's own configuration, including RCLONE_CONFIG, applies as usual.
+type Rclone struct {
+	binary string
+}
+
+// NewRclone finds the rclone executable. An empty binary looks up "rclone" in PATH.
+func NewRclone(binary string) (*Rclone, error) {
+	if binary == "" {
+		binary = "rclone"
+	}
+	resolved, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("rclone output needs the rclone tool: %w\n\nInstall it from https://rclone.org/install/", err)
+	}
+	return &Rclone{binary: resolved}, nil
+}
+
+// RcloneError reports a failed rclone invocation
+type RcloneError struct {
+	Args     []string
+	ExitCode int
+	Stderr   string
+}
+
+func (e *RcloneError) Error() string {
+	message := strings.TrimSpace(e.Stderr)
+	if message == "" {
+		message = fmt.Sprintf("exit status %d", e.ExitCode)
+	}
+	return fmt.Sprintf("rclone %s: %s", strings.Join(e.Args, " "), message)
+}
+
+// Is lets errors.Is match fs.ErrNotExist for rclone's not-found exit codes
+func (e *RcloneError) Is(target error) bool {
+	return target == fs.ErrNotExist &&
+		(e.ExitCode == rcloneDirNotFound || e.ExitCode == rcloneFileNotFound)
+}
+
+// Stat returns information about a remote file or directory
+func (r *Rclone) Stat(name string) (fs.FileInfo, error) {
+	out, err := r.run("lsjson", "--stat", "--no-mimetype", remotePath(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry struct {
+		Name    string
+		Size    int64
+		ModTime time.Time
+		IsDir   bool
+	}
+	if err := json.Unmarshal(out, &entry); err != nil {
+		return nil, fmt.Errorf("rclone lsjson %s: %w", name, err)
+	}
+	info := &fileInfo{name: entry.Name, size: entry.Size, mode: 0o644, modTime: entry.ModTime}
+	if entry.IsDir {
+		info.mode = fs.ModeDir | 0o755
+	}
+	return info, nil
+}
+
+// MkdirAll creates a remote directory and any missing parents. Bucket-based remotes
+// have no real directories, where this is a no-op.
+func (r *Rclone) MkdirAll(dir string) error {
+	_, err := r.run("mkdir", remotePath(dir))
+	return err
+}
+
+// MkdirTemp creates a new remote directory in dir whose name starts with the part of
+// pattern before the last "*" and returns its path
+func (r *Rclone) MkdirTemp(dir, pattern string) (string, error) {
+	prefix, suffix := pattern, ""
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		prefix, suffix = pattern[:i], pattern[i+1:]
+	}
+	random := make([]byte, 6)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, prefix+hex.EncodeToString(random)+suffix)
+	return name, r.MkdirAll(name)
+}
+
+// Create streams a new remote file through rclone rcat. The upload finishes when the
+// returned writer is closed.
+func (r *Rclone) Create(name string) (io.WriteCloser, error) {
+	args := []string{"rcat", remotePath(name)}
+	cmd := exec.Command(r.binary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting rclone: %w", err)
+	}
+	return &rcloneUpload{WriteCloser: stdin, cmd: cmd, args: args, stderr: &stderr}, nil
+}
+
+// Upload copies a local file to a remote path with rclone copyto, which lets rclone
+// use multi-threaded and checksummed transfers
+func (r *Rclone) Upload(source, target string) error {
+	_, err := r.run("copyto", source, remotePath(target))
+	return err
+}
+
+// Rename moves a remote file, replacing an existing target
+func (r *Rclone) Rename(oldPath, newPath string) error {
+	_, err := r.run("moveto", remotePath(oldPath), remotePath(newPath))
+	return err
+}
+
+// Remove removes a remote file or empty directory
+func (r *Rclone) Remove(name string) error {
+	info, err := r.Stat(name)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		_, err = r.run("rmdir", remotePath(name))
+	} else {
+		_, err = r.run("deletefile", remotePath(name))
+	}
+	return err
+}
+
+// RemoveAll removes a remote path and everything below it. A missing path is not an error.
+func (r *Rclone) RemoveAll(name string) error {
+	info, err := r.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		_, err = r.run("deletefile", remotePath(name))
+		return err
+	}
+	_, err = r.run("purge", remotePath(name))
+	return err
+}
+
+// FreeSpace returns the free bytes reported by rclone about. It wraps
+// errors.ErrUnsupported for remotes that don't report quotas.
+func (r *Rclone) FreeSpace(dir string) (uint64, error) {
+	out, err := r.run("about", "--json", remotePath(dir))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errors.ErrUnsupported, err)
+	}
+	var about struct {
+		Free *uint64 `json:"free"`
+	}
+	if err := json.Unmarshal(out, &about); err != nil {
+		return 0, fmt.Errorf("rclone about %s: %w", dir, err)
+	}
+	if about.Free == nil {
+		return 0, errors.ErrUnsupported
+	}
+	return *about.Free, nil
+}
+
+// Close is a no-op; every rclone call is its own process
+func (r *Rclone) Close() error {
+	return nil
+}
+
+func (r *Rclone) run(args ...string) ([]byte, error) {
+	cmd := exec.Command(r.binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, rcloneError(args, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+func rcloneError(args []string, err error, stderr string) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &RcloneError{Args: args, ExitCode: exitErr.ExitCode(), Stderr: stderr}
+	}
+	return fmt.Errorf("running rclone: %w", err)
+}
+
+// remotePath converts a path built with filepath to rclone's slash-separated form
+func remotePath(name string) string {
+	return filepath.ToSlash(name)
+}
+
+// rcloneUpload is the writer returned by Create
+type rcloneUpload struct {
+	io.WriteCloser
+	cmd    *exec.Cmd
+	args   []string
+	stderr *bytes.Buffer
+}
+
+func (u *rcloneUpload) Close() error {
+	closeErr := u.WriteCloser.Close()
+	if err := u.cmd.Wait(); err != nil {
+		return rcloneError(u.args, err, u.stderr.String())
+	}
+	return closeErr
+}
+
+// fileInfo implements fs.FileInfo for remote attributes
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *fileInfo) Sys() interface{}   { return nil }
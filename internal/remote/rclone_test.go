@@ -0,0 +1,128 @@
+package remote
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRclone implements the rclone subcommands the client uses, mapping every
+// remote:path onto a local directory
+const fakeRclone = `#!/bin/sh
+lp() { echo "$FAKE_RCLONE_ROOT/${1#*:}"; }
+cmd="$1"; shift
+case "$cmd" in
+mkdir) mkdir -p "$(lp "$1")" ;;
+lsjson)
+	p="$(lp "$3")"
+	name="$(basename "$p")"
+	if [ -d "$p" ]; then
+		echo "{\"Name\":\"$name\",\"Size\":-1,\"IsDir\":true,\"ModTime\":\"2024-01-01T00:00:00Z\"}"
+	elif [ -f "$p" ]; then
+		echo "{\"Name\":\"$name\",\"Size\":$(wc -c < "$p"),\"IsDir\":false,\"ModTime\":\"2024-01-01T00:00:00Z\"}"
+	else
+		echo "object not found" >&2; exit 3
+	fi ;;
+copyto) mkdir -p "$(dirname "$(lp "$2")")" && cp "$1" "$(lp "$2")" ;;
+rcat) mkdir -p "$(dirname "$(lp "$1")")" && cat > "$(lp "$1")" ;;
+moveto) mkdir -p "$(dirname "$(lp "$2")")" && mv -f "$(lp "$1")" "$(lp "$2")" ;;
+deletefile) [ -f "$(lp "$1")" ] || exit 4; rm "$(lp "$1")" ;;
+rmdir) rmdir "$(lp "$1")" ;;
+purge) [ -e "$(lp "$1")" ] || exit 3; rm -rf "$(lp "$1")" ;;
+about) [ -n "$FAKE_RCLONE_FREE" ] || { echo "about not supported" >&2; exit 1; }; echo "{\"free\":$FAKE_RCLONE_FREE}" ;;
+*) echo "unknown command $cmd" >&2; exit 1 ;;
+esac
+`
+
+func newFakeRclone(t *testing.T) (*Rclone, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake rclone is a shell script")
+	}
+	binDir := t.TempDir()
+	root := t.TempDir()
+	binary := filepath.Join(binDir, "rclone")
+	require.NoError(t, os.WriteFile(binary, []byte(fakeRclone), 0o755))
+	t.Setenv("FAKE_RCLONE_ROOT", root)
+
+	client, err := NewRclone(binary)
+	require.NoError(t, err)
+	return client, root
+}
+
+func TestRcloneFileOperations(t *testing.T) {
+	client, root := newFakeRclone(t)
+
+	require.NoError(t, client.MkdirAll("gdrive:books/Author"))
+	info, err := client.Stat("gdrive:books/Author")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	source := filepath.Join(t.TempDir(), "book.m4b")
+	require.NoError(t, os.WriteFile(source, []byte("audiobook data"), 0o644))
+	require.NoError(t, client.Upload(source, "gdrive:books/Author/staged.m4b"))
+
+	info, err = client.Stat("gdrive:books/Author/staged.m4b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("audiobook data")), info.Size())
+
+	require.NoError(t, client.Rename("gdrive:books/Author/staged.m4b", "gdrive:books/Author/book.m4b"))
+	got, err := os.ReadFile(filepath.Join(root, "books", "Author", "book.m4b"))
+	require.NoError(t, err)
+	assert.Equal(t, "audiobook data", string(got))
+
+	_, err = client.Stat("gdrive:books/Author/staged.m4b")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, client.RemoveAll("gdrive:books"))
+	require.NoError(t, client.RemoveAll("gdrive:books"), "missing paths are not an error")
+	assert.NoDirExists(t, filepath.Join(root, "books"))
+}
+
+func TestRcloneCreateAndStaging(t *testing.T) {
+	client, root := newFakeRclone(t)
+
+	dir, err := client.MkdirTemp("gdrive:books", ".staging-*")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dir, "gdrive:books/.staging-"))
+
+	w, err := client.Create(dir + "/cover.jpg")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "jpeg")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	name := strings.TrimPrefix(dir, "gdrive:")
+	got, err := os.ReadFile(filepath.Join(root, name, "cover.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(got))
+
+	require.NoError(t, client.Remove(dir+"/cover.jpg"))
+	require.NoError(t, client.Remove(dir))
+	assert.NoDirExists(t, filepath.Join(root, name))
+}
+
+func TestRcloneFreeSpace(t *testing.T) {
+	client, _ := newFakeRclone(t)
+
+	_, err := client.FreeSpace("gdrive:books")
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+
+	t.Setenv("FAKE_RCLONE_FREE", "123456")
+	free, err := client.FreeSpace("gdrive:books")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(123456), free)
+}
+
+func TestNewRcloneMissingBinary(t *testing.T) {
+	_, err := NewRclone(filepath.Join(t.TempDir(), "no-rclone"))
+	assert.ErrorContains(t, err, "rclone.org/install")
+}
@@ -0,0 +1,184 @@
+// Package remote implements output backends for libraries that are not mounted
+// locally: a seedbox or NAS reachable over SFTP, or cloud storage through rclone.
+package remote
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/pkg/sftp"
+)
+
+// SFTP extensions the client uses when the server advertises them
+const (
+	posixRenameExtension = "posix-rename@openssh.com"
+	statvfsExtension     = "statvfs@openssh.com"
+)
+
+// SFTPClient is an SFTP session over a byte stream, usually the sftp subsystem of
+// an SSH session, together with the connection it owns
+type SFTPClient struct {
+	client  *sftp.Client
+	closers []io.Closer
+}
+
+// NewSFTPClient performs the SFTP handshake on an established stream
+func NewSFTPClient(r io.Reader, w io.WriteCloser) (*SFTPClient, error) {
+	// Uploads go to fresh staging files, so concurrent writes can't leave holes in
+	// a file that is kept after a failed copy
+	client, err := sftp.NewClientPipe(r, w, sftp.UseConcurrentWrites(true))
+	if err != nil {
+		return nil, fmt.Errorf("sftp handshake: %w", err)
+	}
+	return &SFTPClient{client: client}, nil
+}
+
+// Close ends the SFTP session and any connection it owns
+func (c *SFTPClient) Close() error {
+	err := c.client.Close()
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		if closeErr := c.closers[i].Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// RealPath canonicalizes a remote path; "." resolves to the login directory
+func (c *SFTPClient) RealPath(name string) (string, error) {
+	return c.client.RealPath(name)
+}
+
+// Stat returns information about a remote file, following symlinks
+func (c *SFTPClient) Stat(name string) (fs.FileInfo, error) {
+	return c.client.Stat(name)
+}
+
+// Lstat returns information about a remote file without following symlinks
+func (c *SFTPClient) Lstat(name string) (fs.FileInfo, error) {
+	return c.client.Lstat(name)
+}
+
+// ReadDir lists a remote directory
+func (c *SFTPClient) ReadDir(name string) ([]fs.FileInfo, error) {
+	return c.client.ReadDir(name)
+}
+
+// MkdirAll creates a remote directory and any missing parents
+func (c *SFTPClient) MkdirAll(name string) error {
+	return c.client.MkdirAll(name)
+}
+
+// MkdirTemp creates a new remote directory in dir whose name starts with the part of
+// pattern before the last "*" and returns its path
+func (c *SFTPClient) MkdirTemp(dir, pattern string) (string, error) {
+	prefix, suffix := pattern, ""
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		prefix, suffix = pattern[:i], pattern[i+1:]
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 10; attempt++ {
+		random, err := randomSuffix()
+		if err != nil {
+			return "", err
+		}
+		name := path.Join(dir, prefix+random+suffix)
+		if lastErr = c.client.Mkdir(name); lastErr == nil {
+			return name, nil
+		}
+	}
+	return "", lastErr
+}
+
+// Rename renames a remote file, replacing an existing target. Without POSIX rename
+// support the target is moved aside first and only removed once the new file is in
+// place, so a failed rename never loses it.
+func (c *SFTPClient) Rename(oldPath, newPath string) error {
+	if _, ok := c.client.HasExtension(posixRenameExtension); ok {
+		return c.client.PosixRename(oldPath, newPath)
+	}
+
+	// Plain SFTP rename refuses to overwrite
+	info, err := c.client.Lstat(newPath)
+	if err != nil || info.IsDir() {
+		return c.client.Rename(oldPath, newPath)
+	}
+
+	random, err := randomSuffix()
+	if err != nil {
+		return err
+	}
+	aside := path.Join(path.Dir(newPath), "."+path.Base(newPath)+".replaced-"+random)
+	if err := c.client.Rename(newPath, aside); err != nil {
+		return err
+	}
+	if err := c.client.Rename(oldPath, newPath); err != nil {
+		if restoreErr := c.client.Rename(aside, newPath); restoreErr != nil {
+			return fmt.Errorf("%w (the replaced file was kept as %s)", err, aside)
+		}
+		return err
+	}
+	return c.client.Remove(aside)
+}
+
+// Remove removes a remote file or empty directory
+func (c *SFTPClient) Remove(name string) error {
+	return c.client.Remove(name)
+}
+
+// RemoveAll removes a remote path and everything below it without following
+// symlinks. A missing path is not an error.
+func (c *SFTPClient) RemoveAll(name string) error {
+	info, err := c.client.Lstat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		entries, err := c.client.ReadDir(name)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := c.RemoveAll(path.Join(name, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return c.client.Remove(name)
+}
+
+// Create opens a remote file for writing, truncating it if it exists
+func (c *SFTPClient) Create(name string) (io.WriteCloser, error) {
+	return c.client.Create(name)
+}
+
+// FreeSpace returns the bytes available to the user at a remote path. It returns
+// errors.ErrUnsupported when the server lacks the statvfs extension.
+func (c *SFTPClient) FreeSpace(name string) (uint64, error) {
+	if _, ok := c.client.HasExtension(statvfsExtension); !ok {
+		return 0, errors.ErrUnsupported
+	}
+	stat, err := c.client.StatVFS(name)
+	if err != nil {
+		return 0, err
+	}
+	return stat.Bavail * stat.Frsize, nil
+}
+
+func randomSuffix() (string, error) {
+	random := make([]byte, 6)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
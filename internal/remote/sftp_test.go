@@ -0,0 +1,143 @@
+package remote
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeConn joins the two halves of a pipe pair into a stream for the SFTP server
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// startSFTPServer serves a temporary directory over SFTP and returns a client for it
+// and the directory's remote path. Without extensions the server behaves like one
+// that lacks posix-rename and statvfs.
+func startSFTPServer(t *testing.T, extensions bool) (*SFTPClient, string) {
+	t.Helper()
+	root := t.TempDir()
+
+	if !extensions {
+		require.NoError(t, sftp.SetSFTPExtensions())
+		t.Cleanup(func() {
+			sftp.SetSFTPExtensions("hardlink@openssh.com", posixRenameExtension, statvfsExtension)
+		})
+	}
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	server, err := sftp.NewServer(pipeConn{serverReader, serverWriter}, sftp.WithServerWorkingDirectory(root))
+	require.NoError(t, err)
+	go func() {
+		server.Serve()
+		server.Close()
+	}()
+
+	client, err := NewSFTPClient(clientReader, clientWriter)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, filepath.ToSlash(root)
+}
+
+func TestSFTPClientFileOperations(t *testing.T) {
+	client, root := startSFTPServer(t, true)
+
+	home, err := client.RealPath(".")
+	require.NoError(t, err)
+	assert.Equal(t, root, home)
+
+	library := root + "/library"
+	require.NoError(t, client.MkdirAll(library+"/Author/Title"))
+	info, err := client.Stat(library + "/Author/Title")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	// Large enough to need several concurrent writes
+	content := strings.Repeat("audiobook data ", 20000)
+	w, err := client.Create(library + "/Author/Title/book.m4b")
+	require.NoError(t, err)
+	n, err := io.Copy(w, strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	require.NoError(t, w.Close())
+
+	got, err := os.ReadFile(filepath.Join(root, "library", "Author", "Title", "book.m4b"))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(got))
+
+	info, err = client.Stat(library + "/Author/Title/book.m4b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.Size())
+	assert.False(t, info.IsDir())
+
+	_, err = client.Stat(library + "/missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	free, err := client.FreeSpace(library)
+	require.NoError(t, err)
+	assert.Greater(t, free, uint64(0))
+
+	require.NoError(t, client.RemoveAll(library))
+	_, err = os.Stat(filepath.Join(root, "library"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	require.NoError(t, client.RemoveAll(library))
+}
+
+func TestSFTPClientRenameReplacesTarget(t *testing.T) {
+	for _, extensions := range []bool{true, false} {
+		t.Run("extensions="+strconv.FormatBool(extensions), func(t *testing.T) {
+			client, root := startSFTPServer(t, extensions)
+			require.NoError(t, os.WriteFile(filepath.Join(root, "old.mp3"), []byte("new"), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(root, "target.mp3"), []byte("stale"), 0o644))
+
+			require.NoError(t, client.Rename(root+"/old.mp3", root+"/target.mp3"))
+
+			got, err := os.ReadFile(filepath.Join(root, "target.mp3"))
+			require.NoError(t, err)
+			assert.Equal(t, "new", string(got))
+			entries, err := os.ReadDir(root)
+			require.NoError(t, err)
+			assert.Len(t, entries, 1, "nothing but the target should be left")
+		})
+	}
+}
+
+func TestSFTPClientFailedRenameKeepsTarget(t *testing.T) {
+	client, root := startSFTPServer(t, false)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "target.mp3"), []byte("stale"), 0o644))
+
+	err := client.Rename(root+"/missing.mp3", root+"/target.mp3")
+	require.Error(t, err)
+
+	got, err := os.ReadFile(filepath.Join(root, "target.mp3"))
+	require.NoError(t, err)
+	assert.Equal(t, "stale", string(got))
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the moved-aside target should be restored")
+}
+
+func TestSFTPClientMkdirTempAndFreeSpaceUnsupported(t *testing.T) {
+	client, root := startSFTPServer(t, false)
+
+	dir, err := client.MkdirTemp(root, ".staging-*")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dir, root+"/.staging-"))
+	info, err := os.Stat(filepath.FromSlash(dir))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	_, err = client.FreeSpace(root)
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+}
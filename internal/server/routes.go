@@ -21,16 +21,19 @@ func (s *Server) routes() http.Handler {
 	mux.HandleFunc("/api/config/initial", s.withAuth(s.handleInitialConfig))
 	mux.HandleFunc("/api/config/options", s.withAuth(s.handleOptions))
 	mux.HandleFunc("/api/paths/validate", s.withAuth(s.handleValidatePaths))
+	mux.HandleFunc("/api/fs/list", s.withAuth(s.handleListDirectories))
+	mux.HandleFunc("/api/fs/mkdir", s.withAuth(s.writable(s.handleCreateDirectory)))
+	mux.HandleFunc("/api/fs/free", s.withAuth(s.handleFreeSpace))
 	mux.HandleFunc("/api/organize/preview", s.withAuth(s.handleOrganizePreview))
-	mux.HandleFunc("/api/organize/run", s.withAuth(s.handleOrganizeRun))
+	mux.HandleFunc("/api/organize/run", s.withAuth(s.writable(s.handleOrganizeRun)))
 	mux.HandleFunc("/api/rename/preview", s.withAuth(s.handleRenamePreview))
-	mux.HandleFunc("/api/rename/run", s.withAuth(s.handleRenameRun))
+	mux.HandleFunc("/api/rename/run", s.withAuth(s.writable(s.handleRenameRun)))
 	mux.HandleFunc("/api/abs/libraries", s.withAuth(s.handleABSLibraries))
 	mux.HandleFunc("/api/abs/test-paths", s.withAuth(s.handleABSTestPaths))
 	mux.HandleFunc("/api/abs/items", s.withAuth(s.handleABSItems))
 	mux.HandleFunc("/api/abs/library-state", s.withAuth(s.handleABSLibraryState))
-	mux.HandleFunc("/api/abs/scan-trigger", s.withAuth(s.handleABSScanTrigger))
-	mux.HandleFunc("/api/abs/clean-missing", s.withAuth(s.handleABSCleanMissing))
+	mux.HandleFunc("/api/abs/scan-trigger", s.withAuth(s.writable(s.handleABSScanTrigger)))
+	mux.HandleFunc("/api/abs/clean-missing", s.withAuth(s.writable(s.handleABSCleanMissing)))
 	mux.HandleFunc("/", s.handleStatic)
 
 	return mux
@@ -46,6 +49,18 @@ func (s *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// writable rejects endpoints that change files or Audiobookshelf state when the
+// server runs read-only. Scan, preview, and metadata endpoints stay available.
+func (s *Server) writable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.app.Config().ReadOnly {
+			writeError(w, http.StatusForbidden, errors.New("the web UI is running read-only; restart it without --read-only to make changes"))
+			return
+		}
+		next(w, r)
+	}
+}
+
 func requestToken(r *http.Request) string {
 	if token := r.Header.Get("X-Audiobook-Organizer-Token"); token != "" {
 		return token
@@ -85,6 +100,66 @@ func (s *Server) handleValidatePaths(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+func (s *Server) handleListDirectories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	var req app.DirectoryListRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	resp, err := s.app.ListDirectories(r.Context(), req)
+	if err != nil {
+		writeError(w, pathErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateDirectory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	var req app.CreateDirectoryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	resp, err := s.app.CreateDirectory(r.Context(), req)
+	if err != nil {
+		writeError(w, pathErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleFreeSpace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	var req app.FreeSpaceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	resp, err := s.app.FreeSpace(r.Context(), req)
+	if err != nil {
+		writeError(w, pathErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pathErrorStatus answers paths outside the allowed roots with 403 and other path
+// errors with 400
+func pathErrorStatus(err error) int {
+	if errors.Is(err, app.ErrOutsideRoots) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
 func (s *Server) handleOrganizePreview(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
@@ -96,7 +171,7 @@ func (s *Server) handleOrganizePreview(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.PreviewOrganize(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -112,8 +187,12 @@ func (s *Server) handleOrganizeRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	resp, err := s.app.RunOrganize(r.Context(), req)
+	if errors.Is(err, app.ErrPlanChanged) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -130,7 +209,7 @@ func (s *Server) handleRenamePreview(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.PreviewRename(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -147,7 +226,7 @@ func (s *Server) handleRenameRun(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.RunRename(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -164,7 +243,7 @@ func (s *Server) handleABSCleanMissing(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.CleanABSMissing(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -181,7 +260,7 @@ func (s *Server) handleABSLibraries(w http.ResponseWriter, r *http.Request) {
 	}
 	libraries, err := s.app.ListABSLibraries(r.Context(), cfg)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{"libraries": libraries})
@@ -198,7 +277,7 @@ func (s *Server) handleABSTestPaths(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.TestABSPathMappings(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -215,7 +294,7 @@ func (s *Server) handleABSItems(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.LoadABSItems(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -232,7 +311,7 @@ func (s *Server) handleABSLibraryState(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.LoadABSLibraryState(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -249,7 +328,7 @@ func (s *Server) handleABSScanTrigger(w http.ResponseWriter, r *http.Request) {
 	}
 	resp, err := s.app.TriggerABSScan(r.Context(), req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, pathErrorStatus(err), err)
 		return
 	}
 	writeJSON(w, http.StatusOK, resp)
@@ -173,6 +173,9 @@ func TestPostEndpointsRejectWrongMethodAndInvalidJSON(t *testing.T) {
 		"/api/organize/preview",
 		"/api/organize/run",
 		"/api/paths/validate",
+		"/api/fs/list",
+		"/api/fs/mkdir",
+		"/api/fs/free",
 		"/api/rename/preview",
 		"/api/rename/run",
 		"/api/abs/libraries",
@@ -236,6 +239,42 @@ func TestValidatePathsEndpointChecksDirectoriesWithoutCreatingOutput(t *testing.
 	assertFileMissing(t, outputDir)
 }
 
+func TestFileSystemEndpointsStayWithinRoots(t *testing.T) {
+	root := t.TempDir()
+	srv := newTestServer(t)
+	cfg := srv.app.Config()
+	cfg.Roots = []string{root}
+	srv.app = app.NewService(cfg)
+	handler := srv.routes()
+
+	rec := performRequest(handler, http.MethodPost, "/api/fs/mkdir",
+		map[string]any{"parent": root, "name": "Library"}, testToken)
+	assertStatus(t, rec, http.StatusOK)
+	assertFileExists(t, filepath.Join(root, "Library"))
+
+	rec = performRequest(handler, http.MethodPost, "/api/fs/list", map[string]any{"path": root}, testToken)
+	assertStatus(t, rec, http.StatusOK)
+	assertJSONField(t, rec, "entries.0.name", "Library")
+
+	rec = performRequest(handler, http.MethodPost, "/api/fs/free", map[string]any{"path": root}, testToken)
+	assertStatus(t, rec, http.StatusOK)
+
+	outside := t.TempDir()
+	for _, path := range []string{"/api/fs/list", "/api/fs/free"} {
+		rec = performRequest(handler, http.MethodPost, path, map[string]any{"path": outside}, testToken)
+		assertStatus(t, rec, http.StatusForbidden)
+	}
+	rec = performRequest(handler, http.MethodPost, "/api/fs/mkdir",
+		map[string]any{"parent": outside, "name": "Library"}, testToken)
+	assertStatus(t, rec, http.StatusForbidden)
+	assertFileMissing(t, filepath.Join(outside, "Library"))
+	rec = performRequest(handler, http.MethodPost, "/api/abs/test-paths", map[string]any{
+		"config":    map[string]any{"sqlite_path": filepath.Join(outside, "absdatabase.sqlite")},
+		"input_dir": root,
+	}, testToken)
+	assertStatus(t, rec, http.StatusForbidden)
+}
+
 func TestOrganizePreviewEndpointReturnsDryRunSummary(t *testing.T) {
 	handler := newTestHandler(t)
 	inputDir, outputDir := createOrganizerFixture(t)
@@ -259,6 +298,7 @@ func TestOrganizePreviewEndpointReturnsDryRunSummary(t *testing.T) {
 	assertStatus(t, rec, http.StatusOK)
 	assertJSONArrayLength(t, rec, "summary.MetadataFound", 1)
 	assertJSONArrayLength(t, rec, "summary.Moves", 1)
+	assertJSONField(t, rec, "summary.Sources.json", float64(1))
 }
 
 func TestOrganizePreviewEndpointAcceptsCustomLayoutTemplate(t *testing.T) {
@@ -329,6 +369,33 @@ func TestOrganizeRunEndpointMovesFiles(t *testing.T) {
 	)
 }
 
+func TestOrganizeRunEndpointRefusesStaleApprovedMoves(t *testing.T) {
+	handler := newTestHandler(t)
+	inputDir, outputDir := createOrganizerFixture(t)
+
+	body := map[string]any{
+		"config": map[string]any{
+			"base_dir":   inputDir,
+			"output_dir": outputDir,
+			"layout":     "author-title",
+			"field_mapping": map[string]any{
+				"title_field":   "title",
+				"series_field":  "series",
+				"author_fields": []string{"authors"},
+			},
+		},
+		"approved_moves": []map[string]string{{
+			"from": filepath.Join(inputDir, "test_book"),
+			"to":   filepath.Join(outputDir, "Somebody Else", "Another Book"),
+		}},
+	}
+
+	rec := performRequest(handler, http.MethodPost, "/api/organize/run", body, testToken)
+
+	assertStatus(t, rec, http.StatusConflict)
+	assertFileExists(t, filepath.Join(inputDir, "test_book", "audio.mp3"))
+}
+
 func TestRenamePreviewEndpointReturnsCandidates(t *testing.T) {
 	handler := newTestHandler(t)
 	inputDir := createRenameFixture(t)
@@ -393,6 +460,44 @@ func TestRenameRunEndpointRenamesFiles(t *testing.T) {
 	assertFileMissing(t, filepath.Join(inputDir, "rename_book", "audio.mp3"))
 }
 
+func TestReadOnlyServerRejectsMutatingEndpoints(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := srv.app.Config()
+	cfg.ReadOnly = true
+	srv.app = app.NewService(cfg)
+	handler := srv.routes()
+	inputDir, outputDir := createOrganizerFixture(t)
+
+	organizeBody := map[string]any{
+		"config": map[string]any{
+			"base_dir":   inputDir,
+			"output_dir": outputDir,
+			"layout":     "author-title",
+		},
+	}
+	for _, path := range []string{
+		"/api/organize/run",
+		"/api/rename/run",
+		"/api/abs/scan-trigger",
+		"/api/abs/clean-missing",
+		"/api/fs/mkdir",
+	} {
+		t.Run(path, func(t *testing.T) {
+			rec := performRequest(handler, http.MethodPost, path, organizeBody, testToken)
+			assertStatus(t, rec, http.StatusForbidden)
+		})
+	}
+	assertFileExists(t, filepath.Join(inputDir, "test_book", "audio.mp3"))
+
+	rec := performRequest(handler, http.MethodPost, "/api/organize/preview", organizeBody, testToken)
+	assertStatus(t, rec, http.StatusOK)
+	assertJSONArrayLength(t, rec, "summary.Moves", 1)
+
+	rec = performRequest(handler, http.MethodGet, "/api/config/initial", nil, testToken)
+	assertStatus(t, rec, http.StatusOK)
+	assertJSONField(t, rec, "read_only", true)
+}
+
 func TestABSTestPathsEndpointWorksWithoutDocker(t *testing.T) {
 	handler := newTestHandler(t)
 
@@ -9,8 +9,14 @@ import (
 
 // Run initializes and starts the TUI application
 func Run(inputDir, outputDir string) error {
+	return RunWithSetup(inputDir, outputDir, models.SetupOptions{})
+}
+
+// RunWithSetup starts the TUI application, running the first-run setup wizard
+// before the scan when setup.FirstRun is set
+func RunWithSetup(inputDir, outputDir string, setup models.SetupOptions) error {
 	// Create the initial model
-	m := models.NewMainModel(inputDir, outputDir)
+	m := models.NewMainModelWithSetup(inputDir, outputDir, setup)
 
 	// Initialize the program
 	p := tea.NewProgram(m, tea.WithAltScreen())
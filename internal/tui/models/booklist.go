@@ -146,19 +146,19 @@ func NewCustomDelegate() list.ItemDelegate {
 	d := CustomDelegate{}
 
 	// Initialize styles struct
-	d.Styles.NormalTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("#2BFFB5")).Bold(true)
+	d.Styles.NormalTitle = lipgloss.NewStyle().Foreground(themeColor("#2BFFB5")).Bold(true)
 	d.Styles.SelectedTitle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FFFFFF")).
+		Background(themeColor("#7D56F4")).
 		Bold(true)
-	d.Styles.NormalDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("#CCCCCC"))
-	d.Styles.SelectedDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("#DDDDDD"))
-	d.Styles.DimmedDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+	d.Styles.NormalDesc = lipgloss.NewStyle().Foreground(themeColor("#CCCCCC"))
+	d.Styles.SelectedDesc = lipgloss.NewStyle().Foreground(themeColor("#DDDDDD"))
+	d.Styles.DimmedDesc = lipgloss.NewStyle().Foreground(themeColor("#666666"))
 
 	d.Styles.NormalItemStyle = lipgloss.NewStyle().PaddingLeft(2)
 	d.Styles.SelectedItemStyle = lipgloss.NewStyle().
 		PaddingLeft(2).
-		Background(lipgloss.Color("#333333"))
+		Background(themeColor("#333333"))
 
 	// Set other properties - make items very compact to show more books
 	d.ItemHeight = 1
@@ -251,8 +251,8 @@ func NewBookListModel(books []AudioBook) *BookListModel {
 	l.SetFilteringEnabled(true)
 	l.SetShowStatusBar(true)
 	l.SetShowPagination(true)
-	l.Styles.Title = l.Styles.Title.Background(lipgloss.Color("#7D56F4")).
-		Foreground(lipgloss.Color("#FFFFFF")).
+	l.Styles.Title = l.Styles.Title.Background(themeColor("#7D56F4")).
+		Foreground(themeColor("#FFFFFF")).
 		Bold(true).
 		Padding(0, 1)
 
@@ -359,8 +359,8 @@ func (m *BookListModel) View() string {
 	// Header
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1).
 		Render("📚 Audiobook Selection")
 
@@ -376,7 +376,7 @@ func (m *BookListModel) View() string {
 
 	// Selection info with better styling
 	selectionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
+		Foreground(themeColor("#FFFF00")).
 		Bold(true).
 		MarginBottom(1)
 
@@ -386,7 +386,7 @@ func (m *BookListModel) View() string {
 	// Add book count if no books are found
 	if len(m.items) == 0 {
 		emptyMsg := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
+			Foreground(themeColor("#FF0000")).
 			Bold(true).
 			Render("No audiobooks found. Please go back and scan again.")
 		content.WriteString(emptyMsg + "\n\n")
@@ -398,7 +398,7 @@ func (m *BookListModel) View() string {
 	// Show filter status if filtering
 	if m.filterState.filtering && m.filterState.query != "" {
 		filterStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FFFF")).
+			Foreground(themeColor("#00FFFF")).
 			Bold(true).
 			MarginTop(1)
 
@@ -408,7 +408,7 @@ func (m *BookListModel) View() string {
 
 	// Footer with help text
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
+		Foreground(themeColor("#888")).
 		MarginTop(1)
 
 	footerText := "Space: toggle selection • a: select all • n: deselect all • /: filter • Esc: clear filter • Enter: continue"
@@ -2,13 +2,23 @@ package models
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 )
 
+// Files written next to each other by the 'w' key
+const (
+	commandScriptName = "audiobook-organizer.sh"
+	selectionFileName = "audiobook-organizer-selection.txt"
+)
+
 // CommandOutputModel represents the command output screen
 type CommandOutputModel struct {
 	books        []AudioBook
@@ -17,6 +27,16 @@ type CommandOutputModel struct {
 	command      string
 	width        int
 	height       int
+
+	scoped    bool   // Only some of the books found are selected
+	scriptDir string // Where the shell script and selection file are written
+	status    string // Result of writing the script
+
+	// Captured output of a dry run through the organizer, shown instead of the command
+	dryRun       bool
+	output       []string
+	runErr       error
+	scrollOffset int
 }
 
 // NewCommandOutputModel creates a new command output model
@@ -30,6 +50,10 @@ func NewCommandOutputModel(
 		config:       config,
 		fieldMapping: fieldMapping,
 	}
+	if found, err := strconv.Atoi(config["Books Found"]); err == nil && found > len(books) {
+		m.scoped = true
+	}
+	m.scriptDir, _ = os.Getwd()
 
 	// Generate the CLI command
 	m.command = m.generateCommand()
@@ -37,6 +61,22 @@ func NewCommandOutputModel(
 	return m
 }
 
+// NewDryRunOutputModel creates a command output screen showing what a dry run
+// through the organizer printed
+func NewDryRunOutputModel(
+	books []AudioBook,
+	config map[string]string,
+	fieldMapping organizer.FieldMapping,
+	output string,
+	runErr error,
+) *CommandOutputModel {
+	m := NewCommandOutputModel(books, config, fieldMapping)
+	m.dryRun = true
+	m.output = strings.Split(strings.TrimRight(output, "\n"), "\n")
+	m.runErr = runErr
+	return m
+}
+
 // Init initializes the model
 func (m *CommandOutputModel) Init() tea.Cmd {
 	return nil
@@ -51,18 +91,18 @@ func (m *CommandOutputModel) generateCommand() string {
 
 	// Add input directory
 	if inputDir := m.config["Input Directory"]; inputDir != "" {
-		parts = append(parts, fmt.Sprintf("--dir=\"%s\"", inputDir))
+		parts = append(parts, "--dir="+shellQuote(inputDir))
 	}
 
 	// Add output directory
 	if outputDir := m.config["Output Directory"]; outputDir != "" {
-		parts = append(parts, fmt.Sprintf("--out=\"%s\"", outputDir))
+		parts = append(parts, "--out="+shellQuote(outputDir))
 	}
 
 	// Add layout
 	if layout := m.config["Layout"]; layout == "custom" {
 		if layoutTemplate := strings.TrimSpace(m.config["Layout Template"]); layoutTemplate != "" {
-			parts = append(parts, fmt.Sprintf("--layout-template=%q", layoutTemplate))
+			parts = append(parts, "--layout-template="+shellQuote(layoutTemplate))
 		}
 	} else if layout := m.config["Layout"]; layout != "" && layout != "author-series-title" {
 		parts = append(parts, fmt.Sprintf("--layout=%s", layout))
@@ -86,29 +126,32 @@ func (m *CommandOutputModel) generateCommand() string {
 	// Add field mapping if not using defaults
 	if !m.fieldMapping.IsEmpty() {
 		if m.fieldMapping.TitleField != "" && m.fieldMapping.TitleField != "title" {
-			parts = append(parts, fmt.Sprintf("--title-field=%s", m.fieldMapping.TitleField))
+			parts = append(parts, "--title-field="+shellQuote(m.fieldMapping.TitleField))
 		}
 		if m.fieldMapping.SeriesField != "" && m.fieldMapping.SeriesField != "series" {
-			parts = append(parts, fmt.Sprintf("--series-field=%s", m.fieldMapping.SeriesField))
+			parts = append(parts, "--series-field="+shellQuote(m.fieldMapping.SeriesField))
 		}
 		if len(m.fieldMapping.AuthorFields) > 0 {
 			// Check if it's not the default
 			defaultAuthors := []string{"authors", "artist", "album_artist"}
 			if !equalStringSlices(m.fieldMapping.AuthorFields, defaultAuthors) {
-				parts = append(
-					parts,
-					fmt.Sprintf(
-						"--author-fields=\"%s\"",
-						strings.Join(m.fieldMapping.AuthorFields, ","),
-					),
-				)
+				parts = append(parts,
+					"--author-fields="+shellQuote(strings.Join(m.fieldMapping.AuthorFields, ",")))
 			}
 		}
 		if m.fieldMapping.TrackField != "" && m.fieldMapping.TrackField != "track" {
-			parts = append(parts, fmt.Sprintf("--track-field=%s", m.fieldMapping.TrackField))
+			parts = append(parts, "--track-field="+shellQuote(m.fieldMapping.TrackField))
+		}
+		if m.fieldMapping.DiscField != "" && m.fieldMapping.DiscField != "disc" {
+			parts = append(parts, "--disc-field="+shellQuote(m.fieldMapping.DiscField))
 		}
 	}
 
+	// Books left unselected (or hidden by a filter) are excluded through a selection file
+	if m.scoped {
+		parts = append(parts, "--selection="+shellQuote(m.selectionPath()))
+	}
+
 	// ALWAYS add --dry-run as the last flag for safety
 	// Users can remove it if they're ready to actually move files
 	parts = append(parts, "--dry-run")
@@ -117,6 +160,53 @@ func (m *CommandOutputModel) generateCommand() string {
 	return strings.Join(parts, " \\\n  ")
 }
 
+// shellQuote quotes s for a POSIX shell when it contains anything beyond plain path
+// characters
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("/._-,+=:@", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// scriptPath and selectionPath are where 'w' writes the shell script and the list of
+// selected books
+func (m *CommandOutputModel) scriptPath() string {
+	return filepath.Join(m.scriptDir, commandScriptName)
+}
+
+func (m *CommandOutputModel) selectionPath() string {
+	return filepath.Join(m.scriptDir, selectionFileName)
+}
+
+// writeScript writes the command to an executable shell script, along with the
+// selection file it refers to
+func (m *CommandOutputModel) writeScript() error {
+	if m.scoped {
+		header := fmt.Sprintf("Books selected in the audiobook-organizer TUI on %s\nUsed by %s",
+			time.Now().Format("2006-01-02 15:04"), commandScriptName)
+		paths := selectionPaths(m.books, m.config["Flat Mode"] == "Yes")
+		for i, path := range paths {
+			if abs, err := filepath.Abs(path); err == nil {
+				paths[i] = abs
+			}
+		}
+		if err := organizer.WriteSelectionFile(m.selectionPath(), header, paths); err != nil {
+			return err
+		}
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+# Generated by the audiobook-organizer TUI on %s
+# Remove --dry-run to move files.
+exec %s
+`, time.Now().Format("2006-01-02 15:04"), m.command)
+	return os.WriteFile(m.scriptPath(), []byte(script), 0755)
+}
+
 // equalStringSlices compares two string slices for equality
 func equalStringSlices(a, b []string) bool {
 	if len(a) != len(b) {
@@ -146,46 +236,87 @@ func (m *CommandOutputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "b", "backspace":
 			// Go back to preview
 			return NewPreviewModel(m.books, m.config, m.fieldMapping), nil
+
+		case "w":
+			if m.dryRun {
+				break
+			}
+			if err := m.writeScript(); err != nil {
+				m.status = fmt.Sprintf("❌ Could not write the script: %v", err)
+			} else {
+				m.status = fmt.Sprintf("✅ Wrote %s", m.scriptPath())
+				if m.scoped {
+					m.status += fmt.Sprintf(" and %s", m.selectionPath())
+				}
+			}
+
+		case "up", "k":
+			if m.scrollOffset > 0 {
+				m.scrollOffset--
+			}
+
+		case "down", "j":
+			if m.scrollOffset < len(m.output)-m.visibleLines() {
+				m.scrollOffset++
+			}
 		}
 	}
 
 	return m, nil
 }
 
+// visibleLines is how many lines of dry-run output fit on screen
+func (m *CommandOutputModel) visibleLines() int {
+	if m.height <= 8 {
+		return 20
+	}
+	return m.height - 8
+}
+
 // View renders the UI
 func (m *CommandOutputModel) View() string {
+	if m.dryRun {
+		return m.renderDryRun()
+	}
+
 	var content strings.Builder
 
 	// Header
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1).
 		Render("📋 Generated CLI Command")
 
 	content.WriteString(header + "\n\n")
 
 	// Description
-	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00"))
+	descStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFF00"))
 	content.WriteString(
 		descStyle.Render("Copy and paste this command to run with the same settings:") + "\n",
 	)
 
 	// Safety note
-	noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF8800")).Italic(true)
+	noteStyle := lipgloss.NewStyle().Foreground(themeColor("#FF8800")).Italic(true)
 	content.WriteString(
 		noteStyle.Render(
 			"Note: --dry-run is always included for safety. Remove it to actually move files.",
-		) + "\n\n",
+		) + "\n",
 	)
+	if m.scoped {
+		content.WriteString(
+			noteStyle.Render("Only the selected books are organized: press w to write the selection file it uses.") + "\n",
+		)
+	}
+	content.WriteString("\n")
 
 	// Separator line
 	content.WriteString(strings.Repeat("─", 80) + "\n")
 
 	// Command in plain text (easy to copy)
 	commandStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FF00"))
+		Foreground(themeColor("#00FF00"))
 
 	content.WriteString(commandStyle.Render(m.command) + "\n")
 
@@ -195,11 +326,11 @@ func (m *CommandOutputModel) View() string {
 	// Configuration summary
 	content.WriteString(lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
+		Foreground(themeColor("#00FFFF")).
 		Render("Configuration Summary:") + "\n\n")
 
-	summaryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
-	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAFF"))
+	summaryStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
+	labelStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAFF"))
 
 	// Show key settings with proper alignment
 	content.WriteString(
@@ -256,7 +387,7 @@ func (m *CommandOutputModel) View() string {
 	if !m.fieldMapping.IsEmpty() {
 		content.WriteString("\n" + lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#00FFFF")).
+			Foreground(themeColor("#00FFFF")).
 			Render("Field Mapping:") + "\n\n")
 
 		if m.fieldMapping.TitleField != "" {
@@ -300,7 +431,7 @@ func (m *CommandOutputModel) View() string {
 	// Statistics
 	content.WriteString("\n" + lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
+		Foreground(themeColor("#00FFFF")).
 		Render("Statistics:") + "\n\n")
 
 	content.WriteString(
@@ -311,12 +442,66 @@ func (m *CommandOutputModel) View() string {
 		),
 	)
 
+	if m.scoped {
+		content.WriteString(
+			fmt.Sprintf(
+				"  %s %s\n",
+				labelStyle.Render("Books found:"),
+				summaryStyle.Render(m.config["Books Found"]+" (the rest are excluded by --selection)"),
+			),
+		)
+	}
+
+	if m.status != "" {
+		content.WriteString("\n" + m.status + "\n")
+	}
+
 	// Footer with help text
 	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
-		Render("\n\nb: Back • q: Quit")
+		Foreground(themeColor("#888")).
+		Render("\n\nw: Write shell script • b: Back • q: Quit")
 
 	content.WriteString(footer)
 
 	return content.String()
 }
+
+// renderDryRun shows the captured organizer output a page at a time
+func (m *CommandOutputModel) renderDryRun() string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
+		Padding(0, 1).
+		Render("🧪 Dry-Run Output")
+	content.WriteString(header + "\n\n")
+
+	if m.runErr != nil {
+		content.WriteString(lipgloss.NewStyle().
+			Foreground(themeColor("#FF5555")).
+			Render(fmt.Sprintf("Dry run failed: %v", m.runErr)) + "\n\n")
+	}
+
+	end := m.scrollOffset + m.visibleLines()
+	if end > len(m.output) {
+		end = len(m.output)
+	}
+	if m.scrollOffset > 0 {
+		content.WriteString("↑ Scroll up for more\n")
+	}
+	for _, line := range m.output[m.scrollOffset:end] {
+		content.WriteString(line + "\n")
+	}
+	if end < len(m.output) {
+		content.WriteString("↓ Scroll down for more\n")
+	}
+
+	footer := lipgloss.NewStyle().
+		Foreground(themeColor("#888")).
+		Render("\n↑/↓: Scroll • b: Back to preview • q: Quit")
+	content.WriteString(footer)
+
+	return content.String()
+}
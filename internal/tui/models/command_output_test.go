@@ -0,0 +1,115 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"/books/in":            "/books/in",
+		"/books/My Books":      "'/books/My Books'",
+		"{author}/{title}":     "'{author}/{title}'",
+		"/books/Tolkien's":     `'/books/Tolkien'\''s'`,
+		"authors,artist":       "authors,artist",
+		"":                     "''",
+		"/books/$HOME/\"odd\"": `'/books/$HOME/"odd"'`,
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestCommandOutputModelCommand(t *testing.T) {
+	books := []AudioBook{{Path: "/books/in/Dune/01.mp3"}}
+	config := map[string]string{
+		"Input Directory":       "/books/in",
+		"Output Directory":      "/books/My Library",
+		"Layout":                "custom",
+		"Layout Template":       "{author}/{title}",
+		"Use Embedded Metadata": "Yes",
+		"Flat Mode":             "No",
+		"Verbose":               "No",
+		"Books Found":           "1",
+	}
+	fieldMapping := organizer.FieldMapping{
+		TitleField:   "album",
+		SeriesField:  "series",
+		AuthorFields: []string{"authors", "narrators", "artist"},
+		TrackField:   "track",
+		DiscField:    "tpos",
+	}
+
+	m := NewCommandOutputModel(books, config, fieldMapping)
+	for _, part := range []string{
+		"--dir=/books/in",
+		"--out='/books/My Library'",
+		"--layout-template='{author}/{title}'",
+		"--use-embedded-metadata",
+		"--title-field=album",
+		"--author-fields=authors,narrators,artist",
+		"--disc-field=tpos",
+	} {
+		if !strings.Contains(m.command, part) {
+			t.Errorf("command missing %s:\n%s", part, m.command)
+		}
+	}
+	if strings.Contains(m.command, "--selection") {
+		t.Error("command is scoped to a selection although every book is selected")
+	}
+	if !strings.HasSuffix(m.command, "--dry-run") {
+		t.Error("command does not end with --dry-run")
+	}
+}
+
+func TestCommandOutputModelWritesScriptAndSelection(t *testing.T) {
+	dir := t.TempDir()
+	books := []AudioBook{
+		{Path: "/books/in/Dune/01.mp3"},
+		{Path: "/books/in/Dune/02.mp3"},
+	}
+	config := map[string]string{
+		"Input Directory": "/books/in",
+		"Flat Mode":       "No",
+		"Books Found":     "5",
+	}
+
+	m := NewCommandOutputModel(books, config, organizer.FieldMapping{})
+	m.scriptDir = dir
+	m.command = m.generateCommand()
+	if !strings.Contains(m.command, "--selection="+filepath.Join(dir, selectionFileName)) {
+		t.Fatalf("command is not scoped to the selection file:\n%s", m.command)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if !strings.Contains(m.View(), "Wrote") {
+		t.Errorf("View() does not report the written script:\n%s", m.View())
+	}
+
+	script, err := os.ReadFile(filepath.Join(dir, commandScriptName))
+	if err != nil {
+		t.Fatalf("script not written: %v", err)
+	}
+	if !strings.HasPrefix(string(script), "#!/bin/sh") || !strings.Contains(string(script), m.command) {
+		t.Errorf("script does not run the command:\n%s", script)
+	}
+	if info, err := os.Stat(filepath.Join(dir, commandScriptName)); err != nil || info.Mode()&0o100 == 0 {
+		t.Error("script is not executable")
+	}
+
+	// Outside flat mode each book directory is listed once
+	paths, err := organizer.ReadSelectionFile(filepath.Join(dir, selectionFileName))
+	if err != nil {
+		t.Fatalf("selection file not readable: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/books/in/Dune" {
+		t.Errorf("selection = %v, want [/books/in/Dune]", paths)
+	}
+}
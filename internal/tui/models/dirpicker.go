@@ -35,8 +35,13 @@ type DirPickerModel struct {
 	scrollOffset int
 	allDirs      []string // All directories in current location
 	cursor       int      // Cursor for non-filtered navigation
+	showDrives   bool     // Listing the drives above the filesystem roots (Windows)
 }
 
+// availableDrives lists the drive roots shown above the filesystem roots; tests
+// replace it to simulate Windows
+var availableDrives = listDrives
+
 // NewDirPickerModel creates a new directory picker model
 func NewDirPickerModel(mode PickerMode, inputDir string) *DirPickerModel {
 	fp := filepicker.New()
@@ -85,8 +90,13 @@ func (m *DirPickerModel) loadDirectories() {
 	m.allDirs = nil
 	m.cursor = 0
 
-	// Add ".." at the top if not at root
-	if m.filepicker.CurrentDirectory != "/" {
+	if m.showDrives {
+		m.allDirs = availableDrives()
+		return
+	}
+
+	// Add ".." at the top if not at root, or at a root below the drive list
+	if m.canGoUp() {
 		m.allDirs = append(m.allDirs, "..")
 	}
 
@@ -136,40 +146,21 @@ func (m *DirPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "ctrl+b":
 			// Navigate up one directory level
-			if m.filepicker.CurrentDirectory != "/" {
-				parent := filepath.Dir(m.filepicker.CurrentDirectory)
-				m.filepicker.CurrentDirectory = parent
-				m.filterText = ""
-				m.filterActive = false
-				m.filterCursor = 0
-				m.scrollOffset = 0
-				m.loadDirectories()
-				return m, m.filepicker.Init()
-			}
-			return m, nil
+			return m, m.goUp()
 
 		case "ctrl+h":
 			// Jump to home directory
 			if homeDir, err := os.UserHomeDir(); err == nil {
-				m.filepicker.CurrentDirectory = homeDir
-				m.filterText = ""
-				m.filterActive = false
-				m.filterCursor = 0
-				m.scrollOffset = 0
-				m.loadDirectories()
-				return m, m.filepicker.Init()
+				return m, m.changeDirectory(homeDir)
 			}
 			return m, nil
 
 		case "ctrl+r":
-			// Jump to root directory
-			m.filepicker.CurrentDirectory = "/"
-			m.filterText = ""
-			m.filterActive = false
-			m.filterCursor = 0
-			m.scrollOffset = 0
-			m.loadDirectories()
-			return m, m.filepicker.Init()
+			// Jump to the root of the current drive or share ("/" outside Windows)
+			if m.showDrives {
+				return m, nil
+			}
+			return m, m.changeDirectory(volumeRoot(m.filepicker.CurrentDirectory))
 
 		case "esc":
 			// Clear filter
@@ -255,35 +246,27 @@ func (m *DirPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Handle ".." specially
 			if selectedDir == ".." {
-				if m.filepicker.CurrentDirectory != "/" {
-					parent := filepath.Dir(m.filepicker.CurrentDirectory)
-					m.filepicker.CurrentDirectory = parent
-					m.filterText = ""
-					m.filterActive = false
-					m.filterCursor = 0
-					m.scrollOffset = 0
-					m.loadDirectories()
-					return m, m.filepicker.Init()
-				}
-				return m, nil
+				return m, m.goUp()
+			}
+
+			// Drive list entries are already roots such as C:\
+			if m.showDrives {
+				return m, m.changeDirectory(selectedDir)
 			}
 
 			selectedPath := filepath.Join(m.filepicker.CurrentDirectory, selectedDir)
 
 			// Check if it's a valid directory
 			if info, err := os.Stat(selectedPath); err == nil && info.IsDir() {
-				m.filepicker.CurrentDirectory = selectedPath
-				m.filterText = ""
-				m.filterActive = false
-				m.filterCursor = 0
-				m.scrollOffset = 0
-				m.loadDirectories()
-				return m, m.filepicker.Init()
+				return m, m.changeDirectory(selectedPath)
 			}
 			return m, nil
 
 		case "ctrl+s", "ctrl+d":
 			// Select current directory (Ctrl+S for "select" or Ctrl+D for "done")
+			if m.showDrives {
+				return m, nil // Pick a drive first
+			}
 			currentDir := m.filepicker.CurrentDirectory
 			if m.mode == PickingInput {
 				m.inputDir = currentDir
@@ -347,12 +330,12 @@ func (m *DirPickerModel) View() string {
 	// Header
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
+		Foreground(themeColor("#FFFF00")).
 		Italic(true)
 
 	var header, description string
@@ -368,14 +351,16 @@ func (m *DirPickerModel) View() string {
 	content += descStyle.Render(description) + "\n\n"
 
 	// Show current directory
-	currentDirStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AAFF"))
-	content += currentDirStyle.Render(
-		fmt.Sprintf("Current: %s", m.filepicker.CurrentDirectory),
-	) + "\n\n"
+	currentDirStyle := lipgloss.NewStyle().Foreground(themeColor("#00AAFF"))
+	currentDir := m.filepicker.CurrentDirectory
+	if m.showDrives {
+		currentDir = "Drives"
+	}
+	content += currentDirStyle.Render(fmt.Sprintf("Current: %s", currentDir)) + "\n\n"
 
 	// Show currently selected input dir if we're picking output
 	if m.mode == PickingOutput && m.inputDir != "" {
-		infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
+		infoStyle := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))
 		content += infoStyle.Render(fmt.Sprintf("✓ Input Directory: %s", m.inputDir)) + "\n\n"
 	}
 
@@ -398,7 +383,7 @@ func (m *DirPickerModel) View() string {
 	}
 
 	if len(dirsToShow) == 0 {
-		noResultsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+		noResultsStyle := lipgloss.NewStyle().Foreground(themeColor("#FF0000"))
 		if m.filterActive {
 			content += noResultsStyle.Render("No matching directories") + "\n"
 		} else {
@@ -412,7 +397,7 @@ func (m *DirPickerModel) View() string {
 
 			if i == currentCursor {
 				cursor = "> "
-				style = style.Bold(true).Foreground(lipgloss.Color("#00FF00"))
+				style = style.Bold(true).Foreground(themeColor("#00FF00"))
 			}
 
 			content += cursor + style.Render(dir) + "\n"
@@ -423,7 +408,7 @@ func (m *DirPickerModel) View() string {
 	content += "\n\n"
 	if m.filterActive {
 		filterStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
+			Foreground(themeColor("#00FF00")).
 			Bold(true)
 		content += filterStyle.Render(
 			fmt.Sprintf("Filter: %s_ (%d matches)", m.filterText, len(m.filteredDirs)),
@@ -433,7 +418,7 @@ func (m *DirPickerModel) View() string {
 	}
 
 	// Help text
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888"))
+	helpStyle := lipgloss.NewStyle().Foreground(themeColor("#888"))
 	content += helpStyle.Render(
 		"↑/↓: Navigate • Enter: Open Directory • Ctrl+S: Select Current Directory",
 	)
@@ -456,16 +441,28 @@ func (m *DirPickerModel) GetOutputDir() string {
 
 // getFilteredDirectories reads directories from current path and filters them
 func (m *DirPickerModel) getFilteredDirectories() ([]string, error) {
-	entries, err := os.ReadDir(m.filepicker.CurrentDirectory)
-	if err != nil {
-		return nil, err
+	var entries []os.DirEntry
+	if !m.showDrives {
+		var err error
+		if entries, err = os.ReadDir(m.filepicker.CurrentDirectory); err != nil {
+			return nil, err
+		}
 	}
 
 	var dirs []string
 	filterLower := strings.ToLower(m.filterText)
 
-	// Add ".." at the top if not at root
-	if m.filepicker.CurrentDirectory != "/" {
+	if m.showDrives {
+		for _, drive := range availableDrives() {
+			if strings.Contains(strings.ToLower(drive), filterLower) {
+				dirs = append(dirs, drive)
+			}
+		}
+		return dirs, nil
+	}
+
+	// Add ".." at the top if not at root, or at a root below the drive list
+	if m.canGoUp() {
 		// Only show .. if filter doesn't exclude it
 		if !m.filterActive || strings.Contains("..", filterLower) {
 			dirs = append(dirs, "..")
@@ -497,3 +494,48 @@ func (m *DirPickerModel) getFilteredDirectories() ([]string, error) {
 
 	return dirs, nil
 }
+
+// canGoUp reports whether ".." leads somewhere: a parent directory, or the drive
+// list from the root of a drive or UNC share
+func (m *DirPickerModel) canGoUp() bool {
+	if m.showDrives {
+		return false
+	}
+	return !isRootDir(m.filepicker.CurrentDirectory) || len(availableDrives()) > 0
+}
+
+// goUp moves to the parent directory, or from a root to the drive list
+func (m *DirPickerModel) goUp() tea.Cmd {
+	if !m.canGoUp() {
+		return nil
+	}
+	if isRootDir(m.filepicker.CurrentDirectory) {
+		m.showDrives = true
+		m.resetFilter()
+		m.loadDirectories()
+		return nil
+	}
+	return m.changeDirectory(filepath.Dir(m.filepicker.CurrentDirectory))
+}
+
+// changeDirectory leaves the drive list if it is shown and opens dir
+func (m *DirPickerModel) changeDirectory(dir string) tea.Cmd {
+	m.showDrives = false
+	m.filepicker.CurrentDirectory = dir
+	m.resetFilter()
+	m.loadDirectories()
+	return m.filepicker.Init()
+}
+
+func (m *DirPickerModel) resetFilter() {
+	m.filterText = ""
+	m.filterActive = false
+	m.filterCursor = 0
+	m.scrollOffset = 0
+}
+
+// volumeRoot returns the root of the drive or UNC share holding dir, such as C:\
+// or \\server\share\, or "/" outside Windows
+func volumeRoot(dir string) string {
+	return filepath.VolumeName(dir) + string(filepath.Separator)
+}
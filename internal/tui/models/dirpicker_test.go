@@ -0,0 +1,65 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDirPickerDriveList(t *testing.T) {
+	driveC := t.TempDir()
+	driveD := t.TempDir()
+	if err := os.Mkdir(filepath.Join(driveD, "Audiobooks"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	original := availableDrives
+	availableDrives = func() []string { return []string{driveC, driveD} }
+	defer func() { availableDrives = original }()
+
+	root := volumeRoot(driveC)
+	m := NewDirPickerModel(PickingInput, root)
+	if len(m.allDirs) == 0 || m.allDirs[0] != ".." {
+		t.Fatalf("expected .. at a root with drives available, got %v", m.allDirs)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if !m.showDrives {
+		t.Fatal("ctrl+b at a root should show the drive list")
+	}
+	if strings.Join(m.allDirs, "|") != driveC+"|"+driveD {
+		t.Errorf("drive list = %v", m.allDirs)
+	}
+	if !strings.Contains(m.View(), "Current: Drives") {
+		t.Error("view should show the drive list as the current location")
+	}
+
+	// Selecting the current directory needs a drive first
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.GetInputDir() != root {
+		t.Errorf("ctrl+s on the drive list changed the input dir to %q", m.GetInputDir())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.showDrives || m.filepicker.CurrentDirectory != driveD {
+		t.Fatalf("enter on a drive should open it, at %q", m.filepicker.CurrentDirectory)
+	}
+	if strings.Join(m.allDirs, "|") != "..|Audiobooks" {
+		t.Errorf("drive contents = %v", m.allDirs)
+	}
+}
+
+func TestDirPickerRootKey(t *testing.T) {
+	dir := t.TempDir()
+	m := NewDirPickerModel(PickingInput, dir)
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if want := volumeRoot(dir); m.filepicker.CurrentDirectory != want {
+		t.Errorf("ctrl+r went to %q, want %q", m.filepicker.CurrentDirectory, want)
+	}
+	if filepath.VolumeName(dir) == "" && m.filepicker.CurrentDirectory != string(filepath.Separator) {
+		t.Errorf("ctrl+r without a volume should go to %q", string(filepath.Separator))
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package models
+
+// listDrives returns nil; only Windows has drive letters above the filesystem root
+func listDrives() []string {
+	return nil
+}
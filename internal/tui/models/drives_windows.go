@@ -0,0 +1,20 @@
+//go:build windows
+
+package models
+
+import "golang.org/x/sys/windows"
+
+// listDrives returns the root of every drive letter in use, such as C:\
+func listDrives() []string {
+	mask, err := windows.GetLogicalDrives()
+	if err != nil {
+		return nil
+	}
+	var drives []string
+	for i := 0; i < 26; i++ {
+		if mask&(1<<uint(i)) != 0 {
+			drives = append(drives, string(rune('A'+i))+`:\`)
+		}
+	}
+	return drives
+}
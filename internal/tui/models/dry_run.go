@@ -0,0 +1,121 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// DryRunCompleteMsg carries the captured output of a dry run through the organizer
+type DryRunCompleteMsg struct {
+	Output string
+	Err    error
+}
+
+// newOrganizerConfig builds the organizer configuration for the settings chosen in
+// the TUI
+func newOrganizerConfig(
+	books []AudioBook,
+	settings map[string]string,
+	fieldMapping organizer.FieldMapping,
+) *organizer.OrganizerConfig {
+	// Get directories from config
+	baseDir := settings["Input Directory"]
+	outputDir := settings["Output Directory"]
+
+	// Fallback: if not in config, try to get from the first book
+	if baseDir == "" && len(books) > 0 {
+		baseDir = filepath.Dir(books[0].Path)
+	}
+	if outputDir == "" {
+		outputDir = baseDir // Use same directory if not specified
+	}
+
+	// Get layout from settings
+	layout := settings["Layout"]
+	if layout == "" {
+		layout = "author-series-title"
+	}
+	layoutTemplate := ""
+	if layout == "custom" {
+		layoutTemplate = strings.TrimSpace(settings["Layout Template"])
+		layout = "author-series-title"
+	}
+
+	return &organizer.OrganizerConfig{
+		BaseDir:             baseDir,
+		OutputDir:           outputDir,
+		Layout:              layout,
+		LayoutTemplate:      layoutTemplate,
+		UseEmbeddedMetadata: settings["Use Embedded Metadata"] == "Yes",
+		Flat:                settings["Flat Mode"] == "Yes",
+		DryRun:              settings["Dry Run"] == "Yes",
+		Verbose:             false, // Always false in TUI mode - we have our own display
+		FieldMapping:        fieldMapping,
+		RemoveEmpty:         false, // Don't remove empty directories in TUI mode
+		Prompt:              false, // Don't prompt in TUI mode
+	}
+}
+
+// selectionPaths returns the source paths the organizer should limit a run to. The
+// TUI lists individual files, while outside flat mode the organizer treats each
+// directory as one book.
+func selectionPaths(books []AudioBook, flat bool) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, book := range books {
+		path := book.Path
+		if !flat {
+			path = filepath.Dir(path)
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// runDryRun runs the real organizer over the selected books in dry-run mode and
+// returns its printed output, so the preview can be checked against the engine.
+// Files regrouped on the grouping screen are organized one by one with their album's
+// identity before the rest.
+func runDryRun(
+	books []AudioBook,
+	settings map[string]string,
+	fieldMapping organizer.FieldMapping,
+) tea.Cmd {
+	return func() tea.Msg {
+		var regrouped, rest []AudioBook
+		for _, book := range books {
+			if book.Group != nil {
+				regrouped = append(regrouped, book)
+			} else {
+				rest = append(rest, book)
+			}
+		}
+
+		config := newOrganizerConfig(books, settings, fieldMapping)
+		config.DryRun = true
+		config.Verbose = settings["Verbose"] == "Yes"
+		config.AllowedSourcePaths = selectionPaths(rest, config.Flat)
+
+		org, err := organizer.NewOrganizer(config)
+		if err != nil {
+			return DryRunCompleteMsg{Err: err}
+		}
+		output := organizer.CaptureOutput(func() {
+			for _, book := range regrouped {
+				if err = org.OrganizeSingleFile(book.Path, book.metadataProvider(fieldMapping)); err != nil {
+					return
+				}
+			}
+			if len(rest) > 0 {
+				err = org.Execute()
+			}
+		})
+		return DryRunCompleteMsg{Output: output, Err: err}
+	}
+}
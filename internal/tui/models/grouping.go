@@ -0,0 +1,381 @@
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// BookGroup is the album identity the grouping screen gave a file: the metadata of
+// the album's first file, whose mapped title, authors, and series every file of the
+// album takes, and a title that replaces the mapped one when not empty
+type BookGroup struct {
+	Leader organizer.Metadata
+	Title  string
+}
+
+// MappedMetadata returns the book's metadata with the field mapping applied and, for
+// a file regrouped on the grouping screen, its album's identity and track number
+func (b AudioBook) MappedMetadata(fieldMapping organizer.FieldMapping) organizer.Metadata {
+	metadata := b.Metadata
+	metadata.ApplyFieldMapping(fieldMapping)
+	if b.Group == nil {
+		return metadata
+	}
+
+	leader := b.Group.Leader
+	leader.ApplyFieldMapping(fieldMapping)
+	metadata.Title, metadata.Authors, metadata.Series = leader.Title, leader.Authors, leader.Series
+	if b.Group.Title != "" {
+		metadata.Title = b.Group.Title
+	}
+	metadata.TrackNumber = b.TrackNumber
+	return metadata
+}
+
+// metadataProvider returns the provider the organizer should read the book with:
+// nil reads the file, while a regrouped file gets its album's identity
+func (b AudioBook) metadataProvider(fieldMapping organizer.FieldMapping) organizer.MetadataProvider {
+	if b.Group == nil {
+		return nil
+	}
+	return organizer.NewMappedMetadataProvider(b.MappedMetadata(fieldMapping))
+}
+
+// albumGroup is one album, or one file of its own, on the grouping screen
+type albumGroup struct {
+	books   []AudioBook
+	leader  organizer.Metadata // Metadata of the file the group takes its identity from
+	title   string             // Title chosen by splitting or renaming; "" keeps the leader's
+	edited  bool               // Files take the group's identity instead of their own
+	changed bool               // Files were split off or merged in
+}
+
+// name describes the group as the scanner names albums, "Author - Title"
+func (g *albumGroup) name() string {
+	if !g.changed && !g.edited && g.books[0].IsPartOfAlbum {
+		return g.books[0].AlbumName
+	}
+	title := g.title
+	if title == "" {
+		title = g.leader.Title
+	}
+	if title == "" {
+		title = fileStem(g.books[0].Path)
+	}
+	if len(g.leader.Authors) > 0 {
+		return g.leader.Authors[0] + " - " + title
+	}
+	return title
+}
+
+// fileStem returns the file name of path without its extension
+func fileStem(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// GroupingModel shows how the scan grouped files into albums and lets a wrongly
+// merged album be split or two albums be merged before the settings
+type GroupingModel struct {
+	books    []AudioBook
+	groups   []*albumGroup
+	cursor   int // Index of the selected file across all groups
+	picked   *albumGroup
+	renaming bool
+	input    textinput.Model
+	message  string
+	width    int
+	height   int
+}
+
+// NewGroupingModel creates a grouping screen for the selected books, keeping the
+// albums the scan found
+func NewGroupingModel(books []AudioBook) *GroupingModel {
+	input := textinput.New()
+	input.Prompt = "Title: "
+	input.CharLimit = 200
+	m := &GroupingModel{books: books, input: input}
+	m.reset()
+	return m
+}
+
+// reset restores the grouping the scan found
+func (m *GroupingModel) reset() {
+	m.groups = nil
+	m.cursor = 0
+	m.picked = nil
+	albums := make(map[string]*albumGroup)
+	for _, book := range m.books {
+		key := filepath.Dir(book.Path) + "\x00" + book.AlbumName
+		if group, ok := albums[key]; ok && book.IsPartOfAlbum {
+			group.books = append(group.books, book)
+			continue
+		}
+		group := &albumGroup{books: []AudioBook{book}, leader: book.Metadata}
+		if book.IsPartOfAlbum {
+			albums[key] = group
+		}
+		m.groups = append(m.groups, group)
+	}
+}
+
+// Init initializes the model
+func (m *GroupingModel) Init() tea.Cmd {
+	return nil
+}
+
+// Editing reports whether a title is being typed, when Enter and q belong to the
+// text field
+func (m *GroupingModel) Editing() bool {
+	return m.renaming
+}
+
+// selected returns the group and position of the file under the cursor
+func (m *GroupingModel) selected() (int, int) {
+	index := m.cursor
+	for i, group := range m.groups {
+		if index < len(group.books) {
+			return i, index
+		}
+		index -= len(group.books)
+	}
+	return len(m.groups) - 1, 0
+}
+
+// Update handles messages and user input
+func (m *GroupingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if len(m.groups) == 0 {
+			return m, nil
+		}
+		if m.renaming {
+			return m.updateRename(msg)
+		}
+		m.message = ""
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.books)-1 {
+				m.cursor++
+			}
+		case "s":
+			m.split()
+		case "m":
+			m.merge()
+		case "r":
+			g, _ := m.selected()
+			m.renaming = true
+			m.input.SetValue(strings.TrimPrefix(m.groups[g].name(), firstAuthor(m.groups[g].leader)+" - "))
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		case "x":
+			m.reset()
+			m.message = "Grouping reset to the scan's"
+		case "esc":
+			m.picked = nil
+		}
+	}
+	return m, nil
+}
+
+// updateRename handles keys while a group's title is typed
+func (m *GroupingModel) updateRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if title := strings.TrimSpace(m.input.Value()); title != "" {
+			g, _ := m.selected()
+			m.groups[g].title = title
+			m.groups[g].edited = true
+		}
+		fallthrough
+	case "esc":
+		m.renaming = false
+		m.input.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// firstAuthor returns the first author of metadata, or ""
+func firstAuthor(metadata organizer.Metadata) string {
+	if len(metadata.Authors) > 0 {
+		return metadata.Authors[0]
+	}
+	return ""
+}
+
+// split makes the file under the cursor and the ones after it an album of their own,
+// titled after the first of them
+func (m *GroupingModel) split() {
+	g, position := m.selected()
+	group := m.groups[g]
+	if position == 0 {
+		m.message = "Select a file after the first one to split the album there"
+		return
+	}
+
+	first := group.books[position]
+	title := first.Metadata.Title
+	if title == "" || title == group.leader.Title {
+		title = fileStem(first.Path)
+	}
+	split := &albumGroup{
+		books:   append([]AudioBook(nil), group.books[position:]...),
+		leader:  first.Metadata,
+		title:   title,
+		edited:  true,
+		changed: true,
+	}
+	group.books = group.books[:position]
+	group.changed = true
+	m.groups = append(m.groups[:g+1], append([]*albumGroup{split}, m.groups[g+1:]...)...)
+	m.message = fmt.Sprintf("Split %d file(s) into %q; press r to rename it", len(split.books), title)
+}
+
+// merge picks the group under the cursor, or moves the files of the picked group into
+// the one under the cursor, which they then share the identity of
+func (m *GroupingModel) merge() {
+	g, _ := m.selected()
+	target := m.groups[g]
+	if m.picked == nil || m.picked == target {
+		m.picked = target
+		m.message = "Move to the album to merge into and press m again (Esc cancels)"
+		return
+	}
+
+	target.books = append(target.books, m.picked.books...)
+	target.edited, target.changed = true, true
+	for i, group := range m.groups {
+		if group == m.picked {
+			m.groups = append(m.groups[:i], m.groups[i+1:]...)
+			break
+		}
+	}
+	m.message = fmt.Sprintf("Merged %d file(s) into %s", len(m.picked.books), target.name())
+	m.picked = nil
+
+	// Keep the cursor on the first file of the merged album
+	m.cursor = 0
+	for _, group := range m.groups {
+		if group == target {
+			break
+		}
+		m.cursor += len(group.books)
+	}
+}
+
+// GetBooks returns the books in album order with the album details of the edited
+// grouping; files whose album was split or merged carry its identity in Group
+func (m *GroupingModel) GetBooks() []AudioBook {
+	var books []AudioBook
+	for _, group := range m.groups {
+		for i, book := range group.books {
+			if group.changed || group.edited {
+				book.IsPartOfAlbum = len(group.books) > 1
+				book.AlbumName, book.TrackNumber, book.TotalTracks = "", 0, 0
+				if book.IsPartOfAlbum {
+					book.AlbumName, book.TrackNumber, book.TotalTracks = group.name(), i+1, len(group.books)
+				}
+			}
+			book.Group = nil
+			if group.edited {
+				book.Group = &BookGroup{Leader: group.leader, Title: group.title}
+			}
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+// View renders the UI
+func (m *GroupingModel) View() string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
+		Padding(0, 1).
+		Render("🗂️  Album Grouping")
+	content.WriteString(header + "\n\n")
+
+	albums := 0
+	for _, group := range m.groups {
+		if len(group.books) > 1 {
+			albums++
+		}
+	}
+	content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#FFFF00")).Bold(true).
+		Render(fmt.Sprintf("%d files in %d albums and %d single books", len(m.books), albums, len(m.groups)-albums)) + "\n\n")
+
+	groupStyle := lipgloss.NewStyle().Foreground(themeColor("#2BFFB5")).Bold(true)
+	pickedStyle := lipgloss.NewStyle().Foreground(themeColor("#FFA500")).Bold(true)
+	fileStyle := lipgloss.NewStyle().Foreground(themeColor("#CCCCCC"))
+	cursorStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFFFF")).Background(themeColor("#7D56F4"))
+
+	var lines []string
+	cursorLine := 0
+	index := 0
+	for _, group := range m.groups {
+		label := fmt.Sprintf("▸ %s (%d file(s))", group.name(), len(group.books))
+		if group.edited {
+			label += " *"
+		}
+		if group == m.picked {
+			lines = append(lines, pickedStyle.Render(label+"  ← merging"))
+		} else {
+			lines = append(lines, groupStyle.Render(label))
+		}
+		for _, book := range group.books {
+			line := "    " + filepath.Base(book.Path)
+			if index == m.cursor {
+				cursorLine = len(lines)
+				line = cursorStyle.Render(line)
+			} else {
+				line = fileStyle.Render(line)
+			}
+			lines = append(lines, line)
+			index++
+		}
+	}
+
+	// Show the lines around the cursor that fit the window
+	visible := len(lines)
+	if m.height > 12 {
+		visible = min(visible, m.height-12)
+	}
+	start := max(0, min(cursorLine-visible/2, len(lines)-visible))
+	content.WriteString(strings.Join(lines[start:start+visible], "\n") + "\n")
+
+	if m.renaming {
+		content.WriteString("\n" + m.input.View() + "\n")
+	}
+	if m.message != "" {
+		content.WriteString("\n" + lipgloss.NewStyle().Foreground(themeColor("#00FFFF")).Render(m.message) + "\n")
+	}
+
+	footerStyle := lipgloss.NewStyle().Foreground(themeColor("#888")).MarginTop(1)
+	footer := "↑/↓: move • s: split album here • m: pick/merge album • r: rename album • x: reset • Enter: continue • q: back"
+	if m.renaming {
+		footer = "Enter: save title • Esc: cancel"
+	}
+	content.WriteString(footerStyle.Render(footer))
+
+	return content.String()
+}
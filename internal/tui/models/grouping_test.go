@@ -0,0 +1,124 @@
+package models
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// groupingBooks returns a scanned album of three tracks that are really two books,
+// followed by a loose file of the second book
+func groupingBooks() []AudioBook {
+	track := func(dir, name, title string, track int) AudioBook {
+		return AudioBook{
+			Path:          filepath.Join("/in", dir, name),
+			Metadata:      organizer.Metadata{Title: title, Authors: []string{"Frank Herbert"}, TrackNumber: track},
+			Selected:      true,
+			IsPartOfAlbum: dir == "dune",
+			AlbumName:     "Frank Herbert - Dune",
+			TrackNumber:   track,
+			TotalTracks:   3,
+		}
+	}
+	loose := track("loose", "Messiah 3.mp3", "Dune Messiah", 3)
+	loose.IsPartOfAlbum, loose.AlbumName, loose.TrackNumber, loose.TotalTracks = false, "", 0, 0
+	return []AudioBook{
+		track("dune", "Dune 1.mp3", "Dune", 1),
+		track("dune", "Messiah 1.mp3", "Dune", 2),
+		track("dune", "Messiah 2.mp3", "Dune", 3),
+		loose,
+	}
+}
+
+func pressKeys(m *GroupingModel, keys ...string) {
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestGroupingModelKeepsScannedAlbums(t *testing.T) {
+	m := NewGroupingModel(groupingBooks())
+	if len(m.groups) != 2 {
+		t.Fatalf("Expected the album and the loose file, got %d groups", len(m.groups))
+	}
+
+	books := m.GetBooks()
+	for i, book := range books {
+		if book.Group != nil {
+			t.Errorf("Book %d: expected no regrouping before an edit", i)
+		}
+	}
+	if books[0].AlbumName != "Frank Herbert - Dune" || books[2].TotalTracks != 3 {
+		t.Errorf("Expected the scanned album details, got %+v", books[2])
+	}
+}
+
+func TestGroupingModelSplitAndMerge(t *testing.T) {
+	m := NewGroupingModel(groupingBooks())
+
+	// Split the album before the second track, then rename the new album
+	pressKeys(m, "down", "s", "r")
+	m.input.SetValue("Dune Messiah")
+	pressKeys(m, "enter")
+	if m.Editing() {
+		t.Fatal("Expected Enter to save the title")
+	}
+	if len(m.groups) != 3 {
+		t.Fatalf("Expected 3 groups after the split, got %d", len(m.groups))
+	}
+
+	// Pick the loose file and merge it into the split-off album
+	pressKeys(m, "down", "down", "m")
+	m.cursor = 1
+	pressKeys(m, "m")
+	if len(m.groups) != 2 {
+		t.Fatalf("Expected 2 groups after the merge, got %d", len(m.groups))
+	}
+
+	books := m.GetBooks()
+	dune := books[0]
+	if dune.IsPartOfAlbum || dune.Group != nil {
+		t.Errorf("Expected the first track to be a book of its own, got %+v", dune)
+	}
+	for i, book := range books[1:] {
+		if book.Group == nil {
+			t.Fatalf("Book %d: expected the regrouped album's identity", i+1)
+		}
+		metadata := book.MappedMetadata(organizer.DefaultFieldMapping())
+		if metadata.Title != "Dune Messiah" || metadata.TrackNumber != i+1 {
+			t.Errorf("Book %d: expected track %d of Dune Messiah, got %q track %d", i+1, i+1, metadata.Title, metadata.TrackNumber)
+		}
+		if book.TotalTracks != 3 || book.AlbumName != "Frank Herbert - Dune Messiah" {
+			t.Errorf("Book %d: unexpected album %q of %d", i+1, book.AlbumName, book.TotalTracks)
+		}
+	}
+
+	// Files of one album share an output folder
+	first := GenerateOutputPath(books[1], "author-title", "", organizer.DefaultFieldMapping(), "/out")
+	last := GenerateOutputPath(books[3], "author-title", "", organizer.DefaultFieldMapping(), "/out")
+	if filepath.Dir(first) != filepath.Dir(last) {
+		t.Errorf("Expected the merged files in one folder, got %s and %s", first, last)
+	}
+
+	pressKeys(m, "x")
+	if len(m.groups) != 2 || m.GetBooks()[1].Group != nil {
+		t.Error("Expected x to restore the scanned grouping")
+	}
+}
+
+func TestGroupingModelSplitNeedsLaterTrack(t *testing.T) {
+	m := NewGroupingModel(groupingBooks())
+	pressKeys(m, "s")
+	if len(m.groups) != 2 {
+		t.Errorf("Expected splitting at the first track to do nothing, got %d groups", len(m.groups))
+	}
+}
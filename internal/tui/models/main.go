@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -18,6 +19,8 @@ const (
 	PreviewScreen
 	ProcessScreen
 	CommandOutputScreen
+	OnboardingScreen
+	GroupingScreen
 )
 
 // MainModel is the main model for the TUI application
@@ -32,11 +35,16 @@ type MainModel struct {
 	dirPickerModel        *DirPickerModel
 	scanModel             *ScanModel
 	bookListModel         *BookListModel
+	groupingModel         *GroupingModel
 	settingsModel         *SettingsTableModel
 	advancedSettingsModel *SettingsTableModel
 	previewModel          *PreviewModel
 	processModel          *ProcessModel
 	commandOutputModel    *CommandOutputModel
+	onboardingModel       *OnboardingModel
+
+	// First-run setup and the saved profile applied to new settings screens
+	setup SetupOptions
 
 	// Application state
 	quitting bool
@@ -45,16 +53,25 @@ type MainModel struct {
 
 // NewMainModel creates a new main model
 func NewMainModel(inputDir, outputDir string) *MainModel {
+	return NewMainModelWithSetup(inputDir, outputDir, SetupOptions{})
+}
+
+// NewMainModelWithSetup creates a main model that runs the first-run setup wizard
+// before scanning when setup.FirstRun is set
+func NewMainModelWithSetup(inputDir, outputDir string, setup SetupOptions) *MainModel {
 	// If no directories provided, start with directory picker
 	startScreen := ScanScreen
 	if inputDir == "" || outputDir == "" {
 		startScreen = DirPickerScreen
+	} else if setup.FirstRun {
+		startScreen = OnboardingScreen
 	}
 
 	return &MainModel{
 		inputDir:  inputDir,
 		outputDir: outputDir,
 		screen:    startScreen,
+		setup:     setup,
 	}
 }
 
@@ -66,11 +83,58 @@ func (m *MainModel) Init() tea.Cmd {
 		return m.dirPickerModel.Init()
 	}
 
-	// Initialize the scan model
-	m.scanModel = NewScanModel(m.inputDir)
+	return m.startScan()
+}
+
+// startScan runs the setup wizard on first run, otherwise starts scanning the input
+// directory
+func (m *MainModel) startScan() tea.Cmd {
+	if m.setup.FirstRun {
+		m.screen = OnboardingScreen
+		m.onboardingModel = NewOnboardingModel(m.inputDir, m.setup.SaveProfile)
+		return m.onboardingModel.Init()
+	}
+
+	m.screen = ScanScreen
+	m.scanModel = m.newScanModel()
 	return m.scanModel.Init()
 }
 
+// newScanModel creates a scan screen for the input directory with the scan depth limits
+func (m *MainModel) newScanModel() *ScanModel {
+	scan := NewScanModel(m.inputDir)
+	scan.minDepth, scan.maxDepth = m.setup.MinDepth, m.setup.MaxDepth
+	return scan
+}
+
+// newSettingsModel creates a settings screen with the saved profile applied
+func (m *MainModel) newSettingsModel(selectedBooks []AudioBook, showAdvanced bool) *SettingsTableModel {
+	settings := NewSettingsTableModel(selectedBooks, showAdvanced)
+	if m.setup.Profile != nil {
+		settings.ApplyProfile(*m.setup.Profile)
+	}
+	return settings
+}
+
+// selectedBooks returns the books chosen on the book list, with the album grouping
+// edited on the grouping screen when it was shown
+func (m *MainModel) selectedBooks() []AudioBook {
+	if m.groupingModel != nil {
+		return m.groupingModel.GetBooks()
+	}
+	return m.bookListModel.GetSelectedBooks()
+}
+
+// openSettings shows the settings screen, creating it the first time
+func (m *MainModel) openSettings() tea.Cmd {
+	m.screen = SettingsScreen
+	if m.settingsModel != nil {
+		return nil
+	}
+	m.settingsModel = m.newSettingsModel(m.selectedBooks(), false)
+	return m.settingsModel.Init()
+}
+
 // Update handles messages and user input
 func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -83,10 +147,23 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 
+		case themeToggleKey:
+			SetTheme(nextTheme())
+			for _, settings := range []*SettingsTableModel{m.settingsModel, m.advancedSettingsModel} {
+				if settings != nil {
+					settings.refreshTheme()
+				}
+			}
+			return m, nil
+
+		case glyphsToggleKey:
+			SetPlainGlyphs(!PlainGlyphs())
+			return m, nil
+
 		case "q":
 			// Handle q differently based on screen
 			switch m.screen {
-			case ScanScreen:
+			case ScanScreen, OnboardingScreen:
 				m.quitting = true
 				return m, tea.Quit
 
@@ -95,9 +172,19 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.screen = ScanScreen
 				return m, nil
 
+			case GroupingScreen:
+				// Go back to book list, unless q is typed into a title
+				if !m.groupingModel.Editing() {
+					m.screen = BookListScreen
+					return m, nil
+				}
+
 			case SettingsScreen:
-				// Go back to book list
+				// Go back to the grouping screen when it was shown, else the book list
 				m.screen = BookListScreen
+				if m.groupingModel != nil {
+					m.screen = GroupingScreen
+				}
 				return m, nil
 
 			case AdvancedSettingsScreen:
@@ -144,13 +231,29 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Both directories selected, update main model and start scan
 					m.inputDir = m.dirPickerModel.inputDir
 					m.outputDir = m.dirPickerModel.outputDir
-					m.screen = ScanScreen
-					m.scanModel = NewScanModel(m.inputDir)
-					return m, m.scanModel.Init()
+					return m, m.startScan()
 				}
 			}
 		}
 
+	case OnboardingScreen:
+		if m.onboardingModel != nil {
+			var onboardingModel tea.Model
+			onboardingModel, cmd = m.onboardingModel.Update(msg)
+			m.onboardingModel = onboardingModel.(*OnboardingModel)
+			cmds = append(cmds, cmd)
+
+			if m.onboardingModel.Done() {
+				if m.onboardingModel.Saved() {
+					profile := m.onboardingModel.Profile()
+					m.setup.Profile = &profile
+				}
+				m.setup.FirstRun = false
+				m.onboardingModel = nil
+				return m, m.startScan()
+			}
+		}
+
 	case ScanScreen:
 		if m.scanModel != nil {
 			var scanModel tea.Model
@@ -181,18 +284,37 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.bookListModel = bookListModel.(*BookListModel)
 			cmds = append(cmds, cmd)
 
-			// Check for Enter key to proceed to settings
+			// Check for Enter key to proceed to the album grouping when there are
+			// several files to group, otherwise to settings
 			if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "enter" {
-				m.screen = SettingsScreen
-				if m.settingsModel == nil {
-					// Pass selected books to settings model for preview
-					selectedBooks := m.bookListModel.GetSelectedBooks()
-					m.settingsModel = NewSettingsTableModel(selectedBooks, false)
-					cmds = append(cmds, m.settingsModel.Init())
+				m.previewModel = nil
+				m.groupingModel = nil
+				if selectedBooks := m.bookListModel.GetSelectedBooks(); len(selectedBooks) > 1 {
+					m.screen = GroupingScreen
+					m.groupingModel = NewGroupingModel(selectedBooks)
+					cmds = append(cmds, m.groupingModel.Init(), tea.WindowSize())
+				} else {
+					cmds = append(cmds, m.openSettings())
 				}
 			}
 		}
 
+	case GroupingScreen:
+		if m.groupingModel != nil {
+			editing := m.groupingModel.Editing()
+			var groupingModel tea.Model
+			groupingModel, cmd = m.groupingModel.Update(msg)
+			m.groupingModel = groupingModel.(*GroupingModel)
+			cmds = append(cmds, cmd)
+
+			// Enter proceeds to settings unless it saved a title; the preview is
+			// regenerated for the edited grouping
+			if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "enter" && !editing {
+				m.previewModel = nil
+				cmds = append(cmds, m.openSettings())
+			}
+		}
+
 	case SettingsScreen:
 		if m.settingsModel != nil {
 			var settingsModel tea.Model
@@ -209,13 +331,14 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if key == "c" || key == "n" {
 					m.screen = PreviewScreen
 					if m.previewModel == nil {
-						selectedBooks := m.bookListModel.GetSelectedBooks()
+						selectedBooks := m.selectedBooks()
 						// Get config and field mapping from unified settings model
 						config := m.settingsModel.GetConfig()
 						fieldMapping := m.settingsModel.GetFieldMapping()
 						// Add input and output directories to config
 						config["Input Directory"] = m.inputDir
 						config["Output Directory"] = m.outputDir
+						config["Books Found"] = strconv.Itoa(len(m.bookListModel.GetBooks()))
 						m.previewModel = NewPreviewModel(selectedBooks, config, fieldMapping)
 						cmds = append(cmds, m.previewModel.Init())
 					}
@@ -223,8 +346,8 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Enter goes to advanced settings (old flow - probably not needed anymore)
 					m.screen = AdvancedSettingsScreen
 					if m.advancedSettingsModel == nil {
-						selectedBooks := m.bookListModel.GetSelectedBooks()
-						m.advancedSettingsModel = NewSettingsTableModel(selectedBooks, true)
+						selectedBooks := m.selectedBooks()
+						m.advancedSettingsModel = m.newSettingsModel(selectedBooks, true)
 						cmds = append(cmds, m.advancedSettingsModel.Init())
 					}
 				}
@@ -255,13 +378,14 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if shouldAdvance {
 					m.screen = PreviewScreen
 					if m.previewModel == nil {
-						selectedBooks := m.bookListModel.GetSelectedBooks()
+						selectedBooks := m.selectedBooks()
 						// Get config from basic settings, field mapping from advanced settings
 						config := m.settingsModel.GetConfig()
 						fieldMapping := m.advancedSettingsModel.GetFieldMapping()
 						// Add input and output directories to config
 						config["Input Directory"] = m.inputDir
 						config["Output Directory"] = m.outputDir
+						config["Books Found"] = strconv.Itoa(len(m.bookListModel.GetBooks()))
 						m.previewModel = NewPreviewModel(selectedBooks, config, fieldMapping)
 						cmds = append(cmds, m.previewModel.Init())
 					}
@@ -294,6 +418,7 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case *ProcessModel:
 				// Switch to process screen (when Enter is pressed)
 				m.screen = ProcessScreen
+				previewModel.messages = m.setup.Messages
 				m.processModel = previewModel
 				cmds = append(cmds, cmd)
 				return m, tea.Batch(cmds...)
@@ -315,12 +440,13 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "r" && m.processModel.complete {
 				// Reset models and go back to scan screen
 				m.bookListModel = nil
+				m.groupingModel = nil
 				m.settingsModel = nil
 				m.advancedSettingsModel = nil
 				m.previewModel = nil
 				m.processModel = nil
 				m.commandOutputModel = nil
-				m.scanModel = NewScanModel(m.inputDir)
+				m.scanModel = m.newScanModel()
 				m.screen = ScanScreen
 				return m, m.scanModel.Init()
 			}
@@ -360,6 +486,10 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the UI
 func (m *MainModel) View() string {
+	return applyGlyphs(m.view())
+}
+
+func (m *MainModel) view() string {
 	if m.quitting {
 		return "Goodbye!\n"
 	}
@@ -379,6 +509,13 @@ func (m *MainModel) View() string {
 			content = "Initializing directory picker..."
 		}
 
+	case OnboardingScreen:
+		if m.onboardingModel != nil {
+			content = m.onboardingModel.View()
+		} else {
+			content = "Preparing setup..."
+		}
+
 	case ScanScreen:
 		if m.scanModel != nil {
 			content = m.scanModel.View()
@@ -393,6 +530,13 @@ func (m *MainModel) View() string {
 			content = "Loading book list..."
 		}
 
+	case GroupingScreen:
+		if m.groupingModel != nil {
+			content = m.groupingModel.View()
+		} else {
+			content = "Grouping albums..."
+		}
+
 	case SettingsScreen:
 		if m.settingsModel != nil {
 			content = m.settingsModel.View()
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// Profile holds the defaults chosen in first-run setup
+type Profile struct {
+	Layout              string
+	UseEmbeddedMetadata bool
+	Flat                bool
+}
+
+// SetupOptions controls the first-run setup wizard
+type SetupOptions struct {
+	FirstRun    bool                // Start with the setup wizard before scanning
+	Profile     *Profile            // Saved defaults applied to the settings screen
+	SaveProfile func(Profile) error // Stores the wizard's result as the default profile
+	Messages    *i18n.Catalog       // Translations for the screen text; nil is English
+	MinDepth    int                 // Scan depth limits, as --min-depth and --max-depth
+	MaxDepth    int
+}
+
+// scanModes are the choices offered for how books are discovered
+var scanModes = []string{
+	"metadata.json folders",
+	"embedded tags",
+	"embedded tags, one file at a time (flat)",
+}
+
+// setupLayouts are the layouts offered by the wizard; custom templates are set later
+var setupLayouts = []string{
+	"author-only",
+	"author-title",
+	"author-series-title",
+	"author-series-title-number",
+	"series-title",
+	"series-title-number",
+}
+
+// surveyCompleteMsg carries the result of surveying the input directory
+type surveyCompleteMsg struct {
+	survey organizer.LibrarySurvey
+	err    error
+}
+
+// OnboardingModel is the first-run setup wizard. It surveys the input directory,
+// shows what the embedded metadata of a few files looks like, recommends a scan mode
+// and layout, and saves the result as the default profile.
+type OnboardingModel struct {
+	inputDir    string
+	survey      *organizer.LibrarySurvey
+	surveyErr   error
+	saveProfile func(Profile) error
+	saveErr     error
+
+	scanMode int
+	layout   int
+	cursor   int // 0: scan mode, 1: layout
+
+	done  bool
+	saved bool
+}
+
+// NewOnboardingModel creates the setup wizard for inputDir
+func NewOnboardingModel(inputDir string, saveProfile func(Profile) error) *OnboardingModel {
+	return &OnboardingModel{
+		inputDir:    inputDir,
+		saveProfile: saveProfile,
+		layout:      indexOf(setupLayouts, "author-series-title"),
+	}
+}
+
+// Init starts surveying the input directory
+func (m *OnboardingModel) Init() tea.Cmd {
+	inputDir := m.inputDir
+	return func() tea.Msg {
+		survey, err := organizer.SurveyLibrary(inputDir, organizer.DefaultSurveySamples, organizer.DefaultSurveyMaxFiles)
+		return surveyCompleteMsg{survey: survey, err: err}
+	}
+}
+
+// Update handles survey results and user input
+func (m *OnboardingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case surveyCompleteMsg:
+		if msg.err != nil {
+			m.surveyErr = msg.err
+			return m, nil
+		}
+		m.survey = &msg.survey
+		m.applyRecommendation(msg.survey.Recommended)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.cursor = 0
+		case "down", "j":
+			m.cursor = 1
+		case "left", "h":
+			m.cycle(-1)
+		case "right", "l", " ":
+			m.cycle(1)
+		case "enter", "s":
+			if m.survey == nil && m.surveyErr == nil {
+				return m, nil
+			}
+			m.save()
+		case "n", "esc":
+			// Continue without saving a profile; the wizard runs again next time
+			m.done = true
+		}
+	}
+	return m, nil
+}
+
+func (m *OnboardingModel) applyRecommendation(rec organizer.SetupRecommendation) {
+	switch {
+	case rec.Flat:
+		m.scanMode = 2
+	case rec.UseEmbeddedMetadata:
+		m.scanMode = 1
+	default:
+		m.scanMode = 0
+	}
+	if i := indexOf(setupLayouts, rec.Layout); i >= 0 {
+		m.layout = i
+	}
+}
+
+func (m *OnboardingModel) cycle(step int) {
+	if m.cursor == 0 {
+		m.scanMode = (m.scanMode + step + len(scanModes)) % len(scanModes)
+		return
+	}
+	m.layout = (m.layout + step + len(setupLayouts)) % len(setupLayouts)
+}
+
+func (m *OnboardingModel) save() {
+	if m.saveProfile != nil {
+		if err := m.saveProfile(m.Profile()); err != nil {
+			m.saveErr = err
+			return
+		}
+	}
+	m.saved = true
+	m.done = true
+}
+
+// Profile returns the settings currently chosen in the wizard
+func (m *OnboardingModel) Profile() Profile {
+	return Profile{
+		Layout:              setupLayouts[m.layout],
+		UseEmbeddedMetadata: m.scanMode > 0,
+		Flat:                m.scanMode == 2,
+	}
+}
+
+// Done reports whether the user finished the wizard, and Saved whether a profile was
+// stored
+func (m *OnboardingModel) Done() bool  { return m.done }
+func (m *OnboardingModel) Saved() bool { return m.saved }
+
+// View renders the wizard
+func (m *OnboardingModel) View() string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
+		Padding(0, 1).
+		Render("🧭 First-Run Setup")
+	content.WriteString(header + "\n\n")
+	content.WriteString(fmt.Sprintf("Input: %s\n\n", m.inputDir))
+
+	if m.surveyErr != nil {
+		content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#FF5555")).
+			Render(fmt.Sprintf("Couldn't look at the input directory: %v", m.surveyErr)) + "\n\n")
+	} else if m.survey == nil {
+		content.WriteString("Looking at your library...\n")
+		return content.String()
+	} else {
+		content.WriteString(m.renderFindings())
+	}
+
+	content.WriteString(m.renderChoices())
+
+	if m.saveErr != nil {
+		content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#FF5555")).
+			Render(fmt.Sprintf("\nCouldn't save the profile: %v", m.saveErr)) + "\n")
+	}
+
+	footer := lipgloss.NewStyle().
+		Foreground(themeColor("#888")).
+		Render("\n↑/↓: Choose setting • ←/→: Change • Enter: Save as default and continue • n: Not now • q: Quit")
+	content.WriteString(footer)
+	return content.String()
+}
+
+func (m *OnboardingModel) renderFindings() string {
+	var b strings.Builder
+	survey := m.survey
+	label := lipgloss.NewStyle().Bold(true)
+
+	b.WriteString(label.Render("What we found") + "\n")
+	files := fmt.Sprintf("%d", survey.Files)
+	if survey.Truncated {
+		files += "+"
+	}
+	b.WriteString(fmt.Sprintf("  %s files: %d\n", organizer.MetadataFileName, survey.MetadataFiles))
+	b.WriteString(fmt.Sprintf("  Audio/EPUB files: %s in %d folders (%d loose in the input folder)\n\n",
+		files, survey.BookDirs, survey.LooseFiles))
+
+	if len(survey.Samples) > 0 {
+		b.WriteString(label.Render("Embedded metadata in a few files") + "\n")
+		muted := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
+		for _, sample := range survey.Samples {
+			b.WriteString("  " + filepath.Base(sample.Path) + "\n")
+			if sample.Error != "" {
+				b.WriteString(muted.Render("    no readable tags") + "\n")
+				continue
+			}
+			md := sample.Metadata
+			b.WriteString(muted.Render(fmt.Sprintf("    Title: %s | Author: %s | Series: %s",
+				valueOrDash(md.Title), valueOrDash(strings.Join(md.Authors, ", ")), valueOrDash(md.GetValidSeries()))) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(label.Render("Recommendation") + "\n")
+	for _, reason := range survey.Recommended.Reasons {
+		b.WriteString("  • " + reason + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m *OnboardingModel) renderChoices() string {
+	var b strings.Builder
+	rows := []struct{ name, value string }{
+		{"Scan mode", scanModes[m.scanMode]},
+		{"Layout", setupLayouts[m.layout]},
+	}
+	for i, row := range rows {
+		line := fmt.Sprintf("  %-10s ◀ %s ▶", row.name, row.value)
+		if i == m.cursor {
+			line = selectedRowStyle().Render(">" + line[1:])
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func valueOrDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+func indexOf(options []string, value string) int {
+	for i, option := range options {
+		if option == value {
+			return i
+		}
+	}
+	return -1
+}
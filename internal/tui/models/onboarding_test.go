@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+func surveyedOnboarding(t *testing.T, save func(Profile) error) *OnboardingModel {
+	t.Helper()
+	m := NewOnboardingModel("/library", save)
+	m.Update(surveyCompleteMsg{survey: organizer.LibrarySurvey{
+		Files:      3,
+		BookDirs:   1,
+		LooseFiles: 3,
+		Samples: []organizer.SurveySample{
+			{Path: "/library/a.mp3", Metadata: organizer.Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}}},
+			{Path: "/library/b.mp3", Error: "no tags"},
+		},
+		Recommended: organizer.SetupRecommendation{
+			UseEmbeddedMetadata: true,
+			Flat:                true,
+			Layout:              "author-title",
+			Reasons:             []string{"Most files are loose"},
+		},
+	}})
+	return m
+}
+
+func TestOnboardingModelAppliesRecommendation(t *testing.T) {
+	m := surveyedOnboarding(t, nil)
+
+	want := Profile{Layout: "author-title", UseEmbeddedMetadata: true, Flat: true}
+	if got := m.Profile(); got != want {
+		t.Errorf("Profile() = %+v, want %+v", got, want)
+	}
+
+	view := m.View()
+	for _, text := range []string{"First-Run Setup", "Dune", "Frank Herbert", "no readable tags", "Most files are loose"} {
+		if !strings.Contains(view, text) {
+			t.Errorf("View() missing %q", text)
+		}
+	}
+}
+
+func TestOnboardingModelChangesChoices(t *testing.T) {
+	m := surveyedOnboarding(t, nil)
+
+	// Scan mode wraps from flat to metadata.json
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if got := m.Profile(); got.UseEmbeddedMetadata || got.Flat {
+		t.Errorf("after right on scan mode, Profile() = %+v, want metadata.json mode", got)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if got := m.Profile().Layout; got != "author-series-title" {
+		t.Errorf("after right on layout, Layout = %q, want author-series-title", got)
+	}
+}
+
+func TestOnboardingModelSave(t *testing.T) {
+	var saved []Profile
+	m := surveyedOnboarding(t, func(p Profile) error {
+		saved = append(saved, p)
+		return nil
+	})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.Done() || !m.Saved() {
+		t.Fatalf("after enter, Done() = %v, Saved() = %v, want both true", m.Done(), m.Saved())
+	}
+	if len(saved) != 1 || saved[0] != m.Profile() {
+		t.Errorf("saved profiles = %+v, want [%+v]", saved, m.Profile())
+	}
+}
+
+func TestOnboardingModelSaveError(t *testing.T) {
+	m := surveyedOnboarding(t, func(Profile) error { return errors.New("read-only") })
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Done() {
+		t.Error("wizard finished even though saving failed")
+	}
+	if !strings.Contains(m.View(), "read-only") {
+		t.Error("View() does not show the save error")
+	}
+}
+
+func TestOnboardingModelSkip(t *testing.T) {
+	m := surveyedOnboarding(t, func(Profile) error {
+		t.Error("profile saved when skipping")
+		return nil
+	})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if !m.Done() || m.Saved() {
+		t.Errorf("after n, Done() = %v, Saved() = %v, want true, false", m.Done(), m.Saved())
+	}
+}
+
+func TestMainModelFirstRun(t *testing.T) {
+	m := NewMainModelWithSetup("/library", "/out", SetupOptions{FirstRun: true})
+	m.Init()
+	if m.screen != OnboardingScreen {
+		t.Fatalf("screen = %v, want OnboardingScreen", m.screen)
+	}
+
+	m.Update(surveyCompleteMsg{survey: organizer.LibrarySurvey{
+		Recommended: organizer.SetupRecommendation{Layout: "author-only"},
+	}})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.screen != ScanScreen {
+		t.Errorf("screen after setup = %v, want ScanScreen", m.screen)
+	}
+	if m.setup.Profile == nil || m.setup.Profile.Layout != "author-only" {
+		t.Errorf("profile after setup = %+v, want author-only layout", m.setup.Profile)
+	}
+}
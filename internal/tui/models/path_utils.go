@@ -19,8 +19,7 @@ func GenerateOutputPath(
 	fieldMapping organizer.FieldMapping,
 	outputDir string,
 ) string {
-	updatedMetadata := book.Metadata
-	updatedMetadata.ApplyFieldMapping(fieldMapping)
+	updatedMetadata := book.MappedMetadata(fieldMapping)
 
 	if outputDir == "" {
 		outputDir = "output"
@@ -59,6 +58,11 @@ func GenerateOutputPath(
 		series = validSeries
 	}
 
+	// A separator inside a tag must not add a directory level on any platform
+	author = pathSeparatorReplacer.Replace(author)
+	title = pathSeparatorReplacer.Replace(title)
+	series = pathSeparatorReplacer.Replace(series)
+
 	seriesNumber := organizer.GetSeriesNumberFromMetadata(updatedMetadata)
 
 	switch layout {
@@ -100,12 +104,41 @@ func GenerateOutputPath(
 }
 
 func previewPathSanitizer(value string) string {
-	for _, char := range []string{"/", "<", ">", ":", "|", "?", "*", "`", "\""} {
+	for _, char := range []string{"/", "\\", "<", ">", ":", "|", "?", "*", "`", "\""} {
 		value = strings.ReplaceAll(value, char, "_")
 	}
 	return strings.Trim(value, " ._")
 }
 
+// pathSeparatorReplacer replaces both separators, since tags written on one platform
+// are previewed on another
+var pathSeparatorReplacer = strings.NewReplacer("/", "_", "\\", "_")
+
+// outputPathComponents splits a previewed target path into its components below
+// outputDir. Paths outside outputDir lose their first component instead.
+func outputPathComponents(path, outputDir string) []string {
+	if rel, err := filepath.Rel(outputDir, path); err == nil && rel != ".." &&
+		!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return splitPath(rel)
+	}
+	parts := splitPath(path)
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+	return parts
+}
+
+// splitPath splits a path on either separator
+func splitPath(path string) []string {
+	return strings.Split(filepath.ToSlash(path), "/")
+}
+
+// isRootDir reports whether dir has no parent: "/" on Unix, or a drive or share
+// root on Windows
+func isRootDir(dir string) bool {
+	return filepath.Dir(dir) == dir
+}
+
 func truncateLayoutTemplate(template string) string {
 	template = strings.TrimSpace(template)
 	if len(template) <= 42 {
@@ -22,6 +22,8 @@ type PreviewModel struct {
 	config       map[string]string
 	fieldMapping organizer.FieldMapping
 	moves        []MovePreview
+	merges       []organizer.AuthorMergeSuggestion // Titles found under similar author spellings
+	dryRunning   bool                              // A dry run through the organizer is in progress
 	cursor       int
 	width        int
 	height       int
@@ -56,20 +58,19 @@ func (m *PreviewModel) Init() tea.Cmd {
 // generatePreviews generates previews of file move operations
 func (m *PreviewModel) generatePreviews() {
 	m.moves = []MovePreview{}
+	authors := organizer.NewAuthorVariantDetector()
 
 	// In a real implementation, we would use the organizer package
 	// For now, we'll use our own implementation
 
 	// Generate previews for each book
 	for _, book := range m.books {
+		authors.Add(book.Path, book.Metadata)
+
 		// Calculate target path using universal function
 		layout := m.config["Layout"]
-		outputDir := m.config["Output Directory"]
-		if outputDir == "" {
-			outputDir = "output"
-		}
 		layoutTemplate := m.config["Layout Template"]
-		targetPath := GenerateOutputPath(book, layout, layoutTemplate, m.fieldMapping, outputDir)
+		targetPath := GenerateOutputPath(book, layout, layoutTemplate, m.fieldMapping, m.outputDir())
 
 		// Add to moves
 		m.moves = append(m.moves, MovePreview{
@@ -77,6 +78,7 @@ func (m *PreviewModel) generatePreviews() {
 			TargetPath: targetPath,
 		})
 	}
+	m.merges = authors.Suggestions()
 }
 
 // Update handles messages and user input
@@ -86,6 +88,10 @@ func (m *PreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case DryRunCompleteMsg:
+		m.dryRunning = false
+		return NewDryRunOutputModel(m.books, m.config, m.fieldMapping, msg.Output, msg.Err), nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "up", "k":
@@ -135,6 +141,13 @@ func (m *PreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "c":
 			// Show CLI command instead of processing
 			return NewCommandOutputModel(m.books, m.config, m.fieldMapping), nil
+
+		case "d":
+			// Dry-run the selection through the real organizer to check this preview
+			if !m.dryRunning {
+				m.dryRunning = true
+				return m, runDryRun(m.books, m.config, m.fieldMapping)
+			}
 		}
 	}
 
@@ -148,8 +161,8 @@ func (m *PreviewModel) View() string {
 	// Header
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1).
 		Render("👁️ File Organization Preview")
 
@@ -162,9 +175,20 @@ func (m *PreviewModel) View() string {
 		m.config["Flat Mode"])
 
 	content.WriteString(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
+		Foreground(themeColor("#FFFF00")).
 		Render(configSummary) + "\n\n")
 
+	// Likely mis-tagged authors would otherwise end up in near-duplicate folders
+	if len(m.merges) > 0 {
+		content.WriteString(m.renderAuthorMerges() + "\n")
+	}
+
+	if m.dryRunning {
+		content.WriteString(lipgloss.NewStyle().
+			Foreground(themeColor("#00FFFF")).
+			Render("🧪 Running a dry run through the organizer...") + "\n\n")
+	}
+
 	// Preview count
 	content.WriteString(fmt.Sprintf("Previewing %d file moves:\n\n", len(m.moves)))
 
@@ -197,15 +221,16 @@ func (m *PreviewModel) View() string {
 		// Style for source path based on cursor position
 		var sourceStyle lipgloss.Style
 		if i == m.cursor {
-			sourceStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF"))
+			sourceStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("#FFFFFF"))
 		} else {
-			sourceStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+			sourceStyle = lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
 		}
 
 		// Add the move preview
-		content.WriteString(fmt.Sprintf("%s From: %s/%s\n",
+		content.WriteString(fmt.Sprintf("%s From: %s%c%s\n",
 			cursor,
 			sourceStyle.Render(sourceDir),
+			filepath.Separator,
 			sourceStyle.Render(sourceName)))
 
 		// Colorize the output path
@@ -220,14 +245,37 @@ func (m *PreviewModel) View() string {
 
 	// Footer with help text
 	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
-		Render("\n↑/↓: Navigate • Enter: Process Files • c: Show CLI Command • b: Back • q: Quit")
+		Foreground(themeColor("#888")).
+		Render("\n↑/↓: Navigate • Enter: Process Files • d: Dry Run • c: Show CLI Command • b: Back • q: Quit")
 
 	content.WriteString(footer)
 
 	return content.String()
 }
 
+// renderAuthorMerges lists titles that appear under several similar author spellings
+// with the spelling each should probably be merged into
+func (m *PreviewModel) renderAuthorMerges() string {
+	warning := lipgloss.NewStyle().Foreground(themeColor("#FFA500"))
+
+	var b strings.Builder
+	b.WriteString(warning.Render(fmt.Sprintf("⚠️ Possible author misspellings: %d", len(m.merges))) + "\n")
+	for i, merge := range m.merges {
+		if i == 3 {
+			b.WriteString(fmt.Sprintf("  …and %d more\n", len(m.merges)-i))
+			break
+		}
+		for _, variant := range merge.Variants[1:] {
+			b.WriteString(fmt.Sprintf("  %s: %q (%d) → %q\n",
+				merge.Title, variant.Author, len(variant.Paths), merge.Suggested))
+		}
+		if merge.BestCopy != "" {
+			b.WriteString(fmt.Sprintf("    best quality: %s\n", filepath.Base(merge.BestCopy)))
+		}
+	}
+	return b.String()
+}
+
 // GetMoves returns the current move previews
 func (m *PreviewModel) GetMoves() []MovePreview {
 	return m.moves
@@ -236,19 +284,14 @@ func (m *PreviewModel) GetMoves() []MovePreview {
 // colorizeOutputPath colorizes the output path components based on the layout
 func (m *PreviewModel) colorizeOutputPath(path string, layout string) string {
 	// Define color styles
-	authorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9500"))    // Orange
-	seriesStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00D9FF"))    // Cyan
-	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))     // Green
-	separatorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")) // Gray
+	authorStyle := lipgloss.NewStyle().Foreground(themeColor("#FF9500"))    // Orange
+	seriesStyle := lipgloss.NewStyle().Foreground(themeColor("#00D9FF"))    // Cyan
+	titleStyle := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))     // Green
+	separatorStyle := lipgloss.NewStyle().Foreground(themeColor("#666666")) // Gray
 
-	// Split the path into components
-	parts := strings.Split(path, string(filepath.Separator))
-
-	// Skip the output directory (first component) and process the rest
+	// Split the path into its components below the output directory
+	parts := outputPathComponents(path, m.outputDir())
 	var coloredParts []string
-	if len(parts) > 1 {
-		parts = parts[1:] // Skip output directory
-	}
 
 	// Apply colors based on layout
 	switch layout {
@@ -298,5 +341,14 @@ func (m *PreviewModel) colorizeOutputPath(path string, layout string) string {
 	}
 
 	// Join with colorized separators
-	return strings.Join(coloredParts, separatorStyle.Render("/"))
+	return strings.Join(coloredParts, separatorStyle.Render(string(filepath.Separator)))
+}
+
+// outputDir returns the configured output directory, or "output" for previews
+// before one is chosen
+func (m *PreviewModel) outputDir() string {
+	if outputDir := m.config["Output Directory"]; outputDir != "" {
+		return outputDir
+	}
+	return "output"
 }
@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -426,3 +428,101 @@ func TestPreviewModelConfigHandling(t *testing.T) {
 	// Config values might appear in the view
 	// (exact behavior depends on implementation)
 }
+
+func TestPreviewModelShowsAuthorMerges(t *testing.T) {
+	books := []AudioBook{
+		{Path: "/in/a.mp3", Metadata: organizer.Metadata{Title: "Mistborn", Authors: []string{"Brandon Sanderson"}}},
+		{Path: "/in/b.mp3", Metadata: organizer.Metadata{Title: "Mistborn", Authors: []string{"Brandon Sandersen"}}},
+	}
+	model := NewPreviewModel(books, map[string]string{"Layout": "author-title"}, organizer.DefaultFieldMapping())
+	model.height = 40
+
+	view := model.View()
+	if !strings.Contains(view, "Possible author misspellings: 1") {
+		t.Fatalf("expected author merge warning in view:\n%s", view)
+	}
+	if !strings.Contains(view, `"Brandon Sandersen" (1) → "Brandon Sanderson"`) {
+		t.Errorf("expected merge suggestion in view:\n%s", view)
+	}
+}
+
+func TestPreviewModelDryRun(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	bookDir := filepath.Join(inputDir, "Dune")
+	if err := os.MkdirAll(bookDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bookDir, "metadata.json"),
+		[]byte(`{"title":"Dune","authors":["Frank Herbert"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	audio := filepath.Join(bookDir, "01.mp3")
+	if err := os.WriteFile(audio, []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	books := []AudioBook{{
+		Path:     audio,
+		Metadata: organizer.Metadata{Title: "Dune", Authors: []string{"Frank Herbert"}},
+		Selected: true,
+	}}
+	config := map[string]string{
+		"Input Directory":       inputDir,
+		"Output Directory":      outputDir,
+		"Layout":                "author-title",
+		"Use Embedded Metadata": "No",
+		"Flat Mode":             "No",
+	}
+	m := NewPreviewModel(books, config, organizer.DefaultFieldMapping())
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("pressing d did not start a dry run")
+	}
+	if !strings.Contains(model.View(), "Running a dry run") {
+		t.Error("preview does not show the dry run in progress")
+	}
+
+	msg, ok := cmd().(DryRunCompleteMsg)
+	if !ok {
+		t.Fatal("dry run did not return a DryRunCompleteMsg")
+	}
+	if msg.Err != nil {
+		t.Fatalf("dry run error = %v", msg.Err)
+	}
+	if !strings.Contains(msg.Output, "Frank Herbert") {
+		t.Errorf("dry run output does not mention the planned move:\n%s", msg.Output)
+	}
+	if _, err := os.Stat(audio); err != nil {
+		t.Errorf("dry run moved the source file: %v", err)
+	}
+
+	next, _ := model.Update(msg)
+	output, ok := next.(*CommandOutputModel)
+	if !ok {
+		t.Fatalf("after the dry run, model = %T, want *CommandOutputModel", next)
+	}
+	if !strings.Contains(output.View(), "Dry-Run Output") {
+		t.Error("command output screen does not show the dry run")
+	}
+}
+
+func TestOutputPathComponents(t *testing.T) {
+	outputDir := filepath.Join(string(filepath.Separator)+"media", "books")
+	target := filepath.Join(outputDir, "Author", "Series", "Title", "book.m4b")
+	got := outputPathComponents(target, outputDir)
+	want := []string{"Author", "Series", "Title", "book.m4b"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("outputPathComponents() = %q, want %q", got, want)
+	}
+
+	got = outputPathComponents(filepath.Join("output", "Author", "book.m4b"), "elsewhere")
+	if strings.Join(got, "|") != "Author|book.m4b" {
+		t.Errorf("outputPathComponents() outside outputDir = %q", got)
+	}
+
+	if !isRootDir(string(filepath.Separator)) || isRootDir(outputDir) {
+		t.Error("isRootDir should only accept the root")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jeeftor/audiobook-organizer/internal/i18n"
 	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 )
 
@@ -52,6 +53,7 @@ type ProcessModel struct {
 	elapsedTime  time.Duration
 	success      int
 	failed       int
+	messages     *i18n.Catalog // Translations for the screen text; nil is English
 }
 
 // NewProcessModel creates a new process model
@@ -91,43 +93,8 @@ func (m *ProcessModel) startProcessing() tea.Cmd {
 	m.startTime = time.Now()
 
 	return func() tea.Msg {
-		// Get directories from config
-		baseDir := m.config["Input Directory"]
-		outputDir := m.config["Output Directory"]
-
-		// Fallback: if not in config, try to get from the first book
-		if baseDir == "" && len(m.books) > 0 {
-			baseDir = filepath.Dir(m.books[0].Path)
-		}
-		if outputDir == "" {
-			outputDir = baseDir // Use same directory if not specified
-		}
-
-		// Get layout from settings
-		layout := m.config["Layout"]
-		if layout == "" {
-			layout = "author-series-title"
-		}
-		layoutTemplate := ""
-		if layout == "custom" {
-			layoutTemplate = strings.TrimSpace(m.config["Layout Template"])
-			layout = "author-series-title"
-		}
-
 		// Create configuration from settings
-		config := &organizer.OrganizerConfig{
-			BaseDir:             baseDir,
-			OutputDir:           outputDir,
-			Layout:              layout,
-			LayoutTemplate:      layoutTemplate,
-			UseEmbeddedMetadata: m.config["Use Embedded Metadata"] == "Yes",
-			Flat:                m.config["Flat Mode"] == "Yes",
-			DryRun:              m.config["Dry Run"] == "Yes",
-			Verbose:             false, // Always false in TUI mode - we have our own display
-			FieldMapping:        m.fieldMapping,
-			RemoveEmpty:         false, // Don't remove empty directories in TUI mode
-			Prompt:              false, // Don't prompt in TUI mode
-		}
+		config := newOrganizerConfig(m.books, m.config, m.fieldMapping)
 
 		// Process each item individually using OrganizeSingleFile
 		org, err := organizer.NewOrganizer(config)
@@ -151,9 +118,9 @@ func (m *ProcessModel) startProcessing() tea.Cmd {
 			// Get the source path for this file
 			sourcePath := m.items[i].SourcePath
 
-			// Process the file using the organizer
-			// Pass nil as the provider to let the organizer create and configure it
-			err := org.OrganizeSingleFile(sourcePath, nil)
+			// Process the file using the organizer. A nil provider lets the organizer
+			// read the file; files regrouped into an album get its identity.
+			err := org.OrganizeSingleFile(sourcePath, m.provider(sourcePath))
 
 			if err != nil {
 				// Processing failed
@@ -184,6 +151,17 @@ func (m *ProcessModel) startProcessing() tea.Cmd {
 	}
 }
 
+// provider returns the metadata provider for a source path, nil unless the file was
+// regrouped on the grouping screen
+func (m *ProcessModel) provider(sourcePath string) organizer.MetadataProvider {
+	for _, book := range m.books {
+		if book.Path == sourcePath {
+			return book.metadataProvider(m.fieldMapping)
+		}
+	}
+	return nil
+}
+
 // Update handles messages and user input
 func (m *ProcessModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -252,29 +230,29 @@ func (m *ProcessModel) View() string {
 	// Header
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1).
-		Render("⚙️ Processing Files")
+		Render("⚙️ " + m.messages.Sprintf("tui.process.title"))
 
 	content.WriteString(header + "\n\n")
 
 	if !m.processing && !m.complete {
 		// Initial state
-		content.WriteString(fmt.Sprintf("Ready to process %d files.\n\n", len(m.items)))
+		content.WriteString(m.messages.Sprintf("tui.process.ready", len(m.items)) + "\n\n")
 		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFF00")).
-			Render("Press Enter to begin processing..."))
+			Foreground(themeColor("#FFFF00")).
+			Render(m.messages.Sprintf("tui.process.start")))
 		return content.String()
 	}
 
 	// Status information
 	if m.processing {
-		content.WriteString(fmt.Sprintf("Processing %d files...\n", len(m.items)))
-		content.WriteString(fmt.Sprintf("Elapsed time: %s\n\n", m.elapsedTime.Round(time.Second)))
+		content.WriteString(m.messages.Sprintf("tui.process.running", len(m.items)) + "\n")
+		content.WriteString(m.messages.Sprintf("tui.process.elapsed", m.elapsedTime.Round(time.Second)) + "\n\n")
 	} else if m.complete {
-		content.WriteString(fmt.Sprintf("Processing complete in %s\n", m.elapsedTime.Round(time.Second)))
-		content.WriteString(fmt.Sprintf("Success: %d | Failed: %d\n\n", m.success, m.failed))
+		content.WriteString(m.messages.Sprintf("tui.process.complete", m.elapsedTime.Round(time.Second)) + "\n")
+		content.WriteString(m.messages.Sprintf("tui.process.counts", m.success, m.failed) + "\n\n")
 	}
 
 	// Calculate visible range based on height
@@ -305,17 +283,17 @@ func (m *ProcessModel) View() string {
 
 		switch item.Status {
 		case StatusPending:
-			statusStr = "⏳ Pending"
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+			statusStr = "⏳ " + m.messages.Sprintf("tui.process.pending")
+			statusStyle = lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
 		case StatusProcessing:
-			statusStr = "🔄 Processing"
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00"))
+			statusStr = "🔄 " + m.messages.Sprintf("tui.process.processing")
+			statusStyle = lipgloss.NewStyle().Foreground(themeColor("#FFFF00"))
 		case StatusSuccess:
-			statusStr = "✅ Success"
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
+			statusStr = "✅ " + m.messages.Sprintf("tui.process.success")
+			statusStyle = lipgloss.NewStyle().Foreground(themeColor("#00FF00"))
 		case StatusError:
-			statusStr = "❌ Error"
-			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+			statusStr = "❌ " + m.messages.Sprintf("tui.process.error")
+			statusStyle = lipgloss.NewStyle().Foreground(themeColor("#FF0000"))
 		}
 
 		// Format paths
@@ -342,7 +320,7 @@ func (m *ProcessModel) View() string {
 			content.WriteString(fmt.Sprintf(
 				"  %s\n",
 				lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#FF0000")).
+					Foreground(themeColor("#FF0000")).
 					Render(item.Error.Error()),
 			))
 		}
@@ -350,7 +328,7 @@ func (m *ProcessModel) View() string {
 		// Add field mapping information if available
 		if item.Status == StatusSuccess && item.Message != "" {
 			content.WriteString(fmt.Sprintf("  %s\n",
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#00AAFF")).Render(item.Message)))
+				lipgloss.NewStyle().Foreground(themeColor("#00AAFF")).Render(item.Message)))
 		}
 	}
 
@@ -363,11 +341,11 @@ func (m *ProcessModel) View() string {
 	var footer string
 	if m.complete {
 		footer = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888")).
+			Foreground(themeColor("#888")).
 			Render("\n↑/↓: Navigate • r: Return to main menu • q: Quit")
 	} else {
 		footer = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888")).
+			Foreground(themeColor("#888")).
 			Render("\n↑/↓: Navigate • q: Quit")
 	}
 
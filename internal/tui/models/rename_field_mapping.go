@@ -202,12 +202,12 @@ func NewRenameFieldMappingModel(
 	s := table.DefaultStyles()
 	s.Header = s.Header.
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(themeColor("240")).
 		BorderBottom(true).
 		Bold(true)
 	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
+		Foreground(themeColor("229")).
+		Background(themeColor("57")).
 		Bold(true)
 	t.SetStyles(s)
 
@@ -340,13 +340,13 @@ func (m *RenameFieldMappingModel) renderDetailedMetadataContent(
 	var content strings.Builder
 
 	// Color styles for different field types
-	titleLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
-	authorLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
-	seriesLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF"))
-	defaultLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAFF"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	titleLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))
+	authorLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#FFA500"))
+	seriesLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#00FFFF"))
+	defaultLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAFF"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FFFF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FFFF"))
 	// Show the actual sample number (1-based index in the full list)
 	sampleNum := bookIndex + 1
 	content.WriteString(
@@ -372,12 +372,12 @@ func (m *RenameFieldMappingModel) renderDetailedMetadataContent(
 	}
 
 	// Raw metadata fields with inline indicators (sorted alphabetically)
-	rawLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+	rawLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
 	jsonFieldStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFD700"))
+		Foreground(themeColor("#FFD700"))
 		// Gold for JSON fields
 	embeddedFieldStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00CED1"))
+		Foreground(themeColor("#00CED1"))
 		// Turquoise for embedded
 
 	// Show header with hybrid mode indicator if applicable
@@ -523,7 +523,7 @@ func (m *RenameFieldMappingModel) renderDetailedMetadata(sampleIndices []int) st
 	// Render in a box
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#444444")).
+		BorderForeground(themeColor("#444444")).
 		Width(m.width - 4)
 
 	return box.Render(content)
@@ -1203,9 +1203,9 @@ func (m *RenameFieldMappingModel) updatePreviewMapping() {
 
 // renderFieldMappingSummary renders a compact summary of current field mappings
 func (m *RenameFieldMappingModel) renderFieldMappingSummary() string {
-	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00AAFF"))
-	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00AAFF"))
+	keyStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFF00"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
 
 	var sb strings.Builder
 	sb.WriteString(labelStyle.Render("⚙️  Field Mappings:") + " ")
@@ -1270,12 +1270,12 @@ func (m *RenameFieldMappingModel) View() string {
 	// Title and metadata mode - PROMINENT
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#333333"))
+		Foreground(themeColor("#00FFFF")).
+		Background(themeColor("#333333"))
 	modeStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFF00")).
-		Background(lipgloss.Color("#333333"))
+		Foreground(themeColor("#FFFF00")).
+		Background(themeColor("#333333"))
 
 	var modeText, modeIcon string
 	switch m.metadataMode {
@@ -1305,7 +1305,7 @@ func (m *RenameFieldMappingModel) View() string {
 	}
 
 	// Build status indicator
-	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	statusStyle := lipgloss.NewStyle().Foreground(themeColor("#888888"))
 	var statusText string
 	if m.metadataMode == 0 {
 		// JSON priority mode - show what was found
@@ -1322,7 +1322,7 @@ func (m *RenameFieldMappingModel) View() string {
 
 	// Prominent mode indicator with debug resolution
 	debugInfo := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
+		Foreground(themeColor("#666666")).
 		Render(fmt.Sprintf(" [%dx%d]", m.width, m.height))
 	modeHeader := titleStyle.Render(
 		" Metadata Mode: ",
@@ -1395,7 +1395,7 @@ func (m *RenameFieldMappingModel) View() string {
 			boxWidth := (m.width - 10) / 3
 			box := lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#444444")).
+				BorderForeground(themeColor("#444444")).
 				Width(boxWidth)
 
 			sb.WriteString(lipgloss.JoinHorizontal(
@@ -1413,7 +1413,7 @@ func (m *RenameFieldMappingModel) View() string {
 			boxWidth := (m.width - 6) / 2
 			box := lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#444444")).
+				BorderForeground(themeColor("#444444")).
 				Width(boxWidth)
 
 			sb.WriteString(lipgloss.JoinHorizontal(
@@ -1438,13 +1438,13 @@ func (m *RenameFieldMappingModel) View() string {
 	sb.WriteString(
 		lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#00AAFF")).
+			Foreground(themeColor("#00AAFF")).
 			Render("📋 Sample Files"+focusIndicator) +
 			"\n",
 	)
 
-	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00"))
+	labelStyle := lipgloss.NewStyle().Foreground(themeColor("#888888"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFF00"))
 
 	// Determine which samples are currently visible in metadata preview
 	visibleStart := m.metadataBookIndex
@@ -1458,7 +1458,7 @@ func (m *RenameFieldMappingModel) View() string {
 		visibleEnd = len(sampleIndices) - 1
 	}
 
-	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Bold(true)
+	highlightStyle := lipgloss.NewStyle().Foreground(themeColor("#00FFFF")).Bold(true)
 
 	for i, idx := range sampleIndices {
 		if idx >= len(m.candidates) {
@@ -1493,22 +1493,22 @@ func (m *RenameFieldMappingModel) View() string {
 	sb.WriteString(
 		lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#00AAFF")).
+			Foreground(themeColor("#00AAFF")).
 			Render("👁️  Rename Preview"+focusIndicator) +
 			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
+				Foreground(themeColor("#888888")).
 				Render(" ["+templateDisplay+"]") +
 			"\n",
 	)
 
 	// Color styles matching field types
-	authorColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")) // Orange
-	seriesColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")) // Cyan
-	titleColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))  // Green
-	trackColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAFF"))  // Blue
+	authorColor := lipgloss.NewStyle().Foreground(themeColor("#FFA500")) // Orange
+	seriesColor := lipgloss.NewStyle().Foreground(themeColor("#00FFFF")) // Cyan
+	titleColor := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))  // Green
+	trackColor := lipgloss.NewStyle().Foreground(themeColor("#AAAAFF"))  // Blue
 
-	highlightNumberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Bold(true)
-	normalNumberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	highlightNumberStyle := lipgloss.NewStyle().Foreground(themeColor("#00FFFF")).Bold(true)
+	normalNumberStyle := lipgloss.NewStyle().Foreground(themeColor("#888888"))
 
 	for i, idx := range sampleIndices {
 		if idx >= len(m.candidates) {
@@ -1541,7 +1541,7 @@ func (m *RenameFieldMappingModel) View() string {
 
 	// Controls
 	sb.WriteString(
-		"\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).
+		"\n" + lipgloss.NewStyle().Foreground(themeColor("#888888")).
 			Render("t: Title | s: Series | a: Author | o: Track | p: Template | m: Mode | ←→: Samples | c: Continue | Q: Back"),
 	)
 
@@ -1554,24 +1554,24 @@ func (m *RenameFieldMappingModel) renderTemplatePopup() string {
 
 	popupStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(themeColor("62")).
 		Padding(1, 2).
 		Width(70)
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#333333"))
+		Foreground(themeColor("#00FFFF")).
+		Background(themeColor("#333333"))
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
+		Foreground(themeColor("#FFFF00")).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#AAAAAA"))
+		Foreground(themeColor("#AAAAAA"))
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00AAFF"))
+		Foreground(themeColor("#00AAFF"))
 
 	// Title
 	sb.WriteString(titleStyle.Render(" Build Output Template ") + "\n\n")
@@ -1629,7 +1629,7 @@ func (m *RenameFieldMappingModel) renderTemplatePopup() string {
 	}
 	sb.WriteString(labelStyle.Render("Preview: ") + selectedStyle.Render(preview) + "\n")
 
-	sb.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).
+	sb.WriteString("\n" + lipgloss.NewStyle().Foreground(themeColor("#888888")).
 		Render("↑↓: Navigate • 1-4: Assign Position • s: Separator • Enter: Apply • Esc: Cancel"))
 
 	return "\n\n" + popupStyle.Render(sb.String())
@@ -1750,14 +1750,14 @@ func (m *RenameFieldMappingModel) renderPopupWithColumns(useMultiColumn bool) st
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00FFFF"))
+		Foreground(themeColor("#00FFFF"))
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
+		Foreground(themeColor("#FFFF00")).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#AAAAAA"))
+		Foreground(themeColor("#AAAAAA"))
 
 	sb.WriteString(
 		titleStyle.Render(fmt.Sprintf("Select %s:", m.settings[m.popupSettingIdx].Name)) + "\n\n",
@@ -1812,12 +1812,12 @@ func (m *RenameFieldMappingModel) renderPopupWithColumns(useMultiColumn bool) st
 		}
 	}
 
-	sb.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).
+	sb.WriteString("\n" + lipgloss.NewStyle().Foreground(themeColor("#888888")).
 		Render("↑↓: Navigate • ←→: Samples • Enter: Select • Esc: Cancel"))
 
 	popupStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(themeColor("62")).
 		Padding(1, 2)
 
 	return popupStyle.Render(sb.String())
@@ -1827,7 +1827,7 @@ func (m *RenameFieldMappingModel) renderPopupWithColumns(useMultiColumn bool) st
 func (m *RenameFieldMappingModel) render3ColumnSelectionView() string {
 	var sb strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00AAFF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00AAFF"))
 	sb.WriteString(
 		titleStyle.Render(fmt.Sprintf("Select %s:", m.settings[m.popupSettingIdx].Name)) + "\n\n",
 	)
@@ -1858,7 +1858,7 @@ func (m *RenameFieldMappingModel) render3ColumnSelectionView() string {
 		boxStyle := lipgloss.NewStyle().
 			Width(boxWidth).
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#666666")).
+			BorderForeground(themeColor("#666666")).
 			Padding(0, 1)
 		metadataColumns = append(metadataColumns, boxStyle.Render(metaContent))
 	}
@@ -1866,8 +1866,8 @@ func (m *RenameFieldMappingModel) render3ColumnSelectionView() string {
 	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, metadataColumns...) + "\n\n")
 
 	// Render selection lists under each column - each showing values from that file's metadata
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true)
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+	selectedStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFF00")).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
 
 	var selectionColumns []string
 	for col := 0; col < 3; col++ {
@@ -1944,14 +1944,14 @@ func (m *RenameFieldMappingModel) render3ColumnSelectionView() string {
 		boxStyle := lipgloss.NewStyle().
 			Width(boxWidth).
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
+			BorderForeground(themeColor("62")).
 			Padding(0, 1)
 		selectionColumns = append(selectionColumns, boxStyle.Render(colContent.String()))
 	}
 
 	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, selectionColumns...) + "\n\n")
 
-	sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).
+	sb.WriteString(lipgloss.NewStyle().Foreground(themeColor("#888888")).
 		Render("↑↓: Navigate • ←→: Samples • Enter: Select • Esc: Cancel"))
 
 	return sb.String()
@@ -23,12 +23,12 @@ func (m *RenameFieldMappingModel) renderMetadataForFile(
 	var content strings.Builder
 
 	// Color styles
-	titleLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
-	authorLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
-	seriesLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF"))
-	defaultLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAFF"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FFFF"))
+	titleLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))
+	authorLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#FFA500"))
+	seriesLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#00FFFF"))
+	defaultLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAFF"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FFFF"))
 
 	content.WriteString(
 		titleStyle.Render(fmt.Sprintf("Metadata Preview (#%d):", displayNum)) + "\n\n",
@@ -49,7 +49,7 @@ func (m *RenameFieldMappingModel) renderMetadataForFile(
 	)
 
 	// Raw metadata fields (sorted, limited to fit in column)
-	rawLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+	rawLabelStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))
 	content.WriteString(titleStyle.Render("Raw Metadata Fields:") + "\n")
 
 	fieldMapping := m.config.FieldMapping
@@ -85,6 +85,14 @@ func (m *RenameMainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 
+		case themeToggleKey:
+			SetTheme(nextTheme())
+			return m, nil
+
+		case glyphsToggleKey:
+			SetPlainGlyphs(!PlainGlyphs())
+			return m, nil
+
 		case "q":
 			// Handle quit based on screen
 			if m.screen == RenameScanScreen {
@@ -197,6 +205,10 @@ func (m *RenameMainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the current screen
 func (m *RenameMainModel) View() string {
+	return applyGlyphs(m.view())
+}
+
+func (m *RenameMainModel) view() string {
 	if m.quitting {
 		if m.err != nil {
 			return "Error: " + m.err.Error() + "\n"
@@ -218,10 +230,10 @@ func (m *RenameMainModel) View() string {
 		return "Loading field mapping..."
 
 	case RenameCommandScreen:
-		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FFFF"))
-		commandStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
-		labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true)
-		helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FFFF"))
+		commandStyle := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))
+		labelStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFF00")).Bold(true)
+		helpStyle := lipgloss.NewStyle().Foreground(themeColor("#888888"))
 
 		var sb strings.Builder
 		sb.WriteString(titleStyle.Render("📋 Generated Commands") + "\n\n")
@@ -99,10 +99,10 @@ func (m *RenamePreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *RenamePreviewModel) View() string {
 	var sb strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("63"))
+	helpStyle := lipgloss.NewStyle().Foreground(themeColor("241"))
+	successStyle := lipgloss.NewStyle().Foreground(themeColor("42"))
+	warningStyle := lipgloss.NewStyle().Foreground(themeColor("214"))
 
 	sb.WriteString(titleStyle.Render("👀 Preview Changes") + "\n\n")
 
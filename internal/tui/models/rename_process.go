@@ -78,10 +78,10 @@ func (m *RenameProcessModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *RenameProcessModel) View() string {
 	var sb strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
-	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("63"))
+	successStyle := lipgloss.NewStyle().Foreground(themeColor("42"))
+	errorStyle := lipgloss.NewStyle().Foreground(themeColor("196"))
+	helpStyle := lipgloss.NewStyle().Foreground(themeColor("241"))
 
 	if m.processing {
 		sb.WriteString(titleStyle.Render("⚙️  Processing Renames...") + "\n\n")
@@ -116,12 +116,12 @@ func (m *RenameProcessModel) View() string {
 		}
 
 		// Show CLI command
-		sb.WriteString("\n" + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FFFF")).Render("CLI Command:") + "\n")
-		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("To rename these files from the command line, run:") + "\n\n")
+		sb.WriteString("\n" + lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FFFF")).Render("CLI Command:") + "\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(themeColor("#888888")).Render("To rename these files from the command line, run:") + "\n\n")
 
 		commandStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFF00")).
-			Background(lipgloss.Color("#333333")).
+			Foreground(themeColor("#FFFF00")).
+			Background(themeColor("#333333")).
 			Padding(0, 1)
 
 		sb.WriteString(commandStyle.Render(m.generateCommand()) + "\n")
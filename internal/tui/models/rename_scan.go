@@ -78,7 +78,7 @@ func (m *RenameScanModel) View() string {
 	style := lipgloss.NewStyle().
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63"))
+		BorderForeground(themeColor("63"))
 
 	var content string
 
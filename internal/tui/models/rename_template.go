@@ -144,8 +144,8 @@ func (m *RenameTemplateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *RenameTemplateModel) View() string {
 	var sb strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("63"))
+	helpStyle := lipgloss.NewStyle().Foreground(themeColor("241"))
 
 	sb.WriteString(titleStyle.Render("📝 Template Builder") + "\n\n")
 
@@ -225,6 +225,7 @@ func (m *RenameTemplateModel) updatePreview() {
 	// Create renderer
 	formatter := organizer.NewAuthorFormatter(m.authorFormat)
 	renderer := organizer.NewTemplateRenderer(template, formatter)
+	renderer.SetInitial(organizer.SortInitial)
 
 	// Generate preview for first 10 candidates
 	for i, candidate := range m.candidates {
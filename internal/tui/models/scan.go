@@ -2,8 +2,7 @@ package models
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,10 +26,11 @@ type AudioBook struct {
 	Path          string
 	Metadata      organizer.Metadata
 	Selected      bool
-	IsPartOfAlbum bool   // Indicates if this file is part of a multi-file album
-	AlbumName     string // Name of the album this file belongs to
-	TrackNumber   int    // Track number within the album
-	TotalTracks   int    // Total number of tracks in the album
+	IsPartOfAlbum bool       // Indicates if this file is part of a multi-file album
+	AlbumName     string     // Name of the album this file belongs to
+	TrackNumber   int        // Track number within the album
+	TotalTracks   int        // Total number of tracks in the album
+	Group         *BookGroup // Album identity set on the grouping screen; nil keeps the file's own
 }
 
 // ScanModel represents the scanning screen
@@ -41,6 +41,10 @@ type ScanModel struct {
 	books        []AudioBook
 	scannedDirs  int
 	scannedFiles int
+	skipListed   int // Paths left out because of the input directory's skip list
+	deferred     int // Directories left out because a download is still in progress
+	minDepth     int // Scan depth limits passed to the scanner (see ScanOptions)
+	maxDepth     int
 	startTime    time.Time
 	elapsedTime  time.Duration
 }
@@ -84,190 +88,76 @@ func (m *ScanModel) startScan() tea.Cmd {
 	)
 }
 
-// scanDirectory scans a directory for audiobooks
+// scanDirectory scans a directory for audiobooks using the shared organizer scanner.
+// Every supported file is listed; files in a directory with consistent metadata are
+// marked as tracks of one album.
 func (m *ScanModel) scanDirectory(dir string) []AudioBook {
 	var books []AudioBook
 
-	// Use the organizer package to scan for audiobooks and extract metadata
-	m.scannedDirs++
-
-	// Define supported file extensions
-	extensions := []string{".m4b", ".mp3", ".m4a", ".epub"}
-
-	// First pass: collect all audio files and their metadata
-	type fileInfo struct {
-		path     string
-		metadata organizer.Metadata
-		dir      string
-	}
-
-	fileInfos := []fileInfo{}
-
-	// Map to track directories with multiple audio files (potential albums)
-	dirFileCount := make(map[string]int)
-
-	// Walk through the directory to collect all files
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			m.scannedDirs++
-			return nil
-		}
-
-		m.scannedFiles++
-		ext := strings.ToLower(filepath.Ext(path))
-
-		for _, validExt := range extensions {
-			if ext == validExt {
-
-				// ALWAYS use NewMetadataProvider which auto-detects and does hybrid extraction
-				// It will automatically:
-				// - Detect file type (audio, epub, json)
-				// - For audio files, check for metadata.json in parent dir
-				// - Do hybrid extraction (JSON + embedded) if metadata.json exists
-				// - Use only embedded metadata if no metadata.json
-				// Pass false for useEmbeddedOnly to allow hybrid mode (JSON + embedded)
-				provider := organizer.NewMetadataProvider(path, false)
-				metadata, err := provider.GetMetadata()
-				if err != nil {
-					// If metadata extraction fails, create basic metadata from filename
-					baseName := filepath.Base(path)
-					metadata = organizer.Metadata{
-						Title:   baseName,
-						Authors: []string{"Unknown Author"},
-					}
-				}
-
-				// Get the directory path for this file
-				fileDir := filepath.Dir(path)
-
-				// Store file info for later processing
-				fileInfos = append(fileInfos, fileInfo{
-					path:     path,
-					metadata: metadata,
-					dir:      fileDir,
-				})
-
-				// Count files per directory for album detection
-				dirFileCount[fileDir]++
-				break
-			}
-		}
-
-		return nil
+	// Hybrid extraction (metadata.json plus embedded tags), keeping unreadable files
+	// so they can still be selected by filename
+	scanner := organizer.NewScanner(organizer.ScanOptions{
+		Flat:               true,
+		FallbackToFilename: true,
+		SkipUnreadable:     true,
+		MinDepth:           m.minDepth,
+		MaxDepth:           m.maxDepth,
+		Progress: func(progress organizer.ScanProgress) {
+			m.scannedDirs = progress.DirsScanned
+			m.scannedFiles = progress.FilesScanned
+			m.skipListed = progress.SkipListed
+			m.deferred = progress.Deferred
+		},
 	})
-
-	// Second pass: group files into albums or process as individual files
-	// Map to track which directories have been processed as albums
-	processedDirs := make(map[string]bool)
-
-	// Group files by directory for album detection
-	dirFiles := make(map[string][]fileInfo)
-	for _, fi := range fileInfos {
-		dirFiles[fi.dir] = append(dirFiles[fi.dir], fi)
-	}
-
-	// Process each directory
-	for dir, files := range dirFiles {
-		// Skip if already processed
-		if processedDirs[dir] {
-			continue
-		}
-
-		// Check if this directory should be processed as an album
-		isAlbum := len(files) > 1
-
-		// If it's an album, check for consistent metadata
-		if isAlbum {
-			// Check for consistent title and author across files
-			var albumTitle, albumArtist string
-			consistentMetadata := true
-
-			// Get first file's metadata as reference
-			if len(files) > 0 {
-				albumTitle = files[0].metadata.Title
-				if len(files[0].metadata.Authors) > 0 {
-					albumArtist = files[0].metadata.Authors[0]
+	// Groups arrive as each directory finishes, so only the list itself is kept
+	err := scanner.Walk(dir, organizer.ScanHandler{
+		Group: func(group organizer.Group) error {
+			for i, book := range group.Books {
+				audioBook := AudioBook{
+					Path:     book.Path,
+					Metadata: book.Metadata,
+					Selected: true,
 				}
-
-				// Check if other files have matching metadata
-				for i := 1; i < len(files); i++ {
-					currentTitle := files[i].metadata.Title
-					var currentArtist string
-					if len(files[i].metadata.Authors) > 0 {
-						currentArtist = files[i].metadata.Authors[0]
-					}
-
-					// Check if title and artist match
-					if currentTitle != albumTitle ||
-						(albumArtist != "" && currentArtist != "" && currentArtist != albumArtist) {
-						// Check for track number patterns in title
-						if !organizer.HasTrackNumberPattern(currentTitle, albumTitle) &&
-							!organizer.HasCommonPrefix(currentTitle, albumTitle) {
-							consistentMetadata = false
-							break
-						}
-					}
+				if group.Album {
+					audioBook.IsPartOfAlbum = true
+					audioBook.AlbumName = group.Name
+					audioBook.TrackNumber = group.TrackNumber(i)
+					audioBook.TotalTracks = len(group.Books)
 				}
+				books = append(books, audioBook)
 			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil
+	}
 
-			// Process as album if metadata is consistent
-			if consistentMetadata {
-				// Create album name from common metadata
-				albumName := albumTitle
-				if albumArtist != "" {
-					albumName = albumArtist + " - " + albumName
-				}
-
-				// Sort files by track number if available
-				organizer.SortFilesByTrackNumber(files)
-
-				// Create AudioBook entries for each file in the album
-				totalTracks := len(files)
-				for i, file := range files {
-					trackNumber := i + 1 // Default to position in sorted list
-
-					// Use actual track number if available
-					if file.metadata.TrackNumber > 0 {
-						trackNumber = file.metadata.TrackNumber
-					}
-
-					book := AudioBook{
-						Path:          file.path,
-						Metadata:      file.metadata,
-						Selected:      true,
-						IsPartOfAlbum: true,
-						AlbumName:     albumName,
-						TrackNumber:   trackNumber,
-						TotalTracks:   totalTracks,
-					}
-					books = append(books, book)
-				}
+	sortBooks(books)
+	return books
+}
 
-				// Mark this directory as processed
-				processedDirs[dir] = true
-				continue
-			}
+// sortBooks orders books by author and then title in the configured locale. Album
+// tracks share their album's place and keep their track order.
+func sortBooks(books []AudioBook) {
+	key := func(book AudioBook) (string, string) {
+		author := ""
+		if len(book.Metadata.Authors) > 0 {
+			author = book.Metadata.Authors[0]
 		}
-
-		// Process files individually if not an album or inconsistent metadata
-		for _, file := range files {
-			if !processedDirs[dir] {
-				book := AudioBook{
-					Path:          file.path,
-					Metadata:      file.metadata,
-					Selected:      true,
-					IsPartOfAlbum: false,
-				}
-				books = append(books, book)
-			}
+		if book.IsPartOfAlbum {
+			return author, book.AlbumName
 		}
+		return author, book.Metadata.Title
 	}
-
-	return books
+	sort.SliceStable(books, func(i, j int) bool {
+		authorI, titleI := key(books[i])
+		authorJ, titleJ := key(books[j])
+		if byAuthor := organizer.CompareNames(authorI, authorJ); byAuthor != 0 {
+			return byAuthor < 0
+		}
+		return organizer.CompareNames(titleI, titleJ) < 0
+	})
 }
 
 // Update handles messages and user input
@@ -305,6 +195,8 @@ func (m *ScanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.books = []AudioBook{}
 			m.scannedDirs = 0
 			m.scannedFiles = 0
+			m.skipListed = 0
+			m.deferred = 0
 			return m, m.startScan()
 		}
 	}
@@ -319,8 +211,8 @@ func (m *ScanModel) View() string {
 	// Title
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1).
 		Render("📚 Audiobook Scanner")
 
@@ -330,7 +222,7 @@ func (m *ScanModel) View() string {
 		// Initial state
 		content.WriteString("Press Enter to start scanning directory:\n")
 		content.WriteString(
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Render(m.inputDir),
+			lipgloss.NewStyle().Foreground(themeColor("#FFFF00")).Render(m.inputDir),
 		)
 		content.WriteString("\n\n")
 		content.WriteString(
@@ -352,16 +244,23 @@ func (m *ScanModel) View() string {
 	} else if m.complete {
 		// Complete state
 		content.WriteString("✅ Scan complete!\n\n")
-		content.WriteString(fmt.Sprintf("Found %d audiobooks in %s\n\n", len(m.books), m.elapsedTime.Round(time.Second)))
+		content.WriteString(fmt.Sprintf("Found %d audiobooks in %s\n", len(m.books), m.elapsedTime.Round(time.Second)))
+		if m.skipListed > 0 {
+			content.WriteString(fmt.Sprintf("⏭️  %d skipped by %s\n", m.skipListed, organizer.SkipListFileName))
+		}
+		if m.deferred > 0 {
+			content.WriteString(fmt.Sprintf("⏳ %d deferred while still downloading\n", m.deferred))
+		}
+		content.WriteString("\n")
 
 		if len(m.books) > 0 {
-			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Render("Press any key to continue to book selection..."))
+			content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#00FF00")).Render("Press any key to continue to book selection..."))
 		} else {
-			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Bold(true).Render("No audiobooks found.") + "\n\n")
+			content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#FF0000")).Bold(true).Render("No audiobooks found.") + "\n\n")
 			content.WriteString("This could be because:\n")
-			content.WriteString("1. The directory doesn't contain supported audiobook files (.m4b, .mp3, .m4a, .epub)\n")
+			content.WriteString("1. The directory doesn't contain supported audiobook files (.m4b, .mp3, .m4a, .flac, .ogg, .opus, .aac, .wma, .wav, .epub)\n")
 			content.WriteString("2. The files don't have readable metadata\n\n")
-			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Render("Press 'r' to scan again or 'q' to quit"))
+			content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#FFFF00")).Render("Press 'r' to scan again or 'q' to quit"))
 		}
 	}
 
@@ -423,3 +423,22 @@ func TestScanDirectoryWithMetadataJson(t *testing.T) {
 		t.Errorf("Expected path %s, got %s", audioPath, book.Path)
 	}
 }
+
+func TestSortBooks(t *testing.T) {
+	books := []AudioBook{
+		{Path: "z", Metadata: organizer.Metadata{Authors: []string{"Zelazny"}, Title: "Lord of Light"}},
+		{Path: "a2", Metadata: organizer.Metadata{Authors: []string{"Ångström"}, Title: "Track B"}, IsPartOfAlbum: true, AlbumName: "Light"},
+		{Path: "a1", Metadata: organizer.Metadata{Authors: []string{"Ångström"}, Title: "Track A"}, IsPartOfAlbum: true, AlbumName: "Light"},
+		{Path: "b", Metadata: organizer.Metadata{Authors: []string{"brust"}, Title: "Jhereg"}},
+	}
+
+	sortBooks(books)
+
+	var got []string
+	for _, book := range books {
+		got = append(got, book.Path)
+	}
+	if want := "a2 a1 b z"; strings.Join(got, " ") != want {
+		t.Errorf("sortBooks() order = %v, want %s", got, want)
+	}
+}
@@ -168,8 +168,8 @@ func formatFullMetadata(metadata *organizer.Metadata) string {
 	var content strings.Builder
 
 	// Define styles for field names and values
-	fieldStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#AAAAFF"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	fieldStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#AAAAFF"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
 
 	// Format primary metadata fields
 	if metadata.Title != "" {
@@ -210,6 +210,12 @@ func formatFullMetadata(metadata *organizer.Metadata) string {
 			valueStyle.Render(metadata.TrackTitle)))
 	}
 
+	if metadata.Audio != nil {
+		content.WriteString(fmt.Sprintf("%s: %s\n",
+			fieldStyle.Render("Audio"),
+			valueStyle.Render(metadata.Audio.String())))
+	}
+
 	// Display raw metadata fields
 	if len(metadata.RawData) > 0 {
 		content.WriteString("\n" + fieldStyle.Render("Additional Metadata Fields:") + "\n")
@@ -244,8 +250,8 @@ func formatFieldMapping(mapping organizer.FieldMapping) string {
 	var content strings.Builder
 
 	// Define styles for field names and values
-	fieldStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFAAAA"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	fieldStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#FFAAAA"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
 
 	// Format field mappings
 	content.WriteString(fmt.Sprintf("%s: %s\n",
@@ -407,11 +413,11 @@ func (m *SettingsModel) View() string {
 	// Header
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1)
 
-	filterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF")).Bold(true)
+	filterStyle := lipgloss.NewStyle().Foreground(themeColor("#00FFFF")).Bold(true)
 
 	// Different header based on mode
 	if m.showAdvanced {
@@ -442,11 +448,11 @@ func (m *SettingsModel) View() string {
 			var nameStyle, valueStyle lipgloss.Style
 
 			if i == m.fieldCursor {
-				nameStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFF00"))
-				valueStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FF00"))
+				nameStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("#FFFF00"))
+				valueStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FF00"))
 			} else {
-				nameStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
-				valueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#AAFFAA"))
+				nameStyle = lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
+				valueStyle = lipgloss.NewStyle().Foreground(themeColor("#AAFFAA"))
 			}
 
 			// Field name and description
@@ -473,11 +479,11 @@ func (m *SettingsModel) View() string {
 			var nameStyle, valueStyle lipgloss.Style
 
 			if i == m.cursor {
-				nameStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFF00"))
-				valueStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FF00"))
+				nameStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("#FFFF00"))
+				valueStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FF00"))
 			} else {
-				nameStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
-				valueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#AAFFAA"))
+				nameStyle = lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
+				valueStyle = lipgloss.NewStyle().Foreground(themeColor("#AAFFAA"))
 			}
 
 			// Cursor indicator
@@ -513,7 +519,7 @@ func (m *SettingsModel) View() string {
 			content.WriteString(
 				"\n" + lipgloss.NewStyle().
 					Bold(true).
-					Foreground(lipgloss.Color("#00FFFF")).
+					Foreground(themeColor("#00FFFF")).
 					Render("Preview of Output Paths:") +
 					"\n\n",
 			)
@@ -561,13 +567,13 @@ func (m *SettingsModel) View() string {
 				// Display book info and output path
 				content.WriteString(
 					lipgloss.NewStyle().
-						Foreground(lipgloss.Color("#AAFFAA")).
+						Foreground(themeColor("#AAFFAA")).
 						Render(filename) +
 						"\n",
 				)
 				content.WriteString(
 					"  → " + lipgloss.NewStyle().
-						Foreground(lipgloss.Color("#FFAAAA")).
+						Foreground(themeColor("#FFAAAA")).
 						Render(outputPath) +
 						"\n",
 				)
@@ -584,15 +590,15 @@ func (m *SettingsModel) View() string {
 				}
 
 				content.WriteString(fmt.Sprintf("  Author: %s | Series: %s\n\n",
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAFF")).Render(authors),
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAAFF")).Render(series)))
+					lipgloss.NewStyle().Foreground(themeColor("#AAAAFF")).Render(authors),
+					lipgloss.NewStyle().Foreground(themeColor("#FFAAFF")).Render(series)))
 			}
 
 			// Then show the metadata and field mapping preview
 			content.WriteString(
 				"\n" + lipgloss.NewStyle().
 					Bold(true).
-					Foreground(lipgloss.Color("#00FFFF")).
+					Foreground(themeColor("#00FFFF")).
 					Render("Full Metadata Preview:") +
 					"\n\n",
 			)
@@ -606,7 +612,7 @@ func (m *SettingsModel) View() string {
 				content.WriteString(
 					lipgloss.NewStyle().
 						Bold(true).
-						Foreground(lipgloss.Color("#AAFFAA")).
+						Foreground(themeColor("#AAFFAA")).
 						Render(filename) +
 						"\n\n",
 				)
@@ -619,7 +625,7 @@ func (m *SettingsModel) View() string {
 				content.WriteString(
 					lipgloss.NewStyle().
 						Bold(true).
-						Foreground(lipgloss.Color("#00FF00")).
+						Foreground(themeColor("#00FF00")).
 						Render("After Field Mapping Applied:") +
 						"\n",
 				)
@@ -629,7 +635,7 @@ func (m *SettingsModel) View() string {
 				content.WriteString(
 					"\n" + lipgloss.NewStyle().
 						Bold(true).
-						Foreground(lipgloss.Color("#FFFF00")).
+						Foreground(themeColor("#FFFF00")).
 						Render("Current Field Mapping Configuration:") +
 						"\n",
 				)
@@ -638,7 +644,7 @@ func (m *SettingsModel) View() string {
 				content.WriteString(formatFieldMapping(fieldMapping) + "\n")
 			}
 		} else {
-			content.WriteString("\n" + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FFFF")).Render("Preview of Output Paths:") + "\n\n")
+			content.WriteString("\n" + lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FFFF")).Render("Preview of Output Paths:") + "\n\n")
 
 			// Get current layout setting
 			layoutSetting := m.settings[0].Options[m.settings[0].Value]
@@ -662,8 +668,8 @@ func (m *SettingsModel) View() string {
 				outputPath := GenerateOutputPathWithLayout(book, layoutSetting, embeddedMetadataEnabled)
 
 				// Display book info and output path
-				content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#AAFFAA")).Render(filename) + "\n")
-				content.WriteString("  → " + lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAAAA")).Render(outputPath) + "\n")
+				content.WriteString(lipgloss.NewStyle().Foreground(themeColor("#AAFFAA")).Render(filename) + "\n")
+				content.WriteString("  → " + lipgloss.NewStyle().Foreground(themeColor("#FFAAAA")).Render(outputPath) + "\n")
 
 				// Add metadata info
 				authors := "Unknown"
@@ -677,13 +683,13 @@ func (m *SettingsModel) View() string {
 				}
 
 				content.WriteString(fmt.Sprintf("  Author: %s | Series: %s\n\n",
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAFF")).Render(authors),
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAAFF")).Render(series)))
+					lipgloss.NewStyle().Foreground(themeColor("#AAAAFF")).Render(authors),
+					lipgloss.NewStyle().Foreground(themeColor("#FFAAFF")).Render(series)))
 			}
 
 			// Add note about flat mode if enabled
 			if flatMode {
-				content.WriteString(lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#FFFF00")).Render("Note: Flat mode is enabled - each file will be processed individually") + "\n\n")
+				content.WriteString(lipgloss.NewStyle().Italic(true).Foreground(themeColor("#FFFF00")).Render("Note: Flat mode is enabled - each file will be processed individually") + "\n\n")
 			}
 		}
 	}
@@ -695,7 +701,7 @@ func (m *SettingsModel) View() string {
 	}
 
 	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
+		Foreground(themeColor("#888")).
 		Render(footerText)
 
 	content.WriteString(footer)
@@ -13,31 +13,30 @@ import (
 	"github.com/jeeftor/audiobook-organizer/internal/organizer"
 )
 
-var (
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
-			Padding(0, 1)
-
-	summaryStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFF00")).
-			Padding(0, 1)
-
-	tableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Background(lipgloss.Color("#5555AA")).
-				Padding(0, 1)
-
-	selectedRowStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFFF00")).
-				Background(lipgloss.Color("#333333"))
-
-	normalRowStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#AAAAAA"))
-)
+// Shared styles are built on each use so they follow theme changes
+
+func headerStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor("#FAFAFA")).
+		Background(themeColor("#7D56F4")).
+		Padding(0, 1)
+}
+
+func tableHeaderStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor("#FFFFFF")).
+		Background(themeColor("#5555AA")).
+		Padding(0, 1)
+}
+
+func selectedRowStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor("#FFFF00")).
+		Background(themeColor("#333333"))
+}
 
 // FocusArea represents which area has focus
 type FocusArea int
@@ -294,16 +293,13 @@ func NewSettingsTableModel(selectedBooks []AudioBook, showAdvanced bool) *Settin
 		table.WithHeight(11), // Show all settings rows
 	)
 
-	s := table.DefaultStyles()
-	s.Header = tableHeaderStyle
-	s.Selected = selectedRowStyle
-	t.SetStyles(s)
+	t.SetStyles(settingsTableStyles())
 
 	// Create viewport for metadata (will be resized on first WindowSizeMsg)
 	metadataVp := viewport.New(100, 15)
 	metadataVp.Style = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#AA7DFF")).
+		BorderForeground(themeColor("#AA7DFF")).
 		Padding(0, 1)
 
 	layoutTemplateInput := textinput.New()
@@ -585,19 +581,14 @@ func (m *SettingsTableModel) updateTableRow(index int) {
 
 // colorizeOutputPath applies different colors to path components based on layout
 func (m *SettingsTableModel) colorizeOutputPath(path string, layout string) string {
-	parts := strings.Split(path, string(filepath.Separator))
+	parts := outputPathComponents(path, "output")
 
 	// Color scheme for different components
-	authorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9500"))    // Orange for author
-	seriesStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00D9FF"))    // Cyan for series
-	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))     // Green for title
-	fileStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))      // Gray for filename
-	separatorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")) // Gray for /
-
-	// Skip the output directory (first part) and work with the rest
-	if len(parts) > 0 {
-		parts = parts[1:]
-	}
+	authorStyle := lipgloss.NewStyle().Foreground(themeColor("#FF9500"))    // Orange for author
+	seriesStyle := lipgloss.NewStyle().Foreground(themeColor("#00D9FF"))    // Cyan for series
+	titleStyle := lipgloss.NewStyle().Foreground(themeColor("#00FF00"))     // Green for title
+	fileStyle := lipgloss.NewStyle().Foreground(themeColor("#AAAAAA"))      // Gray for filename
+	separatorStyle := lipgloss.NewStyle().Foreground(themeColor("#666666")) // Gray for /
 
 	var coloredParts []string
 
@@ -659,7 +650,7 @@ func (m *SettingsTableModel) colorizeOutputPath(path string, layout string) stri
 	}
 
 	// Join with colored separator
-	return strings.Join(coloredParts, separatorStyle.Render("/"))
+	return strings.Join(coloredParts, separatorStyle.Render(string(filepath.Separator)))
 }
 
 // generateOutputPreview generates a simple preview of output paths (non-scrollable)
@@ -681,7 +672,7 @@ func (m *SettingsTableModel) generateOutputPreview() string {
 		previewCount = len(m.selectedBooks)
 	}
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FFFF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#00FFFF"))
 	content.WriteString(titleStyle.Render("Output Path Preview:") + "\n")
 
 	for i := 0; i < previewCount; i++ {
@@ -696,7 +687,7 @@ func (m *SettingsTableModel) generateOutputPreview() string {
 	}
 
 	if len(m.selectedBooks) > previewCount {
-		moreStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
+		moreStyle := lipgloss.NewStyle().Foreground(themeColor("#888888")).Italic(true)
 		content.WriteString(
 			moreStyle.Render(
 				fmt.Sprintf("  ... and %d more", len(m.selectedBooks)-previewCount),
@@ -726,24 +717,24 @@ func (m *SettingsTableModel) updateMetadata() {
 	}
 	book := m.selectedBooks[m.metadataBookIndex]
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFAAFF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(themeColor("#FFAAFF"))
 
 	// Color styles matching the output path components
 	authorLabelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF9500"))
+		Foreground(themeColor("#FF9500"))
 		// Orange for author
 	seriesLabelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF"))
+		Foreground(themeColor("#00D9FF"))
 		// Cyan for series
 	titleLabelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FF00"))
+		Foreground(themeColor("#00FF00"))
 		// Green for title
 	defaultLabelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#AAAAFF"))
+		Foreground(themeColor("#AAAAFF"))
 		// Default for other fields
 
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
-	usedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00"))
+	valueStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFFFF"))
+	usedStyle := lipgloss.NewStyle().Foreground(themeColor("#FFFF00"))
 	checkmark := usedStyle.Render("✓ ")
 
 	content.WriteString(
@@ -852,10 +843,10 @@ func (m *SettingsTableModel) updateMetadata() {
 	}
 
 	// Show file path (shortened to last 3 components)
-	pathParts := strings.Split(book.Path, string(filepath.Separator))
+	pathParts := splitPath(book.Path)
 	displayPath := book.Path
 	if len(pathParts) > 3 {
-		displayPath = ".../" + strings.Join(pathParts[len(pathParts)-3:], "/")
+		displayPath = filepath.Join(append([]string{"..."}, pathParts[len(pathParts)-3:]...)...)
 	}
 	content.WriteString(
 		"\n" + defaultLabelStyle.Render("Source: ") + valueStyle.Render(displayPath) + "\n",
@@ -867,6 +858,11 @@ func (m *SettingsTableModel) updateMetadata() {
 			book.Metadata.SourceType,
 		) + "\n",
 	)
+	if book.Metadata.Audio != nil {
+		content.WriteString(
+			defaultLabelStyle.Render("Audio: ") + valueStyle.Render(book.Metadata.Audio.String()) + "\n",
+		)
+	}
 
 	// Show raw metadata fields if available
 	if len(book.Metadata.RawData) > 0 {
@@ -940,22 +936,22 @@ func (m *SettingsTableModel) renderMetadataViewport() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FFFFFF")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1)
 
 	if !focused {
 		titleStyle = titleStyle.
-			Foreground(lipgloss.Color("#888888")).
-			Background(lipgloss.Color("#333333"))
+			Foreground(themeColor("#888888")).
+			Background(themeColor("#333333"))
 	}
 
 	scrollStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
-		Background(lipgloss.Color("#7D56F4"))
+		Foreground(themeColor("#FFFF00")).
+		Background(themeColor("#7D56F4"))
 
 	if !focused {
-		scrollStyle = scrollStyle.Background(lipgloss.Color("#333333"))
+		scrollStyle = scrollStyle.Background(themeColor("#333333"))
 	}
 
 	titleBar := titleStyle.Render("Metadata" + focusIndicator)
@@ -1031,8 +1027,8 @@ func (m *SettingsTableModel) renderPopup() string {
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(themeColor("#FFFFFF")).
+		Background(themeColor("#7D56F4")).
 		Padding(0, 1)
 
 	content.WriteString(titleStyle.Render("Select "+settingName) + "\n\n")
@@ -1040,14 +1036,14 @@ func (m *SettingsTableModel) renderPopup() string {
 	// Options list
 	selectedStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFF00")).
-		Background(lipgloss.Color("#333333"))
+		Foreground(themeColor("#FFFF00")).
+		Background(themeColor("#333333"))
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF"))
+		Foreground(themeColor("#FFFFFF"))
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888888")).
+		Foreground(themeColor("#888888")).
 		Italic(true)
 
 	// Get current book's metadata for showing values
@@ -1110,15 +1106,15 @@ func (m *SettingsTableModel) renderPopup() string {
 
 	// Footer
 	content.WriteString("\n")
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	helpStyle := lipgloss.NewStyle().Foreground(themeColor("#888888"))
 	content.WriteString(helpStyle.Render("↑/↓: Navigate • Enter: Select • Esc: Cancel"))
 
 	// Box the popup
 	popupStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FFFF00")).
+		BorderForeground(themeColor("#FFFF00")).
 		Padding(1, 2).
-		Background(lipgloss.Color("#000000"))
+		Background(themeColor("#000000"))
 
 	popup := popupStyle.Render(content.String())
 
@@ -1135,12 +1131,12 @@ func (m *SettingsTableModel) View() string {
 		m.height,
 		m.metadataViewport.Height,
 	)
-	header := headerStyle.Render("⚙️ All Settings (Basic + Advanced)"+debugInfo) + "\n\n"
+	header := headerStyle().Render("⚙️ All Settings (Basic + Advanced)"+debugInfo) + "\n\n"
 
 	// Table with border
 	tableBorderStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
+		BorderForeground(themeColor("#7D56F4")).
 		Padding(0, 1)
 
 	tableView := tableBorderStyle.Render(m.table.View()) + "\n"
@@ -1162,7 +1158,7 @@ func (m *SettingsTableModel) View() string {
 	}
 
 	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
+		Foreground(themeColor("#888")).
 		Render("\n" + helpText)
 
 	baseView := header + tableView + metadataPane + outputPreview + footer
@@ -1182,9 +1178,9 @@ func (m *SettingsTableModel) View() string {
 func (m *SettingsTableModel) renderLayoutTemplateEditor(baseView string) string {
 	editorStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FFFF00")).
+		BorderForeground(themeColor("#FFFF00")).
 		Padding(1, 2).
-		Background(lipgloss.Color("#000000"))
+		Background(themeColor("#000000"))
 
 	content := "Edit Layout Template\n\n" + m.layoutTemplateInput.View() +
 		"\n\nEnter: Save • Esc: Cancel"
@@ -1214,6 +1210,35 @@ func (m *SettingsTableModel) GetConfig() map[string]string {
 	return config
 }
 
+// ApplyProfile sets the layout and scan mode rows from a saved profile
+func (m *SettingsTableModel) ApplyProfile(profile Profile) {
+	values := map[string]string{
+		"Layout":                profile.Layout,
+		"Use Embedded Metadata": yesNo(profile.UseEmbeddedMetadata),
+		"Flat Mode":             yesNo(profile.Flat),
+	}
+	for i, fm := range m.fieldMappings {
+		value, ok := values[fm.Name]
+		if !ok {
+			continue
+		}
+		for j, option := range fm.Options {
+			if option == value {
+				m.fieldMappings[i].Value = j
+				m.updateTableRow(i)
+				break
+			}
+		}
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
 // GetFieldMapping returns the field mapping configuration
 func (m *SettingsTableModel) GetFieldMapping() organizer.FieldMapping {
 	// Unified settings indices:
@@ -1256,3 +1281,17 @@ func (m *SettingsTableModel) GetFieldMapping() organizer.FieldMapping {
 		TrackField:   m.fieldMappings[10].Options[m.fieldMappings[10].Value],
 	}
 }
+
+// settingsTableStyles returns the settings table styles in the current theme
+func settingsTableStyles() table.Styles {
+	s := table.DefaultStyles()
+	s.Header = tableHeaderStyle()
+	s.Selected = selectedRowStyle()
+	return s
+}
+
+// refreshTheme restyles the parts of the screen that keep their styles between
+// renders
+func (m *SettingsTableModel) refreshTheme() {
+	m.table.SetStyles(settingsTableStyles())
+}
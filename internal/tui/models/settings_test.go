@@ -390,3 +390,20 @@ func TestSettingsModelGetCurrentSetting(t *testing.T) {
 		}
 	}
 }
+
+func TestSettingsTableModelApplyProfile(t *testing.T) {
+	m := NewSettingsTableModel([]AudioBook{}, false)
+	m.ApplyProfile(Profile{Layout: "series-title", UseEmbeddedMetadata: true, Flat: true})
+
+	config := m.GetConfig()
+	want := map[string]string{
+		"Layout":                "series-title",
+		"Use Embedded Metadata": "Yes",
+		"Flat Mode":             "Yes",
+	}
+	for name, value := range want {
+		if config[name] != value {
+			t.Errorf("config[%q] = %q, want %q", name, config[name], value)
+		}
+	}
+}
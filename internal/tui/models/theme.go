@@ -0,0 +1,277 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme selects the colors every TUI style is rendered with
+type Theme string
+
+const (
+	ThemeDark         Theme = "dark"
+	ThemeLight        Theme = "light"
+	ThemeHighContrast Theme = "high-contrast"
+	ThemeNoColor      Theme = "no-color"
+)
+
+// Themes lists the themes in the order the theme key cycles through them
+var Themes = []Theme{ThemeDark, ThemeLight, ThemeHighContrast, ThemeNoColor}
+
+// Runtime keys for switching the theme and glyph set from any screen
+const (
+	themeToggleKey  = "ctrl+t"
+	glyphsToggleKey = "ctrl+g"
+)
+
+var (
+	currentTheme = ThemeDark
+	plainGlyphs  bool
+
+	// Terminal settings detected before the first theme change, restored when
+	// leaving the no-color and light themes
+	detected         bool
+	detectedProfile  termenv.Profile
+	detectedDarkBack bool
+)
+
+// ParseTheme returns the theme with the given name; an empty name is the dark theme
+func ParseTheme(name string) (Theme, error) {
+	if name == "" {
+		return ThemeDark, nil
+	}
+	for _, theme := range Themes {
+		if string(theme) == strings.ToLower(name) {
+			return theme, nil
+		}
+	}
+	return "", fmt.Errorf("invalid theme %q: must be dark, light, high-contrast, or no-color", name)
+}
+
+// CurrentTheme returns the theme styles are rendered with
+func CurrentTheme() Theme {
+	return currentTheme
+}
+
+// SetTheme switches the theme. The no-color theme also drops the colors of the
+// bubbles components, and the light theme selects their light-background variants.
+func SetTheme(theme Theme) {
+	if !detected {
+		detectedProfile = lipgloss.ColorProfile()
+		detectedDarkBack = lipgloss.HasDarkBackground()
+		detected = true
+	}
+	currentTheme = theme
+
+	if theme == ThemeNoColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else {
+		lipgloss.SetColorProfile(detectedProfile)
+	}
+	lipgloss.SetHasDarkBackground(detectedDarkBack && theme != ThemeLight)
+}
+
+// nextTheme returns the theme after the current one in Themes
+func nextTheme() Theme {
+	for i, theme := range Themes {
+		if theme == currentTheme {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return ThemeDark
+}
+
+// PlainGlyphs reports whether emoji and other symbols are replaced with ASCII
+func PlainGlyphs() bool {
+	return plainGlyphs
+}
+
+// SetPlainGlyphs replaces emoji, arrows, and box drawing with ASCII, for terminals
+// that render them as mojibake
+func SetPlainGlyphs(plain bool) {
+	plainGlyphs = plain
+}
+
+// lightPalette darkens the colors designed for dark backgrounds so they stay
+// readable on light ones
+var lightPalette = map[string]string{
+	"#FFFF00": "#7A5C00",
+	"#FFD700": "#7A5C00",
+	"#FFA500": "#A34700",
+	"#FF8800": "#A34700",
+	"#FF9500": "#A34700",
+	"214":     "#A34700",
+	"#00FFFF": "#006B7A",
+	"#00AAFF": "#0055AA",
+	"#00D9FF": "#006B7A",
+	"#00CED1": "#006B7A",
+	"#AAAAFF": "#3333AA",
+	"#00FF00": "#1A7A1A",
+	"#AAFFAA": "#1A7A1A",
+	"#2BFFB5": "#007A55",
+	"42":      "#1A7A1A",
+	"#FF0000": "#B00000",
+	"#FF5555": "#B00000",
+	"#FFAAAA": "#B00000",
+	"196":     "#B00000",
+	"#FFAAFF": "#8A008A",
+	"#FFFFFF": "#000000",
+	"#DDDDDD": "#222222",
+	"#CCCCCC": "#333333",
+	"#AAAAAA": "#444444",
+	"#888888": "#555555",
+	"#888":    "#555555",
+	"#666666": "#666666",
+	"241":     "#555555",
+	"240":     "#888888",
+	"#444444": "#AAAAAA",
+	"#333333": "#DDDDDD",
+	"#000000": "#FFFFFF",
+	"63":      "#5A3FD0",
+	"62":      "#5A3FD0",
+	"#AA7DFF": "#5A3FD0",
+}
+
+// highContrastPalette maps every color to one of the basic ANSI colors at full
+// intensity: white text, blue highlights, and no dim grays
+var highContrastPalette = map[string]string{
+	"#FFFF00": "11",
+	"#FFD700": "11",
+	"#FFA500": "11",
+	"#FF8800": "11",
+	"#FF9500": "11",
+	"214":     "11",
+	"229":     "15",
+	"#00FFFF": "14",
+	"#00AAFF": "14",
+	"#00D9FF": "14",
+	"#00CED1": "14",
+	"#AAAAFF": "14",
+	"#00FF00": "10",
+	"#AAFFAA": "10",
+	"#2BFFB5": "10",
+	"42":      "10",
+	"#FF0000": "9",
+	"#FF5555": "9",
+	"#FFAAAA": "9",
+	"196":     "9",
+	"#FFAAFF": "13",
+	"#FFFFFF": "15",
+	"#FAFAFA": "15",
+	"#DDDDDD": "15",
+	"#CCCCCC": "15",
+	"#AAAAAA": "15",
+	"#888888": "15",
+	"#888":    "15",
+	"#666666": "15",
+	"241":     "15",
+	"240":     "15",
+	"#444444": "15",
+	"#333333": "4",
+	"#000000": "0",
+	"#7D56F4": "4",
+	"#5555AA": "4",
+	"57":      "4",
+	"62":      "12",
+	"63":      "12",
+	"#AA7DFF": "12",
+}
+
+// themeColor returns color as the current theme renders it. Styles name the dark
+// theme's colors, and the other themes map them to their own.
+func themeColor(color string) lipgloss.TerminalColor {
+	switch currentTheme {
+	case ThemeNoColor:
+		return lipgloss.NoColor{}
+	case ThemeLight:
+		if mapped, ok := lightPalette[color]; ok {
+			return lipgloss.Color(mapped)
+		}
+	case ThemeHighContrast:
+		if mapped, ok := highContrastPalette[color]; ok {
+			return lipgloss.Color(mapped)
+		}
+	}
+	return lipgloss.Color(color)
+}
+
+// asciiGlyphs spells out the symbols whose meaning would be lost by dropping them
+var asciiGlyphs = strings.NewReplacer(
+	"→", "->",
+	"←", "<-",
+	"↑", "up",
+	"↓", "down",
+	"•", "*",
+	"…", "...",
+	"✓", "[ok]",
+	"✅", "[ok]",
+	"❌", "[x]",
+	"⚠️", "[!]",
+	"⚠", "[!]",
+	"⏳", "[..]",
+	"▶", ">",
+	"◀", "<",
+	"▲", "^",
+	"▼", "v",
+	"●", "*",
+)
+
+// applyGlyphs rewrites a rendered view with ASCII symbols when plain glyphs are
+// on: known symbols become text, box drawing becomes -, | and +, and the
+// remaining emoji are dropped along with the space after them
+func applyGlyphs(view string) string {
+	if !plainGlyphs {
+		return view
+	}
+	view = asciiGlyphs.Replace(view)
+
+	var b strings.Builder
+	b.Grow(len(view))
+	dropSpace := false
+	for _, r := range view {
+		if r == 0xFE0F || r == 0x200D { // Emoji variation selector and joiner
+			continue
+		}
+		if dropSpace {
+			dropSpace = false
+			if r == ' ' {
+				continue
+			}
+		}
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0x2500 && r <= 0x257F: // Box drawing
+			b.WriteByte(boxDrawingASCII(r))
+		case r >= 0x2800 && r <= 0x28FF: // Braille spinner frames
+			b.WriteByte('*')
+		case isEmoji(r):
+			dropSpace = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func boxDrawingASCII(r rune) byte {
+	switch r {
+	case '─', '━', '═', '╌', '╍':
+		return '-'
+	case '│', '┃', '║', '╎', '╏':
+		return '|'
+	}
+	return '+'
+}
+
+// isEmoji reports whether r is a pictograph rather than text, including the
+// symbols that terminals commonly draw as emoji
+func isEmoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) ||
+		(r >= 0x2600 && r <= 0x27BF) ||
+		(r >= 0x2300 && r <= 0x23FF) ||
+		(r >= 0x2B00 && r <= 0x2BFF)
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Theme
+		wantErr bool
+	}{
+		{name: "", want: ThemeDark},
+		{name: "light", want: ThemeLight},
+		{name: "High-Contrast", want: ThemeHighContrast},
+		{name: "no-color", want: ThemeNoColor},
+		{name: "sepia", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTheme(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTheme(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTheme(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestThemeColor(t *testing.T) {
+	defer SetTheme(ThemeDark)
+
+	tests := []struct {
+		theme Theme
+		want  lipgloss.TerminalColor
+	}{
+		{ThemeDark, lipgloss.Color("#FFFF00")},
+		{ThemeLight, lipgloss.Color("#7A5C00")},
+		{ThemeHighContrast, lipgloss.Color("11")},
+		{ThemeNoColor, lipgloss.NoColor{}},
+	}
+
+	for _, tt := range tests {
+		SetTheme(tt.theme)
+		if got := themeColor("#FFFF00"); got != tt.want {
+			t.Errorf("themeColor() in %s theme = %v, want %v", tt.theme, got, tt.want)
+		}
+	}
+}
+
+func TestApplyGlyphs(t *testing.T) {
+	defer SetPlainGlyphs(false)
+
+	view := "📚 Books ╭──╮ ↑/↓: Navigate • ⚠️ Warning ✅ Done"
+	if got := applyGlyphs(view); got != view {
+		t.Errorf("applyGlyphs() changed the view without plain glyphs: %q", got)
+	}
+
+	SetPlainGlyphs(true)
+	want := "Books +--+ up/down: Navigate * [!] Warning [ok] Done"
+	if got := applyGlyphs(view); got != want {
+		t.Errorf("applyGlyphs() = %q, want %q", got, want)
+	}
+}
+
+func TestMainModelAppearanceToggles(t *testing.T) {
+	defer SetTheme(ThemeDark)
+	defer SetPlainGlyphs(false)
+
+	m := NewMainModel("", "")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if got := CurrentTheme(); got != ThemeLight {
+		t.Errorf("theme after %s = %q, want light", themeToggleKey, got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if !PlainGlyphs() {
+		t.Errorf("%s did not turn on plain glyphs", glyphsToggleKey)
+	}
+}
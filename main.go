@@ -7,7 +7,9 @@ import (
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+	code, err := cmd.Execute()
+	if err != nil {
+		os.Exit(cmd.ExitFatal)
 	}
+	os.Exit(code)
 }
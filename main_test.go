@@ -32,7 +32,7 @@ func TestMainFlags(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			os.Args = tt.args
-			err := cmd.Execute()
+			_, err := cmd.Execute()
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cmd.Execute() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -0,0 +1,25 @@
+// Package organizer is the public Go API of the audiobook organizer.
+//
+// The stable entry points are:
+//
+//   - Config: the settings shared by every entry point
+//   - Scanner: discovers books below a directory without changing anything
+//   - Planner: computes the moves a run would make
+//   - Executor: performs the moves, organizes single books and undoes runs
+//   - Provider: the metadata provider interface, with JSON, EPUB and audio
+//     implementations plus StaticMetadataProvider for caller-supplied metadata
+//
+// Planner and Executor copy the Config they are given and keep no package-level
+// state between runs, so several can be used at once from one process.
+//
+//	plan, err := organizer.NewPlanner(organizer.Config{BaseDir: in, OutputDir: out}).Plan()
+//	if err != nil {
+//		return err
+//	}
+//	for _, move := range plan.Moves {
+//		fmt.Println(move.From, "->", move.To)
+//	}
+//
+// Other exported names wrap internal helpers used by the GUI and may change between
+// minor releases.
+package organizer
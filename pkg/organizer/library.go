@@ -0,0 +1,133 @@
+package organizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jeeftor/audiobook-organizer/internal/organizer"
+)
+
+// Config configures a Planner or Executor. Constructors take a copy, so one Config
+// value can be reused for any number of runs.
+type Config = organizer.OrganizerConfig
+
+// Provider supplies metadata for a book. Implement it to feed metadata from another
+// source, such as a download manager, into Executor.OrganizeBook.
+type Provider = organizer.MetadataProvider
+
+// StaticMetadataProvider is a Provider for metadata the caller already has
+type StaticMetadataProvider = organizer.StaticMetadataProvider
+
+// NewStaticMetadataProvider wraps already-loaded metadata as a Provider
+var NewStaticMetadataProvider = organizer.NewStaticMetadataProvider
+
+// Plan lists the moves a run would make without touching the filesystem
+type Plan struct {
+	Moves   []MoveSummary `json:"moves"`
+	Summary Summary       `json:"summary"`
+}
+
+// Planner computes the moves for a library without changing it
+type Planner struct {
+	config Config
+}
+
+// NewPlanner creates a Planner for config
+func NewPlanner(config Config) *Planner {
+	return &Planner{config: copyConfig(config)}
+}
+
+// Plan scans the configured input directory and returns the moves that Executor.Run
+// would perform with the same Config
+func (p *Planner) Plan() (*Plan, error) {
+	config := copyConfig(p.config)
+	config.DryRun = true
+	config.Prompt = false
+
+	org, err := organizer.NewOrganizer(&config)
+	if err != nil {
+		return nil, err
+	}
+	if err := org.Execute(); err != nil {
+		return nil, err
+	}
+
+	summary := org.GetSummary()
+	return &Plan{Moves: summary.Moves, Summary: summary}, nil
+}
+
+// Executor organizes a library on disk
+type Executor struct {
+	config Config
+}
+
+// NewExecutor creates an Executor for config. Set config.DryRun to preview instead.
+func NewExecutor(config Config) *Executor {
+	return &Executor{config: copyConfig(config)}
+}
+
+// Run organizes every book below the configured input directory and returns the
+// run summary. Per-book failures are listed in Summary.Errors.
+func (e *Executor) Run() (Summary, error) {
+	config := copyConfig(e.config)
+	org, err := organizer.NewOrganizer(&config)
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := org.Execute(); err != nil {
+		return org.GetSummary(), err
+	}
+	return org.GetSummary(), nil
+}
+
+// OrganizeBook organizes a single book directory or file using metadata from provider,
+// skipping the scan. The undo log is written as for a full run.
+func (e *Executor) OrganizeBook(sourcePath string, provider Provider) (Summary, error) {
+	config := copyConfig(e.config)
+	org, err := organizer.NewOrganizer(&config)
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := org.ResolvePaths(); err != nil {
+		return Summary{}, err
+	}
+	defer org.Close()
+
+	absSource, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return Summary{}, fmt.Errorf("error resolving source path: %w", err)
+	}
+	metadata, err := organizer.ExtractMappedMetadata(provider, config.FieldMapping)
+	if err != nil {
+		return Summary{}, fmt.Errorf("error getting metadata: %w", err)
+	}
+
+	startTime := time.Now()
+	if err := org.OrganizePathWithMetadata(absSource, metadata); err != nil {
+		return org.GetSummary(), err
+	}
+	if err := org.Finish(startTime); err != nil {
+		return org.GetSummary(), err
+	}
+	return org.GetSummary(), nil
+}
+
+// Undo reverts the moves recorded in the undo log of the configured directories
+func (e *Executor) Undo() error {
+	config := copyConfig(e.config)
+	config.Undo = true
+	org, err := organizer.NewOrganizer(&config)
+	if err != nil {
+		return err
+	}
+	return org.Execute()
+}
+
+// copyConfig returns a copy of config that shares no slices with the original, so
+// path resolution in one run can never leak into another
+func copyConfig(config Config) Config {
+	config.AllowedSourcePaths = append([]string(nil), config.AllowedSourcePaths...)
+	config.FieldMapping.AuthorFields = append([]string(nil), config.FieldMapping.AuthorFields...)
+	return config
+}
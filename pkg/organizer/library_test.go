@@ -0,0 +1,99 @@
+package organizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createBookDir creates a book subdirectory with a metadata.json and a fake .mp3
+// file, like the helper of the same name in internal/organizer.
+func createBookDir(t *testing.T, baseDir, name, title, author string) string {
+	t.Helper()
+	bookDir := filepath.Join(baseDir, name)
+	if err := os.MkdirAll(bookDir, 0o755); err != nil {
+		t.Fatalf("failed to create book directory %s: %v", bookDir, err)
+	}
+	meta := map[string]interface{}{
+		"title":   title,
+		"authors": []string{author},
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatalf("failed to marshal metadata: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bookDir, "metadata.json"), metaBytes, 0o644); err != nil {
+		t.Fatalf("failed to write metadata.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bookDir, "audio.mp3"), []byte("fake audio data"), 0o644); err != nil {
+		t.Fatalf("failed to write audio.mp3: %v", err)
+	}
+	return bookDir
+}
+
+func TestPlannerDoesNotMoveFiles(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	bookDir := createBookDir(t, inputDir, "Book", "Book", "Author")
+
+	config := Config{BaseDir: inputDir, OutputDir: outputDir, AllowedSourcePaths: []string{bookDir}}
+	plan, err := NewPlanner(config).Plan()
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Moves) != 1 {
+		t.Fatalf("Plan() moves = %d, want 1", len(plan.Moves))
+	}
+	if _, err := os.Stat(filepath.Join(bookDir, "audio.mp3")); err != nil {
+		t.Errorf("Plan() moved the source file: %v", err)
+	}
+	if !config.FieldMapping.IsEmpty() || config.AllowedSourcePaths[0] != bookDir {
+		t.Errorf("Plan() mutated the caller's config: %+v", config)
+	}
+}
+
+func TestExecutorRunAndUndo(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	bookDir := createBookDir(t, inputDir, "Book", "Book", "Author")
+	target := filepath.Join(outputDir, "Author", "Book", "audio.mp3")
+
+	executor := NewExecutor(Config{BaseDir: inputDir, OutputDir: outputDir})
+	summary, err := executor.Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(summary.Moves) != 1 {
+		t.Fatalf("Run() moves = %d, want 1", len(summary.Moves))
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("Run() did not create %s: %v", target, err)
+	}
+
+	if err := executor.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(bookDir, "audio.mp3")); err != nil {
+		t.Errorf("Undo() did not restore the source file: %v", err)
+	}
+}
+
+func TestExecutorOrganizeBook(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	bookDir := createBookDir(t, inputDir, "Download", "Download", "Unknown")
+
+	metadata := Metadata{Title: "Real Title", Authors: []string{"Real Author"}}
+	summary, err := NewExecutor(Config{BaseDir: inputDir, OutputDir: outputDir}).
+		OrganizeBook(bookDir, NewStaticMetadataProvider(metadata))
+	if err != nil {
+		t.Fatalf("OrganizeBook() error = %v", err)
+	}
+	if len(summary.Moves) != 1 {
+		t.Fatalf("OrganizeBook() moves = %d, want 1", len(summary.Moves))
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "Real Author", "Real Title", "audio.mp3")); err != nil {
+		t.Errorf("OrganizeBook() did not use the provided metadata: %v", err)
+	}
+}
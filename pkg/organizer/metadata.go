@@ -1,4 +1,3 @@
-// Package organizer provides public APIs for audiobook metadata extraction and manipulation.
 package organizer
 
 import (
@@ -63,13 +62,14 @@ func FindEPUBInDirectory(dir string) (string, error) {
 func DetectFileType(path string) string {
 	ext := filepath.Ext(path)
 	switch ext {
-	case ".mp3", ".m4b", ".m4a", ".ogg", ".flac":
-		return "audio"
 	case ".epub":
 		return "epub"
 	case ".json":
 		return "json"
 	default:
+		if organizer.IsSupportedAudioFile(ext) {
+			return "audio"
+		}
 		return "unknown"
 	}
 }
@@ -1,5 +1,3 @@
-// Package organizer provides a public API for the audiobook organizer functionality.
-// This wraps the internal organizer package to allow external modules (like the GUI) to use it.
 package organizer
 
 import (
@@ -19,23 +17,32 @@ type (
 	TemplateRenderer = organizer.TemplateRenderer
 	AuthorFormatter  = organizer.AuthorFormatter
 	TemplateField    = organizer.TemplateField
+	Scanner          = organizer.Scanner
+	ScanOptions      = organizer.ScanOptions
+	ScanProgress     = organizer.ScanProgress
+	ScanResult       = organizer.ScanResult
+	ScanHandler      = organizer.ScanHandler
+	Book             = organizer.Book
+	Group            = organizer.Group
 )
 
 // Re-export functions
 var (
-	NewOrganizer        = organizer.NewOrganizer
-	DefaultFieldMapping = organizer.DefaultFieldMapping
-	AudioFieldMapping   = organizer.AudioFieldMapping
-	EpubFieldMapping    = organizer.EpubFieldMapping
-	NewMetadata         = organizer.NewMetadata
-	TextFieldOptions    = organizer.TextFieldOptions
-	AuthorFieldOptions  = organizer.AuthorFieldOptions
-	TrackFieldOptions   = organizer.TrackFieldOptions
-	DiscFieldOptions    = organizer.DiscFieldOptions
-	ParseTemplate       = organizer.ParseTemplate
-	NewTemplateRenderer = organizer.NewTemplateRenderer
-	NewAuthorFormatter  = organizer.NewAuthorFormatter
-	GetAvailableFields  = organizer.GetAvailableFields
+	NewOrganizer          = organizer.NewOrganizer
+	DefaultFieldMapping   = organizer.DefaultFieldMapping
+	AudioFieldMapping     = organizer.AudioFieldMapping
+	EpubFieldMapping      = organizer.EpubFieldMapping
+	NewMetadata           = organizer.NewMetadata
+	TextFieldOptions      = organizer.TextFieldOptions
+	AuthorFieldOptions    = organizer.AuthorFieldOptions
+	TrackFieldOptions     = organizer.TrackFieldOptions
+	DiscFieldOptions      = organizer.DiscFieldOptions
+	ParseTemplate         = organizer.ParseTemplate
+	NewTemplateRenderer   = organizer.NewTemplateRenderer
+	NewAuthorFormatter    = organizer.NewAuthorFormatter
+	GetAvailableFields    = organizer.GetAvailableFields
+	NewScanner            = organizer.NewScanner
+	ScanOptionsFromConfig = organizer.ScanOptionsFromConfig
 )
 
 // Re-export field mapping constants
@@ -1,4 +1,3 @@
-// Package organizer provides public APIs for metadata providers
 package organizer
 
 import (
@@ -1,4 +1,3 @@
-// Package organizer provides public utility functions
 package organizer
 
 import (
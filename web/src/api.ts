@@ -28,6 +28,8 @@ export type OrganizerConfig = {
   layout: string
   layout_template: string
   author_format: string
+  casing?: string
+  strip_title_prefix?: boolean
   field_mapping: FieldMapping
   allowed_source_paths?: string[]
   metadata_source?: string
@@ -39,15 +41,63 @@ export type MoveSummary = {
   to: string
 }
 
+export type AudioInfo = {
+  codec?: string
+  bitrate?: number
+  sample_rate?: number
+  channels?: number
+  duration?: number
+}
+
+export type AuthorVariant = {
+  author: string
+  paths: string[]
+  audio?: Record<string, AudioInfo>
+}
+
+export type AuthorMergeSuggestion = {
+  title: string
+  suggested: string
+  variants: AuthorVariant[]
+  best_copy?: string
+}
+
 export type OrganizerSummary = {
   MetadataFound: string[]
   MetadataMissing: string[]
   Moves: MoveSummary[]
   EmptyDirsRemoved: string[]
+  AuthorVariants?: AuthorMergeSuggestion[] | null
+  SkipListed?: string[] | null
+  Deferred?: Deferral[] | null
+  Sources?: MetadataSources
+}
+
+export type MetadataSources = {
+  json: number
+  hybrid: number
+  audio: number
+  epub: number
+  fallback: number
+  other: number
+}
+
+export type Deferral = {
+  path: string
+  reason: string
+}
+
+export type PlanBook = {
+  source: string
+  target: string
+  files: MoveSummary[]
+  conflicts?: string[] | null
+  warnings?: string[] | null
 }
 
 export type OrganizePreviewResponse = {
   summary: OrganizerSummary
+  plan: PlanBook[]
   log_path?: string
 }
 
@@ -81,6 +131,7 @@ export type RenameCandidate = {
   IsNoOp: boolean
   IsConflict: boolean
   Error: string
+  Companions?: MoveSummary[] | null
 }
 
 export type RenameSummary = {
@@ -194,13 +245,46 @@ export type PathValidationResponse = {
     path: string
     valid: boolean
     error?: string
+    free_bytes?: number
   }>
 }
 
+export type DirectoryEntry = {
+  name: string
+  path: string
+}
+
+export type DirectoryListResponse = {
+  path: string
+  parent?: string
+  entries: DirectoryEntry[]
+  free_bytes?: number
+}
+
+export type FreeSpaceResponse = {
+  path: string
+  free_bytes: number
+  supported: boolean
+}
+
+// formatBytes renders free space reported by the API, such as "12.4 GB"
+export function formatBytes(bytes: number): string {
+  const units = ['B', 'KB', 'MB', 'GB', 'TB']
+  let value = bytes
+  let unit = 0
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024
+    unit += 1
+  }
+  return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`
+}
+
 export type WebConfig = {
   host: string
   port: number
   open: boolean
+  read_only?: boolean
+  roots?: string[]
   initial: {
     input_dir: string
     output_dir: string
@@ -208,6 +292,9 @@ export type WebConfig = {
   organizer: OrganizerConfig
   rename: RenameConfig
   abs: ABSConfig
+  language?: string
+  // Translated UI text by message key; missing keys keep their English text
+  messages?: Record<string, string>
 }
 
 export type OptionsResponse = {
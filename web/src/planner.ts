@@ -0,0 +1,110 @@
+import type { FieldMapping } from './api'
+
+// Client-side planning core compiled from cmd/planner-wasm ("make wasm-build").
+// When the module is missing the UI falls back to server-side previews only.
+
+export type PlannerMetadata = {
+  title: string
+  authors: string[]
+  series?: string[]
+  track_number?: number
+  album?: string
+  raw_data?: Record<string, unknown>
+}
+
+export type PlannerPreviewRequest = {
+  layout: string
+  layout_template?: string
+  filename_template?: string
+  author_format?: string
+  replace_space?: string
+  target_os?: string
+  output_dir?: string
+  field_mapping?: FieldMapping
+  books: { source_path: string; metadata: PlannerMetadata }[]
+}
+
+export type PlannerPreviewResult = {
+  source_path: string
+  target_dir?: string
+  filename?: string
+  error?: string
+}
+
+export type PlannerPreviewResponse = {
+  results: PlannerPreviewResult[]
+  error?: string
+}
+
+type PlannerModule = {
+  preview: (request: string) => string
+  validateTemplate: (template: string) => string
+}
+
+type GoRuntime = {
+  importObject: WebAssembly.Imports
+  run: (instance: WebAssembly.Instance) => Promise<void>
+}
+
+declare global {
+  interface Window {
+    Go?: new () => GoRuntime
+    audiobookPlanner?: PlannerModule
+  }
+}
+
+export const plannerSampleBook: PlannerPreviewRequest['books'][number] = {
+  source_path: 'The Final Empire.m4b',
+  metadata: {
+    title: 'The Final Empire',
+    authors: ['Brandon Sanderson'],
+    series: ['Mistborn #1'],
+    track_number: 1,
+    album: 'Mistborn',
+    raw_data: { narrators: ['Michael Kramer'], series_index: 1, year: '2006' },
+  },
+}
+
+let plannerLoad: Promise<PlannerModule | null> | null = null
+
+export function loadPlanner(): Promise<PlannerModule | null> {
+  plannerLoad ??= loadPlannerModule().catch(() => null)
+  return plannerLoad
+}
+
+export async function previewPlan(request: PlannerPreviewRequest): Promise<PlannerPreviewResponse | null> {
+  const planner = await loadPlanner()
+  if (!planner) {
+    return null
+  }
+  return JSON.parse(planner.preview(JSON.stringify(request))) as PlannerPreviewResponse
+}
+
+async function loadPlannerModule(): Promise<PlannerModule | null> {
+  // Unknown paths fall back to index.html, so check the type before loading anything
+  const response = await fetch('/planner/planner.wasm')
+  if (!response.ok || !response.headers.get('Content-Type')?.includes('wasm')) {
+    return null
+  }
+  if (!window.Go) {
+    await loadScript('/planner/wasm_exec.js')
+  }
+  if (!window.Go) {
+    return null
+  }
+
+  const go = new window.Go()
+  const { instance } = await WebAssembly.instantiate(await response.arrayBuffer(), go.importObject)
+  void go.run(instance)
+  return window.audiobookPlanner ?? null
+}
+
+function loadScript(src: string): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const script = document.createElement('script')
+    script.src = src
+    script.onload = () => resolve()
+    script.onerror = () => reject(new Error(`failed to load ${src}`))
+    document.head.appendChild(script)
+  })
+}
@@ -75,8 +75,8 @@ test('guides a mounted library through real Audiobookshelf organize metadata', a
 
   await page.getByRole('button', { name: 'Review & Run Select, execute, inspect' }).click()
   await expect(page.getByRole('heading', { name: 'Reviewed Organize Plan' })).toBeVisible()
-  await expect(page.locator('.move-list.selectable-list')).toContainText('Charles Dickens')
-  await expect(page.locator('.move-list.selectable-list')).toContainText('Lewis Carroll')
+  await expect(page.locator('.plan-tree')).toContainText('Charles Dickens')
+  await expect(page.locator('.plan-tree')).toContainText('Lewis Carroll')
   await expect(page.getByRole('button', { name: /Run 2 Selected Moves/ })).toBeEnabled()
 
   page.once('dialog', async (dialog) => {
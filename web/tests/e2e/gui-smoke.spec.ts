@@ -227,24 +227,35 @@ test('keeps invalid configure paths on the first step before preview requests ru
   }
 })
 
-test('supports folder picker and drop affordances while preserving manual path entry', async ({ page }) => {
+test('supports the folder browser and drop affordances while preserving manual path entry', async ({ page }) => {
   await loadApp(page)
 
   const sourceInput = page.getByRole('textbox', { name: 'Source folder' })
   const outputInput = page.getByRole('textbox', { name: 'Output folder' })
   await sourceInput.fill('/manual/source')
-  await outputInput.fill('/manual/output')
 
-  await expect(page.getByRole('button', { name: 'Choose source folder' })).toBeVisible()
-  await expect(page.getByRole('button', { name: 'Choose output folder' })).toBeVisible()
+  await expect(page.getByRole('button', { name: 'Browse for source folder' })).toBeVisible()
+  await expect(page.getByRole('button', { name: 'Browse for output folder' })).toBeVisible()
 
   const pickerDir = await mkdtemp(join(tmpdir(), 'abo-picker-'))
-  await writeFile(join(pickerDir, 'fixture.m4b'), 'fixture')
-  await page.getByLabel('Output folder directory picker').setInputFiles(pickerDir)
-  await expect(
-    page.locator('.path-message').filter({ hasText: 'Folder selected, but this browser did not expose a local path' }),
-  ).toBeVisible()
-  await expect(outputInput).toHaveValue('/manual/output')
+  try {
+    await mkdir(join(pickerDir, 'Library'))
+    await outputInput.fill(pickerDir)
+    await page.getByRole('button', { name: 'Browse for output folder' }).click()
+    const browser = page.getByRole('dialog', { name: 'Output folder' })
+    await browser.getByRole('button', { name: 'Library' }).click()
+    await browser.getByRole('textbox', { name: 'New folder name' }).fill('Audiobooks')
+    await browser.getByRole('button', { name: 'Create' }).click()
+    await expect(browser.getByText(join(pickerDir, 'Library', 'Audiobooks'))).toBeVisible()
+    await browser.getByRole('button', { name: 'Use this folder' }).click()
+    await expect(browser).toHaveCount(0)
+    await expect(outputInput).toHaveValue(join(pickerDir, 'Library', 'Audiobooks'))
+    await expect(
+      page.locator('.path-message').filter({ hasText: 'Output folder set from the folder browser.' }),
+    ).toBeVisible()
+  } finally {
+    await rm(pickerDir, { recursive: true, force: true })
+  }
 
   const dataTransfer = await page.evaluateHandle(() => {
     const transfer = new DataTransfer()